@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// auditEntry is one line of the --audit-log file: a single attempt of an
+// AWS API call
+type auditEntry struct {
+	Time       time.Time `json:"time"`
+	Service    string    `json:"service"`
+	Operation  string    `json:"operation"`
+	Bucket     string    `json:"bucket,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	Range      string    `json:"range,omitempty"`
+	Prefix     string    `json:"prefix,omitempty"`
+	StartAfter string    `json:"start_after,omitempty"`
+	// Attempt counts retries of the same call from 1
+	Attempt    int     `json:"attempt"`
+	DurationMS float64 `json:"duration_ms"`
+	Status     int     `json:"status,omitempty"`
+	// Result is "ok", the AWS error code (e.g. SlowDown) or "error"
+	Result    string `json:"result"`
+	Error     string `json:"error,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// auditLog writes an auditEntry per AWS request attempt as JSON lines
+type auditLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newAuditLog(w io.Writer) *auditLog {
+	return &auditLog{enc: json.NewEncoder(w)}
+}
+
+// write appends an entry; a failed write must not fail the API call
+func (a *auditLog) write(entry auditEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_ = a.enc.Encode(entry)
+}
+
+// auditCall is attached to the context of each SDK call: the operation
+// input, which is gone once the request is serialized, and the number of
+// attempts so far
+type auditCall struct {
+	params   any
+	attempts int
+}
+
+type auditCallKey struct{}
+
+// addMiddleware logs every attempt of SDK operations. The logging step
+// runs inside the retry loop, so each retry is its own entry.
+func (a *auditLog) addMiddleware(stack *middleware.Stack) error {
+	err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("AuditCall",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			return next.HandleInitialize(middleware.WithStackValue(ctx, auditCallKey{}, &auditCall{params: in.Parameters}), in)
+		}), middleware.After)
+	if err != nil {
+		return err
+	}
+
+	return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("AuditLog",
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			start := time.Now()
+			out, metadata, err := next.HandleFinalize(ctx, in)
+
+			entry := auditEntry{
+				Time:       start.UTC(),
+				Service:    awsmiddleware.GetServiceID(ctx),
+				Operation:  awsmiddleware.GetOperationName(ctx),
+				Attempt:    1,
+				DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if call, ok := middleware.GetStackValue(ctx, auditCallKey{}).(*auditCall); ok {
+				call.attempts++
+				entry.Attempt = call.attempts
+				entry.Bucket = inputField(call.params, "Bucket")
+				entry.Key = inputField(call.params, "Key")
+				entry.Range = inputField(call.params, "Range")
+				entry.Prefix = inputField(call.params, "Prefix")
+				entry.StartAfter = inputField(call.params, "StartAfter")
+			}
+			if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok {
+				entry.Status = resp.StatusCode
+			}
+			entry.RequestID, _ = awsmiddleware.GetRequestIDMetadata(metadata)
+			entry.Result, entry.Error = auditResult(err)
+			a.write(entry)
+
+			return out, metadata, err
+		}), middleware.After)
+}
+
+// auditResult summarizes an attempt's outcome
+func auditResult(err error) (string, string) {
+	if err == nil {
+		return "ok", ""
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode(), err.Error()
+	}
+	return "error", err.Error()
+}
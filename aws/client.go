@@ -2,55 +2,364 @@ package aws
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 // Client wraps the AWS S3 client with configuration
 type Client struct {
 	S3     *s3.Client
 	Config aws.Config
+
+	accountOnce sync.Once
+	accountID   string
+	accountErr  error
+
+	// guard is set in strict read-only mode
+	guard *readOnlyGuard
+	// audit is set when AuditLog is configured
+	audit *auditLog
+	// endpointURL is set for S3-compatible stores
+	endpointURL string
+	// anonymous is set when requests are sent unsigned
+	anonymous bool
+	// retryer is shared by the calls to services without an SDK client
+	retryer aws.Retryer
+}
+
+// ClientOptions configures how the AWS client is created.
+// Zero values leave the SDK defaults (and shared config settings) in effect.
+type ClientOptions struct {
+	Profile string
+	Region  string
+
+	// HTTP client tuning
+	MaxConns            int
+	RequestTimeout      time.Duration
+	TLSHandshakeTimeout time.Duration
+
+	// Retry overrides; when unset, max_attempts and retry_mode from the
+	// shared config file or environment are honored
+	MaxAttempts int
+	RetryMode   string
+
+	// ReadOnlyStrict blocks every AWS call that is not a Get, List, Head or
+	// Describe operation and logs each call to ReadOnlyLog
+	ReadOnlyStrict bool
+	ReadOnlyLog    io.Writer
+
+	// AuditLog receives a JSON line per AWS request attempt
+	AuditLog io.Writer
+
+	// NoContentAccess blocks every S3 operation that reads an object's
+	// content or headers (GetObject, HeadObject and the like)
+	NoContentAccess bool
+
+	// EndpointURL points the S3 client at an S3-compatible store such as
+	// MinIO, Ceph, LocalStack or Cloudflare R2; ForcePathStyle addresses
+	// buckets as endpoint/bucket instead of bucket.endpoint
+	EndpointURL    string
+	ForcePathStyle bool
+
+	// NoSignRequest sends unsigned requests, for public buckets such as
+	// open data registries; no credentials are loaded
+	NoSignRequest bool
+
+	// RoleARN is assumed with the loaded credentials, e.g. to profile the
+	// buckets of another account from a central tooling account.
+	// ExternalID is passed to the role's trust policy; with MFASerial the
+	// MFA token code is read from stdin.
+	RoleARN    string
+	ExternalID string
+	MFASerial  string
+
+	// RequestPayer is "requester" to accept the charges of requester-pays
+	// buckets, which reject requests without it
+	RequestPayer string
+
+	// MaxRequestRate limits the AWS requests per second sent for each
+	// bucket, retries included; calls not about a bucket share one more
+	// budget (0 = unlimited)
+	MaxRequestRate float64
+
+	// Headers are set on every S3 request before it is signed, for
+	// gateways in front of S3-compatible stores that require them
+	Headers http.Header
+
+	// S3Middleware is added to every S3 operation after the client's own
+	// middleware, for custom headers, signing tweaks or request logging
+	S3Middleware []Middleware
 }
 
-// NewClient creates a new AWS S3 client with the specified profile and region
-func NewClient(ctx context.Context, profile, region string) (*Client, error) {
-	var opts []func(*config.LoadOptions) error
+// defaultEndpointRegion signs requests to custom endpoints when no region
+// is configured; most S3-compatible stores accept any region
+const defaultEndpointRegion = "us-east-1"
+
+// requestPayerRequester is the only request payer value S3 accepts
+const requestPayerRequester = "requester"
+
+// Assumed role sessions last an hour, the longest every role allows, so
+// MFA codes are asked for as rarely as possible
+const (
+	roleSessionName     = "s3-profiler"
+	roleSessionDuration = time.Hour
+)
+
+// NewClient creates a new AWS S3 client with the specified options
+func NewClient(ctx context.Context, opts ClientOptions) (*Client, error) {
+	var loadOpts []func(*config.LoadOptions) error
 
 	// Add profile if specified
-	if profile != "" {
-		opts = append(opts, config.WithSharedConfigProfile(profile))
+	if opts.Profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(opts.Profile))
 	}
 
 	// Add region if specified
-	if region != "" {
-		opts = append(opts, config.WithRegion(region))
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(opts.Region))
 	}
 
+	// Only override retry settings when explicitly requested, so the SDK
+	// resolves them from shared config otherwise
+	if opts.MaxAttempts > 0 {
+		loadOpts = append(loadOpts, config.WithRetryMaxAttempts(opts.MaxAttempts))
+	}
+	if opts.RetryMode != "" {
+		mode, err := aws.ParseRetryMode(opts.RetryMode)
+		if err != nil {
+			return nil, err
+		}
+		loadOpts = append(loadOpts, config.WithRetryMode(mode))
+	}
+
+	if opts.RequestPayer != "" && opts.RequestPayer != requestPayerRequester {
+		return nil, fmt.Errorf("invalid request payer %q (expected %s)", opts.RequestPayer, requestPayerRequester)
+	}
+	if opts.RequestPayer != "" && opts.NoSignRequest {
+		return nil, errors.New("requester-pays buckets bill the requester's account and cannot be read unsigned")
+	}
+
+	if opts.NoSignRequest {
+		loadOpts = append(loadOpts, config.WithCredentialsProvider(aws.AnonymousCredentials{}))
+	}
+	if opts.RoleARN == "" && (opts.ExternalID != "" || opts.MFASerial != "") {
+		return nil, errors.New("an external ID or MFA serial needs a role to assume")
+	}
+	if opts.RoleARN != "" && opts.NoSignRequest {
+		return nil, errors.New("unsigned requests cannot assume a role")
+	}
+
+	// Tune the underlying HTTP client
+	loadOpts = append(loadOpts, config.WithHTTPClient(newHTTPClient(opts)))
+
 	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create S3 client
-	s3Client := s3.NewFromConfig(cfg)
+	// The role is assumed outside the read-only guard, which would block
+	// AssumeRole
+	if opts.RoleARN != "" {
+		cfg.Credentials = aws.NewCredentialsCache(assumeRoleProvider(cfg, opts))
+	}
+
+	// The guards, audit log and rate limiter must be in place before any
+	// service client is created
+	var guard *readOnlyGuard
+	if opts.ReadOnlyStrict {
+		guard = &readOnlyGuard{log: opts.ReadOnlyLog}
+		if guard.log == nil {
+			guard.log = os.Stderr
+		}
+		cfg.APIOptions = append(cfg.APIOptions, guard.addMiddleware)
+	}
+
+	if opts.NoContentAccess {
+		cfg.APIOptions = append(cfg.APIOptions, addContentGuard)
+	}
+
+	var audit *auditLog
+	if opts.AuditLog != nil {
+		audit = newAuditLog(opts.AuditLog)
+		cfg.APIOptions = append(cfg.APIOptions, audit.addMiddleware)
+	}
+
+	if opts.MaxRequestRate > 0 {
+		cfg.APIOptions = append(cfg.APIOptions, newRateLimiter(opts.MaxRequestRate).addMiddleware)
+	}
+
+	if opts.RequestTimeout > 0 {
+		cfg.APIOptions = append(cfg.APIOptions, requestTimeout(opts.RequestTimeout).addMiddleware)
+	}
+
+	if (opts.EndpointURL != "" || opts.NoSignRequest) && cfg.Region == "" {
+		cfg.Region = defaultEndpointRegion
+	}
+
+	// Create S3 client; only S3 is redirected to a custom endpoint
+	s3Client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if opts.EndpointURL != "" {
+			o.BaseEndpoint = aws.String(opts.EndpointURL)
+		}
+		o.UsePathStyle = opts.ForcePathStyle
+		if opts.RequestPayer != "" {
+			o.APIOptions = append(o.APIOptions, smithyhttp.AddHeaderValue("X-Amz-Request-Payer", opts.RequestPayer))
+		}
+		if len(opts.Headers) > 0 {
+			o.APIOptions = append(o.APIOptions, HeaderMiddleware(opts.Headers))
+		}
+		for _, m := range opts.S3Middleware {
+			o.APIOptions = append(o.APIOptions, m)
+		}
+	})
 
 	return &Client{
-		S3:     s3Client,
-		Config: cfg,
+		S3:          s3Client,
+		Config:      cfg,
+		guard:       guard,
+		audit:       audit,
+		endpointURL: opts.EndpointURL,
+		anonymous:   opts.NoSignRequest,
+		retryer:     newRetryer(cfg),
 	}, nil
 }
 
-// GetBucketRegion retrieves the region for a specific bucket
+// newRetryer returns the retryer SDK clients resolve from cfg: the
+// configured one, or the standard or adaptive mode with the configured
+// attempts
+func newRetryer(cfg aws.Config) aws.Retryer {
+	if cfg.Retryer != nil {
+		return cfg.Retryer()
+	}
+	standard := func(o *retry.StandardOptions) {
+		if cfg.RetryMaxAttempts > 0 {
+			o.MaxAttempts = cfg.RetryMaxAttempts
+		}
+	}
+	if cfg.RetryMode == aws.RetryModeAdaptive {
+		return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, standard)
+		})
+	}
+	return retry.NewStandard(standard)
+}
+
+// assumeRoleProvider returns credentials of the options' role, assumed
+// with the credentials of cfg
+func assumeRoleProvider(cfg aws.Config, opts ClientOptions) *stscreds.AssumeRoleProvider {
+	stsClient := sts.NewFromConfig(cfg, func(o *sts.Options) {
+		// STS has a global endpoint; the bucket region may still be unknown
+		if o.Region == "" {
+			o.Region = defaultEndpointRegion
+		}
+	})
+	return stscreds.NewAssumeRoleProvider(stsClient, opts.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = roleSessionName
+		o.Duration = roleSessionDuration
+		if opts.ExternalID != "" {
+			o.ExternalID = aws.String(opts.ExternalID)
+		}
+		if opts.MFASerial != "" {
+			o.SerialNumber = aws.String(opts.MFASerial)
+			o.TokenProvider = stdinTokenCode
+		}
+	})
+}
+
+// tokenPrompt keeps clients that assume roles concurrently from prompting
+// for MFA codes at the same time
+var tokenPrompt sync.Mutex
+
+// stdinTokenCode prompts for an MFA token code on stdin, one prompt at a
+// time
+func stdinTokenCode() (string, error) {
+	tokenPrompt.Lock()
+	defer tokenPrompt.Unlock()
+	return stscreds.StdinTokenProvider()
+}
+
+// newHTTPClient builds an HTTP client with the connection pool and timeouts
+// from the options applied on top of the SDK defaults
+func newHTTPClient(opts ClientOptions) *awshttp.BuildableClient {
+	client := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		if opts.MaxConns > 0 {
+			tr.MaxIdleConns = opts.MaxConns
+			tr.MaxIdleConnsPerHost = opts.MaxConns
+			tr.MaxConnsPerHost = opts.MaxConns
+		}
+		if opts.TLSHandshakeTimeout > 0 {
+			tr.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+		}
+		// An overall client timeout would cut off long object downloads;
+		// the transport only bounds the wait for response headers, and
+		// requestTimeout each attempt's complete response
+		if opts.RequestTimeout > 0 {
+			tr.ResponseHeaderTimeout = opts.RequestTimeout
+		}
+	})
+	return client
+}
+
+// AccountID returns the AWS account ID of the configured credentials
+func (c *Client) AccountID(ctx context.Context) (string, error) {
+	c.accountOnce.Do(func() {
+		result, err := sts.NewFromConfig(c.Config).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			c.accountErr = err
+			return
+		}
+		c.accountID = aws.ToString(result.Account)
+	})
+	return c.accountID, c.accountErr
+}
+
+// CustomEndpoint reports whether S3 requests go to an S3-compatible store
+// instead of AWS
+func (c *Client) CustomEndpoint() bool {
+	return c.endpointURL != ""
+}
+
+// Anonymous reports whether requests are sent unsigned
+func (c *Client) Anonymous() bool {
+	return c.anonymous
+}
+
+// GetBucketRegion retrieves the region for a specific bucket. Stores behind
+// a custom endpoint that do not implement GetBucketLocation get the
+// configured region.
 func (c *Client) GetBucketRegion(ctx context.Context, bucketName string) (string, error) {
+	// GetBucketLocation is reserved to the bucket owner
+	if c.anonymous && c.endpointURL == "" {
+		return c.headBucketRegion(ctx, bucketName)
+	}
+
 	result, err := c.S3.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
 		Bucket: aws.String(bucketName),
 	})
 	if err != nil {
+		if c.endpointURL != "" {
+			return c.Config.Region, nil
+		}
 		return "", err
 	}
+	if c.endpointURL != "" && result.LocationConstraint == "" {
+		return c.Config.Region, nil
+	}
 
 	// Handle empty region (means us-east-1)
 	if result.LocationConstraint == "" {
@@ -59,3 +368,26 @@ func (c *Client) GetBucketRegion(ctx context.Context, bucketName string) (string
 
 	return string(result.LocationConstraint), nil
 }
+
+// headBucketRegion reads a bucket's region from the x-amz-bucket-region
+// header, which S3 returns to anyone, even when HeadBucket itself is
+// redirected or denied
+func (c *Client) headBucketRegion(ctx context.Context, bucketName string) (string, error) {
+	result, err := c.S3.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err == nil {
+		if region := aws.ToString(result.BucketRegion); region != "" {
+			return region, nil
+		}
+		return c.Config.Region, nil
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		if region := respErr.Response.Header.Get("X-Amz-Bucket-Region"); region != "" {
+			return region, nil
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,177 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// metricStatisticsResponse is the GetMetricStatistics response
+type metricStatisticsResponse struct {
+	Datapoints []struct {
+		Timestamp float64 `json:"Timestamp"`
+		Average   float64 `json:"Average"`
+	} `json:"Datapoints"`
+}
+
+// cloudWatchOperation returns a call to the CloudWatch JSON API for a
+// bucket's storage metrics
+func cloudWatchOperation(name, region, bucketName string, body any) *operation {
+	return &operation{
+		ServiceID:      "CloudWatch",
+		Name:           name,
+		SigningName:    "monitoring",
+		EndpointPrefix: "monitoring",
+		Region:         region,
+		Method:         "POST",
+		Header: map[string]string{
+			"Content-Type": "application/x-amz-json-1.0",
+			"X-Amz-Target": "GraniteServiceVersion20100801." + name,
+		},
+		Body:   body,
+		Bucket: &bucketName,
+	}
+}
+
+// GetBucketObjectCount returns the most recent daily NumberOfObjects
+// storage metric that S3 publishes to CloudWatch for the bucket, and the
+// day it was recorded. It returns a zero count when no datapoint exists,
+// e.g. for buckets created less than a day ago.
+func (c *Client) GetBucketObjectCount(ctx context.Context, bucketName, region string) (int64, time.Time, error) {
+	end := time.Now()
+	// Storage metrics are published once a day with up to a day of delay
+	start := end.Add(-3 * 24 * time.Hour)
+
+	body := map[string]any{
+		"Namespace":  "AWS/S3",
+		"MetricName": "NumberOfObjects",
+		"Dimensions": []map[string]string{
+			{"Name": "BucketName", "Value": bucketName},
+			{"Name": "StorageType", "Value": "AllStorageTypes"},
+		},
+		"StartTime":  start.Unix(),
+		"EndTime":    end.Unix(),
+		"Period":     86400,
+		"Statistics": []string{"Average"},
+	}
+
+	var resp metricStatisticsResponse
+	err := c.invoke(ctx, cloudWatchOperation("GetMetricStatistics", region, bucketName, body), &resp)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var count int64
+	var latest float64
+	for _, dp := range resp.Datapoints {
+		if dp.Timestamp > latest {
+			latest = dp.Timestamp
+			count = int64(dp.Average)
+		}
+	}
+	if latest == 0 {
+		return 0, time.Time{}, nil
+	}
+
+	return count, time.Unix(int64(latest), 0).UTC(), nil
+}
+
+// bucketStorageTypes are the StorageType dimensions of the BucketSizeBytes
+// storage metric
+var bucketStorageTypes = []string{
+	"StandardStorage",
+	"IntelligentTieringFAStorage",
+	"IntelligentTieringIAStorage",
+	"IntelligentTieringAIAStorage",
+	"IntelligentTieringAAStorage",
+	"IntelligentTieringDAAStorage",
+	"StandardIAStorage",
+	"StandardIASizeOverhead",
+	"OneZoneIAStorage",
+	"OneZoneIASizeOverhead",
+	"ReducedRedundancyStorage",
+	"GlacierInstantRetrievalStorage",
+	"GlacierIRSizeOverhead",
+	"GlacierStorage",
+	"GlacierStagingStorage",
+	"GlacierObjectOverhead",
+	"GlacierS3ObjectOverhead",
+	"DeepArchiveStorage",
+	"DeepArchiveObjectOverhead",
+	"DeepArchiveS3ObjectOverhead",
+	"DeepArchiveStagingStorage",
+}
+
+// metricDataResponse is the GetMetricData response
+type metricDataResponse struct {
+	MetricDataResults []struct {
+		ID         string    `json:"Id"`
+		Timestamps []float64 `json:"Timestamps"`
+		Values     []float64 `json:"Values"`
+	} `json:"MetricDataResults"`
+}
+
+// GetBucketSizeBytes returns the most recent daily BucketSizeBytes storage
+// metric per storage type, and the day it was recorded. Storage types
+// without a datapoint that day are left out; the map is empty when the
+// bucket has no metrics yet.
+func (c *Client) GetBucketSizeBytes(ctx context.Context, bucketName, region string) (map[string]int64, time.Time, error) {
+	end := time.Now()
+	// Storage metrics are published once a day with up to a day of delay
+	start := end.Add(-3 * 24 * time.Hour)
+
+	queries := make([]map[string]any, len(bucketStorageTypes))
+	for i, storageType := range bucketStorageTypes {
+		queries[i] = map[string]any{
+			"Id": fmt.Sprintf("m%d", i),
+			"MetricStat": map[string]any{
+				"Metric": map[string]any{
+					"Namespace":  "AWS/S3",
+					"MetricName": "BucketSizeBytes",
+					"Dimensions": []map[string]string{
+						{"Name": "BucketName", "Value": bucketName},
+						{"Name": "StorageType", "Value": storageType},
+					},
+				},
+				"Period": 86400,
+				"Stat":   "Average",
+			},
+			"ReturnData": true,
+		}
+	}
+
+	var resp metricDataResponse
+	err := c.invoke(ctx, cloudWatchOperation("GetMetricData", region, bucketName, map[string]any{
+		"MetricDataQueries": queries,
+		"StartTime":         start.Unix(),
+		"EndTime":           end.Unix(),
+	}), &resp)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// Report the latest day any storage type has a datapoint for
+	var latest float64
+	for _, result := range resp.MetricDataResults {
+		for _, ts := range result.Timestamps {
+			latest = max(latest, ts)
+		}
+	}
+	sizes := make(map[string]int64)
+	if latest == 0 {
+		return sizes, time.Time{}, nil
+	}
+	for _, result := range resp.MetricDataResults {
+		var i int
+		if _, err := fmt.Sscanf(result.ID, "m%d", &i); err != nil || i >= len(bucketStorageTypes) {
+			continue
+		}
+		for j, ts := range result.Timestamps {
+			if ts == latest && j < len(result.Values) {
+				sizes[bucketStorageTypes[i]] = int64(result.Values[j])
+			}
+		}
+	}
+
+	return sizes, time.Unix(int64(latest), 0).UTC(), nil
+}
@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ErrContentAccess is returned for calls blocked by --no-content-access
+var ErrContentAccess = errors.New("blocked by --no-content-access")
+
+// contentOperations are the S3 operations that read an object's content
+// or its per-object headers, which --no-content-access blocks
+var contentOperations = map[string]bool{
+	"GetObject":           true,
+	"HeadObject":          true,
+	"GetObjectAttributes": true,
+	"SelectObjectContent": true,
+	"GetObjectTorrent":    true,
+}
+
+// addContentGuard rejects S3 operations that read objects. It is added to
+// aws.Config.APIOptions like the read-only guard, so no client built from
+// the configuration can read an object, whatever analyzer asks.
+func addContentGuard(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("NoContentAccess",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			operation := awsmiddleware.GetOperationName(ctx)
+			if awsmiddleware.GetServiceID(ctx) == "S3" && contentOperations[operation] {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("S3:%s %w", operation, ErrContentAccess)
+			}
+			return next.HandleInitialize(ctx, in)
+		}), middleware.After)
+}
@@ -0,0 +1,187 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourceexplorer2"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Discovery backends accepted by --discover
+const (
+	DiscoverResourceExplorer = "resource-explorer"
+	DiscoverConfig           = "config"
+)
+
+// Discovery is a parsed --discover value: a backend and, for Config, the
+// aggregator name or, for Resource Explorer, an optional view ARN
+type Discovery struct {
+	Backend string
+	Target  string
+}
+
+// ParseDiscovery parses "resource-explorer", "resource-explorer:<view-arn>"
+// or "config:<aggregator-name>"
+func ParseDiscovery(value string) (*Discovery, error) {
+	backend, target, _ := strings.Cut(value, ":")
+	switch {
+	case backend == DiscoverResourceExplorer:
+		if target != "" && !strings.HasPrefix(target, "arn:") {
+			return nil, fmt.Errorf("invalid --discover view %q (expected a Resource Explorer view ARN)", target)
+		}
+	case backend == DiscoverConfig && target != "":
+	case backend == DiscoverConfig:
+		return nil, fmt.Errorf("--discover config needs an aggregator name: config:<aggregator-name>")
+	default:
+		return nil, fmt.Errorf("invalid --discover backend %q (expected %s[:<view-arn>] or %s:<aggregator-name>)", value, DiscoverResourceExplorer, DiscoverConfig)
+	}
+	return &Discovery{Backend: backend, Target: target}, nil
+}
+
+// String describes the backend for progress messages
+func (d *Discovery) String() string {
+	if d.Backend == DiscoverConfig {
+		return fmt.Sprintf("AWS Config aggregator %s", d.Target)
+	}
+	return "AWS Resource Explorer"
+}
+
+// DiscoverBuckets lists the S3 buckets known to an inventory service. Unlike
+// ListBuckets, an aggregator or a multi-account Resource Explorer view
+// covers every account and region it collects, and reports each bucket's
+// owner and region. Buckets are sorted by name.
+func (c *Client) DiscoverBuckets(ctx context.Context, d *Discovery) ([]types.DiscoveredBucket, error) {
+	var buckets []types.DiscoveredBucket
+	var err error
+	if d.Backend == DiscoverConfig {
+		buckets, err = c.discoverConfig(ctx, d.Target)
+	} else {
+		buckets, err = c.discoverResourceExplorer(ctx, d.Target)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover buckets with %s: %w", d, err)
+	}
+
+	// A bucket name is global, but inventories can report it twice while
+	// it is being recreated
+	seen := make(map[string]bool, len(buckets))
+	unique := buckets[:0]
+	for _, b := range buckets {
+		if b.Name != "" && !seen[b.Name] {
+			seen[b.Name] = true
+			unique = append(unique, b)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].Name < unique[j].Name })
+	return unique, nil
+}
+
+// discoverResourceExplorer searches a Resource Explorer view for buckets.
+// Without a view ARN the default view of the client's region is used; a
+// view is searched in the region it was created in.
+func (c *Client) discoverResourceExplorer(ctx context.Context, viewArn string) ([]types.DiscoveredBucket, error) {
+	input := &resourceexplorer2.SearchInput{
+		QueryString: aws.String("resourcetype:s3:bucket"),
+		MaxResults:  aws.Int32(1000),
+	}
+	region := c.Config.Region
+	if viewArn != "" {
+		input.ViewArn = aws.String(viewArn)
+		if parts := strings.SplitN(viewArn, ":", 6); len(parts) == 6 && parts[3] != "" {
+			region = parts[3]
+		}
+	}
+	client := resourceexplorer2.NewFromConfig(c.Config, func(o *resourceexplorer2.Options) {
+		o.Region = region
+	})
+
+	var buckets []types.DiscoveredBucket
+	paginator := resourceexplorer2.NewSearchPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page.Resources {
+			loc, err := types.ParseLocation(aws.ToString(r.Arn))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Resource Explorer bucket: %w", err)
+			}
+			buckets = append(buckets, types.DiscoveredBucket{
+				Name:    loc.Bucket,
+				Account: aws.ToString(r.OwningAccountId),
+				Region:  aws.ToString(r.Region),
+			})
+		}
+	}
+
+	return buckets, nil
+}
+
+// discoverConfig lists the buckets recorded by an AWS Config aggregator,
+// which is queried in the client's region
+func (c *Client) discoverConfig(ctx context.Context, aggregator string) ([]types.DiscoveredBucket, error) {
+	region := c.Config.Region
+
+	var buckets []types.DiscoveredBucket
+	nextToken := ""
+	for {
+		body := map[string]any{
+			"ConfigurationAggregatorName": aggregator,
+			"ResourceType":                "AWS::S3::Bucket",
+			"Limit":                       100,
+		}
+		if nextToken != "" {
+			body["NextToken"] = nextToken
+		}
+
+		var page struct {
+			ResourceIdentifiers []struct {
+				SourceAccountId string `json:"SourceAccountId"`
+				SourceRegion    string `json:"SourceRegion"`
+				ResourceId      string `json:"ResourceId"`
+				ResourceName    string `json:"ResourceName"`
+			} `json:"ResourceIdentifiers"`
+			NextToken string `json:"NextToken"`
+		}
+		err := c.invoke(ctx, &operation{
+			ServiceID:      "Config Service",
+			Name:           "ListAggregateDiscoveredResources",
+			SigningName:    "config",
+			EndpointPrefix: "config",
+			Region:         region,
+			Method:         "POST",
+			Header: map[string]string{
+				"Content-Type": "application/x-amz-json-1.1",
+				"X-Amz-Target": "StarlingDoveService.ListAggregateDiscoveredResources",
+			},
+			Body: body,
+		}, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range page.ResourceIdentifiers {
+			name := r.ResourceName
+			if name == "" {
+				name = r.ResourceId
+			}
+			buckets = append(buckets, types.DiscoveredBucket{
+				Name:    name,
+				Account: r.SourceAccountId,
+				Region:  r.SourceRegion,
+			})
+		}
+
+		if page.NextToken == "" {
+			break
+		}
+		nextToken = page.NextToken
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// glueTableResponse is the subset of the Glue GetTable response needed to
+// register partitions
+type glueTableResponse struct {
+	Table struct {
+		Name              string         `json:"Name"`
+		DatabaseName      string         `json:"DatabaseName"`
+		StorageDescriptor map[string]any `json:"StorageDescriptor"`
+		PartitionKeys     []struct {
+			Name string `json:"Name"`
+		} `json:"PartitionKeys"`
+	} `json:"Table"`
+}
+
+// GetGlueTable returns a Glue table's location, partition keys, storage
+// descriptor and the values of every registered partition
+func (c *Client) GetGlueTable(ctx context.Context, database, table, region string) (*types.GlueTable, error) {
+	var resp glueTableResponse
+	if err := c.callGlue(ctx, region, "GetTable", map[string]any{
+		"DatabaseName": database,
+		"Name":         table,
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &types.GlueTable{
+		Database:          resp.Table.DatabaseName,
+		Name:              resp.Table.Name,
+		StorageDescriptor: resp.Table.StorageDescriptor,
+	}
+	if location, ok := resp.Table.StorageDescriptor["Location"].(string); ok {
+		result.Location = location
+	}
+	for _, key := range resp.Table.PartitionKeys {
+		result.PartitionKeys = append(result.PartitionKeys, key.Name)
+	}
+
+	nextToken := ""
+	for {
+		body := map[string]any{
+			"DatabaseName":        database,
+			"TableName":           table,
+			"ExcludeColumnSchema": true,
+		}
+		if nextToken != "" {
+			body["NextToken"] = nextToken
+		}
+
+		var page struct {
+			Partitions []struct {
+				Values []string `json:"Values"`
+			} `json:"Partitions"`
+			NextToken string `json:"NextToken"`
+		}
+		if err := c.callGlue(ctx, region, "GetPartitions", body, &page); err != nil {
+			return nil, err
+		}
+		for _, p := range page.Partitions {
+			result.Partitions = append(result.Partitions, p.Values)
+		}
+
+		if page.NextToken == "" {
+			break
+		}
+		nextToken = page.NextToken
+	}
+
+	return result, nil
+}
+
+// callGlue sends a Glue JSON API request
+func (c *Client) callGlue(ctx context.Context, region, action string, body any, out any) error {
+	return c.invoke(ctx, &operation{
+		ServiceID:      "Glue",
+		Name:           action,
+		SigningName:    "glue",
+		EndpointPrefix: "glue",
+		Region:         region,
+		Method:         "POST",
+		Header: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+			"X-Amz-Target": "AWSGlue." + action,
+		},
+		Body: body,
+	}, out)
+}
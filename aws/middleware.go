@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Middleware adds custom steps to the middleware stack of every S3
+// operation, such as headers a gateway requires, signing tweaks or request
+// logging. It runs after the client's own middleware is registered, so
+// steps can be inserted relative to SDK steps by ID (e.g. before
+// "Signing" in stack.Finalize).
+type Middleware func(stack *middleware.Stack) error
+
+// HeaderMiddleware sets headers on every request. They are added in the
+// build step, before signing, so they are covered by the signature.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(stack *middleware.Stack) error {
+		return stack.Build.Add(middleware.BuildMiddlewareFunc("CustomHeaders",
+			func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					for name, values := range header {
+						req.Header.Del(name)
+						for _, value := range values {
+							req.Header.Add(name, value)
+						}
+					}
+				}
+				return next.HandleBuild(ctx, in)
+			}), middleware.After)
+	}
+}
+
+// ContextMiddleware calls inject with the operation name and input of
+// every call at the end of the initialize step. The context it returns is
+// passed to the serialize, build, finalize and deserialize steps, so custom
+// middleware in later steps can read values stored per call.
+func ContextMiddleware(id string, inject func(ctx context.Context, operation string, input any) context.Context) Middleware {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(id,
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				return next.HandleInitialize(inject(ctx, awsmiddleware.GetOperationName(ctx), in.Parameters), in)
+			}), middleware.After)
+	}
+}
+
+// ParseHeaders parses "Name: value" pairs as given to --header
+func ParseHeaders(pairs []string) (http.Header, error) {
+	header := make(http.Header)
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q (expected \"Name: value\")", pair)
+		}
+		header.Add(name, strings.TrimSpace(value))
+	}
+	return header, nil
+}
@@ -0,0 +1,326 @@
+package aws
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// operation is a call to an AWS service whose SDK client this module does
+// not depend on. It runs on an SDK middleware stack with the client's
+// APIOptions, retryer, credentials and HTTP client, so the read-only
+// guard, the audit log and the rate limiter see it like any SDK call.
+type operation struct {
+	// ServiceID and Name identify the call as the SDK would, e.g. "Glue"
+	// and "GetTable"
+	ServiceID string
+	Name      string
+	// SigningName is the SigV4 service name; EndpointPrefix the first
+	// label of the regional host
+	SigningName    string
+	EndpointPrefix string
+	// HostPrefix is prepended to the host, e.g. "<account>." for S3 Control
+	HostPrefix string
+	Region     string
+
+	Method string
+	Path   string
+	Query  url.Values
+	Header map[string]string
+	// Body is sent as JSON
+	Body any
+	// XML decodes the response as XML instead of JSON
+	XML bool
+
+	// Bucket is the bucket the call is about, logged by the guards and the
+	// audit log
+	Bucket *string
+}
+
+// invoke sends an operation and decodes the response into out
+func (c *Client) invoke(ctx context.Context, op *operation, out any) error {
+	stack := middleware.NewStack(op.Name, smithyhttp.NewStackRequest)
+	err := stack.Initialize.Add(&awsmiddleware.RegisterServiceMetadata{
+		ServiceID:     op.ServiceID,
+		SigningName:   op.SigningName,
+		Region:        op.Region,
+		OperationName: op.Name,
+	}, middleware.Before)
+	if err == nil {
+		err = stack.Serialize.Add(middleware.SerializeMiddlewareFunc("OperationSerializer", c.serializeOperation), middleware.After)
+	}
+	if err == nil {
+		err = smithyhttp.AddComputeContentLengthMiddleware(stack)
+	}
+	if err == nil {
+		err = awsmiddleware.AddClientRequestIDMiddleware(stack)
+	}
+	if err == nil {
+		err = stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("Signing", c.signOperation), middleware.After)
+	}
+	if err == nil {
+		err = retry.AddRetryMiddlewares(stack, retry.AddRetryMiddlewaresOptions{Retryer: c.retryer})
+	}
+	if err == nil {
+		err = stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("OperationDeserializer", deserializeOperation), middleware.After)
+	}
+	for _, fn := range []func(*middleware.Stack) error{
+		awsmiddleware.AddRequestIDRetrieverMiddleware,
+		awsmiddleware.AddRawResponseToMetadata,
+		awsmiddleware.AddRecordResponseTiming,
+	} {
+		if err == nil {
+			err = fn(stack)
+		}
+	}
+	for _, fn := range c.Config.APIOptions {
+		if err == nil {
+			err = fn(stack)
+		}
+	}
+	if err != nil {
+		return &smithy.OperationError{ServiceID: op.ServiceID, OperationName: op.Name, Err: err}
+	}
+
+	handler := middleware.DecorateHandler(smithyhttp.NewClientHandler(c.Config.HTTPClient), stack)
+	result, _, err := handler.Handle(ctx, op)
+	if err != nil {
+		return &smithy.OperationError{ServiceID: op.ServiceID, OperationName: op.Name, Err: err}
+	}
+
+	body, _ := result.([]byte)
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if op.XML {
+		err = xml.Unmarshal(body, out)
+	} else {
+		err = json.Unmarshal(body, out)
+	}
+	if err != nil {
+		return &smithy.OperationError{ServiceID: op.ServiceID, OperationName: op.Name, Err: fmt.Errorf("failed to decode response: %w", err)}
+	}
+	return nil
+}
+
+// serializeOperation builds the HTTP request of an operation against the
+// endpoint of its region
+func (c *Client) serializeOperation(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (middleware.SerializeOutput, middleware.Metadata, error) {
+	op, ok := in.Parameters.(*operation)
+	req, isHTTP := in.Request.(*smithyhttp.Request)
+	if !ok || !isHTTP {
+		return middleware.SerializeOutput{}, middleware.Metadata{}, fmt.Errorf("unexpected operation input %T", in.Parameters)
+	}
+
+	endpoint, err := c.serviceEndpoint(ctx, op.EndpointPrefix, op.Region)
+	if err != nil {
+		return middleware.SerializeOutput{}, middleware.Metadata{}, err
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return middleware.SerializeOutput{}, middleware.Metadata{}, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	// Custom endpoints, such as local emulators, are used as given
+	if c.Config.BaseEndpoint == nil {
+		u.Host = op.HostPrefix + u.Host
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + op.Path
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawQuery = op.Query.Encode()
+	req.URL = u
+	req.Method = op.Method
+
+	var payload []byte
+	if op.Body != nil {
+		if payload, err = json.Marshal(op.Body); err != nil {
+			return middleware.SerializeOutput{}, middleware.Metadata{}, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range op.Header {
+		req.Header.Set(k, v)
+	}
+	hash := sha256.Sum256(payload)
+	if op.SigningName == "s3" {
+		// S3 Control requires the payload hash as a signed header
+		req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(hash[:]))
+	}
+	if req, err = req.SetStream(bytes.NewReader(payload)); err != nil {
+		return middleware.SerializeOutput{}, middleware.Metadata{}, err
+	}
+	in.Request = req
+
+	return next.HandleSerialize(v4.SetPayloadHash(ctx, hex.EncodeToString(hash[:])), in)
+}
+
+// signOperation signs each attempt with SigV4; anonymous clients send
+// requests unsigned
+func (c *Client) signOperation(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	if c.anonymous || c.Config.Credentials == nil {
+		return next.HandleFinalize(ctx, in)
+	}
+	req, ok := in.Request.(*smithyhttp.Request)
+	if !ok {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("unexpected request type %T", in.Request)
+	}
+
+	creds, err := c.Config.Credentials.Retrieve(ctx)
+	if err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+	err = v4.NewSigner().SignHTTP(ctx, creds, req.Request, v4.GetPayloadHash(ctx),
+		awsmiddleware.GetSigningName(ctx), awsmiddleware.GetRegion(ctx), time.Now())
+	if err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("failed to sign request: %w", err)
+	}
+	return next.HandleFinalize(ctx, in)
+}
+
+// deserializeOperation returns the response body, or an API error the
+// retryer can classify for error responses
+func deserializeOperation(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (middleware.DeserializeOutput, middleware.Metadata, error) {
+	out, metadata, err := next.HandleDeserialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+	resp, ok := out.RawResponse.(*smithyhttp.Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unexpected response type %T", out.RawResponse)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return out, metadata, &smithy.DeserializationError{Err: fmt.Errorf("failed to read response body: %w", err)}
+	}
+	if resp.StatusCode >= 300 {
+		return out, metadata, &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{Response: resp, Err: apiError(resp, body)},
+			RequestID:     cmp.Or(resp.Header.Get("X-Amzn-Requestid"), resp.Header.Get("X-Amz-Request-Id")),
+		}
+	}
+
+	out.Result = body
+	return out, metadata, nil
+}
+
+// apiError reads the error code and message of a JSON, REST-JSON or XML
+// error response
+func apiError(resp *smithyhttp.Response, body []byte) *smithy.GenericAPIError {
+	// JSON field names match case-insensitively, so these also read Code
+	// and Message
+	var j struct {
+		Type    string `json:"__type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	var x struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+		Error   struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		} `xml:"Error"`
+	}
+	if json.Unmarshal(body, &j) != nil {
+		_ = xml.Unmarshal(body, &x)
+	}
+
+	code := cmp.Or(resp.Header.Get("X-Amzn-Errortype"), j.Type, j.Code, x.Code, x.Error.Code)
+	// Codes may carry a namespace and a trailing reason:
+	// com.amazon.coral#ThrottlingException:http://...
+	code, _, _ = strings.Cut(code, ":")
+	if i := strings.LastIndex(code, "#"); i >= 0 {
+		code = code[i+1:]
+	}
+	return &smithy.GenericAPIError{
+		Code:    cmp.Or(code, resp.Status),
+		Message: cmp.Or(j.Message, x.Message, x.Error.Message),
+	}
+}
+
+// partitionDNSSuffixes are the endpoint domains of the AWS partitions by
+// region prefix; other regions are in the aws partition
+var partitionDNSSuffixes = []struct {
+	regionPrefix, partition, dnsSuffix string
+}{
+	{"cn-", "aws-cn", "amazonaws.com.cn"},
+	{"us-gov-", "aws-us-gov", "amazonaws.com"},
+	{"us-isob-", "aws-iso-b", "sc2s.sgov.gov"},
+	{"us-isof-", "aws-iso-f", "csp.hci.ic.gov"},
+	{"us-iso-", "aws-iso", "c2s.ic.gov"},
+	{"eu-isoe-", "aws-iso-e", "cloud.adc-e.uk"},
+	{"eusc-", "aws-eusc", "amazonaws.eu"},
+}
+
+// regionPartition returns the ARN partition of a region, e.g. aws-cn for
+// cn-north-1
+func regionPartition(region string) string {
+	for _, p := range partitionDNSSuffixes {
+		if strings.HasPrefix(region, p.regionPrefix) {
+			return p.partition
+		}
+	}
+	return "aws"
+}
+
+// serviceEndpoint returns the base URL of a service in a region: the
+// configured endpoint URL (AWS_ENDPOINT_URL, endpoint_url) when set, or
+// the regional host in the region's partition, its FIPS variant when
+// FIPS endpoints are enabled
+func (c *Client) serviceEndpoint(ctx context.Context, prefix, region string) (string, error) {
+	if c.Config.BaseEndpoint != nil {
+		return *c.Config.BaseEndpoint, nil
+	}
+	if region == "" {
+		return "", fmt.Errorf("no region to send %s requests to", prefix)
+	}
+
+	dnsSuffix := "amazonaws.com"
+	for _, p := range partitionDNSSuffixes {
+		if strings.HasPrefix(region, p.regionPrefix) {
+			dnsSuffix = p.dnsSuffix
+			break
+		}
+	}
+	if c.useFIPS(ctx) {
+		prefix += "-fips"
+	}
+	return fmt.Sprintf("https://%s.%s.%s", prefix, region, dnsSuffix), nil
+}
+
+// useFIPS reports whether FIPS endpoints are enabled in the environment
+// or shared config, which the configuration keeps as config sources
+func (c *Client) useFIPS(ctx context.Context) bool {
+	type fipsSource interface {
+		GetUseFIPSEndpoint(context.Context) (aws.FIPSEndpointState, bool, error)
+	}
+	for _, source := range c.Config.ConfigSources {
+		if s, ok := source.(fipsSource); ok {
+			if state, found, err := s.GetUseFIPSEndpoint(ctx); err == nil && found {
+				return state == aws.FIPSEndpointStateEnabled
+			}
+		}
+	}
+	return false
+}
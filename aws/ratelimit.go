@@ -0,0 +1,101 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+)
+
+// rateLimiter keeps AWS requests under a rate per bucket. Each bucket has
+// its own token bucket, shared by every worker sending requests to it, so
+// listing, enrichment and sampling workers draw from the same budget.
+type rateLimiter struct {
+	rate float64
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokenBucket refills at rate tokens per second up to burst; a request
+// takes one token, waiting for it when the bucket is empty
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{rate: rate, buckets: make(map[string]*tokenBucket)}
+}
+
+// bucket returns the token bucket of an S3 bucket, starting full so the
+// first second of requests is not delayed
+func (r *rateLimiter) bucket(name string) *tokenBucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buckets[name]
+	if !ok {
+		burst := max(r.rate, 1)
+		b = &tokenBucket{rate: r.rate, burst: burst, tokens: burst, last: time.Now()}
+		r.buckets[name] = b
+	}
+	return b
+}
+
+// wait takes a token, blocking until one is available. Tokens are
+// reserved in arrival order, so waiting workers are served fairly.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the reserved token back
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+type rateLimitBucketKey struct{}
+
+// addMiddleware delays every attempt of AWS operations, including
+// retries, until its bucket has a token. Operations without a Bucket
+// input share the bucket named "". The wait runs after the retry step but
+// before signing, so a long wait cannot age a signature.
+func (r *rateLimiter) addMiddleware(stack *middleware.Stack) error {
+	err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("RateLimitBucket",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			return next.HandleInitialize(middleware.WithStackValue(ctx, rateLimitBucketKey{}, inputField(in.Parameters, "Bucket")), in)
+		}), middleware.After)
+	if err != nil {
+		return err
+	}
+
+	return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("RateLimit",
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			name, _ := middleware.GetStackValue(ctx, rateLimitBucketKey{}).(string)
+			if err := r.bucket(name).wait(ctx); err != nil {
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+			}
+			return next.HandleFinalize(ctx, in)
+		}), "Retry", middleware.After)
+}
@@ -0,0 +1,29 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// PublishSNS publishes a message to an SNS topic. The region is taken from
+// the topic ARN. Publish is a write, so --read-only-strict blocks it.
+func (c *Client) PublishSNS(ctx context.Context, topicARN, subject, message string) error {
+	parts := strings.Split(topicARN, ":")
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "sns" {
+		return fmt.Errorf("invalid SNS topic ARN %q", topicARN)
+	}
+
+	client := sns.NewFromConfig(c.Config, func(o *sns.Options) {
+		o.Region = parts[3]
+	})
+	_, err := client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(topicARN),
+		Subject:  aws.String(subject),
+		Message:  aws.String(message),
+	})
+	return err
+}
@@ -0,0 +1,149 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// storageLensList is the ListStorageLensConfigurations response
+type storageLensList struct {
+	NextToken string `xml:"NextToken"`
+	Configs   []struct {
+		ID         string `xml:"Id"`
+		HomeRegion string `xml:"HomeRegion"`
+		IsEnabled  bool   `xml:"IsEnabled"`
+	} `xml:"StorageLensConfigurationList"`
+}
+
+// enabledFlag is a Storage Lens metrics selection
+type enabledFlag struct {
+	IsEnabled bool `xml:"IsEnabled"`
+}
+
+// storageLensConfig is the subset of GetStorageLensConfiguration needed to
+// decide whether a bucket is covered by paid advanced metrics
+type storageLensConfig struct {
+	ID           string `xml:"Id"`
+	IsEnabled    bool   `xml:"IsEnabled"`
+	AccountLevel struct {
+		ActivityMetrics                 enabledFlag `xml:"ActivityMetrics"`
+		AdvancedCostOptimizationMetrics enabledFlag `xml:"AdvancedCostOptimizationMetrics"`
+		AdvancedDataProtectionMetrics   enabledFlag `xml:"AdvancedDataProtectionMetrics"`
+		DetailedStatusCodesMetrics      enabledFlag `xml:"DetailedStatusCodesMetrics"`
+		BucketLevel                     struct {
+			PrefixLevel *struct{} `xml:"PrefixLevel"`
+		} `xml:"BucketLevel"`
+	} `xml:"AccountLevel"`
+	Include *storageLensScope `xml:"Include"`
+	Exclude *storageLensScope `xml:"Exclude"`
+}
+
+// storageLensScope lists the buckets and regions a configuration includes
+// or excludes
+type storageLensScope struct {
+	Buckets []string `xml:"Buckets>Arn"`
+	Regions []string `xml:"Regions>Region"`
+}
+
+// GetStorageLensConfigs returns the enabled Storage Lens configurations
+// homed in the given region that cover the bucket
+func (c *Client) GetStorageLensConfigs(ctx context.Context, bucketName, region string) ([]types.StorageLensConfig, error) {
+	accountID, err := c.AccountID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account ID: %w", err)
+	}
+
+	var ids []string
+	nextToken := ""
+	for {
+		op := storageLensOperation("ListStorageLensConfigurations", accountID, region, bucketName, "")
+		if nextToken != "" {
+			op.Query = url.Values{"nextToken": {nextToken}}
+		}
+
+		var page storageLensList
+		err := c.invoke(ctx, op, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cfg := range page.Configs {
+			if cfg.IsEnabled && cfg.HomeRegion == region {
+				ids = append(ids, cfg.ID)
+			}
+		}
+
+		if page.NextToken == "" {
+			break
+		}
+		nextToken = page.NextToken
+	}
+
+	bucketArn := types.Location{Bucket: bucketName, Partition: regionPartition(region)}.ARN()
+	var configs []types.StorageLensConfig
+	for _, id := range ids {
+		var cfg storageLensConfig
+		err := c.invoke(ctx, storageLensOperation("GetStorageLensConfiguration", accountID, region, bucketName, "/"+id), &cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if !cfg.covers(bucketArn, region) {
+			continue
+		}
+
+		account := cfg.AccountLevel
+		configs = append(configs, types.StorageLensConfig{
+			ID: cfg.ID,
+			Advanced: account.ActivityMetrics.IsEnabled ||
+				account.AdvancedCostOptimizationMetrics.IsEnabled ||
+				account.AdvancedDataProtectionMetrics.IsEnabled ||
+				account.DetailedStatusCodesMetrics.IsEnabled ||
+				account.BucketLevel.PrefixLevel != nil,
+		})
+	}
+
+	return configs, nil
+}
+
+// storageLensOperation returns a read of the account's Storage Lens
+// configurations from the S3 Control API
+func storageLensOperation(name, accountID, region, bucketName, path string) *operation {
+	return &operation{
+		ServiceID:      "S3 Control",
+		Name:           name,
+		SigningName:    "s3",
+		EndpointPrefix: "s3-control",
+		HostPrefix:     accountID + ".",
+		Region:         region,
+		Method:         "GET",
+		Path:           "/v20180820/storagelens" + path,
+		Header:         map[string]string{"X-Amz-Account-Id": accountID},
+		XML:            true,
+		Bucket:         &bucketName,
+	}
+}
+
+// covers reports whether the configuration's scope includes the bucket
+func (cfg *storageLensConfig) covers(bucketArn, region string) bool {
+	if cfg.Exclude != nil && (contains(cfg.Exclude.Buckets, bucketArn) || contains(cfg.Exclude.Regions, region)) {
+		return false
+	}
+	if cfg.Include == nil || (len(cfg.Include.Buckets) == 0 && len(cfg.Include.Regions) == 0) {
+		return true
+	}
+	return contains(cfg.Include.Buckets, bucketArn) || contains(cfg.Include.Regions, region)
+}
+
+// contains reports whether values contains v
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
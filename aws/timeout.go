@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// errAttemptTimeout cancels an attempt that ran past the request timeout
+var errAttemptTimeout = errors.New("request timeout")
+
+// requestTimeout bounds each attempt of an AWS call, from sending the
+// request to the end of its response. Object bodies streamed by GetObject
+// are read after the call returns and have no limit, so large downloads
+// are not cut off; the transport's ResponseHeaderTimeout still bounds the
+// wait for their first byte.
+type requestTimeout time.Duration
+
+// addMiddleware runs inside the retry loop, so a timed-out attempt is
+// retried like a dropped connection
+func (t requestTimeout) addMiddleware(stack *middleware.Stack) error {
+	return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("RequestTimeout", t.handle), "Retry", middleware.After)
+}
+
+func (t requestTimeout) handle(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	attemptCtx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(time.Duration(t), func() { cancel(errAttemptTimeout) })
+	out, metadata, err := next.HandleFinalize(attemptCtx, in)
+	timer.Stop()
+
+	if output, ok := out.Result.(*s3.GetObjectOutput); ok && err == nil && output.Body != nil {
+		// The caller reads the body with the attempt's context; release
+		// it once the body is closed
+		output.Body = &cancelOnClose{ReadCloser: output.Body, cancel: func() { cancel(nil) }}
+		return out, metadata, nil
+	}
+	cancel(nil)
+	if err != nil && ctx.Err() == nil && context.Cause(attemptCtx) == errAttemptTimeout {
+		// The SDK does not retry cancelled requests, so the cancellation
+		// is reported as the send failure it stands for
+		err = &smithyhttp.RequestSendError{Err: fmt.Errorf("request timeout: no complete response within %s", time.Duration(t))}
+	}
+	return out, metadata, err
+}
+
+// cancelOnClose is a response body that cancels its request's context
+// when closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,115 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// timeoutClient returns a client with a short --request-timeout sending
+// unsigned S3 requests to handler
+func timeoutClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewClient(context.Background(), ClientOptions{
+		Region:         "us-east-1",
+		EndpointURL:    server.URL,
+		ForcePathStyle: true,
+		NoSignRequest:  true,
+		RequestTimeout: 200 * time.Millisecond,
+		MaxAttempts:    2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		// stall delays the response headers of the first attempts, drip
+		// the body of every response
+		stall int32
+		drip  bool
+		want  string
+		// wantAttempts is the number of requests the server receives
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "fast", want: "data", wantAttempts: 1},
+		{name: "slow body is read whole", drip: true, want: "abcdef", wantAttempts: 1},
+		{name: "stalled attempt is retried", stall: 1, want: "data", wantAttempts: 2},
+		{name: "every attempt stalls", stall: 2, wantAttempts: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			client := timeoutClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) <= tt.stall {
+					time.Sleep(400 * time.Millisecond)
+					return
+				}
+				if !tt.drip {
+					io.WriteString(w, "data")
+					return
+				}
+				for _, c := range "abcdef" {
+					io.WriteString(w, string(c))
+					w.(http.Flusher).Flush()
+					time.Sleep(60 * time.Millisecond)
+				}
+			})
+
+			out, err := client.S3.GetObject(context.Background(), &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "timeout") {
+					t.Errorf("err = %v, want a request timeout", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(out.Body)
+			out.Body.Close()
+			if err != nil || string(body) != tt.want {
+				t.Errorf("body = %q (%v), want %q", body, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestRequestTimeoutBoundsResponse checks that responses the SDK reads
+// itself are bounded as a whole, not only until their headers arrive
+func TestRequestTimeoutBoundsResponse(t *testing.T) {
+	var attempts atomic.Int32
+	client := timeoutClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult>`)
+		w.(http.Flusher).Flush()
+		time.Sleep(400 * time.Millisecond)
+		io.WriteString(w, `<Name>bucket</Name></ListBucketResult>`)
+	})
+
+	_, err := client.S3.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{Bucket: aws.String("bucket")})
+	if err == nil || !strings.Contains(err.Error(), "no complete response within 200ms") {
+		t.Errorf("err = %v, want a request timeout", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
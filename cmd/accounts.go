@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	awsclient "github.com/yourusername/s3-profiler/aws"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/profiler"
+	"github.com/yourusername/s3-profiler/types"
+	"gopkg.in/yaml.v3"
+)
+
+// accountTarget is an AWS profile and region of an --accounts file with
+// the buckets to profile; no buckets means every bucket of the account
+type accountTarget struct {
+	Name    string `yaml:"name"`
+	Profile string `yaml:"profile"`
+	Region  string `yaml:"region"`
+	// RoleARN is assumed with the profile's credentials, passing ExternalID
+	RoleARN    string   `yaml:"role_arn"`
+	ExternalID string   `yaml:"external_id"`
+	Buckets    []string `yaml:"buckets"`
+}
+
+// accountsConfig is the layout of an --accounts file
+type accountsConfig struct {
+	Accounts []accountTarget `yaml:"accounts"`
+}
+
+// loadAccounts reads an --accounts file. Accounts without a name are named
+// after their profile; names must be unique because each account's reports
+// go to a directory of that name.
+func loadAccounts(path string) ([]accountTarget, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounts file: %w", err)
+	}
+
+	var config accountsConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse accounts file %s: %w", path, err)
+	}
+	if len(config.Accounts) == 0 {
+		return nil, fmt.Errorf("accounts file %s lists no accounts", path)
+	}
+
+	names := make(map[string]bool)
+	for i := range config.Accounts {
+		account := &config.Accounts[i]
+		if account.Name == "" {
+			account.Name = account.Profile
+		}
+		switch {
+		case account.Name == "":
+			return nil, fmt.Errorf("account %d in %s has neither a name nor a profile", i+1, path)
+		case account.Name == "." || account.Name == ".." || strings.ContainsAny(account.Name, `/\`):
+			return nil, fmt.Errorf("invalid account name %q in %s (it names the account's report directory)", account.Name, path)
+		case names[account.Name]:
+			return nil, fmt.Errorf("duplicate account name %q in %s", account.Name, path)
+		}
+		names[account.Name] = true
+
+		var buckets []string
+		for _, bucket := range account.Buckets {
+			if bucket = strings.TrimSpace(bucket); bucket != "" {
+				buckets = append(buckets, bucket)
+			}
+		}
+		account.Buckets = buckets
+	}
+
+	return config.Accounts, nil
+}
+
+// runAccounts profiles the accounts of an --accounts file concurrently,
+// each with its own client, and writes a combined roll-up. Reports of an
+// account go to a subdirectory of --output-dir named after it.
+func runAccounts(ctx context.Context, path string, discovery *awsclient.Discovery, audit io.Writer, header http.Header) error {
+	if bucketNames != "" || allBuckets || profile != "" || region != "" || roleARN != "" || externalID != "" || noSignRequest || openData {
+		return fmt.Errorf("--accounts names the profiles, roles, regions and buckets to profile; it cannot be combined with --buckets, --all, --profile, --region, --role-arn, --external-id, --no-sign-request or --open-data")
+	}
+
+	accounts, err := loadAccounts(path)
+	if err != nil {
+		return err
+	}
+
+	// The roll-up is written like the reports, so it honors encryption,
+	// redaction, the report language and --currency (--bucket-currency only
+	// applies to bucket reports)
+	encryption, err := output.ParseEncryption(encryptOutput)
+	if err != nil {
+		return err
+	}
+	language, err := output.ParseLanguage(lang)
+	if err != nil {
+		return err
+	}
+	reportCurrency, err := profiler.NewCurrency(currency, currencyRates)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fmt.Printf("Profiling %d account(s) concurrently...\n", len(accounts))
+
+	rollups := make([]types.AccountRollup, len(accounts))
+	violations := make([]int, len(accounts))
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rollups[i], violations[i] = profileAccount(ctx, account, discovery, audit, header)
+		}()
+	}
+	wg.Wait()
+	if dryRun {
+		return nil
+	}
+
+	writer := output.NewWriter(outputDir, output.Options{
+		Encryption: encryption,
+		Redact:     redact,
+		RedactSalt: redactSalt,
+		Language:   language,
+		Currency:   reportCurrency,
+	})
+	if err := writer.WriteAccountRollup(rollups); err != nil {
+		return fmt.Errorf("failed to write account roll-up: %w", err)
+	}
+	fmt.Printf("\nMulti-account roll-up: %s\n", filepath.Join(outputDir, writer.AccountRollupName()))
+
+	if ctx.Err() != nil {
+		return ErrInterrupted
+	}
+	total := 0
+	for _, n := range violations {
+		total += n
+	}
+	return checkFailOn(total)
+}
+
+// profileAccount profiles the buckets of one account and returns its
+// roll-up and policy violation count. Failures are recorded in the
+// roll-up so the other accounts still complete. Accounts that name no
+// buckets are listed with discovery when it is set.
+func profileAccount(ctx context.Context, account accountTarget, discovery *awsclient.Discovery, audit io.Writer, header http.Header) (types.AccountRollup, int) {
+	rollup := types.AccountRollup{
+		Name:    account.Name,
+		Profile: account.Profile,
+		Region:  account.Region,
+	}
+	progress := newAccountReporter(os.Stdout, account.Name)
+	fail := func(format string, args ...any) (types.AccountRollup, int) {
+		rollup.Error = fmt.Sprintf(format, args...)
+		progress.Printf("ERROR: %s\n", rollup.Error)
+		return rollup, 0
+	}
+
+	opts := clientOptions(account.Profile, account.Region, audit, header)
+	opts.RoleARN, opts.ExternalID = account.RoleARN, account.ExternalID
+	client, err := awsclient.NewClient(ctx, opts)
+	if err != nil {
+		return fail("failed to create AWS client: %v", err)
+	}
+
+	buckets := account.Buckets
+	getRegion := client.GetBucketRegion
+	if len(buckets) == 0 {
+		progress.Printf("Listing all accessible buckets...\n")
+		if buckets, getRegion, err = listBuckets(ctx, client, discovery, progress); err != nil {
+			return fail("%v", err)
+		}
+		progress.Printf("Found %d bucket(s)\n", len(buckets))
+	}
+	if len(buckets) == 0 {
+		return rollup, 0
+	}
+
+	dir := filepath.Join(outputDir, account.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fail("failed to create output directory: %v", err)
+	}
+
+	p, closeProfiler, err := newProfiler(ctx, client, profileConfig(buckets, account.Profile, account.Region, dir))
+	if err != nil {
+		return fail("%v", err)
+	}
+	defer closeProfiler()
+	p.SetProgressReporter(progress)
+
+	p.PlanRequests(ctx, buckets, getRegion)
+	if dryRun {
+		return rollup, 0
+	}
+
+	// Interrupted accounts keep the buckets with partial reports
+	profileErr := p.ProfileMultipleBuckets(ctx, buckets, getRegion)
+	if profileErr != nil && !errors.Is(profileErr, ErrInterrupted) {
+		return fail("%v", profileErr)
+	}
+
+	if err := p.WriteWorkbook(); err != nil {
+		progress.Warnf("%v", err)
+	}
+
+	rollup.Buckets = p.Profiled()
+	profiled := make(map[string]bool, len(rollup.Buckets))
+	for _, snapshot := range rollup.Buckets {
+		profiled[snapshot.Bucket] = true
+	}
+	for _, bucket := range buckets {
+		if !profiled[bucket] {
+			rollup.Failed = append(rollup.Failed, bucket)
+		}
+	}
+	return rollup, p.PolicyViolations()
+}
+
+// accountReporter prefixes every progress line with the account name, so
+// the output of accounts profiled concurrently can be told apart
+type accountReporter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	prefix    string
+	lineStart bool
+}
+
+func newAccountReporter(w io.Writer, name string) *accountReporter {
+	return &accountReporter{w: w, prefix: "[" + name + "] ", lineStart: true}
+}
+
+func (r *accountReporter) Printf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(fmt.Sprintf(format, args...), "\n") {
+		if line == "" {
+			continue
+		}
+		if r.lineStart && line != "\n" {
+			b.WriteString(r.prefix)
+		}
+		b.WriteString(line)
+		r.lineStart = strings.HasSuffix(line, "\n")
+	}
+	io.WriteString(r.w, b.String())
+}
+
+func (r *accountReporter) Warnf(format string, args ...any) {
+	r.Printf("Warning: "+format+"\n", args...)
+}
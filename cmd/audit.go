@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	awsclient "github.com/yourusername/s3-profiler/aws"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/profiler"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+var (
+	auditBuckets    string
+	auditProfile    string
+	auditRegion     string
+	auditRoleARN    string
+	auditExternalID string
+	auditMFASerial  string
+	auditExitCode   bool
+)
+
+// auditCmd reports the security posture of buckets
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check buckets for public access, encryption, versioning, logging and ownership settings",
+	Long: `audit checks each bucket's security posture and prints a pass/fail report:
+
+  - Block Public Access: all four bucket-level settings are on
+  - Bucket Policy: the policy does not make the bucket public
+  - Default Encryption: default server-side encryption is configured
+  - Versioning: versioning is enabled
+  - MFA Delete: MFA delete is enabled
+  - Access Logging: server access logging is enabled
+  - Object Ownership: BucketOwnerEnforced, i.e. ACLs are disabled
+
+Only bucket configuration is read; no objects are listed or read. A check
+that cannot be run, usually because of a missing permission, is reported
+as ERROR. Without --buckets every accessible bucket is audited.
+
+With --exit-code the command exits with status 2 when any check fails.`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().StringVarP(&auditBuckets, "buckets", "b", "", "Comma-separated list of bucket names to audit (default: all accessible buckets)")
+	auditCmd.Flags().StringVarP(&auditProfile, "profile", "p", "", "AWS profile name to use")
+	auditCmd.Flags().StringVarP(&auditRegion, "region", "r", "", "AWS region (defaults to bucket region)")
+	auditCmd.Flags().StringVar(&auditRoleARN, "role-arn", "", "Assume this IAM role with the loaded credentials, e.g. to audit the buckets of another account")
+	auditCmd.Flags().StringVar(&auditExternalID, "external-id", "", "External ID passed when assuming --role-arn")
+	auditCmd.Flags().StringVar(&auditMFASerial, "mfa-serial", "", "Serial number or ARN of the MFA device required to assume the role; the token code is read from stdin")
+	auditCmd.Flags().BoolVar(&auditExitCode, "exit-code", false, "Exit with status 2 when any check fails")
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := awsclient.NewClient(ctx, awsclient.ClientOptions{
+		Profile:    auditProfile,
+		Region:     auditRegion,
+		RoleARN:    auditRoleARN,
+		ExternalID: auditExternalID,
+		MFASerial:  auditMFASerial,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create AWS client: %w", err)
+	}
+
+	var buckets []string
+	if auditBuckets != "" {
+		for _, name := range strings.Split(auditBuckets, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				buckets = append(buckets, name)
+			}
+		}
+	} else {
+		buckets, err = profiler.ListAllBuckets(ctx, client.S3)
+		if err != nil {
+			return fmt.Errorf("failed to list buckets: %w", err)
+		}
+	}
+	if len(buckets) == 0 {
+		fmt.Println("No buckets to audit.")
+		return nil
+	}
+
+	auditor := profiler.NewSecurityAuditor(client.S3)
+	audits := make([]types.BucketAudit, 0, len(buckets))
+	failed := 0
+	for i, bucketName := range buckets {
+		// Progress goes to stderr so stdout is the report alone
+		fmt.Fprintf(os.Stderr, "[%d/%d] Auditing %s\n", i+1, len(buckets), bucketName)
+
+		bucketRegion, err := client.GetBucketRegion(ctx, bucketName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to get region for bucket %s: %v\n", bucketName, err)
+			bucketRegion = ""
+		}
+		audit := auditor.AuditBucket(ctx, bucketName, bucketRegion)
+		for _, check := range audit.Checks {
+			if check.Status == types.AuditFail {
+				failed++
+			}
+		}
+		audits = append(audits, audit)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	fmt.Print(output.FormatAudit(audits))
+
+	if auditExitCode && failed > 0 {
+		return fmt.Errorf("%d audit check(s) failed: %w", failed, ErrFailOn)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/profiler"
+	"github.com/yourusername/s3-profiler/store"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+var (
+	compareTop       int
+	compareTimezone  string
+	compareResultsDB string
+)
+
+// compareCmd compares two runs of the same bucket
+var compareCmd = &cobra.Command{
+	Use:   "compare <old-snapshot.json> <new-snapshot.json>",
+	Short: "Attribute bucket growth between two runs to prefixes and partitions",
+	Long: `compare reads the bucket-name-snapshot.json files written by two profiling
+runs and reports the bucket-level change together with the prefixes and
+partitions that contributed most to added and removed bytes.
+
+With --results-db the runs are read from the results database instead: pass
+two run ids (see the history command), or a bucket name to compare its two
+most recent runs.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().IntVar(&compareTop, "top", 10, "Number of contributors listed per section")
+	compareCmd.Flags().StringVar(&compareTimezone, "timezone", "UTC", "Time zone for run timestamps (IANA name such as Europe/Berlin, or Local)")
+	compareCmd.Flags().StringVar(&compareResultsDB, "results-db", os.Getenv("S3_PROFILER_RESULTS_DB"), "Read runs from this PostgreSQL results database")
+	rootCmd.AddCommand(compareCmd)
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	location, err := time.LoadLocation(compareTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", compareTimezone, err)
+	}
+
+	var old, current *types.Snapshot
+	if compareResultsDB != "" {
+		old, current, err = loadStoredRuns(context.Background(), args)
+	} else {
+		if len(args) != 2 {
+			return fmt.Errorf("compare needs two snapshot files (or --results-db)")
+		}
+		if old, err = profiler.LoadSnapshot(args[0]); err == nil {
+			current, err = profiler.LoadSnapshot(args[1])
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if old.Bucket != current.Bucket {
+		fmt.Printf("Warning: comparing different buckets (%s and %s)\n\n", old.Bucket, current.Bucket)
+	}
+
+	fmt.Print(output.FormatComparison(profiler.CompareSnapshots(old, current), compareTop, location))
+	return nil
+}
+
+// loadStoredRuns reads two runs by id, or the latest two runs of a bucket
+func loadStoredRuns(ctx context.Context, args []string) (*types.Snapshot, *types.Snapshot, error) {
+	db, err := store.Open(ctx, compareResultsDB)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer db.Close()
+
+	var ids [2]int64
+	if len(args) == 2 {
+		for i, arg := range args {
+			if ids[i], err = strconv.ParseInt(arg, 10, 64); err != nil {
+				return nil, nil, fmt.Errorf("invalid run id %q", arg)
+			}
+		}
+	} else {
+		runs, err := db.Runs(ctx, args[0], 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(runs) < 2 {
+			return nil, nil, fmt.Errorf("bucket %s has %d stored run(s); at least 2 are needed", args[0], len(runs))
+		}
+		ids = [2]int64{runs[1].ID, runs[0].ID}
+	}
+
+	old, err := db.LoadRun(ctx, ids[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err := db.LoadRun(ctx, ids[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return old, current, nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// applyConfigFile sets the flags named in a --config YAML file. Keys are
+// long flag names; flags given on the command line keep their value.
+// Repeatable flags take a list, set once per item, or a map, set once per
+// "key=value" pair, so
+//
+//	storage-price:
+//	  STANDARD: 0.0245
+//
+// is --storage-price STANDARD=0.0245.
+func applyConfigFile(flags *pflag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s must map flag names to values", path)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		name := key.Value
+		flag := flags.Lookup(name)
+		if flag == nil || name == "config" || name == "help" {
+			return fmt.Errorf("%s:%d: unknown option %q (keys are long flag names, e.g. max-workers)", path, key.Line, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%s:%d: %s is set twice", path, key.Line, name)
+		}
+		seen[name] = true
+		if flag.Changed {
+			continue
+		}
+
+		values, err := configValues(value)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s: %w", path, value.Line, name, err)
+		}
+		if len(values) > 1 && !repeatable(flag) {
+			return fmt.Errorf("%s:%d: %s takes a single value", path, value.Line, name)
+		}
+		for _, v := range values {
+			if err := flags.Set(name, v); err != nil {
+				return fmt.Errorf("%s:%d: invalid %s: %w", path, value.Line, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// configValues returns the flag values of a config file entry
+func configValues(node *yaml.Node) ([]string, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil, fmt.Errorf("no value")
+		}
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("list items must be plain values")
+			}
+			values = append(values, item.Value)
+		}
+		return values, nil
+	case yaml.MappingNode:
+		values := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if k.Kind != yaml.ScalarNode || v.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("map entries must be plain values")
+			}
+			values = append(values, k.Value+"="+v.Value)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported value")
+}
+
+// repeatable reports whether a flag accepts several values
+func repeatable(flag *pflag.Flag) bool {
+	kind := flag.Value.Type()
+	return strings.HasSuffix(kind, "Slice") || strings.HasSuffix(kind, "Array")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/s3-profiler/output"
+)
+
+var (
+	dashboardDatasource string
+	dashboardOutput     string
+)
+
+// dashboardCmd exports a Grafana dashboard for the results database
+var dashboardCmd = &cobra.Command{
+	Use:   "export-dashboard",
+	Short: "Write a Grafana dashboard over the results database views",
+	Long: `export-dashboard writes Grafana dashboard JSON whose panels query the
+bucket_growth, cost_by_class and partition_freshness views that --results-db
+creates in PostgreSQL. Without --datasource-uid, Grafana asks for the
+PostgreSQL datasource when the dashboard is imported.`,
+	Args: cobra.NoArgs,
+	RunE: runExportDashboard,
+}
+
+func init() {
+	dashboardCmd.Flags().StringVar(&dashboardDatasource, "datasource-uid", "", "UID of the Grafana PostgreSQL datasource to bind the panels to")
+	dashboardCmd.Flags().StringVarP(&dashboardOutput, "output", "o", "", "File to write (default stdout)")
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+func runExportDashboard(cmd *cobra.Command, args []string) error {
+	dashboard, err := output.GrafanaDashboard(dashboardDatasource)
+	if err != nil {
+		return fmt.Errorf("failed to build dashboard: %w", err)
+	}
+	dashboard = append(dashboard, '\n')
+
+	if dashboardOutput == "" {
+		_, err = os.Stdout.Write(dashboard)
+		return err
+	}
+	if err := os.WriteFile(dashboardOutput, dashboard, 0644); err != nil {
+		return fmt.Errorf("failed to write dashboard: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", dashboardOutput)
+	return nil
+}
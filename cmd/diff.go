@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/profiler"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+var (
+	diffTop      int
+	diffTimezone string
+)
+
+// diffCmd compares the bucket summaries of two profiling runs
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-report> <new-report>",
+	Short: "Compare the bucket summaries of two profiling runs",
+	Long: `diff compares the bucket summaries recorded by two profiling runs: object
+count, total size, estimated cost, size per storage class, and the partitions
+that appeared or disappeared.
+
+Pass two bucket-name-snapshot.json files, or the output directories of two
+runs to compare every bucket found in both. Use compare to attribute one
+bucket's growth to prefixes.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().IntVar(&diffTop, "top", 10, "Number of new and removed partitions listed per bucket")
+	diffCmd.Flags().StringVar(&diffTimezone, "timezone", "UTC", "Time zone for run timestamps (IANA name such as Europe/Berlin, or Local)")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	location, err := time.LoadLocation(diffTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", diffTimezone, err)
+	}
+
+	old, err := loadSnapshots(args[0])
+	if err != nil {
+		return err
+	}
+	current, err := loadSnapshots(args[1])
+	if err != nil {
+		return err
+	}
+
+	// A single snapshot on each side is compared whatever its bucket, so
+	// renamed or redacted buckets can still be diffed
+	if len(old) == 1 && len(current) == 1 {
+		for _, o := range old {
+			for _, c := range current {
+				if o.Bucket != c.Bucket {
+					fmt.Printf("Warning: comparing different buckets (%s and %s)\n\n", o.Bucket, c.Bucket)
+				}
+				fmt.Print(output.FormatDiff([]*types.SnapshotDiff{profiler.DiffSnapshots(o, c)}, nil, nil, diffTop, location))
+			}
+		}
+		return nil
+	}
+
+	var diffs []*types.SnapshotDiff
+	var removed, added []string
+	for bucket, o := range old {
+		if c, ok := current[bucket]; ok {
+			diffs = append(diffs, profiler.DiffSnapshots(o, c))
+		} else {
+			removed = append(removed, bucket)
+		}
+	}
+	for bucket := range current {
+		if _, ok := old[bucket]; !ok {
+			added = append(added, bucket)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Bucket < diffs[j].Bucket })
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	fmt.Print(output.FormatDiff(diffs, removed, added, diffTop, location))
+	return nil
+}
+
+// loadSnapshots reads a snapshot file, or every snapshot in a run's output
+// directory, by bucket
+func loadSnapshots(path string) (map[string]*types.Snapshot, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*-snapshot.json")); err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no bucket-name-snapshot.json files in %s", path)
+		}
+	}
+
+	snapshots := make(map[string]*types.Snapshot, len(files))
+	for _, file := range files {
+		snapshot, err := profiler.LoadSnapshot(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := snapshots[snapshot.Bucket]; ok {
+			return nil, fmt.Errorf("%s holds more than one snapshot of bucket %s", path, snapshot.Bucket)
+		}
+		snapshots[snapshot.Bucket] = snapshot
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	awsclient "github.com/yourusername/s3-profiler/aws"
+	"github.com/yourusername/s3-profiler/profiler"
+)
+
+// listBuckets returns the buckets to profile when none are named: those
+// ListBuckets returns or, with --discover, every bucket the inventory
+// service knows across its accounts and regions. The returned lookup uses
+// the discovered regions before asking S3.
+func listBuckets(ctx context.Context, client *awsclient.Client, discovery *awsclient.Discovery, progress profiler.ProgressReporter) ([]string, func(context.Context, string) (string, error), error) {
+	if discovery == nil {
+		buckets, err := profiler.ListAllBuckets(ctx, client.S3)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list buckets: %w", err)
+		}
+		return buckets, client.GetBucketRegion, nil
+	}
+
+	discovered, err := client.DiscoverBuckets(ctx, discovery)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buckets := make([]string, len(discovered))
+	regions := make(map[string]string, len(discovered))
+	accounts := make(map[string]bool)
+	regionSet := make(map[string]bool)
+	for i, bucket := range discovered {
+		buckets[i] = bucket.Name
+		regions[bucket.Name] = bucket.Region
+		if bucket.Account != "" {
+			accounts[bucket.Account] = true
+		}
+		if bucket.Region != "" {
+			regionSet[bucket.Region] = true
+		}
+	}
+	progress.Printf("Discovered %d bucket(s) in %d account(s) and %d region(s) with %s\n",
+		len(buckets), len(accounts), len(regionSet), discovery)
+
+	getRegion := func(ctx context.Context, bucket string) (string, error) {
+		if region := regions[bucket]; region != "" {
+			return region, nil
+		}
+		return client.GetBucketRegion(ctx, bucket)
+	}
+	return buckets, getRegion, nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/s3-profiler/explore"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/profiler"
+	"github.com/yourusername/s3-profiler/store"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+var (
+	exploreManifestDir string
+	exploreResultsDB   string
+)
+
+// exploreCmd browses the prefix tree of a stored inventory
+var exploreCmd = &cobra.Command{
+	Use:   "explore <inventory>",
+	Short: "Browse the prefix tree of a stored inventory, ncdu-style",
+	Long: `explore opens an interactive, ncdu-like view of a bucket's prefix tree with
+the size, object count and age (time since the last write) of every prefix.
+
+The inventory is an object export written with --export-objects (gzip or
+zstd compressed ones too), or a bucket-name-snapshot.json. Snapshots only
+record prefixes a few levels deep and no timestamps, so they show no ages.
+With --results-db, pass a run id (see the history command) or a bucket
+name for its latest run.
+
+Mark prefixes with space and press w to write bucket-name-remediation-prefixes.csv
+to --manifest-dir. From an object export, every key under the marked
+prefixes is also written to bucket-name-remediation-manifest.csv in the
+S3 Batch Operations manifest format.
+
+Keys: up/down or j/k move, right/enter opens a prefix, left/backspace goes
+up, s cycles the sort order (size, count, age, name), q quits.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplore,
+}
+
+func init() {
+	exploreCmd.Flags().StringVar(&exploreManifestDir, "manifest-dir", ".", "Directory the remediation manifest is written to")
+	exploreCmd.Flags().StringVar(&exploreResultsDB, "results-db", os.Getenv("S3_PROFILER_RESULTS_DB"), "Read the run from this PostgreSQL results database")
+	rootCmd.AddCommand(exploreCmd)
+}
+
+func runExplore(cmd *cobra.Command, args []string) error {
+	var tree *types.PrefixNode
+	var objects []types.ObjectMetadata
+
+	switch {
+	case exploreResultsDB != "":
+		snapshot, err := loadStoredRun(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		tree = profiler.PrefixTreeFromSnapshot(snapshot)
+
+	case strings.HasSuffix(args[0], ".json"):
+		snapshot, err := profiler.LoadSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		tree = profiler.PrefixTreeFromSnapshot(snapshot)
+
+	default:
+		inventory, err := output.OpenObjectInventory(args[0])
+		if err != nil {
+			return err
+		}
+		objects, err = inventory.ReadAll()
+		inventory.Close()
+		if err != nil {
+			return err
+		}
+		if objects == nil {
+			objects = []types.ObjectMetadata{}
+		}
+		tree = profiler.BuildPrefixTree(inventory.Bucket, objects)
+	}
+
+	return explore.Run(explore.NewBrowser(tree, objects, args[0], exploreManifestDir))
+}
+
+// loadStoredRun reads a run by id, or the latest run of a bucket
+func loadStoredRun(ctx context.Context, arg string) (*types.Snapshot, error) {
+	db, err := store.Open(ctx, exploreResultsDB)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		runs, err := db.Runs(ctx, arg, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) == 0 {
+			return nil, fmt.Errorf("bucket %s has no stored runs", arg)
+		}
+		id = runs[0].ID
+	}
+	return db.LoadRun(ctx, id)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/store"
+)
+
+var (
+	historyLimit     int
+	historyTimezone  string
+	historyResultsDB string
+)
+
+// historyCmd lists the runs of a bucket stored in the results database
+var historyCmd = &cobra.Command{
+	Use:   "history <bucket>",
+	Short: "List a bucket's runs from the results database",
+	Long: `history lists the runs of a bucket recorded with --results-db, newest
+first, with the change since the run before. Pass two of the listed run ids
+to compare to attribute that change to prefixes and partitions.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Number of runs listed (0 = all)")
+	historyCmd.Flags().StringVar(&historyTimezone, "timezone", "UTC", "Time zone for run timestamps (IANA name such as Europe/Berlin, or Local)")
+	historyCmd.Flags().StringVar(&historyResultsDB, "results-db", os.Getenv("S3_PROFILER_RESULTS_DB"), "PostgreSQL results database to read runs from")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if historyResultsDB == "" {
+		return fmt.Errorf("history needs --results-db or S3_PROFILER_RESULTS_DB")
+	}
+	location, err := time.LoadLocation(historyTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", historyTimezone, err)
+	}
+
+	ctx := context.Background()
+	db, err := store.Open(ctx, historyResultsDB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	runs, err := db.Runs(ctx, args[0], historyLimit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output.FormatRunHistory(args[0], runs, location))
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/query"
+)
+
+var (
+	queryFrom   []string
+	queryDir    string
+	queryFormat string
+)
+
+// queryCmd runs SQL over exported object inventories
+var queryCmd = &cobra.Command{
+	Use:   "query <sql>",
+	Short: "Run SQL over object inventories exported with --export-objects",
+	Long: `query loads the bucket-name-objects.csv files written with --export-objects
+(gzip or zstd compressed ones too) into an in-memory SQLite database and
+runs a SQL statement over them. Every export is loaded into one table:
+
+  objects(bucket, key, prefix, extension, size, last_modified, storage_class, etag)
+
+prefix is the key's parent prefix ("/" for keys at the root), extension the
+lower-case file extension and last_modified an RFC 3339 UTC timestamp that
+SQLite's date functions accept. key REGEXP '...' matches Go regular
+expressions. For example:
+
+  s3-profiler query "SELECT prefix, sum(size) AS bytes FROM objects
+    GROUP BY prefix ORDER BY bytes DESC LIMIT 10"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	queryCmd.Flags().StringSliceVar(&queryFrom, "from", nil, "Export files to load (default: every export in --output-dir)")
+	queryCmd.Flags().StringVarP(&queryDir, "output-dir", "o", ".", "Directory holding the exports")
+	queryCmd.Flags().StringVar(&queryFormat, "format", "table", "Result format: table, csv or json")
+	rootCmd.AddCommand(queryCmd)
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseQueryFormat(queryFormat)
+	if err != nil {
+		return err
+	}
+
+	files := queryFrom
+	if len(files) == 0 {
+		if files, err = output.FindObjectInventories(queryDir); err != nil {
+			return fmt.Errorf("failed to find exports: %w", err)
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no object inventory exports in %s; profile with --export-objects or pass --from", queryDir)
+		}
+	}
+
+	ctx := context.Background()
+	engine, err := query.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	for _, file := range files {
+		n, err := engine.Load(ctx, file)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Loaded %d objects from %s\n", n, file)
+	}
+
+	result, err := engine.Query(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	return output.WriteQueryResult(os.Stdout, format, result.Columns, result.Rows)
+}
@@ -0,0 +1,439 @@
+// Package explore is an ncdu-like terminal browser for the prefix tree of a
+// stored inventory, with prefixes marked for a remediation manifest
+package explore
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// SortOrder selects how the children of a prefix are listed
+type SortOrder int
+
+const (
+	SortBySize SortOrder = iota
+	SortByCount
+	// SortByAge lists the prefixes written to least recently first
+	SortByAge
+	SortByName
+)
+
+var sortNames = []string{"size", "count", "age", "name"}
+
+func (s SortOrder) String() string {
+	return sortNames[s]
+}
+
+// frame is one level of the navigation stack
+type frame struct {
+	node     *types.PrefixNode
+	children []*types.PrefixNode
+	selected int
+	offset   int
+}
+
+// Browser navigates a prefix tree. Objects are optional; with them, the
+// remediation manifest lists every key under the marked prefixes.
+type Browser struct {
+	root        *types.PrefixNode
+	objects     []types.ObjectMetadata
+	source      string
+	manifestDir string
+	now         time.Time
+
+	stack   []*frame
+	order   SortOrder
+	parents map[*types.PrefixNode]*types.PrefixNode
+	marked  map[*types.PrefixNode]bool
+	// dirty is set when marks changed since the manifest was written
+	dirty   bool
+	message string
+}
+
+// NewBrowser creates a browser positioned at the bucket root. source names
+// the inventory in the title; manifests are written to manifestDir.
+func NewBrowser(root *types.PrefixNode, objects []types.ObjectMetadata, source, manifestDir string) *Browser {
+	b := &Browser{
+		root:        root,
+		objects:     objects,
+		source:      source,
+		manifestDir: manifestDir,
+		now:         time.Now(),
+		parents:     make(map[*types.PrefixNode]*types.PrefixNode),
+		marked:      make(map[*types.PrefixNode]bool),
+	}
+	var index func(node *types.PrefixNode)
+	index = func(node *types.PrefixNode) {
+		for _, child := range node.Children {
+			b.parents[child] = node
+			index(child)
+		}
+	}
+	index(root)
+	b.push(root)
+	return b
+}
+
+// push descends into a node
+func (b *Browser) push(node *types.PrefixNode) {
+	f := &frame{node: node}
+	f.children = b.sorted(node)
+	b.stack = append(b.stack, f)
+}
+
+// current returns the frame being shown
+func (b *Browser) current() *frame {
+	return b.stack[len(b.stack)-1]
+}
+
+// sorted returns a node's children in the current sort order
+func (b *Browser) sorted(node *types.PrefixNode) []*types.PrefixNode {
+	children := append([]*types.PrefixNode(nil), node.Children...)
+	sort.SliceStable(children, func(i, j int) bool {
+		x, y := children[i], children[j]
+		switch b.order {
+		case SortByCount:
+			if x.ObjectCount != y.ObjectCount {
+				return x.ObjectCount > y.ObjectCount
+			}
+		case SortByAge:
+			if !x.Newest.Equal(y.Newest) {
+				return x.Newest.Before(y.Newest)
+			}
+		case SortByName:
+			return x.Name < y.Name
+		}
+		if x.Size != y.Size {
+			return x.Size > y.Size
+		}
+		return x.Name < y.Name
+	})
+	return children
+}
+
+// selectedNode returns the highlighted child, or nil for an empty prefix
+func (b *Browser) selectedNode() *types.PrefixNode {
+	f := b.current()
+	if len(f.children) == 0 {
+		return nil
+	}
+	return f.children[f.selected]
+}
+
+// Move moves the selection by delta rows, clamped to the list
+func (b *Browser) Move(delta int) {
+	f := b.current()
+	f.selected = max(0, min(len(f.children)-1, f.selected+delta))
+}
+
+// Open descends into the selected prefix
+func (b *Browser) Open() {
+	node := b.selectedNode()
+	if node == nil {
+		return
+	}
+	if len(node.Children) == 0 {
+		b.message = fmt.Sprintf("%s has no sub-prefixes", output.EscapeKey(b.Prefix(node)))
+		return
+	}
+	b.push(node)
+}
+
+// Up returns to the parent prefix with the prefix just left selected
+func (b *Browser) Up() {
+	if len(b.stack) == 1 {
+		return
+	}
+	left := b.current().node
+	b.stack = b.stack[:len(b.stack)-1]
+	f := b.current()
+	f.children = b.sorted(f.node)
+	for i, child := range f.children {
+		if child == left {
+			f.selected = i
+		}
+	}
+}
+
+// ToggleMark marks or unmarks the selected prefix
+func (b *Browser) ToggleMark() {
+	node := b.selectedNode()
+	if node == nil {
+		return
+	}
+	if b.marked[node] {
+		delete(b.marked, node)
+	} else {
+		b.marked[node] = true
+	}
+	b.dirty = true
+	b.Move(1)
+}
+
+// CycleSort switches to the next sort order, keeping the selection
+func (b *Browser) CycleSort() {
+	b.order = (b.order + 1) % SortOrder(len(sortNames))
+	selected := b.selectedNode()
+	f := b.current()
+	f.children = b.sorted(f.node)
+	for i, child := range f.children {
+		if child == selected {
+			f.selected = i
+		}
+	}
+	b.message = "Sorted by " + b.order.String()
+}
+
+// Prefix returns the key prefix of a node as shown, "/" for the bucket root
+func (b *Browser) Prefix(node *types.PrefixNode) string {
+	if node == b.root {
+		return "/"
+	}
+	return b.keyPrefix(node)
+}
+
+// keyPrefix returns the key prefix of a node, "" for the bucket root
+func (b *Browser) keyPrefix(node *types.PrefixNode) string {
+	var names []string
+	for n := node; n != b.root; n = b.parents[n] {
+		names = append(names, n.Name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	slices.Reverse(names)
+	return strings.Join(names, "/") + "/"
+}
+
+// coveredByMark reports whether an ancestor of node is marked, so the node
+// is already part of the manifest
+func (b *Browser) coveredByMark(node *types.PrefixNode) bool {
+	for n := b.parents[node]; n != nil; n = b.parents[n] {
+		if b.marked[n] {
+			return true
+		}
+	}
+	return false
+}
+
+// Marked returns the marked prefixes that are not inside another marked
+// prefix, in key order
+func (b *Browser) Marked() []*types.PrefixNode {
+	var nodes []*types.PrefixNode
+	for node := range b.marked {
+		if !b.coveredByMark(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return b.Prefix(nodes[i]) < b.Prefix(nodes[j])
+	})
+	return nodes
+}
+
+// WriteManifest writes the marked prefixes to bucket-remediation-prefixes.csv
+// and, when the browser has the objects, every key under them to
+// bucket-remediation-manifest.csv in the S3 Batch Operations format. It
+// returns the paths written.
+func (b *Browser) WriteManifest() ([]string, error) {
+	marked := b.Marked()
+	if len(marked) == 0 {
+		return nil, fmt.Errorf("no prefixes are marked")
+	}
+	if err := os.MkdirAll(b.manifestDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	var records [][]string
+	records = append(records, []string{"bucket", "prefix", "objects", "size", "oldest", "newest"})
+	for _, node := range marked {
+		records = append(records, []string{
+			b.root.Name,
+			b.Prefix(node),
+			fmt.Sprint(node.ObjectCount),
+			fmt.Sprint(node.Size),
+			formatTime(node.Oldest),
+			formatTime(node.Newest),
+		})
+	}
+	prefixesPath := filepath.Join(b.manifestDir, b.root.Name+"-remediation-prefixes.csv")
+	if err := writeCSV(prefixesPath, records); err != nil {
+		return nil, err
+	}
+	paths := []string{prefixesPath}
+
+	if b.objects != nil {
+		prefixes := make([]string, len(marked))
+		for i, node := range marked {
+			prefixes[i] = b.keyPrefix(node)
+		}
+		var keys [][]string
+		for _, obj := range b.objects {
+			if slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(obj.Key, prefix) }) {
+				// Keys are URL-encoded like S3 Batch Operations manifests
+				keys = append(keys, []string{b.root.Name, url.QueryEscape(obj.Key)})
+			}
+		}
+		manifestPath := filepath.Join(b.manifestDir, b.root.Name+"-remediation-manifest.csv")
+		if err := writeCSV(manifestPath, keys); err != nil {
+			return nil, err
+		}
+		paths = append(paths, manifestPath)
+	}
+
+	b.dirty = false
+	return paths, nil
+}
+
+// writeCSV writes records to a file
+func writeCSV(path string, records [][]string) error {
+	var sb strings.Builder
+	cw := csv.NewWriter(&sb)
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// formatTime renders a timestamp for the manifest, "" when unknown
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatAge renders the time since a prefix was last written
+func (b *Browser) formatAge(newest time.Time) string {
+	if newest.IsZero() {
+		return "-"
+	}
+	days := int(b.now.Sub(newest).Hours() / 24)
+	switch {
+	case days < 1:
+		return "<1d"
+	case days < 365:
+		return fmt.Sprintf("%dd", days)
+	}
+	return fmt.Sprintf("%.1fy", float64(days)/365)
+}
+
+// Render lays out the browser for a terminal of the given size, one string
+// per line
+func (b *Browser) Render(width, height int) []string {
+	f := b.current()
+	title := fmt.Sprintf("--- %s ", output.EscapeKey(b.Prefix(f.node)))
+	if f.node.SelfCount > 0 {
+		title += fmt.Sprintf("(%s object(s) directly, %s) ", output.FormatNumber(f.node.SelfCount), output.FormatBytes(f.node.SelfSize))
+	}
+	lines := []string{
+		reverse(pad(fmt.Sprintf(" s3-profiler explore  %s  (%s)", b.root.Name, b.source), width)),
+		pad(title, width, '-'),
+	}
+
+	// Two header lines and four footer lines
+	rows := max(1, height-6)
+	if f.selected < f.offset {
+		f.offset = f.selected
+	}
+	if f.selected >= f.offset+rows {
+		f.offset = f.selected - rows + 1
+	}
+
+	for i := f.offset; i < f.offset+rows; i++ {
+		if i >= len(f.children) {
+			lines = append(lines, "")
+			continue
+		}
+		child := f.children[i]
+		mark := " "
+		if b.marked[child] {
+			mark = "*"
+		} else if b.coveredByMark(child) {
+			mark = "+"
+		}
+		share := 0.0
+		if f.node.Size > 0 {
+			share = float64(child.Size) / float64(f.node.Size)
+		}
+		bar := strings.Repeat("#", int(share*10+0.5))
+		name := output.EscapeKey(child.Name) + "/"
+		line := fmt.Sprintf("%s %10s %5.1f%% [%-10s] %12s %6s  %s",
+			mark, output.FormatBytes(child.Size), share*100, bar,
+			output.FormatNumber(child.ObjectCount), b.formatAge(child.Newest), name)
+		line = pad(line, width)
+		if i == f.selected {
+			line = reverse(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(f.children) == 0 {
+		lines[2] = pad("  (no sub-prefixes)", width)
+	}
+
+	lines = append(lines, pad("", width, '-'))
+	lines = append(lines, pad(b.details(), width))
+
+	var markedSize int64
+	marked := b.Marked()
+	for _, node := range marked {
+		markedSize += node.Size
+	}
+	status := fmt.Sprintf(" Total %s in %s objects   Marked %d (%s)   Sort: %s",
+		output.FormatBytes(f.node.Size), output.FormatNumber(f.node.ObjectCount),
+		len(marked), output.FormatBytes(markedSize), b.order)
+	if b.message != "" {
+		status = " " + b.message
+		b.message = ""
+	}
+	lines = append(lines, reverse(pad(status, width)))
+	lines = append(lines, pad(" ↑↓ move  → open  ← up  space mark  s sort  w write manifest  q quit", width))
+	return lines
+}
+
+// details describes the selected prefix
+func (b *Browser) details() string {
+	node := b.selectedNode()
+	if node == nil {
+		node = b.current().node
+	}
+	text := " " + output.EscapeKey(b.Prefix(node))
+	if !node.Oldest.IsZero() {
+		text += fmt.Sprintf("  written %s .. %s", node.Oldest.Format("2006-01-02"), node.Newest.Format("2006-01-02"))
+	}
+	if node.SelfCount > 0 {
+		text += fmt.Sprintf("  %s object(s) directly (%s)", output.FormatNumber(node.SelfCount), output.FormatBytes(node.SelfSize))
+	}
+	return text
+}
+
+// pad cuts or fills a line to exactly width cells
+func pad(s string, width int, fill ...rune) string {
+	r := []rune(s)
+	if len(r) > width {
+		return string(r[:width])
+	}
+	c := ' '
+	if len(fill) > 0 {
+		c = fill[0]
+	}
+	return s + strings.Repeat(string(c), width-len(r))
+}
+
+// reverse shows a line in reverse video
+func reverse(s string) string {
+	return "\x1b[7m" + s + "\x1b[0m"
+}
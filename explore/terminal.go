@@ -0,0 +1,146 @@
+package explore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// key is a decoded keypress
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyOpen
+	keyBack
+	keyMark
+	keySort
+	keyWrite
+	keyQuit
+)
+
+// decodeKey maps the bytes of one terminal read to a key. Arrow and paging
+// keys arrive as ANSI escape sequences.
+func decodeKey(input []byte) key {
+	switch string(input) {
+	case "\x1b[A", "\x1bOA", "k":
+		return keyUp
+	case "\x1b[B", "\x1bOB", "j":
+		return keyDown
+	case "\x1b[5~":
+		return keyPageUp
+	case "\x1b[6~":
+		return keyPageDown
+	case "\x1b[H", "\x1b[1~", "g":
+		return keyHome
+	case "\x1b[F", "\x1b[4~", "G":
+		return keyEnd
+	case "\x1b[C", "\x1bOC", "\r", "\n", "l":
+		return keyOpen
+	case "\x1b[D", "\x1bOD", "\x7f", "\b", "h":
+		return keyBack
+	case " ":
+		return keyMark
+	case "s":
+		return keySort
+	case "w":
+		return keyWrite
+	case "q", "\x03":
+		return keyQuit
+	}
+	return keyNone
+}
+
+// Run shows the browser on the terminal until the user quits. Quitting with
+// marks that were not written asks for confirmation first.
+func Run(b *Browser) error {
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return errors.New("explore needs an interactive terminal")
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(in, state)
+
+	w := bufio.NewWriter(os.Stdout)
+	// Alternate screen with a hidden cursor, restored on exit
+	fmt.Fprint(w, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(w, "\x1b[?25h\x1b[?1049l")
+		w.Flush()
+	}()
+
+	buf := make([]byte, 16)
+	confirmQuit := false
+	for {
+		width, height, err := term.GetSize(out)
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		rows := max(1, height-6)
+
+		fmt.Fprint(w, "\x1b[H")
+		fmt.Fprint(w, strings.Join(b.Render(width, height), "\r\n"))
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+
+		k := decodeKey(buf[:n])
+		if k != keyQuit {
+			confirmQuit = false
+		}
+		switch k {
+		case keyUp:
+			b.Move(-1)
+		case keyDown:
+			b.Move(1)
+		case keyPageUp:
+			b.Move(-rows)
+		case keyPageDown:
+			b.Move(rows)
+		case keyHome:
+			b.Move(-len(b.current().children))
+		case keyEnd:
+			b.Move(len(b.current().children))
+		case keyOpen:
+			b.Open()
+		case keyBack:
+			b.Up()
+		case keyMark:
+			b.ToggleMark()
+		case keySort:
+			b.CycleSort()
+		case keyWrite:
+			paths, err := b.WriteManifest()
+			if err != nil {
+				b.message = err.Error()
+			} else {
+				b.message = "Wrote " + strings.Join(paths, ", ")
+			}
+		case keyQuit:
+			if !b.dirty || confirmQuit {
+				return nil
+			}
+			confirmQuit = true
+			b.message = "Marks were not written; press w to write them or q again to quit"
+		}
+	}
+}
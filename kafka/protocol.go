@@ -0,0 +1,196 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// Kafka API keys and the versions this client speaks
+const (
+	apiProduce       = 0
+	apiMetadata      = 3
+	produceVersion   = 3
+	metadataVersion  = 1
+	recordBatchMagic = 2
+)
+
+var errTruncated = errors.New("kafka: truncated response")
+
+// castagnoli is the CRC-32C table used by record batch checksums
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// encoder appends Kafka protocol primitives to a buffer
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int8(v int8)   { e.buf = append(e.buf, byte(v)) }
+func (e *encoder) int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *encoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *encoder) int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+
+// varint appends a zigzag-encoded variable length integer
+func (e *encoder) varint(v int64) { e.buf = binary.AppendVarint(e.buf, v) }
+
+// string appends an int16 length-prefixed string
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// varbytes appends varint length-prefixed bytes, with -1 for nil
+func (e *encoder) varbytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// decoder reads Kafka protocol primitives from a response
+type decoder struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil || n < 0 || d.pos+n > len(d.buf) {
+		d.err = errTruncated
+		return nil
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.take(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.take(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *decoder) bool() bool {
+	b := d.take(1)
+	return b != nil && b[0] != 0
+}
+
+// string reads an int16 length-prefixed string; -1 (null) reads as ""
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+// arrayLen reads an array length, rejecting lengths the remaining bytes
+// cannot hold
+func (d *decoder) arrayLen() int {
+	n := d.int32()
+	if n < 0 {
+		return 0
+	}
+	if int(n) > len(d.buf)-d.pos {
+		d.err = errTruncated
+		return 0
+	}
+	return int(n)
+}
+
+// encodeRecordBatch encodes messages as one uncompressed v2 record batch
+func encodeRecordBatch(messages []Message, timestamp int64) []byte {
+	var records encoder
+	for i, m := range messages {
+		var r encoder
+		r.int8(0)   // attributes
+		r.varint(0) // timestamp delta
+		r.varint(int64(i))
+		r.varbytes(m.Key)
+		r.varbytes(m.Value)
+		r.varint(int64(len(m.Headers)))
+		for _, h := range m.Headers {
+			r.varbytes([]byte(h.Key))
+			r.varbytes(h.Value)
+		}
+		records.varint(int64(len(r.buf)))
+		records.buf = append(records.buf, r.buf...)
+	}
+
+	// The CRC covers everything from the attributes to the end
+	var body encoder
+	body.int16(0) // attributes: no compression, CreateTime
+	body.int32(int32(len(messages) - 1))
+	body.int64(timestamp)
+	body.int64(timestamp)
+	body.int64(-1) // producer id
+	body.int16(-1) // producer epoch
+	body.int32(-1) // base sequence
+	body.int32(int32(len(messages)))
+	body.buf = append(body.buf, records.buf...)
+
+	var batch encoder
+	batch.int64(0) // base offset, assigned by the broker
+	batch.int32(int32(4 + 1 + 4 + len(body.buf)))
+	batch.int32(-1) // partition leader epoch
+	batch.int8(recordBatchMagic)
+	batch.buf = binary.BigEndian.AppendUint32(batch.buf, crc32.Checksum(body.buf, castagnoli))
+	batch.buf = append(batch.buf, body.buf...)
+	return batch.buf
+}
+
+// murmur2 is the hash the Java client's default partitioner applies to
+// record keys, so keyed messages land on the same partitions as theirs
+func murmur2(data []byte) int32 {
+	const (
+		seed = 0x9747b28c
+		m    = 0x5bd1e995
+		r    = 24
+	)
+
+	h := uint32(seed) ^ uint32(len(data))
+	n := len(data) / 4
+	for i := 0; i < n; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[n*4:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
@@ -0,0 +1,197 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+// TestMurmur2 checks the hash against the vectors of the Java client's
+// Utils.murmur2 tests
+func TestMurmur2(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	}
+	for _, tt := range tests {
+		if got := murmur2([]byte(tt.key)); got != tt.want {
+			t.Errorf("murmur2(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	w := &Writer{leaders: make([]int32, 12)}
+	tests := []struct {
+		key  string
+		want int32
+	}{
+		// (murmur2 & 0x7fffffff) % 12, as the Java default partitioner
+		{"21", (-973932308 & 0x7fffffff) % 12},
+		{"foobar", (-790332482 & 0x7fffffff) % 12},
+		{"abc", 479470107 % 12},
+	}
+	for _, tt := range tests {
+		if got := w.partition([]byte(tt.key)); got != tt.want {
+			t.Errorf("partition(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+
+	// Unkeyed messages go round-robin
+	seen := make(map[int32]bool)
+	for range 12 {
+		seen[w.partition(nil)] = true
+	}
+	if len(seen) != 12 {
+		t.Errorf("unkeyed messages used %d of 12 partitions", len(seen))
+	}
+}
+
+// TestCastagnoli checks the CRC-32C table against the standard check value
+func TestCastagnoli(t *testing.T) {
+	if got := crc32.Checksum([]byte("123456789"), castagnoli); got != 0xe3069283 {
+		t.Errorf("CRC-32C = %#x, want 0xe3069283", got)
+	}
+}
+
+// TestEncodeRecordBatch compares a one-record batch with the v2 record
+// batch layout written out field by field
+func TestEncodeRecordBatch(t *testing.T) {
+	const timestamp = 1700000000000
+	ts := binary.BigEndian.AppendUint64(nil, timestamp)
+
+	var body []byte
+	body = append(body, 0x00, 0x00)                       // attributes
+	body = append(body, 0x00, 0x00, 0x00, 0x00)           // last offset delta
+	body = append(body, ts...)                            // first timestamp
+	body = append(body, ts...)                            // max timestamp
+	body = append(body, bytes.Repeat([]byte{0xff}, 8)...) // producer id
+	body = append(body, 0xff, 0xff)                       // producer epoch
+	body = append(body, 0xff, 0xff, 0xff, 0xff)           // base sequence
+	body = append(body, 0x00, 0x00, 0x00, 0x01)           // records
+	body = append(body,
+		0x18,      // record length 12, zigzag varint
+		0x00,      // attributes
+		0x00,      // timestamp delta
+		0x00,      // offset delta
+		0x02, 'k', // key
+		0x02, 'v', // value
+		0x02,      // one header
+		0x02, 'h', // header key
+		0x02, 'x', // header value
+	)
+
+	var want []byte
+	want = append(want, make([]byte, 8)...) // base offset
+	want = binary.BigEndian.AppendUint32(want, uint32(4+1+4+len(body)))
+	want = append(want, 0xff, 0xff, 0xff, 0xff) // partition leader epoch
+	want = append(want, 2)                      // magic
+	want = binary.BigEndian.AppendUint32(want, crc32.Checksum(body, crc32.MakeTable(crc32.Castagnoli)))
+	want = append(want, body...)
+
+	got := encodeRecordBatch([]Message{{
+		Key:     []byte("k"),
+		Value:   []byte("v"),
+		Headers: []Header{{Key: "h", Value: []byte("x")}},
+	}}, timestamp)
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeRecordBatch =\n% x\nwant\n% x", got, want)
+	}
+}
+
+func TestEncodeRecordBatchNilKey(t *testing.T) {
+	batch := encodeRecordBatch([]Message{{Value: []byte("a")}, {Value: []byte("b")}}, 0)
+	d := &decoder{buf: batch}
+	d.int64()
+	if length := d.int32(); int(length) != len(batch)-12 {
+		t.Fatalf("batch length = %d, want %d", length, len(batch)-12)
+	}
+	d.take(4 + 1 + 4 + 2) // leader epoch, magic, CRC, attributes
+	if last := d.int32(); last != 1 {
+		t.Errorf("last offset delta = %d, want 1", last)
+	}
+	d.take(8 + 8 + 8 + 2 + 4)
+	if n := d.int32(); n != 2 {
+		t.Errorf("record count = %d, want 2", n)
+	}
+	// Length, attributes, timestamp delta, offset delta, then -1 (zigzag
+	// 0x01) for the null key
+	if rec := d.take(5); d.err != nil || rec[4] != 0x01 {
+		t.Errorf("first record starts % x, want a null key", rec)
+	}
+}
+
+func TestEncoderPrimitives(t *testing.T) {
+	tests := []struct {
+		name   string
+		encode func(*encoder)
+		want   []byte
+	}{
+		{"int16", func(e *encoder) { e.int16(-2) }, []byte{0xff, 0xfe}},
+		{"int32", func(e *encoder) { e.int32(258) }, []byte{0, 0, 1, 2}},
+		{"int64", func(e *encoder) { e.int64(-1) }, bytes.Repeat([]byte{0xff}, 8)},
+		{"varint zero", func(e *encoder) { e.varint(0) }, []byte{0x00}},
+		{"varint negative", func(e *encoder) { e.varint(-1) }, []byte{0x01}},
+		{"varint 300", func(e *encoder) { e.varint(300) }, []byte{0xd8, 0x04}},
+		{"string", func(e *encoder) { e.string("ab") }, []byte{0, 2, 'a', 'b'}},
+		{"nil varbytes", func(e *encoder) { e.varbytes(nil) }, []byte{0x01}},
+		{"empty varbytes", func(e *encoder) { e.varbytes([]byte{}) }, []byte{0x00}},
+	}
+	for _, tt := range tests {
+		var e encoder
+		tt.encode(&e)
+		if !bytes.Equal(e.buf, tt.want) {
+			t.Errorf("%s: got % x, want % x", tt.name, e.buf, tt.want)
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	d := &decoder{buf: []byte{0, 2, 'o', 'k', 0xff, 0xff, 0, 0, 0, 7, 1}}
+	if s := d.string(); s != "ok" {
+		t.Errorf("string = %q, want ok", s)
+	}
+	if s := d.string(); s != "" {
+		t.Errorf("null string = %q, want empty", s)
+	}
+	if n := d.int32(); n != 7 {
+		t.Errorf("int32 = %d, want 7", n)
+	}
+	if !d.bool() {
+		t.Error("bool = false, want true")
+	}
+	if d.err != nil {
+		t.Fatalf("unexpected error %v", d.err)
+	}
+	d.int16()
+	if d.err != errTruncated {
+		t.Errorf("reading past the end: err = %v, want errTruncated", d.err)
+	}
+}
+
+func TestDecoderRejectsOversizedLengths(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  []byte
+		read func(*decoder)
+	}{
+		{"array", []byte{0x7f, 0xff, 0xff, 0xff, 0}, func(d *decoder) { d.arrayLen() }},
+		{"string", []byte{0x00, 0x09, 'a'}, func(d *decoder) { d.string() }},
+		{"truncated int64", []byte{1, 2, 3}, func(d *decoder) { d.int64() }},
+	}
+	for _, tt := range tests {
+		d := &decoder{buf: tt.buf}
+		tt.read(d)
+		if d.err != errTruncated {
+			t.Errorf("%s: err = %v, want errTruncated", tt.name, d.err)
+		}
+	}
+}
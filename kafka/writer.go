@@ -0,0 +1,431 @@
+// Package kafka is a minimal Kafka producer. It looks up partition leaders
+// with the Metadata API and sends uncompressed record batches that the
+// leader acknowledges. Consumers, compression, SASL and idempotent or
+// transactional writes are not supported.
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxBatchBytes keeps each produce request under the broker's default
+	// 1 MB message.max.bytes
+	maxBatchBytes = 900 << 10
+	// writeAttempts is how often a partition is retried after its leader
+	// moved
+	writeAttempts = 3
+	// defaultTimeout bounds each broker round trip without a context
+	// deadline
+	defaultTimeout = 30 * time.Second
+	// maxResponseBytes bounds what a broker can make the writer allocate;
+	// produce and metadata responses for one topic are a few kilobytes
+	maxResponseBytes = 16 << 20
+)
+
+// Retriable produce error codes: the partition's leader changed
+const (
+	errLeaderNotAvailable = 5
+	errNotLeader          = 6
+	errRequestTimedOut    = 7
+)
+
+// Header is a record header
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is one record; a nil Key spreads messages across partitions
+type Message struct {
+	Key     []byte
+	Value   []byte
+	Headers []Header
+}
+
+// Config configures a Writer
+type Config struct {
+	// Brokers are host:port bootstrap addresses
+	Brokers []string
+	Topic   string
+	// TLS connects to the brokers over TLS
+	TLS      bool
+	ClientID string
+}
+
+// ParseURL parses kafka://broker[,broker...]/topic, or kafka+tls:// for
+// TLS connections
+func ParseURL(s string) (Config, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid Kafka URL %q: %w", s, err)
+	}
+	topic := strings.Trim(u.Path, "/")
+	if (u.Scheme != "kafka" && u.Scheme != "kafka+tls") || u.Host == "" || topic == "" {
+		return Config{}, fmt.Errorf("invalid Kafka URL %q (expected kafka://broker:9092/topic)", s)
+	}
+	return Config{
+		Brokers: strings.Split(u.Host, ","),
+		Topic:   topic,
+		TLS:     u.Scheme == "kafka+tls",
+	}, nil
+}
+
+// Writer produces messages to one topic. It is safe for concurrent use.
+type Writer struct {
+	config Config
+
+	mu          sync.Mutex
+	conns       map[int32]*brokerConn
+	addrs       map[int32]string
+	leaders     []int32
+	next        int
+	correlation int32
+}
+
+// NewWriter creates a writer; brokers are contacted on the first write
+func NewWriter(config Config) *Writer {
+	if config.ClientID == "" {
+		config.ClientID = "s3-profiler"
+	}
+	return &Writer{config: config, conns: make(map[int32]*brokerConn)}
+}
+
+// WriteMessages sends messages and waits for the partition leaders to
+// acknowledge them. Keyed messages are partitioned like the Java client.
+func (w *Writer) WriteMessages(ctx context.Context, messages ...Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	start, size := 0, 0
+	for i, m := range messages {
+		n := len(m.Key) + len(m.Value) + 32
+		for _, h := range m.Headers {
+			n += len(h.Key) + len(h.Value)
+		}
+		if size+n > maxBatchBytes && i > start {
+			if err := w.write(ctx, messages[start:i]); err != nil {
+				return err
+			}
+			start, size = i, 0
+		}
+		size += n
+	}
+	if start < len(messages) {
+		return w.write(ctx, messages[start:])
+	}
+	return nil
+}
+
+// write sends one batch, retrying the partitions whose leader moved
+func (w *Writer) write(ctx context.Context, messages []Message) error {
+	if w.leaders == nil {
+		if err := w.refreshMetadata(ctx); err != nil {
+			return err
+		}
+	}
+
+	pending := make(map[int32][]Message)
+	for _, m := range messages {
+		p := w.partition(m.Key)
+		pending[p] = append(pending[p], m)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < writeAttempts && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			if err := w.refreshMetadata(ctx); err != nil {
+				return err
+			}
+		}
+
+		byLeader := make(map[int32][]int32)
+		for p := range pending {
+			leader := w.leaders[p]
+			byLeader[leader] = append(byLeader[leader], p)
+		}
+		for leader, partitions := range byLeader {
+			done, err := w.produce(ctx, leader, partitions, pending)
+			for _, p := range done {
+				delete(pending, p)
+			}
+			if err != nil {
+				var fatal *produceError
+				if errors.As(err, &fatal) {
+					return err
+				}
+				lastErr = err
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("kafka: failed to write to %s after %d attempts: %w", w.config.Topic, writeAttempts, lastErr)
+	}
+	return nil
+}
+
+// partition picks the partition for a key
+func (w *Writer) partition(key []byte) int32 {
+	n := int32(len(w.leaders))
+	if key == nil {
+		w.next++
+		return int32(w.next % int(n))
+	}
+	return (murmur2(key) & 0x7fffffff) % n
+}
+
+// produceError is a non-retriable error code returned by a broker
+type produceError struct {
+	topic     string
+	partition int32
+	code      int16
+}
+
+func (e *produceError) Error() string {
+	return fmt.Sprintf("kafka: write to %s partition %d failed with error code %d", e.topic, e.partition, e.code)
+}
+
+// produce sends the pending messages of partitions led by one broker and
+// returns the partitions the broker accepted
+func (w *Writer) produce(ctx context.Context, leader int32, partitions []int32, pending map[int32][]Message) ([]int32, error) {
+	conn, err := w.conn(ctx, leader)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	var req encoder
+	req.int16(-1) // transactional id
+	req.int16(1)  // acks: leader only
+	req.int32(int32(defaultTimeout / time.Millisecond))
+	req.int32(1)
+	req.string(w.config.Topic)
+	req.int32(int32(len(partitions)))
+	for _, p := range partitions {
+		batch := encodeRecordBatch(pending[p], now)
+		req.int32(p)
+		req.int32(int32(len(batch)))
+		req.buf = append(req.buf, batch...)
+	}
+
+	resp, err := w.roundTrip(ctx, conn, apiProduce, produceVersion, req.buf)
+	if err != nil {
+		conn.Close()
+		delete(w.conns, leader)
+		return nil, err
+	}
+
+	var done []int32
+	var retry error
+	d := &decoder{buf: resp}
+	for range d.arrayLen() {
+		topic := d.string()
+		for range d.arrayLen() {
+			p := d.int32()
+			code := d.int16()
+			d.int64() // base offset
+			d.int64() // log append time
+			if d.err != nil {
+				break
+			}
+			switch code {
+			case 0:
+				done = append(done, p)
+			case errLeaderNotAvailable, errNotLeader, errRequestTimedOut:
+				retry = fmt.Errorf("kafka: partition %d of %s is moving (error code %d)", p, topic, code)
+			default:
+				return done, &produceError{topic: topic, partition: p, code: code}
+			}
+		}
+	}
+	if d.err != nil {
+		return done, d.err
+	}
+	return done, retry
+}
+
+// refreshMetadata looks up the topic's partition leaders from the first
+// bootstrap broker that answers
+func (w *Writer) refreshMetadata(ctx context.Context) error {
+	var req encoder
+	req.int32(1)
+	req.string(w.config.Topic)
+
+	var lastErr error
+	for _, addr := range w.config.Brokers {
+		conn, err := w.dial(ctx, addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp, err := w.roundTrip(ctx, conn, apiMetadata, metadataVersion, req.buf)
+		conn.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return w.parseMetadata(resp)
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no brokers configured")
+	}
+	return fmt.Errorf("kafka: failed to get metadata for %s: %w", w.config.Topic, lastErr)
+}
+
+// parseMetadata records broker addresses and partition leaders
+func (w *Writer) parseMetadata(resp []byte) error {
+	d := &decoder{buf: resp}
+	addrs := make(map[int32]string)
+	for range d.arrayLen() {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		addrs[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller
+
+	var leaders []int32
+	for range d.arrayLen() {
+		code := d.int16()
+		name := d.string()
+		d.bool() // internal
+		partitions := d.arrayLen()
+		if code != 0 && d.err == nil {
+			return fmt.Errorf("kafka: metadata for %s failed with error code %d", name, code)
+		}
+		leaders = make([]int32, partitions)
+		for range partitions {
+			d.int16() // partition error
+			p := d.int32()
+			leader := d.int32()
+			for range d.arrayLen() {
+				d.int32() // replicas
+			}
+			for range d.arrayLen() {
+				d.int32() // in-sync replicas
+			}
+			if p >= 0 && int(p) < len(leaders) {
+				leaders[p] = leader
+			}
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(leaders) == 0 {
+		return fmt.Errorf("kafka: topic %s has no partitions", w.config.Topic)
+	}
+
+	w.addrs = addrs
+	w.leaders = leaders
+	return nil
+}
+
+// brokerConn is a connection to one broker
+type brokerConn struct {
+	net.Conn
+}
+
+// conn returns a connection to a broker, dialing it if needed
+func (w *Writer) conn(ctx context.Context, id int32) (*brokerConn, error) {
+	if conn, ok := w.conns[id]; ok {
+		return conn, nil
+	}
+	addr, ok := w.addrs[id]
+	if !ok {
+		return nil, fmt.Errorf("kafka: unknown broker %d", id)
+	}
+	conn, err := w.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	w.conns[id] = conn
+	return conn, nil
+}
+
+// dial connects to a broker address
+func (w *Writer) dial(ctx context.Context, addr string) (*brokerConn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if w.config.TLS {
+		host, _, _ := net.SplitHostPort(addr)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+	return &brokerConn{Conn: conn}, nil
+}
+
+// roundTrip sends a request and returns the response body after the
+// correlation id
+func (w *Writer) roundTrip(ctx context.Context, conn *brokerConn, apiKey, version int16, body []byte) ([]byte, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	w.correlation++
+	var req encoder
+	req.int32(0) // size, filled in below
+	req.int16(apiKey)
+	req.int16(version)
+	req.int32(w.correlation)
+	req.string(w.config.ClientID)
+	req.buf = append(req.buf, body...)
+	binary.BigEndian.PutUint32(req.buf, uint32(len(req.buf)-4))
+	if _, err := conn.Write(req.buf); err != nil {
+		return nil, err
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxResponseBytes {
+		return nil, fmt.Errorf("kafka: %d byte response exceeds the %d byte limit", n, maxResponseBytes)
+	}
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	if len(resp) < 4 || int32(binary.BigEndian.Uint32(resp)) != w.correlation {
+		return nil, errors.New("kafka: response does not match request")
+	}
+	return resp[4:], nil
+}
+
+// Close closes the broker connections
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	for id, conn := range w.conns {
+		if cerr := conn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		delete(w.conns, id)
+	}
+	return err
+}
@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeBroker answers the requests sent over a pipe: each request is passed
+// to respond with its API key and body, and the response is framed with
+// the request's correlation id
+func fakeBroker(t *testing.T, respond func(apiKey int16, body []byte) []byte) *brokerConn {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	go func() {
+		defer server.Close()
+		for {
+			var size [4]byte
+			if _, err := io.ReadFull(server, size[:]); err != nil {
+				return
+			}
+			req := make([]byte, binary.BigEndian.Uint32(size[:]))
+			if _, err := io.ReadFull(server, req); err != nil {
+				return
+			}
+			d := &decoder{buf: req}
+			apiKey := d.int16()
+			d.int16() // version
+			correlation := d.int32()
+			d.string() // client id
+
+			var resp encoder
+			resp.int32(0)
+			resp.int32(correlation)
+			resp.buf = append(resp.buf, respond(apiKey, req[d.pos:])...)
+			binary.BigEndian.PutUint32(resp.buf, uint32(len(resp.buf)-4))
+			if _, err := server.Write(resp.buf); err != nil {
+				return
+			}
+		}
+	}()
+	return &brokerConn{Conn: client}
+}
+
+// metadataResponse encodes a v1 Metadata response with one broker leading
+// every partition of the topic
+func metadataResponse(topic string, partitions int, code int16) []byte {
+	var e encoder
+	e.int32(1)
+	e.int32(1) // node id
+	e.string("broker-1")
+	e.int32(9092)
+	e.int16(-1) // rack
+	e.int32(1)  // controller
+
+	e.int32(1)
+	e.int16(code)
+	e.string(topic)
+	e.int8(0) // internal
+	e.int32(int32(partitions))
+	for p := range partitions {
+		e.int16(0)
+		e.int32(int32(p))
+		e.int32(1) // leader
+		e.int32(1)
+		e.int32(1) // replicas
+		e.int32(1)
+		e.int32(1) // in-sync replicas
+	}
+	return e.buf
+}
+
+func TestParseMetadata(t *testing.T) {
+	w := NewWriter(Config{Topic: "objects"})
+	if err := w.parseMetadata(metadataResponse("objects", 3, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.leaders) != 3 || w.leaders[2] != 1 {
+		t.Errorf("leaders = %v, want three partitions led by broker 1", w.leaders)
+	}
+	if got := w.addrs[1]; got != "broker-1:9092" {
+		t.Errorf("broker 1 address = %q, want broker-1:9092", got)
+	}
+
+	err := w.parseMetadata(metadataResponse("objects", 0, 3))
+	if err == nil || !strings.Contains(err.Error(), "error code 3") {
+		t.Errorf("unknown topic: err = %v, want error code 3", err)
+	}
+
+	resp := metadataResponse("objects", 3, 0)
+	if err := w.parseMetadata(resp[:len(resp)-6]); err != errTruncated {
+		t.Errorf("truncated metadata: err = %v, want errTruncated", err)
+	}
+}
+
+// produceResponse encodes a v3 Produce response with one error code per
+// partition, in partition order
+func produceResponse(topic string, codes map[int32]int16) []byte {
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+	e.int32(int32(len(codes)))
+	for _, p := range slices.Sorted(maps.Keys(codes)) {
+		e.int32(p)
+		e.int16(codes[p])
+		e.int64(0)  // base offset
+		e.int64(-1) // log append time
+	}
+	e.int32(0) // throttle time
+	return e.buf
+}
+
+func TestProduce(t *testing.T) {
+	tests := []struct {
+		name     string
+		codes    map[int32]int16
+		wantDone int
+		wantErr  string
+	}{
+		{"accepted", map[int32]int16{0: 0, 1: 0}, 2, ""},
+		{"leader moved", map[int32]int16{0: 0, 1: errNotLeader}, 1, "is moving"},
+		{"rejected", map[int32]int16{0: 0, 1: 10}, 1, "error code 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter(Config{Topic: "objects"})
+			var got []byte
+			w.conns[1] = fakeBroker(t, func(apiKey int16, body []byte) []byte {
+				if apiKey != apiProduce {
+					t.Errorf("api key = %d, want %d", apiKey, apiProduce)
+				}
+				got = body
+				return produceResponse("objects", tt.codes)
+			})
+
+			pending := map[int32][]Message{0: {{Value: []byte("a")}}, 1: {{Value: []byte("b")}}}
+			done, err := w.produce(context.Background(), 1, []int32{0, 1}, pending)
+			if len(done) != tt.wantDone {
+				t.Errorf("accepted %d partitions, want %d", len(done), tt.wantDone)
+			}
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+
+			d := &decoder{buf: got}
+			d.int16() // transactional id
+			if acks := d.int16(); acks != 1 {
+				t.Errorf("acks = %d, want 1", acks)
+			}
+			d.int32() // timeout
+			d.arrayLen()
+			if topic := d.string(); topic != "objects" {
+				t.Errorf("topic = %q, want objects", topic)
+			}
+			if n := d.arrayLen(); n != 2 || d.err != nil {
+				t.Errorf("partitions = %d (%v), want 2", n, d.err)
+			}
+		})
+	}
+}
+
+func TestRoundTripRejectsOversizedResponses(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		var size [4]byte
+		io.ReadFull(server, size[:])
+		io.ReadFull(server, make([]byte, binary.BigEndian.Uint32(size[:])))
+		binary.BigEndian.PutUint32(size[:], 0x7fffffff)
+		server.Write(size[:])
+	}()
+
+	w := NewWriter(Config{Topic: "objects"})
+	_, err := w.roundTrip(context.Background(), &brokerConn{Conn: client}, apiMetadata, metadataVersion, nil)
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("err = %v, want the response size limit", err)
+	}
+}
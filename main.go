@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	_ "time/tzdata" // embed zone database for --timezone on systems without one
 
 	"github.com/yourusername/s3-profiler/cmd"
 )
@@ -10,6 +12,12 @@ import (
 func main() {
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if errors.Is(err, cmd.ErrFailOn) {
+			os.Exit(2)
+		}
+		if errors.Is(err, cmd.ErrInterrupted) {
+			os.Exit(130)
+		}
 		os.Exit(1)
 	}
 }
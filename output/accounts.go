@@ -0,0 +1,101 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// accountRollupName is the file the multi-account roll-up is written to
+const accountRollupName = "accounts-rollup.txt"
+
+// AccountRollupName returns the name the multi-account roll-up is stored
+// under
+func (w *Writer) AccountRollupName() string {
+	return w.FileName(accountRollupName)
+}
+
+// WriteAccountRollup writes the combined totals of a multi-account run:
+// one row per account, then the buckets profiled with each account
+func (w *Writer) WriteAccountRollup(rollups []types.AccountRollup) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.t("Multi-Account Roll-up")))
+	b.WriteString("\n\n")
+
+	row := "%-20s %-20s %-14s %8s %8s %14s %12s %12s\n"
+	fmt.Fprintf(&b, row, w.t("Account"), w.t("Profile"), w.t("Region"), w.t("Buckets"), w.t("Failed"), w.t("Objects"), w.t("Size"), w.costColumn())
+
+	var buckets, failed int
+	var objects, size int64
+	var cost float64
+	for _, account := range rollups {
+		var accountObjects, accountSize int64
+		var accountCost float64
+		for _, snapshot := range account.Buckets {
+			accountObjects += snapshot.TotalObjects
+			accountSize += snapshot.TotalSize
+			accountCost += snapshot.EstimatedCost
+		}
+		fmt.Fprintf(&b, row, account.Name, account.Profile, account.Region,
+			FormatNumber(int64(len(account.Buckets))), FormatNumber(int64(len(account.Failed))),
+			FormatNumber(accountObjects), FormatBytes(accountSize), w.cost(accountCost))
+
+		buckets += len(account.Buckets)
+		failed += len(account.Failed)
+		objects += accountObjects
+		size += accountSize
+		cost += accountCost
+	}
+	fmt.Fprintf(&b, row, w.t("Total"), "", "",
+		FormatNumber(int64(buckets)), FormatNumber(int64(failed)),
+		FormatNumber(objects), FormatBytes(size), w.cost(cost))
+	b.WriteString("\n")
+
+	estimated := false
+	for _, account := range rollups {
+		b.WriteString(FormatSubHeader(w.tf("Account: %s", account.Name)))
+		b.WriteString("\n")
+		if account.Error != "" {
+			b.WriteString(w.tf("Not profiled: %s", account.Error))
+			b.WriteString("\n\n")
+			continue
+		}
+
+		snapshots := append([]*types.Snapshot(nil), account.Buckets...)
+		sort.Slice(snapshots, func(i, j int) bool {
+			if snapshots[i].TotalSize != snapshots[j].TotalSize {
+				return snapshots[i].TotalSize > snapshots[j].TotalSize
+			}
+			return snapshots[i].Bucket < snapshots[j].Bucket
+		})
+		if len(snapshots) > 0 {
+			fmt.Fprintf(&b, "%-50s %14s %12s %12s\n", w.t("Bucket"), w.t("Objects"), w.t("Size"), w.costColumn())
+		}
+		for _, snapshot := range snapshots {
+			name := w.bucket(snapshot.Bucket)
+			if snapshot.Estimate != nil {
+				name += " *"
+				estimated = true
+			}
+			fmt.Fprintf(&b, "%-50s %14s %12s %12s\n",
+				name, FormatNumber(snapshot.TotalObjects), FormatBytes(snapshot.TotalSize), w.cost(snapshot.EstimatedCost))
+		}
+		for _, bucket := range account.Failed {
+			fmt.Fprintf(&b, "%-50s %s\n", w.bucket(bucket), w.t("failed"))
+		}
+		b.WriteString("\n")
+	}
+
+	if estimated {
+		b.WriteString(w.t("* Partial listing (--limit or interrupted); totals only cover the objects listed"))
+		b.WriteString("\n")
+	}
+	if note := w.currencyNote(); note != "" {
+		b.WriteString(note + "\n")
+	}
+
+	return w.writeFile(accountRollupName, b.String())
+}
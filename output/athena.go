@@ -0,0 +1,155 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// WriteAthenaDDL writes a CREATE EXTERNAL TABLE statement per table, each
+// followed by MSCK REPAIR TABLE to load the existing partitions. Tables
+// whose columns are unknown are written commented out.
+func (w *Writer) WriteAthenaDDL(bucketName string, tables []types.AthenaTable) error {
+	bucket := w.bucket(bucketName)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Athena tables over the Hive-style partitions of s3://%s\n", bucket)
+	b.WriteString("-- Run each statement with the target database selected.\n")
+
+	seen := make(map[string]int)
+	for _, table := range tables {
+		location := w.rawKey(table.Location)
+		name := athenaTableName(bucket, location)
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, seen[name])
+		}
+
+		var ddl strings.Builder
+		fmt.Fprintf(&ddl, "CREATE EXTERNAL TABLE IF NOT EXISTS %s (\n", athenaIdentifier(name))
+		columns, nested := athenaTopLevelColumns(table.Columns)
+		if len(columns) == 0 {
+			ddl.WriteString("  <column> <type>\n")
+		}
+		for i, column := range columns {
+			fmt.Fprintf(&ddl, "  %s %s", athenaIdentifier(column.Name), column.Type)
+			if i < len(columns)-1 {
+				ddl.WriteString(",")
+			}
+			ddl.WriteString("\n")
+		}
+		ddl.WriteString(")\nPARTITIONED BY (\n")
+		for i, key := range table.PartitionKeys {
+			fmt.Fprintf(&ddl, "  %s string", athenaIdentifier(key))
+			if i < len(table.PartitionKeys)-1 {
+				ddl.WriteString(",")
+			}
+			ddl.WriteString("\n")
+		}
+		ddl.WriteString(")\n")
+		switch table.Format {
+		case "parquet":
+			ddl.WriteString("STORED AS PARQUET\n")
+		case "csv":
+			delimiter := table.Delimiter
+			if delimiter == "" {
+				delimiter = ","
+			}
+			// OpenCSVSerDe handles quoted fields and reads every column
+			// as a string
+			ddl.WriteString("ROW FORMAT SERDE 'org.apache.hadoop.hive.serde2.OpenCSVSerde'\n")
+			fmt.Fprintf(&ddl, "WITH SERDEPROPERTIES ('separatorChar' = '%s', 'quoteChar' = '\"')\n", delimiter)
+			ddl.WriteString("STORED AS TEXTFILE\n")
+		case "json":
+			ddl.WriteString("ROW FORMAT SERDE 'org.openx.data.jsonserde.JsonSerDe'\n")
+			ddl.WriteString("STORED AS TEXTFILE\n")
+		}
+		fmt.Fprintf(&ddl, "LOCATION %s", athenaString("s3://"+bucket+"/"+location))
+		if table.Header {
+			ddl.WriteString("\nTBLPROPERTIES ('skip.header.line.count' = '1')")
+		}
+		ddl.WriteString(";\n\n")
+		fmt.Fprintf(&ddl, "MSCK REPAIR TABLE %s;\n", athenaIdentifier(name))
+
+		b.WriteString("\n")
+		root := location
+		if root == "" {
+			root = "(bucket root)"
+		}
+		fmt.Fprintf(&b, "-- %s: %d partition(s) of %s, %s files\n", root, table.Partitions, table.Pattern, table.Format)
+		if len(nested) > 0 {
+			fmt.Fprintf(&b, "-- Nested columns left out (declare them as struct, array or map): %s\n", strings.Join(nested, ", "))
+		}
+		if len(columns) == 0 {
+			b.WriteString("-- Columns unknown: rerun with --parquet-stats or --sample-content, or\n")
+			b.WriteString("-- fill in the column list and uncomment the statements.\n")
+			for _, line := range strings.Split(strings.TrimSuffix(ddl.String(), "\n"), "\n") {
+				if line == "" {
+					b.WriteString("\n")
+					continue
+				}
+				b.WriteString("-- " + line + "\n")
+			}
+			continue
+		}
+		fmt.Fprintf(&b, "-- Columns from %s\n", w.key(table.SchemaSource))
+		b.WriteString(ddl.String())
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-athena.sql"), b.String())
+}
+
+// athenaTopLevelColumns drops the leaves of nested Parquet columns, named
+// by dotted paths, returning the top-level names of those left out
+func athenaTopLevelColumns(columns []types.SchemaColumn) ([]types.SchemaColumn, []string) {
+	var flat []types.SchemaColumn
+	var nested []string
+	seen := make(map[string]bool)
+	for _, column := range columns {
+		top, _, isNested := strings.Cut(column.Name, ".")
+		if !isNested {
+			flat = append(flat, column)
+			continue
+		}
+		if !seen[top] {
+			seen[top] = true
+			nested = append(nested, top)
+		}
+	}
+	return flat, nested
+}
+
+// athenaTableName derives a table name from the location's last directory,
+// or from the bucket name for tables at the bucket root
+func athenaTableName(bucketName, location string) string {
+	name := bucketName
+	if location != "" {
+		dirs := strings.Split(strings.TrimSuffix(location, "/"), "/")
+		name = dirs[len(dirs)-1]
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	sanitized := strings.Trim(b.String(), "_")
+	if sanitized == "" || (sanitized[0] >= '0' && sanitized[0] <= '9') {
+		sanitized = "t_" + sanitized
+	}
+	return sanitized
+}
+
+// athenaIdentifier quotes a table or column name for Athena DDL
+func athenaIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// athenaString quotes a string literal for Athena DDL
+func athenaString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "\\'") + "'"
+}
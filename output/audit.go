@@ -0,0 +1,74 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// FormatAudit renders the pass/fail security posture report of the audit
+// command: one section per bucket followed by the results per check
+func FormatAudit(audits []types.BucketAudit) string {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader("Security Audit"))
+	b.WriteString("\n\n")
+
+	// Totals per check, in the order the checks were run
+	type checkTotals struct {
+		name               string
+		pass, fail, errors int
+	}
+	var totals []*checkTotals
+	byName := make(map[string]*checkTotals)
+	var pass, fail, errors int
+
+	for _, audit := range audits {
+		title := audit.Bucket
+		if audit.Region != "" {
+			title = fmt.Sprintf("%s (%s)", audit.Bucket, audit.Region)
+		}
+		b.WriteString(FormatSubHeader(title))
+		b.WriteString("\n")
+
+		for _, check := range audit.Checks {
+			fmt.Fprintf(&b, "  %-6s %-22s %s\n", check.Status, check.Name, check.Detail)
+
+			t, ok := byName[check.Name]
+			if !ok {
+				t = &checkTotals{name: check.Name}
+				byName[check.Name] = t
+				totals = append(totals, t)
+			}
+			switch check.Status {
+			case types.AuditPass:
+				t.pass++
+				pass++
+			case types.AuditFail:
+				t.fail++
+				fail++
+			default:
+				t.errors++
+				errors++
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(FormatSubHeader("Summary"))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%-24s %8s %8s %8s\n", "Check", "Pass", "Fail", "Error")
+	for _, t := range totals {
+		fmt.Fprintf(&b, "%-24s %8d %8d %8d\n", t.name, t.pass, t.fail, t.errors)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Buckets:        %d\n", len(audits))
+	fmt.Fprintf(&b, "Checks Passed:  %d\n", pass)
+	fmt.Fprintf(&b, "Checks Failed:  %d\n", fail)
+	if errors > 0 {
+		fmt.Fprintf(&b, "Checks Not Run: %d (see ERROR rows; usually missing permissions)\n", errors)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,214 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// defaultCompareRows is the number of contributors listed per section
+const defaultCompareRows = 10
+
+// FormatComparison renders a growth attribution report for two runs.
+// top limits the contributors listed per section (0 = default).
+func FormatComparison(c *types.Comparison, top int, loc *time.Location) string {
+	if top <= 0 {
+		top = defaultCompareRows
+	}
+
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(fmt.Sprintf("Run Comparison: %s", c.Bucket)))
+	b.WriteString("\n\n")
+	if c.Estimated {
+		b.WriteString("*** ESTIMATE ***\nAt least one run was truncated by --limit; changes include keys that were\nsimply not listed and are not actual growth or deletion.\n\n")
+	}
+
+	fmt.Fprintf(&b, "Previous Run:  %s\n", FormatTime(c.OldGenerated, loc))
+	fmt.Fprintf(&b, "Current Run:   %s\n\n", FormatTime(c.NewGenerated, loc))
+	fmt.Fprintf(&b, "Total Size:    %s -> %s (%s)\n", FormatBytes(c.OldSize), FormatBytes(c.NewSize), formatSizeDelta(c.NewSize-c.OldSize))
+	fmt.Fprintf(&b, "Total Objects: %s -> %s (%s)\n", FormatNumber(c.OldObjects), FormatNumber(c.NewObjects), formatCountDelta(c.NewObjects-c.OldObjects))
+
+	var added, removed int64
+	for _, d := range c.Prefixes {
+		if d.SizeDelta > 0 {
+			added += d.SizeDelta
+		} else {
+			removed -= d.SizeDelta
+		}
+	}
+	fmt.Fprintf(&b, "Bytes Added:   %s\n", FormatBytes(added))
+	fmt.Fprintf(&b, "Bytes Removed: %s\n", FormatBytes(removed))
+
+	writeDeltaTable(&b, "Change by Top-Level Prefix", c.TopLevel, top, func(d types.PrefixDelta) bool { return true }, 0)
+	writeDeltaTable(&b, "Top Contributors to Added Bytes", c.Prefixes, top, func(d types.PrefixDelta) bool { return d.SizeDelta > 0 }, added)
+	writeDeltaTable(&b, "Top Contributors to Removed Bytes", c.Prefixes, top, func(d types.PrefixDelta) bool { return d.SizeDelta < 0 }, removed)
+	if len(c.Partitions) > 0 {
+		writeDeltaTable(&b, "Change by Partition", c.Partitions, top, func(d types.PrefixDelta) bool { return true }, 0)
+	}
+	writeChurn(&b, c, top)
+
+	return b.String()
+}
+
+// writeDeltaTable lists the matching deltas in their existing order. When
+// share is non-zero, each row also shows its part of that total.
+func writeDeltaTable(b *strings.Builder, title string, deltas []types.PrefixDelta, top int, match func(types.PrefixDelta) bool, share int64) {
+	var rows []types.PrefixDelta
+	for _, d := range deltas {
+		if match(d) {
+			rows = append(rows, d)
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(FormatSubHeader(title))
+	b.WriteString("\n")
+	if len(rows) == 0 {
+		b.WriteString("No changes\n")
+		return
+	}
+
+	shown := len(rows)
+	if shown > top {
+		shown = top
+	}
+
+	header := fmt.Sprintf("%-40s %14s %14s %15s %12s", "Prefix", "Before", "After", "Change", "Objects")
+	if share != 0 {
+		header += fmt.Sprintf(" %8s", "Share")
+	}
+	b.WriteString(header + "\n")
+	for _, d := range rows[:shown] {
+		prefix := d.Prefix
+		switch {
+		case d.OldCount == 0:
+			prefix += " (new)"
+		case d.NewCount == 0:
+			prefix += " (gone)"
+		}
+		fmt.Fprintf(b, "%-40s %14s %14s %15s %12s",
+			prefix,
+			FormatBytes(d.OldSize),
+			FormatBytes(d.NewSize),
+			formatSizeDelta(d.SizeDelta),
+			formatCountDelta(d.CountDelta))
+		if share != 0 {
+			fmt.Fprintf(b, " %8s", FormatPercent(abs(d.SizeDelta), share))
+		}
+		b.WriteString("\n")
+	}
+	if len(rows) > shown {
+		fmt.Fprintf(b, "... %d more not shown (use --top to change)\n", len(rows)-shown)
+	}
+}
+
+// writeChurn lists the prefixes with the most replaced bytes between runs
+func writeChurn(b *strings.Builder, c *types.Comparison, top int) {
+	b.WriteString("\n")
+	b.WriteString(FormatSubHeader("Churn by Prefix"))
+	b.WriteString("\n")
+	b.WriteString("Written: bytes last modified after the previous run\n")
+	b.WriteString("Removed: bytes from the previous run deleted or overwritten since\n")
+	b.WriteString("Replaced: written bytes that replaced removed ones (the churn)\n")
+	if c.ChurnTruncated {
+		b.WriteString("Note: runs are further apart than the write history kept in snapshots; churn is a lower bound\n")
+	}
+	b.WriteString("\n")
+
+	if len(c.Churn) == 0 {
+		b.WriteString("No churn\n")
+		return
+	}
+
+	var written, removed, replaced int64
+	for _, ch := range c.Churn {
+		written += ch.Written
+		removed += ch.Removed
+		replaced += ch.Replaced
+	}
+
+	shown := len(c.Churn)
+	if shown > top {
+		shown = top
+	}
+
+	fmt.Fprintf(b, "%-40s %14s %14s %14s %14s\n", "Prefix", "Written", "Removed", "Replaced", "Rate/30d")
+	for _, ch := range c.Churn[:shown] {
+		fmt.Fprintf(b, "%-40s %14s %14s %14s %13.1f%%\n",
+			ch.Prefix,
+			FormatBytes(ch.Written),
+			FormatBytes(ch.Removed),
+			FormatBytes(ch.Replaced),
+			ch.MonthlyRate*100)
+	}
+	if len(c.Churn) > shown {
+		fmt.Fprintf(b, "... %d more not shown (use --top to change)\n", len(c.Churn)-shown)
+	}
+	fmt.Fprintf(b, "%-40s %14s %14s %14s\n", "Total", FormatBytes(written), FormatBytes(removed), FormatBytes(replaced))
+}
+
+// formatSizeDelta formats a signed byte change
+func formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + FormatBytes(-delta)
+	}
+	return "+" + FormatBytes(delta)
+}
+
+// formatCountDelta formats a signed object count change
+func formatCountDelta(delta int64) string {
+	if delta < 0 {
+		return FormatNumber(delta)
+	}
+	return "+" + FormatNumber(delta)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// FormatRunHistory lists stored runs of a bucket, newest first, with the
+// change since the previous run
+func FormatRunHistory(bucketName string, runs []types.RunRecord, loc *time.Location) string {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(fmt.Sprintf("Run History: %s", bucketName)))
+	b.WriteString("\n\n")
+	if len(runs) == 0 {
+		b.WriteString("No runs stored\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%8s  %-31s %14s %15s %14s %12s\n", "Run", "Generated", "Size", "Change", "Objects", "Cost/Month")
+	for i, run := range runs {
+		change := ""
+		if i+1 < len(runs) {
+			change = formatSizeDelta(run.TotalSize - runs[i+1].TotalSize)
+		}
+		generated := FormatTime(run.Generated, loc)
+		if run.Truncated {
+			generated += " *"
+		}
+		fmt.Fprintf(&b, "%8d  %-31s %14s %15s %14s %12s\n",
+			run.ID,
+			generated,
+			FormatBytes(run.TotalSize),
+			change,
+			FormatNumber(run.TotalObjects),
+			FormatCost(run.EstimatedCost))
+	}
+
+	for _, run := range runs {
+		if run.Truncated {
+			b.WriteString("\n* truncated by --limit; totals cover only the listed objects\n")
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression selects how large export files are compressed
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// ParseCompression converts a flag value into a Compression
+func ParseCompression(value string) (Compression, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none":
+		return CompressionNone, nil
+	case "gzip", "gz":
+		return CompressionGzip, nil
+	case "zstd", "zst":
+		return CompressionZstd, nil
+	default:
+		return CompressionNone, fmt.Errorf("unknown compression %q (expected none, gzip or zstd)", value)
+	}
+}
+
+// Extension returns the file name suffix for the compression
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// compressedFile closes the compressor or encryptor before the underlying
+// file
+type compressedFile struct {
+	io.WriteCloser
+	file io.Closer
+}
+
+func (cf *compressedFile) Close() error {
+	if err := cf.WriteCloser.Close(); err != nil {
+		cf.file.Close()
+		return err
+	}
+	return cf.file.Close()
+}
+
+// createExport creates an export file in the output directory, wrapped in the
+// configured compression and encryption. Their extensions are appended to
+// name.
+func (w *Writer) createExport(name string) (io.WriteCloser, error) {
+	path := filepath.Join(w.outputDir, w.ExportName(name))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Data is compressed before it is encrypted; ciphertext does not compress
+	var dst io.WriteCloser = file
+	if w.opts.Encryption != nil {
+		enc, err := w.opts.Encryption.encrypt(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		dst = &compressedFile{WriteCloser: enc, file: file}
+	}
+
+	switch w.opts.Compression {
+	case CompressionGzip:
+		return &compressedFile{WriteCloser: gzip.NewWriter(dst), file: dst}, nil
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(dst)
+		if err != nil {
+			dst.Close()
+			return nil, err
+		}
+		return &compressedFile{WriteCloser: enc, file: dst}, nil
+	default:
+		return dst, nil
+	}
+}
+
+// ExportName returns the file name an export is written under, including the
+// compression and encryption extensions
+func (w *Writer) ExportName(name string) string {
+	return w.FileName(name + w.opts.Compression.Extension())
+}
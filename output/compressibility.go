@@ -0,0 +1,55 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// maxListedCompressibility is the default number of rows of the
+// compressibility table
+const maxListedCompressibility = 20
+
+// writeCompressibility writes the entropy, compression ratio and projected
+// savings of each sampled top-level prefix
+func (w *Writer) writeCompressibility(b *strings.Builder, c *types.CompressibilityAnalysis) {
+	b.WriteString(FormatSubHeader(w.t("Compressibility")))
+	b.WriteString("\n")
+
+	prefixes := append([]types.PrefixCompressibility(nil), c.Prefixes...)
+	sortTable(prefixes, w.opts.Table, func(p types.PrefixCompressibility) tableRow {
+		return tableRow{name: p.Prefix, count: p.ObjectCount, size: p.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(prefixes), maxListedCompressibility)
+
+	fmt.Fprintf(b, "%-30s %12s %12s %8s %8s %6s %-20s %12s %10s\n",
+		w.t("Prefix"), w.t("Objects"), w.t("Size"), w.t("Sampled"), w.t("Entropy"), w.t("Ratio"), w.t("Content"), w.t("Saved"), w.t("Savings"))
+	for _, p := range prefixes[:shown] {
+		saved, savings := "-", "-"
+		if p.Class != types.CompressedContent {
+			saved, savings = FormatBytes(p.SavedBytes), w.cost(p.Savings)
+		}
+		fmt.Fprintf(b, "%-30s %12s %12s %8d %8.2f %6.2f %-20s %12s %10s\n",
+			w.key(p.Prefix),
+			FormatNumber(p.ObjectCount),
+			FormatBytes(p.Size),
+			p.Sampled,
+			p.Entropy,
+			p.Ratio,
+			w.t(p.Class),
+			saved,
+			savings)
+	}
+	writeMoreFooter(b, shown, len(prefixes))
+
+	b.WriteString(w.tf("Sampled the first %s of %d object(s)", FormatBytes(c.SampleBytes), c.Sampled))
+	if c.Failed > 0 {
+		b.WriteString(w.tf("; %d read(s) failed", c.Failed))
+	}
+	b.WriteString("\n")
+	b.WriteString(w.tf("Estimated savings: %s/month", w.cost(c.Savings)) + "\n")
+	b.WriteString(w.t("Entropy is in bits per byte; 8 is random. Ratio is the DEFLATE (gzip) size of the sampled bytes; compressed or encrypted content stays near 1.") + "\n")
+	b.WriteString(w.t("Savings assume the pipelines writing a prefix compress its objects at the sampled ratio; zstd usually does better, columnar formats with a codec are already compressed.") + "\n")
+	b.WriteString("\n")
+}
@@ -0,0 +1,307 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// WriteConfiguration writes the bucket configuration audit report
+func (w *Writer) WriteConfiguration(bucketName string, config *types.BucketConfiguration) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.tf("Bucket Configuration: %s", w.bucket(bucketName))))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	if len(config.Tags) > 0 {
+		tags := make([]string, 0, len(config.Tags))
+		for key, value := range config.Tags {
+			tags = append(tags, w.tag(key)+"="+w.tag(value))
+		}
+		sort.Strings(tags)
+		fmt.Fprintf(&b, "%s %s\n\n", w.label("Tags:", 6), strings.Join(tags, ", "))
+	}
+
+	// S3 Inventory
+	b.WriteString(FormatSubHeader(w.t("S3 Inventory")))
+	b.WriteString("\n")
+	if len(config.Inventories) == 0 {
+		b.WriteString(w.t("No inventory configurations") + "\n")
+	}
+	for _, inv := range config.Inventories {
+		status := "disabled"
+		if inv.Enabled {
+			status = "enabled"
+		}
+		fmt.Fprintf(&b, "%s (%s): %s %s, %s versions -> %s\n",
+			inv.ID, status, inv.Frequency, inv.Format, inv.IncludedVersions, w.key(inv.Destination))
+	}
+	if rec := config.InventoryRecommendation; rec != nil {
+		b.WriteString("\nRecommendation:\n")
+		fmt.Fprintf(&b, "  %s.\n", rec.Reason)
+		fmt.Fprintf(&b, "  Enable a %s inventory in %s format with fields: %s\n",
+			strings.ToLower(rec.Frequency), rec.Format, strings.Join(rec.OptionalFields, ", "))
+	}
+	b.WriteString("\n")
+
+	w.writeChargeableFeatures(&b, config.ChargeableFeatures)
+	w.writeNotifications(&b, config)
+	w.writeWebsite(&b, config)
+	w.writeSecurity(&b, config)
+
+	if config.AccessAnalyzerChecked {
+		w.writeExternalAccess(&b, config.AccessFindings)
+	}
+
+	if len(config.Warnings) > 0 {
+		b.WriteString(FormatSubHeader(w.t("Warnings")))
+		b.WriteString("\n")
+		for _, warning := range config.Warnings {
+			fmt.Fprintf(&b, "  [!] %s\n", warning)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(config.Errors) > 0 {
+		b.WriteString(FormatSubHeader(w.t("Checks Not Completed")))
+		b.WriteString("\n")
+		for _, e := range config.Errors {
+			fmt.Fprintf(&b, "  - %s\n", e)
+		}
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-configuration.txt"), b.String())
+}
+
+// writeNotifications writes the event notification section
+func (w *Writer) writeNotifications(b *strings.Builder, config *types.BucketConfiguration) {
+	b.WriteString(FormatSubHeader(w.t("Event Notifications")))
+	b.WriteString("\n")
+	if config.EventBridgeEnabled {
+		b.WriteString(w.t("EventBridge: enabled (all events)") + "\n")
+	} else {
+		b.WriteString(w.t("EventBridge: disabled") + "\n")
+	}
+	for _, n := range config.Notifications {
+		filter := "all keys"
+		if n.Prefix != "" || n.Suffix != "" {
+			filter = fmt.Sprintf("prefix=%q suffix=%q", w.key(n.Prefix), n.Suffix)
+		}
+		fmt.Fprintf(b, "%-6s %s [%s] %s\n", n.Type, n.Destination, filter, strings.Join(n.Events, ","))
+	}
+
+	if len(config.NotificationCoverage) > 0 {
+		b.WriteString("\nCoverage by prefix:\n")
+		for _, c := range config.NotificationCoverage {
+			status := "covered"
+			if len(c.Destinations) == 0 {
+				status = "no notifications"
+				if c.Expected {
+					status = "MISMATCH: expected to be event-driven but has no notifications"
+				}
+			}
+			fmt.Fprintf(b, "  %-40s %s\n", w.key(c.Prefix), status)
+			for _, d := range c.Destinations {
+				fmt.Fprintf(b, "      -> %s\n", d)
+			}
+		}
+	}
+	b.WriteString("\n")
+}
+
+// writeWebsite writes the website hosting and CORS section
+func (w *Writer) writeWebsite(b *strings.Builder, config *types.BucketConfiguration) {
+	b.WriteString(FormatSubHeader(w.t("Website Hosting and CORS")))
+	b.WriteString("\n")
+	if site := config.Website; site == nil {
+		b.WriteString(w.t("Website hosting: disabled") + "\n")
+	} else {
+		b.WriteString(w.t("Website hosting: ENABLED") + "\n")
+		if site.RedirectAllTo != "" {
+			fmt.Fprintf(b, "  Redirect all requests to: %s\n", site.RedirectAllTo)
+		}
+		if site.IndexDocument != "" {
+			fmt.Fprintf(b, "  Index document: %s\n", site.IndexDocument)
+		}
+		if site.ErrorDocument != "" {
+			fmt.Fprintf(b, "  Error document: %s\n", w.key(site.ErrorDocument))
+		}
+		for _, rule := range site.RoutingRules {
+			fmt.Fprintf(b, "  Routing rule: %s\n", rule)
+		}
+	}
+
+	if len(config.CORSRules) == 0 {
+		b.WriteString(w.t("CORS: no rules") + "\n")
+	}
+	for _, rule := range config.CORSRules {
+		fmt.Fprintf(b, "CORS rule %s: origins=%s methods=%s headers=%s max-age=%ds\n",
+			rule.ID,
+			strings.Join(rule.AllowedOrigins, ","),
+			strings.Join(rule.AllowedMethods, ","),
+			strings.Join(rule.AllowedHeaders, ","),
+			rule.MaxAgeSeconds)
+	}
+	b.WriteString("\n")
+}
+
+// writeSecurity writes the public access, default encryption and lifecycle
+// section
+func (w *Writer) writeSecurity(b *strings.Builder, config *types.BucketConfiguration) {
+	if !config.PublicAccessChecked && !config.EncryptionChecked && !config.LifecycleChecked {
+		return
+	}
+	b.WriteString(FormatSubHeader(w.t("Public Access, Encryption and Lifecycle")))
+	b.WriteString("\n")
+
+	if config.PublicAccessChecked {
+		if len(config.PublicAccessBlockOff) == 0 {
+			b.WriteString(w.t("Block Public Access: all settings on") + "\n")
+		} else {
+			b.WriteString(w.tf("Block Public Access: OFF for %s", strings.Join(config.PublicAccessBlockOff, ", ")) + "\n")
+		}
+		if config.PolicyPublic {
+			b.WriteString(w.t("Bucket policy: PUBLIC") + "\n")
+		} else {
+			b.WriteString(w.t("Bucket policy: not public") + "\n")
+		}
+	}
+
+	if config.EncryptionChecked {
+		if config.DefaultEncryption == "" {
+			b.WriteString(w.t("Default encryption: NONE") + "\n")
+		} else {
+			b.WriteString(w.tf("Default encryption: %s", config.DefaultEncryption) + "\n")
+		}
+	}
+
+	if config.LifecycleChecked {
+		if len(config.LifecycleRules) == 0 {
+			b.WriteString(w.t("Lifecycle: no rules") + "\n")
+		}
+		for _, rule := range config.LifecycleRules {
+			status := "disabled"
+			if rule.Enabled {
+				status = "enabled"
+			}
+			scope := "all keys"
+			if rule.Prefix != "" {
+				scope = fmt.Sprintf("prefix=%q", w.key(rule.Prefix))
+			}
+			fmt.Fprintf(b, "Lifecycle rule %s (%s) [%s]: %s\n", rule.ID, status, scope, strings.Join(rule.Actions, "; "))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// writeExternalAccess writes the IAM Access Analyzer section
+func (w *Writer) writeExternalAccess(b *strings.Builder, findings []types.AccessFinding) {
+	b.WriteString(FormatSubHeader(w.t("External Access (IAM Access Analyzer)")))
+	b.WriteString("\n")
+	if len(findings) == 0 {
+		b.WriteString("No active findings: not externally accessible via policy, ACL or access point\n\n")
+		return
+	}
+
+	b.WriteString(w.t("Externally accessible via policy: YES") + "\n")
+	for _, f := range findings {
+		scope := "external"
+		if f.IsPublic {
+			scope = "PUBLIC"
+		}
+		fmt.Fprintf(b, "  %s [%s] principal=%s actions=%s\n", f.ID, scope, f.Principal, strings.Join(f.Actions, ","))
+		if f.Condition != "" {
+			fmt.Fprintf(b, "      condition: %s\n", f.Condition)
+		}
+		if len(f.Sources) > 0 {
+			fmt.Fprintf(b, "      granted by: %s\n", strings.Join(f.Sources, ","))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// writeChargeableFeatures writes the separately billed feature inventory
+func (w *Writer) writeChargeableFeatures(b *strings.Builder, features []types.ChargeableFeature) {
+	b.WriteString(FormatSubHeader(w.t("Chargeable Features")))
+	b.WriteString("\n")
+	if len(features) == 0 {
+		b.WriteString("No analytics, metrics, inventory or Storage Lens configurations\n\n")
+		return
+	}
+
+	total := 0.0
+	fmt.Fprintf(b, "%-24s %-20s %-30s %12s %10s\n", w.t("Feature"), w.t("ID"), w.t("Scope"), w.t("Objects"), w.costColumn())
+	for _, f := range features {
+		objects := "-"
+		if f.MonitoredObjects > 0 {
+			objects = FormatNumber(f.MonitoredObjects)
+		}
+		fmt.Fprintf(b, "%-24s %-20s %-30s %12s %10s", f.Type, f.ID, w.key(f.Scope), objects, w.cost(f.MonthlyCost))
+		if f.Note != "" {
+			fmt.Fprintf(b, "  (%s)", f.Note)
+		}
+		b.WriteString("\n")
+		total += f.MonthlyCost
+	}
+	fmt.Fprintf(b, "Estimated total: %s/month (approximate, US East pricing)\n", w.cost(total))
+	if note := w.currencyNote(); note != "" {
+		b.WriteString(note + "\n")
+	}
+	b.WriteString("\n")
+}
+
+// inventoryConfigInput mirrors the PutBucketInventoryConfiguration request
+// shape accepted by `aws s3api put-bucket-inventory-configuration --cli-input-json`
+type inventoryConfigInput struct {
+	Bucket                 string `json:"Bucket"`
+	ID                     string `json:"Id"`
+	InventoryConfiguration struct {
+		Destination struct {
+			S3BucketDestination struct {
+				Bucket string `json:"Bucket"`
+				Format string `json:"Format"`
+				Prefix string `json:"Prefix"`
+			} `json:"S3BucketDestination"`
+		} `json:"Destination"`
+		IsEnabled              bool     `json:"IsEnabled"`
+		ID                     string   `json:"Id"`
+		IncludedObjectVersions string   `json:"IncludedObjectVersions"`
+		OptionalFields         []string `json:"OptionalFields"`
+		Schedule               struct {
+			Frequency string `json:"Frequency"`
+		} `json:"Schedule"`
+	} `json:"InventoryConfiguration"`
+}
+
+// WriteInventoryConfig writes the recommended inventory configuration as a
+// PutBucketInventoryConfiguration JSON payload. destBucket defaults to the
+// profiled bucket itself.
+func (w *Writer) WriteInventoryConfig(bucketName, destBucket string, rec *types.InventoryRecommendation) error {
+	if destBucket == "" {
+		destBucket = bucketName
+	}
+
+	var input inventoryConfigInput
+	input.Bucket = w.bucket(bucketName)
+	input.ID = "s3-profiler"
+	inv := &input.InventoryConfiguration
+	inv.Destination.S3BucketDestination.Bucket = types.NewLocation(w.bucket(destBucket), "").ARN()
+	inv.Destination.S3BucketDestination.Format = rec.Format
+	inv.Destination.S3BucketDestination.Prefix = "s3-inventory"
+	inv.IsEnabled = true
+	inv.ID = input.ID
+	inv.IncludedObjectVersions = "Current"
+	inv.OptionalFields = rec.OptionalFields
+	inv.Schedule.Frequency = rec.Frequency
+
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-inventory-config.json"), string(data)+"\n")
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// currencySymbols are the symbols costs are prefixed with; other currencies
+// are suffixed with their code
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"INR": "₹",
+}
+
+// Currency converts the USD costs of reports into another currency
+type Currency struct {
+	// Code is the ISO 4217 code, e.g. EUR
+	Code string
+	// Rate is the number of units of the currency per US dollar
+	Rate float64
+	// Source describes where the rate came from, e.g. "ECB reference rate
+	// of 2024-01-05"
+	Source string
+}
+
+// Format converts a USD amount and formats it in the currency
+func (c *Currency) Format(usd float64) string {
+	if c == nil || c.Code == "USD" {
+		return FormatCost(usd)
+	}
+	amount := usd * c.Rate
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	if symbol, ok := currencySymbols[c.Code]; ok {
+		return fmt.Sprintf("%s%s%.2f", sign, symbol, amount)
+	}
+	return fmt.Sprintf("%s%.2f %s", sign, amount, c.Code)
+}
+
+// ParseCurrencyCode validates and normalizes an ISO 4217 currency code
+func ParseCurrencyCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("invalid currency %q (expected an ISO 4217 code such as EUR)", code)
+	}
+	return code, nil
+}
+
+// cost formats a USD amount in the currency of the writer's reports
+func (w *Writer) cost(usd float64) string {
+	return w.currency.Format(usd)
+}
+
+// currencyNote describes the conversion of report costs, or returns ""
+// when they are in US dollars
+func (w *Writer) currencyNote() string {
+	if w.currency == nil || w.currency.Code == "USD" {
+		return ""
+	}
+	return w.tf("Costs converted to %s at %s per USD (%s)", w.currency.Code,
+		strconv.FormatFloat(w.currency.Rate, 'f', -1, 64), w.currency.Source)
+}
+
+// costColumn is the header of monthly cost columns
+func (w *Writer) costColumn() string {
+	if w.currency == nil || w.currency.Code == "USD" {
+		return w.t("$/month")
+	}
+	return w.tf("%s/month", w.currency.Code)
+}
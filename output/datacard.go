@@ -0,0 +1,111 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// unsafeFileChars matches characters replaced in data card file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DataCardName returns the file name of a dataset's data card
+func (w *Writer) DataCardName(bucketName string, card types.DataCard) string {
+	dataset := "bucket"
+	if card.Scope != "" {
+		dataset = unsafeFileChars.ReplaceAllString(strings.TrimSuffix(w.rawKey(card.Scope), "/"), "_")
+	}
+	return w.ReportName(bucketName, "-datacard-"+dataset+".md")
+}
+
+// WriteDataCard writes a Markdown data card describing one dataset
+func (w *Writer) WriteDataCard(bucketName string, card types.DataCard) error {
+	var b strings.Builder
+
+	location := "s3://" + w.bucket(card.Bucket) + "/" + w.key(card.Scope)
+	dataset := w.key(card.Scope)
+	if card.Scope == "" {
+		dataset = w.bucket(card.Bucket)
+	}
+	fmt.Fprintf(&b, "# %s\n\n", w.tf("Dataset: %s", dataset))
+	fmt.Fprintf(&b, "%s\n\n", w.tf("Generated by s3-profiler on %s.", FormatTime(card.Generated, w.location())))
+	if w.estimate != nil {
+		b.WriteString("> " + w.estimateNote(w.estimate) + "\n\n")
+	}
+
+	fmt.Fprintf(&b, "| %s | %s |\n|---|---|\n", w.t("Property"), w.t("Value"))
+	fmt.Fprintf(&b, "| %s | `%s` |\n", w.t("Location"), location)
+	fmt.Fprintf(&b, "| %s | %s |\n", w.t("Region"), card.Region)
+	fmt.Fprintf(&b, "| %s | %s |\n", w.t("Objects"), FormatNumber(card.ObjectCount))
+	fmt.Fprintf(&b, "| %s | %s |\n", w.t("Size"), FormatBytes(card.TotalSize))
+	fmt.Fprintf(&b, "| %s | %s |\n", w.t("Storage classes"), joinShares(card.StorageClasses, card.TotalSize))
+	fmt.Fprintf(&b, "| %s | %s |\n\n", w.t("Formats"), joinCounts(card.Formats))
+
+	b.WriteString("## " + w.t("Partition Scheme") + "\n\n")
+	if len(card.Patterns) == 0 {
+		b.WriteString(w.t("No date partitions detected.") + "\n\n")
+	} else {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n|---|---:|---:|---:|\n", w.t("Pattern"), w.t("Objects"), w.t("Size"), w.t("Coverage"))
+		for _, p := range card.Patterns {
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %.1f%% |\n", p.Pattern, FormatNumber(p.ObjectCount), FormatBytes(p.TotalSize), p.Coverage*100)
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%s\n\n", w.tf("%d date partitions from %s to %s.",
+			card.Partitions, card.FirstPartition.Format(dateLayout), card.LastPartition.Format(dateLayout)))
+	}
+
+	b.WriteString("## " + w.t("Schema") + "\n\n")
+	if card.Schema == nil {
+		b.WriteString(w.t("No schema sampled; run with --sample-content or --parquet-stats.") + "\n\n")
+	} else {
+		fmt.Fprintf(&b, "%s\n\n", w.tf("Read from %s (%s).", "`"+w.key(card.Schema.Source)+"`", card.Schema.Format))
+		fmt.Fprintf(&b, "| %s | %s |\n|---|---|\n", w.t("Column"), w.t("Type"))
+		for _, column := range card.Schema.Columns {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", column.Name, column.Type)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## " + w.t("Freshness") + "\n\n")
+	fmt.Fprintf(&b, "- %s: %s (%s)\n", w.t("Newest object written"), FormatTime(card.Newest, w.location()),
+		w.tf("%d days before this profile", max(0, int(card.Generated.Sub(card.Newest).Hours()/24))))
+	fmt.Fprintf(&b, "- %s: %s\n", w.t("Oldest object written"), FormatTime(card.Oldest, w.location()))
+	if !card.LastPartition.IsZero() {
+		fmt.Fprintf(&b, "- %s: %s\n", w.t("Latest partition"), card.LastPartition.Format(dateLayout))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## " + w.t("Retention Recommendation") + "\n\n")
+	b.WriteString(card.Retention + "\n\n")
+	fmt.Fprintf(&b, "- %s: %s (%s)\n", w.t("Written over 90 days ago"), FormatBytes(card.SizeOver90Days), FormatPercent(card.SizeOver90Days, card.TotalSize))
+	fmt.Fprintf(&b, "- %s: %s (%s)\n\n", w.t("Written over a year ago"), FormatBytes(card.SizeOver1Year), FormatPercent(card.SizeOver1Year, card.TotalSize))
+
+	b.WriteString("## " + w.t("Sample Keys") + "\n\n")
+	for _, key := range card.SampleKeys {
+		fmt.Fprintf(&b, "- `%s`\n", w.key(key))
+	}
+
+	return w.writeFile(w.DataCardName(bucketName, card), b.String())
+}
+
+// joinShares renders storage classes by descending size with their share
+func joinShares(classes map[string]types.StorageClassStats, total int64) string {
+	names := sortedStorageClasses(classes)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %s", name, FormatPercent(classes[name].Size, total))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// joinCounts renders counts by descending value, e.g. "parquet (120), json (3)"
+func joinCounts(counts map[string]int64) string {
+	names := sortedByCount(counts)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%s)", name, FormatNumber(counts[name]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// FormatDiff renders the summary changes of buckets between two runs.
+// Buckets found in only one of the runs are listed by name. top limits the
+// partitions listed per section (0 = default).
+func FormatDiff(diffs []*types.SnapshotDiff, removed, added []string, top int, loc *time.Location) string {
+	if top <= 0 {
+		top = defaultCompareRows
+	}
+
+	var b strings.Builder
+	if len(diffs)+len(removed)+len(added) > 1 {
+		writeDiffOverview(&b, diffs, removed, added)
+	}
+	for _, d := range diffs {
+		writeSnapshotDiff(&b, d, top, loc)
+	}
+	return b.String()
+}
+
+// writeDiffOverview lists the size change of every bucket of the runs
+func writeDiffOverview(b *strings.Builder, diffs []*types.SnapshotDiff, removed, added []string) {
+	b.WriteString(FormatHeader("Run Diff"))
+	b.WriteString("\n\n")
+	fmt.Fprintf(b, "%-40s %14s %14s %15s %12s\n", "Bucket", "Before", "After", "Change", "Objects")
+	var oldSize, newSize, objects int64
+	for _, d := range diffs {
+		fmt.Fprintf(b, "%-40s %14s %14s %15s %12s\n",
+			d.Bucket,
+			FormatBytes(d.OldSize),
+			FormatBytes(d.NewSize),
+			formatSizeDelta(d.NewSize-d.OldSize),
+			formatCountDelta(d.NewObjects-d.OldObjects))
+		oldSize += d.OldSize
+		newSize += d.NewSize
+		objects += d.NewObjects - d.OldObjects
+	}
+	fmt.Fprintf(b, "%-40s %14s %14s %15s %12s\n", "Total", FormatBytes(oldSize), FormatBytes(newSize),
+		formatSizeDelta(newSize-oldSize), formatCountDelta(objects))
+	if len(added) > 0 {
+		fmt.Fprintf(b, "Only in the current run: %s\n", strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(b, "Only in the previous run: %s\n", strings.Join(removed, ", "))
+	}
+	b.WriteString("\n")
+}
+
+// writeSnapshotDiff writes one bucket's changes
+func writeSnapshotDiff(b *strings.Builder, d *types.SnapshotDiff, top int, loc *time.Location) {
+	b.WriteString(FormatHeader(fmt.Sprintf("Run Diff: %s", d.Bucket)))
+	b.WriteString("\n\n")
+	if d.Estimated {
+		b.WriteString("*** ESTIMATE ***\nAt least one run was truncated by --limit; changes include keys that were\nsimply not listed and are not actual growth or deletion.\n\n")
+	}
+
+	fmt.Fprintf(b, "Previous Run:    %s\n", FormatTime(d.OldGenerated, loc))
+	fmt.Fprintf(b, "Current Run:     %s\n\n", FormatTime(d.NewGenerated, loc))
+	fmt.Fprintf(b, "Total Objects:   %s -> %s (%s)\n", FormatNumber(d.OldObjects), FormatNumber(d.NewObjects), formatCountDelta(d.NewObjects-d.OldObjects))
+	fmt.Fprintf(b, "Total Size:      %s -> %s (%s)\n", FormatBytes(d.OldSize), FormatBytes(d.NewSize), formatSizeDelta(d.NewSize-d.OldSize))
+	if d.OldCost > 0 && d.NewCost > 0 {
+		fmt.Fprintf(b, "Est. Cost/Month: %s -> %s (%s)\n", FormatCost(d.OldCost), FormatCost(d.NewCost), formatCostDelta(d.NewCost-d.OldCost))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(FormatSubHeader("Change by Storage Class"))
+	b.WriteString("\n")
+	if d.ClassesMissing {
+		b.WriteString("Not recorded: a snapshot was written by an older release\n")
+	} else {
+		fmt.Fprintf(b, "%-22s %14s %14s %15s %12s\n", "Storage Class", "Before", "After", "Change", "Objects")
+		for _, c := range d.Classes {
+			fmt.Fprintf(b, "%-22s %14s %14s %15s %12s\n",
+				c.StorageClass,
+				FormatBytes(c.OldSize),
+				FormatBytes(c.NewSize),
+				formatSizeDelta(c.NewSize-c.OldSize),
+				formatCountDelta(c.NewObjects-c.OldObjects))
+		}
+	}
+
+	writePartitionList(b, "New Partitions", d.NewPartitions, top)
+	writePartitionList(b, "Removed Partitions", d.RemovedPartitions, top)
+	b.WriteString("\n")
+}
+
+// writePartitionList lists partitions with their object count and size
+func writePartitionList(b *strings.Builder, title string, partitions []types.PrefixStats, top int) {
+	b.WriteString("\n")
+	b.WriteString(FormatSubHeader(fmt.Sprintf("%s (%d)", title, len(partitions))))
+	b.WriteString("\n")
+	if len(partitions) == 0 {
+		b.WriteString("None\n")
+		return
+	}
+
+	shown := min(len(partitions), top)
+	fmt.Fprintf(b, "%-50s %12s %14s\n", "Partition", "Objects", "Size")
+	for _, p := range partitions[:shown] {
+		fmt.Fprintf(b, "%-50s %12s %14s\n", p.Prefix, FormatNumber(p.ObjectCount), FormatBytes(p.Size))
+	}
+	if len(partitions) > shown {
+		fmt.Fprintf(b, "... %d more not shown (use --top to change)\n", len(partitions)-shown)
+	}
+}
+
+// formatCostDelta formats a signed monthly cost change
+func formatCostDelta(delta float64) string {
+	if delta < 0 {
+		return "-" + FormatCost(-delta)
+	}
+	return "+" + FormatCost(delta)
+}
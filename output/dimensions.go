@@ -0,0 +1,48 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// WriteDimensions writes aggregate tables for user-defined key dimensions
+func (w *Writer) WriteDimensions(bucketName string, tables []types.DimensionTable) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.tf("Dimension Analysis: %s", w.bucket(bucketName))))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	for _, table := range tables {
+		b.WriteString(FormatSubHeader(w.tf("Dimension: %s", table.Name)))
+		b.WriteString("\n")
+
+		rows := append([]types.DimensionStats(nil), table.Rows...)
+		sortTable(rows, w.opts.Table, func(r types.DimensionStats) tableRow {
+			return tableRow{name: r.Value, count: r.ObjectCount, size: r.TotalSize}
+		})
+		shown := w.opts.Table.visibleRows(len(rows), 0)
+
+		fmt.Fprintf(&b, "%-30s %12s %14s %12s %10s  %-10s  %-10s\n",
+			w.t("Value"), w.t("Objects"), w.t("Size"), w.costColumn(), w.t("Avg age"), w.t("Oldest"), w.t("Newest"))
+		for _, r := range rows[:shown] {
+			fmt.Fprintf(&b, "%-30s %12s %14s %12s %9.0fd  %-10s  %-10s\n",
+				w.key(r.Value),
+				FormatNumber(r.ObjectCount),
+				FormatBytes(r.TotalSize),
+				w.cost(r.MonthlyCost),
+				r.AverageAgeDays,
+				r.Oldest.In(w.location()).Format("2006-01-02"),
+				r.Newest.In(w.location()).Format("2006-01-02"))
+		}
+		writeMoreFooter(&b, shown, len(rows))
+		if table.Unmatched > 0 {
+			b.WriteString(w.tf("%s object(s) did not match this dimension", FormatNumber(table.Unmatched)) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-dimensions.txt"), b.String())
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// encryptionExtension is appended to the names of encrypted files
+const encryptionExtension = ".age"
+
+// Encryption encrypts report and export files to age recipients. A nil
+// Encryption writes plain files.
+type Encryption struct {
+	recipients []age.Recipient
+}
+
+// ParseEncryption converts an --encrypt-output value into an Encryption.
+// The value is "age:" followed by a recipient (age1...) or the path of a
+// recipients file with one recipient per line and # comments, as read by
+// age -R. An empty value disables encryption.
+func ParseEncryption(value string) (*Encryption, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	scheme, target, ok := strings.Cut(value, ":")
+	if !ok || scheme != "age" || target == "" {
+		return nil, fmt.Errorf("unknown output encryption %q (expected age:<recipient or recipients file>)", value)
+	}
+
+	var recipients []age.Recipient
+	if strings.HasPrefix(target, "age1") {
+		recipient, err := age.ParseX25519Recipient(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	} else {
+		file, err := os.Open(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open recipients file: %w", err)
+		}
+		defer file.Close()
+		if recipients, err = age.ParseRecipients(file); err != nil {
+			return nil, fmt.Errorf("failed to read recipients from %s: %w", target, err)
+		}
+	}
+
+	return &Encryption{recipients: recipients}, nil
+}
+
+// Extension returns the file name suffix for encrypted files
+func (e *Encryption) Extension() string {
+	if e == nil {
+		return ""
+	}
+	return encryptionExtension
+}
+
+// Recipients returns the number of recipients files are encrypted to
+func (e *Encryption) Recipients() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.recipients)
+}
+
+// encrypt wraps w so everything written to it is encrypted; closing the
+// returned writer finishes the age payload but leaves w open
+func (e *Encryption) encrypt(w io.Writer) (io.WriteCloser, error) {
+	return age.Encrypt(w, e.recipients...)
+}
+
+// writeEncrypted writes content to path as an age file
+func (e *Encryption) writeEncrypted(path, content string) (err error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	enc, err := e.encrypt(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f := &compressedFile{WriteCloser: enc, file: file}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+// FileName returns the name a report or export is stored under, with the
+// encryption extension when files are encrypted
+func (w *Writer) FileName(name string) string {
+	return name + w.opts.Encryption.Extension()
+}
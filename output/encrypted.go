@@ -0,0 +1,49 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// maxListedEncryptedPrefixes is the default number of rows of the
+// client-encrypted content table
+const maxListedEncryptedPrefixes = 20
+
+// writeEncryptedContent writes the prefixes holding objects that generic
+// consumers cannot read without keys kept outside S3
+func (w *Writer) writeEncryptedContent(b *strings.Builder, prefixes []types.EncryptedPrefix, sampled bool) {
+	b.WriteString(FormatSubHeader(w.t("Client-Encrypted Content")))
+	b.WriteString("\n")
+
+	prefixes = append([]types.EncryptedPrefix(nil), prefixes...)
+	sortTable(prefixes, w.opts.Table, func(p types.EncryptedPrefix) tableRow {
+		return tableRow{name: p.Prefix, count: p.ObjectCount, size: p.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(prefixes), maxListedEncryptedPrefixes)
+
+	fmt.Fprintf(b, "%-30s %12s %12s %12s %10s %10s %12s %8s\n",
+		w.t("Prefix"), w.t("Objects"), w.t("Size"), w.t("By Name"), w.t("SSE-C"), w.t("Client"), w.t("Unreadable"), "%")
+	for _, p := range prefixes[:shown] {
+		fmt.Fprintf(b, "%-30s %12s %12s %12s %10s %10s %12s %8s\n",
+			w.key(p.Prefix),
+			FormatNumber(p.ObjectCount),
+			FormatBytes(p.Size),
+			FormatNumber(p.Suffix),
+			FormatNumber(int64(p.SSECustomer+0.5)),
+			FormatNumber(int64(p.ClientSide+0.5)),
+			FormatNumber(p.Unreadable),
+			FormatPercent(p.Unreadable, p.ObjectCount))
+	}
+	writeMoreFooter(b, shown, len(prefixes))
+
+	b.WriteString(w.t("By Name: objects named like encrypted files (.gpg, .pgp, .age, .enc, .aes, .crypt, .p7m).") + "\n")
+	if sampled {
+		b.WriteString(w.t("SSE-C and Client are estimated from the HeadObject sample: SSE-C objects cannot be read without the customer key, client-side encrypted objects carry an encryption envelope in their metadata.") + "\n")
+	} else {
+		b.WriteString(w.t("Pass --enrich to also estimate SSE-C and client-side encrypted objects from HeadObject samples.") + "\n")
+	}
+	b.WriteString(w.t("Crawlers, query engines and content sampling cannot read these objects; exclude them from catalogs, and keep their keys for as long as lifecycle rules retain the data.") + "\n")
+	b.WriteString("\n")
+}
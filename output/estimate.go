@@ -0,0 +1,48 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// SetEstimate marks every report written afterwards as covering only part
+// of the bucket; nil clears the mark
+func (w *Writer) SetEstimate(estimate *types.ListingEstimate) {
+	w.estimate = estimate
+}
+
+// estimateNote describes a truncated listing in one or two sentences
+func (w *Writer) estimateNote(e *types.ListingEstimate) string {
+	if e.Interrupted {
+		return w.tf("Profiling was interrupted after listing %s objects (%s). This report is partial: it covers the listed keys only, and all totals are lower bounds.",
+			FormatNumber(e.ListedObjects), FormatBytes(e.ListedSize))
+	}
+	if e.BucketObjects == 0 {
+		return w.tf("Listing stopped at the --limit of %s objects and the bucket's total object count is unknown (no CloudWatch storage metrics). All totals are lower bounds.",
+			FormatNumber(e.Limit))
+	}
+	return w.tf("Listing stopped at the --limit of %s objects, about %.2f%% of the %s objects reported by CloudWatch on %s. Figures cover the listed keys only; extrapolated totals assume they are representative.",
+		FormatNumber(e.Limit), e.Fraction*100, FormatNumber(e.BucketObjects), e.MetricsDate.Format("2006-01-02"))
+}
+
+// writeEstimateBanner writes the estimate notice at the top of a report
+func (w *Writer) writeEstimateBanner(b *strings.Builder) {
+	if w.estimate == nil {
+		return
+	}
+
+	title := w.t("ESTIMATE")
+	if w.estimate.Interrupted {
+		title = w.t("PARTIAL")
+	}
+	b.WriteString("*** " + title + " ***\n")
+	b.WriteString(w.estimateNote(w.estimate))
+	b.WriteString("\n")
+	if w.estimate.BucketObjects > 0 {
+		b.WriteString(w.tf("Extrapolated totals: ~%s objects, ~%s, ~%s/month (est.)",
+			FormatNumber(w.estimate.BucketObjects), FormatBytes(w.estimate.Size), w.cost(w.estimate.Cost)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
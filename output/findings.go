@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// severityRank orders severity levels, most severe highest; unrated
+// findings rank with info
+func severityRank(severity types.Severity) int {
+	switch severity {
+	case types.SeverityCritical:
+		return 2
+	case types.SeverityWarn:
+		return 1
+	}
+	return 0
+}
+
+// severityLevel returns the SARIF level of a finding, from its severity
+// when it was rated and from its rule otherwise
+func severityLevel(f types.Finding) string {
+	switch f.Severity {
+	case types.SeverityCritical:
+		return "error"
+	case types.SeverityWarn:
+		return "warning"
+	case types.SeverityInfo:
+		return "note"
+	}
+	return f.Rule.Level
+}
+
+// findingSeverity returns the severity of a finding; unrated findings
+// are info
+func findingSeverity(f types.Finding) types.Severity {
+	if f.Severity == "" {
+		return types.SeverityInfo
+	}
+	return f.Severity
+}
+
+// findingURI returns the s3:// URI of a finding's bucket or prefix
+func (w *Writer) findingURI(f types.Finding) string {
+	loc := types.NewLocation(w.bucket(f.Bucket), f.Prefix)
+	loc.Prefix = w.key(loc.Prefix)
+	return loc.URI()
+}
+
+// findingCounts summarizes findings by severity, e.g. "1 critical, 2 warn,
+// 0 info"
+func (w *Writer) findingCounts(findings []types.Finding) string {
+	var counts [3]int
+	for _, f := range findings {
+		counts[severityRank(f.Severity)]++
+	}
+	return w.tf("%d critical, %d warn, %d info", counts[2], counts[1], counts[0])
+}
+
+// writeFindings writes the findings table of the bucket summary, most
+// severe first
+func (w *Writer) writeFindings(b *strings.Builder, findings []types.Finding) {
+	b.WriteString(FormatSubHeader(w.t("Findings")))
+	b.WriteString("\n")
+	b.WriteString(w.findingCounts(findings) + "\n\n")
+
+	shown := w.opts.Table.visibleRows(len(findings), 0)
+	fmt.Fprintf(b, "%-9s %-7s %-40s %s\n", w.t("Severity"), w.t("Rule"), w.t("Location"), w.t("Finding"))
+	for _, f := range findings[:shown] {
+		fmt.Fprintf(b, "%-9s %-7s %-40s %s\n", strings.ToUpper(string(findingSeverity(f))), f.Rule.ID, w.findingURI(f), f.Message)
+	}
+	writeMoreFooter(b, shown, len(findings))
+	b.WriteString("\n")
+}
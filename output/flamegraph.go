@@ -0,0 +1,146 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// FlameGraphFormat selects the flame graph export format
+type FlameGraphFormat string
+
+const (
+	FlameGraphNone       FlameGraphFormat = ""
+	FlameGraphFolded     FlameGraphFormat = "folded"
+	FlameGraphSpeedscope FlameGraphFormat = "speedscope"
+)
+
+// ParseFlameGraphFormat converts a flag value into a FlameGraphFormat
+func ParseFlameGraphFormat(value string) (FlameGraphFormat, error) {
+	switch FlameGraphFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case FlameGraphNone:
+		return FlameGraphNone, nil
+	case FlameGraphFolded:
+		return FlameGraphFolded, nil
+	case FlameGraphSpeedscope:
+		return FlameGraphSpeedscope, nil
+	default:
+		return FlameGraphNone, fmt.Errorf("unknown flame graph format %q (expected folded or speedscope)", value)
+	}
+}
+
+// FlameGraphName returns the file name of a bucket's flame graph export
+func (w *Writer) FlameGraphName(bucketName string, format FlameGraphFormat) string {
+	if format == FlameGraphSpeedscope {
+		return w.ReportName(bucketName, "-prefixes.speedscope.json")
+	}
+	return w.ReportName(bucketName, "-prefixes.folded")
+}
+
+// WriteFlameGraph exports the prefix tree weighted by bytes
+func (w *Writer) WriteFlameGraph(bucketName string, root *types.PrefixNode, format FlameGraphFormat) error {
+	var content string
+	var err error
+
+	switch format {
+	case FlameGraphSpeedscope:
+		content, err = w.speedscope(bucketName, root)
+	default:
+		content = w.folded(bucketName, root)
+	}
+	if err != nil {
+		return err
+	}
+
+	return w.writeFile(w.FlameGraphName(bucketName, format), content)
+}
+
+// folded renders the tree as folded stacks ("a;b;c bytes"), the input
+// format of flamegraph.pl, inferno and speedscope
+func (w *Writer) folded(bucketName string, root *types.PrefixNode) string {
+	var b strings.Builder
+	w.walkStacks(root, []string{w.frameName(w.bucket(bucketName))}, func(stack []string, bytes int64) {
+		fmt.Fprintf(&b, "%s %d\n", strings.Join(stack, ";"), bytes)
+	})
+	return b.String()
+}
+
+// speedscope renders the tree as a speedscope sampled profile in bytes
+func (w *Writer) speedscope(bucketName string, root *types.PrefixNode) (string, error) {
+	type frame struct {
+		Name string `json:"name"`
+	}
+	type profile struct {
+		Type       string  `json:"type"`
+		Name       string  `json:"name"`
+		Unit       string  `json:"unit"`
+		StartValue int64   `json:"startValue"`
+		EndValue   int64   `json:"endValue"`
+		Samples    [][]int `json:"samples"`
+		Weights    []int64 `json:"weights"`
+	}
+	type file struct {
+		Schema string `json:"$schema"`
+		Shared struct {
+			Frames []frame `json:"frames"`
+		} `json:"shared"`
+		Profiles []profile `json:"profiles"`
+		Name     string    `json:"name"`
+		Exporter string    `json:"exporter"`
+	}
+
+	name := w.bucket(bucketName)
+	if w.estimate != nil {
+		name += fmt.Sprintf(" (estimate: first %s objects)", FormatNumber(w.estimate.ListedObjects))
+	}
+	out := file{
+		Schema:   "https://www.speedscope.app/file-format-schema.json",
+		Name:     name,
+		Exporter: "s3-profiler",
+	}
+	p := profile{Type: "sampled", Name: name + " prefixes", Unit: "bytes", EndValue: root.Size}
+
+	frameIndex := make(map[string]int)
+	w.walkStacks(root, []string{w.frameName(name)}, func(stack []string, bytes int64) {
+		sample := make([]int, len(stack))
+		for i, f := range stack {
+			idx, ok := frameIndex[f]
+			if !ok {
+				idx = len(out.Shared.Frames)
+				frameIndex[f] = idx
+				out.Shared.Frames = append(out.Shared.Frames, frame{Name: f})
+			}
+			sample[i] = idx
+		}
+		p.Samples = append(p.Samples, sample)
+		p.Weights = append(p.Weights, bytes)
+	})
+	out.Profiles = []profile{p}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// walkStacks calls fn for every node holding objects directly, with the
+// stack of frame names from the root to that node
+func (w *Writer) walkStacks(node *types.PrefixNode, stack []string, fn func([]string, int64)) {
+	if node.SelfSize > 0 {
+		fn(stack, node.SelfSize)
+	}
+	for _, child := range node.Children {
+		w.walkStacks(child, append(stack[:len(stack):len(stack)], w.frameName(w.key(child.Name))), fn)
+	}
+}
+
+// frameName makes a prefix segment safe for the folded stack format
+func (w *Writer) frameName(name string) string {
+	if name == "" {
+		name = "(empty)"
+	}
+	return strings.NewReplacer(";", "_", " ", "_", "\n", "_").Replace(name)
+}
@@ -0,0 +1,118 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const headerWidth = 60
+
+// FormatHeader formats a section header with separator lines
+func FormatHeader(title string) string {
+	separator := strings.Repeat("=", headerWidth)
+	return fmt.Sprintf("%s\n%s\n%s", separator, title, separator)
+}
+
+// FormatSubHeader formats a subsection header with an underline
+func FormatSubHeader(title string) string {
+	return fmt.Sprintf("%s\n%s", title, strings.Repeat("-", displayWidth(title)))
+}
+
+// FormatBytes converts a byte count to a human-readable string
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatNumber formats an integer with thousands separators
+func FormatNumber(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	s := fmt.Sprintf("%d", n)
+	if len(s) <= 3 {
+		return sign + s
+	}
+
+	var b strings.Builder
+	pre := len(s) % 3
+	if pre > 0 {
+		b.WriteString(s[:pre])
+	}
+	for i := pre; i < len(s); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(s[i : i+3])
+	}
+
+	return sign + b.String()
+}
+
+// FormatPercent formats a part/total ratio as a percentage
+func FormatPercent(part, total int64) string {
+	if total == 0 {
+		return "0.00%"
+	}
+	return fmt.Sprintf("%.2f%%", float64(part)/float64(total)*100)
+}
+
+// FormatCost formats a dollar amount
+func FormatCost(cost float64) string {
+	return fmt.Sprintf("$%.2f", cost)
+}
+
+// TimeLayout is used for every timestamp rendered in reports; it always
+// includes the numeric offset and zone name
+const TimeLayout = "2006-01-02 15:04:05 -0700 MST"
+
+// FormatTime formats a timestamp for reports in the given location
+// (UTC when loc is nil)
+func FormatTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return "N/A"
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(TimeLayout)
+}
+
+// FormatBar renders a simple text bar proportional to part/total
+func FormatBar(part, total int64, width int) string {
+	if total == 0 {
+		return ""
+	}
+	n := int(float64(part) / float64(total) * float64(width))
+	if n == 0 && part > 0 {
+		n = 1
+	}
+	return strings.Repeat("#", n)
+}
+
+// EscapeKey escapes control characters and invalid UTF-8 in an object key
+// using Go string escapes (e.g. \x00, \n); other keys are returned as is
+func EscapeKey(key string) string {
+	if utf8.ValidString(key) && strings.IndexFunc(key, unicode.IsControl) < 0 {
+		return key
+	}
+	quoted := strconv.Quote(key)
+	return quoted[1 : len(quoted)-1]
+}
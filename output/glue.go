@@ -0,0 +1,83 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// gluePartitionBatchSize is the most partitions one BatchCreatePartition
+// request may create
+const gluePartitionBatchSize = 100
+
+// batchCreatePartitionInput mirrors the BatchCreatePartition request shape
+// accepted by `aws glue batch-create-partition --cli-input-json`
+type batchCreatePartitionInput struct {
+	DatabaseName       string           `json:"DatabaseName"`
+	TableName          string           `json:"TableName"`
+	PartitionInputList []partitionInput `json:"PartitionInputList"`
+}
+
+// partitionInput is one partition to create
+type partitionInput struct {
+	Values            []string       `json:"Values"`
+	StorageDescriptor map[string]any `json:"StorageDescriptor,omitempty"`
+}
+
+// WriteGluePartitions writes BatchCreatePartition requests for the
+// partitions missing from a Glue table, and a shell script that submits
+// them with the AWS CLI
+func (w *Writer) WriteGluePartitions(bucketName, region string, reg *types.GlueRegistration) error {
+	table := reg.Table
+	var requests []batchCreatePartitionInput
+	for start := 0; start < len(reg.Missing); start += gluePartitionBatchSize {
+		batch := reg.Missing[start:min(start+gluePartitionBatchSize, len(reg.Missing))]
+		request := batchCreatePartitionInput{DatabaseName: table.Database, TableName: table.Name}
+		for _, p := range batch {
+			input := partitionInput{Values: p.Values}
+			// Partitions inherit the table's columns, formats and SerDe;
+			// only the location differs
+			if table.StorageDescriptor != nil {
+				input.StorageDescriptor = maps.Clone(table.StorageDescriptor)
+				input.StorageDescriptor["Location"] = "s3://" + w.bucket(bucketName) + "/" + w.rawKey(p.Prefix)
+			}
+			request.PartitionInputList = append(request.PartitionInputList, input)
+		}
+		requests = append(requests, request)
+	}
+
+	data, err := json.MarshalIndent(requests, "", "  ")
+	if err != nil {
+		return err
+	}
+	payloadName := w.ReportName(bucketName, "-glue-partitions.json")
+	if err := w.writeFile(payloadName, string(data)+"\n"); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Registers %d partition(s) missing from Glue table %s.%s\n", len(reg.Missing), table.Database, table.Name)
+	fmt.Fprintf(&b, "# in %d BatchCreatePartition request(s). Requires the AWS CLI and jq", len(requests))
+	if w.opts.Encryption != nil {
+		b.WriteString(", and age with AGE_IDENTITY set")
+	}
+	b.WriteString(".\n")
+	b.WriteString("# Partitions that fail individually are listed under Errors in the output.\n")
+	b.WriteString("set -e\n")
+	b.WriteString("cd \"$(dirname \"$0\")\"\n")
+	// The payload is read under its final name; encrypted payloads are
+	// decrypted on the fly with the identity in AGE_IDENTITY
+	if w.opts.Encryption != nil {
+		fmt.Fprintf(&b, "age -d -i \"${AGE_IDENTITY:?set AGE_IDENTITY to your age identity file}\" %s | jq -c '.[]' | while read -r request; do\n", w.FileName(payloadName))
+	} else {
+		fmt.Fprintf(&b, "jq -c '.[]' %s | while read -r request; do\n", payloadName)
+	}
+	fmt.Fprintf(&b, "  aws glue batch-create-partition --region %s --cli-input-json \"$request\"\n", region)
+	b.WriteString("done\n")
+
+	return w.writeFile(w.ReportName(bucketName, "-glue-partitions.sh"), b.String())
+}
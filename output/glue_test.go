@@ -0,0 +1,77 @@
+package output
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// TestWriteGluePartitionsScript checks the script reads the requests file
+// under the name it was written with
+func TestWriteGluePartitionsScript(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryption, err := ParseEncryption("age:" + identity.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		encryption *Encryption
+		wantRead   string
+	}{
+		{"plain", nil, "jq -c '.[]' sales-glue-partitions.json |"},
+		{"encrypted", encryption, `age -d -i "${AGE_IDENTITY:?set AGE_IDENTITY to your age identity file}" sales-glue-partitions.json.age | jq`},
+	}
+	reg := &types.GlueRegistration{
+		Table:   &types.GlueTable{Database: "analytics", Name: "sales", StorageDescriptor: map[string]any{"Location": "s3://sales/"}},
+		Missing: []types.GluePartition{{Values: []string{"2024"}, Prefix: "year=2024/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w := NewWriter(dir, Options{Encryption: tt.encryption})
+			if err := w.WriteGluePartitions("sales", "us-east-1", reg); err != nil {
+				t.Fatal(err)
+			}
+
+			script := readOutput(t, filepath.Join(dir, w.FileName("sales-glue-partitions.sh")), identity)
+			if !strings.Contains(script, tt.wantRead) {
+				t.Errorf("script does not read the requests with %q:\n%s", tt.wantRead, script)
+			}
+			payload := readOutput(t, filepath.Join(dir, w.FileName("sales-glue-partitions.json")), identity)
+			if !strings.Contains(payload, `"s3://sales/year=2024/"`) {
+				t.Errorf("requests do not hold the partition location:\n%s", payload)
+			}
+		})
+	}
+}
+
+// readOutput reads an output file, decrypting .age files with identity
+func readOutput(t *testing.T, path string, identity age.Identity) string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if strings.HasSuffix(path, encryptionExtension) {
+		if r, err = age.Decrypt(file, identity); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
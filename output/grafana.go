@@ -0,0 +1,154 @@
+package output
+
+import "encoding/json"
+
+// grafanaDatasourceInput names the datasource Grafana asks for when a
+// dashboard exported without a datasource UID is imported
+const grafanaDatasourceInput = "DS_S3_PROFILER"
+
+// grafanaPostgres is Grafana's PostgreSQL datasource plugin id
+const grafanaPostgres = "grafana-postgresql-datasource"
+
+// grafanaPanel describes one dashboard panel bound to a results view
+type grafanaPanel struct {
+	title  string
+	kind   string
+	format string
+	unit   string
+	query  string
+	width  int
+	height int
+	// stacked stacks the series of a time series panel
+	stacked bool
+}
+
+// grafanaPanels query the bucket_growth, cost_by_class and
+// partition_freshness views created in the results database
+var grafanaPanels = []grafanaPanel{
+	{
+		title:  "Bucket size",
+		kind:   "timeseries",
+		format: "time_series",
+		unit:   "bytes",
+		query:  `SELECT generated AS time, bucket AS metric, total_size AS value FROM bucket_growth WHERE bucket IN ($bucket) AND $__timeFilter(generated) ORDER BY 1`,
+		width:  12,
+		height: 8,
+	},
+	{
+		title:  "Growth since previous run",
+		kind:   "timeseries",
+		format: "time_series",
+		unit:   "bytes",
+		query:  `SELECT generated AS time, bucket AS metric, size_change AS value FROM bucket_growth WHERE bucket IN ($bucket) AND $__timeFilter(generated) AND size_change IS NOT NULL ORDER BY 1`,
+		width:  12,
+		height: 8,
+	},
+	{
+		title:  "Objects",
+		kind:   "timeseries",
+		format: "time_series",
+		unit:   "short",
+		query:  `SELECT generated AS time, bucket AS metric, total_objects AS value FROM bucket_growth WHERE bucket IN ($bucket) AND $__timeFilter(generated) ORDER BY 1`,
+		width:  12,
+		height: 8,
+	},
+	{
+		title:   "Monthly cost by storage class",
+		kind:    "timeseries",
+		format:  "time_series",
+		unit:    "currencyUSD",
+		query:   `SELECT generated AS time, storage_class AS metric, SUM(cost) AS value FROM cost_by_class WHERE bucket IN ($bucket) AND $__timeFilter(generated) GROUP BY 1, 2 ORDER BY 1`,
+		width:   12,
+		height:  8,
+		stacked: true,
+	},
+	{
+		title:  "Latest partitions",
+		kind:   "table",
+		format: "table",
+		query:  `SELECT bucket, prefix, date, newest, hours_since_write, objects, size FROM partition_freshness WHERE bucket IN ($bucket) ORDER BY date DESC NULLS LAST, prefix LIMIT 200`,
+		width:  24,
+		height: 10,
+	},
+}
+
+// GrafanaDashboard renders a Grafana dashboard over the results database
+// views. With an empty datasourceUID the dashboard declares an import
+// input, so Grafana asks for the PostgreSQL datasource on import.
+func GrafanaDashboard(datasourceUID string) ([]byte, error) {
+	uid := datasourceUID
+	if uid == "" {
+		uid = "${" + grafanaDatasourceInput + "}"
+	}
+	datasource := map[string]any{"type": grafanaPostgres, "uid": uid}
+
+	var panels []map[string]any
+	x, y, rowHeight := 0, 0, 0
+	for i, p := range grafanaPanels {
+		if x+p.width > 24 {
+			x, y, rowHeight = 0, y+rowHeight, 0
+		}
+		panel := map[string]any{
+			"id":         i + 1,
+			"title":      p.title,
+			"type":       p.kind,
+			"datasource": datasource,
+			"gridPos":    map[string]int{"x": x, "y": y, "w": p.width, "h": p.height},
+			"targets": []map[string]any{{
+				"refId":      "A",
+				"datasource": datasource,
+				"editorMode": "code",
+				"format":     p.format,
+				"rawQuery":   true,
+				"rawSql":     p.query,
+			}},
+		}
+		defaults := map[string]any{}
+		if p.unit != "" {
+			defaults["unit"] = p.unit
+		}
+		if p.stacked {
+			defaults["custom"] = map[string]any{"stacking": map[string]string{"mode": "normal", "group": "A"}, "fillOpacity": 30}
+		}
+		panel["fieldConfig"] = map[string]any{"defaults": defaults, "overrides": []any{}}
+		panels = append(panels, panel)
+
+		x += p.width
+		rowHeight = max(rowHeight, p.height)
+	}
+
+	dashboard := map[string]any{
+		"uid":           "s3-profiler",
+		"title":         "S3 Profiler",
+		"tags":          []string{"s3-profiler"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"time":          map[string]string{"from": "now-90d", "to": "now"},
+		"panels":        panels,
+		"templating": map[string]any{
+			"list": []map[string]any{{
+				"name":       "bucket",
+				"label":      "Bucket",
+				"type":       "query",
+				"datasource": datasource,
+				"query":      "SELECT DISTINCT bucket FROM runs ORDER BY 1",
+				"refresh":    1,
+				"multi":      true,
+				"includeAll": true,
+				"current":    map[string]any{"text": "All", "value": "$__all"},
+			}},
+		},
+	}
+	if datasourceUID == "" {
+		dashboard["__inputs"] = []map[string]string{{
+			"name":        grafanaDatasourceInput,
+			"label":       "s3-profiler results",
+			"description": "PostgreSQL results database written with --results-db",
+			"type":        "datasource",
+			"pluginId":    grafanaPostgres,
+			"pluginName":  "PostgreSQL",
+		}}
+	}
+
+	return json.MarshalIndent(dashboard, "", "  ")
+}
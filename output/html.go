@@ -0,0 +1,173 @@
+package output
+
+import (
+	"embed"
+	"encoding/json"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Limits keeping the embedded treemap data small for very large buckets
+const (
+	treemapMaxDepth    = 8
+	treemapMaxChildren = 50
+)
+
+//go:embed templates/report.html
+var templateFS embed.FS
+
+// reportTemplate is cloned per report so "t" can use the writer's catalog
+var reportTemplate = template.Must(template.New("report.html").
+	Funcs(template.FuncMap{"t": func(msg string) string { return msg }}).
+	ParseFS(templateFS, "templates/report.html"))
+
+// treemapNode is the JSON shape consumed by the treemap script
+type treemapNode struct {
+	Name      string         `json:"name"`
+	Size      int64          `json:"size"`
+	Count     int64          `json:"count"`
+	Self      int64          `json:"self"`
+	SelfCount int64          `json:"selfCount"`
+	Children  []*treemapNode `json:"children,omitempty"`
+}
+
+// htmlStorageClass is a storage class table row
+type htmlStorageClass struct {
+	Name    string
+	Count   string
+	Size    string
+	Percent string
+}
+
+// htmlFinding is a findings table row; Severity is also the badge class
+type htmlFinding struct {
+	Severity string
+	Rule     string
+	Location string
+	Message  string
+}
+
+// htmlReport is the data rendered by the HTML report template
+type htmlReport struct {
+	Bucket         string
+	Generated      string
+	Region         string
+	CreationDate   string
+	TotalObjects   string
+	TotalSize      string
+	EstimatedCost  string
+	StorageClasses []htmlStorageClass
+	// Findings are most severe first; FindingCounts summarizes them
+	Findings      []htmlFinding
+	FindingCounts string
+	Tree          template.JS
+	// Estimate is the notice shown when the listing was truncated, under
+	// EstimateTitle
+	Estimate      string
+	EstimateTitle string
+	Lang          Language
+}
+
+// WriteHTMLReport writes a self-contained HTML report with the bucket summary
+// and a drill-down treemap of the prefix tree
+func (w *Writer) WriteHTMLReport(summary *types.BucketSummary, tree *types.PrefixNode) error {
+	report := htmlReport{
+		Bucket:        w.bucket(summary.Name),
+		Generated:     FormatTime(time.Now(), w.opts.Location),
+		Region:        summary.Region,
+		CreationDate:  FormatTime(summary.CreationDate, w.opts.Location),
+		TotalObjects:  FormatNumber(summary.TotalObjects),
+		TotalSize:     FormatBytes(summary.TotalSize),
+		EstimatedCost: w.cost(summary.EstimatedCost),
+		Lang:          w.language(),
+	}
+	if e := summary.Estimate; e != nil {
+		report.Estimate = w.estimateNote(e)
+		report.EstimateTitle = w.t("Estimate")
+		if e.Interrupted {
+			report.EstimateTitle = w.t("Partial")
+		}
+		report.TotalObjects = w.tf("%s listed", report.TotalObjects)
+		report.TotalSize = w.tf("%s listed", report.TotalSize)
+		if e.BucketObjects > 0 {
+			report.TotalObjects += w.tf(" of ~%s (est.)", FormatNumber(e.BucketObjects))
+			report.TotalSize += w.tf(", ~%s extrapolated (est.)", FormatBytes(e.Size))
+			report.EstimatedCost = w.tf("%s listed", report.EstimatedCost) +
+				w.tf(", ~%s extrapolated (est.)", w.cost(e.Cost))
+		}
+	}
+
+	for _, class := range sortedStorageClasses(summary.StorageClasses) {
+		stats := summary.StorageClasses[class]
+		report.StorageClasses = append(report.StorageClasses, htmlStorageClass{
+			Name:    class,
+			Count:   FormatNumber(stats.Count),
+			Size:    FormatBytes(stats.Size),
+			Percent: FormatPercent(stats.Size, summary.TotalSize),
+		})
+	}
+
+	for _, f := range summary.Findings {
+		report.Findings = append(report.Findings, htmlFinding{
+			Severity: string(findingSeverity(f)),
+			Rule:     f.Rule.ID,
+			Location: w.findingURI(f),
+			Message:  f.Message,
+		})
+	}
+	if len(summary.Findings) > 0 {
+		report.FindingCounts = w.findingCounts(summary.Findings)
+	}
+
+	root := w.treemap(tree, 0)
+	root.Name = report.Bucket
+	data, err := json.Marshal(root)
+	if err != nil {
+		return err
+	}
+	report.Tree = template.JS(data)
+
+	tmpl, err := reportTemplate.Clone()
+	if err != nil {
+		return err
+	}
+	tmpl.Funcs(template.FuncMap{"t": w.t})
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, report); err != nil {
+		return err
+	}
+
+	return w.writeFile(w.ReportName(summary.Name, "-report.html"), b.String())
+}
+
+// treemap converts a prefix node for the treemap, folding deep levels and
+// small siblings into their parent so the embedded data stays bounded
+func (w *Writer) treemap(node *types.PrefixNode, depth int) *treemapNode {
+	t := &treemapNode{
+		Name:      w.key(node.Name),
+		Size:      node.Size,
+		Count:     node.ObjectCount,
+		Self:      node.SelfSize,
+		SelfCount: node.SelfCount,
+	}
+	if depth >= treemapMaxDepth {
+		t.Self, t.SelfCount = node.Size, node.ObjectCount
+		return t
+	}
+
+	for i, child := range node.Children {
+		if i >= treemapMaxChildren {
+			// Children are sorted by size; attribute the rest to the parent
+			t.Self += child.Size
+			t.SelfCount += child.ObjectCount
+			continue
+		}
+		t.Children = append(t.Children, w.treemap(child, depth+1))
+	}
+
+	return t
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language selects the message catalog used for report labels
+type Language string
+
+const (
+	LanguageEnglish  Language = "en"
+	LanguageSpanish  Language = "es"
+	LanguageJapanese Language = "ja"
+)
+
+// catalogs maps English messages to their translations. Messages missing
+// from a catalog are written in English.
+var catalogs = map[Language]map[string]string{
+	LanguageSpanish:  messagesES,
+	LanguageJapanese: messagesJA,
+}
+
+// ParseLanguage parses a --lang value; empty means English
+func ParseLanguage(s string) (Language, error) {
+	switch lang := Language(strings.ToLower(s)); lang {
+	case "", LanguageEnglish:
+		return LanguageEnglish, nil
+	case LanguageSpanish, LanguageJapanese:
+		return lang, nil
+	}
+	return "", fmt.Errorf("unsupported language %q (use en, es or ja)", s)
+}
+
+// language returns the report language, defaulting to English
+func (w *Writer) language() Language {
+	if w.opts.Language == "" {
+		return LanguageEnglish
+	}
+	return w.opts.Language
+}
+
+// t translates a report message
+func (w *Writer) t(msg string) string {
+	if translated, ok := catalogs[w.opts.Language][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// tf translates a format string and applies the arguments
+func (w *Writer) tf(format string, args ...any) string {
+	return fmt.Sprintf(w.t(format), args...)
+}
+
+// label translates a label and pads it to the given display width so that
+// values line up
+func (w *Writer) label(msg string, width int) string {
+	translated := w.t(msg)
+	if pad := width - displayWidth(translated); pad > 0 {
+		return translated + strings.Repeat(" ", pad)
+	}
+	return translated
+}
+
+// displayWidth returns the number of terminal columns a string occupies,
+// counting East Asian wide characters as two
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width++
+		if isWide(r) {
+			width++
+		}
+	}
+	return width
+}
+
+// isWide reports whether a rune is rendered double-width
+func isWide(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115f) ||
+		(r >= 0x2e80 && r <= 0xa4cf && r != 0x303f) ||
+		(r >= 0xac00 && r <= 0xd7a3) ||
+		(r >= 0xf900 && r <= 0xfaff) ||
+		(r >= 0xfe30 && r <= 0xfe4f) ||
+		(r >= 0xff00 && r <= 0xff60) ||
+		(r >= 0xffe0 && r <= 0xffe6) ||
+		(r >= 0x20000 && r <= 0x3fffd)
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// InterimSummaryName returns the file name of a bucket's interim summary
+func (w *Writer) InterimSummaryName(bucketName string) string {
+	return w.FileName(w.ReportName(bucketName, "-interim.txt"))
+}
+
+// WriteInterimSummary writes the totals of a listing in progress. The file
+// is replaced atomically, so it can be read at any time while the listing
+// updates it.
+func (w *Writer) WriteInterimSummary(s *types.InterimSummary) error {
+	var b strings.Builder
+
+	name := w.bucket(s.Bucket)
+	b.WriteString(FormatHeader(w.tf("Interim Summary: %s", name)))
+	b.WriteString("\n\n")
+
+	elapsed := s.Updated.Sub(s.Started)
+	fmt.Fprintf(&b, "%s %s\n", w.label("Bucket Name:", 15), name)
+	fmt.Fprintf(&b, "%s %s\n", w.label("Region:", 15), s.Region)
+	fmt.Fprintf(&b, "%s %s\n", w.label("Started:", 15), FormatTime(s.Started, w.opts.Location))
+	fmt.Fprintf(&b, "%s %s (%s)\n", w.label("Updated:", 15), FormatTime(s.Updated, w.opts.Location), elapsed.Round(time.Second))
+	status := w.t("listing in progress")
+	if s.Complete {
+		status = w.t("listing complete, analysis in progress")
+	}
+	fmt.Fprintf(&b, "%s %s\n", w.label("Status:", 15), status)
+	fmt.Fprintf(&b, "%s %s\n", w.label("Total Objects:", 15), w.tf("%s listed", FormatNumber(s.Objects)))
+	fmt.Fprintf(&b, "%s %s\n", w.label("Total Size:", 15), w.tf("%s listed", FormatBytes(s.Size)))
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		fmt.Fprintf(&b, "%s %s\n", w.label("Rate:", 15), w.tf("%.0f objects/s", float64(s.Objects)/seconds))
+	}
+	b.WriteString("\n")
+
+	if len(s.StorageClasses) > 0 {
+		b.WriteString(FormatSubHeader(w.t("Storage Class Breakdown")))
+		b.WriteString("\n")
+		classes := sortedStorageClasses(s.StorageClasses)
+		fmt.Fprintf(&b, "%-22s %14s %14s %10s\n", w.t("Storage Class"), w.t("Objects"), w.t("Size"), w.t("% Size"))
+		for _, class := range classes {
+			stats := s.StorageClasses[class]
+			fmt.Fprintf(&b, "%-22s %14s %14s %10s\n", class, FormatNumber(stats.Count), FormatBytes(stats.Size), FormatPercent(stats.Size, s.Size))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.Prefixes) > 0 {
+		b.WriteString(FormatSubHeader(w.t("Top Prefixes")))
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%-40s %14s %14s %10s\n", w.t("Prefix"), w.t("Objects"), w.t("Size"), w.t("% Size"))
+		for _, p := range s.Prefixes {
+			fmt.Fprintf(&b, "%-40s %14s %14s %10s\n", w.key(p.Prefix), FormatNumber(p.ObjectCount), FormatBytes(p.Size), FormatPercent(p.Size, s.Size))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(w.t("Totals cover the objects listed so far; this file is removed once the bucket's reports are written.") + "\n")
+
+	// The temporary file keeps readers from seeing a partial report
+	path := filepath.Join(w.outputDir, w.InterimSummaryName(s.Bucket))
+	tmp := path + ".tmp"
+	var err error
+	if w.opts.Encryption != nil {
+		err = w.opts.Encryption.writeEncrypted(tmp, b.String())
+	} else {
+		err = os.WriteFile(tmp, []byte(b.String()), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write interim summary: %w", err)
+	}
+	return nil
+}
+
+// RemoveInterimSummary removes a bucket's interim summary, if any
+func (w *Writer) RemoveInterimSummary(bucketName string) error {
+	err := os.Remove(filepath.Join(w.outputDir, w.InterimSummaryName(bucketName)))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove interim summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package output
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// InventorySuffix is the name ending of object inventory exports, before
+// any compression extension
+const InventorySuffix = "-objects.csv"
+
+// InventoryBucket returns the bucket name of an export file name, or ""
+// when the name is not an object inventory export
+func InventoryBucket(name string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".zst")
+	if !strings.HasSuffix(name, InventorySuffix) {
+		return ""
+	}
+	return strings.TrimSuffix(name, InventorySuffix)
+}
+
+// FindObjectInventories returns the object inventory exports in a directory
+func FindObjectInventories(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && InventoryBucket(entry.Name()) != "" {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// InventoryReader reads an object inventory written by WriteObjectInventory
+type InventoryReader struct {
+	// Bucket is the bucket name taken from the file name
+	Bucket   string
+	filename string
+	file     *os.File
+	zr       io.Closer
+	cr       *csv.Reader
+	columns  map[string]int
+}
+
+// OpenObjectInventory opens an export for reading. Gzip and zstd
+// compressed exports are read by extension.
+func OpenObjectInventory(filename string) (*InventoryReader, error) {
+	if strings.HasSuffix(filename, encryptionExtension) {
+		return nil, fmt.Errorf("%s is encrypted; decrypt it with age -d first", filename)
+	}
+	bucket := InventoryBucket(filepath.Base(filename))
+	if bucket == "" {
+		return nil, fmt.Errorf("%s is not an object inventory export (bucket-name%s)", filename, InventorySuffix)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	ir := &InventoryReader{Bucket: bucket, filename: filename, file: file}
+
+	var r io.Reader = file
+	switch filepath.Ext(filename) {
+	case ".gz":
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+		ir.zr, r = zr, zr
+	case ".zst":
+		zr, err := zstd.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+		ir.zr, r = zr.IOReadCloser(), zr
+	}
+
+	ir.cr = csv.NewReader(r)
+	header, err := ir.cr.Read()
+	if err != nil {
+		ir.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	ir.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		ir.columns[name] = i
+	}
+	for _, name := range []string{"key", "size", "last_modified", "storage_class", "etag"} {
+		if _, ok := ir.columns[name]; !ok {
+			ir.Close()
+			return nil, fmt.Errorf("%s has no %s column", filename, name)
+		}
+	}
+	return ir, nil
+}
+
+// Read returns the next object, or io.EOF after the last one
+func (ir *InventoryReader) Read() (types.ObjectMetadata, error) {
+	record, err := ir.cr.Read()
+	if errors.Is(err, io.EOF) {
+		return types.ObjectMetadata{}, io.EOF
+	}
+	if err != nil {
+		return types.ObjectMetadata{}, fmt.Errorf("failed to read %s: %w", ir.filename, err)
+	}
+
+	key := record[ir.columns["key"]]
+	size, err := strconv.ParseInt(record[ir.columns["size"]], 10, 64)
+	if err != nil {
+		return types.ObjectMetadata{}, fmt.Errorf("%s: invalid size for %s: %w", ir.filename, key, err)
+	}
+	modified, err := time.Parse(time.RFC3339, record[ir.columns["last_modified"]])
+	if err != nil {
+		return types.ObjectMetadata{}, fmt.Errorf("%s: invalid last_modified for %s: %w", ir.filename, key, err)
+	}
+
+	return types.ObjectMetadata{
+		Key:          key,
+		Size:         size,
+		LastModified: modified,
+		StorageClass: record[ir.columns["storage_class"]],
+		ETag:         record[ir.columns["etag"]],
+	}, nil
+}
+
+// ReadAll reads the remaining objects
+func (ir *InventoryReader) ReadAll() ([]types.ObjectMetadata, error) {
+	var objects []types.ObjectMetadata
+	for {
+		obj, err := ir.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+}
+
+// Close releases the export file
+func (ir *InventoryReader) Close() error {
+	if ir.zr != nil {
+		ir.zr.Close()
+	}
+	return ir.file.Close()
+}
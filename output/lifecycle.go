@@ -0,0 +1,173 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// maxListedDataAges is the default number of rows of the data age table
+const maxListedDataAges = 20
+
+// maxLifecycleRuleID is the longest lifecycle rule ID S3 accepts
+const maxLifecycleRuleID = 255
+
+// writeLifecycle writes the data age distribution and the recommended
+// lifecycle rules of the summary report
+func (w *Writer) writeLifecycle(b *strings.Builder, advice *types.LifecycleAdvice) {
+	b.WriteString(FormatSubHeader(w.t("Data Age")))
+	b.WriteString("\n")
+
+	ages := append([]types.DataAge(nil), advice.Ages...)
+	sortTable(ages, w.opts.Table, func(a types.DataAge) tableRow {
+		return tableRow{name: a.Prefix, count: a.ObjectCount, size: a.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(ages), maxListedDataAges)
+
+	fmt.Fprintf(b, "%-30s %-20s %12s %12s", w.t("Prefix"), w.t("Storage Class"), w.t("Objects"), w.t("Size"))
+	for _, days := range types.LifecycleAgeDays {
+		fmt.Fprintf(b, " %11s", w.tf(">%dd", days))
+	}
+	b.WriteString("\n")
+	for _, age := range ages[:shown] {
+		fmt.Fprintf(b, "%-30s %-20s %12s %12s", w.key(age.Prefix), age.StorageClass, FormatNumber(age.ObjectCount), FormatBytes(age.Size))
+		for _, size := range age.OlderSize {
+			fmt.Fprintf(b, " %11s", FormatBytes(size))
+		}
+		b.WriteString("\n")
+	}
+	writeMoreFooter(b, shown, len(ages))
+	b.WriteString(w.t("Bytes older than each age, counted from the last write.") + "\n")
+
+	if len(advice.Rules) == 0 {
+		return
+	}
+
+	b.WriteString("\n")
+	b.WriteString(FormatSubHeader(w.t("Lifecycle Recommendations")))
+	b.WriteString("\n")
+
+	rules := append([]types.LifecycleRecommendation(nil), advice.Rules...)
+	sortTable(rules, w.opts.Table, func(r types.LifecycleRecommendation) tableRow {
+		return tableRow{name: r.Prefix, count: r.ObjectCount, size: r.Size}
+	})
+	shown = w.opts.Table.visibleRows(len(rules), 0)
+
+	var savings float64
+	fmt.Fprintf(b, "%-30s %12s %12s %10s %10s %10s %9s\n",
+		w.t("Prefix"), w.t("Objects"), w.t("Size"), w.t("Current"), w.t("After"), w.t("Savings"), w.t("Payback"))
+	for _, rule := range rules[:shown] {
+		payback := "-"
+		if rule.PaybackMonths > 0 {
+			payback = w.tf("%.1f mo", max(rule.PaybackMonths, 0.1))
+			if rule.PaybackMonths < 0.1 {
+				payback = "<" + payback
+			}
+		}
+		fmt.Fprintf(b, "%-30s %12s %12s %10s %10s %10s %9s\n",
+			w.key(rule.Prefix),
+			FormatNumber(rule.ObjectCount),
+			FormatBytes(rule.Size),
+			w.cost(rule.CurrentCost),
+			w.cost(rule.NewCost),
+			w.cost(rule.Savings),
+			payback)
+		fmt.Fprintf(b, "  %s\n", w.transitions(rule.Transitions))
+	}
+	writeMoreFooter(b, shown, len(rules))
+	for _, rule := range rules {
+		savings += rule.Savings
+	}
+	b.WriteString(w.tf("Estimated savings: %s/month", w.cost(savings)) + "\n")
+
+	b.WriteString(w.t("Monthly storage costs of the objects each rule would transition today; objects under 128 KB are not transitioned.") + "\n")
+	b.WriteString(w.t("Ages count from the last write: apply a rule only to data that is read no more often. STANDARD_IA and GLACIER_IR charge per GB retrieved and bill at least 30 and 90 days.") + "\n")
+	if advice.Archive {
+		b.WriteString(w.t("GLACIER and DEEP_ARCHIVE objects must be restored before they can be read and bill at least 90 and 180 days.") + "\n")
+	} else {
+		b.WriteString(w.t("Pass --lifecycle-archive to also consider GLACIER and DEEP_ARCHIVE, which need a restore before reads.") + "\n")
+	}
+	b.WriteString(w.t("Payback: months of savings to cover the one-time transition fee.") + "\n")
+}
+
+// transitions renders the transitions of a lifecycle rule
+func (w *Writer) transitions(transitions []types.LifecycleTransition) string {
+	steps := make([]string, len(transitions))
+	for i, t := range transitions {
+		steps[i] = w.tf("after %d days -> %s", t.Days, t.StorageClass)
+	}
+	return strings.Join(steps, ", ")
+}
+
+// lifecycleConfiguration mirrors the LifecycleConfiguration accepted by
+// `aws s3api put-bucket-lifecycle-configuration --lifecycle-configuration`
+type lifecycleConfiguration struct {
+	Rules []lifecycleRule `json:"Rules"`
+}
+
+type lifecycleRule struct {
+	ID     string `json:"ID"`
+	Status string `json:"Status"`
+	Filter struct {
+		Prefix string `json:"Prefix"`
+	} `json:"Filter"`
+	Transitions []lifecycleTransition `json:"Transitions"`
+}
+
+type lifecycleTransition struct {
+	Days         int    `json:"Days"`
+	StorageClass string `json:"StorageClass"`
+}
+
+// WriteLifecycleConfig writes the recommended lifecycle rules as a
+// lifecycle configuration. Objects at the bucket root cannot be selected
+// by a prefix filter, so their rule is left out unless the root is all
+// there is, in which case it applies to the whole bucket.
+func (w *Writer) WriteLifecycleConfig(bucketName string, advice *types.LifecycleAdvice) error {
+	var config lifecycleConfiguration
+	for _, rec := range advice.Rules {
+		var rule lifecycleRule
+		switch {
+		case rec.Prefix != "/":
+			rule.ID = lifecycleRuleID(strings.TrimSuffix(w.rawKey(rec.Prefix), "/"))
+			rule.Filter.Prefix = w.rawKey(rec.Prefix)
+		case len(advice.Rules) == 1:
+			rule.ID = "s3-profiler"
+		default:
+			continue
+		}
+		rule.Status = "Enabled"
+		for _, t := range rec.Transitions {
+			rule.Transitions = append(rule.Transitions, lifecycleTransition{Days: t.Days, StorageClass: t.StorageClass})
+		}
+		config.Rules = append(config.Rules, rule)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-lifecycle.json"), string(data)+"\n")
+}
+
+// lifecycleRuleID names the rule of a prefix. IDs over S3's limit are cut
+// short and end with a hash of the whole prefix, so they stay unique.
+func lifecycleRuleID(prefix string) string {
+	id := "s3-profiler-" + prefix
+	if len(id) <= maxLifecycleRuleID {
+		return id
+	}
+	sum := sha256.Sum256([]byte(prefix))
+	suffix := "-" + hex.EncodeToString(sum[:])[:16]
+	id = id[:maxLifecycleRuleID-len(suffix)]
+	for !utf8.ValidString(id) {
+		id = id[:len(id)-1]
+	}
+	return id + suffix
+}
@@ -0,0 +1,202 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// WriteMarkdownReport writes <bucket>-report.md: the bucket summary,
+// findings, storage classes, file types, size distribution and partitions
+// as GitHub-flavored Markdown tables, for pasting into wikis and issues.
+// Tables are ordered and cut like those of the text reports.
+func (w *Writer) WriteMarkdownReport(summary *types.BucketSummary, metadata *types.MetadataSummary, analysis *types.PartitionAnalysis) error {
+	var b strings.Builder
+
+	name := w.bucket(summary.Name)
+	fmt.Fprintf(&b, "# %s\n\n", mdText(w.tf("Bucket Summary: %s", name)))
+	fmt.Fprintf(&b, "%s\n\n", w.tf("Generated by s3-profiler on %s.", FormatTime(time.Now(), w.location())))
+	if e := w.estimate; e != nil {
+		title := w.t("ESTIMATE")
+		if e.Interrupted {
+			title = w.t("PARTIAL")
+		}
+		fmt.Fprintf(&b, "> **%s**: %s\n\n", title, mdText(w.estimateNote(e)))
+	}
+
+	w.writeMarkdownSummary(&b, summary)
+	if len(summary.Findings) > 0 {
+		w.writeMarkdownFindings(&b, summary.Findings)
+	}
+	w.writeMarkdownStorageClasses(&b, summary)
+	w.writeMarkdownFileTypes(&b, metadata)
+	w.writeMarkdownSizes(&b, metadata)
+	w.writeMarkdownPartitions(&b, summary.Name, analysis)
+
+	return w.writeFile(w.ReportName(summary.Name, "-report.md"), b.String())
+}
+
+// writeMarkdownSummary writes the property table of the bucket
+func (w *Writer) writeMarkdownSummary(b *strings.Builder, summary *types.BucketSummary) {
+	objects := FormatNumber(summary.TotalObjects)
+	size := FormatBytes(summary.TotalSize)
+	cost := w.cost(summary.EstimatedCost)
+	if e := summary.Estimate; e != nil {
+		objects = w.tf("%s listed", objects)
+		size = w.tf("%s listed", size)
+		if e.BucketObjects > 0 {
+			objects += w.tf(" of ~%s (est.)", FormatNumber(e.BucketObjects))
+			size += w.tf(", ~%s extrapolated (est.)", FormatBytes(e.Size))
+			cost = w.tf("%s listed", cost) + w.tf(", ~%s extrapolated (est.)", w.cost(e.Cost))
+		}
+	}
+
+	fmt.Fprintf(b, "| %s | %s |\n|---|---|\n", w.t("Property"), w.t("Value"))
+	fmt.Fprintf(b, "| %s | %s |\n", w.t("Bucket"), mdCode(w.bucket(summary.Name)))
+	fmt.Fprintf(b, "| %s | %s |\n", w.t("Region"), summary.Region)
+	fmt.Fprintf(b, "| %s | %s |\n", w.t("Creation Date"), FormatTime(summary.CreationDate, w.location()))
+	if scope := summary.Scope; scope != nil {
+		fmt.Fprintf(b, "| %s | %s |\n", w.t("Key Scope"), mdText(w.keyScope(scope)))
+	}
+	fmt.Fprintf(b, "| %s | %s |\n", w.t("Total Objects"), objects)
+	fmt.Fprintf(b, "| %s | %s |\n", w.t("Total Size"), size)
+	fmt.Fprintf(b, "| %s | %s |\n\n", w.t("Estimated Monthly Cost"), cost)
+}
+
+// writeMarkdownFindings writes the findings table, most severe first
+func (w *Writer) writeMarkdownFindings(b *strings.Builder, findings []types.Finding) {
+	b.WriteString("## " + w.t("Findings") + "\n\n")
+	b.WriteString(w.findingCounts(findings) + "\n\n")
+	shown := w.opts.Table.visibleRows(len(findings), 0)
+	fmt.Fprintf(b, "| %s | %s | %s | %s |\n|---|---|---|---|\n", w.t("Severity"), w.t("Rule"), w.t("Location"), w.t("Finding"))
+	for _, f := range findings[:shown] {
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", strings.ToUpper(string(findingSeverity(f))), f.Rule.ID, mdCode(w.findingURI(f)), mdText(f.Message))
+	}
+	writeMarkdownMore(b, shown, len(findings))
+}
+
+// writeMarkdownStorageClasses writes the storage class table, largest
+// first
+func (w *Writer) writeMarkdownStorageClasses(b *strings.Builder, summary *types.BucketSummary) {
+	b.WriteString("## " + w.t("Storage Classes") + "\n\n")
+	if len(summary.StorageClasses) == 0 {
+		b.WriteString(w.t("No objects found") + "\n\n")
+		return
+	}
+	classes := sortedStorageClasses(summary.StorageClasses)
+	sortTable(classes, w.opts.Table, func(class string) tableRow {
+		stats := summary.StorageClasses[class]
+		return tableRow{name: class, count: stats.Count, size: stats.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(classes), 0)
+
+	fmt.Fprintf(b, "| %s | %s | %s | %s |\n|---|---:|---:|---:|\n", w.t("Storage Class"), w.t("Objects"), w.t("Size"), w.t("% Size"))
+	for _, class := range classes[:shown] {
+		stats := summary.StorageClasses[class]
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", class, FormatNumber(stats.Count), FormatBytes(stats.Size), FormatPercent(stats.Size, summary.TotalSize))
+	}
+	writeMarkdownMore(b, shown, len(classes))
+}
+
+// writeMarkdownFileTypes writes the file type table, most objects first
+func (w *Writer) writeMarkdownFileTypes(b *strings.Builder, metadata *types.MetadataSummary) {
+	b.WriteString("## " + w.t("File Types") + "\n\n")
+	if len(metadata.FileTypeStats) == 0 {
+		b.WriteString(w.t("No objects found") + "\n\n")
+		return
+	}
+	fileTypes := sortedByCount(metadata.FileTypeStats)
+	sortTable(fileTypes, w.opts.Table, func(ext string) tableRow {
+		return tableRow{name: ext, count: metadata.FileTypeStats[ext], size: metadata.FileTypeSizes[ext]}
+	})
+	shown := w.opts.Table.visibleRows(len(fileTypes), maxListedFileTypes)
+
+	total := int64(len(metadata.Objects))
+	fmt.Fprintf(b, "| %s | %s | %s | %s |\n|---|---:|---:|---:|\n", w.t("Extension"), w.t("Objects"), w.t("%"), w.t("Size"))
+	for _, ext := range fileTypes[:shown] {
+		count := metadata.FileTypeStats[ext]
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", mdCode(ext), FormatNumber(count), FormatPercent(count, total), FormatBytes(metadata.FileTypeSizes[ext]))
+	}
+	writeMarkdownMore(b, shown, len(fileTypes))
+}
+
+// writeMarkdownSizes writes the object size distribution table
+func (w *Writer) writeMarkdownSizes(b *strings.Builder, metadata *types.MetadataSummary) {
+	b.WriteString("## " + w.t("Size Distribution") + "\n\n")
+	total := int64(len(metadata.Objects))
+	fmt.Fprintf(b, "| %s | %s | %s |\n|---|---:|---:|\n", w.t("Size"), w.t("Objects"), w.t("%"))
+	for _, bucket := range metadata.SizeDistribution {
+		fmt.Fprintf(b, "| %s | %s | %s |\n", bucket.Label, FormatNumber(bucket.Count), FormatPercent(bucket.Count, total))
+	}
+	b.WriteString("\n")
+}
+
+// writeMarkdownPartitions writes the detected patterns and the partitions;
+// more than partitionRollupThreshold date partitions are left to the
+// partition list
+func (w *Writer) writeMarkdownPartitions(b *strings.Builder, bucketName string, analysis *types.PartitionAnalysis) {
+	b.WriteString("## " + w.t("Partitions") + "\n\n")
+	partitions := analysis.Partitions
+	if len(partitions) == 0 {
+		b.WriteString(w.t("No partition patterns detected") + "\n")
+		return
+	}
+
+	fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n|---|---|---:|---:|---:|\n", w.t("Scope"), w.t("Pattern"), w.t("Objects"), w.t("Size"), w.t("Coverage"))
+	for _, p := range analysis.Patterns {
+		scope := mdCode(w.key(p.Scope))
+		if p.Scope == "" {
+			scope = w.t("(bucket)")
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %.1f%% |\n", scope, mdCode(p.Pattern), FormatNumber(p.ObjectCount), FormatBytes(p.TotalSize), p.Coverage*100)
+	}
+	b.WriteString("\n")
+
+	if NeedsPartitionRollup(partitions) {
+		b.WriteString(w.tf("%d partitions; the full list is in %s.", len(partitions), mdCode(w.PartitionListName(bucketName))) + "\n")
+		return
+	}
+
+	partitions = append([]types.Partition(nil), partitions...)
+	sortTable(partitions, w.opts.Table, func(p types.Partition) tableRow {
+		return tableRow{name: p.Scope + p.Prefix, count: p.ObjectCount, size: p.TotalSize}
+	})
+	shown := w.opts.Table.visibleRows(len(partitions), 0)
+
+	fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n|---|---:|---:|---|---|\n", w.t("Partition"), w.t("Objects"), w.t("Size"), w.t("Oldest"), w.t("Newest"))
+	for _, p := range partitions[:shown] {
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n", mdCode(w.key(p.Scope+p.Prefix)), FormatNumber(p.ObjectCount), FormatBytes(p.TotalSize),
+			FormatTime(p.Oldest, w.location()), FormatTime(p.Newest, w.location()))
+	}
+	writeMarkdownMore(b, shown, len(partitions))
+}
+
+// writeMarkdownMore ends a table, noting the rows --max-rows left out
+func writeMarkdownMore(b *strings.Builder, shown, total int) {
+	b.WriteString("\n")
+	if total > shown {
+		fmt.Fprintf(b, "_... %d more not shown (use --max-rows to change)_\n\n", total-shown)
+	}
+}
+
+// mdText escapes the characters that would end a table cell or start
+// Markdown emphasis
+func mdText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "\n", " ").Replace(s)
+}
+
+// mdCode renders text as a code span that is safe in a table cell; the
+// fence is longer than any run of backticks in the text
+func mdCode(s string) string {
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
@@ -0,0 +1,385 @@
+package output
+
+// messagesES is the Spanish report catalog
+var messagesES = map[string]string{
+	// Report titles
+	"Bucket Configuration: %s":      "Configuración del bucket: %s",
+	"Bucket Summary: %s":            "Resumen del bucket: %s",
+	"Dimension Analysis: %s":        "Análisis de dimensiones: %s",
+	"Dimension: %s":                 "Dimensión: %s",
+	"Metadata Summary: %s":          "Resumen de metadatos: %s",
+	"Parquet Column Statistics: %s": "Estadísticas de columnas Parquet: %s",
+	"Partition Analysis: %s":        "Análisis de particiones: %s",
+	"Partition: %s":                 "Partición: %s",
+	"Object Tags: %s":               "Etiquetas de objetos: %s",
+	"Tag: %s":                       "Etiqueta: %s",
+	"Multi-Account Roll-up":         "Resumen multicuenta",
+	"Interim Summary: %s":           "Resumen provisional: %s",
+
+	// Labels
+	"Bucket Name:":          "Nombre:",
+	"Creation Date:":        "Creación:",
+	"Deleted Keys:":         "Claves borradas:",
+	"Earliest Modified:":    "Primera modif.:",
+	"Examples:":             "Ejemplos:",
+	"Hidden Size:":          "Tamaño oculto:",
+	"Hidden Versions:":      "Versiones ocultas:",
+	"Latest Modified:":      "Última modif.:",
+	"Objects:":              "Objetos:",
+	"Partition Count:":      "Particiones:",
+	"Region:":               "Región:",
+	"Size:":                 "Tamaño:",
+	"Total Objects:":        "Objetos totales:",
+	"Total Size:":           "Tamaño total:",
+	"Versioning:":           "Versionado:",
+	"Non-current Versions:": "Versiones no actuales:",
+	"Non-current Size:":     "Tamaño no actual:",
+	"Non-current Cost:":     "Coste no actual:",
+	"Delete Markers:":       "Marcadores de borrado:",
+	"Current Objects:":      "Objetos actuales:",
+	"Modeled Billable:":     "Facturable modelado:",
+	"CloudWatch:":           "CloudWatch:",
+	"Incomplete Uploads:":   "Cargas incompletas:",
+	"Tagged":                "Con etiquetas",
+	"Untagged":              "Sin etiquetas",
+	"(not set)":             "(sin definir)",
+	"Content Access:":       "Acceso a contenido:",
+	"Key Scope:":            "Alcance:",
+	"including %s":          "incluyendo %s",
+	"Started:":              "Inicio:",
+	"Updated:":              "Actualizado:",
+	"Status:":               "Estado:",
+	"Rate:":                 "Ritmo:",
+	"PARTIAL":               "PARCIAL",
+	"Partial":               "Parcial",
+
+	"Oldest:": "Más antiguo:",
+	"Newest:": "Más reciente:",
+	"Late data: newest object written %d day(s) after the partition period": "Datos tardíos: el objeto más reciente se escribió %d día(s) después del periodo de la partición",
+
+	// Sections
+	"Chargeable Features":                        "Funciones con coste",
+	"Checks Not Completed":                       "Comprobaciones no completadas",
+	"Content Samples":                            "Muestras de contenido",
+	"Date Range":                                 "Rango de fechas",
+	"Deleted Data Still Billed":                  "Datos borrados aún facturados",
+	"Estimated Monthly Storage Cost":             "Coste mensual estimado de almacenamiento",
+	"Event Notifications":                        "Notificaciones de eventos",
+	"External Access (IAM Access Analyzer)":      "Acceso externo (IAM Access Analyzer)",
+	"File Type Distribution":                     "Distribución por tipo de archivo",
+	"Naming Consistency":                         "Coherencia de nombres",
+	"Object Attributes (HeadObject sample)":      "Atributos de objetos (muestra HeadObject)",
+	"Object Listing":                             "Listado de objetos",
+	"Restore Cost Estimates (archived prefixes)": "Coste estimado de restauración (prefijos archivados)",
+	"S3 Inventory":                               "Inventario S3",
+	"Size Distribution":                          "Distribución de tamaños",
+	"Storage Class Breakdown":                    "Desglose por clase de almacenamiento",
+	"Rejected Date Patterns":                     "Patrones de fecha rechazados",
+	"Unusual Key Encodings":                      "Codificaciones de clave inusuales",
+	"Warnings":                                   "Advertencias",
+	"Website Hosting and CORS":                   "Alojamiento web y CORS",
+	"Object Versions":                            "Versiones de objetos",
+	"Billable Size":                              "Tamaño facturable",
+	"Request-Rate Hotspots":                      "Puntos calientes de tasa de solicitudes",
+	"Performance":                                "Rendimiento",
+	"Tag Keys":                                   "Claves de etiqueta",
+	"Untagged Objects by Prefix":                 "Objetos sin etiquetas por prefijo",
+	"Account: %s":                                "Cuenta: %s",
+	"Data Age":                                   "Antigüedad de los datos",
+	"Lifecycle Recommendations":                  "Recomendaciones de ciclo de vida",
+	"Compressibility":                            "Compresibilidad",
+	"Client-Encrypted Content":                   "Contenido cifrado en el cliente",
+	"Small Files":                                "Archivos pequeños",
+	"Storage Class What-If":                      "Simulación de clases de almacenamiento",
+	"Partition Pruning":                          "Poda de particiones",
+	"Top Prefixes":                               "Prefijos principales",
+	"File Types":                                 "Tipos de archivo",
+	"Largest Objects":                            "Objetos más grandes",
+	"Findings":                                   "Hallazgos",
+
+	// Table headers
+	"Parquet Schema": "Esquema Parquet",
+	"Repetition":     "Repetición",
+	"Codec":          "Códec",
+	"Compressed":     "Comprimido",
+	"Ratio":          "Ratio",
+	"Files":          "Archivos",
+	"Open Data:":     "Datos abiertos:",
+	"Storage is paid by the dataset sponsor through the AWS Open Data program":       "El almacenamiento lo paga el patrocinador del conjunto de datos a través del programa AWS Open Data",
+	"Requester pays: requests and data transfer are billed to the profiling account": "Pago por solicitante: las solicitudes y la transferencia de datos se facturan a la cuenta que realiza el perfilado",
+	"Largest %d Objects":   "Los %d objetos más grandes",
+	"Heaviest %d Prefixes": "Los %d prefijos más pesados",
+	"Sizes count objects directly under each prefix, not in its subprefixes": "Los tamaños cuentan los objetos directamente bajo cada prefijo, no en sus subprefijos",
+	"Last Modified":                       "Última modificación",
+	"Key":                                 "Clave",
+	"Owner:":                              "Propietario:",
+	"Object Owner:":                       "Propiedad obj.:",
+	"another AWS account (not %s)":        "otra cuenta de AWS (no %s)",
+	"this account (%s)":                   "esta cuenta (%s)",
+	"unverified (credentials account %s)": "sin verificar (cuenta de las credenciales %s)",
+	"Billed to the bucket owner, not to the profiling account; exclude it from this account's cost totals": "Se factura al propietario del bucket, no a la cuenta que lo analiza; exclúyalo de los costes de esta cuenta",
+	"Listed From:":              "Origen:",
+	"S3 Inventory %s, as of %s": "S3 Inventory %s, a fecha de %s",
+	"Tags:":                     "Etiquetas:",
+	"Policy Evaluation: %s":     "Evaluación de políticas: %s",
+	"Policies:":                 "Políticas:",
+	"Violations":                "Infracciones",
+	"No policy violations":      "Sin infracciones de políticas",
+	"Public Access, Encryption and Lifecycle": "Acceso público, cifrado y ciclo de vida",
+	"Block Public Access: all settings on":    "Bloqueo de acceso público: todos los ajustes activados",
+	"Block Public Access: OFF for %s":         "Bloqueo de acceso público: DESACTIVADO para %s",
+	"Bucket policy: PUBLIC":                   "Política del bucket: PÚBLICA",
+	"Bucket policy: not public":               "Política del bucket: no pública",
+	"Default encryption: NONE":                "Cifrado predeterminado: NINGUNO",
+	"Default encryption: %s":                  "Cifrado predeterminado: %s",
+	"Lifecycle: no rules":                     "Ciclo de vida: sin reglas",
+	"Per-Object Fee Warnings":                 "Advertencias de tarifas por objeto",
+	"Plus %s in per-object fees (Intelligent-Tiering monitoring, archive overhead)": "Más %s en tarifas por objeto (monitorización de Intelligent-Tiering, sobrecarga de archivo)",
+	"Status":   "Estado",
+	"Avg Size": "Tamaño medio",
+	"Data":     "Datos",
+	"Fees":     "Tarifas",
+	"% Fees":   "% tarifas",
+	"current":  "actual",
+	"if moved": "si se mueve",
+	"Monthly costs. Intelligent-Tiering monitors objects of 128 KB and larger at $0.0025 per 1,000; GLACIER and DEEP_ARCHIVE bill 32 KB at the archive rate and 8 KB at the STANDARD rate per object.": "Costes mensuales. Intelligent-Tiering monitoriza los objetos de 128 KB o más a $0.0025 por cada 1.000; GLACIER y DEEP_ARCHIVE facturan por objeto 32 KB a la tarifa de archivo y 8 KB a la tarifa STANDARD.",
+	"Listed prefixes pay more in per-object fees than for their bytes; aggregate small objects or exclude them from lifecycle transitions.":                                                            "Los prefijos listados pagan más en tarifas por objeto que por sus bytes; agrupe los objetos pequeños o exclúyalos de las transiciones del ciclo de vida.",
+	"Intelligent-Tiering Simulation": "Simulación de Intelligent-Tiering",
+	"Monitored":                      "Monitorizados",
+	"Current":                        "Actual",
+	"Tiered":                         "Con niveles",
+	"Monitoring":                     "Monitorización",
+	"Savings":                        "Ahorro",
+	"Break-even":                     "Equilibrio",
+	"Payback":                        "Amortización",
+	"never":                          "nunca",
+	"%.1f mo":                        "%.1f meses",
+	"Monthly costs of the STANDARD and STANDARD_IA objects; objects under 128 KB are not moved or monitored.":                                                    "Costes mensuales de los objetos STANDARD y STANDARD_IA; los objetos de menos de 128 KB no se mueven ni se monitorizan.",
+	"Tiers assume objects are read no more often than written: 30+ days since the last write is infrequent, 90+ days archive instant.":                           "Los niveles suponen que los objetos se leen con la misma frecuencia que se escriben o menos: 30+ días desde la última escritura es acceso poco frecuente, 90+ días archivo instantáneo.",
+	"Break-even: share of the monitored bytes that must turn cold to cover the monitoring fee. Payback: months of savings to cover the one-time transition fee.": "Equilibrio: parte de los bytes monitorizados que debe enfriarse para cubrir la tarifa de monitorización. Amortización: meses de ahorro para cubrir la tarifa única de transición.",
+	"Monitoring only pays off for objects larger than %s that stay cold.":                                                                                        "La monitorización solo compensa en objetos de más de %s que permanecen fríos.",
+	"Dataset: %s":                       "Conjunto de datos: %s",
+	"Generated by s3-profiler on %s.":   "Generado por s3-profiler el %s.",
+	"Property":                          "Propiedad",
+	"Location":                          "Ubicación",
+	"Storage classes":                   "Clases de almacenamiento",
+	"Formats":                           "Formatos",
+	"Partition Scheme":                  "Esquema de particiones",
+	"No date partitions detected.":      "No se detectaron particiones por fecha.",
+	"%d date partitions from %s to %s.": "%d particiones por fecha desde %s hasta %s.",
+	"Schema":                            "Esquema",
+	"No schema sampled; run with --sample-content or --parquet-stats.": "No se muestreó ningún esquema; ejecute con --sample-content o --parquet-stats.",
+	"Read from %s (%s).":                         "Leído de %s (%s).",
+	"Freshness":                                  "Actualidad",
+	"Newest object written":                      "Objeto escrito más reciente",
+	"%d days before this profile":                "%d días antes de este perfil",
+	"Oldest object written":                      "Objeto escrito más antiguo",
+	"Latest partition":                           "Última partición",
+	"Retention Recommendation":                   "Recomendación de retención",
+	"Written over 90 days ago":                   "Escrito hace más de 90 días",
+	"Written over a year ago":                    "Escrito hace más de un año",
+	"Sample Keys":                                "Claves de ejemplo",
+	"Repartitioning Suggestions":                 "Sugerencias de reparticionado",
+	"Suggested: switch from %s to %s partitions": "Sugerencia: cambiar de particiones por %s a por %s",
+	"Scheme":       "Esquema",
+	"Avg size":     "Tamaño medio",
+	"Avg files":    "Archivos medios",
+	"Files @128MB": "Archivos @128MB",
+	"= current, * closest to the target size, ~ assumes objects are spread evenly": "= actual, * más cercano al tamaño objetivo, ~ supone objetos repartidos uniformemente",
+	"Writes Outside Partition Date": "Escrituras fuera de la fecha de la partición",
+	"Partition":                     "Partición",
+	"Reason":                        "Motivo",
+	"Incremental consumers that read each date partition once will miss these writes.": "Los consumidores incrementales que leen cada partición de fecha una sola vez no verán estas escrituras.",
+	"Late": "Tardías",
+	"Late: partitions whose newest object was written after the period ended": "Tardías: particiones cuyo objeto más reciente se escribió después del fin del periodo",
+	"Partitions by Period":        "Particiones por periodo",
+	"Period":                      "Periodo",
+	"Partitions":                  "Particiones",
+	"First":                       "Primera",
+	"Last":                        "Última",
+	"Full per-partition list: %s": "Lista completa por partición: %s",
+	"Detected Patterns":           "Patrones detectados",
+	"Pattern":                     "Patrón",
+	"Coverage":                    "Cobertura",
+	"(bucket)":                    "(bucket)",
+	"$/month":                     "$/mes",
+	"% Size":                      "% tamaño",
+	"%":                           "%",
+	"Avg age":                     "Edad media",
+	"Column":                      "Columna",
+	"Extension":                   "Extensión",
+	"Feature":                     "Función",
+	"ID":                          "ID",
+	"Keys":                        "Claves",
+	"Max":                         "Máx",
+	"Min":                         "Mín",
+	"Newest":                      "Más reciente",
+	"Nulls":                       "Nulos",
+	"Objects":                     "Objetos",
+	"Oldest":                      "Más antiguo",
+	"Prefix":                      "Prefijo",
+	"Scope":                       "Ámbito",
+	"Size":                        "Tamaño",
+	"Storage Class":               "Clase",
+	"Type":                        "Tipo",
+	"Value":                       "Valor",
+	"Versions":                    "Versiones",
+	"Cost":                        "Coste",
+	"Overhead":                    "Sobrecarga",
+	"Multipart":                   "Multiparte",
+	"Modeled":                     "Modelado",
+	"CloudWatch":                  "CloudWatch",
+	"Difference":                  "Diferencia",
+	"Delete Markers":              "Marcadores de borrado",
+	"Non-current":                 "No actual",
+	"Peak/s":                      "Pico/s",
+	"% of limit":                  "% del límite",
+	"Peak at":                     "Pico en",
+	"Burst secs":                  "Segs. ráfaga",
+	"Minute avg/s":                "Media min/s",
+	"Operation":                   "Operación",
+	"Requests":                    "Solicitudes",
+	"Throttled":                   "Limitadas",
+	"Elapsed":                     "Duración",
+	"Req/s":                       "Sol/s",
+	"Objects/s":                   "Objetos/s",
+	"Latency":                     "Latencia",
+	"Workers":                     "Workers",
+	"Account":                     "Cuenta",
+	"Profile":                     "Perfil",
+	"Buckets":                     "Buckets",
+	"Failed":                      "Fallidos",
+	"Total":                       "Total",
+	"Bucket":                      "Bucket",
+	">%dd":                        ">%dd",
+	"After":                       "Después",
+	"Sampled":                     "Muestras",
+	"Entropy":                     "Entropía",
+	"Content":                     "Contenido",
+	"Saved":                       "Ahorrado",
+	"By Name":                     "Por nombre",
+	"SSE-C":                       "SSE-C",
+	"Client":                      "Cliente",
+	"Unreadable":                  "Ilegibles",
+	"Small files":                 "Archivos pequeños",
+	"Compacted":                   "Compactados",
+	"Compacted size":              "Tamaño compactado",
+	"GETs/month":                  "GET/mes",
+	"Scanned":                     "Escaneado",
+	"% of data":                   "% de datos",
+	"$/1k queries":                "$/1k consultas",
+	"Size (bytes)":                "Tamaño (bytes)",
+	"Size (GiB)":                  "Tamaño (GiB)",
+	"Newest Object":               "Objeto más reciente",
+	"Partial Listing":             "Listado parcial",
+	"Severity":                    "Gravedad",
+	"Rule":                        "Regla",
+	"Finding":                     "Hallazgo",
+	"Score":                       "Puntuación",
+	"Key Scope":                   "Alcance",
+
+	// Messages
+	"%s (approximate, US East pricing)":            "%s (aproximado, precios de US East)",
+	"%s matching key(s)":                           "%s clave(s) coincidentes",
+	"%s matching sampled key(s)":                   "%s clave(s) de la muestra coincidentes",
+	"%s listed":                                    "%s listados",
+	"%s object(s) did not match this dimension":    "%s objeto(s) no coinciden con esta dimensión",
+	" of ~%s (est.)":                               " de ~%s (est.)",
+	", ~%s extrapolated (est.)":                    ", ~%s extrapolado (est.)",
+	"~%s extrapolated to the whole bucket (est.)":  "~%s extrapolado a todo el bucket (est.)",
+	"CORS: no rules":                               "CORS: sin reglas",
+	"Covers the listed objects only (lower bound)": "Solo cubre los objetos listados (cota inferior)",
+	"ESTIMATE":                                                "ESTIMACIÓN",
+	"EventBridge: disabled":                                   "EventBridge: desactivado",
+	"EventBridge: enabled (all events)":                       "EventBridge: activado (todos los eventos)",
+	"Externally accessible via policy: YES":                   "Accesible externamente por política: SÍ",
+	"Extrapolated totals: ~%s objects, ~%s, ~%s/month (est.)": "Totales extrapolados: ~%s objetos, ~%s, ~%s/mes (est.)",
+	"No Parquet files found":                                  "No se encontraron archivos Parquet",
+	"No inventory configurations":                             "Sin configuraciones de inventario",
+	"No keys with control characters, invalid UTF-8 or URL-encoded sequences": "Ninguna clave con caracteres de control, UTF-8 no válido o secuencias codificadas como URL",
+	"No objects found":               "No se encontraron objetos",
+	"No partition patterns detected": "No se detectaron patrones de partición",
+	"Retrieval fees only; restored copies are additionally billed at STANDARD rates while available.": "Solo tarifas de recuperación; las copias restauradas se facturan además a tarifa STANDARD mientras estén disponibles.",
+	"Website hosting: ENABLED":  "Alojamiento web: ACTIVADO",
+	"Website hosting: disabled": "Alojamiento web: desactivado",
+	"Listing stopped at the --limit of %s objects and the bucket's total object count is unknown (no CloudWatch storage metrics). All totals are lower bounds.":                                          "El listado se detuvo en el --limit de %s objetos y se desconoce el número total de objetos del bucket (sin métricas de almacenamiento de CloudWatch). Todos los totales son cotas inferiores.",
+	"Listing stopped at the --limit of %s objects, about %.2f%% of the %s objects reported by CloudWatch on %s. Figures cover the listed keys only; extrapolated totals assume they are representative.": "El listado se detuvo en el --limit de %s objetos, alrededor del %.2f%% de los %s objetos que CloudWatch registró el %s. Las cifras solo cubren las claves listadas; los totales extrapolados suponen que son representativas.",
+	"%s current": "%s actuales",
+	"%s/month":   "%s/mes",
+	"Version listing stopped at --limit; counts are lower bounds": "El listado de versiones se detuvo en --limit; los recuentos son cotas inferiores",
+	"Includes %s for non-current object versions":                 "Incluye %s por versiones no actuales de objetos",
+	"as of %s":             "a fecha de %s",
+	"%s uploads, %s parts": "%s cargas, %s partes",
+	"Only the parts of the first 1,000 incomplete uploads were listed":                                                         "Solo se listaron las partes de las primeras 1.000 cargas incompletas",
+	"CloudWatch storage metrics were not available to reconcile against (they need credentials on AWS and a complete listing)": "No había métricas de almacenamiento de CloudWatch con las que conciliar (requieren credenciales en AWS y un listado completo)",
+	"e.g. %s": "p. ej. %s",
+	"S3 supports 3,500 writes per second per partitioned prefix and answers bursts above it with 503 Slow Down until it splits the prefix; rates come from LastModified, so they are lower bounds.": "S3 admite 3.500 escrituras por segundo por prefijo particionado y responde a las ráfagas superiores con 503 Slow Down hasta que divide el prefijo; las tasas se derivan de LastModified, por lo que son cotas inferiores.",
+	"%s concurrency adapted from %d, between %d and %d, ending at %d of up to %d":                                                                                                                   "La concurrencia de %s se adaptó desde %d, entre %d y %d, y terminó en %d de un máximo de %d",
+	"S3 answered %s %s attempts with 503 Slow Down; the SDK retried them":                                                                                                                           "S3 respondió %s intentos de %s con 503 Slow Down; el SDK los reintentó",
+	"Read the tags of %s object(s) across %s prefix(es)":                                                                                                                                            "Se leyeron las etiquetas de %s objeto(s) en %s prefijo(s)",
+	", %s request(s) failed":                                       ", %s solicitud(es) fallaron",
+	"Counts are estimated from per-prefix samples":                 "Los recuentos se estiman a partir de muestras por prefijo",
+	"none; GetObject and HeadObject blocked (--no-content-access)": "ninguno; GetObject y HeadObject bloqueados (--no-content-access)",
+	"Not profiled: %s":                                             "No perfilada: %s",
+	"failed":                                                       "fallido",
+	"* Partial listing (--limit or interrupted); totals only cover the objects listed":                                  "* Listado parcial (--limit o interrumpido); los totales solo cubren los objetos listados",
+	"Bytes older than each age, counted from the last write.":                                                           "Bytes con más antigüedad que cada edad, contada desde la última escritura.",
+	"Estimated savings: %s/month":                                                                                       "Ahorro estimado: %s/mes",
+	"Monthly storage costs of the objects each rule would transition today; objects under 128 KB are not transitioned.": "Costes mensuales de almacenamiento de los objetos que cada regla movería hoy; los objetos de menos de 128 KB no se mueven.",
+	"Ages count from the last write: apply a rule only to data that is read no more often. STANDARD_IA and GLACIER_IR charge per GB retrieved and bill at least 30 and 90 days.": "La antigüedad se cuenta desde la última escritura: aplique una regla solo a datos que no se leen con más frecuencia. STANDARD_IA y GLACIER_IR cobran por GB recuperado y facturan al menos 30 y 90 días.",
+	"GLACIER and DEEP_ARCHIVE objects must be restored before they can be read and bill at least 90 and 180 days.":                                                               "Los objetos GLACIER y DEEP_ARCHIVE deben restaurarse antes de leerse y facturan al menos 90 y 180 días.",
+	"Pass --lifecycle-archive to also consider GLACIER and DEEP_ARCHIVE, which need a restore before reads.":                                                                     "Use --lifecycle-archive para considerar también GLACIER y DEEP_ARCHIVE, que requieren una restauración antes de leer.",
+	"Payback: months of savings to cover the one-time transition fee.":                                                                                                           "Amortización: meses de ahorro necesarios para cubrir la tarifa única de transición.",
+	"after %d days -> %s":                  "tras %d días -> %s",
+	"prefixes %s":                          "prefijos %s",
+	"excluding %s":                         "excluyendo %s",
+	"compressed":                           "comprimido",
+	"compressible":                         "comprimible",
+	"partly compressible":                  "parcialmente comprimible",
+	"Sampled the first %s of %d object(s)": "Se leyeron los primeros %s de %d objeto(s)",
+	"; %d read(s) failed":                  "; %d lectura(s) fallaron",
+	"Entropy is in bits per byte; 8 is random. Ratio is the DEFLATE (gzip) size of the sampled bytes; compressed or encrypted content stays near 1.":                                                                       "La entropía está en bits por byte; 8 es aleatorio. Ratio es el tamaño DEFLATE (gzip) de los bytes muestreados; el contenido comprimido o cifrado queda cerca de 1.",
+	"Savings assume the pipelines writing a prefix compress its objects at the sampled ratio; zstd usually does better, columnar formats with a codec are already compressed.":                                             "El ahorro supone que las canalizaciones que escriben un prefijo comprimen sus objetos al ratio muestreado; zstd suele comprimir más y los formatos columnares con códec ya están comprimidos.",
+	"By Name: objects named like encrypted files (.gpg, .pgp, .age, .enc, .aes, .crypt, .p7m).":                                                                                                                            "Por nombre: objetos con nombre de archivo cifrado (.gpg, .pgp, .age, .enc, .aes, .crypt, .p7m).",
+	"SSE-C and Client are estimated from the HeadObject sample: SSE-C objects cannot be read without the customer key, client-side encrypted objects carry an encryption envelope in their metadata.":                      "SSE-C y Cliente se estiman a partir de la muestra de HeadObject: los objetos SSE-C no se pueden leer sin la clave del cliente y los objetos cifrados en el cliente llevan un sobre de cifrado en sus metadatos.",
+	"Pass --enrich to also estimate SSE-C and client-side encrypted objects from HeadObject samples.":                                                                                                                      "Use --enrich para estimar también los objetos SSE-C y cifrados en el cliente a partir de muestras de HeadObject.",
+	"Crawlers, query engines and content sampling cannot read these objects; exclude them from catalogs, and keep their keys for as long as lifecycle rules retain the data.":                                              "Los rastreadores, los motores de consulta y el muestreo de contenido no pueden leer estos objetos; exclúyalos de los catálogos y conserve sus claves mientras las reglas de ciclo de vida retengan los datos.",
+	"Compacting to 128 MB files would turn %s files into %s.":                                                                                                                                                              "Compactar en archivos de 128 MB convertiría %s archivos en %s.",
+	"Every file costs query engines a GET request and open overhead; compact with a periodic job (e.g. Spark coalesce, Iceberg rewrite_data_files) or buffer writes upstream.":                                             "Cada archivo cuesta a los motores de consulta una solicitud GET y la sobrecarga de abrirlo; compacte con un trabajo periódico (p. ej. coalesce de Spark, rewrite_data_files de Iceberg) o agrupe las escrituras en origen.",
+	"* GETs from %d day(s) of server access logs (%s requests), scaled to 30 days.":                                                                                                                                        "* GET de %d día(s) de registros de acceso al servidor (%s solicitudes), escalados a 30 días.",
+	"Monthly storage, GET request and retrieval costs with the whole prefix in each class; < marks the cheapest. GETs are assumed to read an average object unless measured from access logs.":                             "Costes mensuales de almacenamiento, solicitudes GET y recuperación con todo el prefijo en cada clase; < marca la más barata. Se supone que cada GET lee un objeto medio salvo que se mida en los registros de acceso.",
+	"Intelligent-Tiering keeps objects read within 30 days frequent, assuming GETs hit objects at random; the IA classes bill objects under 128 KB as 128 KB. Transition fees and minimum storage durations are left out.": "Intelligent-Tiering mantiene en el nivel frecuente los objetos leídos en los últimos 30 días, suponiendo que los GET alcanzan objetos al azar; las clases IA facturan los objetos de menos de 128 KB como 128 KB. No se incluyen las tarifas de transición ni las duraciones mínimas de almacenamiento.",
+	"GLACIER and DEEP_ARCHIVE are priced with Standard restores for every GET and must be restored before reads.":                                                                                                          "GLACIER y DEEP_ARCHIVE se valoran con una restauración Standard por cada GET y deben restaurarse antes de leerse.",
+	"GLACIER and DEEP_ARCHIVE need a restore before every read and are not recommended; pass --lifecycle-archive to consider them.":                                                                                        "GLACIER y DEEP_ARCHIVE requieren una restauración antes de cada lectura y no se recomiendan; use --lifecycle-archive para considerarlas.",
+	"cheapest: %s, saves %s/month":            "más barata: %s, ahorra %s/mes",
+	"stays cheapest at any GET rate":          "sigue siendo la más barata con cualquier tasa de GET",
+	"stays cheapest above %s GETs/month":      "sigue siendo la más barata por encima de %s GET/mes",
+	"stays cheapest below %s GETs/month":      "sigue siendo la más barata por debajo de %s GET/mes",
+	"stays cheapest from %s to %s GETs/month": "sigue siendo la más barata entre %s y %s GET/mes",
+	"last %s of %s":                           "últimos %s de %s",
+	"= current, * suggested by repartitioning, ~ assumes objects are spread evenly":                                                                                                      "= actual, * sugerido por el reparticionado, ~ supone objetos repartidos uniformemente",
+	"Windows end with the newest partition; queries filter on the partition date. Athena bills $5 per TB scanned, at least 10 MB per query, before compression and columnar projection.": "Las ventanas terminan con la partición más reciente; las consultas filtran por la fecha de partición. Athena cobra 5 $ por TB escaneado, al menos 10 MB por consulta, antes de compresión y proyección de columnas.",
+	"listing in progress":                    "listado en curso",
+	"listing complete, analysis in progress": "listado completo, análisis en curso",
+	"%.0f objects/s":                         "%.0f objetos/s",
+	"Totals cover the objects listed so far; this file is removed once the bucket's reports are written.": "Los totales cubren los objetos listados hasta ahora; este archivo se elimina cuando se escriben los informes del bucket.",
+	"Costs converted to %s at %s per USD (%s)":                                                            "Costes convertidos a %s a %s por USD (%s)",
+	"yes":                           "sí",
+	"%d critical, %d warn, %d info": "%d críticos, %d advertencias, %d informativos",
+	"Profiling was interrupted after listing %s objects (%s). This report is partial: it covers the listed keys only, and all totals are lower bounds.": "La creación del perfil se interrumpió tras listar %s objetos (%s). Este informe es parcial: solo cubre las claves listadas y todos los totales son cotas inferiores.",
+	"%d partitions; the full list is in %s.": "%d particiones; la lista completa está en %s.",
+
+	// HTML report
+	"S3 Profile":             "Perfil S3",
+	"Generated":              "Generado",
+	"Estimate":               "Estimación",
+	"Summary":                "Resumen",
+	"Region":                 "Región",
+	"Creation Date":          "Fecha de creación",
+	"Total Objects":          "Objetos totales",
+	"Total Size":             "Tamaño total",
+	"Estimated Monthly Cost": "Coste mensual estimado",
+	"Storage Classes":        "Clases de almacenamiento",
+	"Prefix Treemap":         "Mapa de prefijos",
+	"Area is proportional to bytes. Click a prefix to drill down.": "El área es proporcional a los bytes. Haga clic en un prefijo para profundizar.",
+}
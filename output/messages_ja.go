@@ -0,0 +1,385 @@
+package output
+
+// messagesJA is the Japanese report catalog
+var messagesJA = map[string]string{
+	// Report titles
+	"Bucket Configuration: %s":      "バケット設定: %s",
+	"Bucket Summary: %s":            "バケット概要: %s",
+	"Dimension Analysis: %s":        "ディメンション分析: %s",
+	"Dimension: %s":                 "ディメンション: %s",
+	"Metadata Summary: %s":          "メタデータ概要: %s",
+	"Parquet Column Statistics: %s": "Parquet 列統計: %s",
+	"Partition Analysis: %s":        "パーティション分析: %s",
+	"Partition: %s":                 "パーティション: %s",
+	"Object Tags: %s":               "オブジェクトタグ: %s",
+	"Tag: %s":                       "タグ: %s",
+	"Multi-Account Roll-up":         "マルチアカウント集計",
+	"Interim Summary: %s":           "中間サマリー: %s",
+
+	// Labels
+	"Bucket Name:":          "バケット名:",
+	"Creation Date:":        "作成日:",
+	"Deleted Keys:":         "削除済みキー:",
+	"Earliest Modified:":    "最古の更新:",
+	"Examples:":             "例:",
+	"Hidden Size:":          "非表示サイズ:",
+	"Hidden Versions:":      "非表示版数:",
+	"Latest Modified:":      "最新の更新:",
+	"Objects:":              "オブジェクト:",
+	"Partition Count:":      "パーティション数:",
+	"Region:":               "リージョン:",
+	"Size:":                 "サイズ:",
+	"Total Objects:":        "総数:",
+	"Total Size:":           "総サイズ:",
+	"Versioning:":           "バージョニング:",
+	"Non-current Versions:": "非現行バージョン:",
+	"Non-current Size:":     "非現行サイズ:",
+	"Non-current Cost:":     "非現行コスト:",
+	"Delete Markers:":       "削除マーカー:",
+	"Current Objects:":      "現行オブジェクト:",
+	"Modeled Billable:":     "推定課金対象:",
+	"CloudWatch:":           "CloudWatch:",
+	"Incomplete Uploads:":   "未完了アップロード:",
+	"Tagged":                "タグあり",
+	"Untagged":              "タグなし",
+	"(not set)":             "(未設定)",
+	"Content Access:":       "コンテンツアクセス:",
+	"Key Scope:":            "キー範囲:",
+	"including %s":          "対象 %s",
+	"Started:":              "開始:",
+	"Updated:":              "更新:",
+	"Status:":               "状態:",
+	"Rate:":                 "速度:",
+	"PARTIAL":               "部分的",
+	"Partial":               "部分的",
+
+	"Oldest:": "最古:",
+	"Newest:": "最新:",
+	"Late data: newest object written %d day(s) after the partition period": "遅延データ: 最新オブジェクトはパーティション期間の %d 日後に書き込まれました",
+
+	// Sections
+	"Chargeable Features":                        "課金対象の機能",
+	"Checks Not Completed":                       "未完了のチェック",
+	"Content Samples":                            "コンテンツサンプル",
+	"Date Range":                                 "日付範囲",
+	"Deleted Data Still Billed":                  "課金が続く削除済みデータ",
+	"Estimated Monthly Storage Cost":             "推定月額ストレージ料金",
+	"Event Notifications":                        "イベント通知",
+	"External Access (IAM Access Analyzer)":      "外部アクセス (IAM Access Analyzer)",
+	"File Type Distribution":                     "ファイル種別の分布",
+	"Naming Consistency":                         "命名の一貫性",
+	"Object Attributes (HeadObject sample)":      "オブジェクト属性 (HeadObject サンプル)",
+	"Object Listing":                             "オブジェクト一覧",
+	"Restore Cost Estimates (archived prefixes)": "復元料金の見積もり (アーカイブ済みプレフィックス)",
+	"S3 Inventory":                               "S3 インベントリ",
+	"Size Distribution":                          "サイズ分布",
+	"Storage Class Breakdown":                    "ストレージクラス別内訳",
+	"Rejected Date Patterns":                     "却下された日付パターン",
+	"Unusual Key Encodings":                      "特殊なキーエンコーディング",
+	"Warnings":                                   "警告",
+	"Website Hosting and CORS":                   "ウェブサイトホスティングと CORS",
+	"Object Versions":                            "オブジェクトバージョン",
+	"Billable Size":                              "課金対象サイズ",
+	"Request-Rate Hotspots":                      "リクエストレートのホットスポット",
+	"Performance":                                "パフォーマンス",
+	"Tag Keys":                                   "タグキー",
+	"Untagged Objects by Prefix":                 "プレフィックス別のタグなしオブジェクト",
+	"Account: %s":                                "アカウント: %s",
+	"Data Age":                                   "データの経過日数",
+	"Lifecycle Recommendations":                  "ライフサイクルの推奨",
+	"Compressibility":                            "圧縮性",
+	"Client-Encrypted Content":                   "クライアント暗号化コンテンツ",
+	"Small Files":                                "小さなファイル",
+	"Storage Class What-If":                      "ストレージクラスの試算",
+	"Partition Pruning":                          "パーティションプルーニング",
+	"Top Prefixes":                               "上位プレフィックス",
+	"File Types":                                 "ファイル形式",
+	"Largest Objects":                            "最大のオブジェクト",
+	"Findings":                                   "検出事項",
+
+	// Table headers
+	"Parquet Schema": "Parquet スキーマ",
+	"Repetition":     "繰り返し",
+	"Codec":          "コーデック",
+	"Compressed":     "圧縮後",
+	"Ratio":          "圧縮率",
+	"Files":          "ファイル",
+	"Open Data:":     "オープンデータ:",
+	"Storage is paid by the dataset sponsor through the AWS Open Data program":       "ストレージ料金は AWS Open Data プログラムを通じてデータセットのスポンサーが負担します",
+	"Requester pays: requests and data transfer are billed to the profiling account": "リクエスタ支払い: リクエストとデータ転送はプロファイリングを行うアカウントに請求されます",
+	"Largest %d Objects":   "最大のオブジェクト %d 件",
+	"Heaviest %d Prefixes": "最も重いプレフィックス %d 件",
+	"Sizes count objects directly under each prefix, not in its subprefixes": "サイズは各プレフィックス直下のオブジェクトのみを数え、サブプレフィックスは含みません",
+	"Last Modified":                       "最終更新",
+	"Key":                                 "キー",
+	"Owner:":                              "所有者:",
+	"Object Owner:":                       "オブジェクト所有:",
+	"another AWS account (not %s)":        "別の AWS アカウント（%s ではない）",
+	"this account (%s)":                   "このアカウント（%s）",
+	"unverified (credentials account %s)": "未確認（認証情報のアカウント %s）",
+	"Billed to the bucket owner, not to the profiling account; exclude it from this account's cost totals": "バケット所有者に課金され、プロファイル実行アカウントには課金されません。このアカウントのコスト集計から除外してください",
+	"Listed From:":              "取得元:",
+	"S3 Inventory %s, as of %s": "S3 Inventory %s（%s 時点）",
+	"Tags:":                     "タグ:",
+	"Policy Evaluation: %s":     "ポリシー評価: %s",
+	"Policies:":                 "ポリシー:",
+	"Violations":                "違反",
+	"No policy violations":      "ポリシー違反はありません",
+	"Public Access, Encryption and Lifecycle": "パブリックアクセス、暗号化、ライフサイクル",
+	"Block Public Access: all settings on":    "ブロックパブリックアクセス: すべての設定が有効",
+	"Block Public Access: OFF for %s":         "ブロックパブリックアクセス: %s が無効",
+	"Bucket policy: PUBLIC":                   "バケットポリシー: パブリック",
+	"Bucket policy: not public":               "バケットポリシー: 非公開",
+	"Default encryption: NONE":                "デフォルト暗号化: なし",
+	"Default encryption: %s":                  "デフォルト暗号化: %s",
+	"Lifecycle: no rules":                     "ライフサイクル: ルールなし",
+	"Per-Object Fee Warnings":                 "オブジェクト単位の料金に関する警告",
+	"Plus %s in per-object fees (Intelligent-Tiering monitoring, archive overhead)": "これに加えてオブジェクト単位の料金 %s (Intelligent-Tiering の監視、アーカイブのオーバーヘッド)",
+	"Status":   "状態",
+	"Avg Size": "平均サイズ",
+	"Data":     "データ",
+	"Fees":     "料金",
+	"% Fees":   "料金の割合",
+	"current":  "現在",
+	"if moved": "移動時",
+	"Monthly costs. Intelligent-Tiering monitors objects of 128 KB and larger at $0.0025 per 1,000; GLACIER and DEEP_ARCHIVE bill 32 KB at the archive rate and 8 KB at the STANDARD rate per object.": "月額料金です。Intelligent-Tiering は 128 KB 以上のオブジェクトを 1,000 件あたり $0.0025 で監視します。GLACIER と DEEP_ARCHIVE はオブジェクトごとに 32 KB をアーカイブ料金、8 KB を STANDARD 料金で請求します。",
+	"Listed prefixes pay more in per-object fees than for their bytes; aggregate small objects or exclude them from lifecycle transitions.":                                                            "表示されたプレフィックスは、データ量よりもオブジェクト単位の料金の方が高くなっています。小さなオブジェクトをまとめるか、ライフサイクルの移行対象から除外してください。",
+	"Intelligent-Tiering Simulation": "Intelligent-Tiering シミュレーション",
+	"Monitored":                      "監視対象",
+	"Current":                        "現在",
+	"Tiered":                         "階層化後",
+	"Monitoring":                     "監視料金",
+	"Savings":                        "削減額",
+	"Break-even":                     "損益分岐",
+	"Payback":                        "回収期間",
+	"never":                          "なし",
+	"%.1f mo":                        "%.1f か月",
+	"Monthly costs of the STANDARD and STANDARD_IA objects; objects under 128 KB are not moved or monitored.":                                                    "STANDARD と STANDARD_IA オブジェクトの月額料金です。128 KB 未満のオブジェクトは移動も監視もされません。",
+	"Tiers assume objects are read no more often than written: 30+ days since the last write is infrequent, 90+ days archive instant.":                           "オブジェクトは書き込み以上の頻度で読まれないと仮定しています。最終書き込みから 30 日以上は低頻度、90 日以上はアーカイブインスタントです。",
+	"Break-even: share of the monitored bytes that must turn cold to cover the monitoring fee. Payback: months of savings to cover the one-time transition fee.": "損益分岐: 監視料金を賄うためにコールドになる必要がある監視対象バイトの割合。回収期間: 1 回限りの移行料金を削減額で賄うまでの月数。",
+	"Monitoring only pays off for objects larger than %s that stay cold.":                                                                                        "監視はコールドのままの %s を超えるオブジェクトでのみ元が取れます。",
+	"Dataset: %s":                       "データセット: %s",
+	"Generated by s3-profiler on %s.":   "s3-profiler により %s に生成。",
+	"Property":                          "項目",
+	"Location":                          "場所",
+	"Storage classes":                   "ストレージクラス",
+	"Formats":                           "形式",
+	"Partition Scheme":                  "パーティション方式",
+	"No date partitions detected.":      "日付パーティションは検出されませんでした。",
+	"%d date partitions from %s to %s.": "%d 個の日付パーティション（%s から %s）。",
+	"Schema":                            "スキーマ",
+	"No schema sampled; run with --sample-content or --parquet-stats.": "スキーマは未取得です。--sample-content または --parquet-stats を指定して実行してください。",
+	"Read from %s (%s).":                         "%s から読み取り（%s）。",
+	"Freshness":                                  "鮮度",
+	"Newest object written":                      "最新の書き込み",
+	"%d days before this profile":                "このプロファイルの %d 日前",
+	"Oldest object written":                      "最古の書き込み",
+	"Latest partition":                           "最新のパーティション",
+	"Retention Recommendation":                   "保持期間の推奨",
+	"Written over 90 days ago":                   "90 日以上前に書き込み",
+	"Written over a year ago":                    "1 年以上前に書き込み",
+	"Sample Keys":                                "サンプルキー",
+	"Repartitioning Suggestions":                 "再パーティションの提案",
+	"Suggested: switch from %s to %s partitions": "提案: %s 単位から %s 単位のパーティションへ変更",
+	"Scheme":       "方式",
+	"Avg size":     "平均サイズ",
+	"Avg files":    "平均ファイル数",
+	"Files @128MB": "128MB時ファイル数",
+	"= current, * closest to the target size, ~ assumes objects are spread evenly": "= 現在、* 目標サイズに最も近い、~ オブジェクトが均等に分布すると仮定",
+	"Writes Outside Partition Date": "パーティション日付外の書き込み",
+	"Partition":                     "パーティション",
+	"Reason":                        "理由",
+	"Incremental consumers that read each date partition once will miss these writes.": "各日付パーティションを一度だけ読む増分処理では、これらの書き込みが取りこぼされます。",
+	"Late": "遅延",
+	"Late: partitions whose newest object was written after the period ended": "遅延: 期間終了後に最新オブジェクトが書き込まれたパーティション",
+	"Partitions by Period":        "期間別パーティション",
+	"Period":                      "期間",
+	"Partitions":                  "パーティション",
+	"First":                       "最初",
+	"Last":                        "最後",
+	"Full per-partition list: %s": "パーティション一覧 (全件): %s",
+	"Detected Patterns":           "検出されたパターン",
+	"Pattern":                     "パターン",
+	"Coverage":                    "カバー率",
+	"(bucket)":                    "(バケット)",
+	"$/month":                     "$/月",
+	"% Size":                      "サイズ%",
+	"%":                           "%",
+	"Avg age":                     "平均経過",
+	"Column":                      "列",
+	"Extension":                   "拡張子",
+	"Feature":                     "機能",
+	"ID":                          "ID",
+	"Keys":                        "キー",
+	"Max":                         "最大",
+	"Min":                         "最小",
+	"Newest":                      "最新",
+	"Nulls":                       "NULL数",
+	"Objects":                     "オブジェクト",
+	"Oldest":                      "最古",
+	"Prefix":                      "プレフィックス",
+	"Scope":                       "範囲",
+	"Size":                        "サイズ",
+	"Storage Class":               "クラス",
+	"Type":                        "種別",
+	"Value":                       "値",
+	"Versions":                    "バージョン",
+	"Cost":                        "コスト",
+	"Overhead":                    "オーバーヘッド",
+	"Multipart":                   "マルチパート",
+	"Modeled":                     "推定",
+	"CloudWatch":                  "CloudWatch",
+	"Difference":                  "差分",
+	"Delete Markers":              "削除マーカー",
+	"Non-current":                 "非現行",
+	"Peak/s":                      "ピーク/秒",
+	"% of limit":                  "上限比",
+	"Peak at":                     "ピーク時刻",
+	"Burst secs":                  "バースト秒数",
+	"Minute avg/s":                "分平均/秒",
+	"Operation":                   "操作",
+	"Requests":                    "リクエスト",
+	"Throttled":                   "スロットル",
+	"Elapsed":                     "所要時間",
+	"Req/s":                       "リクエスト/秒",
+	"Objects/s":                   "オブジェクト/秒",
+	"Latency":                     "レイテンシ",
+	"Workers":                     "ワーカー",
+	"Account":                     "アカウント",
+	"Profile":                     "プロファイル",
+	"Buckets":                     "バケット数",
+	"Failed":                      "失敗",
+	"Total":                       "合計",
+	"Bucket":                      "バケット",
+	">%dd":                        ">%d日",
+	"After":                       "移行後",
+	"Sampled":                     "サンプル",
+	"Entropy":                     "エントロピー",
+	"Content":                     "内容",
+	"Saved":                       "削減量",
+	"By Name":                     "名前",
+	"SSE-C":                       "SSE-C",
+	"Client":                      "クライアント",
+	"Unreadable":                  "読み取り不可",
+	"Small files":                 "小ファイル数",
+	"Compacted":                   "圧縮後",
+	"Compacted size":              "圧縮後サイズ",
+	"GETs/month":                  "GET/月",
+	"Scanned":                     "スキャン量",
+	"% of data":                   "データ比",
+	"$/1k queries":                "$/1千クエリ",
+	"Size (bytes)":                "サイズ (バイト)",
+	"Size (GiB)":                  "サイズ (GiB)",
+	"Newest Object":               "最新オブジェクト",
+	"Partial Listing":             "部分的な一覧",
+	"Severity":                    "重大度",
+	"Rule":                        "ルール",
+	"Finding":                     "検出内容",
+	"Score":                       "スコア",
+	"Key Scope":                   "キー範囲",
+
+	// Messages
+	"%s (approximate, US East pricing)":            "%s (概算、US East 料金)",
+	"%s matching key(s)":                           "一致キー %s 件",
+	"%s matching sampled key(s)":                   "サンプル中の一致キー %s 件",
+	"%s listed":                                    "%s (一覧取得分)",
+	"%s object(s) did not match this dimension":    "%s 件のオブジェクトがこのディメンションに一致しませんでした",
+	" of ~%s (est.)":                               " / 約 %s (推定)",
+	", ~%s extrapolated (est.)":                    "、外挿値 約 %s (推定)",
+	"~%s extrapolated to the whole bucket (est.)":  "バケット全体への外挿値 約 %s (推定)",
+	"CORS: no rules":                               "CORS: ルールなし",
+	"Covers the listed objects only (lower bound)": "一覧取得したオブジェクトのみ (下限値)",
+	"ESTIMATE":                                                "推定値",
+	"EventBridge: disabled":                                   "EventBridge: 無効",
+	"EventBridge: enabled (all events)":                       "EventBridge: 有効 (全イベント)",
+	"Externally accessible via policy: YES":                   "ポリシーによる外部アクセス: あり",
+	"Extrapolated totals: ~%s objects, ~%s, ~%s/month (est.)": "外挿合計: 約 %s オブジェクト、約 %s、約 %s/月 (推定)",
+	"No Parquet files found":                                  "Parquet ファイルは見つかりませんでした",
+	"No inventory configurations":                             "インベントリ設定なし",
+	"No keys with control characters, invalid UTF-8 or URL-encoded sequences": "制御文字・不正な UTF-8・URL エンコード列を含むキーはありません",
+	"No objects found":               "オブジェクトが見つかりませんでした",
+	"No partition patterns detected": "パーティションパターンは検出されませんでした",
+	"Retrieval fees only; restored copies are additionally billed at STANDARD rates while available.": "取り出し料金のみ。復元されたコピーには利用可能な間 STANDARD 料金が別途かかります。",
+	"Website hosting: ENABLED":  "ウェブサイトホスティング: 有効",
+	"Website hosting: disabled": "ウェブサイトホスティング: 無効",
+	"Listing stopped at the --limit of %s objects and the bucket's total object count is unknown (no CloudWatch storage metrics). All totals are lower bounds.":                                          "--limit の %s オブジェクトで一覧取得を停止しました。バケットの総オブジェクト数は不明です (CloudWatch ストレージメトリクスなし)。合計はすべて下限値です。",
+	"Listing stopped at the --limit of %s objects, about %.2f%% of the %s objects reported by CloudWatch on %s. Figures cover the listed keys only; extrapolated totals assume they are representative.": "--limit の %s オブジェクトで一覧取得を停止しました。これは CloudWatch が報告した %[3]s オブジェクト (%[4]s 時点) の約 %[2].2f%% です。数値は一覧取得したキーのみを対象とし、外挿値はそれらが代表的であると仮定しています。",
+	"%s current": "現行 %s",
+	"%s/month":   "%s/月",
+	"Version listing stopped at --limit; counts are lower bounds": "バージョン一覧は --limit で停止しました。件数は下限値です",
+	"Includes %s for non-current object versions":                 "非現行オブジェクトバージョン分 %s を含みます",
+	"as of %s":             "%s 時点",
+	"%s uploads, %s parts": "%s 件のアップロード、%s 個のパート",
+	"Only the parts of the first 1,000 incomplete uploads were listed":                                                         "最初の 1,000 件の未完了アップロードのパートのみ一覧表示しました",
+	"CloudWatch storage metrics were not available to reconcile against (they need credentials on AWS and a complete listing)": "照合に使う CloudWatch のストレージメトリクスがありません (AWS の認証情報と完全な一覧が必要です)",
+	"e.g. %s": "例: %s",
+	"S3 supports 3,500 writes per second per partitioned prefix and answers bursts above it with 503 Slow Down until it splits the prefix; rates come from LastModified, so they are lower bounds.": "S3 はパーティション化されたプレフィックスごとに毎秒 3,500 件の書き込みをサポートし、プレフィックスが分割されるまでそれを超えるバーストには 503 Slow Down を返します。レートは LastModified から求めているため下限値です。",
+	"%s concurrency adapted from %d, between %d and %d, ending at %d of up to %d":                                                                                                                   "%s の同時実行数は %d から調整され、%d〜%d の間で推移し、最大 %[6]d のうち %[5]d で終了しました",
+	"S3 answered %s %s attempts with 503 Slow Down; the SDK retried them":                                                                                                                           "S3 は %s 件の %s 試行に 503 Slow Down を返しました。SDK が再試行しました",
+	"Read the tags of %s object(s) across %s prefix(es)":                                                                                                                                            "%[2]s 個のプレフィックスにわたる %[1]s 件のオブジェクトのタグを読み取りました",
+	", %s request(s) failed":                                       "、%s 件のリクエストが失敗しました",
+	"Counts are estimated from per-prefix samples":                 "件数はプレフィックスごとのサンプルから推定しています",
+	"none; GetObject and HeadObject blocked (--no-content-access)": "なし。GetObject と HeadObject はブロック済み (--no-content-access)",
+	"Not profiled: %s":                                             "プロファイルされていません: %s",
+	"failed":                                                       "失敗",
+	"* Partial listing (--limit or interrupted); totals only cover the objects listed":                                  "* 一覧は部分的です (--limit または中断)。合計は一覧されたオブジェクトのみを対象とします",
+	"Bytes older than each age, counted from the last write.":                                                           "各経過日数を超えるバイト数 (最終書き込みから数えた日数)。",
+	"Estimated savings: %s/month":                                                                                       "推定削減額: %s/月",
+	"Monthly storage costs of the objects each rule would transition today; objects under 128 KB are not transitioned.": "各ルールが現時点で移行するオブジェクトの月額ストレージ料金です。128 KB 未満のオブジェクトは移行されません。",
+	"Ages count from the last write: apply a rule only to data that is read no more often. STANDARD_IA and GLACIER_IR charge per GB retrieved and bill at least 30 and 90 days.": "経過日数は最終書き込みから数えます。書き込みより頻繁に読まれないデータにのみルールを適用してください。STANDARD_IA と GLACIER_IR は取り出し 1 GB ごとに課金され、最低 30 日と 90 日分が請求されます。",
+	"GLACIER and DEEP_ARCHIVE objects must be restored before they can be read and bill at least 90 and 180 days.":                                                               "GLACIER と DEEP_ARCHIVE のオブジェクトは読み取る前に復元が必要で、最低 90 日と 180 日分が請求されます。",
+	"Pass --lifecycle-archive to also consider GLACIER and DEEP_ARCHIVE, which need a restore before reads.":                                                                     "読み取り前に復元が必要な GLACIER と DEEP_ARCHIVE も検討するには --lifecycle-archive を指定してください。",
+	"Payback: months of savings to cover the one-time transition fee.":                                                                                                           "回収期間: 1 回限りの移行料金を削減額で回収するまでの月数。",
+	"after %d days -> %s":                  "%d 日後 -> %s",
+	"prefixes %s":                          "プレフィックス %s",
+	"excluding %s":                         "除外 %s",
+	"compressed":                           "圧縮済み",
+	"compressible":                         "圧縮可能",
+	"partly compressible":                  "一部圧縮可能",
+	"Sampled the first %s of %d object(s)": "%[2]d 個のオブジェクトの先頭 %[1]s を読み取り",
+	"; %d read(s) failed":                  "、%d 件の読み取りに失敗",
+	"Entropy is in bits per byte; 8 is random. Ratio is the DEFLATE (gzip) size of the sampled bytes; compressed or encrypted content stays near 1.":                                                                       "エントロピーはバイトあたりのビット数です（8 はランダム）。比率はサンプルしたバイトの DEFLATE (gzip) サイズで、圧縮済みや暗号化済みの内容は 1 に近くなります。",
+	"Savings assume the pipelines writing a prefix compress its objects at the sampled ratio; zstd usually does better, columnar formats with a codec are already compressed.":                                             "削減額は、プレフィックスに書き込むパイプラインがサンプルの比率でオブジェクトを圧縮すると仮定しています。zstd は通常さらに小さくなり、コーデック付きの列指向形式は圧縮済みです。",
+	"By Name: objects named like encrypted files (.gpg, .pgp, .age, .enc, .aes, .crypt, .p7m).":                                                                                                                            "名前: 暗号化ファイルの名前を持つオブジェクト (.gpg、.pgp、.age、.enc、.aes、.crypt、.p7m)。",
+	"SSE-C and Client are estimated from the HeadObject sample: SSE-C objects cannot be read without the customer key, client-side encrypted objects carry an encryption envelope in their metadata.":                      "SSE-C とクライアントは HeadObject のサンプルから推定されます。SSE-C オブジェクトは顧客キーなしでは読み取れず、クライアント側で暗号化されたオブジェクトはメタデータに暗号化エンベロープを持ちます。",
+	"Pass --enrich to also estimate SSE-C and client-side encrypted objects from HeadObject samples.":                                                                                                                      "--enrich を指定すると、HeadObject のサンプルから SSE-C とクライアント側暗号化のオブジェクトも推定します。",
+	"Crawlers, query engines and content sampling cannot read these objects; exclude them from catalogs, and keep their keys for as long as lifecycle rules retain the data.":                                              "クローラー、クエリエンジン、コンテンツのサンプリングはこれらのオブジェクトを読み取れません。カタログから除外し、ライフサイクルルールがデータを保持する間はキーを保管してください。",
+	"Compacting to 128 MB files would turn %s files into %s.":                                                                                                                                                              "128 MB のファイルにまとめると、%s 個のファイルが %s 個になります。",
+	"Every file costs query engines a GET request and open overhead; compact with a periodic job (e.g. Spark coalesce, Iceberg rewrite_data_files) or buffer writes upstream.":                                             "ファイルごとにクエリエンジンは GET リクエストとオープンのオーバーヘッドを負います。定期ジョブ (Spark の coalesce、Iceberg の rewrite_data_files など) でまとめるか、上流で書き込みをバッファしてください。",
+	"* GETs from %d day(s) of server access logs (%s requests), scaled to 30 days.":                                                                                                                                        "* GET はサーバーアクセスログ %d 日分 (%s リクエスト) から 30 日に換算。",
+	"Monthly storage, GET request and retrieval costs with the whole prefix in each class; < marks the cheapest. GETs are assumed to read an average object unless measured from access logs.":                             "プレフィックス全体を各クラスに置いた場合の月額のストレージ、GET リクエスト、取り出しの料金です。< は最安を示します。アクセスログで測定しない限り、GET は平均的なオブジェクトを読むものとします。",
+	"Intelligent-Tiering keeps objects read within 30 days frequent, assuming GETs hit objects at random; the IA classes bill objects under 128 KB as 128 KB. Transition fees and minimum storage durations are left out.": "Intelligent-Tiering は 30 日以内に読まれたオブジェクトを高頻度層に保ちます (GET はランダムなオブジェクトに当たると仮定)。IA クラスは 128 KB 未満のオブジェクトを 128 KB として課金します。移行料金と最低保存期間は含みません。",
+	"GLACIER and DEEP_ARCHIVE are priced with Standard restores for every GET and must be restored before reads.":                                                                                                          "GLACIER と DEEP_ARCHIVE は GET ごとに Standard の復元で計算され、読み取り前に復元が必要です。",
+	"GLACIER and DEEP_ARCHIVE need a restore before every read and are not recommended; pass --lifecycle-archive to consider them.":                                                                                        "GLACIER と DEEP_ARCHIVE は読み取りのたびに復元が必要なため推奨しません。検討するには --lifecycle-archive を指定してください。",
+	"cheapest: %s, saves %s/month":            "最安: %s、月 %s の節約",
+	"stays cheapest at any GET rate":          "どの GET 頻度でも最安のまま",
+	"stays cheapest above %s GETs/month":      "月 %s GET 以上で最安のまま",
+	"stays cheapest below %s GETs/month":      "月 %s GET 以下で最安のまま",
+	"stays cheapest from %s to %s GETs/month": "月 %s から %s GET まで最安のまま",
+	"last %s of %s":                           "%[2]s のうち直近 %[1]s",
+	"= current, * suggested by repartitioning, ~ assumes objects are spread evenly":                                                                                                      "= 現在、* 再パーティションの提案、~ オブジェクトが均等に分布すると仮定",
+	"Windows end with the newest partition; queries filter on the partition date. Athena bills $5 per TB scanned, at least 10 MB per query, before compression and columnar projection.": "ウィンドウは最新のパーティションで終わり、クエリはパーティションの日付で絞り込みます。Athena はスキャンした 1 TB あたり 5 ドル、クエリごとに最低 10 MB を課金します (圧縮や列の射影前の値)。",
+	"listing in progress":                    "一覧取得中",
+	"listing complete, analysis in progress": "一覧取得完了、分析中",
+	"%.0f objects/s":                         "%.0f オブジェクト/秒",
+	"Totals cover the objects listed so far; this file is removed once the bucket's reports are written.": "合計はこれまでに一覧取得したオブジェクトが対象です。このファイルはバケットのレポートが書き出されると削除されます。",
+	"Costs converted to %s at %s per USD (%s)":                                                            "コストは 1 USD = %[2]s %[1]s で換算 (%[3]s)",
+	"yes":                           "はい",
+	"%d critical, %d warn, %d info": "重大 %d 件、警告 %d 件、情報 %d 件",
+	"Profiling was interrupted after listing %s objects (%s). This report is partial: it covers the listed keys only, and all totals are lower bounds.": "%s 個のオブジェクト (%s) を一覧した時点でプロファイリングが中断されました。このレポートは部分的なもので、一覧されたキーのみを対象とし、すべての合計は下限値です。",
+	"%d partitions; the full list is in %s.": "%d 個のパーティション。全一覧は %s にあります。",
+
+	// HTML report
+	"S3 Profile":             "S3 プロファイル",
+	"Generated":              "生成日時",
+	"Estimate":               "推定",
+	"Summary":                "概要",
+	"Region":                 "リージョン",
+	"Creation Date":          "作成日",
+	"Total Objects":          "総オブジェクト数",
+	"Total Size":             "総サイズ",
+	"Estimated Monthly Cost": "推定月額料金",
+	"Storage Classes":        "ストレージクラス",
+	"Prefix Treemap":         "プレフィックスのツリーマップ",
+	"Area is proportional to bytes. Click a prefix to drill down.": "面積はバイト数に比例します。プレフィックスをクリックすると掘り下げます。",
+}
@@ -0,0 +1,173 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// MetricsTextfileName returns the file name of a bucket's metrics in the
+// textfile collector directory
+func (w *Writer) MetricsTextfileName(bucketName string) string {
+	return metricsTextfileName(w.bucket(bucketName))
+}
+
+// metricsTextfileName names the metrics file of an already redacted bucket
+// name
+func metricsTextfileName(bucket string) string {
+	return "s3_profiler_" + bucket + ".prom"
+}
+
+// metricFamily is one gauge with its samples in OpenMetrics text format
+type metricFamily struct {
+	name, help string
+	samples    []string
+}
+
+func (f *metricFamily) add(value float64, labels ...string) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	f.samples = append(f.samples, fmt.Sprintf("%s{%s} %s",
+		f.name, strings.Join(pairs, ","), strconv.FormatFloat(value, 'g', -1, 64)))
+}
+
+// labelEscaper escapes label values; the text format knows no other
+// escape sequences
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// BucketMetrics are the gauges of one profiled bucket. Names are redacted
+// like in the reports.
+type BucketMetrics struct {
+	Bucket    string
+	Objects   int64
+	Size      int64
+	Cost      float64
+	Truncated bool
+	// Newest is the LastModified of the newest object, zero when unknown
+	Newest         time.Time
+	Generated      time.Time
+	StorageClasses []types.ClassStats
+	// Prefixes are the top-level prefixes
+	Prefixes []types.PrefixStats
+}
+
+// BucketMetrics returns the gauges of a profiled bucket with its top-level
+// prefixes
+func (w *Writer) BucketMetrics(summary *types.BucketSummary, snapshot *types.Snapshot, prefixes []types.PrefixStats, newest time.Time) BucketMetrics {
+	m := BucketMetrics{
+		Bucket:    w.bucket(summary.Name),
+		Objects:   summary.TotalObjects,
+		Size:      summary.TotalSize,
+		Cost:      summary.EstimatedCost,
+		Truncated: summary.Truncated,
+		Newest:    newest,
+		Generated: snapshot.Generated,
+	}
+	for class, stats := range summary.StorageClasses {
+		m.StorageClasses = append(m.StorageClasses, types.ClassStats{StorageClass: class, ObjectCount: stats.Count, Size: stats.Size})
+	}
+	sort.Slice(m.StorageClasses, func(i, j int) bool {
+		return m.StorageClasses[i].StorageClass < m.StorageClasses[j].StorageClass
+	})
+	for _, p := range prefixes {
+		m.Prefixes = append(m.Prefixes, types.PrefixStats{Prefix: w.key(p.Prefix), ObjectCount: p.ObjectCount, Size: p.Size})
+	}
+	return m
+}
+
+// FormatMetrics renders the gauges of buckets in OpenMetrics text format,
+// each metric family once with a sample per bucket
+func FormatMetrics(buckets []BucketMetrics) string {
+	objects := &metricFamily{name: "s3_profiler_bucket_objects", help: "Objects in the bucket."}
+	size := &metricFamily{name: "s3_profiler_bucket_size_bytes", help: "Total size of the bucket's current objects."}
+	cost := &metricFamily{name: "s3_profiler_bucket_storage_cost_dollars", help: "Estimated monthly storage cost in USD."}
+	truncated := &metricFamily{name: "s3_profiler_bucket_listing_truncated", help: "1 when --limit stopped the listing, making totals lower bounds."}
+	newestObject := &metricFamily{name: "s3_profiler_bucket_newest_object_timestamp_seconds", help: "LastModified of the newest object."}
+	generated := &metricFamily{name: "s3_profiler_run_timestamp_seconds", help: "When the profile was taken."}
+	classObjects := &metricFamily{name: "s3_profiler_storage_class_objects", help: "Objects per storage class."}
+	classSize := &metricFamily{name: "s3_profiler_storage_class_size_bytes", help: "Size per storage class."}
+	prefixObjects := &metricFamily{name: "s3_profiler_prefix_objects", help: "Objects per top-level prefix."}
+	prefixSize := &metricFamily{name: "s3_profiler_prefix_size_bytes", help: "Size per top-level prefix."}
+
+	for _, m := range buckets {
+		objects.add(float64(m.Objects), "bucket", m.Bucket)
+		size.add(float64(m.Size), "bucket", m.Bucket)
+		cost.add(m.Cost, "bucket", m.Bucket)
+		isTruncated := 0.0
+		if m.Truncated {
+			isTruncated = 1
+		}
+		truncated.add(isTruncated, "bucket", m.Bucket)
+		if !m.Newest.IsZero() {
+			newestObject.add(float64(m.Newest.Unix()), "bucket", m.Bucket)
+		}
+		generated.add(float64(m.Generated.Unix()), "bucket", m.Bucket)
+
+		for _, stats := range m.StorageClasses {
+			classObjects.add(float64(stats.ObjectCount), "bucket", m.Bucket, "storage_class", stats.StorageClass)
+			classSize.add(float64(stats.Size), "bucket", m.Bucket, "storage_class", stats.StorageClass)
+		}
+
+		for _, p := range m.Prefixes {
+			prefixObjects.add(float64(p.ObjectCount), "bucket", m.Bucket, "prefix", p.Prefix)
+			prefixSize.add(float64(p.Size), "bucket", m.Bucket, "prefix", p.Prefix)
+		}
+	}
+
+	var b strings.Builder
+	for _, f := range []*metricFamily{objects, size, cost, truncated, newestObject, generated, classObjects, classSize, prefixObjects, prefixSize} {
+		if len(f.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", f.name)
+		for _, sample := range f.samples {
+			b.WriteString(sample + "\n")
+		}
+	}
+	b.WriteString("# EOF\n")
+	return b.String()
+}
+
+// WriteMetricsTextfile writes a bucket's gauges for the node_exporter
+// textfile collector. The file is written under a temporary name and
+// renamed, so the collector never reads a partial file.
+func (w *Writer) WriteMetricsTextfile(dir string, metrics BucketMetrics) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	// The collector only reads *.prom files, so the temporary file is
+	// skipped until it is renamed
+	path := filepath.Join(dir, metricsTextfileName(metrics.Bucket))
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if _, err := tmp.WriteString(FormatMetrics([]BucketMetrics{metrics})); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
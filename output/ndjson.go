@@ -0,0 +1,79 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// objectLine is one record of an NDJSON object export
+type objectLine struct {
+	Key          string `json:"key"`
+	Size         int64  `json:"size"`
+	LastModified string `json:"last_modified"`
+	StorageClass string `json:"storage_class"`
+	ETag         string `json:"etag,omitempty"`
+}
+
+// ObjectLines writes listed objects as newline-delimited JSON while a
+// bucket is being listed, one page at a time
+type ObjectLines struct {
+	w     *Writer
+	name  string
+	file  io.WriteCloser
+	buf   *bufio.Writer
+	count int64
+}
+
+// CreateObjectLines creates a bucket's NDJSON object export. It is written
+// to the output directory directly rather than staged, so consumers can
+// read it while the listing runs.
+func (w *Writer) CreateObjectLines(bucketName string) (*ObjectLines, error) {
+	name := w.ReportName(bucketName, "-objects.ndjson")
+	file, err := w.createExport(name)
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectLines{w: w, name: w.ExportName(name), file: file, buf: bufio.NewWriter(file)}, nil
+}
+
+// Name returns the file name of the export
+func (l *ObjectLines) Name() string {
+	return l.name
+}
+
+// Count returns the number of objects written
+func (l *ObjectLines) Count() int64 {
+	return l.count
+}
+
+// Write appends a page of objects and flushes it to the file
+func (l *ObjectLines) Write(page []types.ObjectMetadata) error {
+	enc := json.NewEncoder(l.buf)
+	enc.SetEscapeHTML(false)
+	for _, obj := range page {
+		if err := enc.Encode(objectLine{
+			Key:          l.w.rawKey(obj.Key),
+			Size:         obj.Size,
+			LastModified: obj.LastModified.In(l.w.location()).Format(time.RFC3339),
+			StorageClass: obj.StorageClass,
+			ETag:         obj.ETag,
+		}); err != nil {
+			return err
+		}
+	}
+	l.count += int64(len(page))
+	return l.buf.Flush()
+}
+
+// Close flushes and closes the export
+func (l *ObjectLines) Close() error {
+	if err := l.buf.Flush(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
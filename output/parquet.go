@@ -0,0 +1,111 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// WriteParquetStats writes per-partition column value ranges and null
+// ratios read from Parquet footers
+func (w *Writer) WriteParquetStats(bucketName string, stats []types.ParquetStats) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.tf("Parquet Column Statistics: %s", w.bucket(bucketName))))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	if len(stats) == 0 {
+		b.WriteString(w.t("No Parquet files found") + "\n")
+	}
+
+	for _, s := range stats {
+		b.WriteString(FormatSubHeader(w.tf("Partition: %s", w.key(s.Partition))))
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Parquet Files: %s (sampled %d", FormatNumber(s.Files), s.FilesSampled)
+		if s.FilesFailed > 0 {
+			fmt.Fprintf(&b, ", %d unreadable", s.FilesFailed)
+		}
+		fmt.Fprintf(&b, ")\nRows in Sample: %s\n\n", FormatNumber(s.Rows))
+
+		if len(s.Columns) == 0 {
+			b.WriteString("No column statistics available\n\n")
+			continue
+		}
+
+		fmt.Fprintf(&b, "%-30s %-20s %-28s %-28s %10s\n", w.t("Column"), w.t("Type"), w.t("Min"), w.t("Max"), w.t("Nulls"))
+		for _, c := range s.Columns {
+			minValue, maxValue := "n/a", "n/a"
+			if c.HasMinMax {
+				minValue, maxValue = w.value(c.Min), w.value(c.Max)
+			}
+			nulls := "n/a"
+			if c.NullsKnown {
+				nulls = FormatPercent(c.Nulls, c.Values)
+			}
+			fmt.Fprintf(&b, "%-30s %-20s %-28s %-28s %10s\n", c.Name, c.Type, minValue, maxValue, nulls)
+		}
+		b.WriteString("\n")
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-parquet.txt"), b.String())
+}
+
+// writeParquetSchema writes the columns, row counts and codecs read from
+// Parquet footers per partition. Columns missing from some sampled files
+// show the schema drifting within the partition.
+func (w *Writer) writeParquetSchema(b *strings.Builder, stats []types.ParquetStats) {
+	b.WriteString(FormatSubHeader(w.t("Parquet Schema")))
+	b.WriteString("\n")
+	if len(stats) == 0 {
+		b.WriteString(w.t("No Parquet files found") + "\n\n")
+		return
+	}
+
+	for _, s := range stats {
+		fmt.Fprintf(b, "%s\n", w.tf("Partition: %s", w.key(s.Partition)))
+		fmt.Fprintf(b, "  Files: %s (sampled %d", FormatNumber(s.Files), s.FilesSampled)
+		if s.FilesFailed > 0 {
+			fmt.Fprintf(b, ", %d unreadable", s.FilesFailed)
+		}
+		b.WriteString(")\n")
+		if s.FilesSampled == 0 {
+			b.WriteString("\n")
+			continue
+		}
+
+		fmt.Fprintf(b, "  Rows: %s in sample", FormatNumber(s.Rows))
+		if s.Files > int64(s.FilesSampled) {
+			estimate := float64(s.Rows) / float64(s.FilesSampled) * float64(s.Files)
+			fmt.Fprintf(b, ", ~%s in all files", FormatNumber(int64(estimate+0.5)))
+		}
+		fmt.Fprintf(b, " (%d row group(s))\n", s.RowGroups)
+		if len(s.Codecs) > 0 {
+			fmt.Fprintf(b, "  Codecs: %s\n", strings.Join(s.Codecs, ", "))
+		}
+		b.WriteString("\n")
+
+		fmt.Fprintf(b, "  %-30s %-26s %-10s %-14s %12s %7s %7s\n",
+			w.t("Column"), w.t("Type"), w.t("Repetition"), w.t("Codec"), w.t("Compressed"), w.t("Ratio"), w.t("Files"))
+		for _, c := range s.Columns {
+			ratio := "n/a"
+			if c.CompressedSize > 0 {
+				ratio = fmt.Sprintf("%.1fx", float64(c.UncompressedSize)/float64(c.CompressedSize))
+			}
+			fmt.Fprintf(b, "  %-30s %-26s %-10s %-14s %12s %7s %7s\n",
+				c.Name, c.LogicalType, c.Repetition, strings.Join(c.Codecs, ","),
+				FormatBytes(c.CompressedSize), ratio, fmt.Sprintf("%d/%d", c.Files, s.FilesSampled))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// value returns a data value as it should appear in reports; data values
+// are hashed whole when redacting
+func (w *Writer) value(v string) string {
+	if w.redactor == nil {
+		return v
+	}
+	return w.redactor.Value(v)
+}
@@ -0,0 +1,321 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// partitionRollupThreshold is the number of date partitions above which
+// the report summarizes them by month or year and the full list goes to a
+// CSV file
+const partitionRollupThreshold = 100
+
+// dateLayout renders partition dates
+const dateLayout = "2006-01-02"
+
+// NeedsPartitionRollup reports whether the partition report is summarized
+// and the full list written with WritePartitionList
+func NeedsPartitionRollup(partitions []types.Partition) bool {
+	dated := 0
+	for _, p := range partitions {
+		if !p.Date.IsZero() {
+			dated++
+		}
+	}
+	return dated > partitionRollupThreshold
+}
+
+// PartitionListName returns the file name of the full partition list
+func (w *Writer) PartitionListName(bucketName string) string {
+	return w.ReportName(bucketName, "-partitions.csv")
+}
+
+// partitionPeriod is one roll-up row: the date partitions of a pattern in
+// one month (daily patterns) or year (monthly patterns)
+type partitionPeriod struct {
+	scope, pattern, period string
+	partitions, late       int
+	objects, size          int64
+	first, last            time.Time
+}
+
+// writePartitionRollup summarizes date partitions by period
+func (w *Writer) writePartitionRollup(b *strings.Builder, bucketName string, partitions []types.Partition) {
+	var rows []*partitionPeriod
+	index := make(map[[3]string]*partitionPeriod)
+	for _, p := range partitions {
+		if p.Date.IsZero() {
+			continue
+		}
+		period := p.Date.Format("2006")
+		if strings.Contains(p.Pattern, "DD") {
+			period = p.Date.Format("2006-01")
+		}
+
+		k := [3]string{p.Scope, p.Pattern, period}
+		row, ok := index[k]
+		if !ok {
+			row = &partitionPeriod{scope: p.Scope, pattern: p.Pattern, period: period, first: p.Date, last: p.Date}
+			index[k] = row
+			rows = append(rows, row)
+		}
+		row.partitions++
+		if lateDays(p) > 0 {
+			row.late++
+		}
+		row.objects += p.ObjectCount
+		row.size += p.TotalSize
+		if p.Date.Before(row.first) {
+			row.first = p.Date
+		}
+		if p.Date.After(row.last) {
+			row.last = p.Date
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].scope != rows[j].scope {
+			return rows[i].scope < rows[j].scope
+		}
+		if rows[i].pattern != rows[j].pattern {
+			return rows[i].pattern < rows[j].pattern
+		}
+		return rows[i].period < rows[j].period
+	})
+
+	b.WriteString(FormatSubHeader(w.t("Partitions by Period")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%-20s %-26s %-8s %10s %6s %12s %14s  %-10s  %s\n",
+		w.t("Scope"), w.t("Pattern"), w.t("Period"), w.t("Partitions"), w.t("Late"), w.t("Objects"), w.t("Size"), w.t("First"), w.t("Last"))
+	shown := w.opts.Table.visibleRows(len(rows), 0)
+	for _, row := range rows[:shown] {
+		fmt.Fprintf(b, "%-20s %-26s %-8s %10d %6d %12s %14s  %-10s  %s\n",
+			w.key(row.scope), row.pattern, row.period, row.partitions, row.late,
+			FormatNumber(row.objects), FormatBytes(row.size),
+			row.first.Format(dateLayout), row.last.Format(dateLayout))
+	}
+	writeMoreFooter(b, shown, len(rows))
+	b.WriteString(w.t("Late: partitions whose newest object was written after the period ended") + "\n\n")
+	b.WriteString(w.tf("Full per-partition list: %s", w.PartitionListName(bucketName)) + "\n")
+}
+
+// WritePartitionList writes every detected partition as CSV
+func (w *Writer) WritePartitionList(bucketName string, partitions []types.Partition) error {
+	var b strings.Builder
+	cw := csv.NewWriter(&b)
+	if err := cw.Write([]string{"scope", "partition", "pattern", "date", "objects", "size", "oldest_modified", "newest_modified", "late_days", "outside_objects", "outside_size", "example"}); err != nil {
+		return err
+	}
+	for _, p := range partitions {
+		var date, example string
+		if !p.Date.IsZero() {
+			date = p.Date.Format(dateLayout)
+		}
+		if len(p.Examples) > 0 {
+			example = w.key(p.Examples[0])
+		}
+		record := []string{
+			w.key(p.Scope),
+			w.key(p.Scope + p.Prefix),
+			p.Pattern,
+			date,
+			strconv.FormatInt(p.ObjectCount, 10),
+			strconv.FormatInt(p.TotalSize, 10),
+			formatCSVTime(p.Oldest, w.location()),
+			formatCSVTime(p.Newest, w.location()),
+			strconv.Itoa(lateDays(p)),
+			strconv.FormatInt(p.OutsideObjects, 10),
+			strconv.FormatInt(p.OutsideSize, 10),
+			example,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	return w.writeFile(w.PartitionListName(bucketName), b.String())
+}
+
+// lateDays returns how many whole days after the end of a date partition's
+// period its newest object was written, or 0. Late data means the
+// partition was still being written after its period closed.
+func lateDays(p types.Partition) int {
+	if p.Date.IsZero() || p.Newest.IsZero() {
+		return 0
+	}
+	end := p.Date.AddDate(0, 1, 0)
+	if strings.Contains(p.Pattern, "DD") {
+		end = p.Date.AddDate(0, 0, 1)
+	}
+	if !p.Newest.After(end) {
+		return 0
+	}
+	return int(p.Newest.Sub(end) / (24 * time.Hour))
+}
+
+// formatCSVTime renders a timestamp for CSV exports, empty when unknown
+func formatCSVTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// writeBackfills lists date partitions receiving writes outside their date
+func (w *Writer) writeBackfills(b *strings.Builder, backfills []types.PartitionBackfill) {
+	b.WriteString(FormatSubHeader(w.t("Writes Outside Partition Date")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%-40s %12s %14s %7s  %-10s  %s\n",
+		w.t("Partition"), w.t("Objects"), w.t("Size"), w.t("%"), w.t("Newest"), w.t("Reason"))
+	shown := w.opts.Table.visibleRows(len(backfills), 20)
+	for _, bf := range backfills[:shown] {
+		fmt.Fprintf(b, "%-40s %12s %14s %6.1f%%  %-10s  %s\n",
+			w.key(bf.Scope+bf.Prefix), FormatNumber(bf.Objects), FormatBytes(bf.Size), bf.Share*100,
+			bf.Newest.In(w.location()).Format(dateLayout), bf.Reason)
+	}
+	writeMoreFooter(b, shown, len(backfills))
+	b.WriteString(w.t("Incremental consumers that read each date partition once will miss these writes.") + "\n\n")
+}
+
+// writeRepartitionSuggestions shows alternative granularities for skewed or
+// off-target date patterns
+func (w *Writer) writeRepartitionSuggestions(b *strings.Builder, suggestions []types.RepartitionSuggestion) {
+	b.WriteString(FormatSubHeader(w.t("Repartitioning Suggestions")))
+	b.WriteString("\n")
+	for _, s := range suggestions {
+		fmt.Fprintf(b, "%s%s: %s\n", w.key(s.Scope), s.Pattern, s.Reason)
+		if s.Recommended != s.Current {
+			b.WriteString("  " + w.tf("Suggested: switch from %s to %s partitions", s.Current, s.Recommended) + "\n")
+		}
+		fmt.Fprintf(b, "  %-8s %12s %14s %12s %14s\n",
+			w.t("Scheme"), w.t("Partitions"), w.t("Avg size"), w.t("Avg files"), w.t("Files @128MB"))
+		for _, scheme := range s.Schemes {
+			marker := " "
+			switch scheme.Granularity {
+			case s.Recommended:
+				marker = "*"
+			case s.Current:
+				marker = "="
+			}
+			partitions := FormatNumber(scheme.Partitions)
+			if scheme.Estimated {
+				partitions = "~" + partitions
+			}
+			fmt.Fprintf(b, "%s %-8s %12s %14s %12.1f %14s\n", marker, scheme.Granularity,
+				partitions, FormatBytes(scheme.AverageSize), scheme.AverageFiles, FormatNumber(scheme.FilesAtTarget))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(w.t("= current, * closest to the target size, ~ assumes objects are spread evenly") + "\n\n")
+}
+
+// writePruning compares the data queries over recent windows scan under
+// each partition granularity
+func (w *Writer) writePruning(b *strings.Builder, estimates []types.PruningEstimate) {
+	b.WriteString(FormatSubHeader(w.t("Partition Pruning")))
+	b.WriteString("\n")
+	for _, e := range estimates {
+		fmt.Fprintf(b, "%s%s: %s\n", w.key(e.Scope), e.Pattern,
+			w.tf("last %s of %s", e.Window, FormatBytes(e.TotalSize)))
+		fmt.Fprintf(b, "  %-8s %12s %14s %10s %14s\n",
+			w.t("Scheme"), w.t("Partitions"), w.t("Scanned"), w.t("% of data"), w.t("$/1k queries"))
+		for _, scan := range e.Scans {
+			marker := " "
+			switch scan.Granularity {
+			case e.Recommended:
+				marker = "*"
+			case e.Current:
+				marker = "="
+			}
+			partitions := FormatNumber(scan.Partitions)
+			switch {
+			case scan.Granularity == "none":
+				partitions = "-"
+			case scan.Estimated:
+				partitions = "~" + partitions
+			}
+			fmt.Fprintf(b, "%s %-8s %12s %14s %10s %14s\n", marker, scan.Granularity,
+				partitions, FormatBytes(scan.Size), FormatPercent(scan.Size, e.TotalSize), w.cost(scan.Cost*1000))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(w.t("= current, * suggested by repartitioning, ~ assumes objects are spread evenly") + "\n")
+	b.WriteString(w.t("Windows end with the newest partition; queries filter on the partition date. Athena bills $5 per TB scanned, at least 10 MB per query, before compression and columnar projection.") + "\n\n")
+}
+
+// writeHotspots lists prefixes whose write bursts approach the S3
+// per-prefix request-rate limit, with a key naming that spreads them
+func (w *Writer) writeHotspots(b *strings.Builder, hotspots []types.PrefixHotspot) {
+	b.WriteString(FormatSubHeader(w.t("Request-Rate Hotspots")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%-40s %10s %10s  %-20s %12s %12s\n",
+		w.t("Prefix"), w.t("Peak/s"), w.t("% of limit"), w.t("Peak at"), w.t("Burst secs"), w.t("Minute avg/s"))
+	shown := w.opts.Table.visibleRows(len(hotspots), 20)
+	for _, h := range hotspots[:shown] {
+		prefix := w.key(h.Prefix)
+		if h.Prefix == "" {
+			prefix = w.t("(bucket)")
+		}
+		fmt.Fprintf(b, "%-40s %10s %9.0f%%  %-20s %12s %12.0f\n",
+			prefix, FormatNumber(h.PeakWrites), h.Share*100,
+			h.PeakAt.In(w.location()).Format("2006-01-02 15:04:05"),
+			FormatNumber(h.BurstSeconds), h.SustainedRate)
+	}
+	writeMoreFooter(b, shown, len(hotspots))
+	b.WriteString("\n")
+	for _, h := range hotspots[:shown] {
+		prefix := w.key(h.Prefix)
+		if h.Prefix == "" {
+			prefix = w.t("(bucket)")
+		}
+		fmt.Fprintf(b, "%s: %s\n", prefix, h.Suggestion)
+		b.WriteString("  " + w.tf("e.g. %s", w.key(h.Example)) + "\n")
+	}
+	b.WriteString(w.t("S3 supports 3,500 writes per second per partitioned prefix and answers bursts above it with 503 Slow Down until it splits the prefix; rates come from LastModified, so they are lower bounds.") + "\n\n")
+}
+
+// maxListedSmallFiles is the default number of rows of the small-file table
+const maxListedSmallFiles = 20
+
+// writeSmallFiles lists directories holding many small files with the
+// files they would hold after compaction
+func (w *Writer) writeSmallFiles(b *strings.Builder, dirs []types.SmallFilePartition) {
+	b.WriteString(FormatSubHeader(w.t("Small Files")))
+	b.WriteString("\n")
+
+	dirs = append([]types.SmallFilePartition(nil), dirs...)
+	sortTable(dirs, w.opts.Table, func(d types.SmallFilePartition) tableRow {
+		return tableRow{name: d.Prefix, count: d.ObjectCount, size: d.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(dirs), maxListedSmallFiles)
+
+	var files, compacted int64
+	fmt.Fprintf(b, "%-40s %12s %12s %12s %12s %12s %14s\n",
+		w.t("Prefix"), w.t("Objects"), w.t("Small files"), w.t("Size"), w.t("Avg size"), w.t("Compacted"), w.t("Compacted size"))
+	for _, d := range dirs[:shown] {
+		fmt.Fprintf(b, "%-40s %12s %12s %12s %12s %12s %14s\n",
+			w.key(d.Prefix),
+			FormatNumber(d.ObjectCount),
+			FormatNumber(d.SmallFiles),
+			FormatBytes(d.Size),
+			FormatBytes(d.AverageSize),
+			FormatNumber(d.CompactedFiles),
+			FormatBytes(d.CompactedSize))
+	}
+	writeMoreFooter(b, shown, len(dirs))
+	for _, d := range dirs {
+		files += d.ObjectCount
+		compacted += d.CompactedFiles
+	}
+	b.WriteString(w.tf("Compacting to 128 MB files would turn %s files into %s.", FormatNumber(files), FormatNumber(compacted)) + "\n")
+	b.WriteString(w.t("Every file costs query engines a GET request and open overhead; compact with a periodic job (e.g. Spark coalesce, Iceberg rewrite_data_files) or buffer writes upstream.") + "\n\n")
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WritePolicyReport writes the result of evaluating the Rego policies
+// against a bucket
+func (w *Writer) WritePolicyReport(bucketName string, policies, violations []string) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.tf("Policy Evaluation: %s", w.bucket(bucketName))))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	fmt.Fprintf(&b, "%s %s\n\n", w.label("Policies:", 12), strings.Join(policies, ", "))
+
+	b.WriteString(FormatSubHeader(w.t("Violations")))
+	b.WriteString("\n")
+	if len(violations) == 0 {
+		b.WriteString(w.t("No policy violations") + "\n")
+	}
+	for _, v := range violations {
+		fmt.Fprintf(&b, "  [x] %s\n", v)
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-policy.txt"), b.String())
+}
@@ -0,0 +1,140 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// QueryFormat selects how query results are printed
+type QueryFormat string
+
+const (
+	QueryFormatTable QueryFormat = "table"
+	QueryFormatCSV   QueryFormat = "csv"
+	QueryFormatJSON  QueryFormat = "json"
+)
+
+// ParseQueryFormat converts a flag value into a QueryFormat
+func ParseQueryFormat(value string) (QueryFormat, error) {
+	switch QueryFormat(strings.ToLower(value)) {
+	case "", QueryFormatTable:
+		return QueryFormatTable, nil
+	case QueryFormatCSV:
+		return QueryFormatCSV, nil
+	case QueryFormatJSON:
+		return QueryFormatJSON, nil
+	}
+	return "", fmt.Errorf("invalid query format %q (expected table, csv or json)", value)
+}
+
+// WriteQueryResult prints query results. Values are int64, float64,
+// string or nil for NULL; tables right-align numbers.
+func WriteQueryResult(out io.Writer, format QueryFormat, columns []string, rows [][]any) error {
+	switch format {
+	case QueryFormatCSV:
+		cw := csv.NewWriter(out)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			record := make([]string, len(row))
+			for i, v := range row {
+				if v != nil {
+					record[i] = queryValue(v)
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case QueryFormatJSON:
+		objects := make([]map[string]any, 0, len(rows))
+		for _, row := range rows {
+			object := make(map[string]any, len(columns))
+			for i, name := range columns {
+				object[name] = row[i]
+			}
+			objects = append(objects, object)
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(objects)
+	}
+
+	_, err := io.WriteString(out, formatQueryTable(columns, rows))
+	return err
+}
+
+// formatQueryTable lays out results in aligned columns
+func formatQueryTable(columns []string, rows [][]any) string {
+	widths := make([]int, len(columns))
+	numeric := make([]bool, len(columns))
+	cells := make([][]string, len(rows))
+	for i, name := range columns {
+		widths[i] = utf8.RuneCountInString(name)
+	}
+	for r, row := range rows {
+		cells[r] = make([]string, len(row))
+		for i, v := range row {
+			cells[r][i] = queryValue(v)
+			widths[i] = max(widths[i], utf8.RuneCountInString(cells[r][i]))
+			switch v.(type) {
+			case int64, float64:
+				numeric[i] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(values []string) {
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v))
+			if numeric[i] {
+				b.WriteString(pad + v)
+			} else if i < len(values)-1 {
+				b.WriteString(v + pad)
+			} else {
+				b.WriteString(v)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(columns)
+	rules := make([]string, len(columns))
+	for i := range columns {
+		rules[i] = strings.Repeat("-", widths[i])
+	}
+	writeRow(rules)
+	for _, row := range cells {
+		writeRow(row)
+	}
+	fmt.Fprintf(&b, "\n(%d row(s))\n", len(rows))
+	return b.String()
+}
+
+// queryValue renders a result value
+func queryValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redactHashLength is the number of hex characters kept from each hash
+const redactHashLength = 10
+
+// Redactor replaces bucket names and key contents with stable hashes so that
+// reports can be shared externally. Key structure is preserved: separators,
+// file extensions, Hive-style partition names and date partition segments
+// are kept, everything else is hashed per path segment. Other numbers, such
+// as account or customer IDs, are hashed too.
+type Redactor struct {
+	salt string
+}
+
+// NewRedactor creates a redactor; the salt makes hashes unguessable for
+// short, well-known names
+func NewRedactor(salt string) *Redactor {
+	return &Redactor{salt: salt}
+}
+
+// Bucket redacts a bucket name
+func (r *Redactor) Bucket(name string) string {
+	return "bucket-" + r.hash(name)
+}
+
+// Value redacts a whole value, such as a tag or a Parquet column bound
+func (r *Redactor) Value(value string) string {
+	if value == "" {
+		return value
+	}
+	return r.hash(value)
+}
+
+// Key redacts an object key or prefix segment by segment
+func (r *Redactor) Key(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = r.segment(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// segment redacts a single path segment
+func (r *Redactor) segment(segment string) string {
+	if segment == "" || isDateComponent(segment) {
+		return segment
+	}
+
+	// Keep Hive-style partition column names, redact the value
+	if name, value, ok := strings.Cut(segment, "="); ok {
+		return name + "=" + r.segment(value)
+	}
+
+	// Keep the file extension so type statistics remain meaningful
+	ext := path.Ext(segment)
+	if ext == segment || len(ext) > 8 {
+		ext = ""
+	}
+
+	return r.hash(strings.TrimSuffix(segment, ext)) + ext
+}
+
+// hash returns a truncated salted SHA-256 of the value
+func (r *Redactor) hash(value string) string {
+	sum := sha256.Sum256([]byte(r.salt + value))
+	return hex.EncodeToString(sum[:])[:redactHashLength]
+}
+
+// dateLayouts are the date and time forms of partition segments
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006_01_02",
+	"20060102",
+	"2006-01",
+	"2006-01-02-15",
+	"2006-01-02T15",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02_15-04-05",
+	"2006010215",
+}
+
+// isDateComponent reports whether a segment is part of a date partition:
+// a year, a month, day, hour or minute number, or a date or timestamp.
+// These describe layout rather than content.
+func isDateComponent(segment string) bool {
+	if n, err := strconv.Atoi(segment); err == nil && segment[0] != '+' && segment[0] != '-' {
+		switch len(segment) {
+		case 1, 2:
+			return n < 60
+		case 4:
+			return n >= 1970 && n <= 2100
+		}
+	}
+	for _, layout := range dateLayouts {
+		if len(segment) != len(layout) && !strings.HasSuffix(layout, "Z07:00") {
+			continue
+		}
+		if t, err := time.Parse(layout, segment); err == nil && t.Year() >= 1970 && t.Year() <= 2100 {
+			return true
+		}
+	}
+	return false
+}
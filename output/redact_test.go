@@ -0,0 +1,82 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactorKey(t *testing.T) {
+	r := NewRedactor("salt")
+	h := r.hash
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"logs/", h("logs") + "/"},
+		{"events/year=2024/month=01/day=05/part-0001.parquet", h("events") + "/year=2024/month=01/day=05/" + h("part-0001") + ".parquet"},
+		{"events/dt=2024-01-05/hour=23/a.json.gz", h("events") + "/dt=2024-01-05/hour=23/" + h("a.json") + ".gz"},
+		{"2024/01/05/report.csv", "2024/01/05/" + h("report") + ".csv"},
+		{"ts=2024-01-05T10:30:00Z/x", "ts=2024-01-05T10:30:00Z/" + h("x")},
+		{"snapshots/20240105/data", h("snapshots") + "/20240105/" + h("data")},
+		// Numbers that are not dates are IDs and get hashed
+		{"customers/123456789012/invoice.pdf", h("customers") + "/" + h("123456789012") + "/" + h("invoice") + ".pdf"},
+		{"accounts/account=4242/", h("accounts") + "/account=" + h("4242") + "/"},
+		{"orders/555-0100/", h("orders") + "/" + h("555-0100") + "/"},
+		{"runs/20241399/", h("runs") + "/" + h("20241399") + "/"},
+		{"tmp/99/", h("tmp") + "/" + h("99") + "/"},
+		// Extensions over eight characters are part of the name
+		{"a.verylongext", h("a.verylongext")},
+		{".hidden", h(".hidden")},
+	}
+	for _, tt := range tests {
+		if got := r.Key(tt.key); got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestRedactorSalt(t *testing.T) {
+	a, b := NewRedactor("one"), NewRedactor("two")
+	if a.Bucket("data") == b.Bucket("data") {
+		t.Error("bucket hashes do not depend on the salt")
+	}
+	if got := a.Bucket("data"); got != a.Bucket("data") || !strings.HasPrefix(got, "bucket-") || len(got) != len("bucket-")+redactHashLength {
+		t.Errorf("Bucket(data) = %q, want a stable bucket- hash", got)
+	}
+	if a.Value("") != "" || a.Value("prod") == "prod" {
+		t.Error("Value must hash non-empty values and keep empty ones")
+	}
+}
+
+func TestIsDateComponent(t *testing.T) {
+	tests := []struct {
+		segment string
+		want    bool
+	}{
+		{"2024", true},
+		{"1969", false},
+		{"01", true},
+		{"7", true},
+		{"59", true},
+		{"60", false},
+		{"123", false},
+		{"2024-01-05", true},
+		{"2024_01_05", true},
+		{"20240105", true},
+		{"2024-02-30", false},
+		{"2024-01", true},
+		{"2024-01-05T10", true},
+		{"2024-01-05T10:30:00+02:00", true},
+		{"2024010510", true},
+		{"+12", false},
+		{"-1", false},
+		{"12345678", false},
+		{"abc", false},
+	}
+	for _, tt := range tests {
+		if got := isDateComponent(tt.segment); got != tt.want {
+			t.Errorf("isDateComponent(%q) = %v, want %v", tt.segment, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// sarifFingerprint is the partialFingerprints key used to deduplicate
+// findings across runs
+const sarifFingerprint = "s3ProfilerFinding/v1"
+
+// sarifLog is the subset of SARIF 2.1.0 needed to report findings
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name"`
+	ShortDescription     sarifMessage   `json:"shortDescription"`
+	Help                 *sarifMessage  `json:"help,omitempty"`
+	DefaultConfiguration sarifLevel     `json:"defaultConfiguration"`
+	Properties           map[string]any `json:"properties"`
+}
+
+type sarifLevel struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation struct {
+		URI string `json:"uri"`
+	} `json:"artifactLocation"`
+}
+
+// sarifHelp returns the rule's recommendation as SARIF help text
+func sarifHelp(rule types.FindingRule) *sarifMessage {
+	if rule.Recommendation == "" {
+		return nil
+	}
+	return &sarifMessage{Text: rule.Recommendation}
+}
+
+// WriteSARIF writes findings as a SARIF 2.1.0 log for code scanning and
+// finding trackers. Each finding is located at the s3:// URI of its bucket
+// or prefix and carries a fingerprint stable across runs, so trackers
+// deduplicate it.
+func (w *Writer) WriteSARIF(bucketName string, findings []types.Finding) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "s3-profiler",
+			InformationURI: "https://github.com/yourusername/s3-profiler",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, f := range findings {
+		index, exists := ruleIndex[f.Rule.ID]
+		if !exists {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[f.Rule.ID] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:                   f.Rule.ID,
+				Name:                 f.Rule.Name,
+				ShortDescription:     sarifMessage{Text: f.Rule.Description},
+				Help:                 sarifHelp(f.Rule),
+				DefaultConfiguration: sarifLevel{Level: f.Rule.Level},
+				Properties: map[string]any{
+					"tags":              []string{"security", "s3"},
+					"security-severity": fmt.Sprintf("%.1f", f.Rule.SecuritySeverity),
+				},
+			})
+		}
+
+		uri := w.findingURI(f)
+		var location sarifLocation
+		location.PhysicalLocation.ArtifactLocation.URI = uri
+
+		sum := sha256.Sum256([]byte(f.Rule.ID + "\x00" + uri + "\x00" + f.Detail))
+		run.Results = append(run.Results, sarifResult{
+			RuleID:              f.Rule.ID,
+			RuleIndex:           index,
+			Level:               severityLevel(f),
+			Message:             sarifMessage{Text: f.Message},
+			Locations:           []sarifLocation{location},
+			PartialFingerprints: map[string]string{sarifFingerprint: hex.EncodeToString(sum[:])},
+		})
+	}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-findings.sarif"), string(data)+"\n")
+}
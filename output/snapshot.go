@@ -0,0 +1,38 @@
+package output
+
+import (
+	"encoding/json"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// WriteSnapshot writes the machine-readable run record used by the compare
+// and diff commands. Names are redacted like the reports, so snapshots taken with the
+// same salt remain comparable.
+func (w *Writer) WriteSnapshot(snapshot *types.Snapshot) error {
+	out := *snapshot
+	out.Bucket = w.bucket(snapshot.Bucket)
+	out.Prefixes = w.prefixStats(snapshot.Prefixes)
+	out.Partitions = w.prefixStats(snapshot.Partitions)
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return w.writeFile(w.ReportName(snapshot.Bucket, "-snapshot.json"), string(data)+"\n")
+}
+
+// prefixStats returns a copy of the stats with prefixes as they should
+// appear in reports
+func (w *Writer) prefixStats(stats []types.PrefixStats) []types.PrefixStats {
+	if stats == nil {
+		return nil
+	}
+	out := make([]types.PrefixStats, len(stats))
+	for i, s := range stats {
+		s.Prefix = w.key(s.Prefix)
+		out[i] = s
+	}
+	return out
+}
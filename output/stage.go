@@ -0,0 +1,122 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// stagingPrefix marks temporary staging directories inside the output directory
+const stagingPrefix = ".staging-"
+
+// replacedDir holds, inside a staging directory, the files of the output
+// directory a Commit replaces until every staged file is in place
+const replacedDir = ".replaced"
+
+// Stage is a Writer whose files are written to a private temporary directory
+// and only moved into the output directory once Commit is called. This keeps
+// failed or cancelled profiles from leaving truncated reports behind.
+type Stage struct {
+	*Writer
+	targetDir string
+	committed bool
+	// rename moves files; tests replace it to fail a move
+	rename func(oldpath, newpath string) error
+}
+
+// Stage creates a staging area for one bucket's output files. The staging
+// directory lives inside the output directory so that Commit can use atomic
+// renames on the same filesystem. Costs are written in the bucket's currency.
+func (w *Writer) Stage(bucketName string) (*Stage, error) {
+	dir, err := os.MkdirTemp(w.outputDir, stagingPrefix+w.bucket(bucketName)+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	writer := NewWriter(dir, w.opts)
+	if currency, ok := w.opts.BucketCurrencies[bucketName]; ok {
+		writer.currency = currency
+	}
+	return &Stage{
+		Writer:    writer,
+		targetDir: w.outputDir,
+		rename:    os.Rename,
+	}, nil
+}
+
+// stagedMove is a staged file Commit moved into the output directory
+type stagedMove struct {
+	name string
+	// replaced is set when the move set aside a file of the same name
+	replaced bool
+}
+
+// Commit moves all staged files into the output directory. The files they
+// replace are set aside first, so when a move fails the files already
+// moved are taken back and the output directory is left as it was.
+func (s *Stage) Commit() error {
+	entries, err := os.ReadDir(s.outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to read staging directory: %w", err)
+	}
+	backupDir := filepath.Join(s.outputDir, replacedDir)
+	if err := os.Mkdir(backupDir, 0755); err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	var done []stagedMove
+	for _, entry := range entries {
+		move := stagedMove{name: entry.Name()}
+		dst := filepath.Join(s.targetDir, move.name)
+		if _, err := os.Lstat(dst); err == nil {
+			if err := s.rename(dst, filepath.Join(backupDir, move.name)); err != nil {
+				return s.rollback(done, fmt.Errorf("failed to replace %s in output directory: %w", move.name, err))
+			}
+			move.replaced = true
+		}
+		if err := s.rename(filepath.Join(s.outputDir, move.name), dst); err != nil {
+			err = fmt.Errorf("failed to move %s into output directory: %w", move.name, err)
+			if move.replaced {
+				if restoreErr := s.rename(filepath.Join(backupDir, move.name), dst); restoreErr != nil {
+					err = errors.Join(err, fmt.Errorf("failed to restore %s: %w", move.name, restoreErr))
+				}
+			}
+			return s.rollback(done, err)
+		}
+		done = append(done, move)
+	}
+
+	s.committed = true
+	return os.RemoveAll(s.outputDir)
+}
+
+// rollback moves committed files back into the staging directory, most
+// recent first, and restores the files they replaced. The staging
+// directory is kept for Discard.
+func (s *Stage) rollback(done []stagedMove, err error) error {
+	backupDir := filepath.Join(s.outputDir, replacedDir)
+	for i := len(done) - 1; i >= 0; i-- {
+		move := done[i]
+		dst := filepath.Join(s.targetDir, move.name)
+		if moveErr := s.rename(dst, filepath.Join(s.outputDir, move.name)); moveErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to take back %s: %w", move.name, moveErr))
+			continue
+		}
+		if move.replaced {
+			if restoreErr := s.rename(filepath.Join(backupDir, move.name), dst); restoreErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to restore %s: %w", move.name, restoreErr))
+			}
+		}
+	}
+	return err
+}
+
+// Discard removes the staging directory and any files left in it. It is safe
+// to call after Commit, which makes it suitable for use with defer.
+func (s *Stage) Discard() error {
+	if s.committed {
+		return nil
+	}
+	return os.RemoveAll(s.outputDir)
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// stageFiles stages files with the given contents in a Writer over dir
+func stageFiles(t *testing.T, dir string, files map[string]string) *Stage {
+	t.Helper()
+	s, err := NewWriter(dir, Options{}).Stage("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(s.outputDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+// readFiles returns the contents of the regular files in dir
+func readFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files
+}
+
+func TestStageCommit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("old a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := stageFiles(t, dir, map[string]string{"a.txt": "new a", "b.txt": "new b"})
+	if err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Discard(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("output directory has %d entries, want a.txt and b.txt only", len(entries))
+	}
+	got := readFiles(t, dir)
+	if got["a.txt"] != "new a" || got["b.txt"] != "new b" {
+		t.Errorf("output files = %v, want the staged ones", got)
+	}
+}
+
+// TestStageCommitRollback fails the second staged file's move and checks
+// the output directory keeps the previous files
+func TestStageCommitRollback(t *testing.T) {
+	tests := []struct {
+		name string
+		// fail reports whether the rename from src to dst fails, given the
+		// staging and output directories
+		fail func(staging, out, src, dst string) bool
+	}{
+		{"move into output", func(staging, out, src, dst string) bool {
+			return src == filepath.Join(staging, "b.txt") && dst == filepath.Join(out, "b.txt")
+		}},
+		{"set aside replaced file", func(staging, out, src, dst string) bool {
+			return dst == filepath.Join(staging, replacedDir, "b.txt")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			previous := map[string]string{"a.txt": "old a", "b.txt": "old b"}
+			for name, content := range previous {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s := stageFiles(t, dir, map[string]string{"a.txt": "new a", "b.txt": "new b", "c.txt": "new c"})
+			errRename := errors.New("rename failed")
+			s.rename = func(src, dst string) error {
+				if tt.fail(s.outputDir, dir, src, dst) {
+					return errRename
+				}
+				return os.Rename(src, dst)
+			}
+
+			if err := s.Commit(); !errors.Is(err, errRename) {
+				t.Fatalf("Commit err = %v, want the rename error", err)
+			}
+			if got := readFiles(t, dir); len(got) != 2 || got["a.txt"] != "old a" || got["b.txt"] != "old b" {
+				t.Errorf("output files after a failed commit = %v, want %v", got, previous)
+			}
+			if got := readFiles(t, s.outputDir); got["a.txt"] != "new a" {
+				t.Errorf("staged a.txt was not taken back: %v", got)
+			}
+
+			if err := s.Discard(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(s.outputDir); !os.IsNotExist(err) {
+				t.Errorf("staging directory left after Discard: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// SortField selects the column report tables are sorted by
+type SortField string
+
+const (
+	SortDefault SortField = ""
+	SortSize    SortField = "size"
+	SortCount   SortField = "count"
+	SortName    SortField = "name"
+)
+
+// ParseSortField converts a flag value into a SortField
+func ParseSortField(value string) (SortField, error) {
+	switch SortField(strings.ToLower(strings.TrimSpace(value))) {
+	case SortDefault:
+		return SortDefault, nil
+	case SortSize:
+		return SortSize, nil
+	case SortCount:
+		return SortCount, nil
+	case SortName:
+		return SortName, nil
+	default:
+		return SortDefault, fmt.Errorf("unknown sort field %q (expected size, count or name)", value)
+	}
+}
+
+// TableOptions controls sorting and truncation of report tables
+type TableOptions struct {
+	SortBy  SortField
+	Desc    bool
+	MaxRows int
+}
+
+// tableRow exposes the sortable columns of a report table row
+type tableRow struct {
+	name  string
+	count int64
+	size  int64
+}
+
+// sortTable sorts rows in place according to the table options. Tables keep
+// their default order when no sort field is set.
+func sortTable[T any](rows []T, opts TableOptions, columns func(T) tableRow) {
+	if opts.SortBy == SortDefault {
+		return
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := columns(rows[i]), columns(rows[j])
+		var less, equal bool
+		switch opts.SortBy {
+		case SortSize:
+			less, equal = a.size < b.size, a.size == b.size
+		case SortCount:
+			less, equal = a.count < b.count, a.count == b.count
+		default:
+			less, equal = a.name < b.name, a.name == b.name
+		}
+		if equal {
+			return a.name < b.name
+		}
+		if opts.Desc {
+			return !less
+		}
+		return less
+	})
+}
+
+// sortedStorageClasses returns the storage classes largest first; classes
+// of equal size are ordered by name, so reports list them in the same
+// order on every run
+func sortedStorageClasses(classes map[string]types.StorageClassStats) []string {
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if classes[names[i]].Size != classes[names[j]].Size {
+			return classes[names[i]].Size > classes[names[j]].Size
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// sortedByCount returns the names of counts, most counted first and by
+// name among equal counts
+func sortedByCount(counts map[string]int64) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// visibleRows returns how many of total rows to print; defaultMax applies
+// when no --max-rows limit is configured (0 = unlimited)
+func (o TableOptions) visibleRows(total, defaultMax int) int {
+	max := o.MaxRows
+	if max <= 0 {
+		max = defaultMax
+	}
+	if max <= 0 || total <= max {
+		return total
+	}
+	return max
+}
+
+// writeMoreFooter writes the "N more not shown" line for truncated tables
+func writeMoreFooter(b *strings.Builder, shown, total int) {
+	if total > shown {
+		fmt.Fprintf(b, "... %d more not shown (use --max-rows to change)\n", total-shown)
+	}
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// maxListedTagValues is the default number of values listed per tag key
+const maxListedTagValues = 20
+
+// WriteTagReport writes the objects, size and monthly storage cost per tag
+// key and value, and where the untagged objects are
+func (w *Writer) WriteTagReport(bucketName string, tags *types.TagSummary) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.tf("Object Tags: %s", w.bucket(bucketName))))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	b.WriteString(w.tf("Read the tags of %s object(s) across %s prefix(es)", FormatNumber(tags.Sampled), FormatNumber(int64(tags.Strata))))
+	if tags.Failed > 0 {
+		b.WriteString(w.tf(", %s request(s) failed", FormatNumber(tags.Failed)))
+	}
+	b.WriteString("\n")
+	if !tags.Complete {
+		b.WriteString(w.t("Counts are estimated from per-prefix samples") + "\n")
+	}
+	b.WriteString("\n")
+
+	total := tags.Tagged.ObjectCount + tags.Untagged.ObjectCount
+	usageHeader := func(name string) {
+		fmt.Fprintf(&b, "%-40s %12s %14s %12s %8s\n", name, w.t("Objects"), w.t("Size"), w.t("$/month"), "%")
+	}
+	usageRow := func(name string, u types.TagUsage) {
+		fmt.Fprintf(&b, "%-40s %12s %14s %12s %8s\n",
+			name, FormatNumber(u.ObjectCount), FormatBytes(u.TotalSize), w.cost(u.MonthlyCost), FormatPercent(u.ObjectCount, total))
+	}
+
+	usageHeader("")
+	usageRow(w.t("Tagged"), tags.Tagged)
+	usageRow(w.t("Untagged"), tags.Untagged)
+	b.WriteString("\n")
+
+	if len(tags.Keys) > 0 {
+		b.WriteString(FormatSubHeader(w.t("Tag Keys")))
+		b.WriteString("\n")
+		usageHeader(w.t("Key"))
+		for _, key := range tags.Keys {
+			usageRow(w.tag(key.Name), key.TagUsage)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, key := range tags.Keys {
+		b.WriteString(FormatSubHeader(w.tf("Tag: %s", w.tag(key.Name))))
+		b.WriteString("\n")
+
+		values := append([]types.TagUsage(nil), key.Values...)
+		sortTable(values, w.opts.Table, func(u types.TagUsage) tableRow {
+			return tableRow{name: u.Name, count: u.ObjectCount, size: u.TotalSize}
+		})
+		shown := w.opts.Table.visibleRows(len(values), maxListedTagValues)
+
+		usageHeader(w.t("Value"))
+		for _, value := range values[:shown] {
+			usageRow(w.tag(value.Name), value)
+		}
+		writeMoreFooter(&b, shown, key.DistinctValues)
+
+		// Objects without the key cannot be allocated by it
+		missing := types.TagUsage{
+			ObjectCount: total - key.ObjectCount,
+			TotalSize:   tags.Tagged.TotalSize + tags.Untagged.TotalSize - key.TotalSize,
+			MonthlyCost: tags.Tagged.MonthlyCost + tags.Untagged.MonthlyCost - key.MonthlyCost,
+		}
+		if missing.ObjectCount > 0 {
+			usageRow(w.t("(not set)"), missing)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(tags.UntaggedPrefixes) > 0 {
+		b.WriteString(FormatSubHeader(w.t("Untagged Objects by Prefix")))
+		b.WriteString("\n")
+
+		prefixes := append([]types.TagUsage(nil), tags.UntaggedPrefixes...)
+		sortTable(prefixes, w.opts.Table, func(u types.TagUsage) tableRow {
+			return tableRow{name: u.Name, count: u.ObjectCount, size: u.TotalSize}
+		})
+		shown := w.opts.Table.visibleRows(len(prefixes), maxListedTagValues)
+
+		usageHeader(w.t("Prefix"))
+		for _, prefix := range prefixes[:shown] {
+			usageRow(w.key(prefix.Name), prefix)
+		}
+		writeMoreFooter(&b, shown, len(prefixes))
+		b.WriteString("\n")
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-tags.txt"), b.String())
+}
+
+// tag returns a tag key or value as printed in reports, redacted when
+// reports are
+func (w *Writer) tag(s string) string {
+	if w.redactor != nil {
+		s = w.redactor.Value(s)
+	}
+	return EscapeKey(s)
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// whatIfColumns are the short column names of the storage classes of the
+// what-if table
+var whatIfColumns = map[string]string{
+	"STANDARD":            "STANDARD",
+	"INTELLIGENT_TIERING": "INT_TIER",
+	"STANDARD_IA":         "STD_IA",
+	"ONEZONE_IA":          "ONEZONE",
+	"GLACIER_IR":          "GLAC_IR",
+	"GLACIER":             "GLACIER",
+	"DEEP_ARCHIVE":        "DEEP_ARCH",
+}
+
+// writeAccessCosts writes the monthly cost of each prefix under every
+// storage class at its assumed GET rate, with the cheapest class and the
+// rates over which it stays the cheapest
+func (w *Writer) writeAccessCosts(b *strings.Builder, simulation *types.AccessSimulation) {
+	b.WriteString(FormatSubHeader(w.t("Storage Class What-If")))
+	b.WriteString("\n")
+
+	prefixes := append([]types.PrefixAccessCost(nil), simulation.Prefixes...)
+	sortTable(prefixes, w.opts.Table, func(p types.PrefixAccessCost) tableRow {
+		return tableRow{name: p.Prefix, count: p.ObjectCount, size: p.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(prefixes), 0)
+
+	fmt.Fprintf(b, "%-30s %12s %12s %10s", w.t("Prefix"), w.t("Size"), w.t("GETs/month"), w.t("Current"))
+	if len(prefixes) > 0 {
+		for _, c := range prefixes[0].Costs {
+			fmt.Fprintf(b, " %10s", whatIfColumns[c.StorageClass])
+		}
+	}
+	b.WriteString("\n")
+	for _, p := range prefixes[:shown] {
+		gets := FormatNumber(int64(p.Gets + 0.5))
+		if p.Source == "logs" {
+			gets += "*"
+		}
+		fmt.Fprintf(b, "%-30s %12s %12s %10s", w.key(p.Prefix), FormatBytes(p.Size), gets, w.cost(p.CurrentCost))
+		for _, c := range p.Costs {
+			cost := w.cost(c.Total)
+			if c.StorageClass == p.Recommended {
+				cost = "<" + cost
+			}
+			fmt.Fprintf(b, " %10s", cost)
+		}
+		b.WriteString("\n")
+
+		savings := w.cost(p.Savings)
+		if p.Savings <= -0.005 {
+			savings = "-" + w.cost(-p.Savings)
+		} else if p.Savings < 0 {
+			savings = w.cost(0)
+		}
+		fmt.Fprintf(b, "  %s; %s\n", w.tf("cheapest: %s, saves %s/month", p.Recommended, savings), w.accessRange(p.MinGets, p.MaxGets))
+	}
+	writeMoreFooter(b, shown, len(prefixes))
+
+	if simulation.LogDays > 0 {
+		b.WriteString(w.tf("* GETs from %d day(s) of server access logs (%s requests), scaled to 30 days.", simulation.LogDays, FormatNumber(simulation.LogRequests)) + "\n")
+	}
+	b.WriteString(w.t("Monthly storage, GET request and retrieval costs with the whole prefix in each class; < marks the cheapest. GETs are assumed to read an average object unless measured from access logs.") + "\n")
+	b.WriteString(w.t("Intelligent-Tiering keeps objects read within 30 days frequent, assuming GETs hit objects at random; the IA classes bill objects under 128 KB as 128 KB. Transition fees and minimum storage durations are left out.") + "\n")
+	if simulation.Archive {
+		b.WriteString(w.t("GLACIER and DEEP_ARCHIVE are priced with Standard restores for every GET and must be restored before reads.") + "\n")
+	} else {
+		b.WriteString(w.t("GLACIER and DEEP_ARCHIVE need a restore before every read and are not recommended; pass --lifecycle-archive to consider them.") + "\n")
+	}
+}
+
+// accessRange describes the monthly GETs over which a recommendation
+// stays the cheapest
+func (w *Writer) accessRange(low, high float64) string {
+	from, to := FormatNumber(int64(low+0.5)), FormatNumber(int64(high+0.5))
+	switch {
+	case low == 0 && high < 0:
+		return w.t("stays cheapest at any GET rate")
+	case high < 0:
+		return w.tf("stays cheapest above %s GETs/month", from)
+	case low == 0:
+		return w.tf("stays cheapest below %s GETs/month", to)
+	default:
+		return w.tf("stays cheapest from %s to %s GETs/month", from, to)
+	}
+}
@@ -0,0 +1,162 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// workbookName is the file name of the run workbook
+const workbookName = "s3-profiler-report.xlsx"
+
+// xlsxMaxRows is the number of rows a worksheet holds
+const xlsxMaxRows = 1 << 20
+
+// WorkbookBucket is what the run workbook keeps of a profiled bucket
+type WorkbookBucket struct {
+	Summary       *types.BucketSummary
+	FileTypeStats map[string]int64
+	FileTypeSizes map[string]int64
+	Partitions    []types.Partition
+	// Newest is the LastModified of the newest object
+	Newest time.Time
+}
+
+// WorkbookName returns the file name of the run workbook
+func (w *Writer) WorkbookName() string {
+	return w.FileName(workbookName)
+}
+
+// WriteWorkbook writes the buckets of a run to one Excel workbook with
+// sheets for the summary, findings, storage classes, file types,
+// partitions and largest objects. Sizes are in bytes and GiB and costs in the report
+// currency, as numbers, so the sheets can be sorted and summed.
+func (w *Writer) WriteWorkbook(buckets []WorkbookBucket) error {
+	buckets = append([]WorkbookBucket(nil), buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Summary.Name < buckets[j].Summary.Name })
+
+	code := "USD"
+	if w.currency != nil {
+		code = w.currency.Code
+	}
+	costHeader := fmt.Sprintf("%s (%s)", w.t("Estimated Monthly Cost"), code)
+
+	summary := xlsxSheet{name: w.t("Summary"), rows: [][]any{{
+		w.t("Bucket"), w.t("Region"), w.t("Creation Date"), w.t("Objects"), w.t("Size (bytes)"), w.t("Size (GiB)"),
+		costHeader, w.t("Newest Object"), w.t("Partial Listing"),
+	}}}
+	findings := xlsxSheet{name: w.t("Findings"), rows: [][]any{{
+		w.t("Bucket"), w.t("Severity"), w.t("Score"), w.t("Rule"), w.t("Location"), w.t("Finding"),
+	}}}
+	classes := xlsxSheet{name: w.t("Storage Classes"), rows: [][]any{{
+		w.t("Bucket"), w.t("Storage Class"), w.t("Objects"), w.t("Size (bytes)"), w.t("Size (GiB)"), w.t("% Size"), costHeader,
+	}}}
+	fileTypes := xlsxSheet{name: w.t("File Types"), rows: [][]any{{
+		w.t("Bucket"), w.t("Extension"), w.t("Objects"), w.t("Size (bytes)"), w.t("Size (GiB)"),
+	}}}
+	partitions := xlsxSheet{name: w.t("Partitions"), rows: [][]any{{
+		w.t("Bucket"), w.t("Scope"), w.t("Partition"), w.t("Pattern"), w.t("Objects"), w.t("Size (bytes)"), w.t("Size (GiB)"),
+		w.t("Oldest"), w.t("Newest"),
+	}}}
+	objects := xlsxSheet{name: w.t("Largest Objects"), rows: [][]any{{
+		w.t("Bucket"), w.t("Key"), w.t("Size (bytes)"), w.t("Size (GiB)"), w.t("Last Modified"), w.t("Storage Class"),
+	}}}
+
+	for _, bucket := range buckets {
+		s := bucket.Summary
+		name := w.bucket(s.Name)
+		partial := ""
+		if s.Truncated {
+			partial = w.t("yes")
+		}
+		summary.rows = append(summary.rows, []any{
+			name, s.Region, s.CreationDate, s.TotalObjects, s.TotalSize, gib(s.TotalSize), w.costValue(s.EstimatedCost), bucket.Newest, partial,
+		})
+
+		for _, f := range s.Findings {
+			findings.rows = append(findings.rows, []any{
+				name, string(findingSeverity(f)), f.Rule.SecuritySeverity, f.Rule.ID, w.findingURI(f), f.Message,
+			})
+		}
+
+		names := make([]string, 0, len(s.StorageClasses))
+		for class := range s.StorageClasses {
+			names = append(names, class)
+		}
+		sort.Strings(names)
+		for _, class := range names {
+			stats := s.StorageClasses[class]
+			share := 0.0
+			if s.TotalSize > 0 {
+				share = math.Round(float64(stats.Size)/float64(s.TotalSize)*1000) / 10
+			}
+			classes.rows = append(classes.rows, []any{
+				name, class, stats.Count, stats.Size, gib(stats.Size), share, w.costValue(stats.Cost),
+			})
+		}
+
+		exts := make([]string, 0, len(bucket.FileTypeStats))
+		for ext := range bucket.FileTypeStats {
+			exts = append(exts, ext)
+		}
+		sort.Slice(exts, func(i, j int) bool {
+			if bucket.FileTypeSizes[exts[i]] != bucket.FileTypeSizes[exts[j]] {
+				return bucket.FileTypeSizes[exts[i]] > bucket.FileTypeSizes[exts[j]]
+			}
+			return exts[i] < exts[j]
+		})
+		for _, ext := range exts {
+			size := bucket.FileTypeSizes[ext]
+			fileTypes.rows = append(fileTypes.rows, []any{name, ext, bucket.FileTypeStats[ext], size, gib(size)})
+		}
+
+		for _, p := range bucket.Partitions {
+			partitions.rows = append(partitions.rows, []any{
+				name, w.key(p.Scope), w.key(p.Prefix), p.Pattern, p.ObjectCount, p.TotalSize, gib(p.TotalSize), p.Oldest, p.Newest,
+			})
+		}
+
+		if s.Top != nil {
+			for _, obj := range s.Top.Objects {
+				objects.rows = append(objects.rows, []any{
+					name, w.key(obj.Key), obj.Size, gib(obj.Size), obj.LastModified, obj.StorageClass,
+				})
+			}
+		}
+	}
+
+	// Most severe findings first across buckets, so the sheet triages top-down
+	rated := findings.rows[1:]
+	sort.SliceStable(rated, func(i, j int) bool {
+		return severityRank(types.Severity(rated[i][1].(string))) > severityRank(types.Severity(rated[j][1].(string)))
+	})
+
+	sheets := []xlsxSheet{summary, findings, classes, fileTypes, partitions, objects}
+	for i := range sheets {
+		// Rows past the sheet limit are dropped; the text reports have them
+		if len(sheets[i].rows) > xlsxMaxRows {
+			sheets[i].rows = sheets[i].rows[:xlsxMaxRows]
+		}
+	}
+	data, err := encodeXLSX(sheets, w.opts.Location)
+	if err != nil {
+		return fmt.Errorf("failed to encode workbook: %w", err)
+	}
+	return w.writeFile(workbookName, string(data))
+}
+
+// gib converts bytes to GiB rounded to three decimals
+func gib(size int64) float64 {
+	return math.Round(float64(size)/(1<<30)*1000) / 1000
+}
+
+// costValue converts a USD amount to the report currency, rounded to cents
+func (w *Writer) costValue(usd float64) float64 {
+	if w.currency != nil {
+		usd *= w.currency.Rate
+	}
+	return math.Round(usd*100) / 100
+}
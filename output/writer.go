@@ -0,0 +1,935 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// maxListedObjects caps the object listing in the metadata report
+const maxListedObjects = 100
+
+// maxListedFileTypes caps the file type table in the metadata report
+const maxListedFileTypes = 20
+
+// Options controls how reports and exports are written
+type Options struct {
+	Compression Compression
+
+	// Encryption encrypts every report and export (plain files when nil)
+	Encryption *Encryption
+
+	// Redact hashes bucket names and key contents in all reports
+	Redact     bool
+	RedactSalt string
+
+	// Location is the time zone timestamps are rendered in (UTC when nil)
+	Location *time.Location
+
+	// Table controls sorting and truncation of report tables
+	Table TableOptions
+
+	// Language selects the message catalog for report labels (English when empty)
+	Language Language
+
+	// Currency converts report costs (US dollars when nil); BucketCurrencies
+	// overrides it for the reports of a bucket
+	Currency         *Currency
+	BucketCurrencies map[string]*Currency
+}
+
+// Writer generates report files in the output directory
+type Writer struct {
+	outputDir string
+	opts      Options
+	redactor  *Redactor
+	estimate  *types.ListingEstimate
+	currency  *Currency
+}
+
+// NewWriter creates a new writer for the given output directory
+func NewWriter(outputDir string, opts Options) *Writer {
+	w := &Writer{
+		outputDir: outputDir,
+		opts:      opts,
+		currency:  opts.Currency,
+	}
+	if opts.Redact {
+		w.redactor = NewRedactor(opts.RedactSalt)
+	}
+	return w
+}
+
+// ReportName returns the file name for a bucket's report with the given
+// suffix, e.g. "-summary.txt"
+func (w *Writer) ReportName(bucketName, suffix string) string {
+	return w.bucket(bucketName) + suffix
+}
+
+// bucket returns the bucket name as it should appear in reports
+func (w *Writer) bucket(name string) string {
+	if w.redactor == nil {
+		return name
+	}
+	return w.redactor.Bucket(name)
+}
+
+// key returns an object key or prefix as it should appear in reports, with
+// control characters and invalid UTF-8 escaped so they cannot corrupt the
+// report layout
+func (w *Writer) key(key string) string {
+	return EscapeKey(w.rawKey(key))
+}
+
+// rawKey returns an object key with redaction applied but no escaping, for
+// outputs that must reproduce the exact key
+func (w *Writer) rawKey(key string) string {
+	if w.redactor == nil {
+		return key
+	}
+	return w.redactor.Key(key)
+}
+
+// keyScope describes the prefixes and glob patterns a listing covered
+func (w *Writer) keyScope(scope *types.KeyScope) string {
+	var parts []string
+	if len(scope.Prefixes) > 0 {
+		prefixes := make([]string, len(scope.Prefixes))
+		for i, prefix := range scope.Prefixes {
+			prefixes[i] = w.key(prefix)
+		}
+		parts = append(parts, w.tf("prefixes %s", strings.Join(prefixes, ", ")))
+	}
+	if len(scope.Include) > 0 {
+		parts = append(parts, w.tf("including %s", strings.Join(scope.Include, ", ")))
+	}
+	if len(scope.Exclude) > 0 {
+		parts = append(parts, w.tf("excluding %s", strings.Join(scope.Exclude, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// WriteBucketSummary writes the bucket summary report
+func (w *Writer) WriteBucketSummary(summary *types.BucketSummary) error {
+	var b strings.Builder
+
+	name := w.bucket(summary.Name)
+	b.WriteString(FormatHeader(w.tf("Bucket Summary: %s", name)))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	fmt.Fprintf(&b, "%s %s\n", w.label("Bucket Name:", 15), name)
+	fmt.Fprintf(&b, "%s %s\n", w.label("Region:", 15), summary.Region)
+	if d := summary.OpenData; d != nil {
+		fmt.Fprintf(&b, "%s %s\n", w.label("Open Data:", 15), fmt.Sprintf("%s (%s)", d.Name, d.Registry))
+	}
+	if o := summary.Ownership; o != nil {
+		fmt.Fprintf(&b, "%s %s\n", w.label("Owner:", 15), w.ownerNote(o))
+		if o.ObjectOwnership != "" {
+			fmt.Fprintf(&b, "%s %s\n", w.label("Object Owner:", 15), o.ObjectOwnership)
+		}
+	}
+	fmt.Fprintf(&b, "%s %s\n", w.label("Creation Date:", 15), FormatTime(summary.CreationDate, w.opts.Location))
+	if inv := summary.Inventory; inv != nil {
+		fmt.Fprintf(&b, "%s %s\n", w.label("Listed From:", 15),
+			w.tf("S3 Inventory %s, as of %s", inv.ConfigID, FormatTime(inv.Generated, w.opts.Location)))
+	}
+	if scope := summary.Scope; scope != nil {
+		fmt.Fprintf(&b, "%s %s\n", w.label("Key Scope:", 15), w.keyScope(scope))
+	}
+	if summary.NoContentAccess {
+		fmt.Fprintf(&b, "%s %s\n", w.label("Content Access:", 15), w.t("none; GetObject and HeadObject blocked (--no-content-access)"))
+	}
+	if e := summary.Estimate; e != nil {
+		fmt.Fprintf(&b, "%s %s", w.label("Total Objects:", 15), w.tf("%s listed", FormatNumber(summary.TotalObjects)))
+		if e.BucketObjects > 0 {
+			b.WriteString(w.tf(" of ~%s (est.)", FormatNumber(e.BucketObjects)))
+		}
+		fmt.Fprintf(&b, "\n%s %s", w.label("Total Size:", 15), w.tf("%s listed", FormatBytes(summary.TotalSize)))
+		if e.BucketObjects > 0 {
+			b.WriteString(w.tf(", ~%s extrapolated (est.)", FormatBytes(e.Size)))
+		}
+		b.WriteString("\n")
+	} else {
+		fmt.Fprintf(&b, "%s %s\n", w.label("Total Objects:", 15), FormatNumber(summary.TotalObjects))
+		fmt.Fprintf(&b, "%s %s\n", w.label("Total Size:", 15), FormatBytes(summary.TotalSize))
+	}
+	b.WriteString("\n")
+
+	if len(summary.Findings) > 0 {
+		w.writeFindings(&b, summary.Findings)
+	}
+
+	b.WriteString(FormatSubHeader(w.t("Storage Class Breakdown")))
+	b.WriteString("\n")
+	if len(summary.StorageClasses) == 0 {
+		b.WriteString(w.t("No objects found") + "\n")
+	} else {
+		classes := sortedStorageClasses(summary.StorageClasses)
+		sortTable(classes, w.opts.Table, func(class string) tableRow {
+			stats := summary.StorageClasses[class]
+			return tableRow{name: class, count: stats.Count, size: stats.Size}
+		})
+		shown := w.opts.Table.visibleRows(len(classes), 0)
+
+		fmt.Fprintf(&b, "%-22s %14s %14s %10s\n", w.t("Storage Class"), w.t("Objects"), w.t("Size"), w.t("% Size"))
+		for _, class := range classes[:shown] {
+			stats := summary.StorageClasses[class]
+			fmt.Fprintf(&b, "%-22s %14s %14s %10s\n",
+				class,
+				FormatNumber(stats.Count),
+				FormatBytes(stats.Size),
+				FormatPercent(stats.Size, summary.TotalSize))
+		}
+		writeMoreFooter(&b, shown, len(classes))
+	}
+	b.WriteString("\n")
+
+	if summary.Top != nil {
+		w.writeTopUsage(&b, summary.Top, summary.TotalSize)
+	}
+
+	if summary.Versioning != nil {
+		w.writeVersioning(&b, summary.Versioning)
+	}
+
+	if summary.Billable != nil {
+		w.writeBillable(&b, summary.Billable)
+	}
+
+	b.WriteString(FormatSubHeader(w.t("Estimated Monthly Storage Cost")))
+	b.WriteString("\n")
+	b.WriteString(w.tf("%s (approximate, US East pricing)", w.cost(summary.EstimatedCost)) + "\n")
+	if note := w.currencyNote(); note != "" {
+		b.WriteString(note + "\n")
+	}
+	if e := summary.Estimate; e != nil && e.BucketObjects > 0 {
+		b.WriteString(w.tf("~%s extrapolated to the whole bucket (est.)", w.cost(e.Cost)) + "\n")
+	} else if e != nil {
+		b.WriteString(w.t("Covers the listed objects only (lower bound)") + "\n")
+	}
+	if o := summary.Ownership; o != nil && o.Foreign {
+		b.WriteString(w.t("Billed to the bucket owner, not to the profiling account; exclude it from this account's cost totals") + "\n")
+	}
+	if d := summary.OpenData; d != nil {
+		b.WriteString(w.t("Storage is paid by the dataset sponsor through the AWS Open Data program") + "\n")
+		if d.RequesterPays {
+			b.WriteString(w.t("Requester pays: requests and data transfer are billed to the profiling account") + "\n")
+		}
+	}
+	if v := summary.Versioning; v != nil && v.NoncurrentCost > 0 {
+		b.WriteString(w.tf("Includes %s for non-current object versions", w.cost(v.NoncurrentCost)) + "\n")
+	}
+	if summary.ObjectFeeCost > 0 {
+		b.WriteString(w.tf("Plus %s in per-object fees (Intelligent-Tiering monitoring, archive overhead)", w.cost(summary.ObjectFeeCost)) + "\n")
+	}
+
+	if len(summary.Restores) > 0 {
+		b.WriteString("\n")
+		w.writeRestores(&b, summary.Restores)
+	}
+
+	if summary.Tiering != nil && len(summary.Tiering.Prefixes) > 0 {
+		b.WriteString("\n")
+		w.writeTiering(&b, summary.Tiering)
+	}
+
+	if summary.Lifecycle != nil && len(summary.Lifecycle.Ages) > 0 {
+		b.WriteString("\n")
+		w.writeLifecycle(&b, summary.Lifecycle)
+	}
+
+	if summary.AccessCosts != nil && len(summary.AccessCosts.Prefixes) > 0 {
+		b.WriteString("\n")
+		w.writeAccessCosts(&b, summary.AccessCosts)
+	}
+
+	if len(summary.ObjectFees) > 0 {
+		b.WriteString("\n")
+		w.writeObjectFees(&b, summary.ObjectFees)
+	}
+
+	if len(summary.Performance) > 0 {
+		b.WriteString("\n")
+		w.writePerformance(&b, summary.Performance)
+	}
+
+	return w.writeFile(w.ReportName(summary.Name, "-summary.txt"), b.String())
+}
+
+// writePerformance writes the request rates each stage achieved and the
+// concurrency it settled at
+func (w *Writer) writePerformance(b *strings.Builder, stats []types.OperationStats) {
+	b.WriteString(FormatSubHeader(w.t("Performance")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%-14s %10s %14s %12s %10s %10s %12s %10s %9s\n",
+		w.t("Operation"), w.t("Requests"), w.t("Throttled"), w.t("Objects"), w.t("Elapsed"),
+		w.t("Req/s"), w.t("Objects/s"), w.t("Latency"), w.t("Workers"))
+	for _, s := range stats {
+		elapsed := s.Elapsed.Seconds()
+		var requestRate, itemRate float64
+		if elapsed > 0 {
+			requestRate = float64(s.Requests) / elapsed
+			itemRate = float64(s.Items) / elapsed
+		}
+		fmt.Fprintf(b, "%-14s %10s %14s %12s %10s %10.1f %12.1f %10s %9s\n",
+			s.Operation,
+			FormatNumber(s.Requests),
+			fmt.Sprintf("%s (%s)", FormatNumber(s.Throttled), FormatPercent(s.Throttled, s.Requests)),
+			FormatNumber(s.Items),
+			s.Elapsed.Round(time.Second),
+			requestRate,
+			itemRate,
+			s.MeanLatency.Round(time.Millisecond),
+			fmt.Sprintf("%d/%d", s.EndWorkers, s.MaxWorkers))
+	}
+	for _, s := range stats {
+		if s.Adaptive && s.MaxWorkers > 1 {
+			b.WriteString(w.tf("%s concurrency adapted from %d, between %d and %d, ending at %d of up to %d",
+				s.Operation, s.InitWorkers, s.MinWorkers, s.PeakWorkers, s.EndWorkers, s.MaxWorkers) + "\n")
+		}
+	}
+	for _, s := range stats {
+		if s.Throttled > 0 {
+			b.WriteString(w.tf("S3 answered %s %s attempts with 503 Slow Down; the SDK retried them", FormatNumber(s.Throttled), s.Operation) + "\n")
+		}
+	}
+}
+
+// writeTopUsage writes the largest objects and heaviest prefixes sections
+// of the bucket summary
+func (w *Writer) writeTopUsage(b *strings.Builder, top *types.TopUsage, totalSize int64) {
+	if len(top.Objects) > 0 {
+		b.WriteString(FormatSubHeader(w.tf("Largest %d Objects", len(top.Objects))))
+		b.WriteString("\n")
+		fmt.Fprintf(b, "%-60s %14s %-20s %s\n", w.t("Key"), w.t("Size"), w.t("Storage Class"), w.t("Last Modified"))
+		for _, obj := range top.Objects {
+			fmt.Fprintf(b, "%-60s %14s %-20s %s\n",
+				w.key(obj.Key),
+				FormatBytes(obj.Size),
+				obj.StorageClass,
+				FormatTime(obj.LastModified, w.opts.Location))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(top.Prefixes) > 0 {
+		b.WriteString(FormatSubHeader(w.tf("Heaviest %d Prefixes", len(top.Prefixes))))
+		b.WriteString("\n")
+		fmt.Fprintf(b, "%-60s %14s %14s %10s\n", w.t("Prefix"), w.t("Objects"), w.t("Size"), w.t("% Size"))
+		for _, p := range top.Prefixes {
+			fmt.Fprintf(b, "%-60s %14s %14s %10s\n",
+				w.key(p.Prefix),
+				FormatNumber(p.ObjectCount),
+				FormatBytes(p.Size),
+				FormatPercent(p.Size, totalSize))
+		}
+		b.WriteString(w.t("Sizes count objects directly under each prefix, not in its subprefixes") + "\n\n")
+	}
+}
+
+// writeVersioning writes the versioned data sections of the bucket summary
+func (w *Writer) writeVersioning(b *strings.Builder, versions *types.VersionSummary) {
+	b.WriteString(FormatSubHeader(w.t("Object Versions")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%s %s\n", w.label("Versioning:", 22), versions.Status)
+	fmt.Fprintf(b, "%s %s\n", w.label("Non-current Versions:", 22), FormatNumber(versions.NoncurrentVersions))
+	fmt.Fprintf(b, "%s %s\n", w.label("Non-current Size:", 22), FormatBytes(versions.NoncurrentSize))
+	fmt.Fprintf(b, "%s %s\n", w.label("Non-current Cost:", 22), w.tf("%s/month", w.cost(versions.NoncurrentCost)))
+	fmt.Fprintf(b, "%s %s (%s)\n", w.label("Delete Markers:", 22), FormatNumber(versions.DeleteMarkers),
+		w.tf("%s current", FormatNumber(versions.CurrentDeleteMarkers)))
+	if versions.Truncated {
+		b.WriteString(w.t("Version listing stopped at --limit; counts are lower bounds") + "\n")
+	}
+
+	if len(versions.NoncurrentClasses) > 0 {
+		b.WriteString("\n")
+		classes := sortedStorageClasses(versions.NoncurrentClasses)
+		fmt.Fprintf(b, "%-22s %12s %14s %12s\n", w.t("Storage Class"), w.t("Versions"), w.t("Size"), w.t("Cost"))
+		for _, class := range classes {
+			stats := versions.NoncurrentClasses[class]
+			fmt.Fprintf(b, "%-22s %12s %14s %12s\n", class, FormatNumber(stats.Count), FormatBytes(stats.Size), w.cost(stats.Cost))
+		}
+	}
+
+	if len(versions.NoncurrentPrefixes) > 0 {
+		b.WriteString("\n")
+		w.writeVersionPrefixes(b, versions.NoncurrentPrefixes)
+	}
+	b.WriteString("\n")
+
+	b.WriteString(FormatSubHeader(w.t("Deleted Data Still Billed")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%s %s\n", w.label("Deleted Keys:", 18), FormatNumber(versions.DeletedKeys))
+	fmt.Fprintf(b, "%s %s\n", w.label("Hidden Versions:", 18), FormatNumber(versions.DeletedVersions))
+	fmt.Fprintf(b, "%s %s\n", w.label("Hidden Size:", 18), FormatBytes(versions.DeletedSize))
+
+	if len(versions.DeletedPrefixes) > 0 {
+		b.WriteString("\n")
+		w.writeVersionPrefixes(b, versions.DeletedPrefixes)
+	}
+	b.WriteString("\n")
+}
+
+// writeBillable writes the modeled billable bytes per storage class and
+// their difference from the CloudWatch storage metrics
+func (w *Writer) writeBillable(b *strings.Builder, billable *types.BillableSize) {
+	b.WriteString(FormatSubHeader(w.t("Billable Size")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%s %s\n", w.label("Current Objects:", 20), FormatBytes(billable.Naive))
+	fmt.Fprintf(b, "%s %s (%s)\n", w.label("Modeled Billable:", 20), FormatBytes(billable.Total),
+		formatSizeDelta(billable.Total-billable.Naive))
+	hasMetrics := !billable.MetricsDate.IsZero()
+	if hasMetrics {
+		fmt.Fprintf(b, "%s %s (%s)\n", w.label("CloudWatch:", 20), FormatBytes(billable.CloudWatchTotal),
+			w.tf("as of %s", billable.MetricsDate.Format("2006-01-02")))
+	}
+	fmt.Fprintf(b, "%s %s\n", w.label("Incomplete Uploads:", 20),
+		w.tf("%s uploads, %s parts", FormatNumber(billable.Uploads), FormatNumber(billable.Parts)))
+	if billable.PartsTruncated {
+		b.WriteString(w.t("Only the parts of the first 1,000 incomplete uploads were listed") + "\n")
+	}
+
+	if len(billable.Classes) > 0 {
+		b.WriteString("\n")
+		fmt.Fprintf(b, "%-22s %12s %12s %12s %14s %12s %12s",
+			w.t("Storage Class"), w.t("Current"), w.t("Overhead"), w.t("Non-current"), w.t("Delete Markers"), w.t("Multipart"), w.t("Modeled"))
+		if hasMetrics {
+			fmt.Fprintf(b, " %12s %12s", w.t("CloudWatch"), w.t("Difference"))
+		}
+		b.WriteString("\n")
+		for _, c := range billable.Classes {
+			fmt.Fprintf(b, "%-22s %12s %12s %12s %14s %12s %12s",
+				c.StorageClass,
+				FormatBytes(c.Current),
+				FormatBytes(c.Overhead),
+				FormatBytes(c.Noncurrent),
+				FormatBytes(c.DeleteMarkers),
+				FormatBytes(c.Multipart),
+				FormatBytes(c.Total))
+			if hasMetrics {
+				fmt.Fprintf(b, " %12s %12s", FormatBytes(c.CloudWatch), formatSizeDelta(c.CloudWatch-c.Total))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if !hasMetrics {
+		b.WriteString(w.t("CloudWatch storage metrics were not available to reconcile against (they need credentials on AWS and a complete listing)") + "\n")
+	}
+	b.WriteString("\n")
+}
+
+// writeVersionPrefixes writes a table of version statistics by prefix
+func (w *Writer) writeVersionPrefixes(b *strings.Builder, stats []types.PrefixVersionStats) {
+	prefixes := append([]types.PrefixVersionStats(nil), stats...)
+	sortTable(prefixes, w.opts.Table, func(p types.PrefixVersionStats) tableRow {
+		return tableRow{name: p.Prefix, count: p.Keys, size: p.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(prefixes), 0)
+
+	fmt.Fprintf(b, "%-40s %12s %12s %14s\n", w.t("Prefix"), w.t("Keys"), w.t("Versions"), w.t("Size"))
+	for _, p := range prefixes[:shown] {
+		fmt.Fprintf(b, "%-40s %12s %12s %14s\n",
+			w.key(p.Prefix),
+			FormatNumber(p.Keys),
+			FormatNumber(p.Versions),
+			FormatBytes(p.Size))
+	}
+	writeMoreFooter(b, shown, len(prefixes))
+}
+
+// writeRestores writes the restore cost estimates for archived prefixes
+func (w *Writer) writeRestores(b *strings.Builder, restores []types.RestoreEstimate) {
+	b.WriteString(FormatSubHeader(w.t("Restore Cost Estimates (archived prefixes)")))
+	b.WriteString("\n")
+	for _, r := range restores {
+		fmt.Fprintf(b, "%s [%s] - %s objects, %s\n",
+			w.key(r.Prefix), r.StorageClass, FormatNumber(r.ObjectCount), FormatBytes(r.Size))
+		for _, opt := range r.Options {
+			fmt.Fprintf(b, "  %-10s %12s  %s\n", opt.Tier, w.cost(opt.Cost), opt.Time)
+		}
+	}
+	b.WriteString(w.t("Retrieval fees only; restored copies are additionally billed at STANDARD rates while available.") + "\n")
+}
+
+// writeTiering writes the Intelligent-Tiering simulation per prefix
+func (w *Writer) writeTiering(b *strings.Builder, simulation *types.TieringSimulation) {
+	b.WriteString(FormatSubHeader(w.t("Intelligent-Tiering Simulation")))
+	b.WriteString("\n")
+
+	prefixes := append([]types.TieringEstimate(nil), simulation.Prefixes...)
+	sortTable(prefixes, w.opts.Table, func(e types.TieringEstimate) tableRow {
+		return tableRow{name: e.Prefix, count: e.ObjectCount, size: e.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(prefixes), 0)
+
+	fmt.Fprintf(b, "%-30s %12s %12s %10s %10s %10s %11s %10s %9s\n",
+		w.t("Prefix"), w.t("Monitored"), w.t("Size"), w.t("Current"), w.t("Tiered"), w.t("Monitoring"), w.t("Savings"), w.t("Break-even"), w.t("Payback"))
+	for _, e := range prefixes[:shown] {
+		breakEven := w.t("never")
+		switch {
+		case e.BreakEvenColdShare > 0 && e.BreakEvenColdShare < 0.01:
+			breakEven = "<1%"
+		case e.BreakEvenColdShare > 0 && e.BreakEvenColdShare <= 1:
+			breakEven = fmt.Sprintf("%.0f%%", e.BreakEvenColdShare*100)
+		}
+		payback := "-"
+		if e.PaybackMonths > 0 {
+			payback = w.tf("%.1f mo", max(e.PaybackMonths, 0.1))
+			if e.PaybackMonths < 0.1 {
+				payback = "<" + payback
+			}
+		}
+		savings := w.cost(e.Savings)
+		if e.Savings < 0 {
+			savings = "-" + w.cost(-e.Savings)
+		}
+		fmt.Fprintf(b, "%-30s %12s %12s %10s %10s %10s %11s %10s %9s\n",
+			w.key(e.Prefix),
+			FormatNumber(e.MonitoredObjects),
+			FormatBytes(e.MonitoredSize),
+			w.cost(e.CurrentCost),
+			w.cost(e.TieredCost),
+			w.cost(e.MonitoringFee),
+			savings,
+			breakEven,
+			payback)
+	}
+	writeMoreFooter(b, shown, len(prefixes))
+
+	b.WriteString(w.t("Monthly costs of the STANDARD and STANDARD_IA objects; objects under 128 KB are not moved or monitored.") + "\n")
+	b.WriteString(w.t("Tiers assume objects are read no more often than written: 30+ days since the last write is infrequent, 90+ days archive instant.") + "\n")
+	b.WriteString(w.t("Break-even: share of the monitored bytes that must turn cold to cover the monitoring fee. Payback: months of savings to cover the one-time transition fee.") + "\n")
+	b.WriteString(w.tf("Monitoring only pays off for objects larger than %s that stay cold.", FormatBytes(simulation.BreakEvenObjectSize)) + "\n")
+}
+
+// writeObjectFees writes the prefixes whose per-object fees outweigh
+// their storage cost
+func (w *Writer) writeObjectFees(b *strings.Builder, warnings []types.ObjectFeeWarning) {
+	b.WriteString(FormatSubHeader(w.t("Per-Object Fee Warnings")))
+	b.WriteString("\n")
+
+	warnings = append([]types.ObjectFeeWarning(nil), warnings...)
+	sortTable(warnings, w.opts.Table, func(f types.ObjectFeeWarning) tableRow {
+		return tableRow{name: f.Prefix, count: f.ObjectCount, size: f.Size}
+	})
+	shown := w.opts.Table.visibleRows(len(warnings), 0)
+
+	fmt.Fprintf(b, "%-30s %-20s %-9s %12s %10s %10s %10s %9s\n",
+		w.t("Prefix"), w.t("Storage Class"), w.t("Status"), w.t("Objects"), w.t("Avg Size"), w.t("Data"), w.t("Fees"), w.t("% Fees"))
+	for _, f := range warnings[:shown] {
+		status := w.t("if moved")
+		if f.Current {
+			status = w.t("current")
+		}
+		share := f.OverheadCost / (f.OverheadCost + f.DataCost) * 100
+		fmt.Fprintf(b, "%-30s %-20s %-9s %12s %10s %10s %10s %8.0f%%\n",
+			w.key(f.Prefix),
+			f.StorageClass,
+			status,
+			FormatNumber(f.ObjectCount),
+			FormatBytes(f.Size/f.ObjectCount),
+			w.cost(f.DataCost),
+			w.cost(f.OverheadCost),
+			share)
+	}
+	writeMoreFooter(b, shown, len(warnings))
+
+	b.WriteString(w.t("Monthly costs. Intelligent-Tiering monitors objects of 128 KB and larger at $0.0025 per 1,000; GLACIER and DEEP_ARCHIVE bill 32 KB at the archive rate and 8 KB at the STANDARD rate per object.") + "\n")
+	b.WriteString(w.t("Listed prefixes pay more in per-object fees than for their bytes; aggregate small objects or exclude them from lifecycle transitions.") + "\n")
+}
+
+// WriteMetadataSummary writes the metadata analysis report
+func (w *Writer) WriteMetadataSummary(bucketName string, summary *types.MetadataSummary) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.tf("Metadata Summary: %s", w.bucket(bucketName))))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	totalObjects := int64(len(summary.Objects))
+
+	// File type distribution
+	b.WriteString(FormatSubHeader(w.t("File Type Distribution")))
+	b.WriteString("\n")
+	if len(summary.FileTypeStats) == 0 {
+		b.WriteString(w.t("No objects found") + "\n")
+	} else {
+		fileTypes := sortedByCount(summary.FileTypeStats)
+		sortTable(fileTypes, w.opts.Table, func(ext string) tableRow {
+			return tableRow{name: ext, count: summary.FileTypeStats[ext], size: summary.FileTypeSizes[ext]}
+		})
+		shown := w.opts.Table.visibleRows(len(fileTypes), maxListedFileTypes)
+
+		fmt.Fprintf(&b, "%-20s %14s %10s %14s\n", w.t("Extension"), w.t("Objects"), w.t("%"), w.t("Size"))
+		for _, ext := range fileTypes[:shown] {
+			count := summary.FileTypeStats[ext]
+			fmt.Fprintf(&b, "%-20s %14s %10s %14s\n",
+				ext, FormatNumber(count), FormatPercent(count, totalObjects), FormatBytes(summary.FileTypeSizes[ext]))
+		}
+		writeMoreFooter(&b, shown, len(fileTypes))
+	}
+	b.WriteString("\n")
+
+	// Size distribution
+	b.WriteString(FormatSubHeader(w.t("Size Distribution")))
+	b.WriteString("\n")
+	for _, bucket := range summary.SizeDistribution {
+		fmt.Fprintf(&b, "%-12s %14s  %s\n",
+			bucket.Label,
+			FormatNumber(bucket.Count),
+			FormatBar(bucket.Count, totalObjects, 40))
+	}
+	b.WriteString("\n")
+
+	// Date range
+	b.WriteString(FormatSubHeader(w.t("Date Range")))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%s %s\n", w.label("Earliest Modified:", 18), FormatTime(summary.DateRange.Earliest, w.opts.Location))
+	fmt.Fprintf(&b, "%s %s\n", w.label("Latest Modified:", 18), FormatTime(summary.DateRange.Latest, w.opts.Location))
+	b.WriteString("\n")
+
+	// Unusual key encodings
+	b.WriteString(FormatSubHeader(w.t("Unusual Key Encodings")))
+	b.WriteString("\n")
+	if len(summary.KeyEncoding) == 0 {
+		b.WriteString(w.t("No keys with control characters, invalid UTF-8 or URL-encoded sequences") + "\n")
+	}
+	for _, issue := range summary.KeyEncoding {
+		fmt.Fprintf(&b, "%s: %s key(s)\n", issue.Issue, FormatNumber(issue.Count))
+		for _, example := range issue.Examples {
+			fmt.Fprintf(&b, "  e.g. %s\n", w.key(example))
+		}
+	}
+	b.WriteString("\n")
+
+	if summary.Enrichment != nil {
+		w.writeEnrichment(&b, summary.Enrichment, totalObjects)
+	}
+
+	if len(summary.EncryptedContent) > 0 {
+		w.writeEncryptedContent(&b, summary.EncryptedContent, summary.Enrichment != nil)
+	}
+
+	if len(summary.ContentSamples) > 0 {
+		w.writeContentSamples(&b, summary.ContentSamples)
+	}
+
+	if summary.Compressibility != nil {
+		w.writeCompressibility(&b, summary.Compressibility)
+	}
+
+	if summary.ParquetSchema != nil {
+		w.writeParquetSchema(&b, summary.ParquetSchema)
+	}
+
+	// Object listing
+	b.WriteString(FormatSubHeader(w.t("Object Listing")))
+	b.WriteString("\n")
+	if totalObjects > maxListedObjects {
+		fmt.Fprintf(&b, "Showing first %d of %s objects\n\n", maxListedObjects, FormatNumber(totalObjects))
+	}
+	for i, obj := range summary.Objects {
+		if i >= maxListedObjects {
+			break
+		}
+		fmt.Fprintf(&b, "%-12s %-30s %-14s %s\n",
+			FormatBytes(obj.Size),
+			FormatTime(obj.LastModified, w.opts.Location),
+			obj.StorageClass,
+			w.key(obj.Key))
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-metadata.txt"), b.String())
+}
+
+// writeEnrichment writes the HeadObject attribute distributions
+func (w *Writer) writeEnrichment(b *strings.Builder, e *types.EnrichmentSummary, totalObjects int64) {
+	b.WriteString(FormatSubHeader(w.t("Object Attributes (HeadObject sample)")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "Sampled %s object(s) across %s prefix(es)", FormatNumber(e.Sampled), FormatNumber(int64(e.Strata)))
+	if e.Failed > 0 {
+		fmt.Fprintf(b, ", %s request(s) failed", FormatNumber(e.Failed))
+	}
+	b.WriteString("\nCounts are estimated from per-prefix samples\n\n")
+
+	for _, attr := range []struct {
+		title  string
+		values map[string]float64
+	}{
+		{"Content-Type", e.ContentTypes},
+		{"Content-Encoding", e.ContentEncodings},
+		{"Server-Side Encryption", e.Encryption},
+		{"Replication Status", e.ReplicationStatus},
+		{"Object Lock Mode", e.ObjectLock},
+		{"User Metadata Keys", e.UserMetadataKeys},
+	} {
+		if len(attr.values) == 0 {
+			continue
+		}
+
+		values := make([]string, 0, len(attr.values))
+		for value := range attr.values {
+			values = append(values, value)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			ci, cj := attr.values[values[i]], attr.values[values[j]]
+			if ci != cj {
+				return ci > cj
+			}
+			return values[i] < values[j]
+		})
+
+		fmt.Fprintf(b, "%-40s %14s %10s\n", attr.title, "Est. Objects", "%")
+		for _, value := range values {
+			estimate := int64(attr.values[value] + 0.5)
+			fmt.Fprintf(b, "  %-38s %14s %10s\n", value, FormatNumber(estimate), FormatPercent(estimate, totalObjects))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// writeContentSamples writes what the content samplers found per object
+func (w *Writer) writeContentSamples(b *strings.Builder, samples []types.ContentSample) {
+	b.WriteString(FormatSubHeader(w.t("Content Samples")))
+	b.WriteString("\n")
+
+	for _, sample := range samples {
+		fmt.Fprintf(b, "%s [%s]\n", w.key(sample.Key), sample.Format)
+		if sample.Error != "" {
+			fmt.Fprintf(b, "  error: %s\n", sample.Error)
+			continue
+		}
+
+		names := make([]string, 0, len(sample.Attributes))
+		for name := range sample.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(b, "  %s: %s\n", name, sample.Attributes[name])
+		}
+	}
+	b.WriteString("\n")
+}
+
+// WritePartitions writes the partition detection report
+func (w *Writer) WritePartitions(bucketName string, analysis *types.PartitionAnalysis) error {
+	var b strings.Builder
+
+	b.WriteString(FormatHeader(w.tf("Partition Analysis: %s", w.bucket(bucketName))))
+	b.WriteString("\n\n")
+	w.writeEstimateBanner(&b)
+
+	if len(analysis.Issues) > 0 {
+		w.writePartitionIssues(&b, analysis.Issues)
+	}
+	if len(analysis.Rejected) > 0 {
+		w.writeRejectedPatterns(&b, analysis.Rejected)
+	}
+	if len(analysis.Backfills) > 0 {
+		w.writeBackfills(&b, analysis.Backfills)
+	}
+	if len(analysis.Suggestions) > 0 {
+		w.writeRepartitionSuggestions(&b, analysis.Suggestions)
+	}
+	if len(analysis.Pruning) > 0 {
+		w.writePruning(&b, analysis.Pruning)
+	}
+	if len(analysis.Hotspots) > 0 {
+		w.writeHotspots(&b, analysis.Hotspots)
+	}
+	if len(analysis.SmallFiles) > 0 {
+		w.writeSmallFiles(&b, analysis.SmallFiles)
+	}
+
+	partitions := analysis.Partitions
+	name := w.ReportName(bucketName, "-partitions.txt")
+	if len(partitions) == 0 {
+		b.WriteString(w.t("No partition patterns detected") + "\n")
+		return w.writeFile(name, b.String())
+	}
+
+	fmt.Fprintf(&b, "%s %d\n\n", w.label("Partition Count:", 18), len(partitions))
+	w.writePartitionPatterns(&b, analysis.Patterns)
+
+	// Thousands of daily partitions are summarized; the full list is a CSV
+	if NeedsPartitionRollup(partitions) {
+		w.writePartitionRollup(&b, bucketName, partitions)
+		return w.writeFile(name, b.String())
+	}
+
+	partitions = append([]types.Partition(nil), partitions...)
+	sortTable(partitions, w.opts.Table, func(p types.Partition) tableRow {
+		return tableRow{name: p.Scope + p.Prefix, count: p.ObjectCount, size: p.TotalSize}
+	})
+	shown := w.opts.Table.visibleRows(len(partitions), 0)
+
+	for _, p := range partitions[:shown] {
+		b.WriteString(FormatSubHeader(w.key(p.Scope + p.Prefix)))
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%s %s\n", w.label("Objects:", 9), FormatNumber(p.ObjectCount))
+		fmt.Fprintf(&b, "%s %s\n", w.label("Size:", 9), FormatBytes(p.TotalSize))
+		fmt.Fprintf(&b, "%s %s\n", w.label("Oldest:", 9), FormatTime(p.Oldest, w.opts.Location))
+		fmt.Fprintf(&b, "%s %s\n", w.label("Newest:", 9), FormatTime(p.Newest, w.opts.Location))
+		if days := lateDays(p); days > 0 {
+			b.WriteString(w.tf("Late data: newest object written %d day(s) after the partition period", days) + "\n")
+		}
+		b.WriteString(w.t("Examples:") + "\n")
+		for _, example := range p.Examples {
+			fmt.Fprintf(&b, "  - %s\n", w.key(example))
+		}
+		b.WriteString("\n")
+	}
+	writeMoreFooter(&b, shown, len(partitions))
+
+	return w.writeFile(name, b.String())
+}
+
+// writePartitionPatterns lists every detected pattern with its coverage
+func (w *Writer) writePartitionPatterns(b *strings.Builder, patterns []types.PatternCoverage) {
+	b.WriteString(FormatSubHeader(w.t("Detected Patterns")))
+	b.WriteString("\n")
+	fmt.Fprintf(b, "%-30s %-30s %12s %14s %9s\n", w.t("Scope"), w.t("Pattern"), w.t("Objects"), w.t("Size"), w.t("Coverage"))
+	for _, p := range patterns {
+		scope := w.key(p.Scope)
+		if p.Scope == "" {
+			scope = w.t("(bucket)")
+		}
+		fmt.Fprintf(b, "%-30s %-30s %12s %14s %8.1f%%\n", scope, p.Pattern,
+			FormatNumber(p.ObjectCount), FormatBytes(p.TotalSize), p.Coverage*100)
+	}
+	b.WriteString("\n")
+}
+
+// writePartitionIssues writes the partition naming consistency section
+func (w *Writer) writePartitionIssues(b *strings.Builder, issues []types.PartitionIssue) {
+	b.WriteString(FormatSubHeader(w.t("Naming Consistency")))
+	b.WriteString("\n")
+	for _, issue := range issues {
+		fmt.Fprintf(b, "[!] %s\n", issue.Issue)
+		for _, example := range issue.Examples {
+			fmt.Fprintf(b, "    e.g. %s\n", w.key(example))
+		}
+		fmt.Fprintf(b, "    Suggestion: %s\n", issue.Suggestion)
+	}
+	b.WriteString("\n")
+}
+
+// writeRejectedPatterns lists date patterns that matched most keys but
+// failed validation
+func (w *Writer) writeRejectedPatterns(b *strings.Builder, rejected []types.RejectedPattern) {
+	b.WriteString(FormatSubHeader(w.t("Rejected Date Patterns")))
+	b.WriteString("\n")
+	for _, r := range rejected {
+		matches := w.tf("%s matching key(s)", FormatNumber(r.Matches))
+		if r.Sampled {
+			matches = w.tf("%s matching sampled key(s)", FormatNumber(r.Matches))
+		}
+		fmt.Fprintf(b, "[x] %s%s (%s): %s\n", w.key(r.Scope), r.Pattern, matches, r.Reason)
+		for _, example := range r.Examples {
+			fmt.Fprintf(b, "    e.g. %s\n", w.key(example))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// WriteObjectInventory exports every listed object as CSV, compressed
+// according to the writer options. Keys are written unescaped, as the
+// query and explore commands read them back.
+func (w *Writer) WriteObjectInventory(bucketName string, objects []types.ObjectMetadata) (err error) {
+	f, err := w.createExport(w.ReportName(bucketName, "-objects.csv"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	cw := csv.NewWriter(f)
+	if err := cw.Write([]string{"key", "size", "last_modified", "storage_class", "etag"}); err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		record := []string{
+			w.rawKey(obj.Key),
+			strconv.FormatInt(obj.Size, 10),
+			obj.LastModified.In(w.location()).Format(time.RFC3339),
+			obj.StorageClass,
+			obj.ETag,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteRenameManifest writes suggested clean names for keys with unusual
+// encodings. Keys are URL-encoded like S3 Batch Operations manifests, so
+// the file is safe to process with standard CSV tools.
+func (w *Writer) WriteRenameManifest(bucketName string, renames []types.KeyRename) error {
+	var b strings.Builder
+	cw := csv.NewWriter(&b)
+	if err := cw.Write([]string{"bucket", "key", "suggested_key"}); err != nil {
+		return err
+	}
+	for _, rename := range renames {
+		record := []string{
+			w.bucket(bucketName),
+			url.QueryEscape(w.rawKey(rename.From)),
+			url.QueryEscape(w.rawKey(rename.To)),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	return w.writeFile(w.ReportName(bucketName, "-rename-manifest.csv"), b.String())
+}
+
+// location returns the configured report time zone
+func (w *Writer) location() *time.Location {
+	if w.opts.Location == nil {
+		return time.UTC
+	}
+	return w.opts.Location
+}
+
+// writeFile writes content to a file in the output directory, encrypted
+// when the writer options ask for it
+func (w *Writer) writeFile(name, content string) error {
+	path := filepath.Join(w.outputDir, w.FileName(name))
+	if w.opts.Encryption != nil {
+		return w.opts.Encryption.writeEncrypted(path, content)
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// ownerNote describes whose account a bucket belongs to
+func (w *Writer) ownerNote(o *types.BucketOwnership) string {
+	switch {
+	case o.Foreign:
+		return w.tf("another AWS account (not %s)", o.CallerAccount)
+	case o.Verified:
+		return w.tf("this account (%s)", o.CallerAccount)
+	}
+	return w.tf("unverified (credentials account %s)", o.CallerAccount)
+}
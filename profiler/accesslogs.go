@@ -0,0 +1,212 @@
+package profiler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Fields of a server access log record
+const (
+	accessLogBucket    = 1
+	accessLogOperation = 6
+	accessLogKey       = 7
+	accessLogStatus    = 9
+	accessLogBytesSent = 11
+)
+
+// accessLogCount is the GET requests and bytes sent for a prefix
+type accessLogCount struct {
+	gets  int64
+	bytes int64
+}
+
+// readAccessLogs counts the successful GET requests per top-level prefix
+// in the bucket's server access logs delivered in the logDays days before
+// today. Logs shared with other buckets are filtered by bucket name.
+func (as *AccessSimulator) readAccessLogs(ctx context.Context, bucketName, region string, now time.Time) (map[string]accessLogCount, int64, error) {
+	logging, err := as.s3Client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get bucket logging: %w", err)
+	}
+	if logging.LoggingEnabled == nil {
+		return nil, 0, fmt.Errorf("server access logging is not enabled")
+	}
+	target := aws.ToString(logging.LoggingEnabled.TargetBucket)
+	prefix := aws.ToString(logging.LoggingEnabled.TargetPrefix)
+
+	// Partitioned keys start with the source account, region and bucket
+	var roots []string
+	if f := logging.LoggingEnabled.TargetObjectKeyFormat; f != nil && f.PartitionedPrefix != nil {
+		accounts, err := as.listLogPrefixes(ctx, target, prefix)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, account := range accounts {
+			roots = append(roots, account+region+"/"+bucketName+"/")
+		}
+	}
+
+	var keys []string
+	for day := 1; day <= as.logDays; day++ {
+		date := now.AddDate(0, 0, -day).UTC()
+		if roots == nil {
+			dayKeys, err := as.listLogKeys(ctx, target, prefix+date.Format("2006-01-02"))
+			if err != nil {
+				return nil, 0, err
+			}
+			keys = append(keys, dayKeys...)
+			continue
+		}
+		for _, root := range roots {
+			dayKeys, err := as.listLogKeys(ctx, target, root+date.Format("2006/01/02/"))
+			if err != nil {
+				return nil, 0, err
+			}
+			keys = append(keys, dayKeys...)
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		requests int64
+	)
+	counts := make(map[string]accessLogCount)
+	err = runParallel(ctx, as.workers, len(keys), func(ctx context.Context, i int) error {
+		result, err := as.s3Client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(target),
+			Key:    aws.String(keys[i]),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read access log %s: %w", keys[i], err)
+		}
+		defer result.Body.Close()
+
+		local := make(map[string]accessLogCount)
+		var n int64
+		scanner := bufio.NewScanner(result.Body)
+		scanner.Buffer(make([]byte, 64<<10), 1<<20)
+		for scanner.Scan() {
+			fields := accessLogFields(scanner.Text())
+			if len(fields) <= accessLogBytesSent || fields[accessLogBucket] != bucketName {
+				continue
+			}
+			n++
+			if fields[accessLogOperation] != "REST.GET.OBJECT" {
+				continue
+			}
+			if status := fields[accessLogStatus]; status != "200" && status != "206" {
+				continue
+			}
+			key, err := url.PathUnescape(fields[accessLogKey])
+			if err != nil {
+				key = fields[accessLogKey]
+			}
+			sent, _ := strconv.ParseInt(fields[accessLogBytesSent], 10, 64)
+			count := local[topLevelPrefix(key)]
+			count.gets++
+			count.bytes += sent
+			local[topLevelPrefix(key)] = count
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read access log %s: %w", keys[i], err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests += n
+		for prefix, count := range local {
+			total := counts[prefix]
+			total.gets += count.gets
+			total.bytes += count.bytes
+			counts[prefix] = total
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return counts, requests, nil
+}
+
+// listLogKeys lists the log objects under a prefix
+func (as *AccessSimulator) listLogKeys(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(as.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access logs in s3://%s/%s: %w", bucket, prefix, err)
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+	return keys, nil
+}
+
+// listLogPrefixes lists the directories directly under a prefix
+func (as *AccessSimulator) listLogPrefixes(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var prefixes []string
+	paginator := s3.NewListObjectsV2Paginator(as.s3Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access logs in s3://%s/%s: %w", bucket, prefix, err)
+		}
+		for _, p := range page.CommonPrefixes {
+			prefixes = append(prefixes, aws.ToString(p.Prefix))
+		}
+	}
+	return prefixes, nil
+}
+
+// accessLogFields splits a server access log record into its fields. The
+// bracketed time and quoted fields keep their spaces; brackets and quotes
+// are removed.
+func accessLogFields(line string) []string {
+	var fields []string
+	for {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			return fields
+		}
+		var field string
+		switch line[0] {
+		case '[', '"':
+			closing := byte(']')
+			if line[0] == '"' {
+				closing = '"'
+			}
+			end := strings.IndexByte(line[1:], closing)
+			if end < 0 {
+				return append(fields, line[1:])
+			}
+			field, line = line[1:end+1], line[end+2:]
+		default:
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			field, line = line[:end], line[end:]
+		}
+		fields = append(fields, field)
+	}
+}
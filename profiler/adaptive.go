@@ -0,0 +1,244 @@
+package profiler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Adaptive concurrency tuning
+const (
+	// latencyTolerance is how far the smoothed latency may rise above the
+	// fastest seen before the limit stops growing and shrinks instead;
+	// minLatencyRise keeps jitter of fast requests from counting
+	latencyTolerance = 2.0
+	minLatencyRise   = 50 * time.Millisecond
+	// latencySmoothing weighs each attempt in the latency average
+	latencySmoothing = 0.2
+	// minLatencySamples is the number of attempts averaged before the
+	// latency baseline is trusted
+	minLatencySamples = 10
+)
+
+// concurrencyController bounds the requests in flight for one stage and,
+// when adaptive, tunes the bound by additive increase and multiplicative
+// decrease: the limit grows by one after a full window of successful
+// attempts and is halved when S3 throttles with 503 Slow Down. Workers
+// beyond the limit wait in acquire.
+type concurrencyController struct {
+	operation string
+	adaptive  bool
+	maxLimit  int
+	start     time.Time
+
+	mu sync.Mutex
+	// wake is closed and replaced whenever a slot may have freed up
+	wake     chan struct{}
+	limit    int
+	inFlight int
+	initial  int
+	lowest   int
+	peak     int
+
+	requests     int64
+	throttled    int64
+	items        int64
+	totalLatency time.Duration
+	// latency is the smoothed attempt latency in seconds; baseline the
+	// lowest it has been
+	latency      float64
+	baseline     float64
+	samples      int
+	successes    int
+	lastDecrease time.Time
+}
+
+// newConcurrencyController creates a controller allowing up to maxLimit
+// requests. An adaptive controller starts at a quarter of that and finds
+// the rate S3 sustains; a fixed one always allows maxLimit.
+func newConcurrencyController(operation string, maxLimit int, adaptive bool) *concurrencyController {
+	maxLimit = max(maxLimit, 1)
+	limit := maxLimit
+	if adaptive {
+		limit = max(maxLimit/4, 1)
+	}
+	return &concurrencyController{
+		operation: operation,
+		adaptive:  adaptive,
+		maxLimit:  maxLimit,
+		start:     time.Now(),
+		wake:      make(chan struct{}),
+		limit:     limit,
+		initial:   limit,
+		lowest:    limit,
+		peak:      limit,
+	}
+}
+
+// acquire waits for a free slot under the current limit
+func (c *concurrencyController) acquire(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		if c.inFlight < c.limit {
+			c.inFlight++
+			c.mu.Unlock()
+			return nil
+		}
+		wake := c.wake
+		c.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees the slot taken by acquire
+func (c *concurrencyController) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight--
+	c.signal()
+}
+
+// signal wakes the waiting workers; the caller holds mu
+func (c *concurrencyController) signal() {
+	close(c.wake)
+	c.wake = make(chan struct{})
+}
+
+// addItems counts the objects a request returned or covered
+func (c *concurrencyController) addItems(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items += int64(n)
+}
+
+// options observes every attempt of an SDK call, including the retries
+// the SDK makes on its own
+func (c *concurrencyController) options(o *s3.Options) {
+	o.APIOptions = append(o.APIOptions, c.addMiddleware)
+}
+
+func (c *concurrencyController) addMiddleware(stack *middleware.Stack) error {
+	return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("AdaptiveConcurrency",
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			start := time.Now()
+			out, metadata, err := next.HandleFinalize(ctx, in)
+
+			status := 0
+			if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok {
+				status = resp.StatusCode
+			}
+			c.observe(start, time.Since(start), isThrottle(status, err))
+
+			return out, metadata, err
+		}), middleware.After)
+}
+
+// isThrottle reports whether an attempt was throttled. HeadObject errors
+// carry no body and so no error code, leaving only the status.
+func isThrottle(status int, err error) bool {
+	if err == nil {
+		return false
+	}
+	return status == http.StatusServiceUnavailable ||
+		retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// observe records one attempt and adjusts the limit. Throttles of attempts
+// sent before the last decrease do not lower it again, so one burst of
+// 503s halves it only once.
+func (c *concurrencyController) observe(start time.Time, latency time.Duration, throttled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests++
+	c.totalLatency += latency
+	if throttled {
+		c.throttled++
+		c.successes = 0
+		if c.adaptive && start.After(c.lastDecrease) {
+			c.limit = max(c.limit/2, 1)
+			c.lowest = min(c.lowest, c.limit)
+			c.lastDecrease = time.Now()
+		}
+		return
+	}
+
+	seconds := latency.Seconds()
+	if c.samples == 0 {
+		c.latency = seconds
+	} else {
+		c.latency += latencySmoothing * (seconds - c.latency)
+	}
+	c.samples++
+	if c.samples >= minLatencySamples && (c.baseline == 0 || c.latency < c.baseline) {
+		c.baseline = c.latency
+	}
+
+	if !c.adaptive {
+		return
+	}
+	c.successes++
+	if c.successes < c.limit {
+		return
+	}
+	// A full window at this limit went through without throttling
+	c.successes = 0
+	if c.baseline > 0 && c.latency > latencyTolerance*c.baseline && c.latency-c.baseline > minLatencyRise.Seconds() {
+		// S3 is slowing down before it throttles
+		if c.limit > 1 {
+			c.limit--
+			c.lowest = min(c.lowest, c.limit)
+		}
+	} else if c.limit < c.maxLimit {
+		c.limit++
+		c.peak = max(c.peak, c.limit)
+		c.signal()
+	}
+}
+
+// stats returns the rates achieved so far
+func (c *concurrencyController) stats() types.OperationStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := types.OperationStats{
+		Operation:   c.operation,
+		Requests:    c.requests,
+		Throttled:   c.throttled,
+		Items:       c.items,
+		Elapsed:     time.Since(c.start),
+		Adaptive:    c.adaptive,
+		MaxWorkers:  c.maxLimit,
+		InitWorkers: c.initial,
+		MinWorkers:  c.lowest,
+		PeakWorkers: c.peak,
+		EndWorkers:  c.limit,
+	}
+	if c.requests > 0 {
+		stats.MeanLatency = c.totalLatency / time.Duration(c.requests)
+	}
+	return stats
+}
+
+// reportThrottling notes a stage that S3 throttled
+func (p *Profiler) reportThrottling(stats types.OperationStats) {
+	if stats.Throttled == 0 {
+		return
+	}
+	p.progress.Printf("S3 throttled %d of %d %s request(s); concurrency ended at %d of up to %d\n",
+		stats.Throttled, stats.Requests, stats.Operation, stats.EndWorkers, stats.MaxWorkers)
+}
@@ -0,0 +1,90 @@
+package profiler
+
+import (
+	"testing"
+	"time"
+)
+
+// attempts is a run of observed attempts: successes of one latency, or
+// throttles. Stale throttles were sent before the last decrease.
+type attempts struct {
+	n        int
+	latency  time.Duration
+	throttle bool
+	stale    bool
+}
+
+func successes(n int, latency time.Duration) attempts { return attempts{n: n, latency: latency} }
+
+func TestConcurrencyControllerObserve(t *testing.T) {
+	const fast = 10 * time.Millisecond
+	tests := []struct {
+		name      string
+		maxLimit  int
+		adaptive  bool
+		attempts  []attempts
+		wantLimit int
+		// wantLowest and wantPeak are the extremes the limit reached
+		wantLowest, wantPeak int
+	}{
+		{"starts at a quarter", 16, true, nil, 4, 4, 4},
+		{"partial window", 16, true, []attempts{successes(3, fast)}, 4, 4, 4},
+		{"grows after a full window", 16, true, []attempts{successes(4, fast)}, 5, 4, 5},
+		{"grows once per window", 16, true, []attempts{successes(4+5+6, fast)}, 7, 4, 7},
+		{"stops at the maximum", 4, true, []attempts{successes(50, fast)}, 4, 1, 4},
+		{"halves on throttle", 16, true, []attempts{{n: 1, throttle: true}}, 2, 2, 4},
+		{"one burst halves once", 16, true, []attempts{
+			{n: 1, throttle: true},
+			{n: 5, throttle: true, stale: true},
+		}, 2, 2, 4},
+		{"later bursts halve again", 16, true, []attempts{
+			{n: 1, throttle: true},
+			{n: 1, throttle: true},
+		}, 1, 1, 4},
+		{"never below one", 4, true, []attempts{{n: 3, throttle: true}}, 1, 1, 1},
+		{"throttle restarts the window", 16, true, []attempts{
+			successes(3, fast),
+			{n: 1, throttle: true},
+			successes(1, fast),
+		}, 2, 2, 4},
+		{"shrinks as latency rises", 8, true, []attempts{
+			// Ten fast attempts set the baseline on the way from 2 to 5
+			successes(10, fast),
+			successes(40, time.Second),
+		}, 1, 1, 5},
+		{"small latency rises are jitter", 8, true, []attempts{
+			successes(10, time.Millisecond),
+			successes(20, 30*time.Millisecond),
+		}, 8, 2, 8},
+		{"fixed limit", 8, false, []attempts{
+			{n: 3, throttle: true},
+			successes(20, fast),
+		}, 8, 8, 8},
+	}
+	for _, tt := range tests {
+		c := newConcurrencyController("ListObjectsV2", tt.maxLimit, tt.adaptive)
+		requests, throttled := 0, 0
+		for _, a := range tt.attempts {
+			for range a.n {
+				start := c.lastDecrease.Add(time.Second)
+				if a.stale {
+					start = c.lastDecrease.Add(-time.Second)
+				}
+				c.observe(start, a.latency, a.throttle)
+				requests++
+				if a.throttle {
+					throttled++
+				}
+			}
+		}
+
+		stats := c.stats()
+		if stats.EndWorkers != tt.wantLimit || stats.MinWorkers != tt.wantLowest || stats.PeakWorkers != tt.wantPeak {
+			t.Errorf("%s: limit %d (lowest %d, peak %d), want %d (lowest %d, peak %d)", tt.name,
+				stats.EndWorkers, stats.MinWorkers, stats.PeakWorkers, tt.wantLimit, tt.wantLowest, tt.wantPeak)
+		}
+		if stats.Requests != int64(requests) || stats.Throttled != int64(throttled) {
+			t.Errorf("%s: %d requests, %d throttled, want %d, %d", tt.name, stats.Requests, stats.Throttled, requests, throttled)
+		}
+	}
+}
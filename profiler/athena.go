@@ -0,0 +1,265 @@
+package profiler
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// athenaFormats are the file extensions Athena tables are generated for
+var athenaFormats = map[string]string{
+	"parquet": "parquet",
+	"csv":     "csv",
+	"json":    "json",
+	"jsonl":   "json",
+	"ndjson":  "json",
+}
+
+// athenaCompressions are the extensions of compressed text files Athena
+// decompresses by name
+var athenaCompressions = map[string]bool{
+	".gz":  true,
+	".bz2": true,
+	".zst": true,
+}
+
+// athenaParquetTypes maps Parquet logical and physical types to Athena
+// column types
+var athenaParquetTypes = map[string]string{
+	"STRING":    "string",
+	"ENUM":      "string",
+	"JSON":      "string",
+	"UUID":      "string",
+	"DATE":      "date",
+	"TIMESTAMP": "timestamp",
+	"INT_8":     "tinyint",
+	"INT_16":    "smallint",
+	"INT_32":    "int",
+	"INT_64":    "bigint",
+	"UINT_8":    "smallint",
+	"UINT_16":   "int",
+	"UINT_32":   "bigint",
+	"UINT_64":   "bigint",
+
+	"BOOLEAN":              "boolean",
+	"INT32":                "int",
+	"INT64":                "bigint",
+	"INT96":                "timestamp",
+	"FLOAT":                "float",
+	"DOUBLE":               "double",
+	"BYTE_ARRAY":           "string",
+	"FIXED_LEN_BYTE_ARRAY": "binary",
+}
+
+// athenaTable accumulates one table while its objects are counted
+type athenaTable struct {
+	table   types.AthenaTable
+	formats map[string]int64
+	// groups are the partitions' ParquetStats groups
+	groups map[string]bool
+}
+
+// buildAthenaTables describes an external table for every location holding
+// Hive-style date partitions (year=/month=/day= or dt=) of Parquet, CSV or
+// JSON files. Columns come from Parquet footer statistics or content
+// samples under the location; they are left empty when neither read any.
+func buildAthenaTables(objects []types.ObjectMetadata, analysis *types.PartitionAnalysis, metadata *types.MetadataSummary, parquetStats []types.ParquetStats) []types.AthenaTable {
+	tables := make(map[string]*athenaTable)
+	for _, partition := range analysis.Partitions {
+		keys := hivePartitionKeys(partition.Pattern)
+		if keys == nil || len(partition.Examples) == 0 {
+			continue
+		}
+		// The partition directories follow the table location
+		example := partition.Examples[0]
+		i := strings.Index(example, partition.Prefix)
+		if i < 0 || (i > 0 && example[i-1] != '/') {
+			continue
+		}
+		location := example[:i]
+
+		t, ok := tables[location]
+		if !ok {
+			t = &athenaTable{
+				table: types.AthenaTable{
+					Location:      location,
+					Pattern:       partition.Pattern,
+					PartitionKeys: keys,
+				},
+				formats: make(map[string]int64),
+				groups:  make(map[string]bool),
+			}
+			tables[location] = t
+		}
+		if t.table.Pattern == partition.Pattern {
+			t.table.Partitions++
+			t.groups[partition.Scope+partition.Prefix] = true
+		}
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+
+	for _, obj := range objects {
+		format := athenaFormat(obj.Key)
+		if format == "" {
+			continue
+		}
+		for _, t := range tables {
+			if inAthenaTable(obj.Key, &t.table) {
+				t.formats[format]++
+			}
+		}
+	}
+
+	var result []types.AthenaTable
+	for _, t := range tables {
+		table := t.table
+		for format, n := range t.formats {
+			if n > t.formats[table.Format] || (n == t.formats[table.Format] && format < table.Format) {
+				table.Format = format
+			}
+		}
+		if table.Format == "" {
+			continue
+		}
+		fillAthenaColumns(&table, t.groups, metadata.ContentSamples, parquetStats)
+		result = append(result, table)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Location < result[j].Location
+	})
+	return result
+}
+
+// inAthenaTable reports whether a key is in one of a table's partition
+// directories, which other files at the location are not
+func inAthenaTable(key string, table *types.AthenaTable) bool {
+	return strings.HasPrefix(key, table.Location+table.PartitionKeys[0]+"=")
+}
+
+// hivePartitionKeys returns the column names of a Hive-style pattern such
+// as year=YYYY/month=MM/day=DD, or nil when a level has no name
+func hivePartitionKeys(pattern string) []string {
+	var keys []string
+	for _, level := range strings.Split(pattern, "/") {
+		name, _, ok := strings.Cut(level, "=")
+		if !ok || name == "" {
+			return nil
+		}
+		keys = append(keys, strings.ToLower(name))
+	}
+	return keys
+}
+
+// athenaFormat returns the table format of a key from its extension,
+// looking through compression extensions, or "" for other files
+func athenaFormat(key string) string {
+	name := strings.ToLower(path.Base(key))
+	if ext := path.Ext(name); athenaCompressions[ext] {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return athenaFormats[strings.TrimPrefix(path.Ext(name), ".")]
+}
+
+// csvSeparators maps the delimiter names of CSV samples to characters
+var csvSeparators = map[string]string{
+	"comma":     ",",
+	"tab":       `\t`,
+	"semicolon": ";",
+	"pipe":      "|",
+}
+
+// fillAthenaColumns sets a table's columns with Athena types and where they
+// were read from. Parquet footer statistics carry logical types; content
+// samples only physical types, or names for CSV headers and JSON Lines
+// keys, which are typed as strings. CSV files without a header get
+// numbered columns.
+func fillAthenaColumns(table *types.AthenaTable, groups map[string]bool, samples []types.ContentSample, parquetStats []types.ParquetStats) {
+	if table.Format == "parquet" {
+		for _, stats := range parquetStats {
+			if !groups[stats.Partition] || len(stats.Columns) == 0 {
+				continue
+			}
+			for _, c := range stats.Columns {
+				table.Columns = append(table.Columns, types.SchemaColumn{Name: c.Name, Type: athenaParquetType(c.LogicalType)})
+			}
+			// Groups are named by scope and partition prefix
+			table.SchemaSource = table.Location + stats.Partition[len(subtreeScope(stats.Partition)):]
+			return
+		}
+	}
+
+	for _, sample := range samples {
+		if sample.Format != table.Format || sample.Error != "" || !inAthenaTable(sample.Key, table) {
+			continue
+		}
+		attributes := sample.Attributes
+		var fields []string
+		switch table.Format {
+		case "csv":
+			table.Delimiter = csvSeparators[attributes["delimiter"]]
+			if header := attributes["header"]; header != "" {
+				fields = strings.Split(header, ", ")
+				table.Header = true
+			} else if n, err := strconv.Atoi(attributes["columns"]); err == nil {
+				for i := 1; i <= n; i++ {
+					fields = append(fields, fmt.Sprintf("col%d", i))
+				}
+			}
+		case "json":
+			// The JSON SerDe reads one object per line
+			if attributes["layout"] == "lines" && attributes["keys"] != "" {
+				fields = strings.Split(attributes["keys"], ", ")
+			}
+		default:
+			if attributes["columns"] != "" {
+				fields = strings.Split(attributes["columns"], ", ")
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		for _, field := range fields {
+			// JSON key lists end in "..." when truncated
+			if field == "..." {
+				continue
+			}
+			column := types.SchemaColumn{Name: field, Type: "string"}
+			// Parquet samples list columns as "path TYPE"
+			if table.Format == "parquet" {
+				if i := strings.LastIndexByte(field, ' '); i > 0 {
+					column = types.SchemaColumn{Name: field[:i], Type: athenaParquetType(field[i+1:])}
+				}
+			}
+			table.Columns = append(table.Columns, column)
+		}
+		table.SchemaSource = sample.Key
+		return
+	}
+}
+
+// athenaParquetType maps a Parquet type, optionally followed by its
+// logical annotation (e.g. "INT64 TIMESTAMP(us)"), to an Athena type
+func athenaParquetType(parquetType string) string {
+	physical, annotation, _ := strings.Cut(parquetType, " ")
+	if strings.HasPrefix(annotation, "DECIMAL(") {
+		return strings.ToLower(annotation)
+	}
+	if i := strings.IndexByte(annotation, '('); i > 0 {
+		annotation = annotation[:i]
+	}
+	if t, ok := athenaParquetTypes[annotation]; ok {
+		return t
+	}
+	if t, ok := athenaParquetTypes[physical]; ok {
+		return t
+	}
+	return "string"
+}
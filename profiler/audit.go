@@ -0,0 +1,211 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Security posture checks run by SecurityAuditor, in report order
+const (
+	auditPublicAccessBlock = "Block Public Access"
+	auditBucketPolicy      = "Bucket Policy"
+	auditEncryption        = "Default Encryption"
+	auditVersioning        = "Versioning"
+	auditMFADelete         = "MFA Delete"
+	auditAccessLogging     = "Access Logging"
+	auditObjectOwnership   = "Object Ownership"
+)
+
+// SecurityAuditor checks the security posture of buckets. It only reads
+// bucket-level configuration, never objects.
+type SecurityAuditor struct {
+	s3Client *s3.Client
+}
+
+// NewSecurityAuditor creates a new security auditor
+func NewSecurityAuditor(s3Client *s3.Client) *SecurityAuditor {
+	return &SecurityAuditor{s3Client: s3Client}
+}
+
+// AuditBucket runs every check against a bucket. A check whose API call
+// fails (typically because of missing permissions) is reported with
+// AuditError instead of failing the audit. Requests are sent to region
+// when it is set, so buckets outside the client's region can be audited.
+func (sa *SecurityAuditor) AuditBucket(ctx context.Context, bucketName, region string) types.BucketAudit {
+	audit := types.BucketAudit{Bucket: bucketName, Region: region}
+	var optFns []func(*s3.Options)
+	if region != "" {
+		optFns = append(optFns, func(o *s3.Options) { o.Region = region })
+	}
+
+	record := func(name string, pass bool, detail string, err error) {
+		check := types.AuditCheck{Name: name, Status: types.AuditFail, Detail: detail}
+		switch {
+		case err != nil:
+			check.Status = types.AuditError
+			check.Detail = err.Error()
+		case pass:
+			check.Status = types.AuditPass
+		}
+		audit.Checks = append(audit.Checks, check)
+	}
+
+	pass, detail, err := sa.checkPublicAccessBlock(ctx, bucketName, optFns)
+	record(auditPublicAccessBlock, pass, detail, err)
+
+	pass, detail, err = sa.checkBucketPolicy(ctx, bucketName, optFns)
+	record(auditBucketPolicy, pass, detail, err)
+
+	pass, detail, err = sa.checkEncryption(ctx, bucketName, optFns)
+	record(auditEncryption, pass, detail, err)
+
+	versioning, err := sa.s3Client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucketName),
+	}, optFns...)
+	if err != nil {
+		record(auditVersioning, false, "", err)
+		record(auditMFADelete, false, "", err)
+	} else {
+		switch versioning.Status {
+		case s3types.BucketVersioningStatusEnabled:
+			record(auditVersioning, true, "enabled", nil)
+		case s3types.BucketVersioningStatusSuspended:
+			record(auditVersioning, false, "suspended", nil)
+		default:
+			record(auditVersioning, false, "never enabled", nil)
+		}
+		if versioning.MFADelete == s3types.MFADeleteStatusEnabled {
+			record(auditMFADelete, true, "enabled", nil)
+		} else {
+			record(auditMFADelete, false, "disabled", nil)
+		}
+	}
+
+	pass, detail, err = sa.checkAccessLogging(ctx, bucketName, optFns)
+	record(auditAccessLogging, pass, detail, err)
+
+	pass, detail, err = sa.checkObjectOwnership(ctx, bucketName, optFns)
+	record(auditObjectOwnership, pass, detail, err)
+
+	return audit
+}
+
+// checkPublicAccessBlock passes when all four bucket-level Block Public
+// Access settings are on. Account-level settings are not checked.
+func (sa *SecurityAuditor) checkPublicAccessBlock(ctx context.Context, bucketName string, optFns []func(*s3.Options)) (bool, string, error) {
+	result, err := sa.s3Client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	}, optFns...)
+	if err != nil {
+		if isErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+			return false, "not configured", nil
+		}
+		return false, "", err
+	}
+
+	block := result.PublicAccessBlockConfiguration
+	if block == nil {
+		return false, "not configured", nil
+	}
+	var off []string
+	for _, setting := range []struct {
+		name    string
+		enabled *bool
+	}{
+		{"BlockPublicAcls", block.BlockPublicAcls},
+		{"IgnorePublicAcls", block.IgnorePublicAcls},
+		{"BlockPublicPolicy", block.BlockPublicPolicy},
+		{"RestrictPublicBuckets", block.RestrictPublicBuckets},
+	} {
+		if !aws.ToBool(setting.enabled) {
+			off = append(off, setting.name)
+		}
+	}
+	if len(off) > 0 {
+		return false, "off: " + strings.Join(off, ", "), nil
+	}
+	return true, "all settings on", nil
+}
+
+// checkBucketPolicy passes when the bucket policy does not make the bucket
+// public, as evaluated by S3
+func (sa *SecurityAuditor) checkBucketPolicy(ctx context.Context, bucketName string, optFns []func(*s3.Options)) (bool, string, error) {
+	status, err := sa.s3Client.GetBucketPolicyStatus(ctx, &s3.GetBucketPolicyStatusInput{
+		Bucket: aws.String(bucketName),
+	}, optFns...)
+	if err != nil {
+		if isErrorCode(err, "NoSuchBucketPolicy") {
+			return true, "no bucket policy", nil
+		}
+		return false, "", err
+	}
+	if status.PolicyStatus != nil && aws.ToBool(status.PolicyStatus.IsPublic) {
+		return false, "policy grants public access", nil
+	}
+	return true, "not public", nil
+}
+
+// checkEncryption passes when default server-side encryption is configured
+func (sa *SecurityAuditor) checkEncryption(ctx context.Context, bucketName string, optFns []func(*s3.Options)) (bool, string, error) {
+	result, err := sa.s3Client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucketName),
+	}, optFns...)
+	if err != nil {
+		if isErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+			return false, "not configured", nil
+		}
+		return false, "", err
+	}
+	if result.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range result.ServerSideEncryptionConfiguration.Rules {
+			if d := rule.ApplyServerSideEncryptionByDefault; d != nil {
+				return true, string(d.SSEAlgorithm), nil
+			}
+		}
+	}
+	return false, "not configured", nil
+}
+
+// checkAccessLogging passes when server access logging is enabled
+func (sa *SecurityAuditor) checkAccessLogging(ctx context.Context, bucketName string, optFns []func(*s3.Options)) (bool, string, error) {
+	result, err := sa.s3Client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{
+		Bucket: aws.String(bucketName),
+	}, optFns...)
+	if err != nil {
+		return false, "", err
+	}
+	if result.LoggingEnabled == nil {
+		return false, "disabled", nil
+	}
+	return true, fmt.Sprintf("to s3://%s/%s", aws.ToString(result.LoggingEnabled.TargetBucket), aws.ToString(result.LoggingEnabled.TargetPrefix)), nil
+}
+
+// checkObjectOwnership passes when Object Ownership is BucketOwnerEnforced,
+// which disables ACLs so the bucket policy alone controls access
+func (sa *SecurityAuditor) checkObjectOwnership(ctx context.Context, bucketName string, optFns []func(*s3.Options)) (bool, string, error) {
+	result, err := sa.s3Client.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{
+		Bucket: aws.String(bucketName),
+	}, optFns...)
+	if err != nil {
+		// Buckets created before Object Ownership existed have no
+		// controls and keep the writer as object owner
+		if isErrorCode(err, "OwnershipControlsNotFoundError") {
+			return false, "ObjectWriter (ACLs enabled)", nil
+		}
+		return false, "", err
+	}
+	if result.OwnershipControls == nil || len(result.OwnershipControls.Rules) == 0 {
+		return false, "not configured", nil
+	}
+	ownership := result.OwnershipControls.Rules[0].ObjectOwnership
+	if ownership != s3types.ObjectOwnershipBucketOwnerEnforced {
+		return false, string(ownership) + " (ACLs enabled)", nil
+	}
+	return true, string(ownership) + " (ACLs disabled)", nil
+}
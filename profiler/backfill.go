@@ -0,0 +1,86 @@
+package profiler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Thresholds for flagging backfills into date partitions
+const (
+	// backfillGrace is how long after a period ends writes still count as
+	// on time, covering late batch jobs and time zone differences
+	backfillGrace = 24 * time.Hour
+	// heavyBackfillShare is the share of a partition's objects written
+	// outside its period that marks it as heavily backfilled
+	heavyBackfillShare = 0.1
+	// recentWriteWindow and closedPeriodAge flag partitions whose period
+	// closed long ago but which were written to recently
+	recentWriteWindow = 7 * 24 * time.Hour
+	closedPeriodAge   = 30 * 24 * time.Hour
+)
+
+// partitionPeriod returns the start and end of a date partition's period:
+// one day for daily patterns, one month for monthly patterns
+func partitionPeriod(p types.Partition) (time.Time, time.Time) {
+	if strings.Contains(p.Pattern, "DD") {
+		return p.Date, p.Date.AddDate(0, 0, 1)
+	}
+	return p.Date, p.Date.AddDate(0, 1, 0)
+}
+
+// writtenOutsidePeriod reports whether an object modified at t lies outside
+// the partition's period plus backfillGrace
+func writtenOutsidePeriod(p types.Partition, t time.Time) bool {
+	if p.Date.IsZero() || t.IsZero() {
+		return false
+	}
+	start, end := partitionPeriod(p)
+	return t.Before(start) || t.After(end.Add(backfillGrace))
+}
+
+// FlagBackfills returns the date partitions with heavy backfill or recent
+// rewrites as of now, largest backfilled size first
+func (pa *PartitionAnalyzer) FlagBackfills(partitions []types.Partition, now time.Time) []types.PartitionBackfill {
+	var flagged []types.PartitionBackfill
+	for _, p := range partitions {
+		if p.Date.IsZero() || p.OutsideObjects == 0 {
+			continue
+		}
+
+		_, end := partitionPeriod(p)
+		share := float64(p.OutsideObjects) / float64(p.ObjectCount)
+		var reasons []string
+		if share >= heavyBackfillShare {
+			reasons = append(reasons, fmt.Sprintf("%.0f%% of objects written outside the period", share*100))
+		}
+		if now.Sub(end) > closedPeriodAge && now.Sub(p.Newest) < recentWriteWindow {
+			reasons = append(reasons, fmt.Sprintf("rewritten %d day(s) ago, %d day(s) after the period closed",
+				int(now.Sub(p.Newest)/(24*time.Hour)), int(p.Newest.Sub(end)/(24*time.Hour))))
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		flagged = append(flagged, types.PartitionBackfill{
+			Scope:   p.Scope,
+			Prefix:  p.Prefix,
+			Objects: p.OutsideObjects,
+			Size:    p.OutsideSize,
+			Share:   share,
+			Newest:  p.Newest,
+			Reason:  strings.Join(reasons, "; "),
+		})
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		if flagged[i].Size != flagged[j].Size {
+			return flagged[i].Size > flagged[j].Size
+		}
+		return flagged[i].Scope+flagged[i].Prefix < flagged[j].Scope+flagged[j].Prefix
+	})
+	return flagged
+}
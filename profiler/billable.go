@@ -0,0 +1,202 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// minimumBillableSize is the size objects under it are billed at in the
+// classes of minimumSizeClasses
+const minimumBillableSize = 128 << 10
+
+// maxPartListings bounds the incomplete uploads whose parts are listed;
+// every upload needs its own ListParts calls
+const maxPartListings = 1000
+
+var minimumSizeClasses = map[string]bool{
+	"STANDARD_IA": true,
+	"ONEZONE_IA":  true,
+	"GLACIER_IR":  true,
+}
+
+// cloudWatchStorageClasses maps the StorageType dimensions of the
+// BucketSizeBytes metric to the storage class they bill
+var cloudWatchStorageClasses = map[string]string{
+	"StandardStorage":                "STANDARD",
+	"IntelligentTieringFAStorage":    "INTELLIGENT_TIERING",
+	"IntelligentTieringIAStorage":    "INTELLIGENT_TIERING",
+	"IntelligentTieringAIAStorage":   "INTELLIGENT_TIERING",
+	"IntelligentTieringAAStorage":    "INTELLIGENT_TIERING",
+	"IntelligentTieringDAAStorage":   "INTELLIGENT_TIERING",
+	"StandardIAStorage":              "STANDARD_IA",
+	"StandardIASizeOverhead":         "STANDARD_IA",
+	"OneZoneIAStorage":               "ONEZONE_IA",
+	"OneZoneIASizeOverhead":          "ONEZONE_IA",
+	"ReducedRedundancyStorage":       "REDUCED_REDUNDANCY",
+	"GlacierInstantRetrievalStorage": "GLACIER_IR",
+	"GlacierIRSizeOverhead":          "GLACIER_IR",
+	"GlacierStorage":                 "GLACIER",
+	"GlacierStagingStorage":          "GLACIER",
+	"GlacierObjectOverhead":          "GLACIER",
+	"GlacierS3ObjectOverhead":        "GLACIER",
+	"DeepArchiveStorage":             "DEEP_ARCHIVE",
+	"DeepArchiveObjectOverhead":      "DEEP_ARCHIVE",
+	"DeepArchiveS3ObjectOverhead":    "DEEP_ARCHIVE",
+	"DeepArchiveStagingStorage":      "DEEP_ARCHIVE",
+}
+
+// BucketSizeFunc returns a bucket's BucketSizeBytes CloudWatch storage
+// metric per storage type and the day it was recorded
+type BucketSizeFunc func(ctx context.Context, bucketName, region string) (map[string]int64, time.Time, error)
+
+// billableBytes returns the bytes S3 bills for storing an object of a
+// class: small objects in the IA and Glacier Instant Retrieval classes are
+// billed at 128 KB, and GLACIER and DEEP_ARCHIVE objects carry 40 KB of
+// index and metadata
+func billableBytes(class string, size int64) int64 {
+	if minimumSizeClasses[class] && size < minimumBillableSize {
+		size = minimumBillableSize
+	}
+	if slices.Contains(archiveClasses, class) {
+		size += archiveIndexOverhead + archiveMetadataOverhead
+	}
+	return size
+}
+
+// multipartUsage is the storage held by incomplete multipart uploads
+type multipartUsage struct {
+	uploads   int64
+	parts     int64
+	truncated bool
+	// bytes by storage class
+	bytes map[string]int64
+}
+
+// listIncompleteUploads sums the parts uploaded by multipart uploads that
+// were neither completed nor aborted; S3 bills them until they are
+func (ba *BucketAnalyzer) listIncompleteUploads(ctx context.Context, bucketName string) (*multipartUsage, error) {
+	usage := &multipartUsage{bytes: make(map[string]int64)}
+
+	paginator := s3.NewListMultipartUploadsPaginator(ba.s3Client, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucketName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list multipart uploads: %w", err)
+		}
+
+		for _, upload := range page.Uploads {
+			usage.uploads++
+			if usage.uploads > maxPartListings {
+				usage.truncated = true
+				continue
+			}
+
+			class := string(upload.StorageClass)
+			if class == "" {
+				class = "STANDARD"
+			}
+			parts := s3.NewListPartsPaginator(ba.s3Client, &s3.ListPartsInput{
+				Bucket:   aws.String(bucketName),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+			for parts.HasMorePages() {
+				partPage, err := parts.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list parts of %s: %w", aws.ToString(upload.Key), err)
+				}
+				for _, part := range partPage.Parts {
+					usage.parts++
+					usage.bytes[class] += aws.ToInt64(part.Size)
+				}
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// modelBillableSize adds up the billable bytes of current versions,
+// non-current versions, delete markers and incomplete multipart uploads
+// per storage class, and reconciles them with CloudWatch BucketSizeBytes
+func (p *Profiler) modelBillableSize(ctx context.Context, summary *types.BucketSummary) *types.BillableSize {
+	billable := &types.BillableSize{Naive: summary.TotalSize}
+	classes := make(map[string]*types.BillableClass)
+	class := func(name string) *types.BillableClass {
+		c, exists := classes[name]
+		if !exists {
+			c = &types.BillableClass{StorageClass: name}
+			classes[name] = c
+		}
+		return c
+	}
+
+	for name, stats := range summary.StorageClasses {
+		c := class(name)
+		c.Current += stats.Size
+		c.Overhead += stats.Billable - stats.Size
+	}
+	if v := summary.Versioning; v != nil {
+		for name, stats := range v.NoncurrentClasses {
+			class(name).Noncurrent += stats.Billable
+		}
+		if v.DeleteMarkerBytes > 0 {
+			class("STANDARD").DeleteMarkers += v.DeleteMarkerBytes
+		}
+	}
+
+	uploads, err := p.bucketAnalyzer.listIncompleteUploads(ctx, summary.Name)
+	if err != nil {
+		p.progress.Warnf("skipping incomplete multipart uploads: %v", err)
+	} else {
+		billable.Uploads = uploads.uploads
+		billable.Parts = uploads.parts
+		billable.PartsTruncated = uploads.truncated
+		for name, size := range uploads.bytes {
+			class(name).Multipart += size
+		}
+	}
+
+	// CloudWatch covers the whole bucket; comparing it with a truncated
+	// listing would only show the part that was not listed
+	if p.bucketSizes != nil && !summary.Truncated {
+		sizes, date, err := p.bucketSizes(ctx, summary.Name, summary.Region)
+		if err != nil {
+			p.progress.Warnf("could not get bucket size from CloudWatch: %v", err)
+		} else if len(sizes) > 0 {
+			billable.MetricsDate = date
+			for storageType, size := range sizes {
+				name, ok := cloudWatchStorageClasses[storageType]
+				if !ok {
+					name = storageType
+				}
+				class(name).CloudWatch += size
+				billable.CloudWatchTotal += size
+			}
+		}
+	}
+
+	for _, c := range classes {
+		c.Total = c.Current + c.Overhead + c.Noncurrent + c.DeleteMarkers + c.Multipart
+		billable.Total += c.Total
+		billable.Classes = append(billable.Classes, *c)
+	}
+	sort.Slice(billable.Classes, func(i, j int) bool {
+		a, b := billable.Classes[i], billable.Classes[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.StorageClass < b.StorageClass
+	})
+
+	return billable
+}
@@ -2,11 +2,16 @@ package profiler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/yourusername/s3-profiler/output"
 	"github.com/yourusername/s3-profiler/types"
 )
 
@@ -14,6 +19,33 @@ import (
 type BucketAnalyzer struct {
 	s3Client *s3.Client
 	limit    int64
+	// stream receives each listed page when --stream-objects is set
+	stream *ObjectStreamer
+	// useInventory reads objects from S3 Inventory reports when available
+	useInventory bool
+	// accountID verifies bucket ownership when set
+	accountID AccountIDFunc
+	// anonymous is set with --no-sign-request, which cannot list buckets
+	anonymous bool
+	// openData annotates buckets of known open datasets
+	openData bool
+	// checkpoints saves listing progress for --resume
+	checkpoints *Checkpointer
+	// listWorkers lists prefix shards concurrently when above 1
+	listWorkers int
+	// adaptive tunes the listing concurrency up to listWorkers
+	adaptive bool
+	// lifecycleArchive lets lifecycle recommendations use archive classes
+	lifecycleArchive bool
+	// scope limits the listing to prefixes and skips excluded keys
+	scope keyScope
+	// objectLines writes listed objects as NDJSON when --export-ndjson is set
+	objectLines *output.Writer
+	// interim writes interim summaries of long listings
+	interim *InterimSummaries
+	// prices are the storage prices costs are estimated with
+	prices   storagePrices
+	progress ProgressReporter
 }
 
 // NewBucketAnalyzer creates a new bucket analyzer
@@ -21,6 +53,7 @@ func NewBucketAnalyzer(s3Client *s3.Client, limit int64) *BucketAnalyzer {
 	return &BucketAnalyzer{
 		s3Client: s3Client,
 		limit:    limit,
+		progress: defaultProgress(),
 	}
 }
 
@@ -32,12 +65,28 @@ func (ba *BucketAnalyzer) AnalyzeBucket(ctx context.Context, bucketName, region
 		StorageClasses: make(map[string]types.StorageClassStats),
 	}
 
-	// Get bucket creation date
-	creationDate, err := ba.getBucketCreationDate(ctx, bucketName)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get bucket creation date: %w", err)
+	if ba.openData {
+		summary.OpenData = LookupOpenDataset(bucketName)
+		if summary.OpenData == nil {
+			ba.progress.Printf("%s is not in the curated open data list; using generic layouts\n", bucketName)
+		} else {
+			ba.progress.Printf("Open dataset: %s (%s)\n", summary.OpenData.Name, summary.OpenData.Registry)
+		}
+	}
+
+	summary.Ownership = ba.checkOwnership(ctx, bucketName)
+	summary.Scope = ba.scope.summary()
+
+	// Get bucket creation date; ListBuckets only returns the account's own
+	// buckets, so it is unknown for foreign ones, open datasets and
+	// anonymous requests
+	if !ba.anonymous && summary.OpenData == nil && (summary.Ownership == nil || !summary.Ownership.Foreign) {
+		creationDate, err := ba.getBucketCreationDate(ctx, bucketName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get bucket creation date: %w", err)
+		}
+		summary.CreationDate = creationDate
 	}
-	summary.CreationDate = creationDate
 
 	// List and analyze objects
 	objects, err := ba.listObjects(ctx, bucketName, summary)
@@ -48,6 +97,18 @@ func (ba *BucketAnalyzer) AnalyzeBucket(ctx context.Context, bucketName, region
 	// Calculate estimated cost
 	summary.EstimatedCost = ba.calculateCost(summary.StorageClasses)
 
+	// Estimate restore costs for archived prefixes
+	summary.Restores = ba.EstimateRestoreCosts(objects)
+
+	// Model a switch to Intelligent-Tiering
+	summary.Tiering = ba.SimulateIntelligentTiering(objects)
+
+	// Recommend lifecycle transitions from the age of the data
+	summary.Lifecycle = ba.RecommendLifecycle(objects, ba.lifecycleArchive)
+
+	// Flag prefixes dominated by per-object fees
+	summary.ObjectFees, summary.ObjectFeeCost = ba.CheckObjectFees(objects)
+
 	return summary, objects, nil
 }
 
@@ -71,106 +132,382 @@ func (ba *BucketAnalyzer) getBucketCreationDate(ctx context.Context, bucketName
 // listObjects lists all objects in the bucket and collects statistics
 func (ba *BucketAnalyzer) listObjects(ctx context.Context, bucketName string, summary *types.BucketSummary) ([]types.ObjectMetadata, error) {
 	var objects []types.ObjectMetadata
-	var continuationToken *string
-	processedCount := int64(0)
 
-	for {
-		// Check if we've reached the limit
-		if ba.limit > 0 && processedCount >= ba.limit {
-			fmt.Printf("Reached limit of %d objects\n", ba.limit)
-			break
+	if ba.listWorkers > 1 && ba.limit > 0 {
+		ba.progress.Printf("Listing sequentially: --limit takes the first objects in key order\n")
+	}
+	if ba.sharded() && ba.checkpoints.Enabled() && ba.checkpoints.resume {
+		ba.progress.Warnf("sharded listings are not checkpointed; listing %s from the start", bucketName)
+	}
+	multiPrefix := len(ba.scope.prefixes) > 1
+	if multiPrefix && ba.checkpoints.Enabled() && ba.checkpoints.resume {
+		ba.progress.Warnf("listings of several prefixes are not checkpointed; listing %s from the start", bucketName)
+	}
+
+	// The NDJSON export grows page by page; it is left partial when the
+	// listing fails
+	var lines *output.ObjectLines
+	if ba.objectLines != nil {
+		var err error
+		if lines, err = ba.objectLines.CreateObjectLines(bucketName); err != nil {
+			return nil, fmt.Errorf("failed to create NDJSON export: %w", err)
+		}
+		defer func() {
+			if lines != nil {
+				lines.Close()
+			}
+		}()
+	}
+	finishLines := func() error {
+		if lines == nil {
+			return nil
 		}
+		name, count := lines.Name(), lines.Count()
+		err := lines.Close()
+		lines = nil
+		if err != nil {
+			return fmt.Errorf("failed to write NDJSON export: %w", err)
+		}
+		ba.progress.Printf("Wrote %d objects to %s\n", count, name)
+		return nil
+	}
 
-		input := &s3.ListObjectsV2Input{
-			Bucket:            aws.String(bucketName),
-			ContinuationToken: continuationToken,
+	// A failed interim summary is reported once and not retried
+	listing := ba.interim.start(bucketName, summary.Region)
+	addInterim := func(page []types.ObjectMetadata) {
+		if err := listing.add(page); err != nil {
+			ba.progress.Warnf("%v; continuing without interim summaries", err)
+			listing = nil
 		}
+	}
 
-		// Set max keys if limit is specified
-		if ba.limit > 0 {
-			remaining := ba.limit - processedCount
-			if remaining < 1000 {
-				input.MaxKeys = aws.Int32(int32(remaining))
+	// Checkpoints follow ListObjectsV2 continuation tokens, which S3
+	// Inventory reads do not have, of a single listing
+	var cursor *listCursor
+	var checkpoint *listingCheckpoint
+	if ba.checkpoints.Enabled() && !ba.useInventory && !ba.sharded() && !multiPrefix {
+		restored, state := ba.restoreListing(bucketName)
+		for _, obj := range restored {
+			countObject(summary, obj)
+		}
+		objects = restored
+		addInterim(restored)
+		// Restored objects are not listed again
+		if lines != nil && len(restored) > 0 {
+			if err := lines.Write(restored); err != nil {
+				return nil, fmt.Errorf("failed to write NDJSON export: %w", err)
 			}
 		}
+		// A checkpoint saved after the last page has no token left
+		if state != nil && (state.Complete || (state.Token == "" && state.Objects > 0)) {
+			summary.Truncated = state.Truncated
+			if err := finishLines(); err != nil {
+				return nil, err
+			}
+			return objects, nil
+		}
+		checkpoint = ba.checkpoints.start(bucketName, ba.limit, ba.scope, state)
+		cursor = &listCursor{listed: int64(len(objects))}
+		if state != nil {
+			cursor.token = state.Token
+		}
+	}
 
-		result, err := ba.s3Client.ListObjectsV2(ctx, input)
-		if err != nil {
+	// A failed send stops streaming for this bucket but not the listing
+	streaming := ba.stream.Enabled()
+	streamed := 0
+
+	err := ba.walkObjects(ctx, bucketName, summary, cursor, func(page []types.ObjectMetadata) error {
+		objects = append(objects, page...)
+		addInterim(page)
+		if checkpoint != nil {
+			if err := checkpoint.add(page, cursor.token); err != nil {
+				ba.progress.Warnf("%v; continuing without checkpoints", err)
+				checkpoint = nil
+			}
+		}
+		if lines != nil {
+			if err := lines.Write(page); err != nil {
+				return fmt.Errorf("failed to write NDJSON export: %w", err)
+			}
+		}
+		if streaming {
+			if err := ba.stream.Send(ctx, bucketName, summary.Region, page); err != nil {
+				ba.progress.Warnf("failed to stream objects, stopping after %d: %v", streamed, err)
+				streaming = false
+			} else {
+				streamed += len(page)
+			}
+		}
+		// Show progress
+		ba.progress.Printf("Processed %d objects...\n", len(objects))
+		return nil
+	})
+	interrupted := err != nil && errors.Is(ctx.Err(), context.Canceled) && len(objects) > 0
+	if err != nil {
+		// Keep the pages listed so far for --resume
+		if checkpoint != nil && len(objects) > 0 {
+			if serr := checkpoint.save(); serr != nil {
+				ba.progress.Warnf("%v", serr)
+			} else {
+				ba.progress.Printf("Saved a checkpoint after %d objects; rerun with --resume to continue\n", len(objects))
+			}
+		}
+		if !interrupted {
 			return nil, err
 		}
+		// An interrupted listing is reported as far as it got
+		summary.Truncated, summary.Interrupted = true, true
+		ba.progress.Warnf("listing interrupted after %d objects; writing partial reports", len(objects))
+	}
+
+	if checkpoint != nil && !interrupted {
+		if err := checkpoint.finish(summary.Truncated); err != nil {
+			ba.progress.Warnf("%v", err)
+		}
+	}
+
+	if streaming {
+		ba.progress.Printf("Streamed %d objects to %s\n", streamed, ba.stream.target)
+	}
+
+	if err := listing.finish(); err != nil {
+		ba.progress.Warnf("%v", err)
+	}
+
+	if err := finishLines(); err != nil {
+		return nil, err
+	}
+
+	// Shards finish in any order; analyses see keys in listing order
+	if ba.sharded() && !slices.IsSortedFunc(objects, compareKeys) {
+		slices.SortFunc(objects, compareKeys)
+	}
+
+	if summary.Truncated && !interrupted {
+		ba.progress.Printf("Reached limit of %d objects\n", ba.limit)
+	}
+
+	return objects, nil
+}
+
+// restoreListing loads the bucket's checkpoint with --resume. A checkpoint
+// that cannot be resumed is reported and the bucket is listed again.
+func (ba *BucketAnalyzer) restoreListing(bucketName string) ([]types.ObjectMetadata, *checkpointState) {
+	if !ba.checkpoints.resume {
+		return nil, nil
+	}
+	objects, state, err := ba.checkpoints.Load(bucketName, ba.limit)
+	if err == nil && state != nil && !state.inScope(ba.scope) {
+		err = fmt.Errorf("checkpoint was taken with other --prefix, --include or --exclude values")
+	}
+	if err != nil {
+		ba.progress.Warnf("cannot resume, listing from the start: %v", err)
+		return nil, nil
+	}
+	if state == nil {
+		ba.progress.Printf("No checkpoint for %s, listing from the start\n", bucketName)
+		return nil, nil
+	}
+	if state.Complete {
+		ba.progress.Printf("Resuming from checkpoint of %s: listing complete with %d objects\n", state.Saved.Format(time.RFC3339), len(objects))
+	} else {
+		ba.progress.Printf("Resuming from checkpoint of %s after %d objects\n", state.Saved.Format(time.RFC3339), len(objects))
+	}
+	return objects, state
+}
 
-		// Process objects
-		for _, obj := range result.Contents {
-			key := aws.ToString(obj.Key)
-			size := aws.ToInt64(obj.Size)
-			storageClass := string(obj.StorageClass)
-			if storageClass == "" {
-				storageClass = "STANDARD"
+// listCursor is the position of a listing: the continuation token of the
+// next page ("" at the end) and the number of objects listed before it
+type listCursor struct {
+	token  string
+	listed int64
+}
+
+// walkObjects lists the bucket one page at a time, updating the summary
+// statistics and passing each page to fn. Listing stops early when fn
+// returns an error. With --use-inventory the latest S3 Inventory report is
+// read instead when the bucket has one. The prefixes of the key scope are
+// listed one after the other, without excluded keys. A cursor with a token
+// continues an earlier listing of a single prefix; walkObjects advances the
+// cursor before each call to fn.
+func (ba *BucketAnalyzer) walkObjects(ctx context.Context, bucketName string, summary *types.BucketSummary, cursor *listCursor, fn func([]types.ObjectMetadata) error) error {
+	var continuationToken *string
+	processedCount := int64(0)
+	if cursor != nil && cursor.token != "" {
+		continuationToken = aws.String(cursor.token)
+		processedCount = cursor.listed
+	} else if used, err := ba.walkInventory(ctx, bucketName, summary, fn); used {
+		return err
+	} else if ba.sharded() {
+		return ba.walkShards(ctx, bucketName, summary, fn)
+	}
+
+	// One request is in flight at a time; the controller only measures
+	// the rate
+	control := newConcurrencyController("ListObjectsV2", 1, false)
+	defer func() {
+		summary.Performance = append(summary.Performance, control.stats())
+	}()
+
+	for _, prefix := range ba.scope.listPrefixes() {
+		for {
+			// Check if we've reached the limit
+			if ba.limit > 0 && processedCount >= ba.limit {
+				// The previous page was truncated, so keys remain unlisted
+				summary.Truncated = true
+				return nil
 			}
 
-			// Update summary statistics
-			summary.TotalObjects++
-			summary.TotalSize += size
+			input := &s3.ListObjectsV2Input{
+				Bucket:            aws.String(bucketName),
+				Prefix:            aws.String(prefix),
+				ContinuationToken: continuationToken,
+			}
 
-			// Update storage class stats
-			stats := summary.StorageClasses[storageClass]
-			stats.Count++
-			stats.Size += size
-			summary.StorageClasses[storageClass] = stats
+			// Set max keys if limit is specified
+			if ba.limit > 0 {
+				remaining := ba.limit - processedCount
+				if remaining < 1000 {
+					input.MaxKeys = aws.Int32(int32(remaining))
+				}
+			}
 
-			// Collect object metadata
-			objects = append(objects, types.ObjectMetadata{
-				Key:          key,
-				Size:         size,
-				LastModified: aws.ToTime(obj.LastModified),
-				StorageClass: storageClass,
-				ETag:         aws.ToString(obj.ETag),
-			})
+			result, err := ba.s3Client.ListObjectsV2(ctx, input, control.options)
+			if err != nil {
+				return err
+			}
 
-			processedCount++
-		}
+			// Process objects
+			listed := listedObjects(result.Contents)
+			control.addItems(len(listed))
+			page := ba.scope.filter(listed)
+			for _, object := range page {
+				countObject(summary, object)
+				processedCount++
+			}
 
-		// Show progress
-		fmt.Printf("Processed %d objects...\n", processedCount)
+			if cursor != nil {
+				cursor.listed = processedCount
+				cursor.token = ""
+				if aws.ToBool(result.IsTruncated) {
+					cursor.token = aws.ToString(result.NextContinuationToken)
+				}
+			}
 
-		// Check if there are more results
-		if !aws.ToBool(result.IsTruncated) {
-			break
+			if err := fn(page); err != nil {
+				return err
+			}
+
+			// Check if there are more results
+			if !aws.ToBool(result.IsTruncated) {
+				break
+			}
+
+			continuationToken = result.NextContinuationToken
 		}
+		continuationToken = nil
+	}
+	return nil
+}
 
-		continuationToken = result.NextContinuationToken
+// listedObjects converts a ListObjectsV2 page to object metadata
+func listedObjects(contents []s3types.Object) []types.ObjectMetadata {
+	page := make([]types.ObjectMetadata, 0, len(contents))
+	for _, obj := range contents {
+		storageClass := string(obj.StorageClass)
+		if storageClass == "" {
+			storageClass = "STANDARD"
+		}
+		page = append(page, types.ObjectMetadata{
+			Key:          aws.ToString(obj.Key),
+			Size:         aws.ToInt64(obj.Size),
+			LastModified: aws.ToTime(obj.LastModified),
+			StorageClass: storageClass,
+			ETag:         aws.ToString(obj.ETag),
+		})
 	}
+	return page
+}
 
-	return objects, nil
+// countObject adds an object to the summary totals and its storage class
+func countObject(summary *types.BucketSummary, obj types.ObjectMetadata) {
+	summary.TotalObjects++
+	summary.TotalSize += obj.Size
+	stats := summary.StorageClasses[obj.StorageClass]
+	stats.Count++
+	stats.Size += obj.Size
+	stats.Billable += billableBytes(obj.StorageClass, obj.Size)
+	summary.StorageClasses[obj.StorageClass] = stats
 }
 
-// calculateCost estimates monthly storage cost based on storage classes
-func (ba *BucketAnalyzer) calculateCost(storageClasses map[string]types.StorageClassStats) float64 {
-	// Pricing per GB per month (approximate US East)
-	pricing := map[string]float64{
-		"STANDARD":            0.023,
-		"INTELLIGENT_TIERING": 0.023,
-		"STANDARD_IA":         0.0125,
-		"ONEZONE_IA":          0.01,
-		"GLACIER":             0.004,
-		"GLACIER_IR":          0.004,
-		"DEEP_ARCHIVE":        0.00099,
+// storagePrices is a price per GB per month per storage class
+type storagePrices map[string]float64
+
+// defaultStoragePrices are approximate US East prices
+var defaultStoragePrices = storagePrices{
+	"STANDARD":            0.023,
+	"INTELLIGENT_TIERING": 0.023,
+	"STANDARD_IA":         0.0125,
+	"ONEZONE_IA":          0.01,
+	"GLACIER":             0.004,
+	"GLACIER_IR":          0.004,
+	"DEEP_ARCHIVE":        0.00099,
+}
+
+// CheckStoragePrices checks overrides of the price per GB per month of
+// storage classes, as set in ProfileConfig.StoragePrices
+func CheckStoragePrices(prices map[string]float64) error {
+	for class, price := range prices {
+		if _, ok := defaultStoragePrices[class]; !ok {
+			return fmt.Errorf("unknown storage class %q in storage prices", class)
+		}
+		if price < 0 {
+			return fmt.Errorf("negative storage price for %s", class)
+		}
 	}
+	return nil
+}
 
+// newStoragePrices returns the default prices with overrides applied
+func newStoragePrices(overrides map[string]float64) (storagePrices, error) {
+	if err := CheckStoragePrices(overrides); err != nil {
+		return nil, err
+	}
+	prices := maps.Clone(defaultStoragePrices)
+	maps.Copy(prices, overrides)
+	return prices, nil
+}
+
+// calculateCost estimates monthly storage cost based on storage classes,
+// recording each class's share. Classes are summed in name order, so the
+// total does not change in its last digits from run to run.
+func (ba *BucketAnalyzer) calculateCost(storageClasses map[string]types.StorageClassStats) float64 {
 	totalCost := 0.0
-	for class, stats := range storageClasses {
-		sizeGB := float64(stats.Size) / (1024 * 1024 * 1024)
-		if price, ok := pricing[class]; ok {
-			totalCost += sizeGB * price
-		} else {
-			// Default to STANDARD pricing if unknown
-			totalCost += sizeGB * pricing["STANDARD"]
-		}
+	for _, class := range slices.Sorted(maps.Keys(storageClasses)) {
+		stats := storageClasses[class]
+		stats.Cost = ba.prices.cost(class, stats.Size)
+		storageClasses[class] = stats
+		totalCost += stats.Cost
 	}
 
 	return totalCost
 }
 
+// cost estimates the monthly cost of storing size bytes in a class; a nil
+// table has the default prices
+func (sp storagePrices) cost(class string, size int64) float64 {
+	if sp == nil {
+		sp = defaultStoragePrices
+	}
+	sizeGB := float64(size) / (1024 * 1024 * 1024)
+	if price, ok := sp[class]; ok {
+		return sizeGB * price
+	}
+	// Default to STANDARD pricing if unknown
+	return sizeGB * sp["STANDARD"]
+}
+
 // ListAllBuckets returns a list of all bucket names
 func ListAllBuckets(ctx context.Context, s3Client *s3.Client) ([]string, error) {
 	result, err := s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
@@ -0,0 +1,47 @@
+package profiler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStoragePrices(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]float64
+		class     string
+		want      float64
+		wantErr   string
+	}{
+		{"default", nil, "STANDARD_IA", 0.0125, ""},
+		{"override", map[string]float64{"STANDARD": 0.0245}, "STANDARD", 0.0245, ""},
+		{"unknown class priced as standard", map[string]float64{"STANDARD": 0.03}, "REDUCED_REDUNDANCY", 0.03, ""},
+		{"unknown override", map[string]float64{"FAST": 1}, "", 0, "unknown storage class"},
+		{"negative", map[string]float64{"GLACIER": -1}, "", 0, "negative storage price"},
+	}
+	for _, tt := range tests {
+		prices, err := newStoragePrices(tt.overrides)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := prices.cost(tt.class, 1<<30); got != tt.want {
+			t.Errorf("%s: cost of 1 GiB in %s = %v, want %v", tt.name, tt.class, got, tt.want)
+		}
+	}
+
+	// Overrides belong to one table; the defaults and other profilers keep
+	// their own prices
+	if got := defaultStoragePrices["STANDARD"]; got != 0.023 {
+		t.Errorf("default STANDARD price changed to %v", got)
+	}
+	var none storagePrices
+	if got := none.cost("STANDARD", 1<<30); got != 0.023 {
+		t.Errorf("nil table: STANDARD cost = %v, want the default 0.023", got)
+	}
+}
@@ -0,0 +1,126 @@
+package profiler
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// cacheVersion is bumped whenever analyzer output changes for the same
+// inventory, so stale cache entries are ignored
+const cacheVersion = 7
+
+// AnalysisCache stores analyzer results on disk keyed by a checksum of the
+// listed inventory, so re-running over an unchanged bucket skips partition
+// detection
+type AnalysisCache struct {
+	dir string
+}
+
+// cachedAnalysis is the on-disk cache entry
+type cachedAnalysis struct {
+	Version    int                      `json:"version"`
+	Checksum   string                   `json:"checksum"`
+	Partitions *types.PartitionAnalysis `json:"partitions"`
+}
+
+// NewAnalysisCache creates a cache in dir; an empty dir disables caching
+func NewAnalysisCache(dir string) *AnalysisCache {
+	return &AnalysisCache{dir: dir}
+}
+
+// Enabled reports whether a cache directory was configured
+func (c *AnalysisCache) Enabled() bool {
+	return c.dir != ""
+}
+
+// InventoryChecksum hashes every listed object's key, size, modification
+// time, storage class and ETag in listing order
+func InventoryChecksum(objects []types.ObjectMetadata) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, obj := range objects {
+		h.Write([]byte(obj.Key))
+		h.Write([]byte{0})
+		binary.BigEndian.PutUint64(buf[:], uint64(obj.Size))
+		h.Write(buf[:])
+		binary.BigEndian.PutUint64(buf[:], uint64(obj.LastModified.UnixNano()))
+		h.Write(buf[:])
+		h.Write([]byte(obj.StorageClass))
+		h.Write([]byte{0})
+		h.Write([]byte(obj.ETag))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the cache file for a checksum
+func (c *AnalysisCache) path(checksum string) string {
+	return filepath.Join(c.dir, checksum+".json")
+}
+
+// LoadPartitions returns the cached partition analysis for an inventory,
+// or nil when there is no usable entry
+func (c *AnalysisCache) LoadPartitions(checksum string) (*types.PartitionAnalysis, error) {
+	data, err := os.ReadFile(c.path(checksum))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis cache: %w", err)
+	}
+
+	var entry cachedAnalysis
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis cache %s: %w", c.path(checksum), err)
+	}
+	if entry.Version != cacheVersion || entry.Checksum != checksum {
+		return nil, nil
+	}
+	return entry.Partitions, nil
+}
+
+// StorePartitions saves the partition analysis for an inventory. The file
+// is written to a temporary name and renamed so readers never see a
+// partial entry.
+func (c *AnalysisCache) StorePartitions(checksum string, analysis *types.PartitionAnalysis) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cachedAnalysis{
+		Version:    cacheVersion,
+		Checksum:   checksum,
+		Partitions: analysis,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, checksum+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(checksum)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package profiler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// checkpointVersion is bumped whenever the checkpoint format changes, so
+// checkpoints of older releases are not resumed
+const checkpointVersion = 1
+
+// Checkpointer saves the progress of long listings so an interrupted run
+// can resume with --resume instead of listing the bucket again. Each
+// bucket has a state file with the ListObjectsV2 continuation token and an
+// object log the listed pages are appended to as gzip members.
+type Checkpointer struct {
+	dir      string
+	interval time.Duration
+	resume   bool
+}
+
+// checkpointState is the state file. LogSize is the length of the object
+// log matching Token; anything after it was written by an interrupted save.
+type checkpointState struct {
+	Version   int       `json:"version"`
+	Bucket    string    `json:"bucket"`
+	Limit     int64     `json:"limit"`
+	Prefixes  []string  `json:"prefixes,omitempty"`
+	Include   []string  `json:"include,omitempty"`
+	Exclude   []string  `json:"exclude,omitempty"`
+	Token     string    `json:"token"`
+	Complete  bool      `json:"complete"`
+	Truncated bool      `json:"truncated"`
+	Objects   int64     `json:"objects"`
+	LogSize   int64     `json:"log_size"`
+	Saved     time.Time `json:"saved"`
+}
+
+// NewCheckpointer creates a checkpointer saving to dir every interval; an
+// empty dir or a zero interval disables checkpoints
+func NewCheckpointer(dir string, interval time.Duration, resume bool) *Checkpointer {
+	return &Checkpointer{dir: dir, interval: interval, resume: resume}
+}
+
+// Enabled reports whether checkpoints are written
+func (c *Checkpointer) Enabled() bool {
+	return c != nil && c.dir != "" && c.interval > 0
+}
+
+func (c *Checkpointer) statePath(bucketName string) string {
+	return filepath.Join(c.dir, bucketName+".checkpoint.json")
+}
+
+func (c *Checkpointer) logPath(bucketName string) string {
+	return filepath.Join(c.dir, bucketName+".checkpoint-objects.csv.gz")
+}
+
+// Load returns the objects and state of a bucket's checkpoint, or nil when
+// there is none. Checkpoints of another --limit are not resumable.
+func (c *Checkpointer) Load(bucketName string, limit int64) ([]types.ObjectMetadata, *checkpointState, error) {
+	data, err := os.ReadFile(c.statePath(bucketName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var state checkpointState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse checkpoint %s: %w", c.statePath(bucketName), err)
+	}
+	if state.Version != checkpointVersion || state.Bucket != bucketName {
+		return nil, nil, fmt.Errorf("checkpoint %s is from another version or bucket", c.statePath(bucketName))
+	}
+	if state.Limit != limit {
+		return nil, nil, fmt.Errorf("checkpoint was taken with --limit %d, not %d", state.Limit, limit)
+	}
+
+	objects, err := readCheckpointLog(c.logPath(bucketName), state.LogSize, state.Objects)
+	if err != nil {
+		return nil, nil, err
+	}
+	return objects, &state, nil
+}
+
+// readCheckpointLog reads the first size bytes of an object log
+func readCheckpointLog(path string, size, count int64) ([]types.ObjectMetadata, error) {
+	objects := make([]types.ObjectMetadata, 0, count)
+	if size == 0 {
+		return objects, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	// Concatenated gzip members read as one stream
+	zr, err := gzip.NewReader(bufio.NewReader(io.LimitReader(file, size)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+	cr := csv.NewReader(zr)
+	cr.FieldsPerRecord = 5
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+		}
+		size, err := strconv.ParseInt(record[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+		}
+		modified, err := time.Parse(time.RFC3339Nano, record[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+		}
+		objects = append(objects, types.ObjectMetadata{
+			Key:          record[0],
+			Size:         size,
+			LastModified: modified,
+			StorageClass: record[3],
+			ETag:         record[4],
+		})
+	}
+
+	if int64(len(objects)) != count {
+		return nil, fmt.Errorf("checkpoint %s holds %d objects, expected %d", path, len(objects), count)
+	}
+	return objects, nil
+}
+
+// Remove deletes a bucket's checkpoint once its profile is complete
+func (c *Checkpointer) Remove(bucketName string) error {
+	for _, path := range []string{c.statePath(bucketName), c.logPath(bucketName)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove checkpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+// listingCheckpoint saves one bucket's listing as it progresses
+type listingCheckpoint struct {
+	c       *Checkpointer
+	state   checkpointState
+	pending []types.ObjectMetadata
+	saved   time.Time
+}
+
+// start begins checkpointing a listing, continuing the restored state when
+// there is one and replacing any older checkpoint otherwise
+func (c *Checkpointer) start(bucketName string, limit int64, scope keyScope, restored *checkpointState) *listingCheckpoint {
+	lc := &listingCheckpoint{c: c, saved: time.Now()}
+	if restored != nil {
+		lc.state = *restored
+	} else {
+		lc.state = checkpointState{
+			Version:  checkpointVersion,
+			Bucket:   bucketName,
+			Limit:    limit,
+			Prefixes: scope.prefixes,
+			Include:  scope.include,
+			Exclude:  scope.exclude,
+		}
+	}
+	return lc
+}
+
+// inScope reports whether a checkpoint was taken with the given key scope
+func (s *checkpointState) inScope(scope keyScope) bool {
+	return slices.Equal(s.Prefixes, scope.prefixes) && slices.Equal(s.Include, scope.include) &&
+		slices.Equal(s.Exclude, scope.exclude)
+}
+
+// add records a listed page and the token that continues after it, saving
+// when the interval has passed
+func (lc *listingCheckpoint) add(page []types.ObjectMetadata, token string) error {
+	lc.pending = append(lc.pending, page...)
+	lc.state.Token = token
+	if time.Since(lc.saved) < lc.c.interval {
+		return nil
+	}
+	return lc.save()
+}
+
+// finish saves the complete listing, so a run interrupted during analysis
+// resumes without listing again. Listings that finished before the first
+// checkpoint are quick to repeat and are not saved.
+func (lc *listingCheckpoint) finish(truncated bool) error {
+	if lc.state.Saved.IsZero() {
+		return nil
+	}
+	lc.state.Complete = true
+	lc.state.Truncated = truncated
+	lc.state.Token = ""
+	return lc.save()
+}
+
+// save appends the pending objects to the log as one gzip member, syncs
+// it, and then replaces the state file, so the state never refers to log
+// data that is not on disk
+func (lc *listingCheckpoint) save() error {
+	if err := os.MkdirAll(lc.c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	bucketName := lc.state.Bucket
+	flags := os.O_CREATE | os.O_WRONLY
+	if lc.state.LogSize == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(lc.c.logPath(bucketName), flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	// Drop whatever an interrupted save appended after the last state
+	if err := file.Truncate(lc.state.LogSize); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if _, err := file.Seek(lc.state.LogSize, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	if len(lc.pending) > 0 {
+		counter := &countingWriter{w: file}
+		zw := gzip.NewWriter(counter)
+		cw := csv.NewWriter(zw)
+		for _, obj := range lc.pending {
+			if err := cw.Write([]string{
+				obj.Key,
+				strconv.FormatInt(obj.Size, 10),
+				obj.LastModified.UTC().Format(time.RFC3339Nano),
+				obj.StorageClass,
+				obj.ETag,
+			}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := errors.Join(cw.Error(), zw.Close()); err != nil {
+			return fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+		lc.state.LogSize += counter.n
+		lc.state.Objects += int64(len(lc.pending))
+	}
+
+	lc.state.Saved = time.Now().UTC()
+	if err := lc.writeState(); err != nil {
+		return err
+	}
+	lc.pending = lc.pending[:0]
+	lc.saved = time.Now()
+	return nil
+}
+
+// writeState replaces the state file through a temporary file
+func (lc *listingCheckpoint) writeState() error {
+	data, err := json.MarshalIndent(lc.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := lc.c.statePath(lc.state.Bucket)
+	tmp, err := os.CreateTemp(lc.c.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,183 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// checkpointPage returns n objects with keys numbered from first
+func checkpointPage(first, n int) []types.ObjectMetadata {
+	modified := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	page := make([]types.ObjectMetadata, n)
+	for i := range page {
+		page[i] = types.ObjectMetadata{
+			Key:          fmt.Sprintf("logs/part,\"%04d\".json", first+i),
+			Size:         int64(100 * (first + i)),
+			LastModified: modified.Add(time.Duration(first+i) * time.Second),
+			StorageClass: "STANDARD_IA",
+			ETag:         fmt.Sprintf("\"etag-%d\"", first+i),
+		}
+	}
+	return page
+}
+
+func TestCheckpointResume(t *testing.T) {
+	c := NewCheckpointer(t.TempDir(), time.Nanosecond, true)
+	scope := keyScope{prefixes: []string{"logs/"}}
+
+	lc := c.start("bucket", 1000, scope, nil)
+	if err := lc.add(checkpointPage(0, 3), "token-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.add(checkpointPage(3, 2), "token-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// An interrupted save leaves data after the last state's log size
+	log, err := os.OpenFile(c.logPath("bucket"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.WriteString("partial gzip member")
+	log.Close()
+
+	objects, state, err := c.Load("bucket", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := checkpointPage(0, 5); !slices.EqualFunc(objects, want, sameObject) {
+		t.Errorf("restored %+v, want %+v", objects, want)
+	}
+	if state.Token != "token-2" || state.Complete || !state.inScope(scope) {
+		t.Errorf("state = %+v, want an incomplete listing at token-2", state)
+	}
+	if state.inScope(keyScope{prefixes: []string{"other/"}}) {
+		t.Error("checkpoint resumed with another prefix")
+	}
+
+	// Resuming overwrites the partial member and finishes the listing
+	lc = c.start("bucket", 1000, scope, state)
+	if err := lc.add(checkpointPage(5, 1), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.finish(true); err != nil {
+		t.Fatal(err)
+	}
+	objects, state, err = c.Load("bucket", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := checkpointPage(0, 6); !slices.EqualFunc(objects, want, sameObject) {
+		t.Errorf("restored %d objects after finishing, want %d", len(objects), len(want))
+	}
+	if !state.Complete || !state.Truncated || state.Token != "" {
+		t.Errorf("state = %+v, want a complete truncated listing", state)
+	}
+
+	if err := c.Remove("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if objects, state, err := c.Load("bucket", 1000); objects != nil || state != nil || err != nil {
+		t.Errorf("Load after Remove = %v, %v, %v, want no checkpoint", objects, state, err)
+	}
+}
+
+func sameObject(a, b types.ObjectMetadata) bool {
+	return a.Key == b.Key && a.Size == b.Size && a.LastModified.Equal(b.LastModified) &&
+		a.StorageClass == b.StorageClass && a.ETag == b.ETag
+}
+
+// TestCheckpointFinishBeforeFirstSave checks that listings finishing
+// within the interval leave no checkpoint behind
+func TestCheckpointFinishBeforeFirstSave(t *testing.T) {
+	c := NewCheckpointer(t.TempDir(), time.Hour, true)
+	lc := c.start("bucket", 0, keyScope{}, nil)
+	if err := lc.add(checkpointPage(0, 2), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.finish(false); err != nil {
+		t.Fatal(err)
+	}
+	if _, state, err := c.Load("bucket", 0); state != nil || err != nil {
+		t.Errorf("Load = %+v, %v, want no checkpoint", state, err)
+	}
+}
+
+func TestCheckpointLoadRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int64
+		modify  func(state string) string
+		wantErr string
+	}{
+		{"other limit", 500, nil, "--limit 1000, not 500"},
+		{"other version", 1000, func(s string) string {
+			return strings.Replace(s, `"version": 1`, `"version": 0`, 1)
+		}, "another version or bucket"},
+		{"other bucket", 1000, func(s string) string {
+			return strings.Replace(s, `"bucket": "bucket"`, `"bucket": "other"`, 1)
+		}, "another version or bucket"},
+		{"object count mismatch", 1000, func(s string) string {
+			return strings.Replace(s, `"objects": 2`, `"objects": 3`, 1)
+		}, "holds 2 objects, expected 3"},
+		{"corrupt state", 1000, func(string) string { return "{" }, "failed to parse checkpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCheckpointer(t.TempDir(), time.Nanosecond, true)
+			if err := c.start("bucket", 1000, keyScope{}, nil).add(checkpointPage(0, 2), "token"); err != nil {
+				t.Fatal(err)
+			}
+			if tt.modify != nil {
+				data, err := os.ReadFile(c.statePath("bucket"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(c.statePath("bucket"), []byte(tt.modify(string(data))), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, _, err := c.Load("bucket", tt.limit)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestoreListing(t *testing.T) {
+	dir := t.TempDir()
+	scope := keyScope{include: []string{"*.json"}}
+	if err := NewCheckpointer(dir, time.Nanosecond, false).start("bucket", 0, scope, nil).add(checkpointPage(0, 4), "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		resume      bool
+		scope       keyScope
+		wantObjects int
+	}{
+		{"resume", true, scope, 4},
+		{"without --resume", false, scope, 0},
+		{"other scope", true, keyScope{include: []string{"*.csv"}}, 0},
+	}
+	for _, tt := range tests {
+		ba := &BucketAnalyzer{
+			checkpoints: NewCheckpointer(dir, time.Nanosecond, tt.resume),
+			scope:       tt.scope,
+			progress:    NewWriterReporter(io.Discard),
+		}
+		objects, state := ba.restoreListing("bucket")
+		if len(objects) != tt.wantObjects || (state != nil) != (tt.wantObjects > 0) {
+			t.Errorf("%s: restored %d objects (state %v), want %d", tt.name, len(objects), state != nil, tt.wantObjects)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package profiler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/yourusername/s3-profiler/kafka"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// BucketProfiledType is the CloudEvents type of the event published when a
+// bucket profile completes
+const BucketProfiledType = "com.github.yourusername.s3-profiler.bucket.profiled"
+
+// BucketProfiled is the data of a BucketProfiledType event
+type BucketProfiled struct {
+	Bucket         string                       `json:"bucket"`
+	Region         string                       `json:"region"`
+	TotalObjects   int64                        `json:"total_objects"`
+	TotalSize      int64                        `json:"total_size"`
+	EstimatedCost  float64                      `json:"estimated_monthly_cost"`
+	StorageClasses map[string]EventStorageClass `json:"storage_classes"`
+	Partitions     int                          `json:"partitions"`
+	Patterns       []EventPattern               `json:"patterns,omitempty"`
+	// Truncated is set when --limit stopped the listing early
+	Truncated bool                   `json:"truncated"`
+	Estimate  *types.ListingEstimate `json:"estimate,omitempty"`
+	OutputDir string                 `json:"output_dir"`
+}
+
+// EventStorageClass is the objects and bytes in one storage class
+type EventStorageClass struct {
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
+
+// EventPattern is a detected date partition pattern
+type EventPattern struct {
+	Scope    string  `json:"scope"`
+	Pattern  string  `json:"pattern"`
+	Objects  int64   `json:"objects"`
+	Size     int64   `json:"size"`
+	Coverage float64 `json:"coverage"`
+}
+
+// EventPublisher sends a CloudEvent per completed bucket profile to an
+// HTTP endpoint or a Kafka topic
+type EventPublisher struct {
+	target string
+	http   cloudevents.Client
+	kafka  *kafka.Writer
+}
+
+// NewEventPublisher creates a publisher for a target: an http(s):// URL
+// receiving binary-mode events, or kafka://broker[,broker...]/topic
+// (kafka+tls:// for TLS) receiving structured-mode JSON events keyed by
+// bucket. An empty target disables publishing.
+func NewEventPublisher(target string) (*EventPublisher, error) {
+	p := &EventPublisher{target: target}
+	if target == "" {
+		return p, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event target %q: %w", target, err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		p.http, err = cloudevents.NewClientHTTP(cloudevents.WithTarget(target))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CloudEvents client: %w", err)
+		}
+	case "kafka", "kafka+tls":
+		config, err := kafka.ParseURL(target)
+		if err != nil {
+			return nil, err
+		}
+		p.kafka = kafka.NewWriter(config)
+	default:
+		return nil, fmt.Errorf("unsupported event target %q (use http://, https://, kafka:// or kafka+tls://)", target)
+	}
+	return p, nil
+}
+
+// Enabled reports whether an event target was configured
+func (p *EventPublisher) Enabled() bool {
+	return p.target != ""
+}
+
+// Publish sends the event for a completed bucket profile
+func (p *EventPublisher) Publish(ctx context.Context, data BucketProfiled) error {
+	id, err := newUUID()
+	if err != nil {
+		return err
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID(id)
+	event.SetSource("s3-profiler")
+	event.SetType(BucketProfiledType)
+	event.SetSubject(data.Bucket)
+	event.SetTime(time.Now().UTC())
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return err
+	}
+
+	if p.kafka != nil {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return p.kafka.WriteMessages(ctx, kafka.Message{
+			Key:     []byte(data.Bucket),
+			Value:   payload,
+			Headers: []kafka.Header{{Key: "content-type", Value: []byte(cloudevents.ApplicationCloudEventsJSON)}},
+		})
+	}
+
+	if result := p.http.Send(ctx, event); !cloudevents.IsACK(result) {
+		return fmt.Errorf("failed to send CloudEvent to %s: %w", p.target, result)
+	}
+	return nil
+}
+
+// Close releases the publisher's connections
+func (p *EventPublisher) Close() error {
+	if p.kafka != nil {
+		return p.kafka.Close()
+	}
+	return nil
+}
+
+// bucketProfiled builds the event data for a profiled bucket
+func bucketProfiled(summary *types.BucketSummary, analysis *types.PartitionAnalysis, outputDir string) BucketProfiled {
+	data := BucketProfiled{
+		Bucket:         summary.Name,
+		Region:         summary.Region,
+		TotalObjects:   summary.TotalObjects,
+		TotalSize:      summary.TotalSize,
+		EstimatedCost:  summary.EstimatedCost,
+		StorageClasses: make(map[string]EventStorageClass, len(summary.StorageClasses)),
+		Partitions:     len(analysis.Partitions),
+		Truncated:      summary.Truncated,
+		Estimate:       summary.Estimate,
+		OutputDir:      outputDir,
+	}
+	for class, stats := range summary.StorageClasses {
+		data.StorageClasses[class] = EventStorageClass{Objects: stats.Count, Size: stats.Size}
+	}
+	for _, pattern := range analysis.Patterns {
+		data.Patterns = append(data.Patterns, EventPattern{
+			Scope:    pattern.Scope,
+			Pattern:  pattern.Pattern,
+			Objects:  pattern.ObjectCount,
+			Size:     pattern.TotalSize,
+			Coverage: pattern.Coverage,
+		})
+	}
+	return data
+}
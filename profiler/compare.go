@@ -0,0 +1,252 @@
+package profiler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// CompareSnapshots attributes the change between two runs of a bucket to
+// the prefixes and partitions that grew or shrank
+func CompareSnapshots(old, new *types.Snapshot) *types.Comparison {
+	comparison := &types.Comparison{
+		Bucket:       new.Bucket,
+		Estimated:    old.Estimate != nil || new.Estimate != nil,
+		OldGenerated: old.Generated,
+		NewGenerated: new.Generated,
+		OldObjects:   old.TotalObjects,
+		NewObjects:   new.TotalObjects,
+		OldSize:      old.TotalSize,
+		NewSize:      new.TotalSize,
+		Prefixes:     diffPrefixStats(old.Prefixes, new.Prefixes),
+		Partitions:   diffPrefixStats(old.Partitions, new.Partitions),
+	}
+
+	// Snapshot prefixes do not overlap, so rolling them up by top-level
+	// prefix accounts for the whole bucket delta
+	comparison.TopLevel = diffPrefixStats(rollUpTopLevel(old.Prefixes), rollUpTopLevel(new.Prefixes))
+
+	comparison.Churn = estimateChurn(old, new)
+	comparison.ChurnTruncated = new.Generated.Sub(old.Generated) > snapshotHistoryDays*24*time.Hour
+
+	return comparison
+}
+
+// estimateChurn combines the current run's LastModified history with the
+// size change of each prefix. Bytes written since the previous run that did
+// not add to the prefix's size must have replaced deleted or overwritten
+// data; that replaced volume is the churn.
+func estimateChurn(old, new *types.Snapshot) []types.PrefixChurn {
+	// Day granularity: writes on the previous run's day are not counted
+	since := old.Generated.UTC().Format(dayLayout)
+	days := new.Generated.Sub(old.Generated).Hours() / 24
+
+	oldSizes := make(map[string]int64, len(old.Prefixes))
+	for _, s := range old.Prefixes {
+		oldSizes[s.Prefix] = s.Size
+	}
+
+	var churn []types.PrefixChurn
+	seen := make(map[string]bool, len(new.Prefixes))
+	for _, s := range new.Prefixes {
+		seen[s.Prefix] = true
+
+		var written int64
+		for day, size := range s.Written {
+			if day > since {
+				written += size
+			}
+		}
+
+		oldSize := oldSizes[s.Prefix]
+		removed := oldSize + written - s.Size
+		if removed < 0 {
+			removed = 0
+		}
+		if written == 0 && removed == 0 {
+			continue
+		}
+
+		c := types.PrefixChurn{
+			Prefix:   s.Prefix,
+			Written:  written,
+			Removed:  removed,
+			Replaced: min(written, removed),
+		}
+		if average := float64(oldSize+s.Size) / 2; average > 0 && days > 0 {
+			c.MonthlyRate = float64(c.Replaced) / average * 30 / days
+		}
+		churn = append(churn, c)
+	}
+
+	// Prefixes that disappeared entirely were removed, not replaced
+	for _, s := range old.Prefixes {
+		if !seen[s.Prefix] && s.Size > 0 {
+			churn = append(churn, types.PrefixChurn{Prefix: s.Prefix, Removed: s.Size})
+		}
+	}
+
+	sort.Slice(churn, func(i, j int) bool {
+		if churn[i].Replaced != churn[j].Replaced {
+			return churn[i].Replaced > churn[j].Replaced
+		}
+		if churn[i].Written != churn[j].Written {
+			return churn[i].Written > churn[j].Written
+		}
+		return churn[i].Prefix < churn[j].Prefix
+	})
+
+	return churn
+}
+
+// diffPrefixStats matches prefixes between runs and returns those that
+// changed, largest absolute size change first
+func diffPrefixStats(old, new []types.PrefixStats) []types.PrefixDelta {
+	deltas := make(map[string]*types.PrefixDelta)
+	get := func(prefix string) *types.PrefixDelta {
+		d, exists := deltas[prefix]
+		if !exists {
+			d = &types.PrefixDelta{Prefix: prefix}
+			deltas[prefix] = d
+		}
+		return d
+	}
+
+	for _, s := range old {
+		d := get(s.Prefix)
+		d.OldCount += s.ObjectCount
+		d.OldSize += s.Size
+	}
+	for _, s := range new {
+		d := get(s.Prefix)
+		d.NewCount += s.ObjectCount
+		d.NewSize += s.Size
+	}
+
+	var result []types.PrefixDelta
+	for _, d := range deltas {
+		d.SizeDelta = d.NewSize - d.OldSize
+		d.CountDelta = d.NewCount - d.OldCount
+		if d.SizeDelta == 0 && d.CountDelta == 0 {
+			continue
+		}
+		result = append(result, *d)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := abs(result[i].SizeDelta), abs(result[j].SizeDelta)
+		if a != b {
+			return a > b
+		}
+		return result[i].Prefix < result[j].Prefix
+	})
+
+	return result
+}
+
+// rollUpTopLevel aggregates prefix stats by their first key segment
+func rollUpTopLevel(stats []types.PrefixStats) []types.PrefixStats {
+	totals := make(map[string]*types.PrefixStats)
+	var order []string
+
+	for _, s := range stats {
+		prefix := topLevelPrefix(s.Prefix)
+		t, exists := totals[prefix]
+		if !exists {
+			t = &types.PrefixStats{Prefix: prefix}
+			totals[prefix] = t
+			order = append(order, prefix)
+		}
+		t.ObjectCount += s.ObjectCount
+		t.Size += s.Size
+	}
+
+	result := make([]types.PrefixStats, 0, len(order))
+	for _, prefix := range order {
+		result = append(result, *totals[prefix])
+	}
+	return result
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// DiffSnapshots compares the summaries of two runs of a bucket: totals,
+// storage classes and the partitions that appeared or disappeared
+func DiffSnapshots(old, new *types.Snapshot) *types.SnapshotDiff {
+	diff := &types.SnapshotDiff{
+		Bucket:       new.Bucket,
+		Estimated:    old.Estimate != nil || new.Estimate != nil,
+		OldGenerated: old.Generated,
+		NewGenerated: new.Generated,
+		OldObjects:   old.TotalObjects,
+		NewObjects:   new.TotalObjects,
+		OldSize:      old.TotalSize,
+		NewSize:      new.TotalSize,
+		OldCost:      old.EstimatedCost,
+		NewCost:      new.EstimatedCost,
+	}
+
+	// An empty bucket has no classes either, so only a non-empty run
+	// without them predates storage class totals
+	if (len(old.StorageClasses) == 0 && old.TotalObjects > 0) || (len(new.StorageClasses) == 0 && new.TotalObjects > 0) {
+		diff.ClassesMissing = true
+	} else {
+		classes := make(map[string]*types.ClassDelta)
+		class := func(name string) *types.ClassDelta {
+			d, exists := classes[name]
+			if !exists {
+				d = &types.ClassDelta{StorageClass: name}
+				classes[name] = d
+			}
+			return d
+		}
+		for _, c := range old.StorageClasses {
+			d := class(c.StorageClass)
+			d.OldObjects, d.OldSize = c.ObjectCount, c.Size
+		}
+		for _, c := range new.StorageClasses {
+			d := class(c.StorageClass)
+			d.NewObjects, d.NewSize = c.ObjectCount, c.Size
+		}
+		for _, d := range classes {
+			diff.Classes = append(diff.Classes, *d)
+		}
+		sort.Slice(diff.Classes, func(i, j int) bool {
+			a, b := diff.Classes[i], diff.Classes[j]
+			if a.NewSize != b.NewSize {
+				return a.NewSize > b.NewSize
+			}
+			return a.StorageClass < b.StorageClass
+		})
+	}
+
+	oldPartitions := make(map[string]bool, len(old.Partitions))
+	for _, p := range old.Partitions {
+		oldPartitions[p.Prefix] = true
+	}
+	newPartitions := make(map[string]bool, len(new.Partitions))
+	for _, p := range new.Partitions {
+		newPartitions[p.Prefix] = true
+		if !oldPartitions[p.Prefix] {
+			diff.NewPartitions = append(diff.NewPartitions, p)
+		}
+	}
+	for _, p := range old.Partitions {
+		if !newPartitions[p.Prefix] {
+			diff.RemovedPartitions = append(diff.RemovedPartitions, p)
+		}
+	}
+	for _, partitions := range [][]types.PrefixStats{diff.NewPartitions, diff.RemovedPartitions} {
+		sort.Slice(partitions, func(i, j int) bool {
+			return partitions[i].Prefix < partitions[j].Prefix
+		})
+	}
+
+	return diff
+}
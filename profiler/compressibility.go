@@ -0,0 +1,206 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"math"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// compressibilitySampleBytes is the number of bytes read from the start
+// of each sampled object
+const compressibilitySampleBytes = 64 << 10
+
+// Ratio bounds of the compressibility classes: content that DEFLATE
+// shrinks by less than 10% is already compressed or encrypted, content it
+// halves compresses well
+const (
+	compressedRatio   = 0.9
+	compressibleRatio = 0.5
+)
+
+// CompressibilityAnalyzer reads the first bytes of a few objects per
+// top-level prefix and measures their entropy and how well they compress
+type CompressibilityAnalyzer struct {
+	s3Client  *s3.Client
+	perPrefix int
+	workers   int
+	prices    storagePrices
+}
+
+// NewCompressibilityAnalyzer creates an analyzer sampling up to perPrefix
+// objects of every top-level prefix with up to workers concurrent reads
+func NewCompressibilityAnalyzer(s3Client *s3.Client, perPrefix, workers int) *CompressibilityAnalyzer {
+	return &CompressibilityAnalyzer{
+		s3Client:  s3Client,
+		perPrefix: perPrefix,
+		workers:   max(workers, 1),
+	}
+}
+
+// Enabled reports whether compressibility scoring was requested
+func (ca *CompressibilityAnalyzer) Enabled() bool {
+	return ca.perPrefix > 0
+}
+
+// compressibilitySample is the measurement of one sampled object
+type compressibilitySample struct {
+	prefix     string
+	read       int64
+	compressed int64
+	entropy    float64
+	failed     bool
+}
+
+// AnalyzeCompressibility samples objects spread evenly across each
+// top-level prefix. The entropy and DEFLATE ratio of the sampled bytes,
+// weighted by bytes read, stand for the whole prefix; the projected
+// savings assume the prefix's objects would compress at that ratio.
+// Failed reads are counted but do not fail the analysis.
+func (ca *CompressibilityAnalyzer) AnalyzeCompressibility(ctx context.Context, bucketName string, objects []types.ObjectMetadata) (*types.CompressibilityAnalysis, error) {
+	type prefixTotals struct {
+		objects []types.ObjectMetadata
+		count   int64
+		size    int64
+		cost    float64
+	}
+	prefixes := make(map[string]*prefixTotals)
+	for _, obj := range objects {
+		prefix := topLevelPrefix(obj.Key)
+		totals, exists := prefixes[prefix]
+		if !exists {
+			totals = &prefixTotals{}
+			prefixes[prefix] = totals
+		}
+		totals.count++
+		totals.size += obj.Size
+		totals.cost += ca.prices.cost(obj.StorageClass, obj.Size)
+		if obj.Size > 0 {
+			totals.objects = append(totals.objects, obj)
+		}
+	}
+
+	var jobs []types.ObjectMetadata
+	for _, totals := range prefixes {
+		n := min(ca.perPrefix, len(totals.objects))
+		for i := 0; i < n; i++ {
+			jobs = append(jobs, totals.objects[i*len(totals.objects)/n])
+		}
+	}
+
+	samples := make([]compressibilitySample, len(jobs))
+	err := runParallel(ctx, ca.workers, len(jobs), func(ctx context.Context, i int) error {
+		obj := jobs[i]
+		samples[i].prefix = topLevelPrefix(obj.Key)
+		reader := &s3RangeReader{client: ca.s3Client, bucketName: bucketName, key: obj.Key}
+		data, err := reader.ReadRange(ctx, 0, compressibilitySampleBytes)
+		if err != nil || len(data) == 0 {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			samples[i].failed = true
+			return nil
+		}
+		samples[i].read = int64(len(data))
+		samples[i].compressed = deflatedSize(data)
+		samples[i].entropy = shannonEntropy(data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type prefixSamples struct {
+		sampled    int
+		read       int64
+		compressed int64
+		entropy    float64
+	}
+	measured := make(map[string]*prefixSamples)
+	analysis := &types.CompressibilityAnalysis{SampleBytes: compressibilitySampleBytes}
+	for _, sample := range samples {
+		if sample.failed {
+			analysis.Failed++
+			continue
+		}
+		analysis.Sampled++
+		m, exists := measured[sample.prefix]
+		if !exists {
+			m = &prefixSamples{}
+			measured[sample.prefix] = m
+		}
+		m.sampled++
+		m.read += sample.read
+		m.compressed += sample.compressed
+		m.entropy += sample.entropy * float64(sample.read)
+	}
+
+	for prefix, m := range measured {
+		totals := prefixes[prefix]
+		result := types.PrefixCompressibility{
+			Prefix:      prefix,
+			ObjectCount: totals.count,
+			Size:        totals.size,
+			Sampled:     m.sampled,
+			Entropy:     m.entropy / float64(m.read),
+			Ratio:       min(float64(m.compressed)/float64(m.read), 1),
+		}
+		switch {
+		case result.Ratio >= compressedRatio:
+			result.Class = types.CompressedContent
+		case result.Ratio <= compressibleRatio:
+			result.Class = types.CompressibleContent
+		default:
+			result.Class = types.PartlyCompressible
+		}
+		if result.Class != types.CompressedContent {
+			result.SavedBytes = int64(float64(totals.size) * (1 - result.Ratio))
+			result.Savings = totals.cost * (1 - result.Ratio)
+			analysis.Savings += result.Savings
+		}
+		analysis.Prefixes = append(analysis.Prefixes, result)
+	}
+	sort.Slice(analysis.Prefixes, func(i, j int) bool {
+		a, b := analysis.Prefixes[i], analysis.Prefixes[j]
+		if a.Savings != b.Savings {
+			return a.Savings > b.Savings
+		}
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Prefix < b.Prefix
+	})
+
+	return analysis, nil
+}
+
+// shannonEntropy returns the entropy of data in bits per byte
+func shannonEntropy(data []byte) float64 {
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	entropy := 0.0
+	n := float64(len(data))
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// deflatedSize returns the size of data compressed with DEFLATE at the
+// default level, roughly what gzip produces
+func deflatedSize(data []byte) int64 {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	w.Write(data)
+	w.Close()
+	return int64(buf.Len())
+}
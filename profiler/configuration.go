@@ -0,0 +1,425 @@
+package profiler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// inventoryDailyThreshold is the object count above which a daily inventory
+// is recommended instead of a weekly one
+const inventoryDailyThreshold = 1000000
+
+// AccessFindingsFunc returns IAM Access Analyzer findings for a bucket
+type AccessFindingsFunc func(ctx context.Context, bucketName, region string) ([]types.AccessFinding, error)
+
+// ConfigAnalyzer audits bucket-level configuration
+type ConfigAnalyzer struct {
+	s3Client            *s3.Client
+	expectNotifications []string
+	accessFindings      AccessFindingsFunc
+	storageLens         StorageLensFunc
+}
+
+// NewConfigAnalyzer creates a new configuration analyzer. expectNotifications
+// lists prefixes that downstream consumers treat as event-driven.
+func NewConfigAnalyzer(s3Client *s3.Client, expectNotifications []string) *ConfigAnalyzer {
+	return &ConfigAnalyzer{
+		s3Client:            s3Client,
+		expectNotifications: expectNotifications,
+	}
+}
+
+// AnalyzeConfiguration audits the configuration of a bucket. Individual
+// checks that fail (typically because of missing permissions) are recorded
+// in the result instead of failing the whole audit.
+func (ca *ConfigAnalyzer) AnalyzeConfiguration(ctx context.Context, summary *types.BucketSummary, objects []types.ObjectMetadata) *types.BucketConfiguration {
+	config := &types.BucketConfiguration{}
+
+	inventories, err := listInventories(ctx, ca.s3Client, summary.Name)
+	if err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("inventory: %v", err))
+	} else {
+		config.Inventories = inventories
+		if !hasEnabledInventory(inventories) {
+			config.InventoryRecommendation = ca.recommendInventory(summary)
+		}
+	}
+
+	if err := ca.analyzeNotifications(ctx, summary.Name, config); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("notifications: %v", err))
+	} else {
+		config.NotificationCoverage = ca.notificationCoverage(config, objects)
+	}
+
+	if err := ca.analyzeWebsite(ctx, summary.Name, config, objects); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("website: %v", err))
+	}
+
+	if err := ca.analyzeCORS(ctx, summary.Name, config); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("cors: %v", err))
+	}
+
+	if err := ca.analyzeTags(ctx, summary.Name, config); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("tags: %v", err))
+	}
+
+	if err := ca.analyzePublicAccess(ctx, summary.Name, config); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("public access: %v", err))
+	}
+
+	if err := ca.analyzeEncryption(ctx, summary.Name, config); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("encryption: %v", err))
+	}
+
+	if err := ca.analyzeLifecycle(ctx, summary.Name, config); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("lifecycle: %v", err))
+	}
+
+	ca.analyzeChargeableFeatures(ctx, summary, config, objects)
+
+	if ca.accessFindings != nil {
+		if err := ca.analyzeExternalAccess(ctx, summary, config); err != nil {
+			config.Errors = append(config.Errors, fmt.Sprintf("access analyzer: %v", err))
+		}
+	}
+
+	return config
+}
+
+// analyzeWebsite records static website hosting and flags buckets that
+// serve a website without holding any web content
+func (ca *ConfigAnalyzer) analyzeWebsite(ctx context.Context, bucketName string, config *types.BucketConfiguration, objects []types.ObjectMetadata) error {
+	result, err := ca.s3Client.GetBucketWebsite(ctx, &s3.GetBucketWebsiteInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if isErrorCode(err, "NoSuchWebsiteConfiguration") {
+			return nil
+		}
+		return err
+	}
+
+	website := &types.WebsiteConfig{}
+	if result.IndexDocument != nil {
+		website.IndexDocument = aws.ToString(result.IndexDocument.Suffix)
+	}
+	if result.ErrorDocument != nil {
+		website.ErrorDocument = aws.ToString(result.ErrorDocument.Key)
+	}
+	if r := result.RedirectAllRequestsTo; r != nil {
+		website.RedirectAllTo = formatRedirectTarget(string(r.Protocol), aws.ToString(r.HostName))
+	}
+	for _, rule := range result.RoutingRules {
+		website.RoutingRules = append(website.RoutingRules, formatRoutingRule(rule))
+	}
+	config.Website = website
+
+	if website.RedirectAllTo == "" && !hasWebContent(objects) {
+		config.Warnings = append(config.Warnings,
+			"Static website hosting is enabled but no HTML objects were found; the bucket may be serving as a website unintentionally")
+	} else {
+		config.Warnings = append(config.Warnings,
+			"Static website hosting is enabled; objects readable through the website endpoint are served over plain HTTP")
+	}
+
+	return nil
+}
+
+// analyzeCORS records CORS rules and flags wildcard origins
+func (ca *ConfigAnalyzer) analyzeCORS(ctx context.Context, bucketName string, config *types.BucketConfiguration) error {
+	result, err := ca.s3Client.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if isErrorCode(err, "NoSuchCORSConfiguration") {
+			return nil
+		}
+		return err
+	}
+
+	for _, r := range result.CORSRules {
+		rule := types.CORSRule{
+			ID:             aws.ToString(r.ID),
+			AllowedOrigins: r.AllowedOrigins,
+			AllowedMethods: r.AllowedMethods,
+			AllowedHeaders: r.AllowedHeaders,
+			MaxAgeSeconds:  aws.ToInt32(r.MaxAgeSeconds),
+		}
+		config.CORSRules = append(config.CORSRules, rule)
+
+		for _, origin := range rule.AllowedOrigins {
+			if strings.Contains(origin, "*") {
+				config.Warnings = append(config.Warnings, fmt.Sprintf(
+					"CORS rule %q allows wildcard origin %q for methods %s",
+					rule.ID, origin, strings.Join(rule.AllowedMethods, ",")))
+			}
+		}
+	}
+
+	return nil
+}
+
+// analyzeExternalAccess records Access Analyzer findings for the bucket
+func (ca *ConfigAnalyzer) analyzeExternalAccess(ctx context.Context, summary *types.BucketSummary, config *types.BucketConfiguration) error {
+	findings, err := ca.accessFindings(ctx, summary.Name, summary.Region)
+	if err != nil {
+		return err
+	}
+
+	// Access Analyzer pages findings in no particular order
+	sort.Slice(findings, func(i, j int) bool { return findings[i].ID < findings[j].ID })
+	config.AccessAnalyzerChecked = true
+	config.AccessFindings = findings
+
+	for _, f := range findings {
+		if f.IsPublic {
+			config.Warnings = append(config.Warnings, fmt.Sprintf(
+				"Access Analyzer finding %s: bucket is PUBLICLY accessible (%s)", f.ID, strings.Join(f.Actions, ",")))
+		} else {
+			config.Warnings = append(config.Warnings, fmt.Sprintf(
+				"Access Analyzer finding %s: externally accessible to %s", f.ID, f.Principal))
+		}
+	}
+
+	return nil
+}
+
+// formatRedirectTarget renders a redirect host with its optional protocol
+func formatRedirectTarget(protocol, host string) string {
+	if protocol == "" {
+		return host
+	}
+	return protocol + "://" + host
+}
+
+// formatRoutingRule renders a website routing rule as "condition -> redirect"
+func formatRoutingRule(rule s3types.RoutingRule) string {
+	condition := "always"
+	if c := rule.Condition; c != nil {
+		var parts []string
+		if c.KeyPrefixEquals != nil {
+			parts = append(parts, "prefix="+aws.ToString(c.KeyPrefixEquals))
+		}
+		if c.HttpErrorCodeReturnedEquals != nil {
+			parts = append(parts, "error="+aws.ToString(c.HttpErrorCodeReturnedEquals))
+		}
+		if len(parts) > 0 {
+			condition = strings.Join(parts, " ")
+		}
+	}
+
+	target := "(none)"
+	if r := rule.Redirect; r != nil {
+		target = formatRedirectTarget(string(r.Protocol), aws.ToString(r.HostName))
+		if r.ReplaceKeyPrefixWith != nil {
+			target += " prefix->" + aws.ToString(r.ReplaceKeyPrefixWith)
+		}
+		if r.ReplaceKeyWith != nil {
+			target += " key->" + aws.ToString(r.ReplaceKeyWith)
+		}
+		if r.HttpRedirectCode != nil {
+			target += " (" + aws.ToString(r.HttpRedirectCode) + ")"
+		}
+	}
+
+	return condition + " -> " + strings.TrimSpace(target)
+}
+
+// hasWebContent reports whether any listed object looks like a web page
+func hasWebContent(objects []types.ObjectMetadata) bool {
+	for _, obj := range objects {
+		key := strings.ToLower(obj.Key)
+		if strings.HasSuffix(key, ".html") || strings.HasSuffix(key, ".htm") {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorCode reports whether err is an AWS API error with the given code
+func isErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+// analyzeNotifications collects the event notification targets of a bucket
+func (ca *ConfigAnalyzer) analyzeNotifications(ctx context.Context, bucketName string, config *types.BucketConfiguration) error {
+	result, err := ca.s3Client.GetBucketNotificationConfiguration(ctx, &s3.GetBucketNotificationConfigurationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return err
+	}
+
+	config.EventBridgeEnabled = result.EventBridgeConfiguration != nil
+
+	for _, t := range result.TopicConfigurations {
+		config.Notifications = append(config.Notifications,
+			newNotificationConfig(t.Id, "SNS", t.TopicArn, t.Events, t.Filter))
+	}
+	for _, q := range result.QueueConfigurations {
+		config.Notifications = append(config.Notifications,
+			newNotificationConfig(q.Id, "SQS", q.QueueArn, q.Events, q.Filter))
+	}
+	for _, l := range result.LambdaFunctionConfigurations {
+		config.Notifications = append(config.Notifications,
+			newNotificationConfig(l.Id, "Lambda", l.LambdaFunctionArn, l.Events, l.Filter))
+	}
+
+	return nil
+}
+
+// newNotificationConfig converts an SDK notification target
+func newNotificationConfig(id *string, kind string, arn *string, events []s3types.Event, filter *s3types.NotificationConfigurationFilter) types.NotificationConfig {
+	n := types.NotificationConfig{
+		ID:          aws.ToString(id),
+		Type:        kind,
+		Destination: aws.ToString(arn),
+	}
+	for _, e := range events {
+		n.Events = append(n.Events, string(e))
+	}
+	if filter != nil && filter.Key != nil {
+		for _, rule := range filter.Key.FilterRules {
+			switch strings.ToLower(string(rule.Name)) {
+			case "prefix":
+				n.Prefix = aws.ToString(rule.Value)
+			case "suffix":
+				n.Suffix = aws.ToString(rule.Value)
+			}
+		}
+	}
+	return n
+}
+
+// notificationCoverage maps each dataset prefix (top-level prefixes seen in
+// the listing plus the expected prefixes) to the targets that receive its
+// events
+func (ca *ConfigAnalyzer) notificationCoverage(config *types.BucketConfiguration, objects []types.ObjectMetadata) []types.PrefixCoverage {
+	expected := make(map[string]bool)
+	for _, p := range ca.expectNotifications {
+		expected[p] = true
+	}
+
+	prefixes := make(map[string]bool)
+	for p := range expected {
+		prefixes[p] = true
+	}
+	for _, obj := range objects {
+		prefixes[topLevelPrefix(obj.Key)] = true
+	}
+
+	var coverage []types.PrefixCoverage
+	for prefix := range prefixes {
+		c := types.PrefixCoverage{Prefix: prefix, Expected: expected[prefix]}
+		if config.EventBridgeEnabled {
+			c.Destinations = append(c.Destinations, "EventBridge")
+		}
+		for _, n := range config.Notifications {
+			if notificationMatches(n.Prefix, prefix) {
+				c.Destinations = append(c.Destinations, n.Type+" "+n.Destination)
+			}
+		}
+		coverage = append(coverage, c)
+	}
+
+	sort.Slice(coverage, func(i, j int) bool {
+		return coverage[i].Prefix < coverage[j].Prefix
+	})
+
+	return coverage
+}
+
+// notificationMatches reports whether a notification prefix filter covers at
+// least part of a dataset prefix
+func notificationMatches(filterPrefix, datasetPrefix string) bool {
+	if datasetPrefix == "/" {
+		return filterPrefix == ""
+	}
+	return strings.HasPrefix(datasetPrefix, filterPrefix) || strings.HasPrefix(filterPrefix, datasetPrefix)
+}
+
+// listInventories returns all S3 Inventory configurations of a bucket
+func listInventories(ctx context.Context, s3Client *s3.Client, bucketName string) ([]types.InventoryConfig, error) {
+	var inventories []types.InventoryConfig
+	var continuationToken *string
+
+	for {
+		result, err := s3Client.ListBucketInventoryConfigurations(ctx, &s3.ListBucketInventoryConfigurationsInput{
+			Bucket:            aws.String(bucketName),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, inv := range result.InventoryConfigurationList {
+			config := types.InventoryConfig{
+				ID:               aws.ToString(inv.Id),
+				Enabled:          aws.ToBool(inv.IsEnabled),
+				IncludedVersions: string(inv.IncludedObjectVersions),
+			}
+			if inv.Filter != nil {
+				config.Prefix = aws.ToString(inv.Filter.Prefix)
+			}
+			if inv.Schedule != nil {
+				config.Frequency = string(inv.Schedule.Frequency)
+			}
+			if inv.Destination != nil && inv.Destination.S3BucketDestination != nil {
+				dest := inv.Destination.S3BucketDestination
+				config.Destination = aws.ToString(dest.Bucket) + "/" + aws.ToString(dest.Prefix)
+				config.Format = string(dest.Format)
+				// Destinations are bucket ARNs, in whatever partition
+				if loc, err := types.ParseLocation(aws.ToString(dest.Bucket)); err == nil {
+					config.DestinationBucket = loc.Bucket
+				}
+				config.DestinationPrefix = aws.ToString(dest.Prefix)
+			}
+			for _, field := range inv.OptionalFields {
+				config.OptionalFields = append(config.OptionalFields, string(field))
+			}
+			inventories = append(inventories, config)
+		}
+
+		if !aws.ToBool(result.IsTruncated) {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+
+	return inventories, nil
+}
+
+// recommendInventory suggests an inventory configuration sized for the bucket
+func (ca *ConfigAnalyzer) recommendInventory(summary *types.BucketSummary) *types.InventoryRecommendation {
+	rec := &types.InventoryRecommendation{
+		Frequency:      "Weekly",
+		Format:         "Parquet",
+		OptionalFields: []string{"Size", "LastModifiedDate", "StorageClass", "ETag", "IntelligentTieringAccessTier"},
+		Reason:         "No S3 Inventory is configured; an inventory lets future runs avoid paginating ListObjectsV2",
+	}
+	if summary.TotalObjects >= inventoryDailyThreshold {
+		rec.Frequency = "Daily"
+		rec.Reason = fmt.Sprintf("No S3 Inventory is configured and the bucket holds %d+ objects; listing it is slow and costly", inventoryDailyThreshold)
+	}
+	return rec
+}
+
+// hasEnabledInventory reports whether any inventory configuration is enabled
+func hasEnabledInventory(inventories []types.InventoryConfig) bool {
+	for _, inv := range inventories {
+		if inv.Enabled {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package profiler
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/sampler"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+func init() {
+	sampler.Register(parquetSampler{}, []string{".parquet"}, []string{"application/vnd.apache.parquet"})
+}
+
+// parquetSampler reports the row count and schema from a Parquet footer
+type parquetSampler struct{}
+
+func (parquetSampler) Format() string { return "parquet" }
+
+func (parquetSampler) Sample(ctx context.Context, obj sampler.Object) (*sampler.Result, error) {
+	footer, err := readParquetFooter(ctx, obj.Reader, obj.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(footer.columns))
+	for _, c := range footer.columns {
+		columns = append(columns, c.path+" "+parquetPhysicalNames[c.physical])
+	}
+
+	return &sampler.Result{
+		Format: "parquet",
+		Attributes: map[string]string{
+			"rows":       strconv.FormatInt(footer.numRows, 10),
+			"row_groups": strconv.Itoa(footer.rowGroups),
+			"columns":    strings.Join(columns, ", "),
+		},
+	}, nil
+}
+
+// ContentAnalyzer inspects the content of a few objects per format using
+// the samplers registered in a sampler.Registry
+type ContentAnalyzer struct {
+	s3Client  *s3.Client
+	registry  *sampler.Registry
+	perFormat int
+}
+
+// NewContentAnalyzer creates an analyzer that samples up to perFormat
+// objects of every format known to the registry
+func NewContentAnalyzer(s3Client *s3.Client, registry *sampler.Registry, perFormat int) *ContentAnalyzer {
+	return &ContentAnalyzer{
+		s3Client:  s3Client,
+		registry:  registry,
+		perFormat: perFormat,
+	}
+}
+
+// Enabled reports whether content sampling was requested
+func (ca *ContentAnalyzer) Enabled() bool {
+	return ca.perFormat > 0
+}
+
+// AnalyzeContent samples objects spread evenly across each format and
+// records what the samplers found. Sampler errors are kept per object.
+func (ca *ContentAnalyzer) AnalyzeContent(ctx context.Context, bucketName string, objects []types.ObjectMetadata) ([]types.ContentSample, error) {
+	byFormat := make(map[string][]types.ObjectMetadata)
+	samplers := make(map[string]sampler.Sampler)
+	for _, obj := range objects {
+		s := ca.registry.Lookup(obj.Key, "")
+		if s == nil {
+			continue
+		}
+		byFormat[s.Format()] = append(byFormat[s.Format()], obj)
+		samplers[s.Format()] = s
+	}
+
+	formats := make([]string, 0, len(byFormat))
+	for format := range byFormat {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	var samples []types.ContentSample
+	for _, format := range formats {
+		members := byFormat[format]
+		n := min(ca.perFormat, len(members))
+		for i := 0; i < n; i++ {
+			obj := members[i*len(members)/n]
+			sample := types.ContentSample{Key: obj.Key, Format: format}
+
+			result, err := samplers[format].Sample(ctx, sampler.Object{
+				Key:    obj.Key,
+				Size:   obj.Size,
+				Reader: &s3RangeReader{client: ca.s3Client, bucketName: bucketName, key: obj.Key},
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				sample.Error = err.Error()
+			} else {
+				sample.Format = result.Format
+				sample.Attributes = result.Attributes
+			}
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,112 @@
+package profiler
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// ecbRatesURL serves the euro foreign exchange reference rates of the
+// European Central Bank, published every working day around 16:00 CET
+const ecbRatesURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ecbRates is the reference rates document; rates are units per euro
+type ecbRates struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ExchangeRates returns the rate per US dollar of each currency. Supplied
+// rates are used as given; the others are fetched once from the ECB
+// reference rates.
+func ExchangeRates(ctx context.Context, codes []string, supplied map[string]float64) (map[string]types.ExchangeRate, error) {
+	rates := make(map[string]types.ExchangeRate, len(codes))
+	var missing []string
+	for _, code := range codes {
+		switch rate, ok := supplied[code]; {
+		case code == "USD":
+			rates[code] = types.ExchangeRate{Rate: 1, Source: "base currency"}
+		case ok:
+			rates[code] = types.ExchangeRate{Rate: rate, Source: "supplied rate"}
+		default:
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) == 0 {
+		return rates, nil
+	}
+
+	perEuro, date, err := fetchECBRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usd, ok := perEuro["USD"]
+	if !ok {
+		return nil, fmt.Errorf("ECB reference rates have no USD rate")
+	}
+	sort.Strings(missing)
+	for _, code := range missing {
+		rate, ok := perEuro[code]
+		if !ok {
+			return nil, fmt.Errorf("no ECB reference rate for %s; supply one with --exchange-rate %s=RATE", code, code)
+		}
+		rates[code] = types.ExchangeRate{Rate: rate / usd, Source: "ECB reference rate of " + date}
+	}
+	return rates, nil
+}
+
+// fetchECBRates fetches the latest ECB reference rates in units per euro,
+// with the date they were published for
+func fetchECBRates(ctx context.Context) (map[string]float64, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ecbRatesURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch exchange rates: %s", resp.Status)
+	}
+
+	var doc ecbRates
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, "", fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+	perEuro := map[string]float64{"EUR": 1}
+	for _, r := range doc.Cube.Cube.Rates {
+		if r.Rate > 0 {
+			perEuro[r.Currency] = r.Rate
+		}
+	}
+	return perEuro, doc.Cube.Cube.Time, nil
+}
+
+// NewCurrency returns the report currency for a code, nil for US dollars
+func NewCurrency(code string, rates map[string]types.ExchangeRate) (*output.Currency, error) {
+	if code == "" || code == "USD" {
+		return nil, nil
+	}
+	rate, ok := rates[code]
+	if !ok {
+		return nil, fmt.Errorf("no exchange rate for %s", code)
+	}
+	return &output.Currency{Code: code, Rate: rate.Rate, Source: rate.Source}, nil
+}
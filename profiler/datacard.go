@@ -0,0 +1,194 @@
+package profiler
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Data card settings
+const (
+	// dataCardSampleKeys is the number of example keys on each card
+	dataCardSampleKeys = 5
+	// majorityShare is the share of a dataset's bytes that decides its
+	// retention recommendation
+	majorityShare = 0.5
+)
+
+// schemaAttributes maps sampled formats to the content sample attribute
+// listing their columns
+var schemaAttributes = map[string]string{
+	"parquet": "columns",
+	"csv":     "header",
+	"avro":    "field_names",
+	"json":    "keys",
+}
+
+// buildDataCards describes each dataset: every top-level prefix with a date
+// pattern, or the whole bucket when no prefix has one
+func (p *Profiler) buildDataCards(summary *types.BucketSummary, objects []types.ObjectMetadata, analysis *types.PartitionAnalysis, metadata *types.MetadataSummary, parquetStats []types.ParquetStats) []types.DataCard {
+	now := time.Now().UTC()
+
+	cards := make(map[string]*types.DataCard)
+	var scopes []string
+	for _, pattern := range analysis.Patterns {
+		if _, ok := cards[pattern.Scope]; !ok {
+			cards[pattern.Scope] = newDataCard(summary, pattern.Scope, now)
+			scopes = append(scopes, pattern.Scope)
+		}
+		cards[pattern.Scope].Patterns = append(cards[pattern.Scope].Patterns, pattern)
+	}
+	wholeBucket := len(scopes) == 0
+	if wholeBucket {
+		cards[""] = newDataCard(summary, "", now)
+		scopes = append(scopes, "")
+	}
+	sort.Strings(scopes)
+
+	members := make(map[string][]types.ObjectMetadata)
+	for _, obj := range objects {
+		scope := subtreeScope(obj.Key)
+		if wholeBucket {
+			scope = ""
+		}
+		card, ok := cards[scope]
+		if !ok {
+			continue
+		}
+		members[scope] = append(members[scope], obj)
+
+		card.ObjectCount++
+		card.TotalSize += obj.Size
+		stats := card.StorageClasses[obj.StorageClass]
+		stats.Count++
+		stats.Size += obj.Size
+		card.StorageClasses[obj.StorageClass] = stats
+		card.Formats[p.metadataAnalyzer.getFileExtension(obj.Key)]++
+
+		if card.Oldest.IsZero() || obj.LastModified.Before(card.Oldest) {
+			card.Oldest = obj.LastModified
+		}
+		if obj.LastModified.After(card.Newest) {
+			card.Newest = obj.LastModified
+		}
+		age := now.Sub(obj.LastModified)
+		if age > 90*24*time.Hour {
+			card.SizeOver90Days += obj.Size
+		}
+		if age > 365*24*time.Hour {
+			card.SizeOver1Year += obj.Size
+		}
+	}
+
+	for _, partition := range analysis.Partitions {
+		card, ok := cards[partition.Scope]
+		if !ok || partition.Date.IsZero() {
+			continue
+		}
+		card.Partitions++
+		if card.FirstPartition.IsZero() || partition.Date.Before(card.FirstPartition) {
+			card.FirstPartition = partition.Date
+		}
+		if partition.Date.After(card.LastPartition) {
+			card.LastPartition = partition.Date
+		}
+	}
+
+	result := make([]types.DataCard, 0, len(scopes))
+	for _, scope := range scopes {
+		card := cards[scope]
+		card.Retention = recommendRetention(card)
+		card.Schema = datasetSchema(scope, wholeBucket, metadata.ContentSamples, parquetStats)
+		card.SampleKeys = spreadKeys(members[scope], dataCardSampleKeys)
+		result = append(result, *card)
+	}
+	return result
+}
+
+// newDataCard creates an empty card for a dataset
+func newDataCard(summary *types.BucketSummary, scope string, now time.Time) *types.DataCard {
+	return &types.DataCard{
+		Bucket:         summary.Name,
+		Region:         summary.Region,
+		Scope:          scope,
+		Generated:      now,
+		StorageClasses: make(map[string]types.StorageClassStats),
+		Formats:        make(map[string]int64),
+	}
+}
+
+// recommendRetention suggests a lifecycle policy from the age of the bytes
+// in a dataset
+func recommendRetention(card *types.DataCard) string {
+	if card.TotalSize == 0 {
+		return "Dataset holds no data; no retention policy needed"
+	}
+
+	switch {
+	case float64(card.SizeOver1Year) >= float64(card.TotalSize)*majorityShare:
+		return "Most data is over a year old: expire it if it is no longer needed, or transition it to GLACIER or DEEP_ARCHIVE after 365 days"
+	case float64(card.SizeOver90Days) >= float64(card.TotalSize)*majorityShare:
+		return "Most data is over 90 days old: transition it to STANDARD_IA (or use INTELLIGENT_TIERING) after 90 days"
+	default:
+		return "Most data was written in the last 90 days: keep it in STANDARD and review retention as it ages"
+	}
+}
+
+// datasetSchema returns the columns of a dataset from Parquet footer
+// statistics, falling back to the first successful content sample
+func datasetSchema(scope string, wholeBucket bool, samples []types.ContentSample, parquetStats []types.ParquetStats) *types.DatasetSchema {
+	inScope := func(key string) bool {
+		return wholeBucket || subtreeScope(key) == scope
+	}
+
+	for _, stats := range parquetStats {
+		if !inScope(stats.Partition) || len(stats.Columns) == 0 {
+			continue
+		}
+		schema := &types.DatasetSchema{Format: "parquet", Source: stats.Partition}
+		for _, column := range stats.Columns {
+			schema.Columns = append(schema.Columns, types.SchemaColumn{Name: column.Name, Type: column.Type})
+		}
+		return schema
+	}
+
+	for _, sample := range samples {
+		attribute, ok := schemaAttributes[sample.Format]
+		if !ok || sample.Error != "" || sample.Attributes[attribute] == "" || !inScope(sample.Key) {
+			continue
+		}
+		schema := &types.DatasetSchema{Format: sample.Format, Source: sample.Key}
+		for _, field := range strings.Split(sample.Attributes[attribute], ", ") {
+			column := types.SchemaColumn{Name: field}
+			// Parquet samples list columns as "path TYPE"
+			if sample.Format == "parquet" {
+				if i := strings.LastIndexByte(field, ' '); i > 0 {
+					column = types.SchemaColumn{Name: field[:i], Type: field[i+1:]}
+				}
+			}
+			schema.Columns = append(schema.Columns, column)
+		}
+		return schema
+	}
+
+	return nil
+}
+
+// spreadKeys picks up to n keys spread evenly through the listing
+func spreadKeys(objects []types.ObjectMetadata, n int) []string {
+	if len(objects) <= n {
+		keys := make([]string, len(objects))
+		for i, obj := range objects {
+			keys[i] = obj.Key
+		}
+		return keys
+	}
+
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = objects[i*(len(objects)-1)/(n-1)].Key
+	}
+	return keys
+}
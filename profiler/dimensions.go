@@ -0,0 +1,114 @@
+package profiler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// dimension is a named value extracted from object keys
+type dimension struct {
+	name  string
+	regex *regexp.Regexp
+	group int
+}
+
+// DimensionAnalyzer aggregates objects by user-defined key dimensions
+type DimensionAnalyzer struct {
+	dimensions []dimension
+	now        func() time.Time
+	prices     storagePrices
+}
+
+// NewDimensionAnalyzer creates a dimension analyzer from "name=regex"
+// definitions. The value is taken from the capture group named after the
+// dimension if present, otherwise from the first capture group.
+func NewDimensionAnalyzer(definitions []string) (*DimensionAnalyzer, error) {
+	da := &DimensionAnalyzer{now: time.Now}
+
+	for _, def := range definitions {
+		name, expr, ok := strings.Cut(def, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || expr == "" {
+			return nil, fmt.Errorf("invalid dimension %q (expected name=regex)", def)
+		}
+
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for dimension %s: %w", name, err)
+		}
+		if regex.NumSubexp() == 0 {
+			return nil, fmt.Errorf("regex for dimension %s needs a capture group", name)
+		}
+
+		group := 1
+		if i := regex.SubexpIndex(name); i > 0 {
+			group = i
+		}
+
+		da.dimensions = append(da.dimensions, dimension{name: name, regex: regex, group: group})
+	}
+
+	return da, nil
+}
+
+// Enabled reports whether any dimensions are configured
+func (da *DimensionAnalyzer) Enabled() bool {
+	return len(da.dimensions) > 0
+}
+
+// AnalyzeDimensions aggregates size, cost and age per dimension value
+func (da *DimensionAnalyzer) AnalyzeDimensions(objects []types.ObjectMetadata) []types.DimensionTable {
+	now := da.now()
+	var tables []types.DimensionTable
+
+	for _, dim := range da.dimensions {
+		table := types.DimensionTable{Name: dim.name}
+		stats := make(map[string]*types.DimensionStats)
+		ageSum := make(map[string]float64)
+
+		for _, obj := range objects {
+			matches := dim.regex.FindStringSubmatch(obj.Key)
+			if matches == nil || matches[dim.group] == "" {
+				table.Unmatched++
+				continue
+			}
+
+			value := matches[dim.group]
+			s, exists := stats[value]
+			if !exists {
+				s = &types.DimensionStats{Value: value, Oldest: obj.LastModified, Newest: obj.LastModified}
+				stats[value] = s
+			}
+			s.ObjectCount++
+			s.TotalSize += obj.Size
+			s.MonthlyCost += da.prices.cost(obj.StorageClass, obj.Size)
+			if obj.LastModified.Before(s.Oldest) {
+				s.Oldest = obj.LastModified
+			}
+			if obj.LastModified.After(s.Newest) {
+				s.Newest = obj.LastModified
+			}
+			ageSum[value] += now.Sub(obj.LastModified).Hours() / 24
+		}
+
+		for value, s := range stats {
+			s.AverageAgeDays = ageSum[value] / float64(s.ObjectCount)
+			table.Rows = append(table.Rows, *s)
+		}
+		sort.Slice(table.Rows, func(i, j int) bool {
+			if table.Rows[i].TotalSize != table.Rows[j].TotalSize {
+				return table.Rows[i].TotalSize > table.Rows[j].TotalSize
+			}
+			return table.Rows[i].Value < table.Rows[j].Value
+		})
+
+		tables = append(tables, table)
+	}
+
+	return tables
+}
@@ -0,0 +1,97 @@
+package profiler
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// encryptedSuffixes are file extensions of content encrypted before upload
+var encryptedSuffixes = map[string]bool{
+	".gpg":   true,
+	".pgp":   true,
+	".age":   true,
+	".enc":   true,
+	".aes":   true,
+	".crypt": true,
+	".p7m":   true,
+}
+
+// clientSideMetadataKeys are the user metadata keys the S3 Encryption
+// Client (and EMRFS client-side encryption) stores the wrapped data key
+// and cipher under
+var clientSideMetadataKeys = []string{
+	"x-amz-key",
+	"x-amz-key-v2",
+	"x-amz-cek-alg",
+	"x-amz-wrap-alg",
+	"x-amz-matdesc",
+}
+
+// hasEncryptedSuffix reports whether a key is named like an encrypted file
+func hasEncryptedSuffix(key string) bool {
+	return encryptedSuffixes[strings.ToLower(path.Ext(key))]
+}
+
+// isClientSideEncrypted reports whether user metadata carries the
+// envelope of a client-side encryption library
+func isClientSideEncrypted(metadata map[string]string) bool {
+	for _, key := range clientSideMetadataKeys {
+		if _, ok := metadata[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectEncryptedContent finds the prefixes (as recorded in snapshots)
+// holding objects generic consumers cannot read: objects named like
+// encrypted files, and, from the HeadObject sample when there is one,
+// SSE-C and client-side encrypted objects. Prefixes are ordered by the
+// estimated unreadable objects.
+func DetectEncryptedContent(objects []types.ObjectMetadata, enrichment *types.EnrichmentSummary) []types.EncryptedPrefix {
+	prefixes := make(map[string]*types.EncryptedPrefix)
+	for _, obj := range objects {
+		prefix := snapshotPrefix(obj.Key)
+		p, exists := prefixes[prefix]
+		if !exists {
+			p = &types.EncryptedPrefix{Prefix: prefix}
+			prefixes[prefix] = p
+		}
+		p.ObjectCount++
+		p.Size += obj.Size
+		if hasEncryptedSuffix(obj.Key) {
+			p.Suffix++
+		}
+	}
+	if enrichment != nil {
+		for prefix, estimate := range enrichment.SSECustomerPrefixes {
+			if p, ok := prefixes[prefix]; ok {
+				p.SSECustomer = estimate
+			}
+		}
+		for prefix, estimate := range enrichment.ClientSidePrefixes {
+			if p, ok := prefixes[prefix]; ok {
+				p.ClientSide = estimate
+			}
+		}
+	}
+
+	var encrypted []types.EncryptedPrefix
+	for _, p := range prefixes {
+		// The markers can overlap, so the larger count is a lower bound
+		p.Unreadable = min(max(p.Suffix, int64(p.SSECustomer+p.ClientSide+0.5)), p.ObjectCount)
+		if p.Unreadable > 0 {
+			encrypted = append(encrypted, *p)
+		}
+	}
+	sort.Slice(encrypted, func(i, j int) bool {
+		if encrypted[i].Unreadable != encrypted[j].Unreadable {
+			return encrypted[i].Unreadable > encrypted[j].Unreadable
+		}
+		return encrypted[i].Prefix < encrypted[j].Prefix
+	})
+	return encrypted
+}
@@ -0,0 +1,197 @@
+package profiler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// defaultEnrichWorkers is the HeadObject concurrency when none is configured
+const defaultEnrichWorkers = 8
+
+// EnrichmentAnalyzer samples objects per prefix and aggregates attributes
+// that are only available from HeadObject
+type EnrichmentAnalyzer struct {
+	s3Client  *s3.Client
+	perPrefix int
+	workers   int
+	// adaptive tunes the concurrency up to workers
+	adaptive bool
+}
+
+// NewEnrichmentAnalyzer creates an analyzer that enriches up to perPrefix
+// objects in every prefix using at most workers concurrent requests
+func NewEnrichmentAnalyzer(s3Client *s3.Client, perPrefix, workers int) *EnrichmentAnalyzer {
+	if workers <= 0 {
+		workers = defaultEnrichWorkers
+	}
+	return &EnrichmentAnalyzer{
+		s3Client:  s3Client,
+		perPrefix: perPrefix,
+		workers:   workers,
+	}
+}
+
+// Enabled reports whether enrichment was requested
+func (ea *EnrichmentAnalyzer) Enabled() bool {
+	return ea.perPrefix > 0
+}
+
+// enrichJob is one sampled object, its prefix and the number of objects
+// it represents
+type enrichJob struct {
+	object types.ObjectMetadata
+	prefix string
+	weight float64
+}
+
+// AnalyzeEnrichment calls HeadObject for a stratified sample of the objects.
+// Failed requests are counted rather than failing the analysis. Unless the
+// concurrency is fixed, fewer than workers requests run while S3 throttles.
+func (ea *EnrichmentAnalyzer) AnalyzeEnrichment(ctx context.Context, bucketName string, objects []types.ObjectMetadata) (*types.EnrichmentSummary, error) {
+	jobs, strata := samplePerPrefix(objects, ea.perPrefix)
+
+	summary := &types.EnrichmentSummary{
+		Strata:            strata,
+		ContentTypes:      make(map[string]float64),
+		ContentEncodings:  make(map[string]float64),
+		Encryption:        make(map[string]float64),
+		ReplicationStatus: make(map[string]float64),
+		ObjectLock:        make(map[string]float64),
+		UserMetadataKeys:  make(map[string]float64),
+
+		UnencryptedPrefixes: make(map[string]float64),
+		SSECustomerPrefixes: make(map[string]float64),
+		ClientSidePrefixes:  make(map[string]float64),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	jobChan := make(chan enrichJob)
+	control := newConcurrencyController("HeadObject", ea.workers, ea.adaptive)
+
+	for i := 0; i < ea.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				if control.acquire(ctx) != nil {
+					continue
+				}
+				result, err := ea.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+					Bucket: aws.String(bucketName),
+					Key:    aws.String(job.object.Key),
+				}, control.options)
+				control.release()
+				control.addItems(1)
+
+				mu.Lock()
+				switch {
+				case isHTTPStatus(err, http.StatusBadRequest):
+					// HeadObject of an SSE-C object fails without its key
+					summary.Sampled++
+					summary.Encryption["SSE-C"] += job.weight
+					summary.SSECustomerPrefixes[job.prefix] += job.weight
+				case err != nil:
+					summary.Failed++
+				default:
+					summary.Sampled++
+					addEnrichment(summary, result, job)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		jobChan <- job
+	}
+	close(jobChan)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := control.stats()
+	summary.Performance = &stats
+	return summary, nil
+}
+
+// samplePerPrefix picks up to perPrefix objects spread evenly across each
+// prefix (as recorded in snapshots) instead of the first objects overall,
+// so that large prefixes cannot crowd out small ones. A negative perPrefix
+// picks every object.
+func samplePerPrefix(objects []types.ObjectMetadata, perPrefix int) ([]enrichJob, int) {
+	strata := make(map[string][]types.ObjectMetadata)
+	for _, obj := range objects {
+		prefix := snapshotPrefix(obj.Key)
+		strata[prefix] = append(strata[prefix], obj)
+	}
+
+	prefixes := make([]string, 0, len(strata))
+	for prefix := range strata {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	var jobs []enrichJob
+	for _, prefix := range prefixes {
+		stratum := strata[prefix]
+		n := perPrefix
+		if n < 0 || n > len(stratum) {
+			n = len(stratum)
+		}
+		weight := float64(len(stratum)) / float64(n)
+		for i := 0; i < n; i++ {
+			jobs = append(jobs, enrichJob{object: stratum[i*len(stratum)/n], prefix: prefix, weight: weight})
+		}
+	}
+
+	return jobs, len(prefixes)
+}
+
+// addEnrichment adds one HeadObject result to the summary
+func addEnrichment(summary *types.EnrichmentSummary, result *s3.HeadObjectOutput, job enrichJob) {
+	weight := job.weight
+	summary.ContentTypes[valueOrNone(aws.ToString(result.ContentType))] += weight
+	summary.ContentEncodings[valueOrNone(aws.ToString(result.ContentEncoding))] += weight
+
+	encryption := string(result.ServerSideEncryption)
+	if result.SSECustomerAlgorithm != nil {
+		encryption = "SSE-C"
+		summary.SSECustomerPrefixes[job.prefix] += weight
+	}
+	summary.Encryption[valueOrNone(encryption)] += weight
+	if encryption == "" {
+		summary.UnencryptedPrefixes[job.prefix] += weight
+	}
+	if isClientSideEncrypted(result.Metadata) {
+		summary.ClientSidePrefixes[job.prefix] += weight
+	}
+
+	summary.ReplicationStatus[valueOrNone(string(result.ReplicationStatus))] += weight
+	summary.ObjectLock[valueOrNone(string(result.ObjectLockMode))] += weight
+
+	for key := range result.Metadata {
+		summary.UserMetadataKeys[key] += weight
+	}
+}
+
+// valueOrNone labels empty attribute values
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
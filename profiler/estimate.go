@@ -0,0 +1,52 @@
+package profiler
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// ObjectCountFunc returns a bucket's total object count from CloudWatch
+// storage metrics and the day it was recorded
+type ObjectCountFunc func(ctx context.Context, bucketName, region string) (int64, time.Time, error)
+
+// estimateListing extrapolates bucket totals for a truncated listing, using
+// the CloudWatch object count as the denominator when it is available
+func (p *Profiler) estimateListing(ctx context.Context, summary *types.BucketSummary) *types.ListingEstimate {
+	estimate := &types.ListingEstimate{
+		Limit:         p.config.Limit,
+		ListedObjects: summary.TotalObjects,
+		ListedSize:    summary.TotalSize,
+		Interrupted:   summary.Interrupted,
+	}
+
+	// CloudWatch counts the whole bucket, not the keys in scope; an
+	// interrupted run makes no further requests
+	if p.objectCounts == nil || summary.Scope != nil || summary.Interrupted {
+		return estimate
+	}
+	count, date, err := p.objectCounts(ctx, summary.Name, summary.Region)
+	if err != nil {
+		p.progress.Warnf("could not get object count from CloudWatch: %v", err)
+		return estimate
+	}
+	if count <= 0 {
+		return estimate
+	}
+
+	// Metrics lag by up to a day; never extrapolate below what was listed
+	if count < summary.TotalObjects {
+		count = summary.TotalObjects
+	}
+
+	estimate.BucketObjects = count
+	estimate.MetricsDate = date
+	estimate.Fraction = float64(summary.TotalObjects) / float64(count)
+	if estimate.Fraction > 0 {
+		estimate.Size = int64(float64(summary.TotalSize) / estimate.Fraction)
+		estimate.Cost = summary.EstimatedCost / estimate.Fraction
+	}
+
+	return estimate
+}
@@ -0,0 +1,189 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Approximate US East pricing of separately billed bucket features
+const (
+	analyticsPerMillionObjects   = 0.10   // Storage Class Analysis, per month
+	inventoryPerMillionObjects   = 0.0025 // S3 Inventory, per report
+	storageLensPerMillionObjects = 0.20   // Storage Lens advanced metrics, per month
+	requestMetricsPerConfig      = 16 * 0.30
+)
+
+// StorageLensFunc returns the Storage Lens configurations covering a bucket
+type StorageLensFunc func(ctx context.Context, bucketName, region string) ([]types.StorageLensConfig, error)
+
+// analyzeChargeableFeatures lists analytics, metrics, inventory and Storage
+// Lens configurations with their estimated monthly cost
+func (ca *ConfigAnalyzer) analyzeChargeableFeatures(ctx context.Context, summary *types.BucketSummary, config *types.BucketConfiguration, objects []types.ObjectMetadata) {
+	for _, inv := range config.Inventories {
+		if !inv.Enabled {
+			continue
+		}
+		reports := 30.0
+		if inv.Frequency == "Weekly" {
+			reports = 52.0 / 12
+		}
+		count := countUnderPrefix(objects, inv.Prefix)
+		config.ChargeableFeatures = append(config.ChargeableFeatures, types.ChargeableFeature{
+			Type:             "Inventory",
+			ID:               inv.ID,
+			Scope:            scopeLabel(inv.Prefix),
+			MonitoredObjects: count,
+			MonthlyCost:      float64(count) / 1e6 * inventoryPerMillionObjects * reports,
+			Note:             strings.ToLower(inv.Frequency) + " reports",
+		})
+	}
+
+	if err := ca.listAnalytics(ctx, summary.Name, config, objects); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("analytics configurations: %v", err))
+	}
+
+	if err := ca.listMetrics(ctx, summary.Name, config); err != nil {
+		config.Errors = append(config.Errors, fmt.Sprintf("metrics configurations: %v", err))
+	}
+
+	if ca.storageLens != nil {
+		lenses, err := ca.storageLens(ctx, summary.Name, summary.Region)
+		if err != nil {
+			config.Errors = append(config.Errors, fmt.Sprintf("storage lens: %v", err))
+		}
+		for _, lens := range lenses {
+			feature := types.ChargeableFeature{
+				Type:             "Storage Lens",
+				ID:               lens.ID,
+				Scope:            "bucket",
+				MonitoredObjects: summary.TotalObjects,
+				Note:             "free metrics only",
+			}
+			if lens.Advanced {
+				feature.MonthlyCost = float64(summary.TotalObjects) / 1e6 * storageLensPerMillionObjects
+				feature.Note = "advanced metrics"
+			}
+			config.ChargeableFeatures = append(config.ChargeableFeatures, feature)
+		}
+	}
+}
+
+// listAnalytics records Storage Class Analysis configurations
+func (ca *ConfigAnalyzer) listAnalytics(ctx context.Context, bucketName string, config *types.BucketConfiguration, objects []types.ObjectMetadata) error {
+	var continuationToken *string
+	for {
+		result, err := ca.s3Client.ListBucketAnalyticsConfigurations(ctx, &s3.ListBucketAnalyticsConfigurationsInput{
+			Bucket:            aws.String(bucketName),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, a := range result.AnalyticsConfigurationList {
+			prefix, scope := analyticsFilterScope(a.Filter)
+			count := countUnderPrefix(objects, prefix)
+			config.ChargeableFeatures = append(config.ChargeableFeatures, types.ChargeableFeature{
+				Type:             "Storage Class Analysis",
+				ID:               aws.ToString(a.Id),
+				Scope:            scope,
+				MonitoredObjects: count,
+				MonthlyCost:      float64(count) / 1e6 * analyticsPerMillionObjects,
+			})
+		}
+
+		if !aws.ToBool(result.IsTruncated) {
+			return nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// listMetrics records CloudWatch request metrics configurations
+func (ca *ConfigAnalyzer) listMetrics(ctx context.Context, bucketName string, config *types.BucketConfiguration) error {
+	var continuationToken *string
+	for {
+		result, err := ca.s3Client.ListBucketMetricsConfigurations(ctx, &s3.ListBucketMetricsConfigurationsInput{
+			Bucket:            aws.String(bucketName),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, m := range result.MetricsConfigurationList {
+			config.ChargeableFeatures = append(config.ChargeableFeatures, types.ChargeableFeature{
+				Type:        "Request Metrics",
+				ID:          aws.ToString(m.Id),
+				Scope:       metricsFilterScope(m.Filter),
+				MonthlyCost: requestMetricsPerConfig,
+				Note:        "16 CloudWatch custom metrics plus request volume",
+			})
+		}
+
+		if !aws.ToBool(result.IsTruncated) {
+			return nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// analyticsFilterScope returns the key prefix of an analytics filter and a
+// human-readable scope description
+func analyticsFilterScope(filter s3types.AnalyticsFilter) (string, string) {
+	switch f := filter.(type) {
+	case *s3types.AnalyticsFilterMemberPrefix:
+		return f.Value, scopeLabel(f.Value)
+	case *s3types.AnalyticsFilterMemberTag:
+		return "", "tag " + aws.ToString(f.Value.Key) + "=" + aws.ToString(f.Value.Value)
+	case *s3types.AnalyticsFilterMemberAnd:
+		prefix := aws.ToString(f.Value.Prefix)
+		return prefix, fmt.Sprintf("%s and %d tag(s)", scopeLabel(prefix), len(f.Value.Tags))
+	default:
+		return "", "bucket"
+	}
+}
+
+// metricsFilterScope returns a human-readable scope for a metrics filter
+func metricsFilterScope(filter s3types.MetricsFilter) string {
+	switch f := filter.(type) {
+	case *s3types.MetricsFilterMemberPrefix:
+		return scopeLabel(f.Value)
+	case *s3types.MetricsFilterMemberTag:
+		return "tag " + aws.ToString(f.Value.Key) + "=" + aws.ToString(f.Value.Value)
+	case *s3types.MetricsFilterMemberAccessPointArn:
+		return "access point " + f.Value
+	case *s3types.MetricsFilterMemberAnd:
+		return fmt.Sprintf("%s and %d tag(s)", scopeLabel(aws.ToString(f.Value.Prefix)), len(f.Value.Tags))
+	default:
+		return "bucket"
+	}
+}
+
+// scopeLabel describes a prefix filter
+func scopeLabel(prefix string) string {
+	if prefix == "" {
+		return "bucket"
+	}
+	return "prefix " + prefix
+}
+
+// countUnderPrefix counts listed objects whose key starts with prefix
+func countUnderPrefix(objects []types.ObjectMetadata, prefix string) int64 {
+	if prefix == "" {
+		return int64(len(objects))
+	}
+	count := int64(0)
+	for _, obj := range objects {
+		if strings.HasPrefix(obj.Key, prefix) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,383 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Finding rules reported by BuildFindings
+var (
+	rulePublicPolicy = types.FindingRule{
+		ID:               "S3P001",
+		Name:             "PublicBucketPolicy",
+		Description:      "The bucket policy grants public access to the bucket.",
+		Recommendation:   "Remove the statements granting access to Principal \"*\" or restrict them with conditions, and enable Block Public Access.",
+		Level:            "error",
+		SecuritySeverity: 9.0,
+	}
+	rulePublicAccessFinding = types.FindingRule{
+		ID:               "S3P002",
+		Name:             "PublicAccessAnalyzerFinding",
+		Description:      "IAM Access Analyzer reports that the bucket is publicly accessible.",
+		Recommendation:   "Remove the public grant named by the Access Analyzer finding, or archive the finding if the access is intended.",
+		Level:            "error",
+		SecuritySeverity: 9.0,
+	}
+	ruleBlockPublicAccessOff = types.FindingRule{
+		ID:               "S3P003",
+		Name:             "BlockPublicAccessDisabled",
+		Description:      "Block Public Access is not fully enabled on the bucket.",
+		Recommendation:   "Turn on all four Block Public Access settings unless the bucket must be public.",
+		Level:            "warning",
+		SecuritySeverity: 5.0,
+	}
+	ruleNoDefaultEncryption = types.FindingRule{
+		ID:               "S3P004",
+		Name:             "NoDefaultEncryption",
+		Description:      "The bucket has no default server-side encryption.",
+		Recommendation:   "Configure default encryption with SSE-S3 or SSE-KMS.",
+		Level:            "warning",
+		SecuritySeverity: 5.0,
+	}
+	ruleUnencryptedPrefix = types.FindingRule{
+		ID:               "S3P005",
+		Name:             "UnencryptedObjects",
+		Description:      "Sampled objects under the prefix are stored without server-side encryption.",
+		Recommendation:   "Copy the objects onto themselves to encrypt them with the bucket's default encryption.",
+		Level:            "warning",
+		SecuritySeverity: 5.0,
+	}
+	ruleMissingLifecycle = types.FindingRule{
+		ID:               "S3P006",
+		Name:             "MissingLifecycle",
+		Description:      "The bucket has no enabled lifecycle rule, so old data, noncurrent versions and incomplete uploads are kept forever.",
+		Recommendation:   "Add lifecycle rules that abort incomplete multipart uploads and expire or transition old data.",
+		Level:            "note",
+		SecuritySeverity: 2.0,
+	}
+	rulePolicyViolation = types.FindingRule{
+		ID:               "S3P100",
+		Name:             "PolicyViolation",
+		Description:      "The bucket violates a user-supplied Rego policy.",
+		Recommendation:   "Change the bucket to comply with the policy, or amend the policy.",
+		Level:            "error",
+		SecuritySeverity: 7.0,
+	}
+)
+
+// Cost rules reported by CostFindings
+var (
+	ruleLifecycleTransition = types.FindingRule{
+		ID:               "S3P201",
+		Name:             "LifecycleTransition",
+		Description:      "Data under the prefix is old enough to transition to a cheaper storage class.",
+		Recommendation:   "Add the recommended lifecycle transition rule (see --emit-lifecycle-config).",
+		Level:            "note",
+		SecuritySeverity: 3.0,
+	}
+	ruleIntelligentTiering = types.FindingRule{
+		ID:               "S3P202",
+		Name:             "IntelligentTiering",
+		Description:      "Moving the prefix to Intelligent-Tiering is modeled to lower its storage cost.",
+		Recommendation:   "Transition the prefix's objects of 128 KB and more to INTELLIGENT_TIERING.",
+		Level:            "note",
+		SecuritySeverity: 3.0,
+	}
+	ruleCheaperStorageClass = types.FindingRule{
+		ID:               "S3P203",
+		Name:             "CheaperStorageClass",
+		Description:      "At its monthly GET rate, the prefix is cheaper in another storage class.",
+		Recommendation:   "Move the prefix to the cheaper storage class.",
+		Level:            "note",
+		SecuritySeverity: 3.0,
+	}
+	ruleObjectFees = types.FindingRule{
+		ID:               "S3P204",
+		Name:             "ObjectFeesExceedStorage",
+		Description:      "Per-object fees of the prefix's storage class cost more than storing its bytes.",
+		Recommendation:   "Move the small objects back to STANDARD or bundle them into larger objects.",
+		Level:            "warning",
+		SecuritySeverity: 4.0,
+	}
+	ruleNoncurrentVersions = types.FindingRule{
+		ID:               "S3P205",
+		Name:             "NoncurrentVersions",
+		Description:      "Non-current object versions are kept without an expiration rule and billed as storage.",
+		Recommendation:   "Add a lifecycle rule with NoncurrentVersionExpiration.",
+		Level:            "note",
+		SecuritySeverity: 3.0,
+	}
+	ruleCompressible = types.FindingRule{
+		ID:               "S3P206",
+		Name:             "CompressibleData",
+		Description:      "Objects under the prefix are stored uncompressed but compress well.",
+		Recommendation:   "Write the objects compressed, e.g. with gzip or zstd.",
+		Level:            "note",
+		SecuritySeverity: 2.0,
+	}
+	ruleSmallFiles = types.FindingRule{
+		ID:               "S3P207",
+		Name:             "SmallFiles",
+		Description:      "The directory holds many small files, which slow down listings and query engines.",
+		Recommendation:   "Compact the files into objects of about 128 MiB.",
+		Level:            "note",
+		SecuritySeverity: 2.0,
+	}
+)
+
+// BuildFindings derives security and compliance findings from the
+// configuration audit and the HeadObject sample. Checks that could not be
+// completed produce no findings.
+func BuildFindings(summary *types.BucketSummary, config *types.BucketConfiguration, metadata *types.MetadataSummary) []types.Finding {
+	var findings []types.Finding
+	add := func(rule types.FindingRule, prefix, detail, message string) {
+		findings = append(findings, types.Finding{
+			Rule:           rule,
+			Bucket:         summary.Name,
+			Prefix:         prefix,
+			Resource:       findingResource(summary.Name, prefix),
+			Message:        message,
+			Recommendation: rule.Recommendation,
+			Detail:         detail,
+		})
+	}
+
+	if config.PublicAccessChecked {
+		if config.PolicyPublic {
+			add(rulePublicPolicy, "", "", "Bucket policy grants public access")
+		}
+		if len(config.PublicAccessBlockOff) > 0 {
+			add(ruleBlockPublicAccessOff, "", "",
+				"Block Public Access settings off: "+strings.Join(config.PublicAccessBlockOff, ", "))
+		}
+	}
+
+	for _, f := range config.AccessFindings {
+		if f.IsPublic {
+			add(rulePublicAccessFinding, "", f.ID, fmt.Sprintf(
+				"Access Analyzer finding %s: bucket is publicly accessible (%s)", f.ID, strings.Join(f.Actions, ",")))
+		}
+	}
+
+	if config.EncryptionChecked && config.DefaultEncryption == "" {
+		add(ruleNoDefaultEncryption, "", "", "No default server-side encryption is configured")
+	}
+
+	if e := metadata.Enrichment; e != nil {
+		prefixes := make([]string, 0, len(e.UnencryptedPrefixes))
+		for prefix := range e.UnencryptedPrefixes {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		for _, prefix := range prefixes {
+			add(ruleUnencryptedPrefix, prefix, "", fmt.Sprintf(
+				"~%d object(s) without server-side encryption (estimated from sampled HeadObject)",
+				int64(math.Round(e.UnencryptedPrefixes[prefix]))))
+		}
+	}
+
+	if config.LifecycleChecked && !hasEnabledLifecycle(config.LifecycleRules) && summary.TotalObjects > 0 {
+		add(ruleMissingLifecycle, "", "", "No enabled lifecycle rule")
+	}
+
+	return findings
+}
+
+// PolicyFindings converts Rego policy violations to findings
+func PolicyFindings(bucketName string, violations []string) []types.Finding {
+	findings := make([]types.Finding, 0, len(violations))
+	for _, v := range violations {
+		findings = append(findings, types.Finding{
+			Rule:           rulePolicyViolation,
+			Bucket:         bucketName,
+			Resource:       findingResource(bucketName, ""),
+			Message:        v,
+			Recommendation: rulePolicyViolation.Recommendation,
+			Detail:         v,
+		})
+	}
+	return findings
+}
+
+// CostFindings derives cost findings from the storage analyses of a
+// bucket: lifecycle, Intelligent-Tiering and storage class what-ifs,
+// per-object fees, non-current versions, compressibility and small files.
+// Analyses that did not run produce no findings.
+func CostFindings(summary *types.BucketSummary, config *types.BucketConfiguration, metadata *types.MetadataSummary, partitions *types.PartitionAnalysis) []types.Finding {
+	var findings []types.Finding
+	add := func(rule types.FindingRule, prefix, message, recommendation string, savings float64) {
+		findings = append(findings, types.Finding{
+			Rule:           rule,
+			Bucket:         summary.Name,
+			Prefix:         prefix,
+			Resource:       findingResource(summary.Name, prefix),
+			Message:        message,
+			Recommendation: recommendation,
+			Savings:        savings,
+		})
+	}
+
+	if l := summary.Lifecycle; l != nil {
+		for _, rec := range l.Rules {
+			if rec.Savings <= 0 {
+				continue
+			}
+			steps := make([]string, 0, len(rec.Transitions))
+			for _, t := range rec.Transitions {
+				steps = append(steps, fmt.Sprintf("%s after %d days", t.StorageClass, t.Days))
+			}
+			add(ruleLifecycleTransition, rec.Prefix,
+				fmt.Sprintf("%s in %d object(s) can transition to a cheaper storage class",
+					output.FormatBytes(rec.Size), rec.ObjectCount),
+				"Add a lifecycle rule transitioning to "+strings.Join(steps, ", ")+".", rec.Savings)
+		}
+	}
+
+	if t := summary.Tiering; t != nil {
+		for _, e := range t.Prefixes {
+			if e.Savings <= 0 {
+				continue
+			}
+			add(ruleIntelligentTiering, e.Prefix,
+				fmt.Sprintf("Intelligent-Tiering is modeled to save %s per month on %s (payback %.1f months)",
+					output.FormatCost(e.Savings), output.FormatBytes(e.MonitoredSize), e.PaybackMonths),
+				ruleIntelligentTiering.Recommendation, e.Savings)
+		}
+	}
+
+	if a := summary.AccessCosts; a != nil {
+		for _, p := range a.Prefixes {
+			if p.Savings <= 0 || p.Recommended == "" {
+				continue
+			}
+			add(ruleCheaperStorageClass, p.Prefix,
+				fmt.Sprintf("%s is cheapest at %.0f GETs per month", p.Recommended, p.Gets),
+				"Move the prefix to "+p.Recommended+".", p.Savings)
+		}
+	}
+
+	for _, f := range summary.ObjectFees {
+		if !f.Current {
+			continue
+		}
+		add(ruleObjectFees, f.Prefix,
+			fmt.Sprintf("%d object(s) in %s pay %s per month in per-object fees for %s of storage",
+				f.ObjectCount, f.StorageClass, output.FormatCost(f.OverheadCost), output.FormatCost(f.DataCost)),
+			ruleObjectFees.Recommendation, 0)
+	}
+
+	if v := summary.Versioning; v != nil && v.NoncurrentCost > 0 && !expiresNoncurrent(config.LifecycleRules) {
+		add(ruleNoncurrentVersions, "",
+			fmt.Sprintf("%d non-current version(s) hold %s", v.NoncurrentVersions, output.FormatBytes(v.NoncurrentSize)),
+			ruleNoncurrentVersions.Recommendation, v.NoncurrentCost)
+	}
+
+	if c := metadata.Compressibility; c != nil {
+		for _, p := range c.Prefixes {
+			if p.Class != types.CompressibleContent || p.Savings <= 0 {
+				continue
+			}
+			add(ruleCompressible, p.Prefix,
+				fmt.Sprintf("Content compresses to %.0f%% of its size; %s could be saved",
+					p.Ratio*100, output.FormatBytes(p.SavedBytes)),
+				ruleCompressible.Recommendation, p.Savings)
+		}
+	}
+
+	if partitions != nil {
+		for _, s := range partitions.SmallFiles {
+			add(ruleSmallFiles, s.Prefix,
+				fmt.Sprintf("%d of %d object(s) are small files, %s on average",
+					s.SmallFiles, s.ObjectCount, output.FormatBytes(s.AverageSize)),
+				fmt.Sprintf("Compact the %d files into %d file(s) of about %s.",
+					s.ObjectCount, s.CompactedFiles, output.FormatBytes(s.CompactedSize)), 0)
+		}
+	}
+
+	return findings
+}
+
+// expiresNoncurrent reports whether an enabled lifecycle rule expires
+// non-current versions
+func expiresNoncurrent(rules []types.LifecycleRule) bool {
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		for _, action := range rule.Actions {
+			if strings.HasPrefix(action, "noncurrent expire") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findingResource returns the s3:// URI of a bucket or prefix; "" and "/"
+// stand for the whole bucket
+func findingResource(bucket, prefix string) string {
+	return types.NewLocation(bucket, prefix).URI()
+}
+
+// DefaultSeverityThresholds rate rules scored 7 or more critical and 4 or
+// more warn, in line with the high and medium CVSS ratings
+var DefaultSeverityThresholds = types.SeverityThresholds{Warn: 4, Critical: 7}
+
+// severityRanks orders the severity levels, most severe last
+var severityRanks = map[types.Severity]int{
+	types.SeverityInfo:     0,
+	types.SeverityWarn:     1,
+	types.SeverityCritical: 2,
+}
+
+// ParseSeverity validates a severity level name
+func ParseSeverity(name string) (types.Severity, error) {
+	severity := types.Severity(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := severityRanks[severity]; !ok {
+		return "", fmt.Errorf("invalid severity %q (expected info, warn or critical)", name)
+	}
+	return severity, nil
+}
+
+// RateFindings sets the severity of each finding from its rule's score
+func RateFindings(findings []types.Finding, thresholds types.SeverityThresholds) {
+	if thresholds == (types.SeverityThresholds{}) {
+		thresholds = DefaultSeverityThresholds
+	}
+	for i := range findings {
+		switch score := findings[i].Rule.SecuritySeverity; {
+		case score >= thresholds.Critical:
+			findings[i].Severity = types.SeverityCritical
+		case score >= thresholds.Warn:
+			findings[i].Severity = types.SeverityWarn
+		default:
+			findings[i].Severity = types.SeverityInfo
+		}
+	}
+}
+
+// SortFindings orders findings most severe first, then by rule score,
+// savings, rule and location
+func SortFindings(findings []types.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if ra, rb := severityRanks[a.Severity], severityRanks[b.Severity]; ra != rb {
+			return ra > rb
+		}
+		if a.Rule.SecuritySeverity != b.Rule.SecuritySeverity {
+			return a.Rule.SecuritySeverity > b.Rule.SecuritySeverity
+		}
+		if a.Savings != b.Savings {
+			return a.Savings > b.Savings
+		}
+		if a.Rule.ID != b.Rule.ID {
+			return a.Rule.ID < b.Rule.ID
+		}
+		return a.Prefix < b.Prefix
+	})
+}
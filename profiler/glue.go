@@ -0,0 +1,132 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// GlueTableFunc returns a Glue table's definition and registered partitions
+type GlueTableFunc func(ctx context.Context, database, table, region string) (*types.GlueTable, error)
+
+// parseGlueTable splits a "database.table" name
+func parseGlueTable(name string) (database, table string, err error) {
+	database, table, ok := strings.Cut(name, ".")
+	if !ok || database == "" || table == "" {
+		return "", "", fmt.Errorf("invalid Glue table %q (expected database.table)", name)
+	}
+	return database, table, nil
+}
+
+// compareGlueTable looks up the configured Glue table in the bucket's
+// region and finds the partition directories it is missing
+func (p *Profiler) compareGlueTable(ctx context.Context, bucketName, region string, objects []types.ObjectMetadata) (*types.GlueRegistration, error) {
+	database, table, err := parseGlueTable(p.config.GlueTable)
+	if err != nil {
+		return nil, err
+	}
+
+	glueTable, err := p.glueTable(ctx, database, table, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Glue table %s: %w", p.config.GlueTable, err)
+	}
+	return findGluePartitions(bucketName, glueTable, objects)
+}
+
+// findGluePartitions groups the objects under a table's location into
+// partition directories and splits them into registered and missing ones.
+// Directories are named key=value (Hive style, matched to the partition
+// keys by name) or hold bare values in partition key order, e.g. 2024/01/05
+// for keys year, month and day.
+func findGluePartitions(bucketName string, table *types.GlueTable, objects []types.ObjectMetadata) (*types.GlueRegistration, error) {
+	if len(table.PartitionKeys) == 0 {
+		return nil, fmt.Errorf("Glue table %s.%s has no partition keys", table.Database, table.Name)
+	}
+
+	location, err := url.Parse(table.Location)
+	if err != nil || (location.Scheme != "s3" && location.Scheme != "s3a") {
+		return nil, fmt.Errorf("Glue table location %q is not an S3 URL", table.Location)
+	}
+	if location.Host != bucketName {
+		return nil, fmt.Errorf("Glue table location %s is not in bucket %s", table.Location, bucketName)
+	}
+	prefix := strings.TrimPrefix(location.Path, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	registered := make(map[string]bool, len(table.Partitions))
+	for _, values := range table.Partitions {
+		registered[strings.Join(values, "\x00")] = true
+	}
+
+	result := &types.GlueRegistration{Table: table}
+	directories := make(map[string]*types.GluePartition)
+	for _, obj := range objects {
+		if !strings.HasPrefix(obj.Key, prefix) {
+			continue
+		}
+		values, dir, ok := partitionValues(obj.Key[len(prefix):], table.PartitionKeys)
+		if !ok {
+			result.Unmapped++
+			continue
+		}
+
+		partition, exists := directories[dir]
+		if !exists {
+			partition = &types.GluePartition{Values: values, Prefix: prefix + dir}
+			directories[dir] = partition
+		}
+		partition.ObjectCount++
+		partition.TotalSize += obj.Size
+	}
+
+	for _, partition := range directories {
+		if registered[strings.Join(partition.Values, "\x00")] {
+			result.Registered++
+			continue
+		}
+		result.Missing = append(result.Missing, *partition)
+	}
+	sort.Slice(result.Missing, func(i, j int) bool {
+		return result.Missing[i].Prefix < result.Missing[j].Prefix
+	})
+
+	return result, nil
+}
+
+// partitionValues reads one value per partition key from the leading
+// directories of a key relative to the table location, returning the values
+// and the partition directory
+func partitionValues(rel string, keys []string) ([]string, string, bool) {
+	segments := strings.Split(rel, "/")
+	// The last segment is the object name, not a directory
+	if len(segments) <= len(keys) {
+		return nil, "", false
+	}
+
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		value := segments[i]
+		if name, v, found := strings.Cut(value, "="); found {
+			if !strings.EqualFold(name, key) {
+				return nil, "", false
+			}
+			// Hive escapes special characters in partition values
+			value = v
+			if unescaped, err := url.PathUnescape(v); err == nil {
+				value = unescaped
+			}
+		}
+		if value == "" {
+			return nil, "", false
+		}
+		values[i] = value
+	}
+
+	return values, strings.Join(segments[:len(keys)], "/") + "/", true
+}
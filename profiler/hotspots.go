@@ -0,0 +1,225 @@
+package profiler
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Thresholds for flagging prefixes near the S3 request-rate limits
+const (
+	// prefixWriteRateLimit is the PUT/COPY/POST/DELETE requests per second
+	// S3 supports per partitioned prefix
+	prefixWriteRateLimit = 3500
+	// hotspotBurstShare is the share of the limit written within one second
+	// that flags a prefix
+	hotspotBurstShare = 0.5
+	// hotspotSustainedShare is the share of the limit sustained over a
+	// clock minute that flags a prefix
+	hotspotSustainedShare = 0.25
+	// hotspotDepth is the deepest directory level whose rates are measured
+	hotspotDepth = 3
+)
+
+// Key naming of the segment that follows a hot prefix
+const (
+	hotspotNamingTimestamp  = "timestamp"
+	hotspotNamingSequential = "sequential"
+	hotspotNamingMixed      = "mixed"
+)
+
+var (
+	// timestampSegment matches segments starting with a year, optionally
+	// as a Hive partition such as dt=2024-01-01
+	timestampSegment = regexp.MustCompile(`^([A-Za-z_]+=)?(19|20)\d{2}`)
+	// sequentialSegment matches segments starting with a long number, such
+	// as increasing ids or epoch timestamps
+	sequentialSegment = regexp.MustCompile(`^\d{6,}`)
+)
+
+// hotspotStats accumulates the write rates of one prefix
+type hotspotStats struct {
+	seconds map[int64]int64
+	minutes map[int64]int64
+	naming  map[string]int64
+	example string
+}
+
+// FlagHotspots returns the prefixes whose writes, clustered by
+// LastModified, come close to the per-prefix request-rate limit, with a
+// key-naming strategy to spread them. Only the most specific prefix over
+// the thresholds is reported.
+func (pa *PartitionAnalyzer) FlagHotspots(objects []types.ObjectMetadata) []types.PrefixHotspot {
+	burstThreshold := int64(prefixWriteRateLimit * hotspotBurstShare)
+	sustainedThreshold := int64(prefixWriteRateLimit * hotspotSustainedShare * 60)
+
+	// A prefix never writes faster than the whole bucket, so only objects
+	// written in seconds or minutes that are hot bucket-wide are counted
+	// per prefix
+	bucketSeconds := make(map[int64]int64)
+	bucketMinutes := make(map[int64]int64)
+	for _, obj := range objects {
+		if obj.LastModified.IsZero() {
+			continue
+		}
+		second := obj.LastModified.Unix()
+		bucketSeconds[second]++
+		bucketMinutes[second/60]++
+	}
+
+	stats := make(map[string]*hotspotStats)
+	for _, obj := range objects {
+		if obj.LastModified.IsZero() {
+			continue
+		}
+		second := obj.LastModified.Unix()
+		if bucketSeconds[second] < burstThreshold && bucketMinutes[second/60] < sustainedThreshold {
+			continue
+		}
+
+		prefix := ""
+		rest := obj.Key
+		for depth := 0; ; depth++ {
+			s, ok := stats[prefix]
+			if !ok {
+				s = &hotspotStats{
+					seconds: make(map[int64]int64),
+					minutes: make(map[int64]int64),
+					naming:  make(map[string]int64),
+					example: obj.Key,
+				}
+				stats[prefix] = s
+			}
+			s.seconds[second]++
+			s.minutes[second/60]++
+
+			segment, _, more := strings.Cut(rest, "/")
+			s.naming[segmentNaming(segment)]++
+			if !more || depth == hotspotDepth {
+				break
+			}
+			prefix += segment + "/"
+			rest = rest[len(segment)+1:]
+		}
+	}
+
+	var hotspots []types.PrefixHotspot
+	for prefix, s := range stats {
+		var h types.PrefixHotspot
+		for second, n := range s.seconds {
+			if n > h.PeakWrites || (n == h.PeakWrites && second < h.PeakAt.Unix()) {
+				h.PeakWrites = n
+				h.PeakAt = time.Unix(second, 0).UTC()
+			}
+			if n >= burstThreshold {
+				h.BurstSeconds++
+			}
+		}
+		var peakMinute int64
+		for _, n := range s.minutes {
+			peakMinute = max(peakMinute, n)
+		}
+		if h.PeakWrites < burstThreshold && peakMinute < sustainedThreshold {
+			continue
+		}
+
+		h.Prefix = prefix
+		h.SustainedRate = float64(peakMinute) / 60
+		h.Share = float64(h.PeakWrites) / prefixWriteRateLimit
+		h.Naming = dominantNaming(s.naming)
+		h.Shards = hotspotShards(h.PeakWrites)
+		h.Example = shardedExample(prefix, s.example, h.Shards)
+		h.Suggestion = hotspotSuggestion(h)
+		hotspots = append(hotspots, h)
+	}
+
+	// Keep the most specific prefixes: a flagged descendant explains its
+	// ancestors' rates
+	var specific []types.PrefixHotspot
+	for _, h := range hotspots {
+		covered := false
+		for _, other := range hotspots {
+			if other.Prefix != h.Prefix && strings.HasPrefix(other.Prefix, h.Prefix) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			specific = append(specific, h)
+		}
+	}
+
+	sort.Slice(specific, func(i, j int) bool {
+		if specific[i].PeakWrites != specific[j].PeakWrites {
+			return specific[i].PeakWrites > specific[j].PeakWrites
+		}
+		return specific[i].Prefix < specific[j].Prefix
+	})
+	return specific
+}
+
+// segmentNaming classifies the key segment that follows a prefix
+func segmentNaming(segment string) string {
+	switch {
+	case timestampSegment.MatchString(segment):
+		return hotspotNamingTimestamp
+	case sequentialSegment.MatchString(segment):
+		return hotspotNamingSequential
+	default:
+		return hotspotNamingMixed
+	}
+}
+
+// dominantNaming returns the naming of most of a prefix's hot writes
+func dominantNaming(naming map[string]int64) string {
+	var total int64
+	for _, n := range naming {
+		total += n
+	}
+	for _, kind := range []string{hotspotNamingTimestamp, hotspotNamingSequential} {
+		if naming[kind]*2 > total {
+			return kind
+		}
+	}
+	return hotspotNamingMixed
+}
+
+// hotspotShards returns the hashed sub-prefixes that keep the peak rate
+// under the burst threshold in each, rounded up to a whole number of hex
+// characters
+func hotspotShards(peak int64) int {
+	needed := math.Ceil(float64(peak) / (prefixWriteRateLimit * hotspotBurstShare))
+	shards := 16
+	for float64(shards) < needed {
+		shards *= 16
+	}
+	return shards
+}
+
+// shardedExample rewrites a key with a hash shard after the prefix, the
+// layout the suggestion proposes
+func shardedExample(prefix, key string, shards int) string {
+	sum := md5.Sum([]byte(key))
+	chars := int(math.Round(math.Log(float64(shards)) / math.Log(16)))
+	return prefix + hex.EncodeToString(sum[:])[:chars] + "/" + strings.TrimPrefix(key, prefix)
+}
+
+// hotspotSuggestion explains how to spread a hot prefix's writes
+func hotspotSuggestion(h types.PrefixHotspot) string {
+	shard := fmt.Sprintf("add a %d-way hash shard after the prefix", h.Shards)
+	switch h.Naming {
+	case hotspotNamingTimestamp:
+		return fmt.Sprintf("keys start with a date or timestamp, so concurrent writes share one key range; %s, ahead of the timestamp", shard)
+	case hotspotNamingSequential:
+		return fmt.Sprintf("keys start with increasing numbers, so concurrent writes share one key range; reverse the number or %s", shard)
+	default:
+		return fmt.Sprintf("writes are concentrated under one prefix; %s", shard)
+	}
+}
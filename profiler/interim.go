@@ -0,0 +1,132 @@
+package profiler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// interimTopPrefixes is the number of top-level prefixes an interim
+// summary lists
+const interimTopPrefixes = 10
+
+// InterimSummaries writes an interim summary of each bucket's listing
+// every interval, so the findings of long runs can be read before they
+// complete
+type InterimSummaries struct {
+	writer   *output.Writer
+	interval time.Duration
+}
+
+// NewInterimSummaries creates interim summaries written every interval;
+// an interval of 0 disables them
+func NewInterimSummaries(writer *output.Writer, interval time.Duration) *InterimSummaries {
+	return &InterimSummaries{writer: writer, interval: interval}
+}
+
+// Enabled reports whether interim summaries are written
+func (is *InterimSummaries) Enabled() bool {
+	return is != nil && is.interval > 0
+}
+
+// interimListing accumulates one bucket's listing. Pages may be added from
+// several goroutines.
+type interimListing struct {
+	is      *InterimSummaries
+	mu      sync.Mutex
+	summary types.InterimSummary
+	// prefixes totals the objects per top-level prefix
+	prefixes map[string]*types.PrefixStats
+	written  time.Time
+}
+
+// start begins the interim summary of a bucket's listing; it returns nil
+// when interim summaries are disabled
+func (is *InterimSummaries) start(bucketName, region string) *interimListing {
+	if !is.Enabled() {
+		return nil
+	}
+	now := time.Now()
+	return &interimListing{
+		is: is,
+		summary: types.InterimSummary{
+			Bucket:         bucketName,
+			Region:         region,
+			Started:        now,
+			StorageClasses: make(map[string]types.StorageClassStats),
+		},
+		prefixes: make(map[string]*types.PrefixStats),
+		written:  now,
+	}
+}
+
+// add counts a listed page and writes the summary when the interval has
+// passed since the last write
+func (il *interimListing) add(page []types.ObjectMetadata) error {
+	if il == nil {
+		return nil
+	}
+	il.mu.Lock()
+	defer il.mu.Unlock()
+
+	for _, obj := range page {
+		il.summary.Objects++
+		il.summary.Size += obj.Size
+		stats := il.summary.StorageClasses[obj.StorageClass]
+		stats.Count++
+		stats.Size += obj.Size
+		il.summary.StorageClasses[obj.StorageClass] = stats
+
+		prefix := topLevelPrefix(obj.Key)
+		p, exists := il.prefixes[prefix]
+		if !exists {
+			p = &types.PrefixStats{Prefix: prefix}
+			il.prefixes[prefix] = p
+		}
+		p.ObjectCount++
+		p.Size += obj.Size
+	}
+
+	if time.Since(il.written) < il.is.interval {
+		return nil
+	}
+	return il.write()
+}
+
+// finish writes the summary of the completed listing, which stays until
+// the bucket's reports are written
+func (il *interimListing) finish() error {
+	if il == nil {
+		return nil
+	}
+	il.mu.Lock()
+	defer il.mu.Unlock()
+	il.summary.Complete = true
+	return il.write()
+}
+
+// write writes the summary; the caller holds il.mu
+func (il *interimListing) write() error {
+	il.written = time.Now()
+	il.summary.Updated = il.written
+
+	il.summary.Prefixes = il.summary.Prefixes[:0]
+	for _, p := range il.prefixes {
+		il.summary.Prefixes = append(il.summary.Prefixes, *p)
+	}
+	sort.Slice(il.summary.Prefixes, func(i, j int) bool {
+		a, b := il.summary.Prefixes[i], il.summary.Prefixes[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Prefix < b.Prefix
+	})
+	if len(il.summary.Prefixes) > interimTopPrefixes {
+		il.summary.Prefixes = il.summary.Prefixes[:interimTopPrefixes]
+	}
+
+	return il.is.writer.WriteInterimSummary(&il.summary)
+}
@@ -0,0 +1,412 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// inventoryFolderLayout names the dated folder each inventory report is
+// delivered to, e.g. 2024-05-01T01-00Z
+const inventoryFolderLayout = "2006-01-02T15-04Z"
+
+// inventoryPageSize is the number of inventory rows passed on at a time,
+// matching a ListObjectsV2 page
+const inventoryPageSize = 1000
+
+// inventoryRequiredFields are the optional inventory fields the profiler
+// cannot do without; ETag is used when present
+var inventoryRequiredFields = []string{"Size", "LastModifiedDate", "StorageClass"}
+
+// inventoryManifest is the manifest.json delivered with each report
+type inventoryManifest struct {
+	SourceBucket      string `json:"sourceBucket"`
+	FileFormat        string `json:"fileFormat"`
+	FileSchema        string `json:"fileSchema"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Files             []struct {
+		Key  string `json:"key"`
+		Size int64  `json:"size"`
+	} `json:"files"`
+}
+
+// inventoryReport is the latest usable report of a bucket
+type inventoryReport struct {
+	source   types.InventorySource
+	manifest inventoryManifest
+}
+
+// walkInventory passes the objects of the bucket's latest S3 Inventory
+// report to fn the way walkObjects does, when --use-inventory is set. It
+// returns false, after printing why, when the bucket has no usable report,
+// so the caller falls back to ListObjectsV2.
+func (ba *BucketAnalyzer) walkInventory(ctx context.Context, bucketName string, summary *types.BucketSummary, fn func([]types.ObjectMetadata) error) (bool, error) {
+	if !ba.useInventory {
+		return false, nil
+	}
+
+	report, err := ba.findInventoryReport(ctx, bucketName)
+	if err != nil {
+		ba.progress.Warnf("cannot use S3 Inventory, listing objects instead: %v", err)
+		return false, nil
+	}
+	if report == nil {
+		ba.progress.Printf("No usable S3 Inventory report (needs an enabled CSV or Parquet inventory of current versions of the whole bucket with Size, LastModifiedDate and StorageClass), listing objects instead\n")
+		return false, nil
+	}
+
+	source := report.source
+	ba.progress.Printf("Reading S3 Inventory %s generated %s (%d files)\n",
+		source.ConfigID, source.Generated.Format(time.RFC3339), source.Files)
+
+	processedCount := int64(0)
+	page := make([]types.ObjectMetadata, 0, inventoryPageSize)
+	flush := func() error {
+		if len(page) == 0 {
+			return nil
+		}
+		err := fn(page)
+		page = make([]types.ObjectMetadata, 0, inventoryPageSize)
+		return err
+	}
+	// add records one inventory row and reports whether the limit was hit
+	add := func(obj types.ObjectMetadata) (bool, error) {
+		if !ba.scope.contains(obj.Key) {
+			return false, nil
+		}
+		if ba.limit > 0 && processedCount >= ba.limit {
+			summary.Truncated = true
+			return true, nil
+		}
+
+		countObject(summary, obj)
+		page = append(page, obj)
+		processedCount++
+		if len(page) >= inventoryPageSize {
+			return false, flush()
+		}
+		return false, nil
+	}
+
+	for _, file := range report.manifest.Files {
+		data, err := ba.getInventoryObject(ctx, source.Bucket, file.Key)
+		if err != nil {
+			return true, fmt.Errorf("failed to read inventory file %s: %w", file.Key, err)
+		}
+
+		var done bool
+		switch source.Format {
+		case "CSV":
+			done, err = readInventoryCSV(data, report.manifest.FileSchema, add)
+		case "Parquet":
+			done, err = readInventoryParquet(data, add)
+		}
+		if err != nil {
+			return true, fmt.Errorf("failed to parse inventory file %s: %w", file.Key, err)
+		}
+		if done {
+			break
+		}
+	}
+	if err := flush(); err != nil {
+		return true, err
+	}
+
+	summary.Inventory = &source
+	return true, nil
+}
+
+// findInventoryReport returns the newest report among the bucket's usable
+// inventory configurations, or nil when there is none
+func (ba *BucketAnalyzer) findInventoryReport(ctx context.Context, bucketName string) (*inventoryReport, error) {
+	inventories, err := listInventories(ctx, ba.s3Client, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *inventoryReport
+	for _, inv := range inventories {
+		if !usableInventory(inv) {
+			continue
+		}
+		report, err := ba.latestInventoryReport(ctx, bucketName, inv)
+		if err != nil {
+			return nil, fmt.Errorf("inventory %s: %w", inv.ID, err)
+		}
+		if report != nil && (latest == nil || report.source.Generated.After(latest.source.Generated)) {
+			latest = report
+		}
+	}
+	return latest, nil
+}
+
+// usableInventory reports whether an inventory configuration lists every
+// current object with the fields the profiler needs. ORC reports are not
+// read.
+func usableInventory(inv types.InventoryConfig) bool {
+	if !inv.Enabled || inv.Prefix != "" || inv.IncludedVersions != "Current" || inv.DestinationBucket == "" {
+		return false
+	}
+	if inv.Format != "CSV" && inv.Format != "Parquet" {
+		return false
+	}
+	for _, field := range inventoryRequiredFields {
+		if !slices.Contains(inv.OptionalFields, field) {
+			return false
+		}
+	}
+	return true
+}
+
+// latestInventoryReport finds the newest delivered report of an inventory
+// configuration. Reports are delivered to
+// <prefix>/<source bucket>/<config ID>/<date>/manifest.json; folders
+// without a manifest are still being written.
+func (ba *BucketAnalyzer) latestInventoryReport(ctx context.Context, bucketName string, inv types.InventoryConfig) (*inventoryReport, error) {
+	base := strings.TrimSuffix(inv.DestinationPrefix, "/")
+	if base != "" {
+		base += "/"
+	}
+	base += bucketName + "/" + inv.ID + "/"
+
+	var folders []string
+	paginator := s3.NewListObjectsV2Paginator(ba.s3Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(inv.DestinationBucket),
+		Prefix:    aws.String(base),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.CommonPrefixes {
+			folder := strings.TrimSuffix(strings.TrimPrefix(aws.ToString(p.Prefix), base), "/")
+			if _, err := time.Parse(inventoryFolderLayout, folder); err == nil {
+				folders = append(folders, folder)
+			}
+		}
+	}
+	// The layout sorts chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(folders)))
+
+	for _, folder := range folders {
+		key := base + folder + "/manifest.json"
+		data, err := ba.getInventoryObject(ctx, inv.DestinationBucket, key)
+		if err != nil {
+			if isErrorCode(err, "NoSuchKey") {
+				continue
+			}
+			return nil, err
+		}
+
+		var manifest inventoryManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %w", key, err)
+		}
+		if manifest.FileFormat != inv.Format {
+			return nil, fmt.Errorf("manifest %s has format %q, expected %q", key, manifest.FileFormat, inv.Format)
+		}
+
+		generated, _ := time.Parse(inventoryFolderLayout, folder)
+		if ms, err := strconv.ParseInt(manifest.CreationTimestamp, 10, 64); err == nil {
+			generated = time.UnixMilli(ms)
+		}
+
+		source := types.InventorySource{
+			ConfigID:  inv.ID,
+			Bucket:    inv.DestinationBucket,
+			Manifest:  key,
+			Format:    inv.Format,
+			Generated: generated.UTC(),
+			Files:     len(manifest.Files),
+		}
+		for _, file := range manifest.Files {
+			source.Size += file.Size
+		}
+		return &inventoryReport{source: source, manifest: manifest}, nil
+	}
+
+	return nil, nil
+}
+
+// getInventoryObject downloads a whole manifest or inventory file
+func (ba *BucketAnalyzer) getInventoryObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	result, err := ba.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+	return io.ReadAll(result.Body)
+}
+
+// readInventoryCSV parses a gzipped CSV inventory file whose columns are
+// named by the manifest's fileSchema, passing each row to add until it
+// reports the limit was reached
+func readInventoryCSV(data []byte, schema string, add func(types.ObjectMetadata) (bool, error)) (bool, error) {
+	columns := make(map[string]int)
+	for i, name := range strings.Split(schema, ",") {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range append([]string{"Key"}, inventoryRequiredFields...) {
+		if _, exists := columns[name]; !exists {
+			return false, fmt.Errorf("fileSchema has no %s column", name)
+		}
+	}
+	etagColumn, hasETag := columns["ETag"]
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	defer zr.Close()
+
+	r := csv.NewReader(zr)
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if len(record) < len(columns) {
+			return false, fmt.Errorf("row has %d columns, expected %d", len(record), len(columns))
+		}
+
+		// Keys are URL-encoded
+		key, err := url.QueryUnescape(record[columns["Key"]])
+		if err != nil {
+			return false, fmt.Errorf("invalid key %q: %w", record[columns["Key"]], err)
+		}
+		// Delete markers have no size
+		sizeField := record[columns["Size"]]
+		if sizeField == "" {
+			continue
+		}
+		size, err := strconv.ParseInt(sizeField, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid size %q for %s", sizeField, key)
+		}
+		lastModified, err := time.Parse(time.RFC3339, record[columns["LastModifiedDate"]])
+		if err != nil {
+			return false, fmt.Errorf("invalid last modified date for %s: %w", key, err)
+		}
+
+		obj := types.ObjectMetadata{
+			Key:          key,
+			Size:         size,
+			LastModified: lastModified,
+			StorageClass: inventoryStorageClass(record[columns["StorageClass"]]),
+		}
+		if hasETag {
+			obj.ETag = inventoryETag(record[etagColumn])
+		}
+		if done, err := add(obj); done || err != nil {
+			return done, err
+		}
+	}
+}
+
+// readInventoryParquet parses a Parquet inventory file, passing each row to
+// add until it reports the limit was reached
+func readInventoryParquet(data []byte, add func(types.ObjectMetadata) (bool, error)) (bool, error) {
+	columns, elements, err := readParquetColumns(data, []string{"key", "size", "last_modified_date", "storage_class", "e_tag"})
+	if err != nil {
+		return false, err
+	}
+	for _, name := range []string{"key", "size", "last_modified_date", "storage_class"} {
+		if _, exists := elements[name]; !exists {
+			return false, fmt.Errorf("file has no %s column", name)
+		}
+	}
+
+	keys := columns["key"]
+	for _, name := range []string{"size", "last_modified_date", "storage_class", "e_tag"} {
+		if _, exists := elements[name]; exists && len(columns[name]) != len(keys) {
+			return false, errors.New("columns have different row counts")
+		}
+	}
+
+	dates := columns["last_modified_date"]
+	toTime := parquetTimestamp(elements["last_modified_date"])
+	for i, key := range keys {
+		size := columns["size"][i]
+		// Delete markers have no size
+		if key.null || size.null {
+			continue
+		}
+
+		obj := types.ObjectMetadata{
+			Key:          string(key.bytes),
+			Size:         size.i64,
+			StorageClass: inventoryStorageClass(string(columns["storage_class"][i].bytes)),
+		}
+		if !dates[i].null {
+			obj.LastModified = toTime(dates[i].i64)
+		}
+		if etags := columns["e_tag"]; etags != nil && !etags[i].null {
+			obj.ETag = inventoryETag(string(etags[i].bytes))
+		}
+		if done, err := add(obj); done || err != nil {
+			return done, err
+		}
+	}
+	return false, nil
+}
+
+// parquetTimestamp returns the conversion of an INT64 timestamp column's
+// values, which inventories write in milliseconds
+func parquetTimestamp(element parquetSchemaElement) func(int64) time.Time {
+	unit := element.timeUnit
+	switch element.convertedType {
+	case parquetConvertedTimestampMillis:
+		unit = "ms"
+	case parquetConvertedTimestampMicros:
+		unit = "us"
+	}
+	switch unit {
+	case "us":
+		return func(v int64) time.Time { return time.UnixMicro(v).UTC() }
+	case "ns":
+		return func(v int64) time.Time { return time.Unix(0, v).UTC() }
+	}
+	return func(v int64) time.Time { return time.UnixMilli(v).UTC() }
+}
+
+// inventoryStorageClass defaults an empty storage class to STANDARD, as
+// walkObjects does
+func inventoryStorageClass(class string) string {
+	if class == "" {
+		return "STANDARD"
+	}
+	return class
+}
+
+// inventoryETag quotes an inventory ETag the way ListObjectsV2 returns it
+func inventoryETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, `"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
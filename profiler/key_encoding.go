@@ -0,0 +1,118 @@
+package profiler
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// maxKeyEncodingExamples is the number of example keys kept per issue
+const maxKeyEncodingExamples = 5
+
+// Key encoding issue names, in report order
+const (
+	keyIssueNUL         = "NUL bytes"
+	keyIssueControl     = "Control characters"
+	keyIssueInvalidUTF8 = "Invalid UTF-8"
+	keyIssueURLEncoded  = "URL-encoded sequences"
+	keyIssueWhitespace  = "Leading or trailing whitespace in a path segment"
+)
+
+var keyIssueOrder = []string{keyIssueNUL, keyIssueControl, keyIssueInvalidUTF8, keyIssueURLEncoded, keyIssueWhitespace}
+
+// urlEncodedPattern matches percent-encoded bytes such as %20 or %2F
+var urlEncodedPattern = regexp.MustCompile(`%[0-9A-Fa-f]{2}`)
+
+// AnalyzeKeyEncodings finds keys that commonly break catalogs and ingestion
+// tools. A key may appear under several issues.
+func (ma *MetadataAnalyzer) AnalyzeKeyEncodings(objects []types.ObjectMetadata) []types.KeyEncodingIssue {
+	found := make(map[string]*types.KeyEncodingIssue)
+
+	for _, obj := range objects {
+		for _, issue := range keyEncodingIssues(obj.Key) {
+			entry, exists := found[issue]
+			if !exists {
+				entry = &types.KeyEncodingIssue{Issue: issue}
+				found[issue] = entry
+			}
+			entry.Count++
+			if len(entry.Examples) < maxKeyEncodingExamples {
+				entry.Examples = append(entry.Examples, obj.Key)
+			}
+		}
+	}
+
+	var issues []types.KeyEncodingIssue
+	for _, issue := range keyIssueOrder {
+		if entry, exists := found[issue]; exists {
+			issues = append(issues, *entry)
+		}
+	}
+	return issues
+}
+
+// SuggestKeyRenames proposes a clean key for every key with an encoding
+// issue; keys whose cleaned form is unchanged are skipped
+func (ma *MetadataAnalyzer) SuggestKeyRenames(objects []types.ObjectMetadata) []types.KeyRename {
+	var renames []types.KeyRename
+	for _, obj := range objects {
+		if len(keyEncodingIssues(obj.Key)) == 0 {
+			continue
+		}
+		if clean := cleanKey(obj.Key); clean != obj.Key && clean != "" {
+			renames = append(renames, types.KeyRename{From: obj.Key, To: clean})
+		}
+	}
+	return renames
+}
+
+// keyEncodingIssues lists the issues found in a key
+func keyEncodingIssues(key string) []string {
+	var issues []string
+
+	if strings.IndexByte(key, 0) >= 0 {
+		issues = append(issues, keyIssueNUL)
+	}
+	if strings.IndexFunc(key, func(r rune) bool { return r != 0 && unicode.IsControl(r) }) >= 0 {
+		issues = append(issues, keyIssueControl)
+	}
+	if !utf8.ValidString(key) {
+		issues = append(issues, keyIssueInvalidUTF8)
+	}
+	if urlEncodedPattern.MatchString(key) {
+		issues = append(issues, keyIssueURLEncoded)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment != strings.TrimSpace(segment) {
+			issues = append(issues, keyIssueWhitespace)
+			break
+		}
+	}
+
+	return issues
+}
+
+// cleanKey decodes percent-encoding, replaces invalid UTF-8, drops control
+// characters and trims whitespace around path segments
+func cleanKey(key string) string {
+	if decoded, err := url.PathUnescape(key); err == nil {
+		key = decoded
+	}
+	key = strings.ToValidUTF8(key, "_")
+	key = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, key)
+
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = strings.TrimSpace(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,150 @@
+package profiler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// lifecycleStep is a transition lifecycle recommendations can include
+type lifecycleStep struct {
+	days  int
+	class string
+	// archive classes need a restore before objects can be read
+	archive bool
+	// transitionPerThousand is the one-time lifecycle transition fee
+	transitionPerThousand float64
+}
+
+// lifecycleSteps are the candidate transitions, by age since the last
+// write, with approximate US East transition fees
+var lifecycleSteps = []lifecycleStep{
+	{days: 30, class: "STANDARD_IA", transitionPerThousand: 0.01},
+	{days: 90, class: "GLACIER_IR", transitionPerThousand: 0.02},
+	{days: 180, class: "GLACIER", archive: true, transitionPerThousand: 0.036},
+	{days: 365, class: "DEEP_ARCHIVE", archive: true, transitionPerThousand: 0.05},
+}
+
+// stepCost is the monthly storage cost of an object after a transition,
+// including the per-object overhead of the archive classes
+func (s lifecycleStep) stepCost(prices storagePrices, size int64) float64 {
+	cost := prices.cost(s.class, size)
+	if s.archive {
+		cost += prices.cost(s.class, archiveIndexOverhead) + prices.cost("STANDARD", archiveMetadataOverhead)
+	}
+	return cost
+}
+
+// RecommendLifecycle reports how much data of each top-level prefix and
+// storage class is older than LifecycleAgeDays, and recommends a lifecycle
+// rule per prefix. Each STANDARD or IA object of at least 128 KB (smaller
+// objects are not transitioned by default) is priced at the cheapest class
+// its age qualifies for; a prefix's rule has the transitions its objects
+// used. GLACIER and DEEP_ARCHIVE are only considered with archive, since
+// reading them needs a restore. Age since the last write stands in for
+// time since the last read, as in SimulateIntelligentTiering.
+func (ba *BucketAnalyzer) RecommendLifecycle(objects []types.ObjectMetadata, archive bool) *types.LifecycleAdvice {
+	now := time.Now()
+
+	var steps []lifecycleStep
+	for _, step := range lifecycleSteps {
+		if archive || !step.archive {
+			steps = append(steps, step)
+		}
+	}
+
+	type ageKey struct {
+		prefix string
+		class  string
+	}
+	ages := make(map[ageKey]*types.DataAge)
+	rules := make(map[string]*types.LifecycleRecommendation)
+	used := make(map[string][]bool)
+
+	for _, obj := range objects {
+		prefix := topLevelPrefix(obj.Key)
+		days := int(now.Sub(obj.LastModified).Hours() / 24)
+
+		key := ageKey{prefix, obj.StorageClass}
+		age, exists := ages[key]
+		if !exists {
+			age = &types.DataAge{
+				Prefix:       prefix,
+				StorageClass: obj.StorageClass,
+				OlderSize:    make([]int64, len(types.LifecycleAgeDays)),
+			}
+			ages[key] = age
+		}
+		age.ObjectCount++
+		age.Size += obj.Size
+		for i, threshold := range types.LifecycleAgeDays {
+			if days >= threshold {
+				age.OlderSize[i] += obj.Size
+			}
+		}
+
+		if !transitionSourceClasses[obj.StorageClass] || obj.Size < tieringMinObjectSize {
+			continue
+		}
+		current := ba.prices.cost(obj.StorageClass, obj.Size)
+		target, cost := -1, current
+		for i, step := range steps {
+			if days >= step.days && step.stepCost(ba.prices, obj.Size) < cost {
+				target, cost = i, step.stepCost(ba.prices, obj.Size)
+			}
+		}
+		if target < 0 {
+			continue
+		}
+
+		rule, exists := rules[prefix]
+		if !exists {
+			rule = &types.LifecycleRecommendation{Prefix: prefix}
+			rules[prefix] = rule
+			used[prefix] = make([]bool, len(steps))
+		}
+		rule.ObjectCount++
+		rule.Size += obj.Size
+		rule.CurrentCost += current
+		rule.NewCost += cost
+		rule.TransitionCost += steps[target].transitionPerThousand / 1000
+		used[prefix][target] = true
+	}
+
+	advice := &types.LifecycleAdvice{Archive: archive}
+	for _, age := range ages {
+		advice.Ages = append(advice.Ages, *age)
+	}
+	sort.Slice(advice.Ages, func(i, j int) bool {
+		a, b := advice.Ages[i], advice.Ages[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		if a.Prefix != b.Prefix {
+			return a.Prefix < b.Prefix
+		}
+		return a.StorageClass < b.StorageClass
+	})
+
+	for prefix, rule := range rules {
+		rule.Savings = rule.CurrentCost - rule.NewCost
+		if rule.Savings > 0 {
+			rule.PaybackMonths = rule.TransitionCost / rule.Savings
+		}
+		for i, step := range steps {
+			if used[prefix][i] {
+				rule.Transitions = append(rule.Transitions, types.LifecycleTransition{Days: step.days, StorageClass: step.class})
+			}
+		}
+		advice.Rules = append(advice.Rules, *rule)
+	}
+	sort.Slice(advice.Rules, func(i, j int) bool {
+		if advice.Rules[i].Savings != advice.Rules[j].Savings {
+			return advice.Rules[i].Savings > advice.Rules[j].Savings
+		}
+		return advice.Rules[i].Prefix < advice.Rules[j].Prefix
+	})
+
+	return advice
+}
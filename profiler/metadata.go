@@ -1,18 +1,24 @@
 package profiler
 
 import (
+	"container/heap"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/yourusername/s3-profiler/types"
 )
 
 // MetadataAnalyzer handles metadata analysis and aggregation
-type MetadataAnalyzer struct{}
+type MetadataAnalyzer struct {
+	// top is the number of largest objects and heaviest prefixes tracked
+	top int
+}
 
-// NewMetadataAnalyzer creates a new metadata analyzer
-func NewMetadataAnalyzer() *MetadataAnalyzer {
-	return &MetadataAnalyzer{}
+// NewMetadataAnalyzer creates a new metadata analyzer that tracks the top
+// largest objects and heaviest prefixes (0 disables)
+func NewMetadataAnalyzer(top int) *MetadataAnalyzer {
+	return &MetadataAnalyzer{top: top}
 }
 
 // AnalyzeMetadata performs metadata analysis on the collected objects
@@ -20,6 +26,7 @@ func (ma *MetadataAnalyzer) AnalyzeMetadata(objects []types.ObjectMetadata) *typ
 	summary := &types.MetadataSummary{
 		Objects:       objects,
 		FileTypeStats: make(map[string]int64),
+		FileTypeSizes: make(map[string]int64),
 	}
 
 	// Initialize date range
@@ -33,6 +40,7 @@ func (ma *MetadataAnalyzer) AnalyzeMetadata(objects []types.ObjectMetadata) *typ
 		// Extract file extension
 		ext := ma.getFileExtension(obj.Key)
 		summary.FileTypeStats[ext]++
+		summary.FileTypeSizes[ext] += obj.Size
 
 		// Update date range
 		if obj.LastModified.Before(summary.DateRange.Earliest) {
@@ -46,9 +54,92 @@ func (ma *MetadataAnalyzer) AnalyzeMetadata(objects []types.ObjectMetadata) *typ
 	// Generate size distribution histogram
 	summary.SizeDistribution = ma.generateSizeDistribution(objects)
 
+	summary.KeyEncoding = ma.AnalyzeKeyEncodings(objects)
+
+	if ma.top > 0 {
+		summary.Top = ma.topUsage(objects)
+	}
+
 	return summary
 }
 
+// objectHeap is a min-heap by size, keeping the largest objects seen
+type objectHeap []types.ObjectMetadata
+
+func (h objectHeap) Len() int           { return len(h) }
+func (h objectHeap) Less(i, j int) bool { return largerObject(h[j], h[i]) }
+func (h objectHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *objectHeap) Push(x any)        { *h = append(*h, x.(types.ObjectMetadata)) }
+func (h *objectHeap) Pop() any {
+	old := *h
+	obj := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return obj
+}
+
+// largerObject orders objects by size, then by key so ties are stable
+func largerObject(a, b types.ObjectMetadata) bool {
+	if a.Size != b.Size {
+		return a.Size > b.Size
+	}
+	return a.Key < b.Key
+}
+
+// topUsage finds the largest objects and the prefixes holding the most
+// bytes directly, i.e. not counting their subprefixes
+func (ma *MetadataAnalyzer) topUsage(objects []types.ObjectMetadata) *types.TopUsage {
+	largest := make(objectHeap, 0, ma.top+1)
+	prefixes := make(map[string]*types.PrefixStats)
+	for _, obj := range objects {
+		if len(largest) < ma.top || largerObject(obj, largest[0]) {
+			heap.Push(&largest, obj)
+			if len(largest) > ma.top {
+				heap.Pop(&largest)
+			}
+		}
+
+		prefix := parentPrefix(obj.Key)
+		stats, exists := prefixes[prefix]
+		if !exists {
+			stats = &types.PrefixStats{Prefix: prefix}
+			prefixes[prefix] = stats
+		}
+		stats.ObjectCount++
+		stats.Size += obj.Size
+	}
+
+	top := &types.TopUsage{Objects: []types.ObjectMetadata(largest)}
+	sort.Slice(top.Objects, func(i, j int) bool {
+		return largerObject(top.Objects[i], top.Objects[j])
+	})
+
+	for _, stats := range prefixes {
+		top.Prefixes = append(top.Prefixes, *stats)
+	}
+	sort.Slice(top.Prefixes, func(i, j int) bool {
+		a, b := top.Prefixes[i], top.Prefixes[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Prefix < b.Prefix
+	})
+	if len(top.Prefixes) > ma.top {
+		top.Prefixes = top.Prefixes[:ma.top]
+	}
+
+	return top
+}
+
+// parentPrefix returns the prefix up to the key's last "/", or "/" for keys
+// at the bucket root
+func parentPrefix(key string) string {
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return "/"
+	}
+	return key[:i+1]
+}
+
 // getFileExtension extracts the file extension from an object key
 func (ma *MetadataAnalyzer) getFileExtension(key string) string {
 	// Get the base filename
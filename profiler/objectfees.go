@@ -0,0 +1,107 @@
+package profiler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Each GLACIER and DEEP_ARCHIVE object is billed for 32 KB of index data at
+// the archive rate and 8 KB of metadata at the STANDARD rate on top of its
+// own size
+const (
+	archiveIndexOverhead    = 32 << 10
+	archiveMetadataOverhead = 8 << 10
+)
+
+// archiveClasses carry the per-object archive overhead
+var archiveClasses = []string{"GLACIER", "DEEP_ARCHIVE"}
+
+// transitionSourceClasses are classes whose objects are commonly moved to
+// Intelligent-Tiering or an archive class by lifecycle rules
+var transitionSourceClasses = map[string]bool{
+	"STANDARD":    true,
+	"STANDARD_IA": true,
+	"ONEZONE_IA":  true,
+}
+
+// CheckObjectFees flags top-level prefixes whose per-object fees cost more
+// than storing their bytes: Intelligent-Tiering monitoring and the archive
+// overhead of GLACIER and DEEP_ARCHIVE. Objects already in those classes
+// are checked as they are; objects in STANDARD and the IA classes are
+// checked as if a lifecycle rule moved them. It also returns the monthly
+// per-object fees of objects already in those classes, which the storage
+// cost estimate leaves out.
+func (ba *BucketAnalyzer) CheckObjectFees(objects []types.ObjectMetadata) ([]types.ObjectFeeWarning, float64) {
+	type group struct {
+		prefix  string
+		class   string
+		current bool
+	}
+	groups := make(map[group]*types.ObjectFeeWarning)
+	add := func(g group, size int64, data, overhead float64) {
+		w, exists := groups[g]
+		if !exists {
+			w = &types.ObjectFeeWarning{Prefix: g.prefix, StorageClass: g.class, Current: g.current}
+			groups[g] = w
+		}
+		w.ObjectCount++
+		w.Size += size
+		w.DataCost += data
+		w.OverheadCost += overhead
+	}
+
+	now := time.Now()
+	var actual float64
+	for _, obj := range objects {
+		prefix := topLevelPrefix(obj.Key)
+		current := obj.StorageClass
+
+		if current == "INTELLIGENT_TIERING" || transitionSourceClasses[current] {
+			// Objects under 128 KB are neither monitored nor transitioned
+			if obj.Size >= tieringMinObjectSize {
+				// Priced at the tier the object's age would put it in, as
+				// in SimulateIntelligentTiering
+				data := float64(obj.Size) / (1024 * 1024 * 1024) * tieringPerGB(now.Sub(obj.LastModified))
+				fee := tieringMonitoringPerThousand / 1000
+				add(group{prefix, "INTELLIGENT_TIERING", current == "INTELLIGENT_TIERING"}, obj.Size, data, fee)
+				if current == "INTELLIGENT_TIERING" {
+					actual += fee
+				}
+			}
+		}
+
+		for _, class := range archiveClasses {
+			if current != class && !transitionSourceClasses[current] {
+				continue
+			}
+			overhead := ba.prices.cost(class, archiveIndexOverhead) + ba.prices.cost("STANDARD", archiveMetadataOverhead)
+			add(group{prefix, class, current == class}, obj.Size, ba.prices.cost(class, obj.Size), overhead)
+			if current == class {
+				actual += overhead
+			}
+		}
+	}
+
+	var warnings []types.ObjectFeeWarning
+	for _, w := range groups {
+		if w.OverheadCost > w.DataCost {
+			warnings = append(warnings, *w)
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Current != warnings[j].Current {
+			return warnings[i].Current
+		}
+		if warnings[i].OverheadCost != warnings[j].OverheadCost {
+			return warnings[i].OverheadCost > warnings[j].OverheadCost
+		}
+		if warnings[i].Prefix != warnings[j].Prefix {
+			return warnings[i].Prefix < warnings[j].Prefix
+		}
+		return warnings[i].StorageClass < warnings[j].StorageClass
+	})
+
+	return warnings, actual
+}
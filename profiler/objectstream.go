@@ -0,0 +1,86 @@
+package profiler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/yourusername/s3-profiler/kafka"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// StreamedObject is the JSON value of each message sent by an
+// ObjectStreamer
+type StreamedObject struct {
+	Bucket       string    `json:"bucket"`
+	Region       string    `json:"region"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	StorageClass string    `json:"storage_class"`
+	ETag         string    `json:"etag"`
+	ListedAt     time.Time `json:"listed_at"`
+}
+
+// ObjectStreamer sends each listed object to a Kafka topic, keyed by
+// bucket/key so updates to one object stay on one partition
+type ObjectStreamer struct {
+	target string
+	writer *kafka.Writer
+}
+
+// NewObjectStreamer creates a streamer for kafka://broker[,broker...]/topic
+// (kafka+tls:// for TLS); an empty target disables streaming
+func NewObjectStreamer(target string) (*ObjectStreamer, error) {
+	s := &ObjectStreamer{target: target}
+	if target == "" {
+		return s, nil
+	}
+
+	config, err := kafka.ParseURL(target)
+	if err != nil {
+		return nil, err
+	}
+	s.writer = kafka.NewWriter(config)
+	return s, nil
+}
+
+// Enabled reports whether a stream target was configured
+func (s *ObjectStreamer) Enabled() bool {
+	return s != nil && s.writer != nil
+}
+
+// Send streams one page of listed objects
+func (s *ObjectStreamer) Send(ctx context.Context, bucketName, region string, page []types.ObjectMetadata) error {
+	listedAt := time.Now().UTC()
+	messages := make([]kafka.Message, 0, len(page))
+	for _, obj := range page {
+		value, err := json.Marshal(StreamedObject{
+			Bucket:       bucketName,
+			Region:       region,
+			Key:          obj.Key,
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+			StorageClass: obj.StorageClass,
+			ETag:         obj.ETag,
+			ListedAt:     listedAt,
+		})
+		if err != nil {
+			return err
+		}
+		messages = append(messages, kafka.Message{
+			Key:     []byte(bucketName + "/" + obj.Key),
+			Value:   value,
+			Headers: []kafka.Header{{Key: "content-type", Value: []byte("application/json")}},
+		})
+	}
+	return s.writer.WriteMessages(ctx, messages...)
+}
+
+// Close releases the streamer's broker connections
+func (s *ObjectStreamer) Close() error {
+	if s.Enabled() {
+		return s.writer.Close()
+	}
+	return nil
+}
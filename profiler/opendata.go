@@ -0,0 +1,46 @@
+package profiler
+
+import (
+	"regexp"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// openDataRegistry is the base URL of AWS Open Data registry entries
+const openDataRegistry = "https://registry.opendata.aws/"
+
+// openDataBuckets are curated buckets of the AWS Open Data registry, by
+// bucket name. Layouts refer to openDataLayouts.
+var openDataBuckets = map[string]types.OpenDataset{
+	"noaa-gfs-bdp-pds": {Name: "NOAA Global Forecast System (GFS)", Registry: openDataRegistry + "noaa-gfs-bdp-pds/", Region: "us-east-1", Layout: "gfs.YYYYMMDD"},
+	"noaa-goes16":      {Name: "NOAA GOES-16", Registry: openDataRegistry + "noaa-goes/", Region: "us-east-1"},
+	"noaa-goes18":      {Name: "NOAA GOES-18", Registry: openDataRegistry + "noaa-goes/", Region: "us-east-1"},
+	"noaa-ghcn-pds":    {Name: "NOAA Global Historical Climatology Network Daily", Registry: openDataRegistry + "noaa-ghcn/", Region: "us-east-1"},
+	"era5-pds":         {Name: "ECMWF ERA5 Reanalysis", Registry: openDataRegistry + "ecmwf-era5/", Region: "us-east-1"},
+	"sentinel-cogs":    {Name: "Sentinel-2 Cloud-Optimized GeoTIFFs", Registry: openDataRegistry + "sentinel-2-l2a-cogs/", Region: "us-west-2", Layout: "UTM/LAT/SQ/YYYY/M/scene"},
+	"sentinel-s2-l1c":  {Name: "Sentinel-2 Level-1C", Registry: openDataRegistry + "sentinel-2/", Region: "eu-central-1", RequesterPays: true, Layout: "tiles/UTM/LAT/SQ/YYYY/M/D"},
+	"sentinel-s2-l2a":  {Name: "Sentinel-2 Level-2A", Registry: openDataRegistry + "sentinel-2/", Region: "eu-central-1", RequesterPays: true, Layout: "tiles/UTM/LAT/SQ/YYYY/M/D"},
+	"usgs-landsat":     {Name: "USGS Landsat Collection 2", Registry: openDataRegistry + "usgs-landsat/", Region: "us-west-2", RequesterPays: true},
+	"nyc-tlc":          {Name: "NYC Taxi and Limousine Commission Trip Records", Registry: openDataRegistry + "nyc-tlc-trip-records-pds/", Region: "us-east-1", Layout: "*_tripdata_YYYY-MM"},
+	"commoncrawl":      {Name: "Common Crawl", Registry: openDataRegistry + "commoncrawl/", Region: "us-east-1"},
+}
+
+// openDataLayouts are date layouts of known datasets that the generic
+// patterns miss, such as unpadded months or dates fused with a product
+// name. The groups are year, month and optionally day, as in datePatterns.
+var openDataLayouts = map[string]datePattern{
+	"gfs.YYYYMMDD":              {"gfs.YYYYMMDD", regexp.MustCompile(`gfs\.(\d{4})(\d{2})(\d{2})/`), 0, false},
+	"UTM/LAT/SQ/YYYY/M/scene":   {"UTM/LAT/SQ/YYYY/M/scene", regexp.MustCompile(`/(\d{4})/(\d{1,2})/S2`), 0, false},
+	"tiles/UTM/LAT/SQ/YYYY/M/D": {"tiles/UTM/LAT/SQ/YYYY/M/D", regexp.MustCompile(`/(\d{4})/(\d{1,2})/(\d{1,2})/`), 0, false},
+	"*_tripdata_YYYY-MM":        {"*_tripdata_YYYY-MM", regexp.MustCompile(`_tripdata_(\d{4})-(\d{2})`), 0, false},
+}
+
+// LookupOpenDataset returns the curated registry entry of a bucket, or nil
+// when the bucket is not a known open dataset
+func LookupOpenDataset(bucketName string) *types.OpenDataset {
+	dataset, ok := openDataBuckets[bucketName]
+	if !ok {
+		return nil
+	}
+	return &dataset
+}
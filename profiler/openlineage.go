@@ -0,0 +1,206 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// OpenLineage identifiers
+const (
+	openLineageProducer    = "https://github.com/yourusername/s3-profiler"
+	openLineageSpec        = "https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/RunEvent"
+	schemaFacetURL         = "https://openlineage.io/spec/facets/1-1-1/SchemaDatasetFacet.json#/$defs/SchemaDatasetFacet"
+	storageFacetURL        = "https://openlineage.io/spec/facets/1-0-0/StorageDatasetFacet.json#/$defs/StorageDatasetFacet"
+	qualityMetricsFacetURL = "https://openlineage.io/spec/facets/1-0-2/DataQualityMetricsInputDatasetFacet.json#/$defs/DataQualityMetricsInputDatasetFacet"
+
+	// DefaultOpenLineageNamespace is the job namespace used when none is
+	// configured
+	DefaultOpenLineageNamespace = "s3-profiler"
+)
+
+// OpenLineageEmitter posts one OpenLineage COMPLETE run event per profiled
+// bucket to a lineage backend such as Marquez. Each dataset is an input
+// carrying schema, storage and dataQualityMetrics facets.
+type OpenLineageEmitter struct {
+	url       string
+	namespace string
+	apiKey    string
+	client    *http.Client
+}
+
+// NewOpenLineageEmitter creates an emitter posting to url; an empty url
+// disables emission
+func NewOpenLineageEmitter(url, namespace, apiKey string) *OpenLineageEmitter {
+	if namespace == "" {
+		namespace = DefaultOpenLineageNamespace
+	}
+	return &OpenLineageEmitter{
+		url:       url,
+		namespace: namespace,
+		apiKey:    apiKey,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Enabled reports whether a lineage endpoint was configured
+func (e *OpenLineageEmitter) Enabled() bool {
+	return e.url != ""
+}
+
+// openLineageEvent is an OpenLineage RunEvent
+type openLineageEvent struct {
+	EventType string           `json:"eventType"`
+	EventTime time.Time        `json:"eventTime"`
+	Producer  string           `json:"producer"`
+	SchemaURL string           `json:"schemaURL"`
+	Run       openLineageRun   `json:"run"`
+	Job       openLineageJob   `json:"job"`
+	Inputs    []lineageDataset `json:"inputs"`
+	Outputs   []lineageDataset `json:"outputs"`
+}
+
+type openLineageRun struct {
+	RunID string `json:"runId"`
+}
+
+type openLineageJob struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// lineageDataset is a dataset with its facets
+type lineageDataset struct {
+	Namespace   string         `json:"namespace"`
+	Name        string         `json:"name"`
+	Facets      map[string]any `json:"facets"`
+	InputFacets map[string]any `json:"inputFacets,omitempty"`
+}
+
+// Emit sends the run event for a bucket's datasets
+func (e *OpenLineageEmitter) Emit(ctx context.Context, bucketName string, cards []types.DataCard) error {
+	runID, err := newUUID()
+	if err != nil {
+		return err
+	}
+
+	event := openLineageEvent{
+		EventType: "COMPLETE",
+		EventTime: time.Now().UTC(),
+		Producer:  openLineageProducer,
+		SchemaURL: openLineageSpec,
+		Run:       openLineageRun{RunID: runID},
+		Job:       openLineageJob{Namespace: e.namespace, Name: "s3-profiler." + bucketName},
+		Outputs:   []lineageDataset{},
+	}
+	for _, card := range cards {
+		event.Inputs = append(event.Inputs, lineageInput(card))
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send OpenLineage event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("OpenLineage backend returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// lineageInput describes a dataset following the OpenLineage S3 naming
+// convention: namespace s3://bucket, name is the path within it
+func lineageInput(card types.DataCard) lineageDataset {
+	name := strings.TrimSuffix(card.Scope, "/")
+	if name == "" {
+		name = "/"
+	}
+
+	dataset := lineageDataset{
+		Namespace: "s3://" + card.Bucket,
+		Name:      name,
+		Facets: map[string]any{
+			"storage": map[string]any{
+				"_producer":    openLineageProducer,
+				"_schemaURL":   storageFacetURL,
+				"storageLayer": "s3",
+				"fileFormat":   datasetFormat(card),
+			},
+		},
+		InputFacets: map[string]any{
+			"dataQualityMetrics": map[string]any{
+				"_producer":     openLineageProducer,
+				"_schemaURL":    qualityMetricsFacetURL,
+				"bytes":         card.TotalSize,
+				"fileCount":     card.ObjectCount,
+				"columnMetrics": map[string]any{},
+			},
+		},
+	}
+
+	if card.Schema != nil {
+		fields := make([]map[string]string, 0, len(card.Schema.Columns))
+		for _, column := range card.Schema.Columns {
+			field := map[string]string{"name": column.Name}
+			if column.Type != "" {
+				field["type"] = column.Type
+			}
+			fields = append(fields, field)
+		}
+		dataset.Facets["schema"] = map[string]any{
+			"_producer":  openLineageProducer,
+			"_schemaURL": schemaFacetURL,
+			"fields":     fields,
+		}
+	}
+
+	return dataset
+}
+
+// datasetFormat returns the sampled format, or the most common file
+// extension
+func datasetFormat(card types.DataCard) string {
+	if card.Schema != nil {
+		return card.Schema.Format
+	}
+	format, top := "", int64(0)
+	for ext, count := range card.Formats {
+		if count > top || (count == top && ext < format) {
+			format, top = ext, count
+		}
+	}
+	return format
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,77 @@
+package profiler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// AccountIDFunc returns the AWS account of the profiling credentials
+type AccountIDFunc func(ctx context.Context) (string, error)
+
+// checkOwnership verifies that the bucket belongs to the credentials'
+// account. S3 rejects a HeadBucket whose ExpectedBucketOwner does not match
+// with 403, so a bucket that answers a plain HeadBucket but rejects the
+// expected owner belongs to another account. It returns nil when no account
+// lookup is configured or the account cannot be determined.
+func (ba *BucketAnalyzer) checkOwnership(ctx context.Context, bucketName string) *types.BucketOwnership {
+	if ba.accountID == nil {
+		return nil
+	}
+	account, err := ba.accountID(ctx)
+	if err != nil {
+		ba.progress.Warnf("cannot verify bucket ownership: %v", err)
+		return nil
+	}
+
+	ownership := &types.BucketOwnership{CallerAccount: account}
+	_, err = ba.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket:              aws.String(bucketName),
+		ExpectedBucketOwner: aws.String(account),
+	})
+	switch {
+	case err == nil:
+		ownership.Verified = true
+	case isHTTPStatus(err, http.StatusForbidden):
+		// A 403 is also what missing s3:ListBucket looks like
+		if _, plainErr := ba.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+			Bucket: aws.String(bucketName),
+		}); plainErr == nil {
+			ownership.Verified = true
+			ownership.Foreign = true
+		}
+	default:
+		ba.progress.Warnf("cannot verify bucket ownership: %v", err)
+	}
+
+	if ownership.Foreign {
+		ba.progress.Warnf("bucket %s belongs to another AWS account than %s; its storage cost is billed to the owner", bucketName, account)
+		return ownership
+	}
+
+	result, err := ba.s3Client.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		// Buckets created before Object Ownership existed have no
+		// controls and keep the writer as object owner
+		if isErrorCode(err, "OwnershipControlsNotFoundError") {
+			ownership.ObjectOwnership = "ObjectWriter"
+		}
+	} else if result.OwnershipControls != nil && len(result.OwnershipControls.Rules) > 0 {
+		ownership.ObjectOwnership = string(result.OwnershipControls.Rules[0].ObjectOwnership)
+	}
+
+	return ownership
+}
+
+// isHTTPStatus reports whether an AWS error carries the given HTTP status
+func isHTTPStatus(err error, status int) bool {
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == status
+}
@@ -0,0 +1,612 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/sampler"
+)
+
+// Parquet physical types
+const (
+	parquetBoolean           = 0
+	parquetInt32             = 1
+	parquetInt64             = 2
+	parquetInt96             = 3
+	parquetFloat             = 4
+	parquetDouble            = 5
+	parquetByteArray         = 6
+	parquetFixedLenByteArray = 7
+)
+
+// Parquet converted types that change how statistics are rendered
+const (
+	parquetConvertedDate            = 6
+	parquetConvertedTimestampMillis = 9
+	parquetConvertedTimestampMicros = 10
+)
+
+const (
+	parquetMagic = "PAR1"
+
+	// parquetTailSize is read first; it holds the whole footer of most files
+	parquetTailSize = 64 * 1024
+
+	// parquetMaxFooterSize guards against corrupt footer lengths
+	parquetMaxFooterSize = 64 * 1024 * 1024
+
+	// parquetMaxValueLength truncates rendered min/max values
+	parquetMaxValueLength = 40
+)
+
+// Parquet compression codecs, by CompressionCodec value
+var parquetCodecNames = map[int32]string{
+	0: "UNCOMPRESSED",
+	1: "SNAPPY",
+	2: "GZIP",
+	3: "LZO",
+	4: "BROTLI",
+	5: "LZ4",
+	6: "ZSTD",
+	7: "LZ4_RAW",
+}
+
+// Parquet field repetitions
+var parquetRepetitionNames = map[int32]string{
+	0: "REQUIRED",
+	1: "OPTIONAL",
+	2: "REPEATED",
+}
+
+// parquetConvertedNames are the legacy ConvertedType annotations, used when
+// a file predates logical types
+var parquetConvertedNames = map[int32]string{
+	0:  "STRING",
+	1:  "MAP",
+	2:  "MAP_KEY_VALUE",
+	3:  "LIST",
+	4:  "ENUM",
+	5:  "DECIMAL",
+	6:  "DATE",
+	7:  "TIME(ms)",
+	8:  "TIME(us)",
+	9:  "TIMESTAMP(ms)",
+	10: "TIMESTAMP(us)",
+	11: "UINT_8",
+	12: "UINT_16",
+	13: "UINT_32",
+	14: "UINT_64",
+	15: "INT_8",
+	16: "INT_16",
+	17: "INT_32",
+	18: "INT_64",
+	19: "JSON",
+	20: "BSON",
+	21: "INTERVAL",
+}
+
+// parquetLogicalNames are the LogicalType union members, by field id
+var parquetLogicalNames = map[int16]string{
+	1:  "STRING",
+	2:  "MAP",
+	3:  "LIST",
+	4:  "ENUM",
+	5:  "DECIMAL",
+	6:  "DATE",
+	7:  "TIME",
+	8:  "TIMESTAMP",
+	10: "INTEGER",
+	11: "NULL",
+	12: "JSON",
+	13: "BSON",
+	14: "UUID",
+	15: "FLOAT16",
+	16: "VARIANT",
+	17: "GEOMETRY",
+	18: "GEOGRAPHY",
+}
+
+var parquetPhysicalNames = map[int32]string{
+	parquetBoolean:           "BOOLEAN",
+	parquetInt32:             "INT32",
+	parquetInt64:             "INT64",
+	parquetInt96:             "INT96",
+	parquetFloat:             "FLOAT",
+	parquetDouble:            "DOUBLE",
+	parquetByteArray:         "BYTE_ARRAY",
+	parquetFixedLenByteArray: "FIXED_LEN_BYTE_ARRAY",
+}
+
+// parquetFooter holds the parts of a Parquet FileMetaData used for profiling
+type parquetFooter struct {
+	numRows   int64
+	rowGroups int
+	schema    []parquetSchemaElement
+	columns   []*parquetColumn
+}
+
+// parquetSchemaElement is a node of the flattened Parquet schema
+type parquetSchemaElement struct {
+	name          string
+	physical      int32
+	repetition    int32
+	numChildren   int32
+	convertedType int32
+	// logical is the LogicalType name, "" for files without one
+	logical string
+	// timeUnit is the logical TIMESTAMP unit: "ms", "us" or "ns"
+	timeUnit         string
+	date             bool
+	scale, precision int32
+}
+
+// parquetColumn is a column's statistics merged across row groups
+type parquetColumn struct {
+	path       string
+	physical   int32
+	values     int64
+	nulls      int64
+	nullsKnown bool
+	min, max   []byte
+	// minMaxKnown is cleared as soon as one row group lacks min/max
+	minMaxKnown bool
+	// codecs are the distinct compression codecs of the column's chunks
+	codecs                   []int32
+	compressed, uncompressed int64
+}
+
+// readParquetFooter fetches and decodes the footer of a Parquet object
+// using ranged reads
+func readParquetFooter(ctx context.Context, reader sampler.RangeReader, size int64) (*parquetFooter, error) {
+	if size < int64(2*len(parquetMagic)+4) {
+		return nil, errors.New("object too small to be a Parquet file")
+	}
+
+	tail, err := reader.ReadRange(ctx, -min(size, parquetTailSize), 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(tail) < 8 || string(tail[len(tail)-4:]) != parquetMagic {
+		return nil, errors.New("missing Parquet magic bytes")
+	}
+
+	footerLen := int64(binary.LittleEndian.Uint32(tail[len(tail)-8:]))
+	if footerLen > parquetMaxFooterSize || footerLen+8 > size {
+		return nil, fmt.Errorf("invalid Parquet footer length %d", footerLen)
+	}
+
+	var footer []byte
+	if footerLen+8 <= int64(len(tail)) {
+		footer = tail[int64(len(tail))-8-footerLen : len(tail)-8]
+	} else {
+		footer, err = reader.ReadRange(ctx, size-8-footerLen, footerLen)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return decodeParquetFooter(footer)
+}
+
+// s3RangeReader reads byte ranges of an S3 object
+type s3RangeReader struct {
+	client     *s3.Client
+	bucketName string
+	key        string
+}
+
+// ReadRange implements sampler.RangeReader
+func (r *s3RangeReader) ReadRange(ctx context.Context, offset, length int64) ([]byte, error) {
+	byteRange := fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	if offset < 0 {
+		byteRange = fmt.Sprintf("bytes=%d", offset)
+	}
+
+	result, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(r.key),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+
+	return io.ReadAll(result.Body)
+}
+
+// decodeParquetFooter decodes a Thrift-encoded FileMetaData
+func decodeParquetFooter(data []byte) (*parquetFooter, error) {
+	footer := &parquetFooter{}
+	columns := make(map[string]*parquetColumn)
+	r := &thriftReader{buf: data}
+
+	err := r.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 2 && typ == thriftList:
+			return r.readList(func(byte) error {
+				element, err := decodeSchemaElement(r)
+				footer.schema = append(footer.schema, element)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			v, err := r.i64()
+			footer.numRows = v
+			return err
+		case id == 4 && typ == thriftList:
+			return r.readList(func(byte) error {
+				footer.rowGroups++
+				return decodeRowGroup(r, func(c *parquetColumn) {
+					footer.columns = mergeParquetColumn(footer.columns, columns, c)
+				})
+			})
+		}
+		return r.skip(typ)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Parquet footer: %w", err)
+	}
+
+	return footer, nil
+}
+
+func decodeSchemaElement(r *thriftReader) (parquetSchemaElement, error) {
+	element := parquetSchemaElement{physical: -1, convertedType: -1}
+	err := r.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err := r.i64()
+			element.physical = int32(v)
+			return err
+		case id == 3 && typ == thriftI32:
+			v, err := r.i64()
+			element.repetition = int32(v)
+			return err
+		case id == 4 && typ == thriftBinary:
+			name, err := r.binary()
+			element.name = string(name)
+			return err
+		case id == 5 && typ == thriftI32:
+			v, err := r.i64()
+			element.numChildren = int32(v)
+			return err
+		case id == 6 && typ == thriftI32:
+			v, err := r.i64()
+			element.convertedType = int32(v)
+			return err
+		case id == 7 && typ == thriftI32:
+			v, err := r.i64()
+			element.scale = int32(v)
+			return err
+		case id == 8 && typ == thriftI32:
+			v, err := r.i64()
+			element.precision = int32(v)
+			return err
+		case id == 10 && typ == thriftStruct:
+			return decodeLogicalType(r, &element)
+		}
+		return r.skip(typ)
+	})
+	return element, err
+}
+
+// decodeLogicalType reads the LogicalType union; beyond its name, only
+// DATE and TIMESTAMP affect how statistics are rendered
+func decodeLogicalType(r *thriftReader, element *parquetSchemaElement) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		element.logical = parquetLogicalNames[id]
+		switch {
+		case id == 6 && typ == thriftStruct:
+			element.date = true
+		case id == 8 && typ == thriftStruct:
+			return r.readStruct(func(id int16, typ byte) error {
+				if id != 2 || typ != thriftStruct {
+					return r.skip(typ)
+				}
+				return r.readStruct(func(id int16, typ byte) error {
+					element.timeUnit = map[int16]string{1: "ms", 2: "us", 3: "ns"}[id]
+					return r.skip(typ)
+				})
+			})
+		}
+		return r.skip(typ)
+	})
+}
+
+func decodeRowGroup(r *thriftReader, column func(*parquetColumn)) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		if id != 1 || typ != thriftList {
+			return r.skip(typ)
+		}
+		return r.readList(func(byte) error {
+			// ColumnChunk; field 3 is the ColumnMetaData
+			return r.readStruct(func(id int16, typ byte) error {
+				if id != 3 || typ != thriftStruct {
+					return r.skip(typ)
+				}
+				c, err := decodeColumnMetaData(r)
+				if err == nil {
+					column(c)
+				}
+				return err
+			})
+		})
+	})
+}
+
+func decodeColumnMetaData(r *thriftReader) (*parquetColumn, error) {
+	c := &parquetColumn{physical: -1}
+	var path []string
+
+	err := r.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err := r.i64()
+			c.physical = int32(v)
+			return err
+		case id == 3 && typ == thriftList:
+			return r.readList(func(byte) error {
+				segment, err := r.binary()
+				path = append(path, string(segment))
+				return err
+			})
+		case id == 4 && typ == thriftI32:
+			v, err := r.i64()
+			c.codecs = []int32{int32(v)}
+			return err
+		case id == 5 && typ == thriftI64:
+			v, err := r.i64()
+			c.values = v
+			return err
+		case id == 6 && typ == thriftI64:
+			v, err := r.i64()
+			c.uncompressed = v
+			return err
+		case id == 7 && typ == thriftI64:
+			v, err := r.i64()
+			c.compressed = v
+			return err
+		case id == 12 && typ == thriftStruct:
+			return decodeStatistics(r, c)
+		}
+		return r.skip(typ)
+	})
+	c.path = strings.Join(path, ".")
+	return c, err
+}
+
+func decodeStatistics(r *thriftReader, c *parquetColumn) error {
+	var legacyMin, legacyMax, minValue, maxValue []byte
+	err := r.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftBinary:
+			legacyMax, err = r.binary()
+		case id == 2 && typ == thriftBinary:
+			legacyMin, err = r.binary()
+		case id == 3 && typ == thriftI64:
+			c.nulls, err = r.i64()
+			c.nullsKnown = true
+		case id == 5 && typ == thriftBinary:
+			maxValue, err = r.binary()
+		case id == 6 && typ == thriftBinary:
+			minValue, err = r.binary()
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+
+	// The legacy fields used signed byte ordering, which is wrong for
+	// strings; only trust them for numeric columns
+	if minValue == nil && maxValue == nil && c.physical != parquetByteArray && c.physical != parquetFixedLenByteArray {
+		minValue, maxValue = legacyMin, legacyMax
+	}
+	if minValue != nil && maxValue != nil && c.physical != parquetInt96 {
+		c.min, c.max = bytes.Clone(minValue), bytes.Clone(maxValue)
+		c.minMaxKnown = true
+	}
+	return err
+}
+
+// mergeParquetColumn merges a row group's column chunk into the file-level
+// column of the same path, appending new columns in schema order
+func mergeParquetColumn(list []*parquetColumn, byPath map[string]*parquetColumn, c *parquetColumn) []*parquetColumn {
+	existing, exists := byPath[c.path]
+	if !exists {
+		byPath[c.path] = c
+		return append(list, c)
+	}
+	mergeColumnStats(existing, c)
+	return list
+}
+
+// mergeColumnStats folds the statistics of other into c
+func mergeColumnStats(c, other *parquetColumn) {
+	c.values += other.values
+	c.compressed += other.compressed
+	c.uncompressed += other.uncompressed
+	for _, codec := range other.codecs {
+		if !slices.Contains(c.codecs, codec) {
+			c.codecs = append(c.codecs, codec)
+		}
+	}
+	c.nulls += other.nulls
+	c.nullsKnown = c.nullsKnown && other.nullsKnown
+	if !c.minMaxKnown || !other.minMaxKnown || c.physical != other.physical {
+		c.minMaxKnown = false
+		return
+	}
+	if compareParquetValues(c.physical, other.min, c.min) < 0 {
+		c.min = other.min
+	}
+	if compareParquetValues(c.physical, other.max, c.max) > 0 {
+		c.max = other.max
+	}
+}
+
+// compareParquetValues orders two plain-encoded values of a physical type
+func compareParquetValues(physical int32, a, b []byte) int {
+	switch physical {
+	case parquetInt32:
+		if len(a) >= 4 && len(b) >= 4 {
+			return cmpOrdered(int32(binary.LittleEndian.Uint32(a)), int32(binary.LittleEndian.Uint32(b)))
+		}
+	case parquetInt64:
+		if len(a) >= 8 && len(b) >= 8 {
+			return cmpOrdered(int64(binary.LittleEndian.Uint64(a)), int64(binary.LittleEndian.Uint64(b)))
+		}
+	case parquetFloat:
+		if len(a) >= 4 && len(b) >= 4 {
+			return cmpOrdered(math.Float32frombits(binary.LittleEndian.Uint32(a)), math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		}
+	case parquetDouble:
+		if len(a) >= 8 && len(b) >= 8 {
+			return cmpOrdered(float64FromBits(a), float64FromBits(b))
+		}
+	}
+	return bytes.Compare(a, b)
+}
+
+func cmpOrdered[T int32 | int64 | float32 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// leafSchema maps dotted column paths to their schema elements
+func (f *parquetFooter) leafSchema() map[string]parquetSchemaElement {
+	leaves := make(map[string]parquetSchemaElement)
+	if len(f.schema) == 0 {
+		return leaves
+	}
+
+	// The first element is the root; walk the depth-first flattened tree
+	pos := 1
+	var walk func(prefix string, children int32)
+	walk = func(prefix string, children int32) {
+		for i := int32(0); i < children && pos < len(f.schema); i++ {
+			element := f.schema[pos]
+			pos++
+			path := element.name
+			if prefix != "" {
+				path = prefix + "." + element.name
+			}
+			if element.numChildren > 0 {
+				walk(path, element.numChildren)
+			} else {
+				leaves[path] = element
+			}
+		}
+	}
+	walk("", f.schema[0].numChildren)
+
+	return leaves
+}
+
+// parquetTypeName renders a column's physical type with its logical or
+// converted annotation, such as "INT64 TIMESTAMP(us)"
+func parquetTypeName(element parquetSchemaElement, physical int32) string {
+	name := parquetPhysicalNames[physical]
+	annotation := element.logical
+	switch {
+	case annotation == "":
+		annotation = parquetConvertedNames[element.convertedType]
+		if element.convertedType == 5 {
+			annotation = fmt.Sprintf("DECIMAL(%d,%d)", element.precision, element.scale)
+		}
+	case annotation == "DECIMAL":
+		annotation = fmt.Sprintf("DECIMAL(%d,%d)", element.precision, element.scale)
+	case annotation == "TIMESTAMP" && element.timeUnit != "":
+		annotation = "TIMESTAMP(" + element.timeUnit + ")"
+	}
+	if annotation == "" {
+		return name
+	}
+	return name + " " + annotation
+}
+
+// formatParquetValue renders a plain-encoded statistics value
+func formatParquetValue(element parquetSchemaElement, physical int32, b []byte) string {
+	switch physical {
+	case parquetBoolean:
+		if len(b) >= 1 {
+			return strconv.FormatBool(b[0] != 0)
+		}
+	case parquetInt32:
+		if len(b) >= 4 {
+			v := int32(binary.LittleEndian.Uint32(b))
+			if element.date || element.convertedType == parquetConvertedDate {
+				return time.Unix(int64(v)*86400, 0).UTC().Format("2006-01-02")
+			}
+			return strconv.FormatInt(int64(v), 10)
+		}
+	case parquetInt64:
+		if len(b) >= 8 {
+			v := int64(binary.LittleEndian.Uint64(b))
+			unit := element.timeUnit
+			switch element.convertedType {
+			case parquetConvertedTimestampMillis:
+				unit = "ms"
+			case parquetConvertedTimestampMicros:
+				unit = "us"
+			}
+			switch unit {
+			case "ms":
+				return time.UnixMilli(v).UTC().Format(time.RFC3339)
+			case "us":
+				return time.UnixMicro(v).UTC().Format(time.RFC3339)
+			case "ns":
+				return time.Unix(0, v).UTC().Format(time.RFC3339)
+			}
+			return strconv.FormatInt(v, 10)
+		}
+	case parquetFloat:
+		if len(b) >= 4 {
+			return strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), 'g', -1, 32)
+		}
+	case parquetDouble:
+		if len(b) >= 8 {
+			return strconv.FormatFloat(float64FromBits(b), 'g', -1, 64)
+		}
+	case parquetByteArray, parquetFixedLenByteArray:
+		if utf8.Valid(b) && isPrintable(string(b)) {
+			return truncateValue(string(b))
+		}
+		return truncateValue("0x" + hex.EncodeToString(b))
+	}
+	return "?"
+}
+
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func truncateValue(s string) string {
+	if utf8.RuneCountInString(s) <= parquetMaxValueLength {
+		return s
+	}
+	return string([]rune(s)[:parquetMaxValueLength-3]) + "..."
+}
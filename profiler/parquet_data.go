@@ -0,0 +1,539 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Parquet page types
+const (
+	parquetDataPage       = 0
+	parquetDictionaryPage = 2
+	parquetDataPageV2     = 3
+)
+
+// Parquet value encodings understood by the column reader
+const (
+	parquetPlain           = 0
+	parquetPlainDictionary = 2
+	parquetRLEDictionary   = 8
+)
+
+// Parquet compression codecs understood by the column reader
+const (
+	parquetUncompressed = 0
+	parquetSnappy       = 1
+	parquetGzip         = 2
+	parquetZstd         = 6
+)
+
+// parquetOptional is the repetition type of nullable fields
+const parquetOptional = 1
+
+// parquetValue is one decoded cell of a column
+type parquetValue struct {
+	null  bool
+	i64   int64
+	bytes []byte
+}
+
+// parquetChunk locates a column chunk within the file
+type parquetChunk struct {
+	path       string
+	codec      int32
+	numValues  int64
+	dataOffset int64
+	dictOffset int64
+	size       int64
+}
+
+// parquetPageHeader holds the PageHeader fields the column reader needs
+type parquetPageHeader struct {
+	kind             int32
+	uncompressedSize int32
+	compressedSize   int32
+	numValues        int32
+	encoding         int32
+	// V2 data pages store levels uncompressed ahead of the values
+	defLevelsLength int32
+	repLevelsLength int32
+	compressed      bool
+}
+
+// readParquetColumns decodes the named top-level columns of a Parquet file
+// held in memory. Only flat schemas with PLAIN or dictionary encoded INT32,
+// INT64 and BYTE_ARRAY columns are supported, which covers S3 Inventory
+// reports. Columns missing from the file are left out of the result; the
+// schema elements are returned so callers can interpret timestamps.
+func readParquetColumns(data []byte, names []string) (map[string][]parquetValue, map[string]parquetSchemaElement, error) {
+	if len(data) < 12 || string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		return nil, nil, errors.New("missing Parquet magic bytes")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if footerLen+12 > int64(len(data)) {
+		return nil, nil, fmt.Errorf("invalid Parquet footer length %d", footerLen)
+	}
+	footer := data[int64(len(data))-8-footerLen : len(data)-8]
+
+	schema, rowGroups, err := decodeParquetLayout(footer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	elements := make(map[string]parquetSchemaElement)
+	for _, element := range schema {
+		elements[element.name] = element
+	}
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		element, exists := elements[name]
+		if !exists {
+			continue
+		}
+		if element.repetition > parquetOptional {
+			return nil, nil, fmt.Errorf("repeated Parquet column %s is not supported", name)
+		}
+		wanted[name] = true
+	}
+
+	columns := make(map[string][]parquetValue)
+	for _, chunks := range rowGroups {
+		for _, chunk := range chunks {
+			if !wanted[chunk.path] {
+				continue
+			}
+			values, err := readParquetChunk(data, chunk, elements[chunk.path])
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read Parquet column %s: %w", chunk.path, err)
+			}
+			columns[chunk.path] = append(columns[chunk.path], values...)
+		}
+	}
+
+	return columns, elements, nil
+}
+
+// decodeParquetLayout decodes the schema and the column chunk locations of
+// every row group from a FileMetaData
+func decodeParquetLayout(footer []byte) ([]parquetSchemaElement, [][]parquetChunk, error) {
+	var schema []parquetSchemaElement
+	var rowGroups [][]parquetChunk
+	r := &thriftReader{buf: footer}
+
+	err := r.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 2 && typ == thriftList:
+			return r.readList(func(byte) error {
+				element, err := decodeSchemaElement(r)
+				schema = append(schema, element)
+				return err
+			})
+		case id == 4 && typ == thriftList:
+			return r.readList(func(byte) error {
+				var chunks []parquetChunk
+				err := r.readStruct(func(id int16, typ byte) error {
+					if id != 1 || typ != thriftList {
+						return r.skip(typ)
+					}
+					return r.readList(func(byte) error {
+						return r.readStruct(func(id int16, typ byte) error {
+							if id != 3 || typ != thriftStruct {
+								return r.skip(typ)
+							}
+							chunk, err := decodeChunkMetaData(r)
+							chunks = append(chunks, chunk)
+							return err
+						})
+					})
+				})
+				rowGroups = append(rowGroups, chunks)
+				return err
+			})
+		}
+		return r.skip(typ)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode Parquet footer: %w", err)
+	}
+	return schema, rowGroups, nil
+}
+
+// decodeChunkMetaData reads the location fields of a ColumnMetaData
+func decodeChunkMetaData(r *thriftReader) (parquetChunk, error) {
+	chunk := parquetChunk{dictOffset: -1}
+	var path []string
+	err := r.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 3 && typ == thriftList:
+			return r.readList(func(byte) error {
+				segment, err := r.binary()
+				path = append(path, string(segment))
+				return err
+			})
+		case id == 4 && typ == thriftI32:
+			var v int64
+			v, err = r.i64()
+			chunk.codec = int32(v)
+		case id == 5 && typ == thriftI64:
+			chunk.numValues, err = r.i64()
+		case id == 7 && typ == thriftI64:
+			chunk.size, err = r.i64()
+		case id == 9 && typ == thriftI64:
+			chunk.dataOffset, err = r.i64()
+		case id == 11 && typ == thriftI64:
+			chunk.dictOffset, err = r.i64()
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+	chunk.path = strings.Join(path, ".")
+	return chunk, err
+}
+
+// readParquetChunk decodes every page of a column chunk
+func readParquetChunk(data []byte, chunk parquetChunk, element parquetSchemaElement) ([]parquetValue, error) {
+	start := chunk.dataOffset
+	if chunk.dictOffset > 0 && chunk.dictOffset < start {
+		start = chunk.dictOffset
+	}
+	if start < 0 || chunk.size < 0 || start+chunk.size > int64(len(data)) {
+		return nil, errors.New("column chunk outside the file")
+	}
+	buf := data[start : start+chunk.size]
+
+	maxDef := 0
+	if element.repetition == parquetOptional {
+		maxDef = 1
+	}
+
+	var dictionary []parquetValue
+	values := make([]parquetValue, 0, chunk.numValues)
+	for int64(len(values)) < chunk.numValues && len(buf) > 0 {
+		r := &thriftReader{buf: buf}
+		header, err := decodePageHeader(r)
+		if err != nil {
+			return nil, err
+		}
+		if header.compressedSize < 0 || int(header.compressedSize) > len(buf)-r.pos {
+			return nil, errors.New("truncated Parquet page")
+		}
+		payload := buf[r.pos : r.pos+int(header.compressedSize)]
+		buf = buf[r.pos+int(header.compressedSize):]
+
+		switch header.kind {
+		case parquetDictionaryPage:
+			page, err := decompressParquetPage(chunk.codec, payload, header.uncompressedSize)
+			if err != nil {
+				return nil, err
+			}
+			dictionary, _, err = decodePlainValues(page, element.physical, int(header.numValues))
+			if err != nil {
+				return nil, err
+			}
+
+		case parquetDataPage, parquetDataPageV2:
+			page, defLevels, err := splitDataPage(chunk.codec, header, payload, maxDef > 0)
+			if err != nil {
+				return nil, err
+			}
+			n := int(header.numValues)
+			present := n
+			var defined []uint32
+			if maxDef > 0 {
+				defined, err = decodeRLEHybrid(defLevels, 1, n)
+				if err != nil {
+					return nil, err
+				}
+				present = 0
+				for _, d := range defined {
+					if d == 1 {
+						present++
+					}
+				}
+			}
+
+			var decoded []parquetValue
+			switch header.encoding {
+			case parquetPlain:
+				decoded, _, err = decodePlainValues(page, element.physical, present)
+			case parquetPlainDictionary, parquetRLEDictionary:
+				decoded, err = decodeDictionaryValues(page, dictionary, present)
+			default:
+				err = fmt.Errorf("unsupported Parquet encoding %d", header.encoding)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			next := 0
+			for i := 0; i < n; i++ {
+				if defined != nil && defined[i] == 0 {
+					values = append(values, parquetValue{null: true})
+					continue
+				}
+				values = append(values, decoded[next])
+				next++
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// decodePageHeader reads a Thrift PageHeader; the reader is left at the
+// start of the page payload
+func decodePageHeader(r *thriftReader) (parquetPageHeader, error) {
+	header := parquetPageHeader{compressed: true}
+	err := r.readStruct(func(id int16, typ byte) error {
+		var err error
+		var v int64
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err = r.i64()
+			header.kind = int32(v)
+		case id == 2 && typ == thriftI32:
+			v, err = r.i64()
+			header.uncompressedSize = int32(v)
+		case id == 3 && typ == thriftI32:
+			v, err = r.i64()
+			header.compressedSize = int32(v)
+		case (id == 5 || id == 7) && typ == thriftStruct:
+			// DataPageHeader and DictionaryPageHeader share their first
+			// two fields: num_values and encoding
+			err = r.readStruct(func(id int16, typ byte) error {
+				var v int64
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					v, err = r.i64()
+					header.numValues = int32(v)
+				case id == 2 && typ == thriftI32:
+					v, err = r.i64()
+					header.encoding = int32(v)
+				default:
+					err = r.skip(typ)
+				}
+				return err
+			})
+		case id == 8 && typ == thriftStruct:
+			err = r.readStruct(func(id int16, typ byte) error {
+				var v int64
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					v, err = r.i64()
+					header.numValues = int32(v)
+				case id == 4 && typ == thriftI32:
+					v, err = r.i64()
+					header.encoding = int32(v)
+				case id == 5 && typ == thriftI32:
+					v, err = r.i64()
+					header.defLevelsLength = int32(v)
+				case id == 6 && typ == thriftI32:
+					v, err = r.i64()
+					header.repLevelsLength = int32(v)
+				case id == 7 && (typ == thriftTrue || typ == thriftFalse):
+					header.compressed = typ == thriftTrue
+				default:
+					err = r.skip(typ)
+				}
+				return err
+			})
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+	if err != nil {
+		return header, fmt.Errorf("failed to decode Parquet page header: %w", err)
+	}
+	return header, nil
+}
+
+// splitDataPage decompresses a data page and returns its values section and
+// its encoded definition levels, which only optional columns have
+func splitDataPage(codec int32, header parquetPageHeader, payload []byte, optional bool) ([]byte, []byte, error) {
+	if header.kind == parquetDataPageV2 {
+		levels := int(header.repLevelsLength) + int(header.defLevelsLength)
+		if header.repLevelsLength < 0 || header.defLevelsLength < 0 || levels > len(payload) {
+			return nil, nil, errors.New("truncated Parquet page levels")
+		}
+		defLevels := payload[header.repLevelsLength:levels]
+		page := payload[levels:]
+		if header.compressed {
+			var err error
+			page, err = decompressParquetPage(codec, page, header.uncompressedSize-int32(levels))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return page, defLevels, nil
+	}
+
+	page, err := decompressParquetPage(codec, payload, header.uncompressedSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !optional {
+		return page, nil, nil
+	}
+	// V1 pages prefix the RLE definition levels with their length
+	if len(page) < 4 {
+		return nil, nil, errors.New("truncated Parquet page levels")
+	}
+	n := int(binary.LittleEndian.Uint32(page))
+	if n > len(page)-4 {
+		return nil, nil, errors.New("truncated Parquet page levels")
+	}
+	return page[4+n:], page[4 : 4+n], nil
+}
+
+// decompressParquetPage decompresses a page with the column chunk's codec
+func decompressParquetPage(codec int32, payload []byte, uncompressedSize int32) ([]byte, error) {
+	switch codec {
+	case parquetUncompressed:
+		return payload, nil
+	case parquetSnappy:
+		return snappy.Decode(make([]byte, 0, max(uncompressedSize, 0)), payload)
+	case parquetGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case parquetZstd:
+		zr, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return zr.DecodeAll(payload, nil)
+	}
+	return nil, fmt.Errorf("unsupported Parquet compression codec %d", codec)
+}
+
+// decodePlainValues decodes n PLAIN encoded values and returns the number
+// of bytes consumed
+func decodePlainValues(data []byte, physical int32, n int) ([]parquetValue, int, error) {
+	values := make([]parquetValue, 0, n)
+	pos := 0
+	for range n {
+		switch physical {
+		case parquetInt32:
+			if len(data)-pos < 4 {
+				return nil, 0, errors.New("truncated Parquet values")
+			}
+			values = append(values, parquetValue{i64: int64(int32(binary.LittleEndian.Uint32(data[pos:])))})
+			pos += 4
+		case parquetInt64:
+			if len(data)-pos < 8 {
+				return nil, 0, errors.New("truncated Parquet values")
+			}
+			values = append(values, parquetValue{i64: int64(binary.LittleEndian.Uint64(data[pos:]))})
+			pos += 8
+		case parquetByteArray:
+			if len(data)-pos < 4 {
+				return nil, 0, errors.New("truncated Parquet values")
+			}
+			length := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if length > len(data)-pos {
+				return nil, 0, errors.New("truncated Parquet values")
+			}
+			values = append(values, parquetValue{bytes: data[pos : pos+length]})
+			pos += length
+		default:
+			return nil, 0, fmt.Errorf("unsupported Parquet type %s", parquetPhysicalNames[physical])
+		}
+	}
+	return values, pos, nil
+}
+
+// decodeDictionaryValues resolves n dictionary indices: a bit width byte
+// followed by RLE/bit-packed hybrid indices
+func decodeDictionaryValues(data []byte, dictionary []parquetValue, n int) ([]parquetValue, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	if len(data) == 0 {
+		return nil, errors.New("truncated Parquet dictionary indices")
+	}
+	indices, err := decodeRLEHybrid(data[1:], int(data[0]), n)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]parquetValue, n)
+	for i, index := range indices {
+		if int(index) >= len(dictionary) {
+			return nil, errors.New("Parquet dictionary index out of range")
+		}
+		values[i] = dictionary[index]
+	}
+	return values, nil
+}
+
+// decodeRLEHybrid decodes n values of the RLE/bit-packed hybrid encoding
+// used for levels and dictionary indices
+func decodeRLEHybrid(data []byte, bitWidth, n int) ([]uint32, error) {
+	if bitWidth > 32 {
+		return nil, fmt.Errorf("invalid bit width %d", bitWidth)
+	}
+	values := make([]uint32, 0, n)
+	pos := 0
+	for len(values) < n {
+		header, size := binary.Uvarint(data[pos:])
+		if size <= 0 {
+			return nil, errors.New("truncated RLE data")
+		}
+		pos += size
+
+		if header&1 == 0 {
+			// RLE run: a repeated value stored in ceil(bitWidth/8) bytes
+			count := int(header >> 1)
+			width := (bitWidth + 7) / 8
+			if len(data)-pos < width {
+				return nil, errors.New("truncated RLE data")
+			}
+			var value uint32
+			for i := range width {
+				value |= uint32(data[pos+i]) << (8 * i)
+			}
+			pos += width
+			for range min(count, n-len(values)) {
+				values = append(values, value)
+			}
+			continue
+		}
+
+		// Bit-packed run: groups of 8 values, least significant bit first
+		count := int(header>>1) * 8
+		byteCount := int(header>>1) * bitWidth
+		if len(data)-pos < byteCount {
+			return nil, errors.New("truncated bit-packed data")
+		}
+		packed := data[pos : pos+byteCount]
+		pos += byteCount
+		for i := 0; i < count && len(values) < n; i++ {
+			var value uint32
+			for b := range bitWidth {
+				bit := i*bitWidth + b
+				if packed[bit/8]&(1<<(bit%8)) != 0 {
+					value |= 1 << b
+				}
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,210 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+const (
+	// parquetWorkers bounds concurrent footer reads
+	parquetWorkers = 8
+
+	// unpartitionedGroup collects Parquet files outside detected partitions
+	unpartitionedGroup = "(unpartitioned)"
+)
+
+// ParquetAnalyzer reads column statistics from the footers of sampled
+// Parquet files and merges them per partition
+type ParquetAnalyzer struct {
+	s3Client     *s3.Client
+	perPartition int
+}
+
+// NewParquetAnalyzer creates an analyzer that samples up to perPartition
+// Parquet files in every partition
+func NewParquetAnalyzer(s3Client *s3.Client, perPartition int) *ParquetAnalyzer {
+	return &ParquetAnalyzer{
+		s3Client:     s3Client,
+		perPartition: perPartition,
+	}
+}
+
+// Enabled reports whether Parquet statistics were requested
+func (pa *ParquetAnalyzer) Enabled() bool {
+	return pa.perPartition > 0
+}
+
+// parquetFile is a sampled file and, once read, its footer
+type parquetFile struct {
+	group  string
+	obj    types.ObjectMetadata
+	footer *parquetFooter
+}
+
+// AnalyzeParquet samples Parquet files per partition, reads their footers
+// with ranged requests and merges the column statistics. Unreadable files
+// are counted as failed.
+func (pa *ParquetAnalyzer) AnalyzeParquet(ctx context.Context, bucketName string, objects []types.ObjectMetadata, partitions []types.Partition) ([]types.ParquetStats, error) {
+	groups := make(map[string][]types.ObjectMetadata)
+	for _, obj := range objects {
+		if strings.EqualFold(path.Ext(obj.Key), ".parquet") {
+			group := partitionOf(obj.Key, partitions)
+			groups[group] = append(groups[group], obj)
+		}
+	}
+
+	var files []*parquetFile
+	for group, members := range groups {
+		n := min(pa.perPartition, len(members))
+		for i := 0; i < n; i++ {
+			files = append(files, &parquetFile{group: group, obj: members[i*len(members)/n]})
+		}
+	}
+
+	var wg sync.WaitGroup
+	fileChan := make(chan *parquetFile)
+	for i := 0; i < parquetWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range fileChan {
+				// A failed read leaves footer nil
+				reader := &s3RangeReader{client: pa.s3Client, bucketName: bucketName, key: f.obj.Key}
+				f.footer, _ = readParquetFooter(ctx, reader, f.obj.Size)
+			}
+		}()
+	}
+	for _, f := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		fileChan <- f
+	}
+	close(fileChan)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return mergeParquetStats(groups, files), nil
+}
+
+// mergeParquetStats combines the footers of each group's sampled files
+func mergeParquetStats(groups map[string][]types.ObjectMetadata, files []*parquetFile) []types.ParquetStats {
+	type groupState struct {
+		stats   types.ParquetStats
+		columns []*parquetColumn
+		byPath  map[string]*parquetColumn
+		schema  map[string]parquetSchemaElement
+		files   map[string]int
+	}
+
+	states := make(map[string]*groupState)
+	for group, members := range groups {
+		states[group] = &groupState{
+			stats:  types.ParquetStats{Partition: group, Files: int64(len(members))},
+			byPath: make(map[string]*parquetColumn),
+			schema: make(map[string]parquetSchemaElement),
+			files:  make(map[string]int),
+		}
+	}
+
+	for _, f := range files {
+		state := states[f.group]
+		if f.footer == nil {
+			state.stats.FilesFailed++
+			continue
+		}
+		state.stats.FilesSampled++
+		state.stats.Rows += f.footer.numRows
+		state.stats.RowGroups += f.footer.rowGroups
+
+		for p, element := range f.footer.leafSchema() {
+			if _, exists := state.schema[p]; !exists {
+				state.schema[p] = element
+			}
+		}
+		for _, c := range f.footer.columns {
+			copied := *c
+			copied.codecs = slices.Clone(c.codecs)
+			state.columns = mergeParquetColumn(state.columns, state.byPath, &copied)
+			state.files[c.path]++
+		}
+	}
+
+	result := make([]types.ParquetStats, 0, len(states))
+	for _, state := range states {
+		codecs := make(map[string]bool)
+		for _, c := range state.columns {
+			element := state.schema[c.path]
+			column := types.ParquetColumnStats{
+				Name:             c.path,
+				Type:             parquetPhysicalNames[c.physical],
+				LogicalType:      parquetTypeName(element, c.physical),
+				Repetition:       parquetRepetitionNames[element.repetition],
+				HasMinMax:        c.minMaxKnown,
+				Values:           c.values,
+				Nulls:            c.nulls,
+				NullsKnown:       c.nullsKnown,
+				CompressedSize:   c.compressed,
+				UncompressedSize: c.uncompressed,
+				Files:            state.files[c.path],
+			}
+			for _, codec := range c.codecs {
+				name, ok := parquetCodecNames[codec]
+				if !ok {
+					name = fmt.Sprintf("codec %d", codec)
+				}
+				column.Codecs = append(column.Codecs, name)
+				codecs[name] = true
+			}
+			if c.minMaxKnown {
+				column.Min = formatParquetValue(element, c.physical, c.min)
+				column.Max = formatParquetValue(element, c.physical, c.max)
+			}
+			state.stats.Columns = append(state.stats.Columns, column)
+		}
+		state.stats.Codecs = slices.Sorted(maps.Keys(codecs))
+		result = append(result, state.stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Partition < result[j].Partition
+	})
+
+	return result
+}
+
+// partitionOf returns the partition a key belongs to. Date partitions are
+// matched anywhere in the key below their scope, so the earliest (then
+// longest) match wins; bucket-wide hierarchical partitions match by prefix.
+func partitionOf(key string, partitions []types.Partition) string {
+	scope := subtreeScope(key)
+	best, bestIndex, bestLen := unpartitionedGroup, -1, 0
+	for _, p := range partitions {
+		i := -1
+		switch {
+		case p.Scope == scope:
+			i = strings.Index(key[len(scope):], p.Prefix)
+		case p.Scope == "" && strings.HasPrefix(key, p.Prefix):
+			i = 0
+		}
+		if i < 0 {
+			continue
+		}
+		if bestIndex < 0 || i < bestIndex || (i == bestIndex && len(p.Prefix) > bestLen) {
+			best, bestIndex, bestLen = p.Scope+p.Prefix, i, len(p.Prefix)
+		}
+	}
+	return best
+}
@@ -0,0 +1,480 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// thriftWriter encodes Thrift compact protocol data for the decoder tests
+type thriftWriter struct {
+	buf []byte
+	// last holds the previous field id of each open struct
+	last []int16
+}
+
+func (w *thriftWriter) begin() { w.last = append(w.last, 0) }
+
+func (w *thriftWriter) end() {
+	w.buf = append(w.buf, thriftStop)
+	w.last = w.last[:len(w.last)-1]
+}
+
+// field writes a field header, using the short delta form when it fits
+func (w *thriftWriter) field(id int16, typ byte) {
+	delta := id - w.last[len(w.last)-1]
+	if delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.i64(int64(id))
+	}
+	w.last[len(w.last)-1] = id
+}
+
+func (w *thriftWriter) list(n int, typ byte) {
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|typ)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|typ)
+	w.buf = binary.AppendUvarint(w.buf, uint64(n))
+}
+
+func (w *thriftWriter) i64(v int64) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(v<<1^v>>63))
+}
+
+func (w *thriftWriter) binary(b []byte) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func le64(v int64) []byte { return binary.LittleEndian.AppendUint64(nil, uint64(v)) }
+
+func TestThriftFieldIDs(t *testing.T) {
+	var w thriftWriter
+	w.begin()
+	w.field(1, thriftI32)
+	w.i64(-3)
+	w.field(20, thriftI32) // delta of 19 needs the long form
+	w.i64(7)
+	w.field(21, thriftTrue)
+	w.field(-1, thriftFalse)
+	w.end()
+
+	r := &thriftReader{buf: w.buf}
+	var ids []int16
+	var values []int64
+	err := r.readStruct(func(id int16, typ byte) error {
+		ids = append(ids, id)
+		if typ == thriftI32 {
+			v, err := r.i64()
+			values = append(values, v)
+			return err
+		}
+		return r.skip(typ)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int16{1, 20, 21, -1}; !slices.Equal(ids, want) {
+		t.Errorf("field ids = %v, want %v", ids, want)
+	}
+	if want := []int64{-3, 7}; !slices.Equal(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if r.pos != len(w.buf) {
+		t.Errorf("consumed %d of %d bytes", r.pos, len(w.buf))
+	}
+}
+
+func TestThriftSkip(t *testing.T) {
+	tests := []struct {
+		name   string
+		typ    byte
+		encode func(*thriftWriter)
+	}{
+		{"byte", thriftByte, func(w *thriftWriter) { w.buf = append(w.buf, 0x7f) }},
+		{"i64", thriftI64, func(w *thriftWriter) { w.i64(-1 << 40) }},
+		{"double", thriftDouble, func(w *thriftWriter) { w.buf = append(w.buf, le64(1)...) }},
+		{"binary", thriftBinary, func(w *thriftWriter) { w.binary([]byte("hello")) }},
+		{"list of bools", thriftList, func(w *thriftWriter) {
+			// Booleans in containers take a byte each
+			w.list(3, thriftTrue)
+			w.buf = append(w.buf, 1, 0, 1)
+		}},
+		{"long list", thriftSet, func(w *thriftWriter) {
+			w.list(20, thriftI32)
+			for i := range 20 {
+				w.i64(int64(i))
+			}
+		}},
+		{"map of string to bool", thriftMap, func(w *thriftWriter) {
+			w.buf = binary.AppendUvarint(w.buf, 2)
+			w.buf = append(w.buf, thriftBinary<<4|thriftTrue)
+			w.binary([]byte("a"))
+			w.buf = append(w.buf, 1)
+			w.binary([]byte("b"))
+			w.buf = append(w.buf, 0)
+		}},
+		{"empty map", thriftMap, func(w *thriftWriter) { w.buf = append(w.buf, 0) }},
+		{"nested struct", thriftStruct, func(w *thriftWriter) {
+			w.begin()
+			w.field(1, thriftStruct)
+			w.begin()
+			w.field(2, thriftBinary)
+			w.binary([]byte("x"))
+			w.end()
+			w.field(3, thriftList)
+			w.list(1, thriftStruct)
+			w.begin()
+			w.end()
+			w.end()
+		}},
+	}
+	for _, tt := range tests {
+		var w thriftWriter
+		tt.encode(&w)
+		// A trailing byte shows the skip stopped at the end of the value
+		w.buf = append(w.buf, 0xaa)
+		r := &thriftReader{buf: w.buf}
+		if err := r.skip(tt.typ); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if r.pos != len(w.buf)-1 {
+			t.Errorf("%s: consumed %d bytes, want %d", tt.name, r.pos, len(w.buf)-1)
+		}
+	}
+}
+
+func TestThriftRejectsMalformedData(t *testing.T) {
+	deep := bytes.Repeat([]byte{1<<4 | thriftStruct}, thriftMaxNested+1)
+	tests := []struct {
+		name    string
+		typ     byte
+		buf     []byte
+		wantErr string
+	}{
+		{"empty", thriftI64, nil, errThriftTruncated.Error()},
+		{"unterminated varint", thriftI32, []byte{0x80, 0x80}, errThriftTruncated.Error()},
+		{"short double", thriftDouble, []byte{1, 2, 3}, errThriftTruncated.Error()},
+		{"binary longer than data", thriftBinary, []byte{0x0a, 'a'}, errThriftTruncated.Error()},
+		{"list longer than data", thriftList, []byte{0xf0 | thriftI32, 0xff, 0xff, 0x03, 0}, errThriftTruncated.Error()},
+		{"map longer than data", thriftMap, []byte{0xff, 0xff, 0x03, thriftI32<<4 | thriftI32}, errThriftTruncated.Error()},
+		{"unterminated struct", thriftStruct, []byte{1<<4 | thriftI32, 0x02}, errThriftTruncated.Error()},
+		{"unknown type", 13, []byte{0}, "unknown thrift type"},
+		{"nested too deeply", thriftStruct, deep, "nested too deeply"},
+	}
+	for _, tt := range tests {
+		r := &thriftReader{buf: tt.buf}
+		err := r.skip(tt.typ)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// testColumnChunk describes one column chunk of a synthetic row group
+type testColumnChunk struct {
+	path     string
+	physical int32
+	codec    int32
+	values   int64
+	nulls    int64
+	// statsField is 5/6 for min_value/max_value, 1/2 for the legacy
+	// max/min fields, or 0 for no min/max
+	statsField int16
+	min, max   []byte
+}
+
+// encodeParquetFooter writes a FileMetaData with an id INT64, a name
+// string and a ts microsecond timestamp column, plus fields the decoder
+// should skip
+func encodeParquetFooter(numRows int64, rowGroups [][]testColumnChunk) []byte {
+	var w thriftWriter
+	w.begin()
+	w.field(1, thriftI32) // version
+	w.i64(2)
+
+	w.field(2, thriftList)
+	w.list(4, thriftStruct)
+	w.begin()
+	w.field(4, thriftBinary)
+	w.binary([]byte("schema"))
+	w.field(5, thriftI32)
+	w.i64(3)
+	w.end()
+
+	w.begin()
+	w.field(1, thriftI32)
+	w.i64(parquetInt64)
+	w.field(3, thriftI32)
+	w.i64(0)
+	w.field(4, thriftBinary)
+	w.binary([]byte("id"))
+	w.end()
+
+	w.begin()
+	w.field(1, thriftI32)
+	w.i64(parquetByteArray)
+	w.field(3, thriftI32)
+	w.i64(1)
+	w.field(4, thriftBinary)
+	w.binary([]byte("name"))
+	w.field(6, thriftI32)
+	w.i64(0)
+	w.field(10, thriftStruct)
+	w.begin()
+	w.field(1, thriftStruct) // STRING
+	w.begin()
+	w.end()
+	w.end()
+	w.end()
+
+	w.begin()
+	w.field(1, thriftI32)
+	w.i64(parquetInt64)
+	w.field(4, thriftBinary)
+	w.binary([]byte("ts"))
+	w.field(10, thriftStruct)
+	w.begin()
+	w.field(8, thriftStruct) // TIMESTAMP
+	w.begin()
+	w.field(1, thriftTrue) // isAdjustedToUTC
+	w.field(2, thriftStruct)
+	w.begin()
+	w.field(2, thriftStruct) // MICROS
+	w.begin()
+	w.end()
+	w.end()
+	w.end()
+	w.end()
+	w.end()
+
+	w.field(3, thriftI64)
+	w.i64(numRows)
+
+	w.field(4, thriftList)
+	w.list(len(rowGroups), thriftStruct)
+	for _, group := range rowGroups {
+		w.begin()
+		w.field(1, thriftList)
+		w.list(len(group), thriftStruct)
+		for _, chunk := range group {
+			w.begin()
+			w.field(2, thriftI64) // file_offset
+			w.i64(4)
+			w.field(3, thriftStruct)
+			w.begin()
+			w.field(1, thriftI32)
+			w.i64(int64(chunk.physical))
+			w.field(2, thriftList) // encodings
+			w.list(1, thriftI32)
+			w.i64(0)
+			w.field(3, thriftList)
+			segments := strings.Split(chunk.path, ".")
+			w.list(len(segments), thriftBinary)
+			for _, s := range segments {
+				w.binary([]byte(s))
+			}
+			w.field(4, thriftI32)
+			w.i64(int64(chunk.codec))
+			w.field(5, thriftI64)
+			w.i64(chunk.values)
+			w.field(6, thriftI64)
+			w.i64(chunk.values * 8)
+			w.field(7, thriftI64)
+			w.i64(chunk.values * 4)
+			w.field(12, thriftStruct)
+			w.begin()
+			if chunk.statsField == 1 {
+				w.field(1, thriftBinary)
+				w.binary(chunk.max)
+				w.field(2, thriftBinary)
+				w.binary(chunk.min)
+			}
+			w.field(3, thriftI64)
+			w.i64(chunk.nulls)
+			if chunk.statsField == 5 {
+				w.field(5, thriftBinary)
+				w.binary(chunk.max)
+				w.field(6, thriftBinary)
+				w.binary(chunk.min)
+			}
+			w.end()
+			w.end()
+			w.end()
+		}
+		w.field(2, thriftI64) // total_byte_size
+		w.i64(1024)
+		w.end()
+	}
+
+	w.field(5, thriftList) // key_value_metadata
+	w.list(1, thriftStruct)
+	w.begin()
+	w.field(1, thriftBinary)
+	w.binary([]byte("writer"))
+	w.end()
+	w.field(100, thriftBinary) // a field from a newer format version
+	w.binary([]byte("ignored"))
+	w.end()
+	return w.buf
+}
+
+func TestDecodeParquetFooter(t *testing.T) {
+	data := encodeParquetFooter(300, [][]testColumnChunk{
+		{
+			{path: "id", physical: parquetInt64, codec: 1, values: 100, statsField: 5, min: le64(10), max: le64(90)},
+			{path: "name", physical: parquetByteArray, codec: 1, values: 100, nulls: 4, statsField: 5, min: []byte("alice"), max: []byte("mallory")},
+			{path: "ts", physical: parquetInt64, codec: 1, values: 100, statsField: 1, min: le64(5), max: le64(6)},
+		},
+		{
+			{path: "id", physical: parquetInt64, codec: 6, values: 200, statsField: 1, min: le64(-5), max: le64(50)},
+			// Legacy statistics of strings are not trusted
+			{path: "name", physical: parquetByteArray, codec: 1, values: 200, nulls: 1, statsField: 1, min: []byte("a"), max: []byte("z")},
+			{path: "ts", physical: parquetInt64, codec: 1, values: 200},
+		},
+	})
+
+	footer, err := decodeParquetFooter(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if footer.numRows != 300 || footer.rowGroups != 2 {
+		t.Errorf("numRows, rowGroups = %d, %d, want 300, 2", footer.numRows, footer.rowGroups)
+	}
+
+	leaves := footer.leafSchema()
+	if name := leaves["name"]; name.logical != "STRING" || name.repetition != 1 || name.physical != parquetByteArray {
+		t.Errorf("name schema = %+v, want an optional STRING byte array", name)
+	}
+	if ts := leaves["ts"]; ts.logical != "TIMESTAMP" || ts.timeUnit != "us" {
+		t.Errorf("ts schema = %+v, want a microsecond TIMESTAMP", ts)
+	}
+
+	tests := []struct {
+		path        string
+		values      int64
+		nulls       int64
+		codecs      []int32
+		minMaxKnown bool
+		min, max    []byte
+	}{
+		{"id", 300, 0, []int32{1, 6}, true, le64(-5), le64(90)},
+		{"name", 300, 5, []int32{1}, false, nil, nil},
+		{"ts", 300, 0, []int32{1}, false, nil, nil},
+	}
+	if len(footer.columns) != len(tests) {
+		t.Fatalf("decoded %d columns, want %d", len(footer.columns), len(tests))
+	}
+	for i, tt := range tests {
+		c := footer.columns[i]
+		if c.path != tt.path {
+			t.Errorf("column %d path = %q, want %q", i, c.path, tt.path)
+			continue
+		}
+		if c.values != tt.values || c.nulls != tt.nulls || !c.nullsKnown {
+			t.Errorf("%s: values, nulls = %d, %d, want %d, %d", tt.path, c.values, c.nulls, tt.values, tt.nulls)
+		}
+		if c.uncompressed != 8*tt.values || c.compressed != 4*tt.values {
+			t.Errorf("%s: sizes = %d/%d, want %d/%d", tt.path, c.compressed, c.uncompressed, 4*tt.values, 8*tt.values)
+		}
+		if !slices.Equal(c.codecs, tt.codecs) {
+			t.Errorf("%s: codecs = %v, want %v", tt.path, c.codecs, tt.codecs)
+		}
+		if c.minMaxKnown != tt.minMaxKnown {
+			t.Errorf("%s: minMaxKnown = %v, want %v", tt.path, c.minMaxKnown, tt.minMaxKnown)
+		}
+		if tt.minMaxKnown && (!bytes.Equal(c.min, tt.min) || !bytes.Equal(c.max, tt.max)) {
+			t.Errorf("%s: min, max = % x, % x, want % x, % x", tt.path, c.min, c.max, tt.min, tt.max)
+		}
+	}
+}
+
+func TestDecodeParquetFooterTruncated(t *testing.T) {
+	data := encodeParquetFooter(10, [][]testColumnChunk{
+		{{path: "id", physical: parquetInt64, values: 10, statsField: 5, min: le64(1), max: le64(2)}},
+	})
+	for _, n := range []int{0, 1, len(data) / 2, len(data) - 1} {
+		if _, err := decodeParquetFooter(data[:n]); !errors.Is(err, errThriftTruncated) {
+			t.Errorf("footer cut to %d of %d bytes: err = %v, want errThriftTruncated", n, len(data), err)
+		}
+	}
+}
+
+// memRangeReader serves ranged reads of an in-memory object
+type memRangeReader struct {
+	data  []byte
+	reads int
+}
+
+func (m *memRangeReader) ReadRange(_ context.Context, offset, length int64) ([]byte, error) {
+	m.reads++
+	if offset < 0 {
+		return m.data[max(0, int64(len(m.data))+offset):], nil
+	}
+	return m.data[offset:min(offset+length, int64(len(m.data)))], nil
+}
+
+// parquetFileBytes wraps a footer in the Parquet file layout, with padding
+// standing in for the column data
+func parquetFileBytes(footer []byte, padding int) []byte {
+	file := append([]byte(parquetMagic), make([]byte, padding)...)
+	file = append(file, footer...)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(footer)))
+	return append(file, parquetMagic...)
+}
+
+func TestReadParquetFooter(t *testing.T) {
+	footer := encodeParquetFooter(42, nil)
+
+	// A footer larger than the first read needs a second one
+	var large thriftWriter
+	large.begin()
+	large.field(3, thriftI64)
+	large.i64(42)
+	large.field(5, thriftBinary)
+	large.binary(make([]byte, parquetTailSize))
+	large.end()
+
+	badLength := parquetFileBytes(footer, 16)
+	binary.LittleEndian.PutUint32(badLength[len(badLength)-8:], uint32(len(badLength)))
+
+	tests := []struct {
+		name      string
+		file      []byte
+		wantReads int
+		wantErr   string
+	}{
+		{"footer in the tail", parquetFileBytes(footer, 1024), 1, ""},
+		{"footer beyond the tail", parquetFileBytes(large.buf, 1024), 2, ""},
+		{"too small", []byte("PAR1PAR1"), 0, "too small"},
+		{"missing magic", append(parquetFileBytes(footer, 16)[:len(footer)+24], "PAR2"...), 1, "magic"},
+		{"footer longer than file", badLength, 1, "footer length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &memRangeReader{data: tt.file}
+			got, err := readParquetFooter(context.Background(), reader, int64(len(tt.file)))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if got.numRows != 42 {
+				t.Errorf("numRows = %d, want 42", got.numRows)
+			}
+			if reader.reads != tt.wantReads {
+				t.Errorf("made %d reads, want %d", reader.reads, tt.wantReads)
+			}
+		})
+	}
+}
@@ -1,115 +1,517 @@
 package profiler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/yourusername/s3-profiler/types"
 )
 
+// minPartitionSample is the smallest inventory that is sampled; smaller
+// listings are always evaluated in full
+const minPartitionSample = 10000
+
 // PartitionAnalyzer handles partition detection in S3 keys
-type PartitionAnalyzer struct{}
+type PartitionAnalyzer struct {
+	// sampleRate is the fraction of keys used to choose a date pattern;
+	// the chosen pattern is then counted over every key
+	sampleRate float64
+	// layout is a known dataset's layout tried before datePatterns
+	layout *datePattern
+	// custom are user-supplied patterns tried before datePatterns
+	custom []datePattern
+	// windows are the query windows pruning is estimated for
+	windows []queryWindow
+}
 
-// NewPartitionAnalyzer creates a new partition analyzer
-func NewPartitionAnalyzer() *PartitionAnalyzer {
-	return &PartitionAnalyzer{}
+// NewPartitionAnalyzer creates a new partition analyzer. A sampleRate
+// outside (0, 1) evaluates every key.
+func NewPartitionAnalyzer(sampleRate float64) *PartitionAnalyzer {
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &PartitionAnalyzer{sampleRate: sampleRate}
 }
 
-// AnalyzePartitions detects partitions in object keys
-func (pa *PartitionAnalyzer) AnalyzePartitions(objects []types.ObjectMetadata) []types.Partition {
-	if len(objects) == 0 {
-		return nil
+// cacheKey identifies settings that change detection results, so cached
+// analyses from other settings are not reused
+func (pa *PartitionAnalyzer) cacheKey() string {
+	var parts []string
+	if pa.sampleRate != 1 {
+		parts = append(parts, "sample-"+strconv.FormatFloat(pa.sampleRate, 'g', -1, 64))
+	}
+	if pa.layout != nil {
+		// Layout names contain slashes; the key becomes a file name
+		parts = append(parts, "layout-"+strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' {
+				return r
+			}
+			return '_'
+		}, pa.layout.name))
+	}
+	if len(pa.custom) > 0 {
+		h := sha256.New()
+		for _, pattern := range pa.custom {
+			fmt.Fprintf(h, "%s\x00%s\x00", pattern.name, pattern.regex)
+		}
+		parts = append(parts, "patterns-"+hex.EncodeToString(h.Sum(nil))[:12])
 	}
+	return strings.Join(parts, "-")
+}
 
-	// Detect different types of partitions
-	var partitions []types.Partition
+// SetPatterns adds date partition patterns, each given as NAME:REGEX,
+// that are tried before the built-in ones. The regex captures the year,
+// the month and optionally the day, in that order; the name labels the
+// pattern in reports and should spell out its layout, e.g.
+// "ingest_date=YYYYMMDD:ingest_date=(\d{4})(\d{2})(\d{2})".
+func (pa *PartitionAnalyzer) SetPatterns(specs []string) error {
+	patterns := make([]datePattern, 0, len(specs))
+	for _, spec := range specs {
+		name, expr, ok := strings.Cut(spec, ":")
+		if !ok || strings.TrimSpace(name) == "" || expr == "" {
+			return fmt.Errorf("invalid partition pattern %q (expected NAME:REGEX)", spec)
+		}
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid partition pattern %q: %w", spec, err)
+		}
+		if n := regex.NumSubexp(); n != 2 && n != 3 {
+			return fmt.Errorf("partition pattern %q has %d capture group(s); expected year, month and optionally day", spec, n)
+		}
+		patterns = append(patterns, datePattern{name: strings.TrimSpace(name), regex: regex})
+	}
+	pa.custom = patterns
+	return nil
+}
+
+// withLayout returns an analyzer that also detects an open dataset's
+// layout; unknown layout names return the analyzer unchanged
+func (pa *PartitionAnalyzer) withLayout(name string) *PartitionAnalyzer {
+	layout, ok := openDataLayouts[name]
+	if !ok {
+		return pa
+	}
+	return &PartitionAnalyzer{sampleRate: pa.sampleRate, layout: &layout, custom: pa.custom, windows: pa.windows}
+}
+
+// patterns returns the date patterns in the order they are tried: a known
+// dataset's layout, then custom patterns, then the built-in ones
+func (pa *PartitionAnalyzer) patterns() []datePattern {
+	if pa.layout == nil && len(pa.custom) == 0 {
+		return datePatterns
+	}
+	var patterns []datePattern
+	if pa.layout != nil {
+		patterns = append(patterns, *pa.layout)
+	}
+	patterns = append(patterns, pa.custom...)
+	return append(patterns, datePatterns...)
+}
+
+// sample returns every n-th object for the configured rate
+func (pa *PartitionAnalyzer) sample(objects []types.ObjectMetadata) []types.ObjectMetadata {
+	if pa.sampleRate == 1 || len(objects) < minPartitionSample {
+		return objects
+	}
+	stride := int(math.Round(1 / pa.sampleRate))
+	if stride <= 1 {
+		return objects
+	}
+	// Keep at least minPartitionSample keys so small buckets stay accurate
+	if len(objects)/stride < minPartitionSample {
+		stride = len(objects) / minPartitionSample
+	}
 
-	// 1. Detect date-based partitions
-	datePartitions := pa.detectDatePartitions(objects)
-	partitions = append(partitions, datePartitions...)
+	sampled := make([]types.ObjectMetadata, 0, len(objects)/stride+1)
+	for i := 0; i < len(objects); i += stride {
+		sampled = append(sampled, objects[i])
+	}
+	return sampled
+}
+
+// AnalyzePartitions detects partitions in object keys, with the coverage
+// of each pattern and the date patterns rejected by validation
+func (pa *PartitionAnalyzer) AnalyzePartitions(objects []types.ObjectMetadata) *types.PartitionAnalysis {
+	analysis := &types.PartitionAnalysis{}
+	if len(objects) == 0 {
+		return analysis
+	}
+
+	// 1. Detect date-based partitions independently per top-level prefix,
+	// so datasets with different layouts are each reported in their subtree
+	for _, subtree := range splitSubtrees(objects) {
+		found := pa.detectDatePartitions(subtree.objects)
+		for _, match := range found.matches {
+			for i := range match.partitions {
+				match.partitions[i].Scope = subtree.scope
+			}
+			analysis.Partitions = append(analysis.Partitions, match.partitions...)
+			analysis.Patterns = append(analysis.Patterns, types.PatternCoverage{
+				Scope:       subtree.scope,
+				Pattern:     match.pattern.name,
+				ObjectCount: match.objects,
+				TotalSize:   match.size,
+				Coverage:    float64(match.objects) / float64(len(subtree.objects)),
+			})
+		}
+		for _, r := range found.rejected {
+			r.Scope = subtree.scope
+			analysis.Rejected = append(analysis.Rejected, r)
+		}
+	}
 
 	// 2. Detect hierarchical prefix partitions (if no date partitions found)
-	if len(datePartitions) == 0 {
-		hierarchicalPartitions := pa.detectHierarchicalPartitions(objects)
-		partitions = append(partitions, hierarchicalPartitions...)
+	if len(analysis.Partitions) == 0 {
+		analysis.Partitions = pa.detectHierarchicalPartitions(objects)
+		if len(analysis.Partitions) > 0 {
+			coverage := types.PatternCoverage{Pattern: analysis.Partitions[0].Pattern}
+			for _, p := range analysis.Partitions {
+				coverage.ObjectCount += p.ObjectCount
+				coverage.TotalSize += p.TotalSize
+			}
+			coverage.Coverage = float64(coverage.ObjectCount) / float64(len(objects))
+			analysis.Patterns = append(analysis.Patterns, coverage)
+		}
 	}
 
-	return partitions
+	return analysis
 }
 
-// detectDatePartitions detects date-based partition patterns
-func (pa *PartitionAnalyzer) detectDatePartitions(objects []types.ObjectMetadata) []types.Partition {
-	patterns := []struct {
-		name  string
-		regex *regexp.Regexp
-	}{
-		{"year=YYYY/month=MM/day=DD", regexp.MustCompile(`year=(\d{4})/month=(\d{2})/day=(\d{2})`)},
-		{"year=YYYY/month=MM", regexp.MustCompile(`year=(\d{4})/month=(\d{2})`)},
-		{"YYYY/MM/DD", regexp.MustCompile(`(\d{4})/(\d{2})/(\d{2})`)},
-		{"YYYY/MM", regexp.MustCompile(`(\d{4})/(\d{2})`)},
-		{"YYYY-MM-DD", regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`)},
-		{"dt=YYYY-MM-DD", regexp.MustCompile(`dt=(\d{4})-(\d{2})-(\d{2})`)},
-	}
-
-	for _, pattern := range patterns {
-		partitions := pa.groupByPattern(objects, pattern.name, pattern.regex)
-		if len(partitions) > 0 {
-			// Check if this pattern covers a significant portion of objects
-			totalMatched := int64(0)
-			for _, p := range partitions {
-				totalMatched += p.ObjectCount
+// subtree is the set of objects under one top-level prefix
+type subtree struct {
+	// scope is the top-level prefix including its trailing slash, or ""
+	// for objects at the bucket root
+	scope   string
+	objects []types.ObjectMetadata
+}
+
+// splitSubtrees groups objects by top-level prefix, sorted by scope.
+// Listings are in key order, so each group is normally one contiguous run
+// and is returned as a subslice without copying.
+func splitSubtrees(objects []types.ObjectMetadata) []subtree {
+	runs := make(map[string][][]types.ObjectMetadata)
+	start := 0
+	for i := 1; i <= len(objects); i++ {
+		if i < len(objects) && subtreeScope(objects[i].Key) == subtreeScope(objects[start].Key) {
+			continue
+		}
+		scope := subtreeScope(objects[start].Key)
+		runs[scope] = append(runs[scope], objects[start:i])
+		start = i
+	}
+
+	subtrees := make([]subtree, 0, len(runs))
+	for scope, parts := range runs {
+		group := parts[0]
+		if len(parts) > 1 {
+			group = nil
+			for _, part := range parts {
+				group = append(group, part...)
 			}
+		}
+		subtrees = append(subtrees, subtree{scope: scope, objects: group})
+	}
+	sort.Slice(subtrees, func(i, j int) bool {
+		return subtrees[i].scope < subtrees[j].scope
+	})
+	return subtrees
+}
+
+// subtreeScope returns a key's top-level prefix with its slash, or ""
+func subtreeScope(key string) string {
+	if i := strings.IndexByte(key, '/'); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
 
-			// If pattern covers >50% of objects, consider it valid
-			if float64(totalMatched)/float64(len(objects)) > 0.5 {
-				return partitions
+// keyFeature is a cheap property of a key that a date pattern requires
+type keyFeature uint8
+
+const (
+	featureYear      keyFeature = 1 << iota // "year=" literal
+	featureDt                               // "dt=" literal
+	featureDateSlash                        // four digits, '/', two digits
+	featureDateDash                         // four digits, '-', two digits
+)
+
+// datePattern is a date partition layout with the key features any match
+// must have, checked before running the regex
+type datePattern struct {
+	name     string
+	regex    *regexp.Regexp
+	requires keyFeature
+	// generic patterns have no key=value names, so they must also match
+	// at a consistent path depth to be accepted
+	generic bool
+}
+
+// datePatterns are compiled once and tried in order, named layouts before
+// generic ones; each key belongs to the first accepted pattern it matches
+var datePatterns = []datePattern{
+	{"year=YYYY/month=MM/day=DD", regexp.MustCompile(`year=(\d{4})/month=(\d{2})/day=(\d{2})`), featureYear, false},
+	{"year=YYYY/month=MM", regexp.MustCompile(`year=(\d{4})/month=(\d{2})`), featureYear, false},
+	{"dt=YYYY-MM-DD", regexp.MustCompile(`dt=(\d{4})-(\d{2})-(\d{2})`), featureDt | featureDateDash, false},
+	{"YYYY/MM/DD", regexp.MustCompile(`(\d{4})/(\d{2})/(\d{2})`), featureDateSlash, true},
+	{"YYYY/MM", regexp.MustCompile(`(\d{4})/(\d{2})`), featureDateSlash, true},
+	{"YYYY-MM-DD", regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`), featureDateDash, true},
+}
+
+// keyFeatures scans a key once and returns the features it has. Keys
+// lacking a pattern's features cannot match it, so most keys in buckets
+// without date partitions never reach a regex.
+func keyFeatures(key string) keyFeature {
+	var f keyFeature
+	if strings.Contains(key, "year=") {
+		f |= featureYear
+	}
+	if strings.Contains(key, "dt=") {
+		f |= featureDt
+	}
+
+	digits := 0
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= '0' && c <= '9' {
+			digits++
+			continue
+		}
+		if digits >= 4 && i+2 < len(key) && isDigit(key[i+1]) && isDigit(key[i+2]) {
+			switch c {
+			case '/':
+				f |= featureDateSlash
+			case '-':
+				f |= featureDateDash
 			}
 		}
+		digits = 0
 	}
+	return f
+}
 
-	return nil
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
 }
 
-// groupByPattern groups objects by a regex pattern
-func (pa *PartitionAnalyzer) groupByPattern(objects []types.ObjectMetadata, patternName string, regex *regexp.Regexp) []types.Partition {
-	partitionMap := make(map[string]*types.Partition)
+// minPatternShare is the share of a subtree's objects a date pattern must
+// cover to be reported
+const minPatternShare = 0.05
 
-	for _, obj := range objects {
-		matches := regex.FindStringSubmatch(obj.Key)
-		if len(matches) > 0 {
-			// Extract the matched prefix
-			prefix := matches[0]
+// patternMatch holds the partitions of one accepted date pattern
+type patternMatch struct {
+	pattern    datePattern
+	partitions []types.Partition
+	objects    int64
+	size       int64
+}
 
-			if partition, exists := partitionMap[prefix]; exists {
-				partition.ObjectCount++
-				partition.TotalSize += obj.Size
-				if len(partition.Examples) < 3 {
-					partition.Examples = append(partition.Examples, obj.Key)
-				}
-			} else {
-				partitionMap[prefix] = &types.Partition{
-					Prefix:      prefix,
-					Pattern:     patternName,
-					ObjectCount: 1,
-					TotalSize:   obj.Size,
-					Examples:    []string{obj.Key},
-				}
+// dateDetection is the result of date partition detection in a subtree
+type dateDetection struct {
+	matches  []patternMatch
+	rejected []types.RejectedPattern
+}
+
+// detectDatePartitions detects date-based partition patterns. Patterns are
+// tried in order against the keys not claimed by an earlier pattern, and
+// every pattern covering at least minPatternShare of the objects is kept,
+// so secondary datasets are reported alongside the main one. Candidates
+// are chosen on a sample of keys; the chosen patterns are then counted
+// over the full inventory.
+func (pa *PartitionAnalyzer) detectDatePartitions(objects []types.ObjectMetadata) dateDetection {
+	var result dateDetection
+	sampled := pa.sample(objects)
+	isSample := len(sampled) < len(objects)
+
+	features := make([]keyFeature, len(sampled))
+	var seen keyFeature
+	for i, obj := range sampled {
+		features[i] = keyFeatures(obj.Key)
+		seen |= features[i]
+	}
+
+	minMatches := max(int64(math.Ceil(minPatternShare*float64(len(sampled)))), 1)
+	claimed := make([]bool, len(sampled))
+	var accepted []datePattern
+	for _, pattern := range pa.patterns() {
+		// Skip patterns no key can match without touching the regex
+		if seen&pattern.requires != pattern.requires {
+			continue
+		}
+		match, rejection := pa.groupByPattern(sampled, features, claimed, pattern, minMatches)
+		if rejection != nil {
+			rejection.Sampled = isSample
+			result.rejected = append(result.rejected, *rejection)
+			continue
+		}
+		if match == nil {
+			continue
+		}
+		accepted = append(accepted, pattern)
+		if !isSample {
+			result.matches = append(result.matches, *match)
+		}
+	}
+
+	if isSample && len(accepted) > 0 {
+		// Backfill exact counts with one pass over every key
+		result.matches = pa.countPatterns(objects, accepted)
+	}
+	return result
+}
+
+// groupByPattern groups the unclaimed objects by a date pattern and claims
+// them when the pattern is accepted. It returns nil as soon as the pattern
+// can no longer reach minMatches, and a rejection when enough keys match
+// but the matches are not plausible dates.
+func (pa *PartitionAnalyzer) groupByPattern(objects []types.ObjectMetadata, features []keyFeature, claimed []bool, pattern datePattern, minMatches int64) (*patternMatch, *types.RejectedPattern) {
+	var candidates int64
+	for _, c := range claimed {
+		if !c {
+			candidates++
+		}
+	}
+	if candidates < minMatches {
+		return nil, nil
+	}
+	maxMisses := candidates - minMatches
+	misses := int64(0)
+
+	guard := newDateGuard(pattern)
+	group := newPartitionGroup(pattern)
+	var matched []int
+
+	for i, obj := range objects {
+		if claimed[i] {
+			continue
+		}
+
+		var match []int
+		if features[i]&pattern.requires == pattern.requires {
+			match = pattern.regex.FindStringSubmatchIndex(obj.Key)
+		}
+		if match == nil {
+			misses++
+			if misses > maxMisses {
+				return nil, nil
 			}
+			continue
 		}
+		if !guard.check(obj.Key, match) {
+			continue
+		}
+
+		group.add(obj, match)
+		matched = append(matched, i)
 	}
 
-	// Convert map to slice and sort by prefix
-	var partitions []types.Partition
-	for _, p := range partitionMap {
-		partitions = append(partitions, *p)
+	if rejection := guard.verdict(minMatches); rejection != nil {
+		return nil, rejection
 	}
+	for _, i := range matched {
+		claimed[i] = true
+	}
+	return group.result(), nil
+}
 
-	sort.Slice(partitions, func(i, j int) bool {
-		return partitions[i].Prefix < partitions[j].Prefix
-	})
+// countPatterns assigns every object to the first accepted pattern it
+// matches with a valid date
+func (pa *PartitionAnalyzer) countPatterns(objects []types.ObjectMetadata, patterns []datePattern) []patternMatch {
+	guards := make([]*dateGuard, len(patterns))
+	groups := make([]*partitionGroup, len(patterns))
+	for i, pattern := range patterns {
+		guards[i] = newDateGuard(pattern)
+		groups[i] = newPartitionGroup(pattern)
+	}
 
-	return partitions
+	for _, obj := range objects {
+		f := keyFeatures(obj.Key)
+		for i, pattern := range patterns {
+			if f&pattern.requires != pattern.requires {
+				continue
+			}
+			match := pattern.regex.FindStringSubmatchIndex(obj.Key)
+			if match == nil || !guards[i].check(obj.Key, match) {
+				continue
+			}
+			groups[i].add(obj, match)
+			break
+		}
+	}
+
+	var matches []patternMatch
+	for _, group := range groups {
+		if match := group.result(); match.objects > 0 {
+			matches = append(matches, *match)
+		}
+	}
+	return matches
+}
+
+// partitionGroup accumulates the partitions of one date pattern
+type partitionGroup struct {
+	pattern    datePattern
+	partitions map[string]*types.Partition
+	objects    int64
+	size       int64
+}
+
+// newPartitionGroup creates an empty group for a pattern
+func newPartitionGroup(pattern datePattern) *partitionGroup {
+	return &partitionGroup{pattern: pattern, partitions: make(map[string]*types.Partition)}
+}
+
+// add records an object under its matched partition prefix
+func (g *partitionGroup) add(obj types.ObjectMetadata, match []int) {
+	prefix := obj.Key[match[0]:match[1]]
+	g.objects++
+	g.size += obj.Size
+
+	partition, exists := g.partitions[prefix]
+	if exists {
+		partition.ObjectCount++
+		partition.TotalSize += obj.Size
+		observeModified(partition, obj.LastModified)
+		if len(partition.Examples) < 3 {
+			partition.Examples = append(partition.Examples, obj.Key)
+		}
+	} else {
+		partition = &types.Partition{
+			Prefix:      prefix,
+			Pattern:     g.pattern.name,
+			Date:        matchDate(obj.Key, match),
+			ObjectCount: 1,
+			TotalSize:   obj.Size,
+			Oldest:      obj.LastModified,
+			Newest:      obj.LastModified,
+			Examples:    []string{obj.Key},
+		}
+		g.partitions[prefix] = partition
+	}
+
+	if writtenOutsidePeriod(*partition, obj.LastModified) {
+		partition.OutsideObjects++
+		partition.OutsideSize += obj.Size
+	}
+}
+
+// result returns the group's partitions sorted by prefix
+func (g *partitionGroup) result() *patternMatch {
+	match := &patternMatch{pattern: g.pattern, objects: g.objects, size: g.size}
+	for _, p := range g.partitions {
+		match.partitions = append(match.partitions, *p)
+	}
+	sort.Slice(match.partitions, func(i, j int) bool {
+		return match.partitions[i].Prefix < match.partitions[j].Prefix
+	})
+	return match
 }
 
 // detectHierarchicalPartitions detects partitions based on common prefixes
@@ -125,6 +527,7 @@ func (pa *PartitionAnalyzer) detectHierarchicalPartitions(objects []types.Object
 			if partition, exists := prefixMap[prefix]; exists {
 				partition.ObjectCount++
 				partition.TotalSize += obj.Size
+				observeModified(partition, obj.LastModified)
 				if len(partition.Examples) < 3 {
 					partition.Examples = append(partition.Examples, obj.Key)
 				}
@@ -134,6 +537,8 @@ func (pa *PartitionAnalyzer) detectHierarchicalPartitions(objects []types.Object
 					Pattern:     "hierarchical (top-level prefix)",
 					ObjectCount: 1,
 					TotalSize:   obj.Size,
+					Oldest:      obj.LastModified,
+					Newest:      obj.LastModified,
 					Examples:    []string{obj.Key},
 				}
 			}
@@ -152,8 +557,21 @@ func (pa *PartitionAnalyzer) detectHierarchicalPartitions(objects []types.Object
 	}
 
 	sort.Slice(partitions, func(i, j int) bool {
-		return partitions[i].ObjectCount > partitions[j].ObjectCount
+		if partitions[i].ObjectCount != partitions[j].ObjectCount {
+			return partitions[i].ObjectCount > partitions[j].ObjectCount
+		}
+		return partitions[i].Prefix < partitions[j].Prefix
 	})
 
 	return partitions
 }
+
+// observeModified widens a partition's LastModified range
+func observeModified(p *types.Partition, modified time.Time) {
+	if modified.Before(p.Oldest) {
+		p.Oldest = modified
+	}
+	if modified.After(p.Newest) {
+		p.Newest = modified
+	}
+}
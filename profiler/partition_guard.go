@@ -0,0 +1,134 @@
+package profiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Thresholds for accepting a date pattern
+const (
+	// minDateYear is the earliest plausible partition year
+	minDateYear = 1970
+	// maxInvalidDateShare is the share of matches that may fail date
+	// validation before the pattern is treated as a false positive
+	maxInvalidDateShare = 0.1
+	// minDepthShare is the share of matches of a generic pattern that must
+	// start at the same path depth
+	minDepthShare = 0.9
+)
+
+// dateGuard validates the matches of one date pattern: every match must be
+// a real calendar date in a plausible year window, and generic patterns
+// must appear at a consistent path depth across keys. This keeps version
+// numbers and IDs such as 2024/01/99 or 1234-56-78 from being reported as
+// date partitions.
+type dateGuard struct {
+	pattern  datePattern
+	maxYear  int
+	valid    int64
+	invalid  int64
+	examples []string
+	depths   map[int]int64
+}
+
+// newDateGuard creates a guard for a pattern
+func newDateGuard(pattern datePattern) *dateGuard {
+	return &dateGuard{
+		pattern: pattern,
+		maxYear: time.Now().Year() + 1,
+		depths:  make(map[int]int64),
+	}
+}
+
+// check reports whether a regex match is a plausible date and records it.
+// match holds the submatch indexes; the groups are year, month and
+// optionally day.
+func (g *dateGuard) check(key string, match []int) bool {
+	if !g.validDate(key, match) {
+		g.invalid++
+		if len(g.examples) < 3 {
+			g.examples = append(g.examples, key)
+		}
+		return false
+	}
+
+	g.valid++
+	if g.pattern.generic {
+		g.depths[strings.Count(key[:match[0]], "/")]++
+	}
+	return true
+}
+
+// validDate parses the captured year, month and day
+func (g *dateGuard) validDate(key string, match []int) bool {
+	year, month, day := matchFields(key, match)
+	if year < minDateYear || year > g.maxYear || month < 1 || month > 12 {
+		return false
+	}
+	if day == 0 {
+		return true
+	}
+
+	// Day 0 of the following month is the last day of this one
+	lastDay := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return day >= 1 && day <= lastDay
+}
+
+// matchFields returns the captured year, month and day of a match; day is
+// 0 for patterns without one and fields that fail to parse are -1
+func matchFields(key string, match []int) (year, month, day int) {
+	group := func(n int) int {
+		v, err := strconv.Atoi(key[match[2*n]:match[2*n+1]])
+		if err != nil {
+			return -1
+		}
+		return v
+	}
+
+	year, month = group(1), group(2)
+	if len(match) >= 8 {
+		day = group(3)
+	}
+	return year, month, day
+}
+
+// matchDate returns the first day covered by a validated match
+func matchDate(key string, match []int) time.Time {
+	year, month, day := matchFields(key, match)
+	return time.Date(year, time.Month(month), max(day, 1), 0, 0, 0, 0, time.UTC)
+}
+
+// verdict returns a rejection when the pattern's matches are not
+// convincing dates, given the number of valid matches the pattern needs
+func (g *dateGuard) verdict(minMatches int64) *types.RejectedPattern {
+	matched := g.valid + g.invalid
+	rejection := &types.RejectedPattern{
+		Pattern:  g.pattern.name,
+		Matches:  matched,
+		Invalid:  g.invalid,
+		Examples: g.examples,
+	}
+
+	if g.invalid > 0 && (float64(g.invalid)/float64(matched) > maxInvalidDateShare || g.valid < minMatches) {
+		rejection.Reason = fmt.Sprintf("%d of %d matches are not valid dates between %d and %d",
+			g.invalid, matched, minDateYear, g.maxYear)
+		return rejection
+	}
+
+	if g.pattern.generic && g.valid > 0 {
+		var top int64
+		for _, count := range g.depths {
+			top = max(top, count)
+		}
+		if float64(top)/float64(g.valid) < minDepthShare {
+			rejection.Reason = fmt.Sprintf("matches appear at %d different path depths", len(g.depths))
+			return rejection
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package profiler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// partitionSynonyms groups Hive partition column names that mean the same
+// thing; the first name of each group is the suggested canonical name
+var partitionSynonyms = [][]string{
+	{"dt", "date", "ds", "event_date", "partition_date"},
+	{"year", "yr", "yyyy", "y"},
+	{"month", "mon", "mm", "m"},
+	{"day", "dd", "d"},
+	{"hour", "hr", "hh", "h"},
+}
+
+// paddedColumns are partition columns whose values should be zero-padded
+var paddedColumns = map[string]bool{
+	"month": true, "mon": true, "mm": true, "m": true,
+	"day": true, "dd": true, "d": true,
+	"hour": true, "hr": true, "hh": true, "h": true,
+}
+
+// hiveColumn collects the observed spellings and values of a partition column
+type hiveColumn struct {
+	spellings map[string]string // spelling -> example key
+	padded    string            // example key with a zero-padded value
+	unpadded  string            // example key with a non-padded value
+	values    map[string]map[string]string
+}
+
+// LintPartitions checks Hive-style partition names in object keys for
+// inconsistencies that silently drop data from Athena queries: synonymous
+// column names, mixed zero-padding and mixed case
+func (pa *PartitionAnalyzer) LintPartitions(objects []types.ObjectMetadata) []types.PartitionIssue {
+	columns := make(map[string]*hiveColumn)
+
+	for _, obj := range objects {
+		segments := strings.Split(obj.Key, "/")
+		// The last segment is the file name, not a partition
+		for _, segment := range segments[:len(segments)-1] {
+			name, value, ok := strings.Cut(segment, "=")
+			if !ok || name == "" {
+				continue
+			}
+
+			lower := strings.ToLower(name)
+			col, exists := columns[lower]
+			if !exists {
+				col = &hiveColumn{
+					spellings: make(map[string]string),
+					values:    make(map[string]map[string]string),
+				}
+				columns[lower] = col
+			}
+			if _, seen := col.spellings[name]; !seen {
+				col.spellings[name] = obj.Key
+			}
+
+			if paddedColumns[lower] && isDigits(value) {
+				if len(value) == 1 && col.unpadded == "" {
+					col.unpadded = obj.Key
+				} else if len(value) == 2 && value[0] == '0' && col.padded == "" {
+					col.padded = obj.Key
+				}
+			}
+
+			lowerValue := strings.ToLower(value)
+			if col.values[lowerValue] == nil {
+				col.values[lowerValue] = make(map[string]string)
+			}
+			if _, seen := col.values[lowerValue][value]; !seen && len(col.values[lowerValue]) < 2 {
+				col.values[lowerValue][value] = obj.Key
+			}
+		}
+	}
+
+	var issues []types.PartitionIssue
+
+	// Synonymous column names
+	for _, group := range partitionSynonyms {
+		var used []string
+		var examples []string
+		for _, name := range group {
+			if col, ok := columns[name]; ok {
+				used = append(used, name+"=")
+				examples = append(examples, firstExample(col.spellings))
+			}
+		}
+		if len(used) > 1 {
+			issues = append(issues, types.PartitionIssue{
+				Issue:      fmt.Sprintf("Mixed partition column names for the same dimension: %s", strings.Join(used, ", ")),
+				Examples:   examples,
+				Suggestion: fmt.Sprintf("Normalize to %s= so a single partition column covers all data", group[0]),
+			})
+		}
+	}
+
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		col := columns[name]
+
+		// Mixed zero-padding
+		if col.padded != "" && col.unpadded != "" {
+			issues = append(issues, types.PartitionIssue{
+				Issue:      fmt.Sprintf("Partition column %s= mixes zero-padded and non-padded values", name),
+				Examples:   []string{col.padded, col.unpadded},
+				Suggestion: "Zero-pad values to two digits (e.g. 01) so string comparisons and projections match",
+			})
+		}
+
+		// Mixed case in column names
+		if len(col.spellings) > 1 {
+			var spellings, examples []string
+			for spelling, example := range col.spellings {
+				spellings = append(spellings, spelling+"=")
+				examples = append(examples, example)
+			}
+			sort.Strings(spellings)
+			sort.Strings(examples)
+			issues = append(issues, types.PartitionIssue{
+				Issue:      fmt.Sprintf("Partition column name uses mixed case: %s", strings.Join(spellings, ", ")),
+				Examples:   examples,
+				Suggestion: fmt.Sprintf("Use lowercase %s= (Athena/Glue lowercase column names)", name),
+			})
+		}
+
+		// Mixed case in values
+		var mixedValues, examples []string
+		for lowerValue, variants := range col.values {
+			if len(variants) > 1 {
+				mixedValues = append(mixedValues, lowerValue)
+				for _, example := range variants {
+					examples = append(examples, example)
+				}
+			}
+		}
+		if len(mixedValues) > 0 {
+			sort.Strings(mixedValues)
+			sort.Strings(examples)
+			if len(examples) > 3 {
+				examples = examples[:3]
+			}
+			issues = append(issues, types.PartitionIssue{
+				Issue:      fmt.Sprintf("Partition column %s= has values differing only by case: %s", name, strings.Join(mixedValues, ", ")),
+				Examples:   examples,
+				Suggestion: "Normalize value case; partition values are compared case-sensitively",
+			})
+		}
+	}
+
+	return issues
+}
+
+// firstExample returns the lexically first example key in a spelling map
+func firstExample(spellings map[string]string) string {
+	first := ""
+	for _, example := range spellings {
+		if first == "" || example < first {
+			first = example
+		}
+	}
+	return first
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
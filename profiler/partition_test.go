@@ -0,0 +1,63 @@
+package profiler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// benchmarkKeys is the inventory size the prescreen was measured on
+const benchmarkKeys = 200000
+
+// datePartitionedKeys returns keys laid out as year=/month=/day= partitions
+func datePartitionedKeys(n int) []types.ObjectMetadata {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	objects := make([]types.ObjectMetadata, n)
+	for i := range objects {
+		day := start.AddDate(0, 0, i%730)
+		objects[i] = types.ObjectMetadata{
+			Key:          fmt.Sprintf("events/year=%04d/month=%02d/day=%02d/part-%05d.parquet", day.Year(), day.Month(), day.Day(), i),
+			Size:         int64(1024 + i%4096),
+			LastModified: day,
+			StorageClass: "STANDARD",
+		}
+	}
+	return objects
+}
+
+// flatKeys returns keys with numbers in them but no date partitions, the
+// case the prescreen lets skip most regex work
+func flatKeys(n int) []types.ObjectMetadata {
+	modified := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	objects := make([]types.ObjectMetadata, n)
+	for i := range objects {
+		objects[i] = types.ObjectMetadata{
+			Key:          fmt.Sprintf("assets/user%06d/img_%08d-thumb.jpg", i%5000, i),
+			Size:         int64(1024 + i%4096),
+			LastModified: modified,
+			StorageClass: "STANDARD",
+		}
+	}
+	return objects
+}
+
+func BenchmarkDetectDatePartitions(b *testing.B) {
+	sets := []struct {
+		name    string
+		objects []types.ObjectMetadata
+	}{
+		{"partitioned", datePartitionedKeys(benchmarkKeys)},
+		{"unpartitioned", flatKeys(benchmarkKeys)},
+	}
+	for _, set := range sets {
+		b.Run(set.name, func(b *testing.B) {
+			pa := NewPartitionAnalyzer(1)
+			b.ResetTimer()
+			for range b.N {
+				pa.detectDatePartitions(set.objects)
+			}
+		})
+	}
+}
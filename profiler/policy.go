@@ -0,0 +1,250 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// policyQuery is the rule user policies define: a set of violation
+// messages, or of objects with a "msg" field
+const policyQuery = "data.s3profiler.deny"
+
+// PolicyEvaluator checks profile results against Rego policies by running
+// `opa eval`, so the OPA runtime is not linked into the profiler
+type PolicyEvaluator struct {
+	paths   []string
+	opaPath string
+}
+
+// NewPolicyEvaluator creates an evaluator for the given .rego files or
+// directories. opaPath is the opa binary, looked up in PATH when empty.
+func NewPolicyEvaluator(paths []string, opaPath string) (*PolicyEvaluator, error) {
+	if len(paths) == 0 {
+		return &PolicyEvaluator{}, nil
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("invalid policy path: %w", err)
+		}
+	}
+	if opaPath == "" {
+		opaPath = "opa"
+	}
+	resolved, err := exec.LookPath(opaPath)
+	if err != nil {
+		return nil, fmt.Errorf("--policy needs the opa binary (https://www.openpolicyagent.org/docs/latest/#running-opa): %w", err)
+	}
+	return &PolicyEvaluator{paths: paths, opaPath: resolved}, nil
+}
+
+// Enabled reports whether policies were given
+func (pe *PolicyEvaluator) Enabled() bool {
+	return len(pe.paths) > 0
+}
+
+// policyInput is the input document policies are evaluated against
+type policyInput struct {
+	Bucket        policyBucket             `json:"bucket"`
+	Summary       policySummary            `json:"summary"`
+	Configuration policyConfiguration      `json:"configuration"`
+	Partitions    int                      `json:"partitions"`
+	Findings      []policyFinding          `json:"findings"`
+	Enrichment    *types.EnrichmentSummary `json:"enrichment,omitempty"`
+}
+
+type policyBucket struct {
+	Name         string            `json:"name"`
+	Region       string            `json:"region"`
+	CreationDate time.Time         `json:"creation_date"`
+	Tags         map[string]string `json:"tags"`
+	// Versioning is "Enabled", "Suspended" or "" when never enabled
+	Versioning string `json:"versioning"`
+}
+
+type policySummary struct {
+	TotalObjects   int64                         `json:"total_objects"`
+	TotalSize      int64                         `json:"total_size"`
+	EstimatedCost  float64                       `json:"estimated_cost"`
+	StorageClasses map[string]policyStorageClass `json:"storage_classes"`
+	Truncated      bool                          `json:"truncated"`
+}
+
+type policyStorageClass struct {
+	Count int64   `json:"count"`
+	Size  int64   `json:"size"`
+	Cost  float64 `json:"cost"`
+}
+
+type policyConfiguration struct {
+	PublicAccessBlockOff []string              `json:"public_access_block_off"`
+	PolicyPublic         bool                  `json:"policy_public"`
+	DefaultEncryption    string                `json:"default_encryption"`
+	LifecycleRules       []policyLifecycleRule `json:"lifecycle_rules"`
+	Inventories          int                   `json:"inventories"`
+	Website              bool                  `json:"website"`
+	EventBridgeEnabled   bool                  `json:"eventbridge_enabled"`
+	Warnings             []string              `json:"warnings"`
+	// Errors are checks that could not be completed, so policies can tell
+	// a missing setting from one that could not be read
+	Errors []string `json:"errors"`
+}
+
+type policyLifecycleRule struct {
+	ID      string   `json:"id"`
+	Enabled bool     `json:"enabled"`
+	Prefix  string   `json:"prefix"`
+	Actions []string `json:"actions"`
+}
+
+type policyFinding struct {
+	Rule     string `json:"rule"`
+	Level    string `json:"level"`
+	Severity string `json:"severity"`
+	Prefix   string `json:"prefix,omitempty"`
+	Message  string `json:"message"`
+}
+
+// buildPolicyInput gathers the profile results policies can refer to.
+// Lists are never null, so policies can count them without guards.
+func buildPolicyInput(summary *types.BucketSummary, config *types.BucketConfiguration, metadata *types.MetadataSummary, partitions []types.Partition, findings []types.Finding) policyInput {
+	input := policyInput{
+		Bucket: policyBucket{
+			Name:         summary.Name,
+			Region:       summary.Region,
+			CreationDate: summary.CreationDate,
+			Tags:         config.Tags,
+		},
+		Summary: policySummary{
+			TotalObjects:   summary.TotalObjects,
+			TotalSize:      summary.TotalSize,
+			EstimatedCost:  summary.EstimatedCost,
+			StorageClasses: make(map[string]policyStorageClass),
+			Truncated:      summary.Truncated,
+		},
+		Configuration: policyConfiguration{
+			PublicAccessBlockOff: append([]string{}, config.PublicAccessBlockOff...),
+			PolicyPublic:         config.PolicyPublic,
+			DefaultEncryption:    config.DefaultEncryption,
+			Inventories:          len(config.Inventories),
+			Website:              config.Website != nil,
+			EventBridgeEnabled:   config.EventBridgeEnabled,
+			LifecycleRules:       []policyLifecycleRule{},
+			Warnings:             append([]string{}, config.Warnings...),
+			Errors:               append([]string{}, config.Errors...),
+		},
+		Partitions: len(partitions),
+		Findings:   []policyFinding{},
+		Enrichment: metadata.Enrichment,
+	}
+	if input.Bucket.Tags == nil {
+		input.Bucket.Tags = map[string]string{}
+	}
+	if summary.Versioning != nil {
+		input.Bucket.Versioning = summary.Versioning.Status
+	}
+	for class, stats := range summary.StorageClasses {
+		input.Summary.StorageClasses[class] = policyStorageClass{Count: stats.Count, Size: stats.Size, Cost: stats.Cost}
+	}
+	for _, rule := range config.LifecycleRules {
+		input.Configuration.LifecycleRules = append(input.Configuration.LifecycleRules, policyLifecycleRule{
+			ID:      rule.ID,
+			Enabled: rule.Enabled,
+			Prefix:  rule.Prefix,
+			Actions: rule.Actions,
+		})
+	}
+	for _, f := range findings {
+		input.Findings = append(input.Findings, policyFinding{
+			Rule:     f.Rule.ID,
+			Level:    f.Rule.Level,
+			Severity: string(f.Severity),
+			Prefix:   f.Prefix,
+			Message:  f.Message,
+		})
+	}
+	return input
+}
+
+// opaEvalOutput is the JSON printed by `opa eval --format json`
+type opaEvalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+	Errors json.RawMessage `json:"errors"`
+}
+
+// Evaluate runs the policies against one bucket's results and returns the
+// violation messages, sorted
+func (pe *PolicyEvaluator) Evaluate(ctx context.Context, input policyInput) ([]string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"eval", "--format", "json", "--stdin-input"}
+	for _, path := range pe.paths {
+		args = append(args, "--data", path)
+	}
+	args = append(args, policyQuery)
+
+	cmd := exec.CommandContext(ctx, pe.opaPath, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	var out opaEvalOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("failed to evaluate policies: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("failed to evaluate policies: %s", out.Errors)
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("failed to evaluate policies: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	if len(out.Result) == 0 || len(out.Result[0].Expressions) == 0 {
+		return nil, errors.New("policies do not define " + policyQuery)
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(out.Result[0].Expressions[0].Value, &values); err != nil {
+		return nil, fmt.Errorf("%s must be a set of messages: %w", policyQuery, err)
+	}
+
+	var violations []string
+	for _, v := range values {
+		var message string
+		if err := json.Unmarshal(v, &message); err == nil {
+			violations = append(violations, message)
+			continue
+		}
+		var object struct {
+			Msg string `json:"msg"`
+		}
+		if err := json.Unmarshal(v, &object); err != nil || object.Msg == "" {
+			violations = append(violations, string(v))
+			continue
+		}
+		violations = append(violations, object.Msg)
+	}
+	sort.Strings(violations)
+
+	return violations, nil
+}
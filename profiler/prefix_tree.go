@@ -0,0 +1,109 @@
+package profiler
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// prefixBuilder accumulates a prefix tree with map-based children
+type prefixBuilder struct {
+	node     *types.PrefixNode
+	children map[string]*prefixBuilder
+}
+
+// BuildPrefixTree aggregates object sizes into a tree of key prefixes rooted
+// at the bucket. Objects are attributed to the prefix that contains them, so
+// the tree has one node per "directory" rather than per object.
+func BuildPrefixTree(bucketName string, objects []types.ObjectMetadata) *types.PrefixNode {
+	root := newPrefixBuilder(bucketName)
+
+	for _, obj := range objects {
+		segments := strings.Split(obj.Key, "/")
+		// Drop the object name; a trailing slash yields an empty last segment
+		segments = segments[:len(segments)-1]
+
+		current := root
+		current.add(obj)
+		for _, segment := range segments {
+			child, exists := current.children[segment]
+			if !exists {
+				child = newPrefixBuilder(segment)
+				current.children[segment] = child
+			}
+			current = child
+			current.add(obj)
+		}
+		current.node.SelfSize += obj.Size
+		current.node.SelfCount++
+	}
+
+	return root.build()
+}
+
+// PrefixTreeFromSnapshot rebuilds the prefix tree recorded in a snapshot.
+// Snapshots keep prefixes to a limited depth and no object timestamps, so
+// the tree is shallower than BuildPrefixTree's and carries no ages.
+func PrefixTreeFromSnapshot(snapshot *types.Snapshot) *types.PrefixNode {
+	root := newPrefixBuilder(snapshot.Bucket)
+
+	for _, stats := range snapshot.Prefixes {
+		var segments []string
+		if stats.Prefix != "/" {
+			segments = strings.Split(strings.TrimSuffix(stats.Prefix, "/"), "/")
+		}
+
+		current := root
+		current.node.Size += stats.Size
+		current.node.ObjectCount += stats.ObjectCount
+		for _, segment := range segments {
+			child, exists := current.children[segment]
+			if !exists {
+				child = newPrefixBuilder(segment)
+				current.children[segment] = child
+			}
+			current = child
+			current.node.Size += stats.Size
+			current.node.ObjectCount += stats.ObjectCount
+		}
+		current.node.SelfSize += stats.Size
+		current.node.SelfCount += stats.ObjectCount
+	}
+
+	return root.build()
+}
+
+func newPrefixBuilder(name string) *prefixBuilder {
+	return &prefixBuilder{
+		node:     &types.PrefixNode{Name: name},
+		children: make(map[string]*prefixBuilder),
+	}
+}
+
+// add counts an object under the builder's prefix
+func (pb *prefixBuilder) add(obj types.ObjectMetadata) {
+	pb.node.Size += obj.Size
+	pb.node.ObjectCount++
+	if pb.node.Oldest.IsZero() || obj.LastModified.Before(pb.node.Oldest) {
+		pb.node.Oldest = obj.LastModified
+	}
+	if obj.LastModified.After(pb.node.Newest) {
+		pb.node.Newest = obj.LastModified
+	}
+}
+
+// build converts the builder into a PrefixNode with children sorted by size
+func (pb *prefixBuilder) build() *types.PrefixNode {
+	for _, child := range pb.children {
+		pb.node.Children = append(pb.node.Children, child.build())
+	}
+	sort.Slice(pb.node.Children, func(i, j int) bool {
+		a, b := pb.node.Children[i], pb.node.Children[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Name < b.Name
+	})
+	return pb.node
+}
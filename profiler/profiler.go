@@ -2,11 +2,19 @@ package profiler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/sampler"
+	"github.com/yourusername/s3-profiler/types"
 )
 
 // Profiler orchestrates the profiling of S3 buckets
@@ -15,85 +23,1053 @@ type Profiler struct {
 	bucketAnalyzer    *BucketAnalyzer
 	metadataAnalyzer  *MetadataAnalyzer
 	partitionAnalyzer *PartitionAnalyzer
+	versionAnalyzer   *VersionAnalyzer
+	configAnalyzer    *ConfigAnalyzer
+	dimensionAnalyzer *DimensionAnalyzer
+	enrichAnalyzer    *EnrichmentAnalyzer
+	tagAnalyzer       *TagAnalyzer
+	parquetAnalyzer   *ParquetAnalyzer
+	contentAnalyzer   *ContentAnalyzer
+	compressAnalyzer  *CompressibilityAnalyzer
+	accessSimulator   *AccessSimulator
+	cache             *AnalysisCache
 	writer            *output.Writer
+	flameGraph        output.FlameGraphFormat
+	objectCounts      ObjectCountFunc
+	bucketSizes       BucketSizeFunc
+	glueTable         GlueTableFunc
+	saveRun           RunRecorder
+	recordMetrics     MetricsRecorder
+	runHistory        RunHistoryFunc
+	alerter           *CapacityAlerter
+	lineage           *OpenLineageEmitter
+	events            *EventPublisher
+	objectStream      *ObjectStreamer
+	policies          *PolicyEvaluator
+	progress          ProgressReporter
+	config            types.ProfileConfig
+
+	// violations counts policy violations across buckets for --fail-on;
+	// profiled holds the snapshots of the buckets profiled so far, sorted
+	// by their runOrder keys in order
+	mu         sync.Mutex
+	violations int
+	order      []string
+	profiled   []*types.Snapshot
+	// workbook holds the buckets of the run for --xlsx, in the same order
+	workbook []output.WorkbookBucket
 }
 
 // NewProfiler creates a new profiler instance
-func NewProfiler(s3Client *s3.Client, outputDir string, limit int64) *Profiler {
+func NewProfiler(s3Client *s3.Client, config types.ProfileConfig) (*Profiler, error) {
+	compression, err := output.ParseCompression(config.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	encryption, err := output.ParseEncryption(config.EncryptOutput)
+	if err != nil {
+		return nil, err
+	}
+	// Checkpoints and the analysis cache keep keys in plain text, and
+	// encrypted ones could not be read back without the recipients'
+	// identities, so encrypted runs write neither
+	if encryption != nil {
+		if config.Resume {
+			return nil, fmt.Errorf("listing checkpoints are not written when output is encrypted, so there is nothing to resume")
+		}
+		if config.CacheDir != "" {
+			return nil, fmt.Errorf("the analysis cache keeps example keys in plain text and cannot be used when output is encrypted")
+		}
+		config.CheckpointDir = ""
+	}
+
+	sortBy, err := output.ParseSortField(config.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	flameGraph, err := output.ParseFlameGraphFormat(config.FlameGraph)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.NoContentAccess {
+		if conflicts := contentAccessOptions(config); len(conflicts) > 0 {
+			return nil, fmt.Errorf("--no-content-access cannot be combined with %s, which read objects", strings.Join(conflicts, ", "))
+		}
+	}
+
+	if config.PartitionSampleRate < 0 || config.PartitionSampleRate > 1 {
+		return nil, fmt.Errorf("invalid partition sample rate %g (expected a fraction between 0 and 1)", config.PartitionSampleRate)
+	}
+
+	if config.GlueTable != "" {
+		if _, _, err := parseGlueTable(config.GlueTable); err != nil {
+			return nil, err
+		}
+	}
+
+	language, err := output.ParseLanguage(config.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := newStoragePrices(config.StoragePrices)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensionAnalyzer, err := NewDimensionAnalyzer(config.Dimensions)
+	if err != nil {
+		return nil, err
+	}
+	dimensionAnalyzer.prices = prices
+
+	events, err := NewEventPublisher(config.EventTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	objectStream, err := NewObjectStreamer(config.ObjectStreamTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := NewPolicyEvaluator(config.Policies, config.OPAPath)
+	if err != nil {
+		return nil, err
+	}
+
+	alerter, err := NewCapacityAlerter(config.AlertTarget, config.AlertCapacity, config.AlertBudget, config.AlertWithinDays)
+	if err != nil {
+		return nil, err
+	}
+
+	accessSimulator, err := NewAccessSimulator(s3Client, config.AccessRates, config.AccessLogDays, config.EnrichWorkers, config.LifecycleArchive)
+	if err != nil {
+		return nil, err
+	}
+	accessSimulator.prices = prices
+
+	partitionAnalyzer := NewPartitionAnalyzer(config.PartitionSampleRate)
+	if err := partitionAnalyzer.SetPatterns(config.PartitionPatterns); err != nil {
+		return nil, err
+	}
+	if err := partitionAnalyzer.SetQueryWindows(config.QueryWindows); err != nil {
+		return nil, err
+	}
+
+	scope, err := newKeyScope(config.Prefixes, config.Include, config.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	if config.Timezone != "" {
+		location, err = time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", config.Timezone, err)
+		}
+	}
+
+	currency, err := NewCurrency(config.Currency, config.ExchangeRates)
+	if err != nil {
+		return nil, err
+	}
+	bucketCurrencies := make(map[string]*output.Currency, len(config.BucketCurrencies))
+	for bucket, code := range config.BucketCurrencies {
+		c, err := NewCurrency(code, config.ExchangeRates)
+		if err != nil {
+			return nil, err
+		}
+		// A nil currency keeps the bucket's costs in US dollars
+		bucketCurrencies[bucket] = c
+	}
+
+	writer := output.NewWriter(config.OutputDir, output.Options{
+		Compression:      compression,
+		Encryption:       encryption,
+		Redact:           config.Redact,
+		RedactSalt:       config.RedactSalt,
+		Location:         location,
+		Language:         language,
+		Currency:         currency,
+		BucketCurrencies: bucketCurrencies,
+		Table: output.TableOptions{
+			SortBy:  sortBy,
+			Desc:    config.SortDesc,
+			MaxRows: config.MaxRows,
+		},
+	})
+
+	bucketAnalyzer := NewBucketAnalyzer(s3Client, config.Limit)
+	bucketAnalyzer.prices = prices
+	bucketAnalyzer.stream = objectStream
+	bucketAnalyzer.useInventory = config.UseInventory
+	bucketAnalyzer.anonymous = config.NoSignRequest
+	bucketAnalyzer.openData = config.OpenData
+	bucketAnalyzer.checkpoints = NewCheckpointer(config.CheckpointDir, config.CheckpointInterval, config.Resume)
+	bucketAnalyzer.listWorkers = config.ListWorkers
+	bucketAnalyzer.adaptive = !config.FixedConcurrency
+	bucketAnalyzer.lifecycleArchive = config.LifecycleArchive
+	bucketAnalyzer.scope = scope
+	if config.NDJSON {
+		bucketAnalyzer.objectLines = writer
+	}
+	bucketAnalyzer.interim = NewInterimSummaries(writer, config.InterimInterval)
+
+	enrichAnalyzer := NewEnrichmentAnalyzer(s3Client, config.EnrichSamples, config.EnrichWorkers)
+	enrichAnalyzer.adaptive = !config.FixedConcurrency
+
+	tagAnalyzer := NewTagAnalyzer(s3Client, config.TagSamples, config.EnrichWorkers)
+	tagAnalyzer.adaptive = !config.FixedConcurrency
+	tagAnalyzer.prices = prices
+
+	versionAnalyzer := NewVersionAnalyzer(s3Client, config.Limit)
+	versionAnalyzer.prices = prices
+	compressAnalyzer := NewCompressibilityAnalyzer(s3Client, config.Compressibility, config.EnrichWorkers)
+	compressAnalyzer.prices = prices
+
 	return &Profiler{
 		s3Client:          s3Client,
-		bucketAnalyzer:    NewBucketAnalyzer(s3Client, limit),
-		metadataAnalyzer:  NewMetadataAnalyzer(),
-		partitionAnalyzer: NewPartitionAnalyzer(),
-		writer:            output.NewWriter(outputDir),
+		bucketAnalyzer:    bucketAnalyzer,
+		metadataAnalyzer:  NewMetadataAnalyzer(config.Top),
+		partitionAnalyzer: partitionAnalyzer,
+		versionAnalyzer:   versionAnalyzer,
+		configAnalyzer:    NewConfigAnalyzer(s3Client, config.ExpectNotifications),
+		dimensionAnalyzer: dimensionAnalyzer,
+		enrichAnalyzer:    enrichAnalyzer,
+		tagAnalyzer:       tagAnalyzer,
+		parquetAnalyzer:   NewParquetAnalyzer(s3Client, max(config.ParquetStats, config.ParquetSchema)),
+		contentAnalyzer:   NewContentAnalyzer(s3Client, sampler.Default, config.SampleContent),
+		compressAnalyzer:  compressAnalyzer,
+		accessSimulator:   accessSimulator,
+		cache:             NewAnalysisCache(config.CacheDir),
+		events:            events,
+		objectStream:      objectStream,
+		policies:          policies,
+		alerter:           alerter,
+		lineage:           NewOpenLineageEmitter(config.OpenLineageURL, config.OpenLineageNamespace, config.OpenLineageAPIKey),
+		writer:            writer,
+		flameGraph:        flameGraph,
+		progress:          defaultProgress(),
+		config:            config,
+	}, nil
+}
+
+// contentAccessOptions returns the options set in config that need to
+// read objects with GetObject or HeadObject
+func contentAccessOptions(config types.ProfileConfig) []string {
+	var options []string
+	if config.UseInventory {
+		options = append(options, "--use-inventory")
+	}
+	if config.EnrichSamples > 0 {
+		options = append(options, "--enrich")
+	}
+	if config.SampleContent > 0 {
+		options = append(options, "--sample-content")
+	}
+	if config.ParquetStats > 0 {
+		options = append(options, "--parquet-stats")
+	}
+	if config.ParquetSchema > 0 {
+		options = append(options, "--parquet-schema")
 	}
+	if config.Compressibility > 0 {
+		options = append(options, "--compressibility")
+	}
+	if config.AccessLogDays > 0 {
+		options = append(options, "--access-logs")
+	}
+	return options
+}
+
+// EnableAccessAnalyzer adds IAM Access Analyzer findings to the
+// configuration audit using the given lookup function
+func (p *Profiler) EnableAccessAnalyzer(fn AccessFindingsFunc) {
+	p.configAnalyzer.accessFindings = fn
 }
 
-// ProfileBucket profiles a single S3 bucket
+// EnableStorageLens adds Storage Lens configurations to the chargeable
+// feature inventory using the given lookup function
+func (p *Profiler) EnableStorageLens(fn StorageLensFunc) {
+	p.configAnalyzer.storageLens = fn
+}
+
+// EnableObjectCounts uses the given lookup to extrapolate totals when
+// --limit truncates the listing
+func (p *Profiler) EnableObjectCounts(fn ObjectCountFunc) {
+	p.objectCounts = fn
+}
+
+// EnableBucketSizes reconciles --billable-size with the CloudWatch
+// BucketSizeBytes metrics returned by fn
+func (p *Profiler) EnableBucketSizes(fn BucketSizeFunc) {
+	p.bucketSizes = fn
+}
+
+// EnableGlueCatalog compares detected partition directories with the
+// --glue-table catalog entry using the given lookup function
+func (p *Profiler) EnableGlueCatalog(fn GlueTableFunc) {
+	p.glueTable = fn
+}
+
+// EnableResultsStore records each completed run, its prefixes and its
+// partitions using the given function
+func (p *Profiler) EnableResultsStore(fn RunRecorder) {
+	p.saveRun = fn
+}
+
+// EnableMetrics passes the gauges of each completed run to fn, e.g. for a
+// Prometheus scrape endpoint
+func (p *Profiler) EnableMetrics(fn MetricsRecorder) {
+	p.recordMetrics = fn
+}
+
+// EnableOwnershipCheck verifies that each bucket belongs to the account
+// returned by fn and annotates reports of buckets that do not
+func (p *Profiler) EnableOwnershipCheck(fn AccountIDFunc) {
+	p.bucketAnalyzer.accountID = fn
+}
+
+// EnableCapacityAlerts projects growth from the stored runs listed by
+// history for --alert-capacity and --alert-budget, publishing alerts for
+// SNS topic targets with publish
+func (p *Profiler) EnableCapacityAlerts(history RunHistoryFunc, publish SNSPublishFunc) {
+	p.runHistory = history
+	p.alerter.publishSNS = publish
+}
+
+// SetProgressReporter routes progress messages and warnings to r instead
+// of stdout; use DiscardProgress to silence them
+func (p *Profiler) SetProgressReporter(r ProgressReporter) {
+	p.progress = r
+	p.bucketAnalyzer.progress = r
+	p.versionAnalyzer.progress = r
+}
+
+// PolicyViolations returns the number of policy violations found so far
+// across all profiled buckets
+func (p *Profiler) PolicyViolations() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.violations
+}
+
+// Profiled returns the snapshots of the buckets profiled so far, by bucket
+// name and then prefix
+func (p *Profiler) Profiled() []*types.Snapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*types.Snapshot(nil), p.profiled...)
+}
+
+// WriteWorkbook writes the Excel workbook of the buckets profiled so far
+// with --xlsx; it does nothing without --xlsx or profiled buckets
+func (p *Profiler) WriteWorkbook() error {
+	p.mu.Lock()
+	buckets := append([]output.WorkbookBucket(nil), p.workbook...)
+	p.mu.Unlock()
+	if len(buckets) == 0 {
+		return nil
+	}
+	if err := p.writer.WriteWorkbook(buckets); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+	p.progress.Printf("Workbook: %s\n", filepath.Join(p.config.OutputDir, p.writer.WorkbookName()))
+	return nil
+}
+
+// Close releases connections held by event publishers and the object
+// stream
+func (p *Profiler) Close() error {
+	return errors.Join(p.events.Close(), p.objectStream.Close())
+}
+
+// Result is everything profiling one bucket produced, before any report
+// is written. Optional parts are nil when their feature is disabled.
+type Result struct {
+	Summary       *types.BucketSummary
+	Objects       []types.ObjectMetadata
+	Configuration *types.BucketConfiguration
+	Metadata      *types.MetadataSummary
+	Partitions    *types.PartitionAnalysis
+	Dimensions    []types.DimensionTable
+	ParquetStats  []types.ParquetStats
+	Glue          *types.GlueRegistration
+	// SecurityFindings are the built-in checks, rated by severity
+	SecurityFindings []types.Finding
+	// Violations are the --policy violation messages and PolicyFindings
+	// the same violations as findings
+	Violations     []string
+	PolicyFindings []types.Finding
+	// CostFindings are the savings the storage analyses found
+	CostFindings []types.Finding
+	DataCards    []types.DataCard
+	// AthenaTables are the tables over Hive-style date partitions
+	AthenaTables []types.AthenaTable
+	// Tags aggregates object tags; nil unless requested
+	Tags     *types.TagSummary
+	Snapshot *types.Snapshot
+}
+
+// Findings returns the security, policy and cost findings of the bucket,
+// most severe first, as data independent of the rendered reports
+func (r *Result) Findings() []types.Finding {
+	findings := make([]types.Finding, 0, len(r.SecurityFindings)+len(r.PolicyFindings)+len(r.CostFindings))
+	findings = append(findings, r.SecurityFindings...)
+	findings = append(findings, r.PolicyFindings...)
+	findings = append(findings, r.CostFindings...)
+	SortFindings(findings)
+	return findings
+}
+
+// ProfileBucket profiles a single S3 bucket, writes its reports and
+// publishes the run
 func (p *Profiler) ProfileBucket(ctx context.Context, bucketName, region string) error {
-	fmt.Printf("\n%s\n", output.FormatHeader(fmt.Sprintf("Profiling bucket: %s", bucketName)))
+	p.progress.Printf("\n%s\n", output.FormatHeader(fmt.Sprintf("Profiling bucket: %s", bucketName)))
 
+	result, err := p.Analyze(ctx, bucketName, region)
+	if err != nil {
+		return err
+	}
+	interrupted := result.Summary.Interrupted
+	if interrupted {
+		// The partial reports are written although the run was cancelled
+		ctx = context.WithoutCancel(ctx)
+	}
+	if err := p.WriteReports(ctx, result); err != nil {
+		return err
+	}
+	if p.bucketAnalyzer.checkpoints.Enabled() && !interrupted {
+		if err := p.bucketAnalyzer.checkpoints.Remove(bucketName); err != nil {
+			p.progress.Warnf("%v", err)
+		}
+	}
+	// Partial runs are not shared with other systems
+	if !interrupted {
+		p.Publish(ctx, result)
+	}
+
+	p.record(result)
+
+	if interrupted {
+		return fmt.Errorf("%s: wrote partial reports of %d listed objects: %w", bucketName, result.Summary.TotalObjects, ErrInterrupted)
+	}
+
+	p.progress.Printf("\n%s Profiling completed successfully!\n\n", "✓")
+
+	return nil
+}
+
+// record adds a profiled bucket to the run. Buckets are profiled
+// concurrently, so they are kept sorted by bucket name and then prefix
+// rather than in the order they finish, and reports of the run come out
+// the same every time.
+func (p *Profiler) record(result *Result) {
+	key := runOrder(result.Summary)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := sort.SearchStrings(p.order, key)
+	p.order = slices.Insert(p.order, i, key)
+	p.profiled = slices.Insert(p.profiled, i, result.Snapshot)
+	if p.config.XLSX {
+		p.workbook = slices.Insert(p.workbook, i, output.WorkbookBucket{
+			Summary:       result.Summary,
+			FileTypeStats: result.Metadata.FileTypeStats,
+			FileTypeSizes: result.Metadata.FileTypeSizes,
+			Partitions:    result.Partitions.Partitions,
+			Newest:        result.Metadata.DateRange.Latest,
+		})
+	}
+}
+
+// runOrder returns the sort key of a profiled bucket: its name, then the
+// prefixes it was limited to
+func runOrder(summary *types.BucketSummary) string {
+	key := summary.Name
+	if summary.Scope != nil {
+		key += "\x00" + strings.Join(summary.Scope.Prefixes, "\x00")
+	}
+	return key
+}
+
+// partialResult is the Result of an interrupted listing. Only the
+// analyses that make no further requests run, on the objects listed so
+// far; the configuration audit and the checks built on it are skipped.
+func (p *Profiler) partialResult(summary *types.BucketSummary, objects []types.ObjectMetadata) *Result {
+	p.progress.Printf("Analyzing the %d objects listed before the interrupt...\n", len(objects))
+	metadataSummary := p.metadataAnalyzer.AnalyzeMetadata(objects)
+	summary.Top = metadataSummary.Top
+
+	var layout string
+	if summary.OpenData != nil {
+		layout = summary.OpenData.Layout
+	}
+	partitionAnalysis := p.analyzePartitions(objects, layout)
+
+	return &Result{
+		Summary:       summary,
+		Objects:       objects,
+		Configuration: &types.BucketConfiguration{},
+		Metadata:      metadataSummary,
+		Partitions:    partitionAnalysis,
+		Snapshot:      BuildSnapshot(summary, objects, partitionAnalysis.Partitions),
+	}
+}
+
+// Analyze runs the analyzers on a bucket and returns their results
+// without writing any files, for services embedding the profiler
+func (p *Profiler) Analyze(ctx context.Context, bucketName, region string) (*Result, error) {
 	// Step 1: Analyze bucket
-	fmt.Println("Step 1/4: Analyzing bucket and listing objects...")
+	p.progress.Printf("Step 1/5: Analyzing bucket and listing objects...\n")
 	summary, objects, err := p.bucketAnalyzer.AnalyzeBucket(ctx, bucketName, region)
 	if err != nil {
-		return fmt.Errorf("failed to analyze bucket: %w", err)
+		return nil, fmt.Errorf("failed to analyze bucket: %w", err)
+	}
+	p.progress.Printf("Found %d objects (Total size: %s)\n", summary.TotalObjects, output.FormatBytes(summary.TotalSize))
+	summary.NoContentAccess = p.config.NoContentAccess
+	for _, stats := range summary.Performance {
+		p.reportThrottling(stats)
+	}
+
+	if summary.Truncated {
+		summary.Estimate = p.estimateListing(ctx, summary)
+		switch {
+		case summary.Interrupted:
+			return p.partialResult(summary, objects), nil
+		case summary.Estimate.BucketObjects > 0:
+			p.progress.Printf("Estimate: listed %.2f%% of ~%d objects; extrapolated total size ~%s\n",
+				summary.Estimate.Fraction*100, summary.Estimate.BucketObjects, output.FormatBytes(summary.Estimate.Size))
+		default:
+			p.progress.Printf("Estimate: bucket object count unknown; totals are lower bounds\n")
+		}
+	}
+
+	// Versioning is optional; missing permissions should not fail the profile
+	versions, err := p.versionAnalyzer.AnalyzeVersions(ctx, bucketName)
+	if err != nil {
+		p.progress.Warnf("skipping version analysis: %v", err)
+	} else if versions != nil {
+		summary.Versioning = versions
+		summary.EstimatedCost += versions.NoncurrentCost
+		p.progress.Printf("Versioning %s: %d non-current version(s) (%s), %d delete marker(s), %s held by deleted objects\n",
+			versions.Status, versions.NoncurrentVersions, output.FormatBytes(versions.NoncurrentSize),
+			versions.DeleteMarkers, output.FormatBytes(versions.DeletedSize))
 	}
-	fmt.Printf("Found %d objects (Total size: %s)\n", summary.TotalObjects, output.FormatBytes(summary.TotalSize))
 
-	// Step 2: Analyze metadata
-	fmt.Println("\nStep 2/4: Analyzing metadata...")
+	if p.accessSimulator.Enabled() {
+		costs, err := p.accessSimulator.Simulate(ctx, bucketName, region, objects)
+		if err != nil {
+			p.progress.Warnf("ignoring server access logs: %v", err)
+		}
+		summary.AccessCosts = costs
+		if costs.LogDays > 0 {
+			p.progress.Printf("Read %d request(s) from %d day(s) of server access logs\n", costs.LogRequests, costs.LogDays)
+		}
+	}
+
+	if p.config.BillableSize {
+		summary.Billable = p.modelBillableSize(ctx, summary)
+		p.progress.Printf("Billable size: %s modeled vs %s of current objects\n",
+			output.FormatBytes(summary.Billable.Total), output.FormatBytes(summary.Billable.Naive))
+	}
+
+	// Step 2: Audit bucket configuration
+	p.progress.Printf("\nStep 2/5: Auditing bucket configuration...\n")
+	configuration := p.configAnalyzer.AnalyzeConfiguration(ctx, summary, objects)
+	for _, e := range configuration.Errors {
+		p.progress.Warnf("%s", e)
+	}
+
+	// Step 3: Analyze metadata
+	p.progress.Printf("\nStep 3/5: Analyzing metadata...\n")
 	metadataSummary := p.metadataAnalyzer.AnalyzeMetadata(objects)
-	fmt.Printf("Identified %d file types\n", len(metadataSummary.FileTypeStats))
+	summary.Top = metadataSummary.Top
+	p.progress.Printf("Identified %d file types\n", len(metadataSummary.FileTypeStats))
+	for _, issue := range metadataSummary.KeyEncoding {
+		p.progress.Warnf("unusual key encoding (%s) in %d key(s)", issue.Issue, issue.Count)
+	}
 
-	// Step 3: Detect partitions
-	fmt.Println("\nStep 3/4: Detecting partitions...")
-	partitions := p.partitionAnalyzer.AnalyzePartitions(objects)
+	if p.enrichAnalyzer.Enabled() {
+		enrichment, err := p.enrichAnalyzer.AnalyzeEnrichment(ctx, bucketName, objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to enrich objects: %w", err)
+		}
+		metadataSummary.Enrichment = enrichment
+		summary.Performance = append(summary.Performance, *enrichment.Performance)
+		p.reportThrottling(*enrichment.Performance)
+		p.progress.Printf("Enriched %d sampled object(s) with HeadObject\n", enrichment.Sampled)
+	}
+
+	var tags *types.TagSummary
+	if p.tagAnalyzer.Enabled() {
+		tags, err = p.tagAnalyzer.AnalyzeTags(ctx, bucketName, objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read object tags: %w", err)
+		}
+		summary.Performance = append(summary.Performance, *tags.Performance)
+		p.reportThrottling(*tags.Performance)
+		p.progress.Printf("Read tags of %d object(s) with GetObjectTagging\n", tags.Sampled)
+		if tags.Failed > 0 {
+			p.progress.Warnf("GetObjectTagging failed for %d object(s)", tags.Failed)
+		}
+	}
+
+	if p.contentAnalyzer.Enabled() {
+		samples, err := p.contentAnalyzer.AnalyzeContent(ctx, bucketName, objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample object content: %w", err)
+		}
+		metadataSummary.ContentSamples = samples
+		p.progress.Printf("Sampled content of %d object(s)\n", len(samples))
+	}
+
+	if p.compressAnalyzer.Enabled() {
+		compressibility, err := p.compressAnalyzer.AnalyzeCompressibility(ctx, bucketName, objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to score compressibility: %w", err)
+		}
+		metadataSummary.Compressibility = compressibility
+		p.progress.Printf("Scored compressibility of %d sampled object(s)\n", compressibility.Sampled)
+		if compressibility.Failed > 0 {
+			p.progress.Warnf("reading %d sampled object(s) failed", compressibility.Failed)
+		}
+	}
+
+	metadataSummary.EncryptedContent = DetectEncryptedContent(objects, metadataSummary.Enrichment)
+	if n := len(metadataSummary.EncryptedContent); n > 0 {
+		p.progress.Printf("Found client-encrypted objects in %d prefix(es)\n", n)
+	}
+
+	// Step 4: Detect partitions
+	p.progress.Printf("\nStep 4/5: Detecting partitions...\n")
+	var layout string
+	if summary.OpenData != nil {
+		layout = summary.OpenData.Layout
+	}
+	partitionAnalysis := p.analyzePartitions(objects, layout)
+	partitions := partitionAnalysis.Partitions
 	if len(partitions) > 0 {
-		fmt.Printf("Detected %d partition(s)\n", len(partitions))
+		p.progress.Printf("Detected %d partition(s)\n", len(partitions))
 	} else {
-		fmt.Println("No partitions detected")
+		p.progress.Printf("No partitions detected\n")
+	}
+	partitionAnalysis.Backfills = p.partitionAnalyzer.FlagBackfills(partitions, time.Now())
+	partitionAnalysis.Suggestions = p.partitionAnalyzer.SuggestRepartitioning(partitions, p.config.TargetPartitionMB<<20)
+	partitionAnalysis.Pruning = p.partitionAnalyzer.EstimatePruning(partitions, partitionAnalysis.Suggestions)
+	if len(partitionAnalysis.Backfills) > 0 {
+		p.progress.Printf("Found %d partition(s) receiving writes outside their date\n", len(partitionAnalysis.Backfills))
+	}
+	partitionAnalysis.Hotspots = p.partitionAnalyzer.FlagHotspots(objects)
+	if len(partitionAnalysis.Hotspots) > 0 {
+		p.progress.Printf("Found %d prefix(es) near the S3 request-rate limit\n", len(partitionAnalysis.Hotspots))
+	}
+	partitionAnalysis.SmallFiles = p.partitionAnalyzer.FlagSmallFiles(objects, p.config.SmallFileKB<<10, p.config.SmallFileCount)
+	if len(partitionAnalysis.SmallFiles) > 0 {
+		p.progress.Printf("Found %d director(ies) with many small files\n", len(partitionAnalysis.SmallFiles))
+	}
+	for _, r := range partitionAnalysis.Rejected {
+		p.progress.Printf("Rejected date pattern %s: %s\n", r.Pattern, r.Reason)
+	}
+	if len(partitionAnalysis.Issues) > 0 {
+		p.progress.Printf("Found %d partition naming issue(s)\n", len(partitionAnalysis.Issues))
+	}
+
+	// The catalog comparison is optional; a missing table or permissions
+	// should not fail the profile
+	var glueRegistration *types.GlueRegistration
+	if p.glueTable != nil && p.config.GlueTable != "" {
+		glueRegistration, err = p.compareGlueTable(ctx, bucketName, region, objects)
+		if err != nil {
+			p.progress.Warnf("skipping Glue partition registration: %v", err)
+		} else {
+			p.progress.Printf("Glue table %s: %d partition(s) registered, %d missing\n",
+				p.config.GlueTable, glueRegistration.Registered, len(glueRegistration.Missing))
+		}
 	}
 
-	// Step 4: Write output files
-	fmt.Println("\nStep 4/4: Writing output files...")
+	var parquetStats []types.ParquetStats
+	if p.parquetAnalyzer.Enabled() {
+		parquetStats, err = p.parquetAnalyzer.AnalyzeParquet(ctx, bucketName, objects, partitions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Parquet statistics: %w", err)
+		}
+		p.progress.Printf("Read Parquet statistics for %d partition(s)\n", len(parquetStats))
+		if p.config.ParquetSchema > 0 {
+			metadataSummary.ParquetSchema = parquetStats
+		}
+	}
+
+	var findings, policyFindings []types.Finding
+	var violations []string
+	findings = BuildFindings(summary, configuration, metadataSummary)
+	RateFindings(findings, p.config.SeverityThresholds)
+	if p.policies.Enabled() {
+		input := buildPolicyInput(summary, configuration, metadataSummary, partitions, findings)
+		violations, err = p.policies.Evaluate(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range violations {
+			p.progress.Printf("Policy violation: %s\n", v)
+		}
+		p.progress.Printf("Evaluated policies: %d violation(s)\n", len(violations))
+		policyFindings = PolicyFindings(bucketName, violations)
+		RateFindings(policyFindings, p.config.SeverityThresholds)
+
+		p.mu.Lock()
+		p.violations += len(violations)
+		p.mu.Unlock()
+	}
+
+	summary.Findings = append(append([]types.Finding(nil), findings...), policyFindings...)
+	SortFindings(summary.Findings)
+
+	costFindings := CostFindings(summary, configuration, metadataSummary, partitionAnalysis)
+	RateFindings(costFindings, p.config.SeverityThresholds)
+
+	var dimensions []types.DimensionTable
+	if p.dimensionAnalyzer.Enabled() {
+		dimensions = p.dimensionAnalyzer.AnalyzeDimensions(objects)
+	}
+
+	var dataCards []types.DataCard
+	if p.config.DataCards || p.lineage.Enabled() {
+		dataCards = p.buildDataCards(summary, objects, partitionAnalysis, metadataSummary, parquetStats)
+	}
 
-	if err := p.writer.WriteBucketSummary(summary); err != nil {
+	athenaTables := buildAthenaTables(objects, partitionAnalysis, metadataSummary, parquetStats)
+	if len(athenaTables) > 0 {
+		p.progress.Printf("Generated Athena DDL for %d table(s) over Hive-style partitions\n", len(athenaTables))
+	}
+
+	return &Result{
+		Summary:          summary,
+		Objects:          objects,
+		Configuration:    configuration,
+		Metadata:         metadataSummary,
+		Partitions:       partitionAnalysis,
+		Dimensions:       dimensions,
+		ParquetStats:     parquetStats,
+		Glue:             glueRegistration,
+		SecurityFindings: findings,
+		Violations:       violations,
+		PolicyFindings:   policyFindings,
+		CostFindings:     costFindings,
+		DataCards:        dataCards,
+		AthenaTables:     athenaTables,
+		Tags:             tags,
+		Snapshot:         BuildSnapshot(summary, objects, partitions),
+	}, nil
+}
+
+// WriteReports writes a bucket's reports to the output directory. Files
+// are staged and only appear once all of them were written.
+func (p *Profiler) WriteReports(ctx context.Context, result *Result) error {
+	summary, objects := result.Summary, result.Objects
+	bucketName, partitions := summary.Name, result.Partitions.Partitions
+
+	// Step 5: Write output files
+	p.progress.Printf("\nStep 5/5: Writing output files...\n")
+
+	// Stage files so a failed or cancelled run never leaves partial reports
+	stage, err := p.writer.Stage(bucketName)
+	if err != nil {
+		return err
+	}
+	defer stage.Discard()
+	stage.SetEstimate(summary.Estimate)
+
+	if err := stage.WriteBucketSummary(summary); err != nil {
 		return fmt.Errorf("failed to write bucket summary: %w", err)
 	}
-	fmt.Printf("  - %s-summary.txt\n", bucketName)
+	p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-summary.txt")))
 
-	if err := p.writer.WriteMetadataSummary(bucketName, metadataSummary); err != nil {
+	if err := stage.WriteMetadataSummary(bucketName, result.Metadata); err != nil {
 		return fmt.Errorf("failed to write metadata summary: %w", err)
 	}
-	fmt.Printf("  - %s-metadata.txt\n", bucketName)
+	p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-metadata.txt")))
 
-	if err := p.writer.WritePartitions(bucketName, partitions); err != nil {
+	if err := stage.WritePartitions(bucketName, result.Partitions); err != nil {
 		return fmt.Errorf("failed to write partitions: %w", err)
 	}
-	fmt.Printf("  - %s-partitions.txt\n", bucketName)
+	p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-partitions.txt")))
+
+	if output.NeedsPartitionRollup(partitions) {
+		if err := stage.WritePartitionList(bucketName, partitions); err != nil {
+			return fmt.Errorf("failed to write partition list: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.PartitionListName(bucketName)))
+	}
+
+	// An interrupted run skipped the configuration audit and the checks
+	// built on it; empty reports would read as a clean bill of health
+	audited := !summary.Interrupted
+
+	if audited {
+		if err := stage.WriteConfiguration(bucketName, result.Configuration); err != nil {
+			return fmt.Errorf("failed to write configuration report: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-configuration.txt")))
+	}
+
+	if p.dimensionAnalyzer.Enabled() {
+		if err := stage.WriteDimensions(bucketName, result.Dimensions); err != nil {
+			return fmt.Errorf("failed to write dimension report: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-dimensions.txt")))
+	}
+
+	if result.Tags != nil {
+		if err := stage.WriteTagReport(bucketName, result.Tags); err != nil {
+			return fmt.Errorf("failed to write tag report: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-tags.txt")))
+	}
+
+	if p.config.ParquetStats > 0 {
+		if err := stage.WriteParquetStats(bucketName, result.ParquetStats); err != nil {
+			return fmt.Errorf("failed to write Parquet statistics: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-parquet.txt")))
+	}
+
+	if err := stage.WriteSnapshot(result.Snapshot); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-snapshot.json")))
+
+	var tree *types.PrefixNode
+	if p.flameGraph != output.FlameGraphNone || p.config.HTML {
+		tree = BuildPrefixTree(bucketName, objects)
+	}
+
+	if p.config.HTML {
+		if err := stage.WriteHTMLReport(summary, tree); err != nil {
+			return fmt.Errorf("failed to write HTML report: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-report.html")))
+	}
+
+	if p.config.Markdown {
+		if err := stage.WriteMarkdownReport(summary, result.Metadata, result.Partitions); err != nil {
+			return fmt.Errorf("failed to write Markdown report: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-report.md")))
+	}
+
+	if p.flameGraph != output.FlameGraphNone {
+		if err := stage.WriteFlameGraph(bucketName, tree, p.flameGraph); err != nil {
+			return fmt.Errorf("failed to write flame graph: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.FlameGraphName(bucketName, p.flameGraph)))
+	}
 
-	fmt.Printf("\n%s Profiling completed successfully!\n\n", "✓")
+	if p.config.EmitInventoryConfig && result.Configuration.InventoryRecommendation != nil {
+		if err := stage.WriteInventoryConfig(bucketName, p.config.InventoryDestination, result.Configuration.InventoryRecommendation); err != nil {
+			return fmt.Errorf("failed to write inventory configuration: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-inventory-config.json")))
+	}
+
+	if p.config.EmitLifecycleConfig && summary.Lifecycle != nil && len(summary.Lifecycle.Rules) > 0 {
+		if err := stage.WriteLifecycleConfig(bucketName, summary.Lifecycle); err != nil {
+			return fmt.Errorf("failed to write lifecycle configuration: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-lifecycle.json")))
+	}
+
+	if p.policies.Enabled() && audited {
+		if err := stage.WritePolicyReport(bucketName, p.config.Policies, result.Violations); err != nil {
+			return fmt.Errorf("failed to write policy report: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-policy.txt")))
+	}
+
+	if p.config.SARIF && audited {
+		if err := stage.WriteSARIF(bucketName, summary.Findings); err != nil {
+			return fmt.Errorf("failed to write SARIF findings: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-findings.sarif")))
+	}
+
+	if p.config.EmitRenameManifest && len(result.Metadata.KeyEncoding) > 0 {
+		if err := stage.WriteRenameManifest(bucketName, p.metadataAnalyzer.SuggestKeyRenames(objects)); err != nil {
+			return fmt.Errorf("failed to write rename manifest: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-rename-manifest.csv")))
+	}
+
+	if p.config.DataCards {
+		for _, card := range result.DataCards {
+			if err := stage.WriteDataCard(bucketName, card); err != nil {
+				return fmt.Errorf("failed to write data card: %w", err)
+			}
+			p.progress.Printf("  - %s\n", stage.FileName(stage.DataCardName(bucketName, card)))
+		}
+	}
+
+	if len(result.AthenaTables) > 0 {
+		if err := stage.WriteAthenaDDL(bucketName, result.AthenaTables); err != nil {
+			return fmt.Errorf("failed to write Athena DDL: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-athena.sql")))
+	}
+
+	if result.Glue != nil && len(result.Glue.Missing) > 0 {
+		if err := stage.WriteGluePartitions(bucketName, summary.Region, result.Glue); err != nil {
+			return fmt.Errorf("failed to write Glue partition requests: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-glue-partitions.json")))
+		p.progress.Printf("  - %s\n", stage.FileName(stage.ReportName(bucketName, "-glue-partitions.sh")))
+	}
+
+	if p.config.ExportObjects {
+		if err := stage.WriteObjectInventory(bucketName, objects); err != nil {
+			return fmt.Errorf("failed to write object inventory: %w", err)
+		}
+		p.progress.Printf("  - %s\n", stage.ExportName(stage.ReportName(bucketName, "-objects.csv")))
+	}
+
+	if err := ctx.Err(); err != nil && !summary.Interrupted {
+		return fmt.Errorf("profiling cancelled: %w", err)
+	}
+
+	if err := stage.Commit(); err != nil {
+		return fmt.Errorf("failed to commit output files: %w", err)
+	}
+	if p.bucketAnalyzer.interim.Enabled() {
+		if err := p.writer.RemoveInterimSummary(bucketName); err != nil {
+			p.progress.Warnf("%v", err)
+		}
+	}
+
+	// Metrics go to the collector's directory, outside the staged output
+	if dir := p.config.MetricsTextfileDir; dir != "" {
+		if err := p.writer.WriteMetricsTextfile(dir, p.bucketMetrics(result)); err != nil {
+			return err
+		}
+		p.progress.Printf("  - %s\n", filepath.Join(dir, p.writer.MetricsTextfileName(bucketName)))
+	}
 
 	return nil
 }
 
+// Publish records a profiled bucket in the results database, raises
+// capacity alerts and announces the run to OpenLineage and event targets.
+// These share results with other systems; their failures are reported as
+// warnings and never fail the profile.
+func (p *Profiler) Publish(ctx context.Context, result *Result) {
+	summary := result.Summary
+
+	// The results database holds shared history; a failed insert leaves
+	// the local snapshot in place
+	if p.saveRun != nil {
+		if id, err := p.saveRun(ctx, summary, result.Snapshot); err != nil {
+			p.progress.Warnf("%v", err)
+		} else {
+			p.progress.Printf("Saved run %d to the results database\n", id)
+		}
+	}
+
+	if p.recordMetrics != nil {
+		p.recordMetrics(p.bucketMetrics(result))
+	}
+
+	if p.alerter.Enabled() && p.runHistory != nil {
+		p.checkCapacity(ctx, summary)
+	}
+
+	// Lineage is published after the reports are in place; a backend
+	// outage should not fail the profile
+	if p.lineage.Enabled() {
+		if err := p.lineage.Emit(ctx, summary.Name, result.DataCards); err != nil {
+			p.progress.Warnf("%v", err)
+		} else {
+			p.progress.Printf("Sent OpenLineage event for %d dataset(s)\n", len(result.DataCards))
+		}
+	}
+
+	if p.events.Enabled() {
+		if err := p.events.Publish(ctx, bucketProfiled(summary, result.Partitions, p.config.OutputDir)); err != nil {
+			p.progress.Warnf("%v", err)
+		} else {
+			p.progress.Printf("Published bucket profiled event\n")
+		}
+	}
+}
+
+// bucketMetrics returns the gauges of a profiled bucket
+func (p *Profiler) bucketMetrics(result *Result) output.BucketMetrics {
+	prefixes := rollUpTopLevel(result.Snapshot.Prefixes)
+	return p.writer.BucketMetrics(result.Summary, result.Snapshot, prefixes, result.Metadata.DateRange.Latest)
+}
+
+// analyzePartitions detects and lints partitions, adding an open dataset's
+// layout when one is named, and reuses a cached result when the inventory
+// is unchanged since an earlier run
+func (p *Profiler) analyzePartitions(objects []types.ObjectMetadata, layout string) *types.PartitionAnalysis {
+	analyzer := p.partitionAnalyzer.withLayout(layout)
+
+	var checksum string
+	if p.cache.Enabled() {
+		checksum = InventoryChecksum(objects)
+		if key := analyzer.cacheKey(); key != "" {
+			checksum += "-" + key
+		}
+		cached, err := p.cache.LoadPartitions(checksum)
+		if err != nil {
+			p.progress.Warnf("ignoring analysis cache: %v", err)
+		} else if cached != nil {
+			p.progress.Printf("Using cached partition analysis (inventory %s)\n", checksum[:12])
+			return cached
+		}
+	}
+
+	analysis := analyzer.AnalyzePartitions(objects)
+	analysis.Issues = analyzer.LintPartitions(objects)
+
+	if p.cache.Enabled() {
+		if err := p.cache.StorePartitions(checksum, analysis); err != nil {
+			p.progress.Warnf("%v", err)
+		}
+	}
+	return analysis
+}
+
+// ErrInterrupted is returned when a cancelled context, such as on SIGINT,
+// stopped profiling; buckets interrupted while listing have partial
+// reports
+var ErrInterrupted = errors.New("profiling interrupted")
+
+// defaultBucketWorkers is the number of buckets profiled concurrently when
+// none is configured
+const defaultBucketWorkers = 5
+
 // ProfileMultipleBuckets profiles multiple S3 buckets concurrently using a worker pool
 func (p *Profiler) ProfileMultipleBuckets(ctx context.Context, bucketNames []string, getRegion func(context.Context, string) (string, error)) error {
 	totalBuckets := len(bucketNames)
 
 	// Thread-safe counters and state
 	var (
-		mu            sync.Mutex
-		successCount  int
-		failedBuckets []string
+		mu             sync.Mutex
+		successCount   int
+		failedBuckets  []string
 		processedCount int
+		skippedCount   int
+		partialBuckets []string
 	)
 
-	fmt.Printf("Profiling %d bucket(s) concurrently...\n", totalBuckets)
+	p.progress.Printf("Profiling %d bucket(s) concurrently...\n", totalBuckets)
 
-	// Configure worker pool size (max 5 concurrent buckets to avoid AWS rate limiting)
-	maxWorkers := 5
+	// Configure worker pool size (--max-workers concurrent buckets)
+	maxWorkers := p.config.MaxWorkers
+	if maxWorkers <= 0 {
+		maxWorkers = defaultBucketWorkers
+	}
 	if totalBuckets < maxWorkers {
 		maxWorkers = totalBuckets
 	}
@@ -109,12 +1085,20 @@ func (p *Profiler) ProfileMultipleBuckets(ctx context.Context, bucketNames []str
 			defer wg.Done()
 
 			for bucketName := range bucketChan {
+				// Buckets not started before an interrupt are skipped
+				if ctx.Err() != nil {
+					mu.Lock()
+					skippedCount++
+					mu.Unlock()
+					continue
+				}
+
 				// Get bucket region
 				region, err := getRegion(ctx, bucketName)
 				if err != nil {
 					mu.Lock()
 					processedCount++
-					fmt.Printf("\n[%d/%d] ERROR: Failed to get region for bucket %s: %v\n",
+					p.progress.Printf("\n[%d/%d] ERROR: Failed to get region for bucket %s: %v\n",
 						processedCount, totalBuckets, bucketName, err)
 					failedBuckets = append(failedBuckets, bucketName)
 					mu.Unlock()
@@ -127,13 +1111,18 @@ func (p *Profiler) ProfileMultipleBuckets(ctx context.Context, bucketNames []str
 				currentCount := processedCount
 				mu.Unlock()
 
-				fmt.Printf("\n[%d/%d] Worker %d: Processing bucket: %s\n",
+				p.progress.Printf("\n[%d/%d] Worker %d: Processing bucket: %s\n",
 					currentCount, totalBuckets, workerID+1, bucketName)
 
 				// Profile the bucket
-				if err := p.ProfileBucket(ctx, bucketName, region); err != nil {
+				if err := p.ProfileBucket(ctx, bucketName, region); errors.Is(err, ErrInterrupted) {
 					mu.Lock()
-					fmt.Printf("ERROR: Worker %d failed to profile bucket %s: %v\n",
+					partialBuckets = append(partialBuckets, bucketName)
+					mu.Unlock()
+					continue
+				} else if err != nil {
+					mu.Lock()
+					p.progress.Printf("ERROR: Worker %d failed to profile bucket %s: %v\n",
 						workerID+1, bucketName, err)
 					failedBuckets = append(failedBuckets, bucketName)
 					mu.Unlock()
@@ -158,17 +1147,28 @@ func (p *Profiler) ProfileMultipleBuckets(ctx context.Context, bucketNames []str
 	wg.Wait()
 
 	// Print summary
-	fmt.Printf("\n%s\n", output.FormatHeader("Summary"))
-	fmt.Printf("Total buckets: %d\n", totalBuckets)
-	fmt.Printf("Successfully profiled: %d\n", successCount)
-	fmt.Printf("Failed: %d\n", len(failedBuckets))
+	p.progress.Printf("\n%s\n", output.FormatHeader("Summary"))
+	p.progress.Printf("Total buckets: %d\n", totalBuckets)
+	p.progress.Printf("Successfully profiled: %d\n", successCount)
+	p.progress.Printf("Failed: %d\n", len(failedBuckets))
+	if len(partialBuckets) > 0 {
+		p.progress.Printf("Partial (interrupted): %d\n", len(partialBuckets))
+	}
+	if skippedCount > 0 {
+		p.progress.Printf("Skipped after the interrupt: %d\n", skippedCount)
+	}
 
 	if len(failedBuckets) > 0 {
-		fmt.Println("\nFailed buckets:")
+		// Workers finish in any order
+		sort.Strings(failedBuckets)
+		p.progress.Printf("\nFailed buckets:\n")
 		for _, bucket := range failedBuckets {
-			fmt.Printf("  - %s\n", bucket)
+			p.progress.Printf("  - %s\n", bucket)
 		}
 	}
 
+	if ctx.Err() != nil {
+		return fmt.Errorf("%d bucket(s) with partial reports, %d skipped: %w", len(partialBuckets), skippedCount, ErrInterrupted)
+	}
 	return nil
 }
@@ -0,0 +1,74 @@
+package profiler
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// profiledResult is the Result record needs for a bucket scoped to prefixes
+func profiledResult(bucket string, prefixes ...string) *Result {
+	summary := &types.BucketSummary{Name: bucket}
+	if len(prefixes) > 0 {
+		summary.Scope = &types.KeyScope{Prefixes: prefixes}
+	}
+	return &Result{
+		Summary:    summary,
+		Metadata:   &types.MetadataSummary{},
+		Partitions: &types.PartitionAnalysis{},
+		Snapshot:   &types.Snapshot{Bucket: bucket},
+	}
+}
+
+// TestRecordOrder profiles two buckets concurrently, finishing in either
+// order, and checks the run lists them by name
+func TestRecordOrder(t *testing.T) {
+	for _, finishFirst := range []string{"alpha", "beta"} {
+		p := &Profiler{config: types.ProfileConfig{XLSX: true}}
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		for _, bucket := range []string{"beta", "alpha"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if bucket != finishFirst {
+					<-done
+				}
+				p.record(profiledResult(bucket))
+				if bucket == finishFirst {
+					close(done)
+				}
+			}()
+		}
+		wg.Wait()
+
+		profiled := p.Profiled()
+		if len(profiled) != 2 || profiled[0].Bucket != "alpha" || profiled[1].Bucket != "beta" {
+			t.Errorf("%s first: profiled %v, want alpha then beta", finishFirst, profiled)
+		}
+		if len(p.workbook) != 2 || p.workbook[0].Summary.Name != "alpha" || p.workbook[1].Summary.Name != "beta" {
+			t.Errorf("%s first: workbook out of order", finishFirst)
+		}
+	}
+}
+
+func TestRunOrder(t *testing.T) {
+	p := &Profiler{}
+	for _, r := range []*Result{
+		profiledResult("logs", "b/"),
+		profiledResult("data"),
+		profiledResult("logs", "a/"),
+		profiledResult("log"),
+	} {
+		p.record(r)
+	}
+	want := []string{"data", "log", "logs\x00a/", "logs\x00b/"}
+	if !slices.Equal(p.order, want) {
+		t.Errorf("order = %q, want %q", p.order, want)
+	}
+	if len(p.workbook) != 0 {
+		t.Errorf("workbook has %d buckets without --xlsx", len(p.workbook))
+	}
+}
@@ -0,0 +1,48 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ProgressReporter receives the profiler's progress messages and non-fatal
+// warnings. Embedding services can route them to their own logger; the
+// default writes them to stdout like the CLI always has.
+type ProgressReporter interface {
+	// Printf reports progress; format follows fmt and carries its own newlines
+	Printf(format string, args ...any)
+	// Warnf reports a non-fatal problem the profile continues after
+	Warnf(format string, args ...any)
+}
+
+// writerReporter writes progress to an io.Writer, prefixing warnings the
+// way the CLI prints them
+type writerReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterReporter creates a reporter that writes progress to w
+func NewWriterReporter(w io.Writer) ProgressReporter {
+	return &writerReporter{w: w}
+}
+
+func (r *writerReporter) Printf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.w, format, args...)
+}
+
+func (r *writerReporter) Warnf(format string, args ...any) {
+	r.Printf("Warning: "+format+"\n", args...)
+}
+
+// DiscardProgress is a reporter that drops all progress and warnings
+var DiscardProgress ProgressReporter = NewWriterReporter(io.Discard)
+
+// defaultProgress writes to stdout
+func defaultProgress() ProgressReporter {
+	return NewWriterReporter(os.Stdout)
+}
@@ -0,0 +1,211 @@
+package profiler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Athena pricing of a query, approximate for US East
+const (
+	athenaPerTB = 5.0
+	// athenaMinScan is the data every query is billed for at least
+	athenaMinScan = 10 << 20
+)
+
+// granularityNone is the unpartitioned scheme every query scans in full
+const granularityNone = "none"
+
+// pruningGranularities are the schemes pruning is estimated for, finest
+// first
+var pruningGranularities = []string{granularityHour, granularityDay, granularityMonth, granularityYear}
+
+// queryWindow is a query predicate on the most recent data, e.g. the last
+// 7 days
+type queryWindow struct {
+	name  string
+	count int
+	unit  string
+}
+
+var queryWindowSpec = regexp.MustCompile(`^(\d+)(h|d|w|mo|y)$`)
+
+// start returns the beginning of the window ending at end
+func (q queryWindow) start(end time.Time) time.Time {
+	switch q.unit {
+	case "h":
+		return end.Add(-time.Duration(q.count) * time.Hour)
+	case "w":
+		return end.AddDate(0, 0, -7*q.count)
+	case "mo":
+		return end.AddDate(0, -q.count, 0)
+	case "y":
+		return end.AddDate(-q.count, 0, 0)
+	default:
+		return end.AddDate(0, 0, -q.count)
+	}
+}
+
+// SetQueryWindows sets the query windows partition pruning is estimated
+// for, each a count and a unit: h, d, w, mo or y (e.g. 7d for the last 7
+// days)
+func (pa *PartitionAnalyzer) SetQueryWindows(specs []string) error {
+	windows := make([]queryWindow, 0, len(specs))
+	for _, spec := range specs {
+		m := queryWindowSpec.FindStringSubmatch(spec)
+		if m == nil {
+			return fmt.Errorf("invalid query window %q (expected a count and h, d, w, mo or y, e.g. 7d)", spec)
+		}
+		count, err := strconv.Atoi(m[1])
+		if err != nil || count == 0 {
+			return fmt.Errorf("invalid query window %q (expected a positive count)", spec)
+		}
+		windows = append(windows, queryWindow{name: spec, count: count, unit: m[2]})
+	}
+	pa.windows = windows
+	return nil
+}
+
+// truncatePeriod returns the start of the granularity's period holding t
+func truncatePeriod(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case granularityHour:
+		return t.Truncate(time.Hour)
+	case granularityDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case granularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// athenaQueryCost is the Athena cost of a query scanning size bytes
+func athenaQueryCost(size int64) float64 {
+	return float64(max(size, athenaMinScan)) / (1 << 40) * athenaPerTB
+}
+
+// EstimatePruning estimates, for each date pattern and query window, how
+// much data a query filtering on the partition date scans under each
+// granularity, and without partitions. Windows end where the newest
+// partition's period ends, so they model queries over the most recent
+// data. Granularities finer than the current one assume objects are
+// spread evenly within each partition. The granularity suggestions
+// recommend, if any, are marked.
+func (pa *PartitionAnalyzer) EstimatePruning(partitions []types.Partition, suggestions []types.RepartitionSuggestion) []types.PruningEstimate {
+	if len(pa.windows) == 0 {
+		return nil
+	}
+
+	groups := make(map[[2]string][]types.Partition)
+	var keys [][2]string
+	for _, p := range partitions {
+		if p.Date.IsZero() {
+			continue
+		}
+		k := [2]string{p.Scope, p.Pattern}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0]+keys[i][1] < keys[j][0]+keys[j][1]
+	})
+
+	recommended := make(map[[2]string]string)
+	for _, s := range suggestions {
+		recommended[[2]string{s.Scope, s.Pattern}] = s.Recommended
+	}
+
+	var estimates []types.PruningEstimate
+	for _, k := range keys {
+		for _, window := range pa.windows {
+			estimate := estimateWindow(groups[k], window)
+			estimate.Scope, estimate.Pattern = k[0], k[1]
+			estimate.Recommended = recommended[k]
+			estimates = append(estimates, estimate)
+		}
+	}
+	return estimates
+}
+
+// estimateWindow prices one query window over one pattern's partitions
+func estimateWindow(partitions []types.Partition, window queryWindow) types.PruningEstimate {
+	current := patternGranularity(partitions[0].Pattern)
+	estimate := types.PruningEstimate{Window: window.name, Current: current}
+
+	var end time.Time
+	for _, p := range partitions {
+		estimate.TotalSize += p.TotalSize
+		if _, pe := partitionPeriod(p); pe.After(end) {
+			end = pe
+		}
+	}
+	start := window.start(end)
+
+	rank := make(map[string]int, len(pruningGranularities))
+	for i, g := range pruningGranularities {
+		rank[g] = i
+	}
+	for _, g := range pruningGranularities {
+		// Hourly partitions are only projected for daily layouts
+		if g == granularityHour && current != granularityDay {
+			continue
+		}
+		scan := types.PruningScan{Granularity: g, Estimated: rank[g] < rank[current]}
+		gStart := truncatePeriod(start, g)
+		var size float64
+		periods := make(map[time.Time]bool)
+		for _, p := range partitions {
+			ps, pe := partitionPeriod(p)
+			if !scan.Estimated {
+				// A coarser partition is read whole when it overlaps the window
+				if period := truncatePeriod(ps, g); !period.Before(gStart) {
+					size += float64(p.TotalSize)
+					periods[period] = true
+				}
+				continue
+			}
+			overlapStart := ps
+			if gStart.After(overlapStart) {
+				overlapStart = gStart
+			}
+			if !overlapStart.Before(pe) {
+				continue
+			}
+			size += float64(p.TotalSize) * pe.Sub(overlapStart).Seconds() / pe.Sub(ps).Seconds()
+			for t := overlapStart; t.Before(pe); t = nextPeriod(t, g) {
+				periods[t] = true
+			}
+		}
+		scan.Partitions = int64(len(periods))
+		scan.Size = int64(size)
+		scan.Cost = athenaQueryCost(scan.Size)
+		estimate.Scans = append(estimate.Scans, scan)
+	}
+	estimate.Scans = append(estimate.Scans, types.PruningScan{
+		Granularity: granularityNone,
+		Size:        estimate.TotalSize,
+		Cost:        athenaQueryCost(estimate.TotalSize),
+	})
+	return estimate
+}
+
+// nextPeriod returns the start of the period after the one starting at t
+func nextPeriod(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case granularityHour:
+		return t.Add(time.Hour)
+	case granularityDay:
+		return t.AddDate(0, 0, 1)
+	case granularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(1, 0, 0)
+	}
+}
@@ -0,0 +1,171 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Repartitioning thresholds
+const (
+	// defaultTargetPartitionSize is the partition size suggestions aim for
+	defaultTargetPartitionSize = 1 << 30
+	// targetFileSize is the file size compaction is projected for
+	targetFileSize = 128 << 20
+	// partitionSizeTolerance is how far (as a factor) the average
+	// partition may be from the target before another granularity is
+	// suggested
+	partitionSizeTolerance = 4
+	// partitionSkewThreshold flags layouts whose largest partition is this
+	// many times the median
+	partitionSkewThreshold = 10
+)
+
+// Partition granularities, finest first
+const (
+	granularityHour  = "hour"
+	granularityDay   = "day"
+	granularityMonth = "month"
+	granularityYear  = "year"
+)
+
+// SuggestRepartitioning compares each date pattern's partitions with the
+// target size and proposes a coarser or finer granularity, with projected
+// partition counts under every scheme. targetSize <= 0 uses 1 GiB.
+func (pa *PartitionAnalyzer) SuggestRepartitioning(partitions []types.Partition, targetSize int64) []types.RepartitionSuggestion {
+	if targetSize <= 0 {
+		targetSize = defaultTargetPartitionSize
+	}
+
+	groups := make(map[[2]string][]types.Partition)
+	var keys [][2]string
+	for _, p := range partitions {
+		if p.Date.IsZero() {
+			continue
+		}
+		k := [2]string{p.Scope, p.Pattern}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0]+keys[i][1] < keys[j][0]+keys[j][1]
+	})
+
+	var suggestions []types.RepartitionSuggestion
+	for _, k := range keys {
+		if s := suggestScheme(k[0], k[1], groups[k], targetSize); s != nil {
+			suggestions = append(suggestions, *s)
+		}
+	}
+	return suggestions
+}
+
+// suggestScheme evaluates the granularities for one pattern's partitions
+// and returns a suggestion when the layout is off target or skewed
+func suggestScheme(scope, pattern string, partitions []types.Partition, targetSize int64) *types.RepartitionSuggestion {
+	current := patternGranularity(pattern)
+
+	var objects, size int64
+	sizes := make([]int64, len(partitions))
+	for i, p := range partitions {
+		objects += p.ObjectCount
+		size += p.TotalSize
+		sizes[i] = p.TotalSize
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	median := sizes[len(sizes)/2]
+	skew := 0.0
+	if median > 0 {
+		skew = float64(sizes[len(sizes)-1]) / float64(median)
+	}
+
+	// Coarser schemes are counted from the partition dates; finer ones
+	// assume objects are spread evenly within each partition
+	counts := map[string]int64{current: int64(len(partitions))}
+	counts[granularityYear] = distinctPeriods(partitions, "2006")
+	if current == granularityDay {
+		counts[granularityMonth] = distinctPeriods(partitions, "2006-01")
+		counts[granularityHour] = int64(len(partitions)) * 24
+	} else {
+		var days int64
+		for _, p := range partitions {
+			days += int64(p.Date.AddDate(0, 1, -1).Day())
+		}
+		counts[granularityDay] = days
+	}
+
+	suggestion := &types.RepartitionSuggestion{
+		Scope:       scope,
+		Pattern:     pattern,
+		Current:     current,
+		Recommended: current,
+		Skew:        skew,
+	}
+	best := math.Inf(1)
+	for _, g := range []string{granularityHour, granularityDay, granularityMonth, granularityYear} {
+		n, ok := counts[g]
+		if !ok || n == 0 {
+			continue
+		}
+		scheme := types.RepartitionScheme{
+			Granularity:   g,
+			Partitions:    n,
+			AverageSize:   size / n,
+			AverageFiles:  float64(objects) / float64(n),
+			FilesAtTarget: int64(math.Ceil(float64(size/n) / targetFileSize)),
+			Estimated:     g == granularityHour || (g == granularityDay && current == granularityMonth),
+		}
+		suggestion.Schemes = append(suggestion.Schemes, scheme)
+
+		// Distance from the target on a log scale, so 2x too big and 2x
+		// too small are equally far
+		if distance := math.Abs(math.Log(float64(max(scheme.AverageSize, 1)) / float64(targetSize))); distance < best {
+			best = distance
+			suggestion.Recommended = g
+		}
+	}
+
+	currentSize := size / int64(len(partitions))
+	offTarget := float64(currentSize) > float64(targetSize)*partitionSizeTolerance ||
+		float64(currentSize)*partitionSizeTolerance < float64(targetSize)
+	skewed := skew >= partitionSkewThreshold
+
+	var reasons []string
+	if offTarget && suggestion.Recommended != current {
+		reasons = append(reasons, fmt.Sprintf("average %s partition holds %s, target is %s",
+			current, output.FormatBytes(currentSize), output.FormatBytes(targetSize)))
+	}
+	if skewed {
+		reasons = append(reasons, fmt.Sprintf("largest partition is %.0fx the median", skew))
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	suggestion.Reason = strings.Join(reasons, "; ")
+	return suggestion
+}
+
+// patternGranularity returns the granularity of a date pattern's
+// partitions: daily when the pattern has a day, monthly otherwise
+func patternGranularity(pattern string) string {
+	if strings.Contains(pattern, "DD") {
+		return granularityDay
+	}
+	return granularityMonth
+}
+
+// distinctPeriods counts the distinct partition dates at a layout's
+// granularity
+func distinctPeriods(partitions []types.Partition, layout string) int64 {
+	seen := make(map[string]bool)
+	for _, p := range partitions {
+		seen[p.Date.Format(layout)] = true
+	}
+	return int64(len(seen))
+}
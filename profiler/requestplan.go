@@ -0,0 +1,152 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Approximate US East request prices
+const (
+	listRequestPerThousand = 0.005
+	getRequestPerThousand  = 0.0004
+)
+
+// Request plan assumptions
+const (
+	// bucketConfigRequests is the number of bucket-level requests (region,
+	// ownership, configuration and feature lookups) a profile issues
+	bucketConfigRequests = 20
+	// planPrefixPages bounds the delimiter listing that counts top-level
+	// prefixes; buckets with more are counted as having this many pages
+	planPrefixPages = 10
+)
+
+// PlanRequests estimates the LIST, HEAD and GET requests profiling each
+// bucket issues and their cost, and prints the plan. Object counts come
+// from CloudWatch storage metrics, capped by --limit, and cover the whole
+// bucket whatever the key scope. Sampling options are assumed to read one
+// sample set per top-level prefix, which is counted with a delimiter
+// listing. Buckets that cannot be planned are reported and left out of the
+// totals.
+func (p *Profiler) PlanRequests(ctx context.Context, bucketNames []string, getRegion func(context.Context, string) (string, error)) []types.RequestPlan {
+	plans := make([]types.RequestPlan, len(bucketNames))
+	workers := p.config.MaxWorkers
+	if workers <= 0 {
+		workers = defaultBucketWorkers
+	}
+	runParallel(ctx, workers, len(bucketNames), func(ctx context.Context, i int) error {
+		plans[i] = p.planBucket(ctx, bucketNames[i], getRegion)
+		return nil
+	})
+	p.printRequestPlan(plans)
+	return plans
+}
+
+// planBucket estimates the requests of one bucket
+func (p *Profiler) planBucket(ctx context.Context, bucketName string, getRegion func(context.Context, string) (string, error)) types.RequestPlan {
+	plan := types.RequestPlan{Bucket: bucketName}
+	region, err := getRegion(ctx, bucketName)
+	if err != nil {
+		plan.Error = fmt.Sprintf("failed to get bucket region: %v", err)
+		return plan
+	}
+	plan.Region = region
+
+	if p.objectCounts != nil {
+		if count, _, err := p.objectCounts(ctx, bucketName, region); err == nil {
+			plan.Objects = count
+		}
+	}
+	if p.config.Limit > 0 && (plan.Objects == 0 || plan.Objects > p.config.Limit) {
+		plan.Objects = p.config.Limit
+	}
+
+	prefixes, err := p.countTopLevelPrefixes(ctx, bucketName)
+	if err != nil {
+		plan.Error = err.Error()
+		return plan
+	}
+	plan.Prefixes = prefixes
+
+	pages := (plan.Objects + 999) / 1000
+	plan.List = pages
+	versioning, err := p.s3Client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: aws.String(bucketName)})
+	if err == nil && versioning.Status != "" {
+		// Versioned buckets are listed again with ListObjectVersions
+		plan.List += pages
+	}
+	if p.config.BillableSize {
+		// ListMultipartUploads
+		plan.List++
+	}
+
+	plan.Head = int64(p.config.EnrichSamples) * prefixes
+	samples := p.config.Compressibility + p.config.SampleContent + p.config.ParquetStats + p.config.ParquetSchema
+	plan.Get = bucketConfigRequests + int64(samples)*prefixes
+	switch {
+	case p.config.TagSamples < 0:
+		plan.Get += plan.Objects
+	case p.config.TagSamples > 0:
+		plan.Get += int64(p.config.TagSamples) * prefixes
+	}
+
+	plan.Cost = float64(plan.List)/1000*listRequestPerThousand + float64(plan.Head+plan.Get)/1000*getRequestPerThousand
+	return plan
+}
+
+// countTopLevelPrefixes counts the top-level prefixes of a bucket, plus
+// one for objects at the root, within planPrefixPages pages
+func (p *Profiler) countTopLevelPrefixes(ctx context.Context, bucketName string) (int64, error) {
+	count := int64(1)
+	paginator := s3.NewListObjectsV2Paginator(p.s3Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucketName),
+		Delimiter: aws.String("/"),
+	})
+	for page := 0; page < planPrefixPages && paginator.HasMorePages(); page++ {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list top-level prefixes: %w", err)
+		}
+		count += int64(len(result.CommonPrefixes))
+	}
+	return count, nil
+}
+
+// printRequestPlan prints the estimated requests per bucket and in total
+func (p *Profiler) printRequestPlan(plans []types.RequestPlan) {
+	p.progress.Printf("\nRequest plan (approximate US East request prices):\n")
+	p.progress.Printf("%-40s %14s %12s %12s %12s %10s\n", "Bucket", "Objects", "LIST", "HEAD", "GET", "Cost")
+	var total types.RequestPlan
+	unknown := false
+	for _, plan := range plans {
+		if plan.Error != "" {
+			p.progress.Printf("%-40s %s\n", plan.Bucket, plan.Error)
+			continue
+		}
+		objects := output.FormatNumber(plan.Objects)
+		if plan.Objects == 0 {
+			objects, unknown = "?", true
+		}
+		p.progress.Printf("%-40s %14s %12s %12s %12s %10s\n", plan.Bucket, objects,
+			output.FormatNumber(plan.List), output.FormatNumber(plan.Head), output.FormatNumber(plan.Get), output.FormatCost(plan.Cost))
+		total.Objects += plan.Objects
+		total.List += plan.List
+		total.Head += plan.Head
+		total.Get += plan.Get
+		total.Cost += plan.Cost
+	}
+	if len(plans) > 1 {
+		p.progress.Printf("%-40s %14s %12s %12s %12s %10s\n", "Total", output.FormatNumber(total.Objects),
+			output.FormatNumber(total.List), output.FormatNumber(total.Head), output.FormatNumber(total.Get), output.FormatCost(total.Cost))
+	}
+	if unknown {
+		p.progress.Printf("? object count unknown (no CloudWatch storage metrics); listing requests are not counted\n")
+	}
+	p.progress.Printf("Object counts come from CloudWatch and include every version. Sampling assumes one sample set per\n" +
+		"top-level prefix, so per-format and per-partition samples can issue more. Access logs are not counted.\n")
+}
@@ -0,0 +1,80 @@
+package profiler
+
+import (
+	"sort"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// restoreTier describes the approximate US East pricing of a retrieval tier
+type restoreTier struct {
+	name           string
+	perGB          float64
+	perThousandReq float64
+	time           string
+}
+
+// restoreTiers lists retrieval tiers for each storage class that requires a
+// restore before objects can be read
+var restoreTiers = map[string][]restoreTier{
+	"GLACIER": {
+		{name: "Expedited", perGB: 0.03, perThousandReq: 10.00, time: "1-5 minutes"},
+		{name: "Standard", perGB: 0.01, perThousandReq: 0.05, time: "3-5 hours"},
+		{name: "Bulk", perGB: 0.0, perThousandReq: 0.025, time: "5-12 hours"},
+	},
+	"DEEP_ARCHIVE": {
+		{name: "Standard", perGB: 0.02, perThousandReq: 0.10, time: "within 12 hours"},
+		{name: "Bulk", perGB: 0.0025, perThousandReq: 0.025, time: "within 48 hours"},
+	},
+}
+
+// EstimateRestoreCosts estimates what it would cost to restore each archived
+// top-level prefix in full, for every retrieval tier of its storage class
+func (ba *BucketAnalyzer) EstimateRestoreCosts(objects []types.ObjectMetadata) []types.RestoreEstimate {
+	type prefixClass struct {
+		prefix string
+		class  string
+	}
+	groups := make(map[prefixClass]*types.RestoreEstimate)
+
+	for _, obj := range objects {
+		if _, ok := restoreTiers[obj.StorageClass]; !ok {
+			continue
+		}
+
+		k := prefixClass{prefix: topLevelPrefix(obj.Key), class: obj.StorageClass}
+		estimate, exists := groups[k]
+		if !exists {
+			estimate = &types.RestoreEstimate{Prefix: k.prefix, StorageClass: k.class}
+			groups[k] = estimate
+		}
+		estimate.ObjectCount++
+		estimate.Size += obj.Size
+	}
+
+	var estimates []types.RestoreEstimate
+	for _, estimate := range groups {
+		sizeGB := float64(estimate.Size) / (1024 * 1024 * 1024)
+		for _, tier := range restoreTiers[estimate.StorageClass] {
+			estimate.Options = append(estimate.Options, types.RestoreOption{
+				Tier: tier.name,
+				Cost: sizeGB*tier.perGB + float64(estimate.ObjectCount)/1000*tier.perThousandReq,
+				Time: tier.time,
+			})
+		}
+		estimates = append(estimates, *estimate)
+	}
+
+	sort.Slice(estimates, func(i, j int) bool {
+		a, b := estimates[i], estimates[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		if a.Prefix != b.Prefix {
+			return a.Prefix < b.Prefix
+		}
+		return a.StorageClass < b.StorageClass
+	})
+
+	return estimates
+}
@@ -0,0 +1,134 @@
+package profiler
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// keyScope limits a listing to the keys under some prefixes that match an
+// include pattern, if any are given, minus the keys matching exclude
+// patterns. The zero value covers every key.
+type keyScope struct {
+	prefixes []string
+	include  []string
+	exclude  []string
+}
+
+// newKeyScope validates the include and exclude patterns and drops
+// prefixes inside another one, so no key is listed twice
+func newKeyScope(prefixes, include, exclude []string) (keyScope, error) {
+	var scope keyScope
+	for _, pattern := range include {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return keyScope{}, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		scope.include = append(scope.include, pattern)
+	}
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return keyScope{}, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		scope.exclude = append(scope.exclude, pattern)
+	}
+
+	sorted := append([]string(nil), prefixes...)
+	sort.Strings(sorted)
+	for _, prefix := range sorted {
+		if prefix == "" {
+			// The whole bucket
+			scope.prefixes = nil
+			break
+		}
+		if n := len(scope.prefixes); n > 0 && strings.HasPrefix(prefix, scope.prefixes[n-1]) {
+			continue
+		}
+		scope.prefixes = append(scope.prefixes, prefix)
+	}
+	return scope, nil
+}
+
+// limited reports whether the scope leaves out any keys
+func (s keyScope) limited() bool {
+	return len(s.prefixes) > 0 || s.filtered()
+}
+
+// filtered reports whether the scope has include or exclude patterns
+func (s keyScope) filtered() bool {
+	return len(s.include) > 0 || len(s.exclude) > 0
+}
+
+// listPrefixes returns the prefixes to list, "" for the whole bucket
+func (s keyScope) listPrefixes() []string {
+	if len(s.prefixes) == 0 {
+		return []string{""}
+	}
+	return s.prefixes
+}
+
+// excluded reports whether a key matches an exclude pattern or, when there
+// are include patterns, matches none of them
+func (s keyScope) excluded(key string) bool {
+	if len(s.include) > 0 && !matchesGlob(s.include, key) {
+		return true
+	}
+	return matchesGlob(s.exclude, key)
+}
+
+// matchesGlob reports whether a key matches any of the patterns. Patterns
+// with a "/" match the whole key; others match its last segment, so
+// "_SUCCESS" and "*.crc" apply at any depth.
+func matchesGlob(patterns []string, key string) bool {
+	name := path.Base(key)
+	for _, pattern := range patterns {
+		subject := name
+		if strings.Contains(pattern, "/") {
+			subject = key
+		}
+		if ok, _ := path.Match(pattern, subject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether a key is in scope
+func (s keyScope) contains(key string) bool {
+	if len(s.prefixes) > 0 {
+		i := sort.SearchStrings(s.prefixes, key)
+		// The prefix of key sorts at or before it
+		if i < len(s.prefixes) && s.prefixes[i] == key {
+			return !s.excluded(key)
+		}
+		if i == 0 || !strings.HasPrefix(key, s.prefixes[i-1]) {
+			return false
+		}
+	}
+	return !s.excluded(key)
+}
+
+// filter drops the excluded objects of a listed page in place
+func (s keyScope) filter(page []types.ObjectMetadata) []types.ObjectMetadata {
+	if !s.filtered() {
+		return page
+	}
+	kept := page[:0]
+	for _, obj := range page {
+		if !s.excluded(obj.Key) {
+			kept = append(kept, obj)
+		}
+	}
+	return kept
+}
+
+// summary returns the scope recorded in the bucket summary, nil when
+// every key is covered
+func (s keyScope) summary() *types.KeyScope {
+	if !s.limited() {
+		return nil
+	}
+	return &types.KeyScope{Prefixes: s.prefixes, Include: s.include, Exclude: s.exclude}
+}
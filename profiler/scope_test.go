@@ -0,0 +1,102 @@
+package profiler
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+func TestNewKeyScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		include  []string
+		exclude  []string
+		want     []string
+		wantErr  string
+	}{
+		{name: "whole bucket", want: []string{""}},
+		{name: "nested prefixes merged", prefixes: []string{"logs/2024/", "data/", "logs/"}, want: []string{"data/", "logs/"}},
+		{name: "empty prefix covers everything", prefixes: []string{"logs/", ""}, want: []string{""}},
+		{name: "bad include", include: []string{"[a"}, wantErr: "invalid include pattern"},
+		{name: "bad exclude", exclude: []string{"a["}, wantErr: "invalid exclude pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := newKeyScope(tt.prefixes, tt.include, tt.exclude)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := scope.listPrefixes(); !slices.Equal(got, tt.want) {
+				t.Errorf("listPrefixes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyScopeContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		include  []string
+		exclude  []string
+		key      string
+		want     bool
+	}{
+		{name: "zero scope", key: "any/key", want: true},
+		{name: "under prefix", prefixes: []string{"logs/"}, key: "logs/a.gz", want: true},
+		{name: "outside prefixes", prefixes: []string{"data/", "logs/"}, key: "images/a.png", want: false},
+		{name: "sorts between prefixes", prefixes: []string{"a/", "c/"}, key: "b/x", want: false},
+		{name: "key equal to prefix", prefixes: []string{"logs/"}, key: "logs/", want: true},
+		{name: "exclude by name at any depth", exclude: []string{"_SUCCESS"}, key: "data/dt=1/_SUCCESS", want: false},
+		{name: "exclude by extension", exclude: []string{"*.crc"}, key: "data/.part-0.crc", want: false},
+		{name: "exclude path pattern", exclude: []string{"tmp/*"}, key: "tmp/a", want: false},
+		{name: "path pattern only matches whole key", exclude: []string{"tmp/*"}, key: "data/tmp/a", want: true},
+		{name: "star stops at separators", exclude: []string{"data/*"}, key: "data/dt=1/a", want: true},
+		{name: "include matches", include: []string{"*.parquet"}, key: "data/a.parquet", want: true},
+		{name: "include misses", include: []string{"*.parquet"}, key: "data/a.json", want: false},
+		{name: "exclude beats include", include: []string{"*.parquet"}, exclude: []string{"tmp-*"}, key: "data/tmp-a.parquet", want: false},
+		{name: "prefix and include", prefixes: []string{"data/"}, include: []string{"*.parquet"}, key: "logs/a.parquet", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := newKeyScope(tt.prefixes, tt.include, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := scope.contains(tt.key); got != tt.want {
+				t.Errorf("contains(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyScopeFilter(t *testing.T) {
+	scope, err := newKeyScope(nil, nil, []string{"_SUCCESS", "*.crc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := []types.ObjectMetadata{{Key: "a/part-0.parquet"}, {Key: "a/_SUCCESS"}, {Key: "a/.part-0.crc"}, {Key: "b/part-1.parquet"}}
+	var got []string
+	for _, obj := range scope.filter(page) {
+		got = append(got, obj.Key)
+	}
+	if want := []string{"a/part-0.parquet", "b/part-1.parquet"}; !slices.Equal(got, want) {
+		t.Errorf("filter kept %q, want %q", got, want)
+	}
+
+	if summary := (keyScope{}).summary(); summary != nil {
+		t.Errorf("zero scope summary = %+v, want nil", summary)
+	}
+	if summary := scope.summary(); summary == nil || !slices.Equal(summary.Exclude, []string{"_SUCCESS", "*.crc"}) {
+		t.Errorf("summary = %+v, want the exclude patterns", summary)
+	}
+}
@@ -0,0 +1,188 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// analyzePublicAccess records the bucket's Block Public Access settings
+// and whether its policy makes it public. Account-level Block Public Access
+// is not checked.
+func (ca *ConfigAnalyzer) analyzePublicAccess(ctx context.Context, bucketName string, config *types.BucketConfiguration) error {
+	block := &s3types.PublicAccessBlockConfiguration{}
+	result, err := ca.s3Client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if !isErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+			return err
+		}
+	} else if result.PublicAccessBlockConfiguration != nil {
+		block = result.PublicAccessBlockConfiguration
+	}
+
+	status, err := ca.s3Client.GetBucketPolicyStatus(ctx, &s3.GetBucketPolicyStatusInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if !isErrorCode(err, "NoSuchBucketPolicy") {
+			return err
+		}
+	} else if status.PolicyStatus != nil {
+		config.PolicyPublic = aws.ToBool(status.PolicyStatus.IsPublic)
+	}
+
+	config.PublicAccessChecked = true
+	for _, setting := range []struct {
+		name    string
+		enabled *bool
+	}{
+		{"BlockPublicAcls", block.BlockPublicAcls},
+		{"IgnorePublicAcls", block.IgnorePublicAcls},
+		{"BlockPublicPolicy", block.BlockPublicPolicy},
+		{"RestrictPublicBuckets", block.RestrictPublicBuckets},
+	} {
+		if !aws.ToBool(setting.enabled) {
+			config.PublicAccessBlockOff = append(config.PublicAccessBlockOff, setting.name)
+		}
+	}
+
+	if config.PolicyPublic {
+		config.Warnings = append(config.Warnings, "Bucket policy grants PUBLIC access")
+	}
+	if len(config.PublicAccessBlockOff) > 0 {
+		config.Warnings = append(config.Warnings, fmt.Sprintf(
+			"Block Public Access is not fully enabled on the bucket (off: %s)", strings.Join(config.PublicAccessBlockOff, ",")))
+	}
+
+	return nil
+}
+
+// analyzeTags records the bucket's tags
+func (ca *ConfigAnalyzer) analyzeTags(ctx context.Context, bucketName string, config *types.BucketConfiguration) error {
+	result, err := ca.s3Client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if isErrorCode(err, "NoSuchTagSet") {
+			return nil
+		}
+		return err
+	}
+
+	config.Tags = make(map[string]string, len(result.TagSet))
+	for _, tag := range result.TagSet {
+		config.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return nil
+}
+
+// analyzeEncryption records the bucket's default server-side encryption
+func (ca *ConfigAnalyzer) analyzeEncryption(ctx context.Context, bucketName string, config *types.BucketConfiguration) error {
+	result, err := ca.s3Client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if !isErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+			return err
+		}
+	} else if result.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range result.ServerSideEncryptionConfiguration.Rules {
+			if d := rule.ApplyServerSideEncryptionByDefault; d != nil {
+				config.DefaultEncryption = string(d.SSEAlgorithm)
+				break
+			}
+		}
+	}
+
+	config.EncryptionChecked = true
+	if config.DefaultEncryption == "" {
+		config.Warnings = append(config.Warnings, "No default server-side encryption is configured")
+	}
+
+	return nil
+}
+
+// analyzeLifecycle records the bucket's lifecycle rules
+func (ca *ConfigAnalyzer) analyzeLifecycle(ctx context.Context, bucketName string, config *types.BucketConfiguration) error {
+	result, err := ca.s3Client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if isErrorCode(err, "NoSuchLifecycleConfiguration") {
+			config.LifecycleChecked = true
+			return nil
+		}
+		return err
+	}
+
+	config.LifecycleChecked = true
+	for _, r := range result.Rules {
+		rule := types.LifecycleRule{
+			ID:      aws.ToString(r.ID),
+			Enabled: r.Status == s3types.ExpirationStatusEnabled,
+			Prefix:  aws.ToString(r.Prefix),
+		}
+		if r.Filter != nil {
+			if r.Filter.Prefix != nil {
+				rule.Prefix = aws.ToString(r.Filter.Prefix)
+			} else if r.Filter.And != nil {
+				rule.Prefix = aws.ToString(r.Filter.And.Prefix)
+			}
+		}
+		rule.Actions = lifecycleActions(r)
+		config.LifecycleRules = append(config.LifecycleRules, rule)
+	}
+
+	return nil
+}
+
+// lifecycleActions summarizes what a lifecycle rule does
+func lifecycleActions(r s3types.LifecycleRule) []string {
+	var actions []string
+	for _, t := range r.Transitions {
+		actions = append(actions, fmt.Sprintf("transition %s -> %s", lifecycleWhen(t.Days, t.Date != nil), t.StorageClass))
+	}
+	if e := r.Expiration; e != nil {
+		if e.Days != nil || e.Date != nil {
+			actions = append(actions, "expire "+lifecycleWhen(e.Days, e.Date != nil))
+		}
+		if aws.ToBool(e.ExpiredObjectDeleteMarker) {
+			actions = append(actions, "remove expired delete markers")
+		}
+	}
+	for _, t := range r.NoncurrentVersionTransitions {
+		actions = append(actions, fmt.Sprintf("noncurrent transition %dd -> %s", aws.ToInt32(t.NoncurrentDays), t.StorageClass))
+	}
+	if e := r.NoncurrentVersionExpiration; e != nil {
+		actions = append(actions, fmt.Sprintf("noncurrent expire %dd", aws.ToInt32(e.NoncurrentDays)))
+	}
+	if a := r.AbortIncompleteMultipartUpload; a != nil {
+		actions = append(actions, fmt.Sprintf("abort incomplete uploads %dd", aws.ToInt32(a.DaysAfterInitiation)))
+	}
+	return actions
+}
+
+// lifecycleWhen renders a lifecycle action's age or fixed date trigger
+func lifecycleWhen(days *int32, hasDate bool) string {
+	if days == nil && hasDate {
+		return "on date"
+	}
+	return fmt.Sprintf("%dd", aws.ToInt32(days))
+}
+
+// hasEnabledLifecycle reports whether any lifecycle rule is enabled
+func hasEnabledLifecycle(rules []types.LifecycleRule) bool {
+	for _, rule := range rules {
+		if rule.Enabled {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package profiler
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Sharded listing limits
+const (
+	// shardsPerWorker is how many shards discovery aims for per worker, so
+	// one large prefix does not leave the other workers idle at the end
+	shardsPerWorker = 4
+	// maxShardDepth bounds the delimiter levels discovery descends
+	maxShardDepth = 3
+)
+
+// sharded reports whether listings are split across --list-workers. Runs
+// with --limit stop after the first objects in key order, which only a
+// sequential listing returns.
+func (ba *BucketAnalyzer) sharded() bool {
+	return ba.listWorkers > 1 && ba.limit == 0
+}
+
+// compareKeys orders objects the way ListObjectsV2 returns them
+func compareKeys(a, b types.ObjectMetadata) int {
+	return strings.Compare(a.Key, b.Key)
+}
+
+// walkShards lists the bucket with concurrent ListObjectsV2 paginators,
+// one per shard of the key space, passing every page to fn like
+// walkObjects. Pages arrive in no particular order; the summary is only
+// updated from the calling goroutine.
+func (ba *BucketAnalyzer) walkShards(ctx context.Context, bucketName string, summary *types.BucketSummary, fn func([]types.ObjectMetadata) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	control := newConcurrencyController("ListObjectsV2", ba.listWorkers, ba.adaptive)
+	defer func() {
+		summary.Performance = append(summary.Performance, control.stats())
+	}()
+
+	shards, loose, err := ba.discoverShards(ctx, bucketName, control)
+	if err != nil {
+		return err
+	}
+	if ba.adaptive {
+		ba.progress.Printf("Listing %d prefix shard(s) with up to %d workers\n", len(shards), ba.listWorkers)
+	} else {
+		ba.progress.Printf("Listing %d prefix shard(s) with %d workers\n", len(shards), ba.listWorkers)
+	}
+
+	if len(loose) > 0 {
+		for _, obj := range loose {
+			countObject(summary, obj)
+		}
+		if err := fn(loose); err != nil {
+			return err
+		}
+	}
+
+	pages := make(chan []types.ObjectMetadata)
+	var listErr error
+	go func() {
+		defer close(pages)
+		listErr = runParallel(ctx, ba.listWorkers, len(shards), func(ctx context.Context, i int) error {
+			return ba.listShard(ctx, bucketName, shards[i], control, pages)
+		})
+	}()
+
+	for page := range pages {
+		for _, obj := range page {
+			countObject(summary, obj)
+		}
+		if err := fn(page); err != nil {
+			// Stop the workers and let them finish before returning
+			cancel()
+			for range pages {
+			}
+			return err
+		}
+	}
+	return listErr
+}
+
+// discoverShards splits the key space by "/" common prefixes, descending
+// until there are shardsPerWorker shards per worker or maxShardDepth is
+// reached. Objects stored directly at a visited level are returned as
+// loose objects, since listing the level already returned them. Keys
+// without delimiters cannot be split and end up in a single shard.
+func (ba *BucketAnalyzer) discoverShards(ctx context.Context, bucketName string, control *concurrencyController) ([]string, []types.ObjectMetadata, error) {
+	shards := ba.scope.listPrefixes()
+	var loose []types.ObjectMetadata
+
+	for depth := 0; depth < maxShardDepth && len(shards) < ba.listWorkers*shardsPerWorker; depth++ {
+		children := make([][]string, len(shards))
+		objects := make([][]types.ObjectMetadata, len(shards))
+		err := runParallel(ctx, ba.listWorkers, len(shards), func(ctx context.Context, i int) error {
+			var err error
+			children[i], objects[i], err = ba.listLevel(ctx, bucketName, shards[i], control)
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// A prefix without children was listed completely
+		var next []string
+		for i := range shards {
+			loose = append(loose, objects[i]...)
+			next = append(next, children[i]...)
+		}
+		shards = next
+		if len(shards) == 0 {
+			break
+		}
+	}
+
+	return shards, loose, nil
+}
+
+// listLevel lists one delimiter level under prefix: the common prefixes
+// below it and the objects stored directly in it
+func (ba *BucketAnalyzer) listLevel(ctx context.Context, bucketName, prefix string, control *concurrencyController) ([]string, []types.ObjectMetadata, error) {
+	var prefixes []string
+	var objects []types.ObjectMetadata
+
+	paginator := s3.NewListObjectsV2Paginator(ba.s3Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := nextPage(ctx, paginator, control)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range page.CommonPrefixes {
+			prefixes = append(prefixes, aws.ToString(p.Prefix))
+		}
+		objects = append(objects, ba.scope.filter(listedObjects(page.Contents))...)
+	}
+
+	return prefixes, objects, nil
+}
+
+// listShard lists every object under prefix, sending each page to pages
+func (ba *BucketAnalyzer) listShard(ctx context.Context, bucketName, prefix string, control *concurrencyController, pages chan<- []types.ObjectMetadata) error {
+	paginator := s3.NewListObjectsV2Paginator(ba.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := nextPage(ctx, paginator, control)
+		if err != nil {
+			return err
+		}
+		select {
+		case pages <- ba.scope.filter(listedObjects(page.Contents)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// nextPage requests a page once the controller has a free slot, so a
+// worker waiting to send its previous page does not hold one
+func nextPage(ctx context.Context, paginator *s3.ListObjectsV2Paginator, control *concurrencyController) (*s3.ListObjectsV2Output, error) {
+	if err := control.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer control.release()
+
+	page, err := paginator.NextPage(ctx, control.options)
+	if err != nil {
+		return nil, err
+	}
+	control.addItems(len(page.Contents))
+	return page, nil
+}
+
+// runParallel calls fn for the items 0..n-1 on up to workers goroutines.
+// The first error cancels the remaining calls and is returned.
+func runParallel(ctx context.Context, workers, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	items := make(chan int)
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		items <- i
+	}
+	close(items)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,64 @@
+package profiler
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Small-file thresholds used when none are configured
+const (
+	defaultSmallFileSize  = 1 << 20
+	defaultSmallFileCount = 1000
+)
+
+// FlagSmallFiles returns the directories (the prefix up to each key's last
+// "/") holding at least minCount objects under maxSize bytes, with the
+// files they would hold if compacted to 128 MiB. Zero-byte folder markers
+// are ignored. Directories are ordered by their number of small files.
+// Non-positive thresholds use 1 MiB and 1,000 files.
+func (pa *PartitionAnalyzer) FlagSmallFiles(objects []types.ObjectMetadata, maxSize, minCount int64) []types.SmallFilePartition {
+	if maxSize <= 0 {
+		maxSize = defaultSmallFileSize
+	}
+	if minCount <= 0 {
+		minCount = defaultSmallFileCount
+	}
+
+	dirs := make(map[string]*types.SmallFilePartition)
+	for _, obj := range objects {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		prefix := parentPrefix(obj.Key)
+		d, exists := dirs[prefix]
+		if !exists {
+			d = &types.SmallFilePartition{Prefix: prefix}
+			dirs[prefix] = d
+		}
+		d.ObjectCount++
+		d.Size += obj.Size
+		if obj.Size < maxSize {
+			d.SmallFiles++
+		}
+	}
+
+	var flagged []types.SmallFilePartition
+	for _, d := range dirs {
+		if d.SmallFiles < minCount {
+			continue
+		}
+		d.AverageSize = d.Size / d.ObjectCount
+		d.CompactedFiles = max((d.Size+targetFileSize-1)/targetFileSize, 1)
+		d.CompactedSize = d.Size / d.CompactedFiles
+		flagged = append(flagged, *d)
+	}
+	sort.Slice(flagged, func(i, j int) bool {
+		if flagged[i].SmallFiles != flagged[j].SmallFiles {
+			return flagged[i].SmallFiles > flagged[j].SmallFiles
+		}
+		return flagged[i].Prefix < flagged[j].Prefix
+	})
+	return flagged
+}
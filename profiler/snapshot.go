@@ -0,0 +1,135 @@
+package profiler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+const (
+	// snapshotPrefixDepth is how many key levels are recorded in snapshots;
+	// deeper prefixes are rolled up into their ancestor at this depth
+	snapshotPrefixDepth = 3
+
+	// snapshotHistoryDays is how far back daily write volumes are kept
+	snapshotHistoryDays = 90
+
+	// dayLayout keys the daily write histogram
+	dayLayout = "2006-01-02"
+)
+
+// MetricsRecorder receives the gauges of a completed run
+type MetricsRecorder func(metrics output.BucketMetrics)
+
+// RunRecorder stores a completed run in a shared results database and
+// returns its run id
+type RunRecorder func(ctx context.Context, summary *types.BucketSummary, snapshot *types.Snapshot) (int64, error)
+
+// BuildSnapshot records the totals, storage classes, prefix sizes, recent
+// write volumes and partitions of a run
+func BuildSnapshot(summary *types.BucketSummary, objects []types.ObjectMetadata, partitions []types.Partition) *types.Snapshot {
+	now := time.Now().UTC()
+	historyStart := now.AddDate(0, 0, -snapshotHistoryDays)
+
+	snapshot := &types.Snapshot{
+		Bucket:       summary.Name,
+		Generated:    now,
+		TotalObjects: summary.TotalObjects,
+		TotalSize:    summary.TotalSize,
+		Estimate:     summary.Estimate,
+
+		NoContentAccess: summary.NoContentAccess,
+
+		EstimatedCost: summary.EstimatedCost,
+	}
+
+	for class, stats := range summary.StorageClasses {
+		snapshot.StorageClasses = append(snapshot.StorageClasses, types.ClassStats{
+			StorageClass: class,
+			ObjectCount:  stats.Count,
+			Size:         stats.Size,
+		})
+	}
+	sort.Slice(snapshot.StorageClasses, func(i, j int) bool {
+		return snapshot.StorageClasses[i].StorageClass < snapshot.StorageClasses[j].StorageClass
+	})
+
+	prefixes := make(map[string]*types.PrefixStats)
+	for _, obj := range objects {
+		prefix := snapshotPrefix(obj.Key)
+		stats, exists := prefixes[prefix]
+		if !exists {
+			stats = &types.PrefixStats{Prefix: prefix}
+			prefixes[prefix] = stats
+		}
+		stats.ObjectCount++
+		stats.Size += obj.Size
+
+		if obj.LastModified.After(historyStart) {
+			if stats.Written == nil {
+				stats.Written = make(map[string]int64)
+			}
+			stats.Written[obj.LastModified.UTC().Format(dayLayout)] += obj.Size
+		}
+	}
+
+	for _, stats := range prefixes {
+		snapshot.Prefixes = append(snapshot.Prefixes, *stats)
+	}
+	sort.Slice(snapshot.Prefixes, func(i, j int) bool {
+		return snapshot.Prefixes[i].Prefix < snapshot.Prefixes[j].Prefix
+	})
+
+	for _, partition := range partitions {
+		snapshot.Partitions = append(snapshot.Partitions, types.PrefixStats{
+			Prefix:      partition.Scope + partition.Prefix,
+			ObjectCount: partition.ObjectCount,
+			Size:        partition.TotalSize,
+			Date:        partition.Date,
+			Newest:      partition.Newest,
+		})
+	}
+
+	return snapshot
+}
+
+// snapshotPrefix returns the prefix an object is recorded under: its
+// directory, cut off at snapshotPrefixDepth levels ("/" for the root).
+// Recorded prefixes never overlap, so their totals add up to the bucket's.
+func snapshotPrefix(key string) string {
+	segments := strings.Split(key, "/")
+	// Drop the object name; a trailing slash yields an empty last segment
+	segments = segments[:len(segments)-1]
+	if len(segments) == 0 {
+		return "/"
+	}
+	if len(segments) > snapshotPrefixDepth {
+		segments = segments[:snapshotPrefixDepth]
+	}
+	return strings.Join(segments, "/") + "/"
+}
+
+// LoadSnapshot reads a snapshot written by a previous run
+func LoadSnapshot(path string) (*types.Snapshot, error) {
+	if strings.HasSuffix(path, ".age") {
+		return nil, fmt.Errorf("snapshot %s is encrypted; decrypt it with age -d first", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot types.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,146 @@
+package profiler
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// maxSigningCertBytes bounds the size of an SNS signing certificate
+const maxSigningCertBytes = 64 << 10
+
+// snsCertHost matches the hosts SNS serves its signing certificates from
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// SNSVerifier checks the signatures of SNS messages with the certificates
+// SNS signs them with, fetched once per certificate URL
+type SNSVerifier struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSNSVerifier creates a verifier that fetches certificates with client
+func NewSNSVerifier(client *http.Client) *SNSVerifier {
+	return &SNSVerifier{client: client, certs: make(map[string]*x509.Certificate)}
+}
+
+// verify checks the signature of an SNS message
+func (v *SNSVerifier) verify(ctx context.Context, m *snsMessage) error {
+	var hash crypto.Hash
+	switch m.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported SNS signature version %q", m.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid SNS signature: %w", err)
+	}
+	cert, err := v.certificate(ctx, m.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("SNS signing certificate %s has no RSA key", m.SigningCertURL)
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(m.stringToSign()))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(m.stringToSign()))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return fmt.Errorf("invalid SNS signature: %w", err)
+	}
+	return nil
+}
+
+// certificate returns the signing certificate at certURL, which must be
+// served over HTTPS by SNS
+func (v *SNSVerifier) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	u, err := url.Parse(certURL)
+	if err != nil || u.Scheme != "https" || !snsCertHost.MatchString(u.Hostname()) || u.Port() != "" || !strings.HasSuffix(u.Path, ".pem") {
+		return nil, fmt.Errorf("invalid SNS signing certificate URL %q", certURL)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if cert, ok := v.certs[certURL]; ok {
+		return cert, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SNS signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch SNS signing certificate: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSigningCertBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SNS signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("SNS signing certificate %s is not PEM", certURL)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SNS signing certificate: %w", err)
+	}
+	v.certs[certURL] = cert
+	return cert, nil
+}
+
+// stringToSign returns the fields SNS signs, as name and value lines in
+// alphabetical order; Subject is signed only when the message has one
+func (m *snsMessage) stringToSign() string {
+	var b strings.Builder
+	field := func(name, value string) {
+		b.WriteString(name)
+		b.WriteByte('\n')
+		b.WriteString(value)
+		b.WriteByte('\n')
+	}
+	field("Message", m.Message)
+	field("MessageId", m.MessageID)
+	if m.Type == "Notification" {
+		if m.Subject != nil {
+			field("Subject", *m.Subject)
+		}
+	} else {
+		field("SubscribeURL", m.SubscribeURL)
+	}
+	field("Timestamp", m.Timestamp)
+	if m.Type != "Notification" {
+		field("Token", m.Token)
+	}
+	field("TopicArn", m.TopicArn)
+	field("Type", m.Type)
+	return b.String()
+}
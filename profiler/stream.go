@@ -0,0 +1,148 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Stage names a step of a streamed profile
+type Stage string
+
+const (
+	// StageBucket looks up the bucket's region and creation date
+	StageBucket Stage = "bucket"
+	// StageList lists the bucket's objects
+	StageList Stage = "list"
+)
+
+// streamBuffer is the number of events buffered ahead of the consumer
+const streamBuffer = 16
+
+// Event is emitted by ProfileStream. It is one of ObjectBatch,
+// StageComplete, Warning or Done.
+type Event interface {
+	event()
+}
+
+// ObjectBatch carries one page of listed objects. The slice is not reused
+// and may be retained by the consumer.
+type ObjectBatch struct {
+	Objects []types.ObjectMetadata
+}
+
+// StageComplete reports that a stage finished. Summary holds the running
+// totals at that point.
+type StageComplete struct {
+	Stage   Stage
+	Summary types.BucketSummary
+}
+
+// Warning reports a non-fatal problem; the stream continues
+type Warning struct {
+	Stage Stage
+	Err   error
+}
+
+// Done is always the last event. Err is set when the stream stopped early.
+type Done struct {
+	Summary *types.BucketSummary
+	Err     error
+}
+
+func (ObjectBatch) event()   {}
+func (StageComplete) event() {}
+func (Warning) event()       {}
+func (Done) event()          {}
+
+// ProfileStream lists a bucket and emits its objects as typed events
+// without running any analyzers or writing reports, so that embedding
+// services can do their own aggregation and storage. Errors before
+// listing starts, such as a missing bucket, are returned directly.
+// The channel is closed after Done; consumers must drain it or cancel ctx.
+func (p *Profiler) ProfileStream(ctx context.Context, bucketName string) (<-chan Event, error) {
+	creationDate, err := p.bucketAnalyzer.getBucketCreationDate(ctx, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket creation date: %w", err)
+	}
+
+	summary := &types.BucketSummary{
+		Name:           bucketName,
+		CreationDate:   creationDate,
+		StorageClasses: make(map[string]types.StorageClassStats),
+	}
+
+	events := make(chan Event, streamBuffer)
+	go func() {
+		defer close(events)
+
+		send := func(e Event) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		// The region is informational; a denied lookup should not stop the listing
+		region, err := p.bucketRegion(ctx, bucketName)
+		if err != nil {
+			if send(Warning{Stage: StageBucket, Err: fmt.Errorf("failed to get bucket region: %w", err)}) != nil {
+				return
+			}
+		}
+		summary.Region = region
+		if send(StageComplete{Stage: StageBucket, Summary: snapshotSummary(summary)}) != nil {
+			return
+		}
+
+		err = p.bucketAnalyzer.walkObjects(ctx, bucketName, summary, nil, func(page []types.ObjectMetadata) error {
+			return send(ObjectBatch{Objects: page})
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				send(Done{Summary: summary, Err: fmt.Errorf("failed to list objects: %w", err)})
+			}
+			return
+		}
+
+		summary.EstimatedCost = p.bucketAnalyzer.calculateCost(summary.StorageClasses)
+		if err := send(StageComplete{Stage: StageList, Summary: snapshotSummary(summary)}); err != nil {
+			return
+		}
+
+		send(Done{Summary: summary})
+	}()
+
+	return events, nil
+}
+
+// bucketRegion returns the bucket's region from GetBucketLocation
+func (p *Profiler) bucketRegion(ctx context.Context, bucketName string) (string, error) {
+	result, err := p.s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// An empty constraint means us-east-1
+	if result.LocationConstraint == "" {
+		return "us-east-1", nil
+	}
+	return string(result.LocationConstraint), nil
+}
+
+// snapshotSummary copies the summary so events are not changed by later pages
+func snapshotSummary(summary *types.BucketSummary) types.BucketSummary {
+	copied := *summary
+	copied.StorageClasses = make(map[string]types.StorageClassStats, len(summary.StorageClasses))
+	for class, stats := range summary.StorageClasses {
+		copied.StorageClasses[class] = stats
+	}
+	return copied
+}
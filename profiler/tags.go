@@ -0,0 +1,214 @@
+package profiler
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// maxTagValues caps the values kept per tag key and the untagged prefixes
+// kept in a TagSummary
+const maxTagValues = 100
+
+// TagAnalyzer reads object tags with GetObjectTagging and aggregates the
+// objects, bytes and storage cost per tag key and value
+type TagAnalyzer struct {
+	s3Client *s3.Client
+	// perPrefix is the number of objects read per prefix; negative reads
+	// every object
+	perPrefix int
+	workers   int
+	// adaptive tunes the concurrency up to workers
+	adaptive bool
+	prices   storagePrices
+}
+
+// NewTagAnalyzer creates an analyzer that reads the tags of up to perPrefix
+// objects in every prefix, or of all objects when perPrefix is negative,
+// using at most workers concurrent requests
+func NewTagAnalyzer(s3Client *s3.Client, perPrefix, workers int) *TagAnalyzer {
+	if workers <= 0 {
+		workers = defaultEnrichWorkers
+	}
+	return &TagAnalyzer{
+		s3Client:  s3Client,
+		perPrefix: perPrefix,
+		workers:   workers,
+	}
+}
+
+// Enabled reports whether tag aggregation was requested
+func (ta *TagAnalyzer) Enabled() bool {
+	return ta.perPrefix != 0
+}
+
+// tagUsage accumulates weighted usage before it is rounded
+type tagUsage struct {
+	objects float64
+	bytes   float64
+	cost    float64
+}
+
+// add counts an object whose monthly storage cost is cost
+func (u *tagUsage) add(job enrichJob, cost float64) {
+	u.objects += job.weight
+	u.bytes += job.weight * float64(job.object.Size)
+	u.cost += job.weight * cost
+}
+
+func (u *tagUsage) usage(name string) types.TagUsage {
+	return types.TagUsage{
+		Name:        name,
+		ObjectCount: int64(u.objects + 0.5),
+		TotalSize:   int64(u.bytes + 0.5),
+		MonthlyCost: u.cost,
+	}
+}
+
+// tagKey accumulates one tag key and its values
+type tagKey struct {
+	tagUsage
+	values map[string]*tagUsage
+}
+
+// AnalyzeTags calls GetObjectTagging for a stratified sample of the
+// objects, or for all of them. Failed requests are counted rather than
+// failing the analysis; they are left out of the tagged and untagged
+// totals.
+func (ta *TagAnalyzer) AnalyzeTags(ctx context.Context, bucketName string, objects []types.ObjectMetadata) (*types.TagSummary, error) {
+	jobs, strata := samplePerPrefix(objects, ta.perPrefix)
+
+	summary := &types.TagSummary{
+		Strata:   strata,
+		Complete: ta.perPrefix < 0,
+	}
+	var (
+		tagged   tagUsage
+		untagged tagUsage
+		keys     = make(map[string]*tagKey)
+		prefixes = make(map[string]*tagUsage)
+	)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	jobChan := make(chan enrichJob)
+	control := newConcurrencyController("GetObjectTagging", ta.workers, ta.adaptive)
+
+	for i := 0; i < ta.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				if control.acquire(ctx) != nil {
+					continue
+				}
+				result, err := ta.s3Client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+					Bucket: aws.String(bucketName),
+					Key:    aws.String(job.object.Key),
+				}, control.options)
+				control.release()
+				control.addItems(1)
+
+				mu.Lock()
+				if err != nil {
+					summary.Failed++
+					mu.Unlock()
+					continue
+				}
+				summary.Sampled++
+				cost := ta.prices.cost(job.object.StorageClass, job.object.Size)
+				if len(result.TagSet) == 0 {
+					untagged.add(job, cost)
+					prefix, ok := prefixes[job.prefix]
+					if !ok {
+						prefix = &tagUsage{}
+						prefixes[job.prefix] = prefix
+					}
+					prefix.add(job, cost)
+				} else {
+					tagged.add(job, cost)
+				}
+				for _, tag := range result.TagSet {
+					name := aws.ToString(tag.Key)
+					key, ok := keys[name]
+					if !ok {
+						key = &tagKey{values: make(map[string]*tagUsage)}
+						keys[name] = key
+					}
+					key.add(job, cost)
+					value, ok := key.values[aws.ToString(tag.Value)]
+					if !ok {
+						value = &tagUsage{}
+						key.values[aws.ToString(tag.Value)] = value
+					}
+					value.add(job, cost)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		jobChan <- job
+	}
+	close(jobChan)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	summary.Tagged = tagged.usage("")
+	summary.Untagged = untagged.usage("")
+	for name, key := range keys {
+		usage := types.TagKeyUsage{
+			TagUsage:       key.usage(name),
+			Values:         sortedTagUsage(key.values, func(a, b types.TagUsage) bool { return a.ObjectCount > b.ObjectCount }),
+			DistinctValues: len(key.values),
+		}
+		summary.Keys = append(summary.Keys, usage)
+	}
+	sort.Slice(summary.Keys, func(i, j int) bool {
+		a, b := summary.Keys[i], summary.Keys[j]
+		if a.ObjectCount != b.ObjectCount {
+			return a.ObjectCount > b.ObjectCount
+		}
+		return a.Name < b.Name
+	})
+	summary.UntaggedPrefixes = sortedTagUsage(prefixes, func(a, b types.TagUsage) bool { return a.TotalSize > b.TotalSize })
+
+	stats := control.stats()
+	summary.Performance = &stats
+	return summary, nil
+}
+
+// sortedTagUsage rounds accumulated usage and returns up to maxTagValues
+// entries in the given order, ties broken by name
+func sortedTagUsage(usage map[string]*tagUsage, before func(a, b types.TagUsage) bool) []types.TagUsage {
+	result := make([]types.TagUsage, 0, len(usage))
+	for name, u := range usage {
+		result = append(result, u.usage(name))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if before(result[i], result[j]) {
+			return true
+		}
+		if before(result[j], result[i]) {
+			return false
+		}
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > maxTagValues {
+		result = result[:maxTagValues]
+	}
+	return result
+}
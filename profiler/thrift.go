@@ -0,0 +1,206 @@
+package profiler
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Thrift compact protocol type codes
+const (
+	thriftStop      = 0
+	thriftTrue      = 1
+	thriftFalse     = 2
+	thriftByte      = 3
+	thriftI16       = 4
+	thriftI32       = 5
+	thriftI64       = 6
+	thriftDouble    = 7
+	thriftBinary    = 8
+	thriftList      = 9
+	thriftSet       = 10
+	thriftMap       = 11
+	thriftStruct    = 12
+	thriftMaxNested = 64
+)
+
+var errThriftTruncated = errors.New("truncated thrift data")
+
+// thriftReader decodes the subset of the Thrift compact protocol needed to
+// read Parquet footers. Unknown fields are skipped, so only the fields of
+// interest have to be handled by callers.
+type thriftReader struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+// readStruct calls field for every field of the struct at the current
+// position; field must consume the value or call skip
+func (r *thriftReader) readStruct(field func(id int16, typ byte) error) error {
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > thriftMaxNested {
+		return errors.New("thrift data nested too deeply")
+	}
+
+	var lastID int16
+	for {
+		header, err := r.byte()
+		if err != nil {
+			return err
+		}
+		typ := header & 0x0f
+		if typ == thriftStop {
+			return nil
+		}
+
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := r.varint()
+			if err != nil {
+				return err
+			}
+			id = int16(zigzag(v))
+		}
+		lastID = id
+
+		if err := field(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// readList reads a list header and calls elem for each element
+func (r *thriftReader) readList(elem func(typ byte) error) error {
+	header, err := r.byte()
+	if err != nil {
+		return err
+	}
+	size := int(header >> 4)
+	if size == 15 {
+		v, err := r.varint()
+		if err != nil {
+			return err
+		}
+		size = int(v)
+	}
+	if size < 0 || size > len(r.buf)-r.pos {
+		return errThriftTruncated
+	}
+
+	typ := header & 0x0f
+	for i := 0; i < size; i++ {
+		if err := elem(typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// i64 reads an i16, i32 or i64 value
+func (r *thriftReader) i64() (int64, error) {
+	v, err := r.varint()
+	return zigzag(v), err
+}
+
+// binary reads a length-prefixed string or byte array
+func (r *thriftReader) binary() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)-r.pos) {
+		return nil, errThriftTruncated
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+// skip consumes a value of the given type
+func (r *thriftReader) skip(typ byte) error {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		return nil
+	case thriftByte:
+		_, err := r.byte()
+		return err
+	case thriftI16, thriftI32, thriftI64:
+		_, err := r.varint()
+		return err
+	case thriftDouble:
+		if len(r.buf)-r.pos < 8 {
+			return errThriftTruncated
+		}
+		r.pos += 8
+		return nil
+	case thriftBinary:
+		_, err := r.binary()
+		return err
+	case thriftList, thriftSet:
+		return r.readList(r.skipElem)
+	case thriftMap:
+		n, err := r.varint()
+		if err != nil || n == 0 {
+			return err
+		}
+		// Every entry takes at least one byte
+		if n > uint64(len(r.buf)-r.pos) {
+			return errThriftTruncated
+		}
+		kv, err := r.byte()
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < n; i++ {
+			if err := r.skipElem(kv >> 4); err != nil {
+				return err
+			}
+			if err := r.skipElem(kv & 0x0f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftStruct:
+		return r.readStruct(func(_ int16, typ byte) error { return r.skip(typ) })
+	}
+	return errors.New("unknown thrift type")
+}
+
+// skipElem consumes a list, set or map element. Booleans there are encoded
+// as one byte each rather than in the field type.
+func (r *thriftReader) skipElem(typ byte) error {
+	if typ == thriftTrue || typ == thriftFalse {
+		_, err := r.byte()
+		return err
+	}
+	return r.skip(typ)
+}
+
+func (r *thriftReader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errThriftTruncated
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errThriftTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+func zigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// float64FromBits decodes a little-endian IEEE 754 double
+func float64FromBits(b []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
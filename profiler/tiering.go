@@ -0,0 +1,126 @@
+package profiler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Approximate US East Intelligent-Tiering pricing
+const (
+	tieringFrequentPerGB   = 0.023
+	tieringInfrequentPerGB = 0.0125
+	tieringArchivePerGB    = 0.004
+	// tieringMonitoringPerThousand is charged monthly per monitored object
+	tieringMonitoringPerThousand = 0.0025
+	// tieringTransitionPerThousand is the one-time lifecycle transition fee
+	tieringTransitionPerThousand = 0.01
+	// tieringMinObjectSize is the smallest object lifecycle rules move into
+	// Intelligent-Tiering and that is monitored
+	tieringMinObjectSize = 128 << 10
+)
+
+// Days without access after which Intelligent-Tiering moves an object down
+const (
+	tieringInfrequentAfter = 30 * 24 * time.Hour
+	tieringArchiveAfter    = 90 * 24 * time.Hour
+)
+
+// tieringSourceClasses are the classes a wholesale switch would move
+var tieringSourceClasses = map[string]bool{
+	"STANDARD":    true,
+	"STANDARD_IA": true,
+}
+
+// tieringPerGB is the Intelligent-Tiering storage price of an object that
+// has not been accessed for age
+func tieringPerGB(age time.Duration) float64 {
+	switch {
+	case age >= tieringArchiveAfter:
+		return tieringArchivePerGB
+	case age >= tieringInfrequentAfter:
+		return tieringInfrequentPerGB
+	default:
+		return tieringFrequentPerGB
+	}
+}
+
+// SimulateIntelligentTiering models moving each top-level prefix's
+// STANDARD and STANDARD_IA objects to Intelligent-Tiering. An object's age
+// since its last write stands in for the time since its last access, so
+// the result holds for data that is read no more often than it is written.
+func (ba *BucketAnalyzer) SimulateIntelligentTiering(objects []types.ObjectMetadata) *types.TieringSimulation {
+	now := time.Now()
+	gb := func(size int64) float64 { return float64(size) / (1024 * 1024 * 1024) }
+
+	estimates := make(map[string]*types.TieringEstimate)
+	// maxSavings is each prefix's storage saving if every monitored object
+	// dropped to the infrequent tier
+	maxSavings := make(map[string]float64)
+	for _, obj := range objects {
+		if !tieringSourceClasses[obj.StorageClass] {
+			continue
+		}
+
+		prefix := topLevelPrefix(obj.Key)
+		estimate, exists := estimates[prefix]
+		if !exists {
+			estimate = &types.TieringEstimate{Prefix: prefix}
+			estimates[prefix] = estimate
+		}
+		estimate.ObjectCount++
+		estimate.Size += obj.Size
+
+		current := ba.prices.cost(obj.StorageClass, obj.Size)
+		estimate.CurrentCost += current
+		if obj.Size < tieringMinObjectSize {
+			// Small objects stay where they are
+			estimate.TieredCost += current
+			continue
+		}
+
+		estimate.MonitoredObjects++
+		estimate.MonitoredSize += obj.Size
+		maxSavings[prefix] += current - gb(obj.Size)*tieringInfrequentPerGB
+
+		age := now.Sub(obj.LastModified)
+		estimate.TieredCost += gb(obj.Size) * tieringPerGB(age)
+		switch {
+		case age >= tieringArchiveAfter:
+			estimate.ArchiveSize += obj.Size
+		case age >= tieringInfrequentAfter:
+			estimate.InfrequentSize += obj.Size
+		}
+	}
+
+	// Below this size, monitoring costs more than moving the object from
+	// STANDARD to the infrequent tier saves
+	breakEvenGB := tieringMonitoringPerThousand / 1000 / (tieringFrequentPerGB - tieringInfrequentPerGB)
+	simulation := &types.TieringSimulation{BreakEvenObjectSize: int64(breakEvenGB * (1024 * 1024 * 1024))}
+	for prefix, estimate := range estimates {
+		if estimate.MonitoredObjects == 0 {
+			continue
+		}
+		estimate.MonitoringFee = float64(estimate.MonitoredObjects) / 1000 * tieringMonitoringPerThousand
+		estimate.TieredCost += estimate.MonitoringFee
+		estimate.TransitionCost = float64(estimate.MonitoredObjects) / 1000 * tieringTransitionPerThousand
+		estimate.Savings = estimate.CurrentCost - estimate.TieredCost
+		if maxSavings[prefix] > 0 {
+			estimate.BreakEvenColdShare = estimate.MonitoringFee / maxSavings[prefix]
+		}
+		if estimate.Savings > 0 {
+			estimate.PaybackMonths = estimate.TransitionCost / estimate.Savings
+		}
+		simulation.Prefixes = append(simulation.Prefixes, *estimate)
+	}
+
+	sort.Slice(simulation.Prefixes, func(i, j int) bool {
+		if simulation.Prefixes[i].Savings != simulation.Prefixes[j].Savings {
+			return simulation.Prefixes[i].Savings > simulation.Prefixes[j].Savings
+		}
+		return simulation.Prefixes[i].Prefix < simulation.Prefixes[j].Prefix
+	})
+
+	return simulation
+}
@@ -0,0 +1,253 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/s3-profiler/output"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// trendRuns is the number of recent runs growth is fitted to
+const trendRuns = 10
+
+// minTrendSpan is the shortest run history growth is fitted to, so two
+// runs minutes apart do not produce a wild rate
+const minTrendSpan = 24 * time.Hour
+
+// RunHistoryFunc lists a bucket's stored runs, newest first
+type RunHistoryFunc func(ctx context.Context, bucket string, limit int) ([]types.RunRecord, error)
+
+// SNSPublishFunc publishes a message to an SNS topic
+type SNSPublishFunc func(ctx context.Context, topicARN, subject, message string) error
+
+// CapacityAlerter projects each bucket's size and storage cost from its
+// run history and alerts when a threshold is crossed or will be within a
+// number of days. Alerts go to a webhook (Slack incoming webhooks get a
+// text message) or an SNS topic ARN; without a target they are only
+// printed.
+type CapacityAlerter struct {
+	target     string
+	capacity   int64
+	budget     float64
+	within     int
+	client     *http.Client
+	publishSNS SNSPublishFunc
+}
+
+// NewCapacityAlerter creates an alerter for a capacity in bytes and a
+// monthly budget in USD (0 disables either)
+func NewCapacityAlerter(target string, capacity int64, budget float64, withinDays int) (*CapacityAlerter, error) {
+	if capacity < 0 || budget < 0 {
+		return nil, fmt.Errorf("alert thresholds must not be negative")
+	}
+	if withinDays < 0 {
+		return nil, fmt.Errorf("invalid alert horizon %d days", withinDays)
+	}
+	if target != "" && !strings.HasPrefix(target, "arn:aws:sns:") {
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("unsupported alert target %q (use an http(s):// webhook or an SNS topic ARN)", target)
+		}
+	}
+	return &CapacityAlerter{
+		target:   target,
+		capacity: capacity,
+		budget:   budget,
+		within:   withinDays,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Enabled reports whether a capacity or budget threshold was configured
+func (a *CapacityAlerter) Enabled() bool {
+	return a.capacity > 0 || a.budget > 0
+}
+
+// fitGrowth fits size and cost growth per day to a bucket's runs by least
+// squares. Truncated runs only cover part of the bucket and are left out.
+// It returns nil when the runs span less than minTrendSpan.
+func fitGrowth(runs []types.RunRecord) *types.GrowthTrend {
+	var full []types.RunRecord
+	for _, run := range runs {
+		if !run.Truncated {
+			full = append(full, run)
+		}
+	}
+	if len(full) < 2 {
+		return nil
+	}
+
+	first, last := full[0].Generated, full[0].Generated
+	for _, run := range full {
+		if run.Generated.Before(first) {
+			first = run.Generated
+		}
+		if run.Generated.After(last) {
+			last = run.Generated
+		}
+	}
+	if last.Sub(first) < minTrendSpan {
+		return nil
+	}
+
+	n := float64(len(full))
+	var sumX, sumSize, sumCost float64
+	for _, run := range full {
+		sumX += run.Generated.Sub(first).Hours() / 24
+		sumSize += float64(run.TotalSize)
+		sumCost += run.EstimatedCost
+	}
+	meanX, meanSize, meanCost := sumX/n, sumSize/n, sumCost/n
+
+	var varX, covSize, covCost float64
+	for _, run := range full {
+		dx := run.Generated.Sub(first).Hours()/24 - meanX
+		varX += dx * dx
+		covSize += dx * (float64(run.TotalSize) - meanSize)
+		covCost += dx * (run.EstimatedCost - meanCost)
+	}
+
+	return &types.GrowthTrend{
+		Runs:       len(full),
+		Since:      first,
+		SizePerDay: covSize / varX,
+		CostPerDay: covCost / varX,
+	}
+}
+
+// Check compares the current totals and their trend with the thresholds
+func (a *CapacityAlerter) Check(summary *types.BucketSummary, trend *types.GrowthTrend, now time.Time) []types.CapacityAlert {
+	var alerts []types.CapacityAlert
+	check := func(kind, label string, current, threshold, perDay float64, format func(float64) string) {
+		if threshold <= 0 {
+			return
+		}
+		alert := types.CapacityAlert{
+			Bucket:    summary.Name,
+			Kind:      kind,
+			Current:   current,
+			Threshold: threshold,
+			PerDay:    perDay,
+		}
+		switch {
+		case current >= threshold:
+			alert.Message = fmt.Sprintf("%s: %s at %s has reached the threshold of %s",
+				summary.Name, label, format(current), format(threshold))
+		case perDay > 0 && (threshold-current)/perDay <= float64(a.within):
+			alert.DaysLeft = (threshold - current) / perDay
+			alert.Crossing = now.Add(time.Duration(alert.DaysLeft * 24 * float64(time.Hour))).UTC()
+			alert.Message = fmt.Sprintf("%s: %s at %s, growing %s a day, will reach %s in %.0f days (around %s)",
+				summary.Name, label, format(current), format(perDay), format(threshold),
+				math.Ceil(alert.DaysLeft), alert.Crossing.Format("2006-01-02"))
+		default:
+			return
+		}
+		alerts = append(alerts, alert)
+	}
+
+	check("capacity", "size", float64(summary.TotalSize), float64(a.capacity), trend.SizePerDay, formatSignedBytes)
+	check("budget", "monthly storage cost", summary.EstimatedCost, a.budget, trend.CostPerDay, output.FormatCost)
+	return alerts
+}
+
+// Send delivers a bucket's alerts to the configured target
+func (a *CapacityAlerter) Send(ctx context.Context, bucketName string, alerts []types.CapacityAlert) error {
+	messages := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		messages = append(messages, alert.Message)
+	}
+	text := strings.Join(messages, "\n")
+
+	if strings.HasPrefix(a.target, "arn:aws:sns:") {
+		if a.publishSNS == nil {
+			return fmt.Errorf("SNS publishing is not available")
+		}
+		// SNS subjects are limited to 100 characters
+		subject := "s3-profiler capacity alert: " + bucketName
+		if len(subject) > 100 {
+			subject = subject[:100]
+		}
+		return a.publishSNS(ctx, a.target, subject, text)
+	}
+
+	var payload any = struct {
+		Bucket string                `json:"bucket"`
+		Alerts []types.CapacityAlert `json:"alerts"`
+	}{bucketName, alerts}
+	if u, _ := url.Parse(a.target); u.Host == "hooks.slack.com" {
+		payload = map[string]string{"text": text}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("alert target returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// checkCapacity fits growth to the bucket's stored runs, which include
+// this one, and raises capacity and budget alerts
+func (p *Profiler) checkCapacity(ctx context.Context, summary *types.BucketSummary) {
+	if summary.Truncated {
+		p.progress.Printf("Skipping capacity alerts: --limit truncated the listing\n")
+		return
+	}
+
+	runs, err := p.runHistory(ctx, summary.Name, trendRuns)
+	if err != nil {
+		p.progress.Warnf("failed to load run history for capacity alerts: %v", err)
+		return
+	}
+	trend := fitGrowth(runs)
+	if trend == nil {
+		p.progress.Printf("Growth projections need two complete runs at least a day apart; checking current totals only\n")
+		trend = &types.GrowthTrend{}
+	} else {
+		p.progress.Printf("Growth over %d runs since %s: size %s a day, monthly cost %s a day\n",
+			trend.Runs, trend.Since.Format("2006-01-02"), formatSignedBytes(trend.SizePerDay), output.FormatCost(trend.CostPerDay))
+	}
+
+	alerts := p.alerter.Check(summary, trend, time.Now())
+	for _, alert := range alerts {
+		p.progress.Printf("Capacity alert: %s\n", alert.Message)
+	}
+	if len(alerts) == 0 || p.alerter.target == "" {
+		return
+	}
+	if err := p.alerter.Send(ctx, summary.Name, alerts); err != nil {
+		p.progress.Warnf("%v", err)
+	} else {
+		p.progress.Printf("Sent %d alert(s) to %s\n", len(alerts), p.alerter.target)
+	}
+}
+
+// formatSignedBytes renders a byte rate that may be negative
+func formatSignedBytes(v float64) string {
+	if v < 0 {
+		return "-" + output.FormatBytes(int64(-v))
+	}
+	return output.FormatBytes(int64(v))
+}
@@ -0,0 +1,247 @@
+package profiler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ObjectEvent is an object creation reported by an S3 event notification
+// or an EventBridge event
+type ObjectEvent struct {
+	Bucket string
+	Key    string
+	Size   int64
+}
+
+// s3Notification is an S3 event notification, delivered directly or
+// wrapped in an SNS message
+type s3Notification struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key  string `json:"key"`
+				Size int64  `json:"size"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// eventBridgeEvent is an S3 event delivered by EventBridge
+type eventBridgeEvent struct {
+	Source     string `json:"source"`
+	DetailType string `json:"detail-type"`
+	Detail     struct {
+		Bucket struct {
+			Name string `json:"name"`
+		} `json:"bucket"`
+		Object struct {
+			Key  string `json:"key"`
+			Size int64  `json:"size"`
+		} `json:"object"`
+	} `json:"detail"`
+}
+
+// snsMessage is the envelope of an SNS HTTP(S) delivery
+type snsMessage struct {
+	Type         string  `json:"Type"`
+	MessageID    string  `json:"MessageId"`
+	TopicArn     string  `json:"TopicArn"`
+	Subject      *string `json:"Subject"`
+	Message      string  `json:"Message"`
+	Timestamp    string  `json:"Timestamp"`
+	Token        string  `json:"Token"`
+	SubscribeURL string  `json:"SubscribeURL"`
+
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// ParseObjectEvents returns the object creations in a webhook body: an S3
+// event notification, an EventBridge "Object Created" event, or either
+// wrapped in an SNS notification. An SNS subscription confirmation returns
+// the URL that confirms it. SNS messages are trusted only once sns has
+// verified their signature. Other events are ignored.
+func ParseObjectEvents(ctx context.Context, body []byte, sns *SNSVerifier) ([]ObjectEvent, string, error) {
+	var envelope snsMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, "", fmt.Errorf("invalid event: %w", err)
+	}
+	switch envelope.Type {
+	case "SubscriptionConfirmation", "Notification":
+		if err := sns.verify(ctx, &envelope); err != nil {
+			return nil, "", err
+		}
+	}
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		u, err := url.Parse(envelope.SubscribeURL)
+		if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+			return nil, "", fmt.Errorf("invalid SNS subscribe URL %q", envelope.SubscribeURL)
+		}
+		return nil, envelope.SubscribeURL, nil
+	case "Notification":
+		body = []byte(envelope.Message)
+	}
+
+	var notification s3Notification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, "", fmt.Errorf("invalid event: %w", err)
+	}
+	var events []ObjectEvent
+	for _, record := range notification.Records {
+		if !strings.HasPrefix(record.EventName, "ObjectCreated:") {
+			continue
+		}
+		// Keys in S3 event notifications are URL-encoded
+		key, err := url.QueryUnescape(record.S3.Object.Key)
+		if err != nil {
+			key = record.S3.Object.Key
+		}
+		events = append(events, ObjectEvent{Bucket: record.S3.Bucket.Name, Key: key, Size: record.S3.Object.Size})
+	}
+	if len(notification.Records) > 0 {
+		return events, "", nil
+	}
+
+	var event eventBridgeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, "", fmt.Errorf("invalid event: %w", err)
+	}
+	if event.Source == "aws.s3" && event.DetailType == "Object Created" {
+		events = append(events, ObjectEvent{Bucket: event.Detail.Bucket.Name, Key: event.Detail.Object.Key, Size: event.Detail.Object.Size})
+	}
+	return events, "", nil
+}
+
+// ProfileTrigger is a prefix to profile because of an object event
+type ProfileTrigger struct {
+	Bucket string
+	// Prefix is the prefix to profile, "" for keys at the bucket root
+	Prefix string
+	Reason string
+}
+
+// TriggerRules decides which object events start a profile of their
+// prefix: uploads of at least minSize bytes, and the first object of a
+// prefix at depth levels, counted from the root. Prefixes of the watched
+// buckets are listed when the rules are created, so objects of existing
+// prefixes do not count as new.
+type TriggerRules struct {
+	s3Client *s3.Client
+	minSize  int64
+	depth    int
+	// newPrefixes is set when new prefixes trigger profiles
+	newPrefixes bool
+
+	mu    sync.Mutex
+	known map[string]map[string]bool
+}
+
+// NewTriggerRules creates the rules for the watched buckets. minSize <= 0
+// disables the size trigger; depth is the number of key segments a
+// profiled prefix has (at least 1).
+func NewTriggerRules(ctx context.Context, s3Client *s3.Client, buckets []string, minSize int64, depth int, newPrefixes bool) (*TriggerRules, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("trigger depth must be at least 1")
+	}
+	tr := &TriggerRules{
+		s3Client:    s3Client,
+		minSize:     minSize,
+		depth:       depth,
+		newPrefixes: newPrefixes,
+		known:       make(map[string]map[string]bool),
+	}
+	for _, bucket := range buckets {
+		known := make(map[string]bool)
+		if newPrefixes {
+			if err := tr.listPrefixes(ctx, bucket, "", 1, known); err != nil {
+				return nil, err
+			}
+		}
+		tr.known[bucket] = known
+	}
+	return tr, nil
+}
+
+// listPrefixes adds the prefixes under prefix down to the rules' depth
+func (tr *TriggerRules) listPrefixes(ctx context.Context, bucket, prefix string, level int, known map[string]bool) error {
+	paginator := s3.NewListObjectsV2Paginator(tr.s3Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list prefixes of %s: %w", bucket, err)
+		}
+		for _, p := range page.CommonPrefixes {
+			child := aws.ToString(p.Prefix)
+			known[child] = true
+			if level < tr.depth {
+				if err := tr.listPrefixes(ctx, bucket, child, level+1, known); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// prefixAt returns the first depth segments of a key as a prefix; keys
+// with fewer segments return their directory, "" at the bucket root
+func prefixAt(key string, depth int) string {
+	end := 0
+	for i := 0; i < depth; i++ {
+		next := strings.IndexByte(key[end:], '/')
+		if next < 0 {
+			break
+		}
+		end += next + 1
+	}
+	return key[:end]
+}
+
+// Match returns the prefixes to profile for a batch of events, one per
+// prefix. Events of buckets that are not watched are ignored.
+func (tr *TriggerRules) Match(events []ObjectEvent) []ProfileTrigger {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	var triggers []ProfileTrigger
+	seen := make(map[[2]string]bool)
+	for _, event := range events {
+		known, watched := tr.known[event.Bucket]
+		if !watched {
+			continue
+		}
+		prefix := prefixAt(event.Key, tr.depth)
+		var reason string
+		switch {
+		case tr.newPrefixes && prefix != "" && !known[prefix]:
+			known[prefix] = true
+			reason = "new prefix created"
+		case tr.minSize > 0 && event.Size >= tr.minSize:
+			reason = fmt.Sprintf("large upload completed: %s", event.Key)
+		default:
+			continue
+		}
+		if k := [2]string{event.Bucket, prefix}; !seen[k] {
+			seen[k] = true
+			triggers = append(triggers, ProfileTrigger{Bucket: event.Bucket, Prefix: prefix, Reason: reason})
+		}
+	}
+	return triggers
+}
@@ -0,0 +1,183 @@
+package profiler
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCertURL = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+
+// testSNS returns a verifier that trusts a generated signing certificate at
+// testCertURL, and a function signing SNS messages with its key
+func testSNS(t *testing.T) (*SNSVerifier, func(snsMessage) []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewSNSVerifier(http.DefaultClient)
+	v.certs[testCertURL] = cert
+
+	sign := func(m snsMessage) []byte {
+		m.SignatureVersion = "2"
+		m.SigningCertURL = testCertURL
+		digest := sha256.Sum256([]byte(m.stringToSign()))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Signature = base64.StdEncoding.EncodeToString(signature)
+		body, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+	return v, sign
+}
+
+func TestParseObjectEvents(t *testing.T) {
+	v, sign := testSNS(t)
+	s3Event := `{"Records":[
+		{"eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"lake"},"object":{"key":"events/dt%3D2024-01-05/a+b.parquet","size":42}}},
+		{"eventName":"ObjectRemoved:Delete","s3":{"bucket":{"name":"lake"},"object":{"key":"old.csv","size":1}}}]}`
+	subject := "s3 event"
+
+	tests := []struct {
+		name      string
+		body      []byte
+		want      []ObjectEvent
+		subscribe string
+		wantErr   string
+	}{
+		{
+			name: "s3 notification",
+			body: []byte(s3Event),
+			want: []ObjectEvent{{Bucket: "lake", Key: "events/dt=2024-01-05/a b.parquet", Size: 42}},
+		},
+		{
+			name: "eventbridge",
+			body: []byte(`{"source":"aws.s3","detail-type":"Object Created","detail":{"bucket":{"name":"lake"},"object":{"key":"raw/x.json","size":7}}}`),
+			want: []ObjectEvent{{Bucket: "lake", Key: "raw/x.json", Size: 7}},
+		},
+		{
+			name: "other eventbridge event",
+			body: []byte(`{"source":"aws.s3","detail-type":"Object Deleted","detail":{"bucket":{"name":"lake"}}}`),
+		},
+		{
+			name: "signed sns notification",
+			body: sign(snsMessage{Type: "Notification", MessageID: "1", TopicArn: "arn:aws:sns:us-east-1:123456789012:lake", Subject: &subject, Message: s3Event, Timestamp: "2024-01-05T00:00:00Z"}),
+			want: []ObjectEvent{{Bucket: "lake", Key: "events/dt=2024-01-05/a b.parquet", Size: 42}},
+		},
+		{
+			name:      "signed subscription confirmation",
+			body:      sign(snsMessage{Type: "SubscriptionConfirmation", MessageID: "2", TopicArn: "arn:aws:sns:us-east-1:123456789012:lake", Message: "confirm", Timestamp: "2024-01-05T00:00:00Z", Token: "t", SubscribeURL: "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=t"}),
+			subscribe: "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=t",
+		},
+		{
+			name:    "subscribe url outside aws",
+			body:    sign(snsMessage{Type: "SubscriptionConfirmation", MessageID: "3", Token: "t", SubscribeURL: "https://example.com/confirm"}),
+			wantErr: "invalid SNS subscribe URL",
+		},
+		{
+			name: "tampered sns notification",
+			body: func() []byte {
+				body := sign(snsMessage{Type: "Notification", MessageID: "4", Message: `{"Records":[]}`})
+				return []byte(strings.Replace(string(body), `"MessageId":"4"`, `"MessageId":"5"`, 1))
+			}(),
+			wantErr: "invalid SNS signature",
+		},
+		{
+			name:    "unsigned sns notification",
+			body:    []byte(`{"Type":"Notification","Message":"{}"}`),
+			wantErr: "unsupported SNS signature version",
+		},
+		{
+			name:    "certificate outside sns",
+			body:    []byte(`{"Type":"Notification","Message":"{}","SignatureVersion":"1","Signature":"","SigningCertURL":"https://example.com/cert.pem"}`),
+			wantErr: "invalid SNS signing certificate URL",
+		},
+		{
+			name:    "not json",
+			body:    []byte(`<xml/>`),
+			wantErr: "invalid event",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, subscribe, err := ParseObjectEvents(context.Background(), tt.body, v)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(events, tt.want) {
+				t.Errorf("events = %+v, want %+v", events, tt.want)
+			}
+			if subscribe != tt.subscribe {
+				t.Errorf("subscribe URL = %q, want %q", subscribe, tt.subscribe)
+			}
+		})
+	}
+}
+
+func TestSNSStringToSign(t *testing.T) {
+	subject := "hi"
+	tests := []struct {
+		name string
+		m    snsMessage
+		want string
+	}{
+		{
+			name: "notification",
+			m:    snsMessage{Type: "Notification", MessageID: "id", TopicArn: "arn", Message: "m", Timestamp: "ts", Token: "ignored", SubscribeURL: "ignored"},
+			want: "Message\nm\nMessageId\nid\nTimestamp\nts\nTopicArn\narn\nType\nNotification\n",
+		},
+		{
+			name: "notification with subject",
+			m:    snsMessage{Type: "Notification", MessageID: "id", TopicArn: "arn", Subject: &subject, Message: "m", Timestamp: "ts"},
+			want: "Message\nm\nMessageId\nid\nSubject\nhi\nTimestamp\nts\nTopicArn\narn\nType\nNotification\n",
+		},
+		{
+			name: "subscription confirmation",
+			m:    snsMessage{Type: "SubscriptionConfirmation", MessageID: "id", TopicArn: "arn", Subject: &subject, Message: "m", Timestamp: "ts", Token: "tok", SubscribeURL: "url"},
+			want: "Message\nm\nMessageId\nid\nSubscribeURL\nurl\nTimestamp\nts\nToken\ntok\nTopicArn\narn\nType\nSubscriptionConfirmation\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.m.stringToSign(); got != tt.want {
+			t.Errorf("%s: string to sign =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// VersionAnalyzer handles analysis of object versions in versioned buckets
+type VersionAnalyzer struct {
+	s3Client *s3.Client
+	limit    int64
+	prices   storagePrices
+	progress ProgressReporter
+}
+
+// NewVersionAnalyzer creates a new version analyzer
+func NewVersionAnalyzer(s3Client *s3.Client, limit int64) *VersionAnalyzer {
+	return &VersionAnalyzer{
+		s3Client: s3Client,
+		limit:    limit,
+		progress: defaultProgress(),
+	}
+}
+
+// AnalyzeVersions reports the non-current versions and delete markers of a
+// bucket with their storage cost, and the data that is only held by
+// non-current versions of keys whose latest version is a delete marker,
+// i.e. data that looks deleted but is still billed. It returns nil if
+// versioning was never enabled.
+func (va *VersionAnalyzer) AnalyzeVersions(ctx context.Context, bucketName string) (*types.VersionSummary, error) {
+	versioning, err := va.s3Client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket versioning: %w", err)
+	}
+	if versioning.Status == "" {
+		return nil, nil
+	}
+
+	summary := &types.VersionSummary{
+		Status:            string(versioning.Status),
+		NoncurrentClasses: make(map[string]types.StorageClassStats),
+	}
+
+	// Non-current bytes per key, and keys whose latest version is a delete marker
+	noncurrent := make(map[string]*types.PrefixVersionStats)
+	deleted := make(map[string]bool)
+
+	var keyMarker, versionIDMarker *string
+	processedCount := int64(0)
+
+	for {
+		if va.limit > 0 && processedCount >= va.limit {
+			va.progress.Printf("Reached limit of %d versions\n", va.limit)
+			summary.Truncated = true
+			break
+		}
+
+		result, err := va.s3Client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+			Bucket:          aws.String(bucketName),
+			KeyMarker:       keyMarker,
+			VersionIdMarker: versionIDMarker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list object versions: %w", err)
+		}
+
+		for _, version := range result.Versions {
+			processedCount++
+			if aws.ToBool(version.IsLatest) {
+				continue
+			}
+
+			key := aws.ToString(version.Key)
+			stats, exists := noncurrent[key]
+			if !exists {
+				stats = &types.PrefixVersionStats{Prefix: key}
+				noncurrent[key] = stats
+			}
+			size := aws.ToInt64(version.Size)
+			stats.Versions++
+			stats.Size += size
+
+			class := string(version.StorageClass)
+			if class == "" {
+				class = "STANDARD"
+			}
+			classStats := summary.NoncurrentClasses[class]
+			classStats.Count++
+			classStats.Size += size
+			classStats.Billable += billableBytes(class, size)
+			summary.NoncurrentClasses[class] = classStats
+			summary.NoncurrentVersions++
+			summary.NoncurrentSize += size
+		}
+
+		for _, marker := range result.DeleteMarkers {
+			processedCount++
+			summary.DeleteMarkers++
+			summary.DeleteMarkerBytes += int64(len(aws.ToString(marker.Key)))
+			if aws.ToBool(marker.IsLatest) {
+				deleted[aws.ToString(marker.Key)] = true
+				summary.CurrentDeleteMarkers++
+			}
+		}
+
+		if !aws.ToBool(result.IsTruncated) {
+			break
+		}
+
+		keyMarker = result.NextKeyMarker
+		versionIDMarker = result.NextVersionIdMarker
+	}
+
+	for _, class := range slices.Sorted(maps.Keys(summary.NoncurrentClasses)) {
+		stats := summary.NoncurrentClasses[class]
+		stats.Cost = va.prices.cost(class, stats.Size)
+		summary.NoncurrentClasses[class] = stats
+		summary.NoncurrentCost += stats.Cost
+	}
+	summary.NoncurrentPrefixes = aggregateVersionPrefixes(noncurrent, func(string) bool { return true })
+
+	summary.DeletedPrefixes = aggregateVersionPrefixes(noncurrent, func(key string) bool { return deleted[key] })
+	for _, p := range summary.DeletedPrefixes {
+		summary.DeletedKeys += p.Keys
+		summary.DeletedVersions += p.Versions
+		summary.DeletedSize += p.Size
+	}
+
+	return summary, nil
+}
+
+// aggregateVersionPrefixes sums the non-current versions of the keys
+// selected by include by top-level prefix, largest first
+func aggregateVersionPrefixes(noncurrent map[string]*types.PrefixVersionStats, include func(key string) bool) []types.PrefixVersionStats {
+	prefixes := make(map[string]*types.PrefixVersionStats)
+	for key, stats := range noncurrent {
+		if !include(key) {
+			continue
+		}
+
+		prefix := topLevelPrefix(key)
+		agg, exists := prefixes[prefix]
+		if !exists {
+			agg = &types.PrefixVersionStats{Prefix: prefix}
+			prefixes[prefix] = agg
+		}
+		agg.Keys++
+		agg.Versions += stats.Versions
+		agg.Size += stats.Size
+	}
+
+	result := make([]types.PrefixVersionStats, 0, len(prefixes))
+	for _, p := range prefixes {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Prefix < result[j].Prefix
+	})
+	return result
+}
+
+// topLevelPrefix returns the first path segment of a key including the
+// trailing slash, or "/" for keys at the bucket root
+func topLevelPrefix(key string) string {
+	if i := strings.Index(key, "/"); i >= 0 {
+		return key[:i+1]
+	}
+	return "/"
+}
@@ -0,0 +1,325 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// Sources of the GET rate of a prefix in the what-if simulation
+const (
+	accessRateFlag    = "flag"
+	accessRateDefault = "default"
+	accessRateLogs    = "logs"
+)
+
+// defaultAccessRate is the --access-rate prefix that applies to every
+// top-level prefix without a rate of its own
+const defaultAccessRate = "*"
+
+// accessPricing is the approximate US East price of reading objects of a
+// storage class. Archive classes are read by restoring a copy first; their
+// request and retrieval prices are those of the Standard restore tier.
+type accessPricing struct {
+	getPerThousand float64
+	retrievalPerGB float64
+	// minObjectSize is the size each smaller object is billed as
+	minObjectSize int64
+	archive       bool
+}
+
+// whatIfClasses are the storage classes the simulation prices, in report
+// order
+var whatIfClasses = []string{
+	"STANDARD",
+	"INTELLIGENT_TIERING",
+	"STANDARD_IA",
+	"ONEZONE_IA",
+	"GLACIER_IR",
+	"GLACIER",
+	"DEEP_ARCHIVE",
+}
+
+var accessPrices = map[string]accessPricing{
+	"STANDARD":            {getPerThousand: 0.0004},
+	"INTELLIGENT_TIERING": {getPerThousand: 0.0004},
+	"STANDARD_IA":         {getPerThousand: 0.001, retrievalPerGB: 0.01, minObjectSize: 128 << 10},
+	"ONEZONE_IA":          {getPerThousand: 0.001, retrievalPerGB: 0.01, minObjectSize: 128 << 10},
+	"GLACIER_IR":          {getPerThousand: 0.01, retrievalPerGB: 0.03, minObjectSize: 128 << 10},
+	"GLACIER":             {getPerThousand: 0.0504, retrievalPerGB: 0.01, archive: true},
+	"DEEP_ARCHIVE":        {getPerThousand: 0.1004, retrievalPerGB: 0.02, archive: true},
+}
+
+// whatIfRange bounds, as a factor of the assumed rate, how far the
+// sensitivity search looks in each direction
+const whatIfRange = 1024
+
+// AccessSimulator prices each top-level prefix under every storage class
+// for assumed monthly GET rates, given per prefix or derived from the
+// bucket's server access logs
+type AccessSimulator struct {
+	s3Client *s3.Client
+	rates    map[string]float64
+	logDays  int
+	workers  int
+	archive  bool
+	prices   storagePrices
+}
+
+// NewAccessSimulator creates a simulator for the given monthly GET rates
+// per top-level prefix ("*" sets the rate of the other prefixes) and, when
+// logDays is positive, the rates found in the last logDays days of server
+// access logs. Server access logs are read with up to workers concurrent
+// requests. Archive classes are only recommended with archive.
+func NewAccessSimulator(s3Client *s3.Client, rates map[string]float64, logDays, workers int, archive bool) (*AccessSimulator, error) {
+	normalized := make(map[string]float64, len(rates))
+	for prefix, rate := range rates {
+		if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+			return nil, fmt.Errorf("invalid access rate %v for %q", rate, prefix)
+		}
+		prefix = strings.TrimSpace(prefix)
+		if prefix != defaultAccessRate && prefix != "/" {
+			prefix = strings.Trim(prefix, "/")
+			if prefix == "" || strings.Contains(prefix, "/") {
+				return nil, fmt.Errorf("invalid access rate prefix %q (expected a top-level prefix, / for the bucket root or *)", prefix)
+			}
+			prefix += "/"
+		}
+		normalized[prefix] = rate
+	}
+	if logDays < 0 {
+		return nil, fmt.Errorf("access log days must not be negative")
+	}
+	return &AccessSimulator{
+		s3Client: s3Client,
+		rates:    normalized,
+		logDays:  logDays,
+		workers:  max(workers, 1),
+		archive:  archive,
+	}, nil
+}
+
+// Enabled reports whether a what-if simulation was requested
+func (as *AccessSimulator) Enabled() bool {
+	return len(as.rates) > 0 || as.logDays > 0
+}
+
+// prefixAccess aggregates what the cost model needs of one prefix
+type prefixAccess struct {
+	prices  storagePrices
+	objects int64
+	size    int64
+	// classObjects counts the objects per current storage class
+	classObjects map[string]int64
+	currentStore float64
+	// billedSmall is the size with each object under 128 KB billed as
+	// 128 KB
+	billedSmall int64
+	// Intelligent-Tiering: monitored objects, and their bytes by age since
+	// the last write
+	monitored      int64
+	infrequentAged int64
+	archiveAged    int64
+}
+
+// Simulate prices each top-level prefix with an access rate. With server
+// access logs every prefix is priced; prefixes without logged GETs are
+// assumed unread, and --access-rate values take precedence. Reading the
+// logs may fail, in which case the error is returned alongside a
+// simulation of the configured rates.
+func (as *AccessSimulator) Simulate(ctx context.Context, bucketName, region string, objects []types.ObjectMetadata) (*types.AccessSimulation, error) {
+	now := time.Now()
+	prefixes := make(map[string]*prefixAccess)
+	for _, obj := range objects {
+		prefix := topLevelPrefix(obj.Key)
+		p, exists := prefixes[prefix]
+		if !exists {
+			p = &prefixAccess{prices: as.prices, classObjects: make(map[string]int64)}
+			prefixes[prefix] = p
+		}
+		p.objects++
+		p.size += obj.Size
+		p.classObjects[obj.StorageClass]++
+		p.currentStore += as.prices.cost(obj.StorageClass, obj.Size)
+		p.billedSmall += max(obj.Size, 128<<10)
+		if obj.Size < tieringMinObjectSize {
+			continue
+		}
+		p.monitored++
+		switch age := now.Sub(obj.LastModified); {
+		case age >= tieringArchiveAfter:
+			p.archiveAged += obj.Size
+		case age >= tieringInfrequentAfter:
+			p.infrequentAged += obj.Size
+		}
+	}
+
+	simulation := &types.AccessSimulation{Archive: as.archive}
+	var logged map[string]accessLogCount
+	var logErr error
+	if as.logDays > 0 {
+		var requests int64
+		logged, requests, logErr = as.readAccessLogs(ctx, bucketName, region, now)
+		if logErr == nil {
+			simulation.LogDays = as.logDays
+			simulation.LogRequests = requests
+		}
+	}
+
+	for prefix, p := range prefixes {
+		gets, source := 0.0, ""
+		var bytesRead float64
+		if rate, ok := as.rates[prefix]; ok {
+			gets, source = rate, accessRateFlag
+		} else if logged != nil {
+			count := logged[prefix]
+			gets = float64(count.gets) * 30 / float64(as.logDays)
+			bytesRead = float64(count.bytes) * 30 / float64(as.logDays)
+			source = accessRateLogs
+		} else if rate, ok := as.rates[defaultAccessRate]; ok {
+			gets, source = rate, accessRateDefault
+		} else {
+			continue
+		}
+		// Without logs a GET reads an average object
+		bytesPerGet := float64(p.size) / float64(p.objects)
+		if source == accessRateLogs && gets > 0 {
+			bytesPerGet = bytesRead / gets
+		}
+
+		estimate := types.PrefixAccessCost{
+			Prefix:      prefix,
+			ObjectCount: p.objects,
+			Size:        p.size,
+			Gets:        gets,
+			Source:      source,
+			CurrentCost: p.currentStore + p.currentRequests(gets, bytesPerGet),
+		}
+		for _, class := range whatIfClasses {
+			estimate.Costs = append(estimate.Costs, p.classCost(class, gets, bytesPerGet))
+		}
+		estimate.Recommended = as.cheapest(p, gets, bytesPerGet)
+		for _, c := range estimate.Costs {
+			if c.StorageClass == estimate.Recommended {
+				estimate.Savings = estimate.CurrentCost - c.Total
+			}
+		}
+		estimate.MinGets, estimate.MaxGets = as.sensitivity(p, estimate.Recommended, gets, bytesPerGet)
+		simulation.Prefixes = append(simulation.Prefixes, estimate)
+	}
+
+	sort.Slice(simulation.Prefixes, func(i, j int) bool {
+		if simulation.Prefixes[i].Savings != simulation.Prefixes[j].Savings {
+			return simulation.Prefixes[i].Savings > simulation.Prefixes[j].Savings
+		}
+		return simulation.Prefixes[i].Prefix < simulation.Prefixes[j].Prefix
+	})
+	return simulation, logErr
+}
+
+// currentRequests is the monthly cost of reading a prefix as it is stored,
+// with GETs spread over its objects evenly
+func (p *prefixAccess) currentRequests(gets, bytesPerGet float64) float64 {
+	var cost float64
+	for _, class := range slices.Sorted(maps.Keys(p.classObjects)) {
+		n := p.classObjects[class]
+		pricing, ok := accessPrices[class]
+		if !ok {
+			pricing = accessPrices["STANDARD"]
+		}
+		share := gets * float64(n) / float64(p.objects)
+		cost += share/1000*pricing.getPerThousand + share*bytesPerGet/(1<<30)*pricing.retrievalPerGB
+	}
+	return cost
+}
+
+// classCost is the monthly cost of a prefix moved wholesale to a class at
+// the given GET rate. Intelligent-Tiering keeps the share of objects read
+// within 30 days, assuming GETs hit objects at random, in the frequent
+// tier; the others move down by age since the last write.
+func (p *prefixAccess) classCost(class string, gets, bytesPerGet float64) types.StorageClassCost {
+	pricing := accessPrices[class]
+	cost := types.StorageClassCost{StorageClass: class}
+	switch {
+	case class == "INTELLIGENT_TIERING":
+		gb := func(size int64) float64 { return float64(size) / (1 << 30) }
+		read := 1 - math.Exp(-gets/float64(p.objects))
+		cost.Storage = gb(p.size-p.infrequentAged-p.archiveAged)*tieringFrequentPerGB +
+			gb(p.infrequentAged)*(read*tieringFrequentPerGB+(1-read)*tieringInfrequentPerGB) +
+			gb(p.archiveAged)*(read*tieringFrequentPerGB+(1-read)*tieringArchivePerGB) +
+			float64(p.monitored)/1000*tieringMonitoringPerThousand
+	case pricing.minObjectSize > 0:
+		cost.Storage = p.prices.cost(class, p.billedSmall)
+	case pricing.archive:
+		cost.Storage = p.prices.cost(class, p.size+p.objects*archiveIndexOverhead) +
+			p.prices.cost("STANDARD", p.objects*archiveMetadataOverhead)
+	default:
+		cost.Storage = p.prices.cost(class, p.size)
+	}
+	cost.Requests = gets / 1000 * pricing.getPerThousand
+	cost.Retrieval = gets * bytesPerGet / (1 << 30) * pricing.retrievalPerGB
+	cost.Total = cost.Storage + cost.Requests + cost.Retrieval
+	return cost
+}
+
+// cheapest returns the class with the lowest monthly cost at the given
+// GET rate, leaving out archive classes unless they were requested
+func (as *AccessSimulator) cheapest(p *prefixAccess, gets, bytesPerGet float64) string {
+	best, bestCost := "", math.Inf(1)
+	for _, class := range whatIfClasses {
+		if accessPrices[class].archive && !as.archive {
+			continue
+		}
+		if cost := p.classCost(class, gets, bytesPerGet).Total; cost < bestCost {
+			best, bestCost = class, cost
+		}
+	}
+	return best
+}
+
+// sensitivity returns the range of monthly GETs around the assumed rate
+// over which the recommended class stays the cheapest, searched in steps
+// of a factor of √2 up to whatIfRange either way. The upper bound is -1
+// when the class stays the cheapest over the whole search.
+func (as *AccessSimulator) sensitivity(p *prefixAccess, class string, gets, bytesPerGet float64) (float64, float64) {
+	// An unread prefix is searched from a GET per thousand objects up to
+	// a thousand GETs per object
+	base, steps := gets, 20
+	if base == 0 {
+		base, steps = float64(p.objects)/whatIfRange, 40
+	}
+
+	low := gets
+	if gets > 0 {
+		for step := 1; step <= 20; step++ {
+			rate := gets / math.Pow(math.Sqrt2, float64(step))
+			if as.cheapest(p, rate, bytesPerGet) != class {
+				break
+			}
+			low = rate
+		}
+		if low <= gets/whatIfRange && as.cheapest(p, 0, bytesPerGet) == class {
+			low = 0
+		}
+	}
+
+	high := -1.0
+	last := gets
+	for step := 1; step <= steps; step++ {
+		rate := base * math.Pow(math.Sqrt2, float64(step))
+		if as.cheapest(p, rate, bytesPerGet) != class {
+			high = last
+			break
+		}
+		last = rate
+	}
+	return low, high
+}
@@ -0,0 +1,214 @@
+// Package query runs SQL over object inventories exported with
+// --export-objects, loaded into an in-memory SQLite database
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/yourusername/s3-profiler/output"
+)
+
+// driverName is the SQLite driver with the functions queries can use
+const driverName = "sqlite3_s3profiler"
+
+// schema is the table every export is loaded into. prefix is the key's
+// parent prefix ("/" for keys at the root) and extension the lower-case
+// file extension without the dot ("" when there is none).
+const schema = `CREATE TABLE objects (
+	bucket        TEXT NOT NULL,
+	key           TEXT NOT NULL,
+	prefix        TEXT NOT NULL,
+	extension     TEXT NOT NULL,
+	size          INTEGER NOT NULL,
+	last_modified TEXT NOT NULL,
+	storage_class TEXT NOT NULL,
+	etag          TEXT NOT NULL
+)`
+
+var registerOnce sync.Once
+
+// register adds the SQLite driver with a regexp function, so queries can
+// use `key REGEXP '...'`
+func register() {
+	registerOnce.Do(func() {
+		sql.Register(driverName, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				return conn.RegisterFunc("regexp", matchRegexp, true)
+			},
+		})
+	})
+}
+
+var (
+	regexpMu    sync.Mutex
+	regexpCache = make(map[string]*regexp.Regexp)
+)
+
+// matchRegexp implements the REGEXP operator, compiling each pattern once
+func matchRegexp(pattern, value string) (bool, error) {
+	regexpMu.Lock()
+	re, ok := regexpCache[pattern]
+	if !ok {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			regexpMu.Unlock()
+			return false, err
+		}
+		regexpCache[pattern] = re
+	}
+	regexpMu.Unlock()
+	return re.MatchString(value), nil
+}
+
+// Engine holds loaded inventories in an in-memory database
+type Engine struct {
+	db *sql.DB
+}
+
+// Result is the output of a query. Values are int64, float64, string or
+// nil for NULL.
+type Result struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Open creates an empty in-memory database with the objects table
+func Open(ctx context.Context) (*Engine, error) {
+	register()
+	db, err := sql.Open(driverName, ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open query database: %w", err)
+	}
+	// Every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create objects table: %w", err)
+	}
+	return &Engine{db: db}, nil
+}
+
+// Load adds an export to the objects table and returns the number of
+// objects read
+func (e *Engine) Load(ctx context.Context, filename string) (n int64, err error) {
+	inventory, err := output.OpenObjectInventory(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer inventory.Close()
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO objects VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for {
+		obj, err := inventory.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+
+		// Exports carry the --timezone offset; UTC keeps SQLite's date
+		// functions and string comparisons consistent
+		if _, err := stmt.ExecContext(ctx,
+			inventory.Bucket,
+			obj.Key,
+			parentPrefix(obj.Key),
+			extension(obj.Key),
+			obj.Size,
+			obj.LastModified.UTC().Format(time.RFC3339),
+			obj.StorageClass,
+			obj.ETag,
+		); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, tx.Commit()
+}
+
+// parentPrefix returns the prefix a key is stored under, with its trailing
+// slash, or "/" for keys at the bucket root
+func parentPrefix(key string) string {
+	i := strings.LastIndexByte(strings.TrimSuffix(key, "/"), '/')
+	if i < 0 {
+		return "/"
+	}
+	return key[:i+1]
+}
+
+// extension returns a key's lower-case file extension without the dot
+func extension(key string) string {
+	if strings.HasSuffix(key, "/") {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(path.Ext(path.Base(key)), "."))
+}
+
+// Query runs a statement. The database is switched to query-only mode
+// first, so statements cannot change the loaded inventories.
+func (e *Engine) Query(ctx context.Context, statement string) (*Result, error) {
+	if _, err := e.db.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, err
+	}
+
+	rows, err := e.db.QueryContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Columns: columns}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+}
+
+// Close releases the database
+func (e *Engine) Close() error {
+	return e.db.Close()
+}
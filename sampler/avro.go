@@ -0,0 +1,116 @@
+package sampler
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var avroMagic = []byte("Obj\x01")
+
+// avroSampler reads the schema and codec from an Avro container header
+type avroSampler struct{}
+
+func (avroSampler) Format() string { return "avro" }
+
+func (avroSampler) Sample(ctx context.Context, obj Object) (*Result, error) {
+	head, err := readHead(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(head) < len(avroMagic) || string(head[:len(avroMagic)]) != string(avroMagic) {
+		return nil, errors.New("missing Avro magic bytes")
+	}
+
+	metadata, err := readAvroMetadata(head[len(avroMagic):])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Format: "avro", Attributes: map[string]string{"codec": "null"}}
+	if codec, ok := metadata["avro.codec"]; ok {
+		result.Attributes["codec"] = codec
+	}
+
+	var schema struct {
+		Type   string `json:"type"`
+		Name   string `json:"name"`
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(metadata["avro.schema"]), &schema); err == nil {
+		result.Attributes["schema"] = schema.Name
+		if schema.Type == "record" {
+			names := make([]string, 0, len(schema.Fields))
+			for _, field := range schema.Fields {
+				names = append(names, field.Name)
+			}
+			result.Attributes["fields"] = strconv.Itoa(len(names))
+			if len(names) > maxListedKeys {
+				names = append(names[:maxListedKeys], "...")
+			}
+			result.Attributes["field_names"] = strings.Join(names, ", ")
+		}
+	}
+
+	return result, nil
+}
+
+// readAvroMetadata decodes the header metadata map (map<bytes>)
+func readAvroMetadata(data []byte) (map[string]string, error) {
+	metadata := make(map[string]string)
+	pos := 0
+
+	readLong := func() (int64, error) {
+		v, n := binary.Varint(data[pos:])
+		if n <= 0 {
+			return 0, errors.New("truncated Avro header")
+		}
+		pos += n
+		return v, nil
+	}
+	readBytes := func() (string, error) {
+		n, err := readLong()
+		if err != nil {
+			return "", err
+		}
+		if n < 0 || n > int64(len(data)-pos) {
+			return "", errors.New("truncated Avro header")
+		}
+		s := string(data[pos : pos+int(n)])
+		pos += int(n)
+		return s, nil
+	}
+
+	for {
+		count, err := readLong()
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return metadata, nil
+		}
+		if count < 0 {
+			// A negative count is followed by the block size in bytes
+			count = -count
+			if _, err := readLong(); err != nil {
+				return nil, err
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			key, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			metadata[key] = value
+		}
+	}
+}
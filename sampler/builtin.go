@@ -0,0 +1,33 @@
+package sampler
+
+import "context"
+
+// headSize is how much of an object the built-in samplers read
+const headSize = 64 * 1024
+
+func init() {
+	Register(csvSampler{}, []string{".csv", ".tsv"}, []string{"text/csv", "text/tab-separated-values"})
+	Register(jsonSampler{}, []string{".json", ".jsonl", ".ndjson"}, []string{"application/json", "application/x-ndjson"})
+	Register(avroSampler{}, []string{".avro"}, []string{"application/avro"})
+	Register(orcSampler{}, []string{".orc"}, nil)
+	Register(gzipSampler{}, []string{".gz", ".gzip"}, []string{"application/gzip", "application/x-gzip"})
+}
+
+// readHead reads the beginning of an object
+func readHead(ctx context.Context, obj Object) ([]byte, error) {
+	return obj.Reader.ReadRange(ctx, 0, headSize)
+}
+
+// completeLines drops a trailing partial line when only part of the object
+// was read
+func completeLines(data []byte, obj Object) []byte {
+	if int64(len(data)) >= obj.Size {
+		return data
+	}
+	for i := len(data) - 1; i >= 0; i-- {
+		if data[i] == '\n' {
+			return data[:i+1]
+		}
+	}
+	return data
+}
@@ -0,0 +1,98 @@
+package sampler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// csvDelimiters are the candidate delimiters with their display names
+var csvDelimiters = []struct {
+	char rune
+	name string
+}{
+	{',', "comma"},
+	{'\t', "tab"},
+	{';', "semicolon"},
+	{'|', "pipe"},
+}
+
+// csvSampler detects the delimiter, column count and header of CSV files
+type csvSampler struct{}
+
+func (csvSampler) Format() string { return "csv" }
+
+func (csvSampler) Sample(ctx context.Context, obj Object) (*Result, error) {
+	head, err := readHead(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	head = completeLines(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), obj)
+
+	// Pick the delimiter that splits the first line into the most fields
+	firstLine, _, _ := bytes.Cut(head, []byte("\n"))
+	delimiter, best := csvDelimiters[0], 0
+	for _, d := range csvDelimiters {
+		if n := bytes.Count(firstLine, []byte(string(d.char))); n > best {
+			delimiter, best = d, n
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(head))
+	reader.Comma = delimiter.char
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil && len(records) == 0 {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no CSV records")
+	}
+
+	consistent := true
+	for _, record := range records {
+		if len(record) != len(records[0]) {
+			consistent = false
+			break
+		}
+	}
+
+	result := &Result{
+		Format: "csv",
+		Attributes: map[string]string{
+			"delimiter":          delimiter.name,
+			"columns":            strconv.Itoa(len(records[0])),
+			"rows_in_sample":     strconv.Itoa(len(records)),
+			"consistent_columns": strconv.FormatBool(consistent),
+		},
+	}
+	if len(records) > 1 && looksLikeHeader(records[0], records[1]) {
+		result.Attributes["header"] = strings.Join(records[0], ", ")
+	}
+
+	return result, nil
+}
+
+// looksLikeHeader reports whether the first row has no numbers where the
+// second row has some
+func looksLikeHeader(first, second []string) bool {
+	numeric := func(s string) bool {
+		_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return err == nil
+	}
+
+	secondHasNumbers := false
+	for i, field := range first {
+		if numeric(field) {
+			return false
+		}
+		if i < len(second) && numeric(second[i]) {
+			secondHasNumbers = true
+		}
+	}
+	return secondHasNumbers
+}
@@ -0,0 +1,76 @@
+package sampler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// gzipSampler reads the gzip header and hands the decompressed head to the
+// sampler of the inner format, e.g. ".csv" for "data.csv.gz"
+type gzipSampler struct{}
+
+func (gzipSampler) Format() string { return "gzip" }
+
+func (gzipSampler) Sample(ctx context.Context, obj Object) (*Result, error) {
+	head, err := readHead(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return nil, err
+	}
+	reader.Multistream(false)
+
+	// Only part of the stream may have been read; keep what decompressed
+	decompressed, err := io.ReadAll(io.LimitReader(reader, headSize))
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	result := &Result{
+		Format: "gzip",
+		Attributes: map[string]string{
+			"decompressed_sample_bytes": strconv.Itoa(len(decompressed)),
+		},
+	}
+	if reader.Name != "" {
+		result.Attributes["original_name"] = reader.Name
+	}
+
+	innerKey := reader.Name
+	if innerKey == "" {
+		innerKey = strings.TrimSuffix(strings.TrimSuffix(obj.Key, ".gz"), ".gzip")
+	}
+	inner := Lookup(innerKey, "")
+	if inner == nil || inner.Format() == "gzip" {
+		return result, nil
+	}
+
+	// Report a larger size when the stream was cut off so the inner sampler
+	// treats the data as partial
+	size := int64(len(decompressed))
+	if err == io.ErrUnexpectedEOF || size == headSize {
+		size++
+	}
+
+	innerResult, err := inner.Sample(ctx, Object{
+		Key:    innerKey,
+		Size:   size,
+		Reader: BytesReader(decompressed),
+	})
+	if err != nil {
+		return result, nil
+	}
+	result.Format = "gzip+" + innerResult.Format
+	for k, v := range innerResult.Attributes {
+		result.Attributes[k] = v
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package sampler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxListedKeys limits the top-level keys reported per object
+const maxListedKeys = 20
+
+// jsonSampler distinguishes JSON Lines from JSON documents and reports the
+// top-level keys of the first record
+type jsonSampler struct{}
+
+func (jsonSampler) Format() string { return "json" }
+
+func (jsonSampler) Sample(ctx context.Context, obj Object) (*Result, error) {
+	head, err := readHead(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	head = bytes.TrimSpace(bytes.TrimPrefix(completeLines(head, obj), []byte("\xef\xbb\xbf")))
+	if len(head) == 0 {
+		return nil, errors.New("empty JSON object")
+	}
+
+	result := &Result{Format: "json", Attributes: make(map[string]string)}
+
+	switch head[0] {
+	case '[':
+		result.Attributes["layout"] = "array"
+	case '{':
+		var records int
+		var first map[string]json.RawMessage
+		for _, line := range bytes.Split(head, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			var record map[string]json.RawMessage
+			if json.Unmarshal(line, &record) != nil {
+				records = 0
+				break
+			}
+			if first == nil {
+				first = record
+			}
+			records++
+		}
+
+		if records > 0 {
+			result.Attributes["layout"] = "lines"
+			result.Attributes["records_in_sample"] = strconv.Itoa(records)
+		} else {
+			result.Attributes["layout"] = "document"
+			// The document may be truncated; only decode its first object
+			json.NewDecoder(bytes.NewReader(head)).Decode(&first)
+		}
+		if first != nil {
+			result.Attributes["keys"] = joinKeys(first)
+		}
+	default:
+		return nil, errors.New("not a JSON object or array")
+	}
+
+	return result, nil
+}
+
+// joinKeys lists the sorted keys of a record
+func joinKeys(record map[string]json.RawMessage) string {
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > maxListedKeys {
+		keys = append(keys[:maxListedKeys], "...")
+	}
+	return strings.Join(keys, ", ")
+}
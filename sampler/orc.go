@@ -0,0 +1,107 @@
+package sampler
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// orcTailSize is enough for the postscript, which is at most 255 bytes
+const orcTailSize = 256
+
+var orcCompression = map[uint64]string{
+	0: "none",
+	1: "zlib",
+	2: "snappy",
+	3: "lzo",
+	4: "lz4",
+	5: "zstd",
+}
+
+// orcSampler reads the compression and version from an ORC postscript
+type orcSampler struct{}
+
+func (orcSampler) Format() string { return "orc" }
+
+func (orcSampler) Sample(ctx context.Context, obj Object) (*Result, error) {
+	tail, err := obj.Reader.ReadRange(ctx, -orcTailSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(tail) < 2 {
+		return nil, errors.New("object too small to be an ORC file")
+	}
+
+	// The last byte is the postscript length
+	psLen := int(tail[len(tail)-1])
+	if psLen+1 > len(tail) {
+		return nil, errors.New("invalid ORC postscript length")
+	}
+	postscript := tail[len(tail)-1-psLen : len(tail)-1]
+
+	result := &Result{Format: "orc", Attributes: make(map[string]string)}
+	var version []string
+	magic := false
+
+	// The postscript is a protobuf message; walk its fields
+	for pos := 0; pos < len(postscript); {
+		tag, n := binary.Uvarint(postscript[pos:])
+		if n <= 0 {
+			return nil, errors.New("invalid ORC postscript")
+		}
+		pos += n
+		field, wireType := tag>>3, tag&7
+
+		switch wireType {
+		case 0:
+			v, n := binary.Uvarint(postscript[pos:])
+			if n <= 0 {
+				return nil, errors.New("invalid ORC postscript")
+			}
+			pos += n
+			switch field {
+			case 2:
+				result.Attributes["compression"] = orcCompression[v]
+			case 4:
+				version = append(version, strconv.FormatUint(v, 10))
+			case 6:
+				result.Attributes["writer_version"] = strconv.FormatUint(v, 10)
+			}
+		case 2:
+			length, n := binary.Uvarint(postscript[pos:])
+			if n <= 0 || length > uint64(len(postscript)-pos-n) {
+				return nil, errors.New("invalid ORC postscript")
+			}
+			pos += n
+			value := postscript[pos : pos+int(length)]
+			pos += int(length)
+			switch field {
+			case 4:
+				// Packed version numbers
+				for len(value) > 0 {
+					v, n := binary.Uvarint(value)
+					if n <= 0 {
+						break
+					}
+					version = append(version, strconv.FormatUint(v, 10))
+					value = value[n:]
+				}
+			case 8000:
+				magic = string(value) == "ORC"
+			}
+		default:
+			return nil, errors.New("unsupported ORC postscript field")
+		}
+	}
+
+	if !magic {
+		return nil, errors.New("missing ORC magic bytes")
+	}
+	if len(version) > 0 {
+		result.Attributes["version"] = strings.Join(version, ".")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+// Package sampler provides content analyzers that inspect sampled objects of
+// a specific format. Samplers register by file extension and MIME type, so
+// new formats can be added without changing the profiler:
+//
+//	func init() {
+//		sampler.Register(mySampler{}, []string{".xyz"}, []string{"application/x-xyz"})
+//	}
+package sampler
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RangeReader reads byte ranges of an object
+type RangeReader interface {
+	// ReadRange returns up to length bytes starting at offset. A negative
+	// offset reads the last -offset bytes and ignores length.
+	ReadRange(ctx context.Context, offset, length int64) ([]byte, error)
+}
+
+// Object is a sampled object handed to a Sampler
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	Reader      RangeReader
+}
+
+// Result holds what a sampler found out about one object
+type Result struct {
+	Format     string
+	Attributes map[string]string
+}
+
+// Sampler analyzes the content of objects in one format
+type Sampler interface {
+	// Format names the format, e.g. "csv"
+	Format() string
+	// Sample inspects an object, reading as little of it as possible
+	Sample(ctx context.Context, obj Object) (*Result, error)
+}
+
+// Registry maps file extensions and MIME types to samplers
+type Registry struct {
+	mu          sync.RWMutex
+	byExtension map[string]Sampler
+	byMIMEType  map[string]Sampler
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byExtension: make(map[string]Sampler),
+		byMIMEType:  make(map[string]Sampler),
+	}
+}
+
+// Register adds a sampler for the given extensions (with leading dot) and
+// MIME types, replacing any sampler previously registered for them
+func (r *Registry) Register(s Sampler, extensions, mimeTypes []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ext := range extensions {
+		r.byExtension[strings.ToLower(ext)] = s
+	}
+	for _, mimeType := range mimeTypes {
+		r.byMIMEType[strings.ToLower(mimeType)] = s
+	}
+}
+
+// Lookup returns the sampler for an object key or content type, preferring
+// the extension. It returns nil when no sampler handles the object.
+func (r *Registry) Lookup(key, contentType string) Sampler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.byExtension[strings.ToLower(path.Ext(key))]; ok {
+		return s
+	}
+
+	// Ignore parameters such as "; charset=utf-8"
+	mimeType, _, _ := strings.Cut(contentType, ";")
+	if s, ok := r.byMIMEType[strings.ToLower(strings.TrimSpace(mimeType))]; ok {
+		return s
+	}
+
+	return nil
+}
+
+// Formats lists the registered formats
+func (r *Registry) Formats() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, s := range r.byExtension {
+		seen[s.Format()] = true
+	}
+	for _, s := range r.byMIMEType {
+		seen[s.Format()] = true
+	}
+
+	formats := make([]string, 0, len(seen))
+	for format := range seen {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Default is the registry used by the profiler; the built-in samplers are
+// registered in it
+var Default = NewRegistry()
+
+// Register adds a sampler to the default registry
+func Register(s Sampler, extensions, mimeTypes []string) {
+	Default.Register(s, extensions, mimeTypes)
+}
+
+// Lookup finds a sampler in the default registry
+func Lookup(key, contentType string) Sampler {
+	return Default.Lookup(key, contentType)
+}
+
+// BytesReader is a RangeReader over data already in memory, useful for
+// samplers that unwrap other formats
+type BytesReader []byte
+
+// ReadRange implements RangeReader
+func (b BytesReader) ReadRange(_ context.Context, offset, length int64) ([]byte, error) {
+	size := int64(len(b))
+	if offset < 0 {
+		return b[max(0, size+offset):], nil
+	}
+	if offset >= size {
+		return nil, nil
+	}
+	return b[offset:min(size, offset+length)], nil
+}
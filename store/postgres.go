@@ -0,0 +1,356 @@
+// Package store keeps profiling results in PostgreSQL so a team can share
+// run history and compare runs made from different machines.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/yourusername/s3-profiler/types"
+)
+
+// migrationLock is the advisory lock key that serializes concurrent
+// migrations
+const migrationLock = 0x53335072
+
+// migrations are applied in order; entry i brings the schema to version
+// i+1. Append new migrations, never edit applied ones.
+var migrations = []string{
+	`CREATE TABLE runs (
+		id             BIGSERIAL PRIMARY KEY,
+		bucket         TEXT NOT NULL,
+		region         TEXT NOT NULL,
+		generated      TIMESTAMPTZ NOT NULL,
+		total_objects  BIGINT NOT NULL,
+		total_size     BIGINT NOT NULL,
+		estimated_cost DOUBLE PRECISION NOT NULL,
+		truncated      BOOLEAN NOT NULL,
+		estimate       JSONB
+	);
+	CREATE INDEX runs_bucket_generated ON runs (bucket, generated DESC);
+
+	CREATE TABLE run_storage_classes (
+		run_id        BIGINT NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+		storage_class TEXT NOT NULL,
+		objects       BIGINT NOT NULL,
+		size          BIGINT NOT NULL,
+		PRIMARY KEY (run_id, storage_class)
+	);
+
+	CREATE TABLE run_prefixes (
+		run_id  BIGINT NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+		prefix  TEXT NOT NULL,
+		objects BIGINT NOT NULL,
+		size    BIGINT NOT NULL,
+		written JSONB,
+		PRIMARY KEY (run_id, prefix)
+	);
+
+	CREATE TABLE run_partitions (
+		run_id  BIGINT NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+		prefix  TEXT NOT NULL,
+		objects BIGINT NOT NULL,
+		size    BIGINT NOT NULL,
+		PRIMARY KEY (run_id, prefix)
+	);`,
+
+	// Views for dashboards; see export-dashboard
+	`ALTER TABLE run_storage_classes ADD COLUMN cost DOUBLE PRECISION;
+	ALTER TABLE run_partitions ADD COLUMN date DATE, ADD COLUMN newest TIMESTAMPTZ;
+
+	CREATE VIEW bucket_growth AS
+	SELECT r.bucket, r.id AS run_id, r.generated, r.region,
+		r.total_objects, r.total_size, r.estimated_cost, r.truncated,
+		r.total_size - LAG(r.total_size) OVER w AS size_change,
+		r.total_objects - LAG(r.total_objects) OVER w AS object_change,
+		EXTRACT(EPOCH FROM r.generated - LAG(r.generated) OVER w) / 86400 AS days_since_previous
+	FROM runs r
+	WINDOW w AS (PARTITION BY r.bucket ORDER BY r.generated, r.id);
+	COMMENT ON VIEW bucket_growth IS 'One row per run with the change since the same bucket''s previous run';
+	COMMENT ON COLUMN bucket_growth.size_change IS 'Bytes added since the previous run (NULL for the first run)';
+	COMMENT ON COLUMN bucket_growth.object_change IS 'Objects added since the previous run (NULL for the first run)';
+	COMMENT ON COLUMN bucket_growth.truncated IS 'The run stopped at --limit, so totals cover only the listed objects';
+
+	CREATE VIEW cost_by_class AS
+	SELECT r.bucket, r.id AS run_id, r.generated, c.storage_class, c.objects, c.size, c.cost
+	FROM run_storage_classes c
+	JOIN runs r ON r.id = c.run_id;
+	COMMENT ON VIEW cost_by_class IS 'Objects, bytes and estimated monthly storage cost per storage class and run';
+	COMMENT ON COLUMN cost_by_class.cost IS 'Estimated monthly storage cost in USD (NULL for runs saved before cost was recorded)';
+
+	CREATE VIEW partition_freshness AS
+	SELECT r.bucket, r.id AS run_id, r.generated, p.prefix, p.date, p.newest, p.objects, p.size,
+		EXTRACT(EPOCH FROM r.generated - p.newest) / 3600 AS hours_since_write
+	FROM run_partitions p
+	JOIN runs r ON r.id = p.run_id
+	WHERE r.id = (SELECT l.id FROM runs l WHERE l.bucket = r.bucket ORDER BY l.generated DESC, l.id DESC LIMIT 1);
+	COMMENT ON VIEW partition_freshness IS 'Partitions of each bucket''s latest run with the time since their last write';
+	COMMENT ON COLUMN partition_freshness.date IS 'First day the partition covers (NULL for non-date partitions)';
+	COMMENT ON COLUMN partition_freshness.hours_since_write IS 'Hours between the partition''s latest write and the run';`,
+}
+
+// Store is a PostgreSQL results database
+type Store struct {
+	db *sql.DB
+}
+
+// Open connects to a PostgreSQL database (a postgres:// URL or key=value
+// connection string) and migrates its schema to the current version
+func Open(ctx context.Context, dsn string) (*Store, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to results database: %w", err)
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate applies the migrations the database has not seen yet
+func (s *Store) migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Another profiler starting at the same time waits here instead of
+	// applying the same migrations twice
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLock); err != nil {
+		return fmt.Errorf("failed to lock results database schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var version int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("results database schema version %d is newer than this s3-profiler supports (%d)", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			return fmt.Errorf("failed to apply results database migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, i+1); err != nil {
+			return fmt.Errorf("failed to record results database migration %d: %w", i+1, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveRun records a completed profile and returns its run id
+func (s *Store) SaveRun(ctx context.Context, summary *types.BucketSummary, snapshot *types.Snapshot) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// A nil interface, not a nil slice, is sent as NULL
+	var estimate any
+	if snapshot.Estimate != nil {
+		data, err := json.Marshal(snapshot.Estimate)
+		if err != nil {
+			return 0, err
+		}
+		estimate = string(data)
+	}
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `INSERT INTO runs
+		(bucket, region, generated, total_objects, total_size, estimated_cost, truncated, estimate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		snapshot.Bucket, summary.Region, snapshot.Generated, snapshot.TotalObjects, snapshot.TotalSize,
+		summary.EstimatedCost, summary.Truncated, estimate).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save run: %w", err)
+	}
+
+	for class, stats := range summary.StorageClasses {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO run_storage_classes (run_id, storage_class, objects, size, cost) VALUES ($1, $2, $3, $4, $5)`,
+			id, class, stats.Count, stats.Size, stats.Cost); err != nil {
+			return 0, fmt.Errorf("failed to save storage classes: %w", err)
+		}
+	}
+
+	// Prefixes and partitions can number in the tens of thousands, so they
+	// are loaded with COPY rather than row by row
+	prefixes := make([][]any, len(snapshot.Prefixes))
+	for i, prefix := range snapshot.Prefixes {
+		var written any
+		if len(prefix.Written) > 0 {
+			data, err := json.Marshal(prefix.Written)
+			if err != nil {
+				return 0, err
+			}
+			written = string(data)
+		}
+		prefixes[i] = []any{id, prefix.Prefix, prefix.ObjectCount, prefix.Size, written}
+	}
+	if err := copyRows(ctx, tx, "run_prefixes", []string{"run_id", "prefix", "objects", "size", "written"}, prefixes); err != nil {
+		return 0, fmt.Errorf("failed to save prefixes: %w", err)
+	}
+
+	partitions := make([][]any, len(snapshot.Partitions))
+	for i, partition := range snapshot.Partitions {
+		partitions[i] = []any{id, partition.Prefix, partition.ObjectCount, partition.Size, nullDate(partition.Date), nullTime(partition.Newest)}
+	}
+	if err := copyRows(ctx, tx, "run_partitions", []string{"run_id", "prefix", "objects", "size", "date", "newest"}, partitions); err != nil {
+		return 0, fmt.Errorf("failed to save partitions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to save run: %w", err)
+	}
+	return id, nil
+}
+
+// nullTime sends zero times as NULL
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// nullDate sends a day as YYYY-MM-DD, or NULL for the zero time
+func nullDate(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.DateOnly)
+}
+
+// copyRows bulk-loads rows into a table
+func copyRows(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
+
+// Runs lists a bucket's runs, newest first; limit 0 lists all of them
+func (s *Store) Runs(ctx context.Context, bucket string, limit int) ([]types.RunRecord, error) {
+	query := `SELECT id, bucket, region, generated, total_objects, total_size, estimated_cost, truncated
+		FROM runs WHERE bucket = $1 ORDER BY generated DESC, id DESC`
+	args := []any{bucket}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []types.RunRecord
+	for rows.Next() {
+		var run types.RunRecord
+		if err := rows.Scan(&run.ID, &run.Bucket, &run.Region, &run.Generated,
+			&run.TotalObjects, &run.TotalSize, &run.EstimatedCost, &run.Truncated); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// LoadRun reads a run back as a snapshot for comparison
+func (s *Store) LoadRun(ctx context.Context, id int64) (*types.Snapshot, error) {
+	snapshot := &types.Snapshot{}
+	var estimate []byte
+	err := s.db.QueryRowContext(ctx, `SELECT bucket, generated, total_objects, total_size, estimate FROM runs WHERE id = $1`, id).
+		Scan(&snapshot.Bucket, &snapshot.Generated, &snapshot.TotalObjects, &snapshot.TotalSize, &estimate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("run %d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run %d: %w", id, err)
+	}
+	if estimate != nil {
+		snapshot.Estimate = &types.ListingEstimate{}
+		if err := json.Unmarshal(estimate, snapshot.Estimate); err != nil {
+			return nil, fmt.Errorf("failed to parse estimate of run %d: %w", id, err)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT prefix, objects, size, written FROM run_prefixes WHERE run_id = $1 ORDER BY prefix`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prefixes of run %d: %w", id, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var prefix types.PrefixStats
+		var written []byte
+		if err := rows.Scan(&prefix.Prefix, &prefix.ObjectCount, &prefix.Size, &written); err != nil {
+			return nil, err
+		}
+		if written != nil {
+			if err := json.Unmarshal(written, &prefix.Written); err != nil {
+				return nil, fmt.Errorf("failed to parse write history of run %d: %w", id, err)
+			}
+		}
+		snapshot.Prefixes = append(snapshot.Prefixes, prefix)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	partitions, err := s.db.QueryContext(ctx, `SELECT prefix, objects, size, date, newest FROM run_partitions WHERE run_id = $1 ORDER BY prefix`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load partitions of run %d: %w", id, err)
+	}
+	defer partitions.Close()
+	for partitions.Next() {
+		var partition types.PrefixStats
+		var date, newest sql.NullTime
+		if err := partitions.Scan(&partition.Prefix, &partition.ObjectCount, &partition.Size, &date, &newest); err != nil {
+			return nil, err
+		}
+		partition.Date, partition.Newest = date.Time, newest.Time
+		snapshot.Partitions = append(snapshot.Partitions, partition)
+	}
+	return snapshot, partitions.Err()
+}
+
+// Close closes the database connection
+func (s *Store) Close() error {
+	return s.db.Close()
+}
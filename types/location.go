@@ -0,0 +1,188 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Location is a bucket, or a prefix in it, as named by a flag, a config
+// file, a finding or a report
+type Location struct {
+	// Bucket is the bucket name, or the ARN of the access point the
+	// location was named through; S3 requests take either
+	Bucket string
+	// Prefix is the key prefix, "" for the whole bucket
+	Prefix string
+	// AccessPoint is the access point name when Bucket is an access point
+	// ARN
+	AccessPoint string
+	// Partition, Region and Account are set from ARNs; bucket and object
+	// ARNs only carry the partition
+	Partition string
+	Region    string
+	Account   string
+}
+
+// NewLocation returns the location of a prefix in a bucket; "" and "/"
+// stand for the whole bucket, as in findings and prefix reports
+func NewLocation(bucket, prefix string) Location {
+	if prefix == "/" {
+		prefix = ""
+	}
+	return Location{Bucket: bucket, Prefix: prefix}
+}
+
+// ParseLocation parses an s3:// URI, a bucket, object or access point
+// ARN, or a bucket name optionally followed by /prefix:
+//
+//	s3://bucket/logs/
+//	arn:aws:s3:::bucket/logs/
+//	arn:aws:s3:us-east-1:123456789012:accesspoint/reports/object/logs/
+//	bucket/logs/
+//
+// Prefixes are kept as given, so logs and logs/ stay distinct.
+func ParseLocation(s string) (Location, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "arn:"):
+		return parseARN(s)
+	case strings.HasPrefix(s, "s3://"):
+		rest := strings.TrimPrefix(s, "s3://")
+		// The AWS CLI names access points as s3://<access point ARN>/<key>
+		if strings.HasPrefix(rest, "arn:") {
+			return parseARN(accessPointObjectARN(rest))
+		}
+		return parseBucketPrefix(rest, s)
+	}
+	return parseBucketPrefix(s, s)
+}
+
+// parseBucketPrefix parses bucket[/prefix]; s is the text being parsed,
+// for errors
+func parseBucketPrefix(path, s string) (Location, error) {
+	bucket, prefix, _ := strings.Cut(path, "/")
+	if err := ValidateBucketName(bucket); err != nil {
+		return Location{}, fmt.Errorf("invalid location %q: %w", s, err)
+	}
+	return Location{Bucket: bucket, Prefix: prefix}, nil
+}
+
+// accessPointObjectARN rewrites the <access point ARN>/<key> of an s3://
+// URI as the access point's object ARN
+func accessPointObjectARN(s string) string {
+	arn, key, ok := strings.Cut(s, "/")
+	// The access point name is itself after a slash: accesspoint/<name>
+	if !ok || !strings.HasSuffix(arn, ":accesspoint") {
+		return s
+	}
+	name, key, ok := strings.Cut(key, "/")
+	if !ok {
+		return s
+	}
+	return arn + "/" + name + "/object/" + key
+}
+
+// parseARN parses a bucket, object or access point ARN:
+// arn:<partition>:s3:<region>:<account>:<resource>
+func parseARN(s string) (Location, error) {
+	parts := strings.SplitN(s, ":", 6)
+	if len(parts) != 6 || parts[1] == "" {
+		return Location{}, fmt.Errorf("invalid ARN %q: expected arn:<partition>:s3:<region>:<account>:<resource>", s)
+	}
+	if parts[2] != "s3" {
+		return Location{}, fmt.Errorf("invalid ARN %q: %s resources are not S3 buckets or access points", s, parts[2])
+	}
+	loc := Location{Partition: parts[1], Region: parts[3], Account: parts[4]}
+	resource := parts[5]
+
+	if name, ok := strings.CutPrefix(resource, "accesspoint/"); ok {
+		if loc.Account == "" {
+			return Location{}, fmt.Errorf("invalid ARN %q: access point ARNs name their account", s)
+		}
+		name, object, hasObject := strings.Cut(name, "/")
+		if name == "" {
+			return Location{}, fmt.Errorf("invalid ARN %q: missing access point name", s)
+		}
+		if hasObject {
+			key, ok := strings.CutPrefix(object, "object/")
+			if !ok && object != "object" {
+				return Location{}, fmt.Errorf("invalid ARN %q: access point resources are accesspoint/<name>[/object/<prefix>]", s)
+			}
+			if ok {
+				loc.Prefix = key
+			}
+		}
+		loc.AccessPoint = name
+		loc.Bucket = strings.Join(parts[:5], ":") + ":accesspoint/" + name
+		return loc, nil
+	}
+
+	if loc.Region != "" || loc.Account != "" {
+		return Location{}, fmt.Errorf("invalid ARN %q: bucket ARNs have no region or account, e.g. arn:aws:s3:::bucket", s)
+	}
+	bucket, prefix, _ := strings.Cut(resource, "/")
+	if err := ValidateBucketName(bucket); err != nil {
+		return Location{}, fmt.Errorf("invalid ARN %q: %w", s, err)
+	}
+	loc.Bucket = bucket
+	loc.Prefix = prefix
+	return loc, nil
+}
+
+// ValidateBucketName checks that a bucket name could exist. Buckets made in
+// us-east-1 before March 2018 may have up to 255 letters, digits, periods,
+// hyphens and underscores, so names are held to those looser rules rather
+// than today's lowercase 3 to 63 characters.
+func ValidateBucketName(name string) error {
+	if name == "" {
+		return fmt.Errorf("missing bucket name")
+	}
+	if len(name) < 3 || len(name) > 255 {
+		return fmt.Errorf("bucket name %q must be 3 to 255 characters long", name)
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+		default:
+			return fmt.Errorf("bucket name %q cannot contain %q", name, r)
+		}
+	}
+	return nil
+}
+
+// IsAccessPoint reports whether the location was named through an access
+// point
+func (l Location) IsAccessPoint() bool {
+	return l.AccessPoint != ""
+}
+
+// URI returns the s3:// URI of the location, s3://bucket/ for the whole
+// bucket; access points are named by ARN, as the AWS CLI does
+func (l Location) URI() string {
+	return "s3://" + l.Bucket + "/" + l.Prefix
+}
+
+// String returns the s3:// URI of the location
+func (l Location) String() string {
+	return l.URI()
+}
+
+// ARN returns the bucket or object ARN of the location, or the access
+// point ARN with the prefix as its object; the partition defaults to aws
+func (l Location) ARN() string {
+	if l.IsAccessPoint() {
+		if l.Prefix == "" {
+			return l.Bucket
+		}
+		return l.Bucket + "/object/" + l.Prefix
+	}
+	partition := l.Partition
+	if partition == "" {
+		partition = "aws"
+	}
+	arn := "arn:" + partition + ":s3:::" + l.Bucket
+	if l.Prefix != "" {
+		arn += "/" + l.Prefix
+	}
+	return arn
+}
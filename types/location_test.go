@@ -0,0 +1,87 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	const apARN = "arn:aws:s3:us-east-1:123456789012:accesspoint/reports"
+	tests := []struct {
+		in      string
+		want    Location
+		wantErr string
+	}{
+		{in: "bucket", want: Location{Bucket: "bucket"}},
+		{in: " bucket/logs ", want: Location{Bucket: "bucket", Prefix: "logs"}},
+		{in: "bucket/logs/", want: Location{Bucket: "bucket", Prefix: "logs/"}},
+		{in: "s3://bucket", want: Location{Bucket: "bucket"}},
+		{in: "s3://bucket/", want: Location{Bucket: "bucket"}},
+		{in: "s3://bucket/a/b/", want: Location{Bucket: "bucket", Prefix: "a/b/"}},
+		{in: "s3://Legacy_Bucket/x", want: Location{Bucket: "Legacy_Bucket", Prefix: "x"}},
+		{in: "arn:aws:s3:::bucket", want: Location{Bucket: "bucket", Partition: "aws"}},
+		{in: "arn:aws-cn:s3:::bucket/logs/", want: Location{Bucket: "bucket", Prefix: "logs/", Partition: "aws-cn"}},
+		{in: apARN, want: Location{Bucket: apARN, AccessPoint: "reports", Partition: "aws", Region: "us-east-1", Account: "123456789012"}},
+		{in: apARN + "/object/logs/", want: Location{Bucket: apARN, Prefix: "logs/", AccessPoint: "reports", Partition: "aws", Region: "us-east-1", Account: "123456789012"}},
+		{in: apARN + "/object", want: Location{Bucket: apARN, AccessPoint: "reports", Partition: "aws", Region: "us-east-1", Account: "123456789012"}},
+		{in: "s3://" + apARN + "/logs/", want: Location{Bucket: apARN, Prefix: "logs/", AccessPoint: "reports", Partition: "aws", Region: "us-east-1", Account: "123456789012"}},
+
+		{in: "", wantErr: "missing bucket name"},
+		{in: "s3://", wantErr: "missing bucket name"},
+		{in: "ab", wantErr: "3 to 255 characters"},
+		{in: "s3://bucket name/", wantErr: "cannot contain"},
+		{in: "arn:aws:s3", wantErr: "expected arn:<partition>"},
+		{in: "arn::s3:::bucket", wantErr: "expected arn:<partition>"},
+		{in: "arn:aws:iam::123456789012:role/x", wantErr: "not S3 buckets"},
+		{in: "arn:aws:s3:us-east-1::bucket", wantErr: "no region or account"},
+		{in: "arn:aws:s3:us-east-1::accesspoint/reports", wantErr: "name their account"},
+		{in: "arn:aws:s3:us-east-1:123456789012:accesspoint/", wantErr: "missing access point name"},
+		{in: apARN + "/logs/", wantErr: "accesspoint/<name>[/object/<prefix>]"},
+	}
+	for _, tt := range tests {
+		got, err := ParseLocation(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseLocation(%q) err = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseLocation(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLocation(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLocationNames(t *testing.T) {
+	const apARN = "arn:aws:s3:us-east-1:123456789012:accesspoint/reports"
+	tests := []struct {
+		loc     Location
+		wantURI string
+		wantARN string
+	}{
+		{NewLocation("bucket", "/"), "s3://bucket/", "arn:aws:s3:::bucket"},
+		{NewLocation("bucket", "logs/"), "s3://bucket/logs/", "arn:aws:s3:::bucket/logs/"},
+		{Location{Bucket: "bucket", Partition: "aws-us-gov"}, "s3://bucket/", "arn:aws-us-gov:s3:::bucket"},
+		{Location{Bucket: apARN, AccessPoint: "reports"}, "s3://" + apARN + "/", apARN},
+		{Location{Bucket: apARN, AccessPoint: "reports", Prefix: "logs/"}, "s3://" + apARN + "/logs/", apARN + "/object/logs/"},
+	}
+	for _, tt := range tests {
+		if got := tt.loc.URI(); got != tt.wantURI {
+			t.Errorf("%+v URI = %q, want %q", tt.loc, got, tt.wantURI)
+		}
+		if got := tt.loc.ARN(); got != tt.wantARN {
+			t.Errorf("%+v ARN = %q, want %q", tt.loc, got, tt.wantARN)
+		}
+		// Names parse back to the same location
+		for _, name := range []string{tt.loc.URI(), tt.loc.ARN()} {
+			back, err := ParseLocation(name)
+			if err != nil || back.Bucket != tt.loc.Bucket || back.Prefix != tt.loc.Prefix {
+				t.Errorf("ParseLocation(%q) = %+v, %v; want bucket %q prefix %q", name, back, err, tt.loc.Bucket, tt.loc.Prefix)
+			}
+		}
+	}
+}
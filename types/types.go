@@ -11,12 +11,569 @@ type BucketSummary struct {
 	TotalSize      int64
 	StorageClasses map[string]StorageClassStats
 	EstimatedCost  float64
+	Versioning     *VersionSummary
+	Restores       []RestoreEstimate
+	Tiering        *TieringSimulation
+	Lifecycle      *LifecycleAdvice
+	// AccessCosts is set with --access-rate or --access-logs
+	AccessCosts *AccessSimulation
+	// ObjectFees are prefixes whose per-object fees outweigh their storage
+	// cost; ObjectFeeCost is the monthly per-object fees the objects
+	// already pay, which EstimatedCost leaves out
+	ObjectFees    []ObjectFeeWarning
+	ObjectFeeCost float64
+	// Truncated is set when --limit stopped the listing before the end,
+	// and with Interrupted when SIGINT or SIGTERM did
+	Truncated   bool
+	Interrupted bool
+	Estimate    *ListingEstimate
+	// Inventory is set when objects were read from an S3 Inventory report
+	// instead of listed
+	Inventory *InventorySource
+	// Scope is set when --prefix, --include or --exclude left keys out of
+	// the listing
+	Scope *KeyScope
+	// Ownership is nil when the credentials' account could not be read
+	Ownership *BucketOwnership
+	// Top is set from the metadata analysis when --top is not 0
+	Top *TopUsage
+	// OpenData is set with --open-data for buckets of known datasets
+	OpenData *OpenDataset
+	// Billable is set with --billable-size
+	Billable *BillableSize
+	// Performance holds the request rates of the listing and, with
+	// --enrich, of enrichment
+	Performance []OperationStats
+	// NoContentAccess is set when the run was barred from reading objects
+	NoContentAccess bool
+	// Findings are the security and compliance findings and policy
+	// violations, most severe first
+	Findings []Finding
+}
+
+// OperationStats records the rate one stage of a profile achieved against
+// S3 and the concurrency it ran at
+type OperationStats struct {
+	// Operation is the S3 API the stage calls, such as ListObjectsV2
+	Operation string
+	// Requests counts attempts, retries included; Throttled those S3
+	// answered with 503 Slow Down
+	Requests  int64
+	Throttled int64
+	// Items counts the objects listed or sampled
+	Items       int64
+	Elapsed     time.Duration
+	MeanLatency time.Duration
+	// Adaptive is set when the concurrency was tuned from throttling and
+	// latency; the workers are the concurrency limits it went through,
+	// up to MaxWorkers
+	Adaptive    bool
+	MaxWorkers  int
+	InitWorkers int
+	MinWorkers  int
+	PeakWorkers int
+	EndWorkers  int
+}
+
+// BillableSize models the bytes S3 bills for a bucket, as opposed to the
+// naive sum of current object sizes, per storage class
+type BillableSize struct {
+	// Naive is the sum of current object sizes (TotalSize); Total is the
+	// modeled billable bytes
+	Naive   int64
+	Total   int64
+	Classes []BillableClass
+	// Uploads and Parts count the incomplete multipart uploads and their
+	// parts; PartsTruncated is set when not every upload's parts were
+	// listed
+	Uploads        int64
+	Parts          int64
+	PartsTruncated bool
+	// MetricsDate is the day of the CloudWatch BucketSizeBytes metrics;
+	// zero when they were not available
+	MetricsDate     time.Time
+	CloudWatchTotal int64
+}
+
+// BillableClass breaks down the modeled billable bytes of a storage class
+type BillableClass struct {
+	StorageClass string
+	// Current is the size of current versions and Overhead the minimum
+	// size and archive overhead billed on top of them
+	Current  int64
+	Overhead int64
+	// Noncurrent includes the overhead of non-current versions
+	Noncurrent    int64
+	DeleteMarkers int64
+	Multipart     int64
+	Total         int64
+	// CloudWatch is the BucketSizeBytes of the class's storage types
+	CloudWatch int64
+}
+
+// OpenDataset describes a bucket of the AWS Open Data registry. Storage of
+// these buckets is paid by the dataset sponsor; requester-pays datasets
+// bill requests and transfer to the account reading them.
+type OpenDataset struct {
+	Name          string
+	Registry      string
+	Region        string
+	RequesterPays bool
+	// Layout names the dataset's date layout added to partition detection
+	Layout string
+}
+
+// BucketOwnership records whether a bucket belongs to the profiling
+// account. Cost figures of a foreign bucket (shared or public data) are
+// billed to its owner, not to CallerAccount.
+type BucketOwnership struct {
+	CallerAccount string
+	// Verified is set when HeadBucket settled ownership; Foreign is set
+	// when the bucket belongs to another account
+	Verified bool
+	Foreign  bool
+	// ObjectOwnership is the Object Ownership setting: BucketOwnerEnforced,
+	// BucketOwnerPreferred or ObjectWriter ("" when unknown)
+	ObjectOwnership string
+}
+
+// Results of a security posture check
+const (
+	AuditPass  = "PASS"
+	AuditFail  = "FAIL"
+	AuditError = "ERROR"
+)
+
+// AuditCheck is the outcome of one security posture check on a bucket
+type AuditCheck struct {
+	Name   string
+	Status string
+	// Detail describes the setting found, or the error for AuditError
+	Detail string
+}
+
+// BucketAudit is the security posture of a bucket, as reported by the
+// audit command
+type BucketAudit struct {
+	Bucket string
+	Region string
+	Checks []AuditCheck
+}
+
+// InventorySource identifies the S3 Inventory report a bucket's objects
+// were read from. The report reflects the bucket as of Generated.
+type InventorySource struct {
+	ConfigID  string
+	Bucket    string
+	Manifest  string
+	Format    string
+	Generated time.Time
+	Files     int
+	Size      int64
+}
+
+// ListingEstimate extrapolates bucket totals when only part of the bucket
+// was listed. Without a CloudWatch object count (BucketObjects is 0) the
+// listed figures are lower bounds and nothing is extrapolated.
+type ListingEstimate struct {
+	Limit         int64     `json:"limit"`
+	ListedObjects int64     `json:"listed_objects"`
+	ListedSize    int64     `json:"listed_size"`
+	BucketObjects int64     `json:"bucket_objects,omitempty"`
+	MetricsDate   time.Time `json:"metrics_date,omitempty"`
+	Fraction      float64   `json:"fraction,omitempty"`
+	Size          int64     `json:"estimated_size,omitempty"`
+	Cost          float64   `json:"estimated_cost,omitempty"`
+	// Interrupted is set when a signal rather than the limit stopped the
+	// listing; the reports are then partial
+	Interrupted bool `json:"interrupted,omitempty"`
 }
 
 // StorageClassStats holds count and size for a specific storage class
 type StorageClassStats struct {
 	Count int64
 	Size  int64
+	// Billable is Size plus the bytes S3 bills on top: the 128 KB minimum
+	// object size of the IA and Glacier Instant Retrieval classes and the
+	// 40 KB per-object overhead of GLACIER and DEEP_ARCHIVE
+	Billable int64
+	// Cost is the estimated monthly storage cost, set on bucket summaries
+	// once listing completes
+	Cost float64
+}
+
+// RestoreEstimate holds restore cost options for an archived prefix
+type RestoreEstimate struct {
+	Prefix       string
+	StorageClass string
+	ObjectCount  int64
+	Size         int64
+	Options      []RestoreOption
+}
+
+// RestoreOption is the estimated cost and time of one retrieval tier
+type RestoreOption struct {
+	Tier string
+	Cost float64
+	Time string
+}
+
+// ObjectFeeWarning is a prefix whose per-object fees in a storage class
+// cost more than storing its bytes there
+type ObjectFeeWarning struct {
+	Prefix       string
+	StorageClass string
+	// Current is set when the objects are already in StorageClass;
+	// otherwise the fees apply once a lifecycle rule moves them
+	Current      bool
+	ObjectCount  int64
+	Size         int64
+	DataCost     float64
+	OverheadCost float64
+}
+
+// TieringSimulation models switching prefixes to Intelligent-Tiering
+type TieringSimulation struct {
+	Prefixes []TieringEstimate
+	// BreakEvenObjectSize is the object size below which monitoring costs
+	// more than the infrequent tier saves over STANDARD
+	BreakEvenObjectSize int64
+}
+
+// AccessSimulation prices each top-level prefix under every storage class
+// for an assumed monthly GET rate
+type AccessSimulation struct {
+	// LogDays is the days of server access logs rates were derived from
+	// (0 when none were read) and LogRequests the requests they held
+	LogDays     int
+	LogRequests int64
+	// Archive is set when GLACIER and DEEP_ARCHIVE can be recommended
+	Archive  bool
+	Prefixes []PrefixAccessCost
+}
+
+// PrefixAccessCost is the monthly cost of a top-level prefix as it is
+// stored and moved wholesale to each storage class
+type PrefixAccessCost struct {
+	Prefix      string
+	ObjectCount int64
+	Size        int64
+	// Gets is the GET requests per month; Source is "flag", "default" or
+	// "logs"
+	Gets        float64
+	Source      string
+	CurrentCost float64
+	Costs       []StorageClassCost
+	// Recommended is the cheapest class and Savings its saving over the
+	// current cost
+	Recommended string
+	Savings     float64
+	// MinGets and MaxGets bound the monthly GETs for which Recommended
+	// stays the cheapest; MaxGets is -1 when there is no upper bound
+	MinGets float64
+	MaxGets float64
+}
+
+// StorageClassCost is the monthly cost of a prefix in one storage class
+type StorageClassCost struct {
+	StorageClass string
+	Storage      float64
+	Requests     float64
+	Retrieval    float64
+	Total        float64
+}
+
+// LifecycleAgeDays are the ages, in days since the last write, that data
+// ages are reported for
+var LifecycleAgeDays = []int{30, 90, 180, 365}
+
+// LifecycleAdvice is the age distribution of a bucket's data and the
+// lifecycle transition rules recommended from it
+type LifecycleAdvice struct {
+	Ages  []DataAge
+	Rules []LifecycleRecommendation
+	// Archive is set when transitions to GLACIER and DEEP_ARCHIVE were
+	// considered
+	Archive bool
+}
+
+// DataAge is the data of a top-level prefix in one storage class
+type DataAge struct {
+	Prefix       string
+	StorageClass string
+	ObjectCount  int64
+	Size         int64
+	// OlderSize holds the bytes older than each of LifecycleAgeDays
+	OlderSize []int64
+}
+
+// LifecycleRecommendation is a recommended lifecycle rule for a top-level
+// prefix. Counts and costs cover the objects the rule would transition
+// today.
+type LifecycleRecommendation struct {
+	Prefix      string
+	Transitions []LifecycleTransition
+	ObjectCount int64
+	Size        int64
+	// CurrentCost and NewCost are the monthly storage cost of the objects
+	// before and after the transition
+	CurrentCost float64
+	NewCost     float64
+	Savings     float64
+	// TransitionCost is the one-time lifecycle transition request fee
+	TransitionCost float64
+	PaybackMonths  float64
+}
+
+// LifecycleTransition moves objects to StorageClass Days after they were
+// written
+type LifecycleTransition struct {
+	Days         int
+	StorageClass string
+}
+
+// TieringEstimate is the modeled monthly cost of moving a prefix's
+// STANDARD and STANDARD_IA objects to Intelligent-Tiering
+type TieringEstimate struct {
+	Prefix      string
+	ObjectCount int64
+	Size        int64
+	// MonitoredObjects and MonitoredSize cover objects of at least 128 KB;
+	// smaller ones are not transitioned
+	MonitoredObjects int64
+	MonitoredSize    int64
+	// InfrequentSize and ArchiveSize are the bytes expected in the
+	// infrequent and archive instant access tiers
+	InfrequentSize int64
+	ArchiveSize    int64
+	CurrentCost    float64
+	// TieredCost includes MonitoringFee
+	TieredCost     float64
+	MonitoringFee  float64
+	Savings        float64
+	TransitionCost float64
+	// BreakEvenColdShare is the share of monitored objects' potential
+	// savings needed to cover the monitoring fee (above 1: never)
+	BreakEvenColdShare float64
+	// PaybackMonths is how long savings take to cover TransitionCost (0
+	// when there are no savings)
+	PaybackMonths float64
+}
+
+// VersionSummary contains statistics about object versions in a bucket
+// with versioning enabled or suspended
+type VersionSummary struct {
+	Status string
+	// NoncurrentVersions and NoncurrentSize cover every version that is not
+	// the latest of its key; NoncurrentCost is their monthly storage cost,
+	// which is included in the bucket's EstimatedCost
+	NoncurrentVersions int64
+	NoncurrentSize     int64
+	NoncurrentCost     float64
+	NoncurrentClasses  map[string]StorageClassStats
+	// NoncurrentPrefixes aggregates non-current versions by top-level
+	// prefix, largest first
+	NoncurrentPrefixes []PrefixVersionStats
+	// DeleteMarkers counts all delete markers; CurrentDeleteMarkers those
+	// that are the latest version, i.e. keys that appear deleted
+	DeleteMarkers        int64
+	CurrentDeleteMarkers int64
+	// DeleteMarkerBytes is the length of the delete markers' key names,
+	// which S3 bills as storage
+	DeleteMarkerBytes int64
+	DeletedKeys       int64
+	DeletedVersions   int64
+	DeletedSize       int64
+	DeletedPrefixes   []PrefixVersionStats
+	// Truncated is set when --limit stopped the version listing
+	Truncated bool
+}
+
+// PrefixVersionStats holds version statistics for a top-level prefix
+type PrefixVersionStats struct {
+	Prefix   string
+	Keys     int64
+	Versions int64
+	Size     int64
+}
+
+// BucketConfiguration contains the audited configuration of a bucket
+type BucketConfiguration struct {
+	Inventories             []InventoryConfig
+	InventoryRecommendation *InventoryRecommendation
+	Notifications           []NotificationConfig
+	EventBridgeEnabled      bool
+	NotificationCoverage    []PrefixCoverage
+	Website                 *WebsiteConfig
+	CORSRules               []CORSRule
+	ChargeableFeatures      []ChargeableFeature
+	AccessFindings          []AccessFinding
+	AccessAnalyzerChecked   bool
+	// Tags are the bucket's cost allocation tags
+	Tags map[string]string
+	// PublicAccessBlockOff lists the bucket's Block Public Access settings
+	// that are off; PolicyPublic is set when its policy grants public access
+	PublicAccessChecked  bool
+	PublicAccessBlockOff []string
+	PolicyPublic         bool
+	// DefaultEncryption is the default server-side encryption algorithm
+	// ("" when the bucket has none)
+	EncryptionChecked bool
+	DefaultEncryption string
+	LifecycleChecked  bool
+	LifecycleRules    []LifecycleRule
+	Warnings          []string
+	Errors            []string
+}
+
+// LifecycleRule describes one lifecycle rule on a bucket
+type LifecycleRule struct {
+	ID      string
+	Enabled bool
+	Prefix  string
+	// Actions summarize the rule, e.g. "transition 30d -> STANDARD_IA"
+	Actions []string
+}
+
+// FindingRule describes a kind of security, compliance or cost finding
+type FindingRule struct {
+	ID          string
+	Name        string
+	Description string
+	// Recommendation is the general remedy for findings of the rule
+	Recommendation string
+	// Level is the SARIF level: "error", "warning" or "note"
+	Level string
+	// SecuritySeverity is the CVSS-style score (0-10) code scanning uses
+	// to rank security findings; cost rules are scored on the same scale
+	SecuritySeverity float64
+}
+
+// Finding is one security, compliance or cost finding for a bucket or one
+// of its prefixes
+type Finding struct {
+	Rule   FindingRule
+	Bucket string
+	// Prefix is "" for bucket-wide findings
+	Prefix string
+	// Resource is the s3:// URI of the bucket or prefix
+	Resource string
+	Message  string
+	// Recommendation is what to do about the finding
+	Recommendation string
+	// Savings is the estimated monthly cost in USD saved by following the
+	// recommendation (0 when it saves none or cannot be estimated)
+	Savings float64
+	// Detail distinguishes findings of the same rule on the same location,
+	// such as an Access Analyzer finding ID
+	Detail string
+	// Severity is rated from the rule's SecuritySeverity
+	Severity Severity
+}
+
+// Severity ranks findings so large reports can be triaged top-down
+type Severity string
+
+// Severity levels, from least to most severe
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarn     Severity = "warn"
+	SeverityCritical Severity = "critical"
+)
+
+// SeverityThresholds are the lowest rule scores (0-10) rated warn and
+// critical; lower scores are info
+type SeverityThresholds struct {
+	Warn     float64
+	Critical float64
+}
+
+// AccessFinding is an IAM Access Analyzer finding granting access to the
+// bucket from outside the zone of trust
+type AccessFinding struct {
+	ID        string
+	IsPublic  bool
+	Principal string
+	Actions   []string
+	Condition string
+	Sources   []string
+}
+
+// WebsiteConfig describes static website hosting on a bucket
+type WebsiteConfig struct {
+	IndexDocument string
+	ErrorDocument string
+	RedirectAllTo string
+	RoutingRules  []string
+}
+
+// CORSRule describes one CORS rule on a bucket
+type CORSRule struct {
+	ID             string
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAgeSeconds  int32
+}
+
+// NotificationConfig describes one bucket event notification target
+type NotificationConfig struct {
+	ID          string
+	Type        string
+	Destination string
+	Events      []string
+	Prefix      string
+	Suffix      string
+}
+
+// PrefixCoverage describes which notification targets cover a dataset prefix
+type PrefixCoverage struct {
+	Prefix       string
+	Destinations []string
+	// Expected is set when the prefix was declared as event-driven
+	Expected bool
+}
+
+// InventoryConfig describes an S3 Inventory configuration on a bucket
+type InventoryConfig struct {
+	ID               string
+	Prefix           string
+	Enabled          bool
+	Destination      string
+	Format           string
+	Frequency        string
+	IncludedVersions string
+	// DestinationBucket and DestinationPrefix locate the delivered reports;
+	// OptionalFields are the columns included besides Bucket and Key
+	DestinationBucket string
+	DestinationPrefix string
+	OptionalFields    []string
+}
+
+// ChargeableFeature is a configured bucket feature that is billed on its
+// own, with an estimate of its monthly cost
+type ChargeableFeature struct {
+	Type             string
+	ID               string
+	Scope            string
+	MonitoredObjects int64
+	MonthlyCost      float64
+	Note             string
+}
+
+// StorageLensConfig is a Storage Lens configuration covering a bucket
+type StorageLensConfig struct {
+	ID       string
+	Advanced bool
+}
+
+// InventoryRecommendation is a suggested S3 Inventory configuration for a
+// bucket that has none
+type InventoryRecommendation struct {
+	Frequency      string
+	Format         string
+	OptionalFields []string
+	Reason         string
 }
 
 // ObjectMetadata contains metadata for a single S3 object
@@ -32,8 +589,165 @@ type ObjectMetadata struct {
 type MetadataSummary struct {
 	Objects          []ObjectMetadata
 	FileTypeStats    map[string]int64
+	FileTypeSizes    map[string]int64
 	SizeDistribution []SizeBucket
 	DateRange        DateRange
+	Enrichment       *EnrichmentSummary
+	ContentSamples   []ContentSample
+	KeyEncoding      []KeyEncodingIssue
+	Top              *TopUsage
+	// ParquetSchema is the schema inferred from Parquet footers per
+	// partition; nil unless requested
+	ParquetSchema []ParquetStats
+	// Compressibility is set with --compressibility
+	Compressibility *CompressibilityAnalysis
+	// EncryptedContent lists the prefixes holding objects encrypted with
+	// keys generic consumers do not have
+	EncryptedContent []EncryptedPrefix
+}
+
+// EncryptedPrefix is a prefix holding SSE-C or client-side encrypted
+// objects, which crawlers and query engines cannot read
+type EncryptedPrefix struct {
+	Prefix      string
+	ObjectCount int64
+	Size        int64
+	// Suffix counts the objects named like encrypted files (.gpg, .enc, ...)
+	Suffix int64
+	// SSECustomer and ClientSide are estimated from the HeadObject sample
+	SSECustomer float64
+	ClientSide  float64
+	// Unreadable is the estimated number of encrypted objects
+	Unreadable int64
+}
+
+// Compressibility classes of a prefix
+const (
+	CompressedContent   = "compressed"
+	CompressibleContent = "compressible"
+	PartlyCompressible  = "partly compressible"
+)
+
+// CompressibilityAnalysis scores how well the content of each top-level
+// prefix compresses, from the first bytes of sampled objects
+type CompressibilityAnalysis struct {
+	Prefixes []PrefixCompressibility
+	// SampleBytes is the number of bytes read from each sampled object
+	SampleBytes int64
+	Sampled     int
+	Failed      int
+	// Savings is the monthly storage cost saved if the compressible
+	// prefixes were written compressed
+	Savings float64
+}
+
+// PrefixCompressibility is the compressibility of one top-level prefix
+type PrefixCompressibility struct {
+	Prefix      string
+	ObjectCount int64
+	Size        int64
+	Sampled     int
+	// Entropy is the Shannon entropy of the sampled bytes in bits per
+	// byte; 8 is indistinguishable from random
+	Entropy float64
+	// Ratio is the DEFLATE-compressed size of the sampled bytes over
+	// their size
+	Ratio float64
+	Class string
+	// SavedBytes and Savings project compressing the prefix's objects at
+	// Ratio; they are zero for compressed content
+	SavedBytes int64
+	Savings    float64
+}
+
+// TopUsage lists the largest objects and the prefixes holding the most
+// bytes directly (not counting subprefixes), largest first
+type TopUsage struct {
+	Objects  []ObjectMetadata
+	Prefixes []PrefixStats
+}
+
+// KeyEncodingIssue counts keys with one kind of unusual encoding
+type KeyEncodingIssue struct {
+	Issue    string
+	Count    int64
+	Examples []string
+}
+
+// KeyRename is a suggested new name for a key with unusual encoding
+type KeyRename struct {
+	From string
+	To   string
+}
+
+// ContentSample is the result of inspecting one object's content with the
+// sampler registered for its format
+type ContentSample struct {
+	Key        string
+	Format     string
+	Attributes map[string]string
+	Error      string
+}
+
+// EnrichmentSummary aggregates HeadObject attributes from a stratified
+// sample. Counts are estimated objects: each sampled object stands for its
+// prefix's objects divided by the number sampled there.
+type EnrichmentSummary struct {
+	Sampled           int64
+	Failed            int64
+	Strata            int
+	ContentTypes      map[string]float64
+	ContentEncodings  map[string]float64
+	Encryption        map[string]float64
+	ReplicationStatus map[string]float64
+	ObjectLock        map[string]float64
+	UserMetadataKeys  map[string]float64
+	// UnencryptedPrefixes estimates the objects without server-side
+	// encryption in each sampled prefix
+	UnencryptedPrefixes map[string]float64
+	// SSECustomerPrefixes and ClientSidePrefixes estimate the objects
+	// encrypted with SSE-C or by the client in each sampled prefix
+	SSECustomerPrefixes map[string]float64
+	ClientSidePrefixes  map[string]float64
+	// Performance is the rate the HeadObject requests achieved
+	Performance *OperationStats
+}
+
+// TagSummary aggregates object tags read with GetObjectTagging. Unless
+// Complete, counts are estimates: each sampled object stands for its
+// prefix's objects divided by the number sampled there.
+type TagSummary struct {
+	Sampled int64
+	Failed  int64
+	Strata  int
+	// Complete is set when the tags of every object were read
+	Complete bool
+	Tagged   TagUsage
+	Untagged TagUsage
+	// Keys lists every tag key, most objects first
+	Keys []TagKeyUsage
+	// UntaggedPrefixes are the prefixes holding untagged objects, most
+	// bytes first
+	UntaggedPrefixes []TagUsage
+	// Performance is the rate the GetObjectTagging requests achieved
+	Performance *OperationStats
+}
+
+// TagUsage is the objects, bytes and monthly storage cost under a tag
+// key, tag value or prefix
+type TagUsage struct {
+	Name        string
+	ObjectCount int64
+	TotalSize   int64
+	MonthlyCost float64
+}
+
+// TagKeyUsage is the usage of one tag key and its most common values
+type TagKeyUsage struct {
+	TagUsage
+	Values []TagUsage
+	// DistinctValues counts all values, including those beyond Values
+	DistinctValues int
 }
 
 // SizeBucket represents a size range in the distribution histogram
@@ -52,11 +766,351 @@ type DateRange struct {
 
 // Partition represents a detected partition pattern in S3 keys
 type Partition struct {
+	// Scope is the top-level prefix the pattern was detected in ("" for
+	// the bucket root or bucket-wide hierarchical partitions); Prefix is
+	// the matched partition path within it
+	Scope   string
+	Prefix  string
+	Pattern string
+	// Date is the first day a date partition covers (zero otherwise)
+	Date        time.Time
+	ObjectCount int64
+	TotalSize   int64
+	// Oldest and Newest are the earliest and latest LastModified of the
+	// partition's objects
+	Oldest time.Time
+	Newest time.Time
+	// OutsideObjects and OutsideSize count objects written before a date
+	// partition's period or more than a day after it ended
+	OutsideObjects int64
+	OutsideSize    int64
+	Examples       []string
+}
+
+// PartitionAnalysis contains detected partitions and related findings
+type PartitionAnalysis struct {
+	Partitions  []Partition
+	Patterns    []PatternCoverage
+	Issues      []PartitionIssue
+	Rejected    []RejectedPattern
+	Backfills   []PartitionBackfill
+	Suggestions []RepartitionSuggestion
+	Pruning     []PruningEstimate
+	Hotspots    []PrefixHotspot
+	SmallFiles  []SmallFilePartition
+}
+
+// SmallFilePartition is a directory holding many small files, which slow
+// down listing and query engines and cost a request per file to read
+type SmallFilePartition struct {
 	Prefix      string
+	ObjectCount int64
+	Size        int64
+	// SmallFiles counts the objects under the size threshold
+	SmallFiles  int64
+	AverageSize int64
+	// CompactedFiles is the number of files after compacting to 128 MiB,
+	// each of CompactedSize bytes
+	CompactedFiles int64
+	CompactedSize  int64
+}
+
+// PrefixHotspot flags a prefix whose writes, clustered by LastModified,
+// come close to the S3 per-prefix request-rate limit
+type PrefixHotspot struct {
+	Prefix string
+	// PeakWrites is the most objects written within one second, at PeakAt
+	PeakWrites int64
+	PeakAt     time.Time
+	// BurstSeconds counts the seconds over half the limit
+	BurstSeconds int64
+	// SustainedRate is the writes per second of the busiest clock minute
+	SustainedRate float64
+	// Share is PeakWrites over the 3,500 writes per second limit
+	Share float64
+	// Naming is how keys continue after the prefix: timestamp, sequential
+	// or mixed
+	Naming string
+	// Shards is the suggested number of hashed sub-prefixes and Example a
+	// key rewritten with one
+	Shards     int
+	Example    string
+	Suggestion string
+}
+
+// RepartitionSuggestion proposes a partition granularity for one date
+// pattern whose partitions are far from the target size or skewed
+type RepartitionSuggestion struct {
+	Scope       string
+	Pattern     string
+	Current     string
+	Recommended string
+	// Skew is the largest partition's size over the median
+	Skew    float64
+	Reason  string
+	Schemes []RepartitionScheme
+}
+
+// RepartitionScheme projects the partitions under one granularity
+type RepartitionScheme struct {
+	Granularity  string
+	Partitions   int64
+	AverageSize  int64
+	AverageFiles float64
+	// FilesAtTarget is the files per partition after compacting to 128 MiB
+	FilesAtTarget int64
+	// Estimated is set for finer granularities, which assume objects are
+	// spread evenly within each current partition
+	Estimated bool
+}
+
+// PruningEstimate is how much data a query over the most recent Window
+// (e.g. "7d") of a date pattern scans under each partition granularity
+type PruningEstimate struct {
+	Scope   string
+	Pattern string
+	Window  string
+	Current string
+	// Recommended is the granularity repartitioning suggests, if any
+	Recommended string
+	TotalSize   int64
+	Scans       []PruningScan
+}
+
+// PruningScan is the data a query scans under one granularity, "none"
+// for an unpartitioned table
+type PruningScan struct {
+	Granularity string
+	// Partitions is the number of partitions the query reads
+	Partitions int64
+	Size       int64
+	// Cost is the Athena cost of the query
+	Cost float64
+	// Estimated is set for granularities finer than the current one
+	Estimated bool
+}
+
+// PartitionBackfill flags a date partition receiving writes outside its
+// nominal date, which incremental consumers keyed on the date would miss
+type PartitionBackfill struct {
+	Scope  string
+	Prefix string
+	// Objects and Size were written outside the partition's period
+	Objects int64
+	Size    int64
+	Share   float64
+	Newest  time.Time
+	Reason  string
+}
+
+// PatternCoverage summarizes one detected pattern within its scope
+type PatternCoverage struct {
+	Scope       string
 	Pattern     string
 	ObjectCount int64
 	TotalSize   int64
-	Examples    []string
+	// Coverage is the share of the scope's objects in the pattern's
+	// partitions (of the whole bucket when Scope is "")
+	Coverage float64
+}
+
+// RejectedPattern is a date pattern that matched most keys but was not
+// accepted because the matches are not plausible dates
+type RejectedPattern struct {
+	Scope    string
+	Pattern  string
+	Matches  int64
+	Invalid  int64
+	Reason   string
+	Examples []string
+	// Sampled is set when the counts come from --partition-sample-rate
+	Sampled bool
+}
+
+// PartitionIssue is a partition naming inconsistency with a suggested fix
+type PartitionIssue struct {
+	Issue      string
+	Examples   []string
+	Suggestion string
+}
+
+// ParquetStats summarizes Parquet footer statistics sampled from one
+// partition
+type ParquetStats struct {
+	Partition    string
+	Files        int64
+	FilesSampled int
+	FilesFailed  int
+	Rows         int64
+	RowGroups    int
+	// Codecs are the compression codecs used by any sampled column chunk
+	Codecs  []string
+	Columns []ParquetColumnStats
+}
+
+// ParquetColumnStats holds a column's merged statistics across the sampled
+// files. Min and Max are only set when every row group recorded them.
+type ParquetColumnStats struct {
+	Name string
+	Type string
+	// LogicalType is the physical type with its logical annotation, such
+	// as "INT64 TIMESTAMP(us)"
+	LogicalType string
+	Repetition  string
+	Codecs      []string
+	Min         string
+	Max         string
+	HasMinMax   bool
+	Values      int64
+	Nulls       int64
+	NullsKnown  bool
+	// CompressedSize and UncompressedSize are the column chunk bytes in
+	// the sampled files
+	CompressedSize   int64
+	UncompressedSize int64
+	// Files is the number of sampled files that have the column; fewer
+	// than FilesSampled means the schema drifted between files
+	Files int
+}
+
+// DimensionTable aggregates objects by the values of a key-derived dimension
+type DimensionTable struct {
+	Name      string
+	Rows      []DimensionStats
+	Unmatched int64
+}
+
+// DimensionStats holds aggregate statistics for one dimension value
+type DimensionStats struct {
+	Value       string
+	ObjectCount int64
+	TotalSize   int64
+	MonthlyCost float64
+	Oldest      time.Time
+	Newest      time.Time
+	// AverageAgeDays is the mean object age, weighted by object count
+	AverageAgeDays float64
+}
+
+// PrefixNode is a node in the key prefix tree. Size and ObjectCount include
+// all descendants; SelfSize and SelfCount cover objects directly under the
+// prefix. Oldest and Newest are the range of LastModified under the node;
+// they are zero when the tree was built without object timestamps.
+type PrefixNode struct {
+	Name        string
+	Size        int64
+	ObjectCount int64
+	SelfSize    int64
+	SelfCount   int64
+	Oldest      time.Time
+	Newest      time.Time
+	Children    []*PrefixNode
+}
+
+// GlueTable is the catalog definition of a partitioned Glue table
+type GlueTable struct {
+	Database string
+	Name     string
+	// Location is the table's S3 URL, e.g. s3://bucket/sales/
+	Location      string
+	PartitionKeys []string
+	// StorageDescriptor is the table's descriptor as returned by GetTable,
+	// copied into new partitions
+	StorageDescriptor map[string]any
+	// Partitions holds the values of every registered partition
+	Partitions [][]string
+}
+
+// GluePartition is a partition directory under a Glue table's location
+type GluePartition struct {
+	Values []string
+	// Prefix is the partition directory's key prefix in the bucket
+	Prefix      string
+	ObjectCount int64
+	TotalSize   int64
+}
+
+// GlueRegistration compares the partition directories found under a Glue
+// table's location with the partitions registered in the catalog
+type GlueRegistration struct {
+	Table *GlueTable
+	// Registered counts directories already in the catalog; Missing lists
+	// the rest
+	Registered int
+	Missing    []GluePartition
+	// Unmapped counts objects under the location whose path does not
+	// supply a value for every partition key
+	Unmapped int64
+}
+
+// DataCard documents one dataset, a top-level prefix with date partitions
+// (or the whole bucket when none has them), for publishing to a data
+// catalog
+type DataCard struct {
+	Bucket    string
+	Region    string
+	Scope     string
+	Generated time.Time
+
+	ObjectCount    int64
+	TotalSize      int64
+	StorageClasses map[string]StorageClassStats
+	// Formats counts objects per file extension
+	Formats map[string]int64
+
+	Patterns       []PatternCoverage
+	Partitions     int
+	FirstPartition time.Time
+	LastPartition  time.Time
+
+	// Oldest and Newest are the earliest and latest LastModified
+	Oldest time.Time
+	Newest time.Time
+	// SizeOver90Days and SizeOver1Year are the bytes written more than 90
+	// and 365 days before the run
+	SizeOver90Days int64
+	SizeOver1Year  int64
+	Retention      string
+
+	// Schema is nil unless content sampling or Parquet statistics covered
+	// the dataset
+	Schema     *DatasetSchema
+	SampleKeys []string
+}
+
+// DatasetSchema is the column layout read from one dataset's files
+type DatasetSchema struct {
+	Format string
+	// Source describes where the columns were read from
+	Source  string
+	Columns []SchemaColumn
+}
+
+// SchemaColumn is one column; Type is empty for formats without types
+type SchemaColumn struct {
+	Name string
+	Type string
+}
+
+// AthenaTable is an external table over one location's Hive-style date
+// partitions
+type AthenaTable struct {
+	// Location is the key prefix the partition directories are under
+	Location string
+	// Format is parquet, csv or json, the most common among the files
+	Format        string
+	Pattern       string
+	PartitionKeys []string
+	Partitions    int
+	// Columns have Athena types; nil when no Parquet footer or content
+	// sample under the location was read. SchemaSource is the key or
+	// partition they were read from.
+	Columns      []SchemaColumn
+	SchemaSource string
+	// Delimiter separates CSV fields; Header is set when the first line
+	// of CSV files names the columns
+	Delimiter string
+	Header    bool
 }
 
 // ProfileConfig holds configuration for the profiling operation
@@ -67,4 +1121,407 @@ type ProfileConfig struct {
 	Limit       int64
 	OutputDir   string
 	AllBuckets  bool
+	// Prefixes limits listings to keys under these prefixes; when Include
+	// globs are given keys must match one, and keys matching an Exclude
+	// glob are skipped
+	Prefixes []string
+	Include  []string
+	Exclude  []string
+	// NoSignRequest is set when requests are sent without credentials
+	NoSignRequest bool
+	// OpenData annotates buckets of the AWS Open Data registry and adds
+	// the layouts of known datasets to partition detection
+	OpenData bool
+	// ListWorkers lists prefix shards of a bucket concurrently when above
+	// 1; runs with Limit list sequentially
+	ListWorkers int
+	// FixedConcurrency runs ListWorkers and EnrichWorkers requests at all
+	// times instead of adapting to throttling and latency up to them
+	FixedConcurrency bool
+	// MaxWorkers is the number of buckets profiled concurrently
+	MaxWorkers int
+	// Top is the number of largest objects and heaviest prefixes listed
+	// in the summary report (0 disables)
+	Top int
+	// BillableSize models billable bytes including versions, delete
+	// markers and incomplete multipart uploads
+	BillableSize bool
+	// UseInventory reads objects from the latest S3 Inventory report
+	// instead of ListObjectsV2 when the bucket has a usable one
+	UseInventory bool
+	// CheckpointDir receives listing checkpoints every CheckpointInterval
+	// (0 disables them); Resume continues from an existing checkpoint
+	CheckpointDir      string
+	CheckpointInterval time.Duration
+	Resume             bool
+	// InterimInterval is how often an interim summary of a bucket's
+	// listing is written (0 disables interim summaries)
+	InterimInterval time.Duration
+
+	// ExportObjects writes a full object inventory alongside the reports
+	ExportObjects bool
+	// NDJSON writes each listed object to <bucket>-objects.ndjson while
+	// the bucket is listed
+	NDJSON bool
+	// Compression for large exports: "", "gzip" or "zstd"
+	Compression string
+	// EncryptOutput encrypts reports and exports: "age:" and a recipient
+	// or recipients file
+	EncryptOutput string
+	// Redact hashes bucket names and key contents in generated reports
+	Redact     bool
+	RedactSalt string
+	// Timezone is the IANA zone name used to render report timestamps
+	Timezone string
+	// Lang selects the report language (en, es or ja)
+	Lang string
+	// Currency is the ISO 4217 code report costs are converted to (USD when
+	// empty); BucketCurrencies overrides it per bucket. ExchangeRates holds
+	// the rate of every currency used.
+	Currency         string
+	BucketCurrencies map[string]string
+	ExchangeRates    map[string]ExchangeRate
+	// EmitInventoryConfig writes a recommended S3 Inventory configuration
+	// for buckets without one; InventoryDestination is its target bucket
+	EmitInventoryConfig  bool
+	InventoryDestination string
+	// EmitLifecycleConfig writes the recommended lifecycle rules as a
+	// lifecycle configuration; LifecycleArchive lets recommendations
+	// transition to GLACIER and DEEP_ARCHIVE
+	EmitLifecycleConfig bool
+	LifecycleArchive    bool
+	// AccessRates are assumed GET requests per month per top-level prefix
+	// ("*" for the others), priced under every storage class; with
+	// AccessLogDays the rates come from that many days of server access
+	// logs as well
+	AccessRates   map[string]float64
+	AccessLogDays int
+	// StoragePrices overrides the price per GB per month of storage
+	// classes, e.g. with the rates of another region
+	StoragePrices map[string]float64
+	// SARIF writes security and compliance findings as a SARIF log
+	SARIF bool
+	// SeverityThresholds rate findings; zero uses the defaults
+	SeverityThresholds SeverityThresholds
+	// Policies are Rego files or directories evaluated against each
+	// bucket's results with the opa binary at OPAPath
+	Policies []string
+	OPAPath  string
+	// ExpectNotifications lists prefixes that are expected to emit events
+	ExpectNotifications []string
+	// Dimensions are "name=regex" definitions; the regex's first capture
+	// group (or the group named after the dimension) is the value
+	Dimensions []string
+	// FlameGraph exports the prefix tree as "folded" or "speedscope"
+	FlameGraph string
+	// HTML writes a self-contained HTML report with a prefix treemap
+	HTML bool
+	// Markdown writes the summary, storage class, file type, size and
+	// partition tables as one Markdown report
+	Markdown bool
+	// XLSX writes one Excel workbook with the buckets of the run
+	XLSX bool
+	// MetricsTextfileDir receives each bucket's gauges for the
+	// node_exporter textfile collector
+	MetricsTextfileDir string
+	// EnrichSamples is the number of objects per prefix enriched with
+	// HeadObject (0 disables enrichment); EnrichWorkers bounds concurrency
+	EnrichSamples int
+	EnrichWorkers int
+	// NoContentAccess rejects every analyzer that reads objects with
+	// GetObject or HeadObject; the AWS client enforces it as well
+	NoContentAccess bool
+	// TagSamples is the number of objects per prefix whose tags are read
+	// with GetObjectTagging (0 disables, negative reads every object);
+	// the requests share EnrichWorkers
+	TagSamples int
+	// Compressibility is the number of objects per top-level prefix whose
+	// first bytes are read to score entropy and compressibility (0
+	// disables)
+	Compressibility int
+	// EmitRenameManifest writes suggested clean names for keys with
+	// unusual encodings
+	EmitRenameManifest bool
+	// SampleContent is the number of objects per format inspected by the
+	// registered content samplers (0 disables)
+	SampleContent int
+	// ParquetStats is the number of Parquet files per partition whose
+	// footer statistics are read (0 disables)
+	ParquetStats int
+	// ParquetSchema is the number of Parquet files per partition whose
+	// footers are read for the schema section of the metadata report (0
+	// disables)
+	ParquetSchema int
+	// PartitionSampleRate is the fraction of keys used to choose date
+	// partition patterns (1 evaluates every key)
+	PartitionSampleRate float64
+	// PartitionPatterns are extra date partition patterns, each NAME:REGEX
+	// with year, month and optional day capture groups
+	PartitionPatterns []string
+	// TargetPartitionMB is the partition size repartitioning suggestions
+	// aim for (0 uses 1024)
+	TargetPartitionMB int64
+	// SmallFileKB and SmallFileCount flag directories holding at least
+	// SmallFileCount objects under SmallFileKB KiB (0 uses 1024 and 1000)
+	SmallFileKB    int64
+	SmallFileCount int64
+	// QueryWindows are recent time ranges, e.g. 7d, that partition pruning
+	// is estimated for
+	QueryWindows []string
+	// DataCards writes a Markdown data card per dataset
+	DataCards bool
+	// OpenLineageURL receives an OpenLineage run event per bucket with
+	// the dataset facets (empty disables); the namespace names the job
+	OpenLineageURL       string
+	OpenLineageNamespace string
+	OpenLineageAPIKey    string
+	// EventTarget receives a CloudEvent per completed bucket: an
+	// http(s):// URL or kafka://broker/topic (empty disables)
+	EventTarget string
+	// ObjectStreamTarget receives a JSON message per listed object:
+	// kafka://broker/topic (empty disables)
+	ObjectStreamTarget string
+	// GlueTable is a "database.table" whose missing partitions are
+	// written as BatchCreatePartition requests
+	GlueTable string
+	// AlertCapacity (bytes) and AlertBudget (USD per month) are thresholds
+	// checked against each bucket's projected growth AlertWithinDays ahead,
+	// using the results database history; alerts go to AlertTarget
+	AlertCapacity   int64
+	AlertBudget     float64
+	AlertWithinDays int
+	AlertTarget     string
+	// CacheDir holds analyzer results keyed by inventory checksum (empty
+	// disables caching)
+	CacheDir string
+	// Report table sorting and truncation
+	SortBy   string
+	SortDesc bool
+	MaxRows  int
+}
+
+// Snapshot is a machine-readable record of one profiling run, used to
+// compare runs of the same bucket over time
+type Snapshot struct {
+	Bucket       string        `json:"bucket"`
+	Generated    time.Time     `json:"generated"`
+	TotalObjects int64         `json:"total_objects"`
+	TotalSize    int64         `json:"total_size"`
+	Prefixes     []PrefixStats `json:"prefixes"`
+	Partitions   []PrefixStats `json:"partitions,omitempty"`
+	// StorageClasses and EstimatedCost are missing from snapshots of older
+	// releases
+	StorageClasses []ClassStats `json:"storage_classes,omitempty"`
+	EstimatedCost  float64      `json:"estimated_cost,omitempty"`
+	// Estimate is set when the run only listed part of the bucket
+	Estimate *ListingEstimate `json:"estimate,omitempty"`
+	// NoContentAccess records that the run could not read any object's
+	// content or headers
+	NoContentAccess bool `json:"no_content_access,omitempty"`
+}
+
+// AccountRollup totals the buckets profiled with one AWS profile and
+// region of a multi-account run
+type AccountRollup struct {
+	Name    string
+	Profile string
+	Region  string
+	// Buckets are the snapshots of the profiled buckets; Failed names the
+	// buckets that could not be profiled
+	Buckets []*Snapshot
+	Failed  []string
+	// Error is set when the account could not be profiled at all, e.g.
+	// because its credentials were rejected
+	Error string
+}
+
+// ExchangeRate is the number of units of a currency per US dollar
+type ExchangeRate struct {
+	Rate float64
+	// Source describes where the rate came from
+	Source string
+}
+
+// RequestPlan estimates the requests profiling a bucket issues and their
+// cost
+type RequestPlan struct {
+	Bucket string
+	Region string
+	// Objects is the CloudWatch object count capped by the limit; 0 when
+	// unknown
+	Objects int64
+	// Prefixes counts the top-level prefixes sampling options read from
+	Prefixes int64
+	List     int64
+	Head     int64
+	Get      int64
+	Cost     float64
+	// Error is set when the bucket could not be planned
+	Error string
+}
+
+// InterimSummary is the state of a listing in progress, written
+// periodically so the findings of long runs can be read before they end
+type InterimSummary struct {
+	Bucket  string
+	Region  string
+	Started time.Time
+	Updated time.Time
+	// Complete is set once the listing finished and analysis continues
+	Complete       bool
+	Objects        int64
+	Size           int64
+	StorageClasses map[string]StorageClassStats
+	// Prefixes are the largest top-level prefixes listed so far
+	Prefixes []PrefixStats
+}
+
+// KeyScope is the part of a bucket a profile covers: the keys under
+// Prefixes (all keys when empty) that match an Include glob, when there are
+// any, and none of the Exclude globs
+type KeyScope struct {
+	Prefixes []string
+	Include  []string
+	Exclude  []string
+}
+
+// DiscoveredBucket is a bucket found by an inventory service (Resource
+// Explorer or an AWS Config aggregator) rather than ListBuckets
+type DiscoveredBucket struct {
+	Name    string
+	Account string
+	Region  string
+}
+
+// RunRecord is a run stored in the results database
+type RunRecord struct {
+	ID            int64
+	Bucket        string
+	Region        string
+	Generated     time.Time
+	TotalObjects  int64
+	TotalSize     int64
+	EstimatedCost float64
+	Truncated     bool
+}
+
+// GrowthTrend is a bucket's growth fitted to its recent stored runs
+type GrowthTrend struct {
+	Runs  int
+	Since time.Time
+	// SizePerDay is bytes added a day; CostPerDay is the change of the
+	// estimated monthly storage cost a day
+	SizePerDay float64
+	CostPerDay float64
+}
+
+// CapacityAlert reports a bucket that has reached, or is projected to
+// reach, a capacity or budget threshold
+type CapacityAlert struct {
+	Bucket string `json:"bucket"`
+	// Kind is "capacity" (bytes) or "budget" (USD per month)
+	Kind      string  `json:"kind"`
+	Current   float64 `json:"current"`
+	Threshold float64 `json:"threshold"`
+	PerDay    float64 `json:"growth_per_day"`
+	// DaysLeft and Crossing are zero when the threshold is already reached
+	DaysLeft float64   `json:"days_left,omitzero"`
+	Crossing time.Time `json:"projected_crossing,omitzero"`
+	Message  string    `json:"message"`
+}
+
+// PrefixStats holds object count and size under a prefix. Snapshot prefixes
+// do not overlap, so their totals add up to the bucket totals.
+type PrefixStats struct {
+	Prefix      string `json:"prefix"`
+	ObjectCount int64  `json:"object_count"`
+	Size        int64  `json:"size"`
+	// Written holds bytes by LastModified day (YYYY-MM-DD) for recent days
+	Written map[string]int64 `json:"written,omitempty"`
+	// Date and Newest are set on date partitions: the first day covered and
+	// the latest write
+	Date   time.Time `json:"date,omitzero"`
+	Newest time.Time `json:"newest,omitzero"`
+}
+
+// ClassStats holds the object count and size of one storage class in a
+// snapshot
+type ClassStats struct {
+	StorageClass string `json:"storage_class"`
+	ObjectCount  int64  `json:"object_count"`
+	Size         int64  `json:"size"`
+}
+
+// SnapshotDiff is the change of a bucket's summary between two runs
+type SnapshotDiff struct {
+	Bucket string
+	// Estimated is set when either run only listed part of the bucket
+	Estimated    bool
+	OldGenerated time.Time
+	NewGenerated time.Time
+	OldObjects   int64
+	NewObjects   int64
+	OldSize      int64
+	NewSize      int64
+	OldCost      float64
+	NewCost      float64
+	Classes      []ClassDelta
+	// ClassesMissing is set when a run's snapshot predates storage class
+	// totals, so Classes is empty
+	ClassesMissing    bool
+	NewPartitions     []PrefixStats
+	RemovedPartitions []PrefixStats
+}
+
+// ClassDelta is the change of one storage class between two runs
+type ClassDelta struct {
+	StorageClass string
+	OldObjects   int64
+	NewObjects   int64
+	OldSize      int64
+	NewSize      int64
+}
+
+// Comparison attributes the change between two snapshots to prefixes
+type Comparison struct {
+	Bucket string
+	// Estimated is set when either run only listed part of the bucket
+	Estimated    bool
+	OldGenerated time.Time
+	NewGenerated time.Time
+	OldObjects   int64
+	NewObjects   int64
+	OldSize      int64
+	NewSize      int64
+	TopLevel     []PrefixDelta
+	Prefixes     []PrefixDelta
+	Partitions   []PrefixDelta
+	Churn        []PrefixChurn
+	// ChurnTruncated is set when the runs are further apart than the write
+	// history kept in snapshots, making churn a lower bound
+	ChurnTruncated bool
+}
+
+// PrefixChurn estimates how much of a prefix was rewritten between two runs
+type PrefixChurn struct {
+	Prefix string
+	// Written is bytes with a LastModified after the previous run
+	Written int64
+	// Removed is bytes from the previous run that were deleted or overwritten
+	Removed int64
+	// Replaced is the part of Written that replaced removed bytes
+	Replaced int64
+	// MonthlyRate is Replaced per 30 days relative to the average prefix size
+	MonthlyRate float64
+}
+
+// PrefixDelta is the change of a single prefix between two runs
+type PrefixDelta struct {
+	Prefix     string
+	OldCount   int64
+	NewCount   int64
+	OldSize    int64
+	NewSize    int64
+	SizeDelta  int64
+	CountDelta int64
 }